@@ -0,0 +1,72 @@
+// Command seed fills the configured database with generated users, articles
+// and comments for load tests. Never point it at a production database.
+//
+//	go run ./cmd/seed -articles 500000 -comments 5000000
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/logging"
+	"go-blog/internal/seed"
+	"go-blog/pkg/config"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	opts := seed.DefaultOptions
+	flag.IntVar(&opts.Users, "users", opts.Users, "users to create")
+	flag.IntVar(&opts.Articles, "articles", opts.Articles, "articles to create")
+	flag.IntVar(&opts.Comments, "comments", opts.Comments, "comments to create")
+	flag.IntVar(&opts.Categories, "categories", opts.Categories, "categories to create")
+	flag.IntVar(&opts.Tags, "tags", opts.Tags, "tags to create")
+	flag.IntVar(&opts.BatchSize, "batch", opts.BatchSize, "rows per insert")
+	flag.IntVar(&opts.Days, "days", opts.Days, "days back creation dates are spread over")
+	flag.Int64Var(&opts.Seed, "seed", opts.Seed, "seed of the random distributions")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fatal("Failed to load configuration", err)
+	}
+	if err := logging.Setup(cfg.Log); err != nil {
+		fatal("Failed to set up logging", err)
+	}
+
+	db, err := database.ConnectWithConfig(cfg)
+	if err != nil {
+		fatal("Failed to connect to database", err)
+	}
+	defer db.Close()
+	if err := database.Migrate(db); err != nil {
+		fatal("Failed to run migrations", err)
+	}
+	// Logging every insert would slow the run down and flood the output
+	db = database.NewDB(db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Error)}))
+
+	start := time.Now()
+	logged := map[string]time.Time{}
+	result, err := seed.Generate(db, opts, func(table string, done, total int) {
+		if done == total || time.Since(logged[table]) >= 5*time.Second {
+			logged[table] = time.Now()
+			slog.Info("Seeding", "table", table, "done", done, "total", total)
+		}
+	})
+	if err != nil {
+		fatal("Failed to seed database", err)
+	}
+	slog.Info("Seeded database", "users", result.Users, "articles", result.Articles,
+		"comments", result.Comments, "password", seed.Password, "duration", time.Since(start).Round(time.Second).String())
+}
+
+// fatal logs err and exits
+func fatal(message string, err error) {
+	slog.Error(message, "error", err)
+	os.Exit(1)
+}
@@ -0,0 +1,241 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/errorreport"
+	"go-blog/internal/middleware"
+	"go-blog/internal/queue"
+	"go-blog/internal/routes"
+	"go-blog/internal/scheduler"
+	"go-blog/internal/services"
+	"go-blog/internal/storage"
+	"go-blog/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App is the fully wired application: configuration, database, repositories,
+// services, handlers and the HTTP router.
+type App struct {
+	Config       *config.Config
+	DB           *database.DB
+	Repositories *Repositories
+	Services     *Services
+	Storage      storage.Storage
+	Handlers     *routes.Handlers
+	Router       *gin.Engine
+	Scheduler    *scheduler.Scheduler
+	Queue        *queue.Queue
+	Sentry       *errorreport.Sentry // nil unless error reporting is configured
+
+	server *http.Server
+}
+
+// New connects to the configured database, runs migrations and builds the application
+func New(cfg *config.Config) (*App, error) {
+	db, err := database.ConnectWithConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	for _, index := range database.MissingIndexes(db) {
+		slog.Warn("Expected index is missing; list queries may be slow", "table", index.Table, "index", index.Name)
+	}
+
+	return NewWithDB(cfg, db), nil
+}
+
+// NewWithDB builds the application on top of an already connected and migrated database.
+// Tests use it to boot the full stack against an in-memory database.
+func NewWithDB(cfg *config.Config, db *database.DB) *App {
+	repos := newRepositories(db)
+	store, err := newStorage(cfg)
+	if err != nil {
+		slog.Error("Uploads are disabled: storage is misconfigured", "driver", cfg.Storage.Driver, "error", err)
+	}
+	q := newQueue(cfg, repos)
+	svc := newServices(cfg, repos, store, q)
+	jobs := newScheduler(cfg, repos, svc)
+	h := newHandlers(cfg, svc, jobs, q)
+
+	sentry := newSentry(cfg.Errors)
+	router := gin.New()
+	// Client IPs feed per-IP limits, so X-Forwarded-For is only believed from
+	// configured proxies
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		slog.Error("Trusting no proxies: trusted_proxies is misconfigured", "error", err)
+		router.SetTrustedProxies(nil)
+	}
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Logger(cfg.Log.DebugSecret))
+	router.Use(middleware.Recovery(sentry))
+	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(middleware.Maintenance(svc.Maintenance))
+	router.Use(middleware.SiteVisibility(svc.Site, svc.Auth))
+	if len(cfg.SLO.Groups) > 0 {
+		router.Use(middleware.Metrics(svc.SLO)) // After maintenance, whose refusals are planned
+	}
+	deps := &routes.Dependencies{Handlers: h, AuthService: svc.Auth}
+	deps.Limits = middleware.NewBodyLimits(cfg.Limits.MaxBodySize, cfg.Limits.MaxUploadSize, cfg.Limits.UploadTypes, cfg.Limits.Routes)
+	router.Use(deps.Limits.Limit())
+	if cfg.Security.CSRF {
+		router.Use(middleware.CSRF()) // After the body limit, as it may parse form bodies for the token
+	}
+	if cfg.Cache.Enabled {
+		deps.Cache = newResponseCache(cfg.Cache)
+		router.Use(deps.Cache.Invalidate())
+	}
+	routes.Setup(router, deps)
+	router.GET("/s/:code", h.ShortLink.Redirect) // Short links live outside /api to stay short
+	serveLocalStorage(router, store, cfg.Storage.CacheMaxAge)
+	serveIndexNowKey(router, svc.SearchEngines)
+
+	return &App{
+		Config:       cfg,
+		DB:           db,
+		Repositories: repos,
+		Services:     svc,
+		Storage:      store,
+		Handlers:     h,
+		Router:       router,
+		Scheduler:    jobs,
+		Queue:        q,
+		Sentry:       sentry,
+	}
+}
+
+// newSentry creates the Sentry reporter of recovered panics, or nil when no DSN
+// is configured or it is invalid
+func newSentry(cfg config.ErrorsConfig) *errorreport.Sentry {
+	if cfg.SentryDSN == "" {
+		return nil
+	}
+	sentry, err := errorreport.NewSentry(cfg.SentryDSN, cfg.Environment)
+	if err != nil {
+		slog.Warn("Not reporting errors to Sentry", "error", err)
+		return nil
+	}
+	return sentry
+}
+
+// newResponseCache creates the cache of anonymous API reads with the configured
+// route TTLs
+func newResponseCache(cfg config.CacheConfig) *middleware.ResponseCache {
+	overrides := make(map[string]time.Duration, len(cfg.Routes))
+	for route, ttl := range cfg.Routes {
+		overrides[route] = time.Duration(ttl) * time.Second
+	}
+	return middleware.NewResponseCache(cfg.MaxEntries, overrides)
+}
+
+// serveLocalStorage serves files of the local storage driver under the path of its
+// base URL, as immutable for maxAge seconds
+func serveLocalStorage(router *gin.Engine, store storage.Storage, maxAge int) {
+	local, ok := store.(*storage.Local)
+	if !ok {
+		return
+	}
+
+	base, err := url.Parse(local.BaseURL)
+	if err != nil || base.Path == "" || base.Path == "/" {
+		slog.Warn("Not serving uploads: storage base URL has no path", "base_url", local.BaseURL)
+		return
+	}
+
+	// Like router.Static, but only found files get the cache header, so a
+	// missing file is not cached as missing for good
+	files := gin.Dir(local.Root, false)
+	fileServer := http.StripPrefix(strings.TrimSuffix(base.Path, "/"), http.FileServer(files))
+	cacheControl := storage.ImmutableCacheControl(maxAge)
+	serve := func(c *gin.Context) {
+		f, err := files.Open(c.Param("filepath"))
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		info, err := f.Stat()
+		f.Close()
+		if err != nil || info.IsDir() {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		if cacheControl != "" {
+			c.Header("Cache-Control", cacheControl)
+		}
+		fileServer.ServeHTTP(c.Writer, c.Request)
+	}
+	pattern := path.Join(base.Path, "/*filepath")
+	router.GET(pattern, serve)
+	router.HEAD(pattern, serve)
+}
+
+// serveIndexNowKey serves the IndexNow key file search engines fetch to verify
+// submissions, at /<key>.txt on the public host
+func serveIndexNowKey(router *gin.Engine, notifier *services.SearchEngineNotifier) {
+	if notifier == nil || notifier.IndexNowKey() == "" {
+		return
+	}
+
+	key := notifier.IndexNowKey()
+	router.GET("/"+key+".txt", func(c *gin.Context) {
+		c.String(http.StatusOK, key)
+	})
+}
+
+// Run starts the job queue workers and scheduled jobs and serves HTTP until
+// Shutdown is called
+func (a *App) Run() error {
+	a.Queue.Start()
+	if a.Config.Scheduler.Enabled {
+		a.Scheduler.Start()
+	}
+
+	a.server = &http.Server{
+		Addr:         ":" + a.Config.Server.Port,
+		Handler:      a.Router,
+		ReadTimeout:  time.Duration(a.Config.Server.ReadTimeout) * time.Second,
+		WriteTimeout: time.Duration(a.Config.Server.WriteTimeout) * time.Second,
+		IdleTimeout:  time.Duration(a.Config.Server.IdleTimeout) * time.Second,
+	}
+
+	slog.Info("Server starting", "address", a.Config.GetServerAddress())
+	if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown gracefully stops the HTTP server, scheduled jobs and job queue workers,
+// then closes the database
+func (a *App) Shutdown(ctx context.Context) error {
+	var shutdownErr error
+	if a.server != nil {
+		shutdownErr = a.server.Shutdown(ctx)
+	}
+
+	a.Scheduler.Stop()
+	a.Queue.Stop()
+	if a.Services.SearchEngines != nil {
+		a.Services.SearchEngines.Wait()
+	}
+	a.Sentry.Flush(2 * time.Second)
+
+	if err := a.DB.Close(); err != nil && shutdownErr == nil {
+		shutdownErr = err
+	}
+	return shutdownErr
+}
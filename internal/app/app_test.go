@@ -0,0 +1,277 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/middleware"
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewWithDBServesRequests(t *testing.T) {
+	application := setupTestApp(t)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/tags", http.StatusOK},
+		{"/api/v1/tags", http.StatusOK},
+		{"/api/stats/popular", http.StatusOK},
+		{"/api/search/suggestions?q=go", http.StatusOK},
+		{"/api/archive", http.StatusOK},
+		{"/api/feed", http.StatusUnauthorized},
+		{"/api/articles?sort=-view_count", http.StatusOK},
+		{"/api/articles?sort=title", http.StatusOK},
+		{"/api/articles?sort=password", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		application.Router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("GET %s: expected status %d, got %d (%s)", tt.path, tt.status, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestServiceRoutes(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	article, err := application.Repositories.Article.GetBySlug("go-only")
+	if err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+
+	get := func(path string, dest interface{}) {
+		t.Helper()
+		w := tokenRequest(application, "", http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected status 200, got %d (%s)", path, w.Code, w.Body.String())
+		}
+		response := struct {
+			Data interface{} `json:"data"`
+		}{Data: dest}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("GET %s: failed to decode response: %v", path, err)
+		}
+	}
+
+	var archive services.ArchiveResponse
+	get("/api/archive", &archive)
+	if archive.Total != 3 || len(archive.Years) != 1 {
+		t.Errorf("Expected 3 archived articles in one year, got %+v", archive)
+	}
+
+	var popular []services.PopularArticle
+	get("/api/stats/popular", &popular)
+	if len(popular) != 3 || popular[0].Title != "Go web" {
+		t.Errorf("Expected the most viewed article, got %+v", popular)
+	}
+
+	var search services.SearchResponse
+	get("/api/search?q=web", &search)
+	if search.Total != 2 || len(search.Articles) != 2 {
+		t.Errorf("Expected 2 articles matching web, got %+v", search)
+	}
+
+	type likeStatus struct {
+		Liked     bool  `json:"liked"`
+		LikeCount int64 `json:"like_count"`
+	}
+	path := fmt.Sprintf("/api/articles/%d/like", article.ID)
+	w := authRequest(t, application, reader, http.MethodPost, path, "")
+	var toggled struct {
+		Data likeStatus `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &toggled)
+	if w.Code != http.StatusOK || !toggled.Data.Liked || toggled.Data.LikeCount != 1 {
+		t.Errorf("Unexpected like response %d (%s)", w.Code, w.Body.String())
+	}
+	var status likeStatus
+	get(path, &status)
+	if status.Liked || status.LikeCount != 1 {
+		t.Errorf("Expected an anonymous like status with one like, got %+v", status)
+	}
+	w = authRequest(t, application, reader, http.MethodGet, path, "")
+	json.Unmarshal(w.Body.Bytes(), &toggled)
+	if !toggled.Data.Liked || toggled.Data.LikeCount != 1 {
+		t.Errorf("Expected the reader's like in the status, got %s", w.Body.String())
+	}
+}
+
+func TestShutdownWithoutRun(t *testing.T) {
+	application := setupTestApp(t)
+
+	if err := application.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown failed: %v", err)
+	}
+}
+
+func TestResponseCache(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Cache = config.CacheConfig{Enabled: true, MaxEntries: 100, Routes: map[string]int{"/tags/:slug": 0}}
+	})
+	goTag, _ := seedArticles(t, application)
+	user := &models.User{Username: "writer", Email: "writer@example.com"}
+	createUsers(t, application, user)
+
+	cacheStatus := func(w *httptest.ResponseRecorder) string {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		return w.Header().Get(middleware.CacheHeader)
+	}
+
+	first := tokenRequest(application, "", "GET", "/api/tags", "")
+	if status := cacheStatus(first); status != "MISS" {
+		t.Errorf("Expected the first read to miss, got %q", status)
+	}
+	// A change made behind the API's back is not seen until the cache is emptied
+	if err := application.DB.Exec("UPDATE tags SET name = ? WHERE id = ?", "golang", goTag.ID); err != nil {
+		t.Fatalf("Failed to rename tag: %v", err)
+	}
+	second := tokenRequest(application, "", "GET", "/api/tags", "")
+	if status := cacheStatus(second); status != "HIT" || second.Body.String() != first.Body.String() {
+		t.Errorf("Expected the second read to be served from the cache, got %q", status)
+	}
+	if status := cacheStatus(authRequest(t, application, user, "GET", "/api/tags", "")); status != "" {
+		t.Errorf("Expected authenticated reads to bypass the cache, got %q", status)
+	}
+	if status := cacheStatus(tokenRequest(application, "", "GET", "/api/tags/go", "")); status != "" {
+		t.Errorf("Expected the route turned off by configuration to bypass the cache, got %q", status)
+	}
+
+	// A successful write empties the cache; a failed one does not
+	if w := authRequest(t, application, user, "POST", "/api/tags", `{"name": ""}`); w.Code < http.StatusBadRequest {
+		t.Fatalf("Expected the invalid tag to be rejected, got %d", w.Code)
+	}
+	if status := cacheStatus(tokenRequest(application, "", "GET", "/api/tags", "")); status != "HIT" {
+		t.Errorf("Expected a failed write to keep the cache, got %q", status)
+	}
+	if w := authRequest(t, application, user, "POST", "/api/tags", `{"name": "rust"}`); w.Code >= http.StatusBadRequest {
+		t.Fatalf("Failed to create tag: %d %s", w.Code, w.Body.String())
+	}
+	third := tokenRequest(application, "", "GET", "/api/tags", "")
+	if status := cacheStatus(third); status != "MISS" || !strings.Contains(third.Body.String(), "golang") {
+		t.Errorf("Expected a fresh read after the write, got %q: %s", status, third.Body.String())
+	}
+}
+
+func TestSLOReport(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.SLO = config.SLOConfig{
+			Windows: []int{5, 60},
+			Groups: []config.SLOGroupConfig{
+				{Name: "articles", Prefix: "/articles", Availability: 0.99, LatencyThreshold: 60000, LatencyTarget: 0.9},
+				{Name: "api", Prefix: "/", Availability: 0.999},
+			},
+		}
+	})
+	seedArticles(t, application)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+
+	for _, path := range []string{"/api/articles", "/api/v1/articles/go-web", "/api/tags", "/api/no-such-route"} {
+		tokenRequest(application, "", "GET", path, "")
+	}
+	// Failing reads burn the error budget of their group only
+	if err := application.DB.Exec("DROP TABLE articles"); err != nil {
+		t.Fatalf("Failed to drop articles: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if w := tokenRequest(application, "", "GET", "/api/articles", ""); w.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected the list to fail, got %d", w.Code)
+		}
+	}
+
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+	if w := authRequest(t, application, reader, "GET", "/api/admin/slo", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected non-admins to be refused, got %d", w.Code)
+	}
+
+	w := authRequest(t, application, admin, "GET", "/api/admin/slo", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the SLO report, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data []services.SLOStatus `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Data) != 2 || response.Data[0].Name != "api" || response.Data[1].Name != "articles" {
+		t.Fatalf("Expected the api and articles groups, got %+v", response.Data)
+	}
+
+	api, articles := response.Data[0], response.Data[1]
+	if len(articles.BurnRates) != 2 || articles.BurnRates[0].Window != "5m0s" {
+		t.Fatalf("Expected burn rates over both windows, got %+v", articles.BurnRates)
+	}
+	rate := articles.BurnRates[0]
+	if rate.Requests != 4 || rate.Errors != 2 || rate.Slow != 0 || math.Abs(rate.ErrorBurnRate-50) > 1e-9 {
+		t.Errorf("Expected half of 4 article reads failing at 50x the budget, got %+v", rate)
+	}
+	if !articles.Alerting {
+		t.Errorf("Expected the articles group to alert")
+	}
+	// The tag read and the reader's forbidden admin request; unknown routes are left out
+	if rate := api.BurnRates[1]; rate.Requests != 2 || rate.Errors != 0 || api.Alerting {
+		t.Errorf("Expected 2 healthy api requests, got %+v", api)
+	}
+}
+
+func TestPanicRecovery(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.SLO.Groups = []config.SLOGroupConfig{{Name: "api", Prefix: "/", Availability: 0.99}}
+	})
+	application.Router.GET("/api/boom", func(c *gin.Context) { panic("boom") })
+
+	req := httptest.NewRequest("GET", "/api/boom", nil)
+	req.Header.Set(middleware.RequestIDHeader, "trace-42")
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected a 500 response, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Success bool `json:"success"`
+		Data    struct {
+			RequestID string `json:"request_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Expected an API response, got %q", w.Body.String())
+	}
+	if response.Success || response.Data.RequestID != "trace-42" || w.Header().Get(middleware.RequestIDHeader) != "trace-42" {
+		t.Errorf("Expected the request ID in the failed response, got %+v (header %q)", response, w.Header().Get(middleware.RequestIDHeader))
+	}
+
+	// Panics burn the error budget
+	report := application.Services.SLO.Report(time.Now())
+	if len(report) != 1 || report[0].BurnRates[0].Errors != 1 {
+		t.Errorf("Expected the panic counted as an error, got %+v", report)
+	}
+
+	// Requests without an ID get a generated one
+	w = tokenRequest(application, "", "GET", "/api/tags", "")
+	if id := w.Header().Get(middleware.RequestIDHeader); len(id) != 32 {
+		t.Errorf("Expected a generated request ID, got %q", id)
+	}
+}
@@ -0,0 +1,898 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+)
+
+func TestArticleListFilters(t *testing.T) {
+	application := setupTestApp(t)
+	goTag, webTag := seedArticles(t, application)
+	tagIDs := fmt.Sprintf("%d,%d", goTag.ID, webTag.ID)
+
+	tests := []struct {
+		name   string
+		query  string
+		titles []string
+	}{
+		{"any tag", "tag_id=" + tagIDs + "&sort=-view_count", []string{"Go web", "Web only", "Go only"}},
+		{"all tags", "tag_id=" + tagIDs + "&tag_match=all", []string{"Go web"}},
+		{"repeated tag param", fmt.Sprintf("tag_id=%d&tag_id=%d&tag_match=all", goTag.ID, webTag.ID), []string{"Go web"}},
+		{"min views", "min_views=50&sort=view_count", []string{"Web only", "Go web"}},
+		{"future date range", "published_from=2999-01-01", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+			}
+
+			var response struct {
+				Data []models.Article `json:"data"`
+				Meta utils.Meta       `json:"meta"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if response.Meta.Pagination.Total != int64(len(tt.titles)) {
+				t.Errorf("Expected total %d, got %d", len(tt.titles), response.Meta.Pagination.Total)
+			}
+			if len(response.Data) != len(tt.titles) {
+				t.Fatalf("Expected %d articles, got %d", len(tt.titles), len(response.Data))
+			}
+			for i, title := range tt.titles {
+				if response.Data[i].Title != title {
+					t.Errorf("Expected article %d to be %q, got %q", i, title, response.Data[i].Title)
+				}
+				if response.Data[i].Content != "" {
+					t.Errorf("Expected list article %q to omit content", title)
+				}
+			}
+		})
+	}
+}
+
+func TestListEnvelope(t *testing.T) {
+	application := setupTestApp(t)
+	goTag, webTag := seedArticles(t, application)
+	now := time.Now()
+
+	tests := []struct {
+		path       string
+		titles     []string
+		pagination utils.Pagination
+		filters    map[string]interface{}
+		sort       string
+	}{
+		{
+			fmt.Sprintf("/api/articles?tag_id=%d,%d&sort=-view_count&page=2&limit=1", goTag.ID, webTag.ID),
+			[]string{"Web only"},
+			utils.Pagination{Page: 2, Limit: 1, Total: 3, TotalPages: 3},
+			map[string]interface{}{"tag_id": fmt.Sprintf("%d,%d", goTag.ID, webTag.ID)},
+			"-view_count",
+		},
+		{
+			"/api/articles?min_views=50&limit=500",
+			[]string{"Web only", "Go web"},
+			utils.Pagination{Page: 1, Limit: 10, Total: 2, TotalPages: 1}, // out of range limits fall back to the default
+			map[string]interface{}{"min_views": "50"},
+			"-created_at",
+		},
+		{
+			fmt.Sprintf("/api/archive/%d/%d?page=3&limit=1", now.Year(), int(now.Month())),
+			[]string{"Go web"},
+			utils.Pagination{Page: 3, Limit: 1, Total: 3, TotalPages: 3},
+			map[string]interface{}{"year": float64(now.Year()), "month": float64(now.Month())},
+			"-published_at",
+		},
+		{
+			"/api/tags/go/articles",
+			[]string{"Go only", "Go web"},
+			utils.Pagination{Page: 1, Limit: 10, Total: 2, TotalPages: 1},
+			map[string]interface{}{"tag": "go"},
+			"-created_at",
+		},
+	}
+
+	for _, tt := range tests {
+		w := tokenRequest(application, "", http.MethodGet, tt.path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected status 200, got %d (%s)", tt.path, w.Code, w.Body.String())
+		}
+		var response struct {
+			utils.APIResponse
+			Data []models.ArticleSummary `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("GET %s: failed to decode response: %v", tt.path, err)
+		}
+
+		if !response.Success || response.Message == "" || response.Meta == nil || response.Meta.Pagination == nil {
+			t.Fatalf("GET %s: expected a list envelope, got %s", tt.path, w.Body.String())
+		}
+		titles := make([]string, len(response.Data))
+		for i, article := range response.Data {
+			titles[i] = article.Title
+		}
+		if !reflect.DeepEqual(titles, tt.titles) {
+			t.Errorf("GET %s: expected articles %v, got %v", tt.path, tt.titles, titles)
+		}
+		if *response.Meta.Pagination != tt.pagination {
+			t.Errorf("GET %s: expected pagination %+v, got %+v", tt.path, tt.pagination, *response.Meta.Pagination)
+		}
+		if !reflect.DeepEqual(response.Meta.Filters, tt.filters) {
+			t.Errorf("GET %s: expected filters %v, got %v", tt.path, tt.filters, response.Meta.Filters)
+		}
+		if response.Meta.Sort != tt.sort {
+			t.Errorf("GET %s: expected sort %q, got %q", tt.path, tt.sort, response.Meta.Sort)
+		}
+	}
+}
+
+func TestArticleTagsAreWrittenAtomically(t *testing.T) {
+	application := setupTestApp(t)
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	article := createArticle(t, application, author.ID, &services.CreateArticleRequest{
+		Title: "Tagged", Content: "Content", TagNames: []string{"go", "web"},
+	})
+
+	// Dropped tags are removed from the article, not just new ones added
+	if _, err := application.Services.Article.Update(article.ID, author.ID, &services.UpdateArticleRequest{
+		TagNames: []string{"web", "rust"},
+	}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	stored, err := application.Repositories.Article.GetByID(article.ID)
+	if err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	var names []string
+	for _, tag := range stored.Tags {
+		names = append(names, tag.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "rust,web" {
+		t.Errorf("Expected tags rust,web, got %v", names)
+	}
+
+	// A create the article model rejects leaves no tags behind
+	if _, err := application.Services.Article.Create(author.ID, &services.CreateArticleRequest{
+		Title: "Broken\ntitle", Content: "Content", TagNames: []string{"orphan"},
+	}); err == nil {
+		t.Fatal("Expected a title with a line break to be rejected")
+	}
+	if _, err := application.Repositories.Tag.GetByName("orphan"); err == nil {
+		t.Error("Expected the tag of the failed create to be rolled back")
+	}
+}
+
+func TestArticleSlugRetriesOnUniqueIndex(t *testing.T) {
+	application := setupTestApp(t)
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	// A row soft-deleted without releasing its slug is invisible to slug lookups
+	// but still holds the slug in the unique index, like an insert racing with ours
+	trashed := createArticle(t, application, author.ID, &services.CreateArticleRequest{Title: "Release notes", Content: "Old"})
+	if err := application.DB.GetDB().Delete(trashed).Error; err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+
+	article, err := application.Services.Article.Create(author.ID, &services.CreateArticleRequest{Title: "Release notes", Content: "New"})
+	if err != nil {
+		t.Fatalf("Expected the create to retry with another slug, got %v", err)
+	}
+	if article.Slug != "release-notes-1" {
+		t.Errorf("Expected slug release-notes-1, got %s", article.Slug)
+	}
+}
+
+func TestArticleRevisions(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	// The seeded article predates revisions
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	update := func(req *services.UpdateArticleRequest) {
+		t.Helper()
+		if _, err := application.Services.Article.Update(article.ID, author.ID, req); err != nil {
+			t.Fatalf("Failed to update article: %v", err)
+		}
+	}
+	update(&services.UpdateArticleRequest{Content: ptr("Content with generics")})
+	update(&services.UpdateArticleRequest{Status: "archived"}) // no new revision
+	update(&services.UpdateArticleRequest{Title: ptr("Go generics")})
+
+	revisionsPath := fmt.Sprintf("/api/articles/%d/revisions", article.ID)
+	w := authRequest(t, application, &author, http.MethodGet, revisionsPath, "")
+	var list struct {
+		Data []models.ArticleRevision `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Expected revisions, got %d (%s)", w.Code, w.Body.String())
+	}
+	if len(list.Data) != 3 || list.Data[0].Number != 3 || list.Data[0].Title != "Go generics" || list.Data[0].Content != "" {
+		t.Fatalf("Expected three revisions listed newest first without content, got %+v", list.Data)
+	}
+
+	w = authRequest(t, application, &author, http.MethodGet, revisionsPath+"/1/compare/3", "")
+	var comparison struct {
+		Data services.RevisionComparison `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &comparison); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("Expected a comparison, got %d (%s)", w.Code, w.Body.String())
+	}
+	result := comparison.Data
+	if result.From.Number != 1 || result.To.Number != 3 || result.WordsAdded != 2 || result.WordsRemoved != 0 {
+		t.Errorf("Expected revision 1 to 3 adding two words, got %+v", result)
+	}
+	if len(result.Content) != 2 || result.Content[1].Op != "insert" || result.Content[1].Text != " with generics" {
+		t.Errorf("Expected the added words as an insert block, got %+v", result.Content)
+	}
+
+	if w := authRequest(t, application, &author, http.MethodGet, revisionsPath+"/1/compare/9", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing revision, got %d", w.Code)
+	}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+	if w := authRequest(t, application, reader, http.MethodGet, revisionsPath+"/1/compare/2", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another user's article, got %d", w.Code)
+	}
+}
+
+func TestDeletedRecordsReleaseUniqueValues(t *testing.T) {
+	application := setupTestApp(t)
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	// A deleted article gives its slug to the next article with the same title
+	create := func(content string) *models.Article {
+		return createArticle(t, application, author.ID, &services.CreateArticleRequest{Title: "Release notes", Content: content})
+	}
+	old := create("Old")
+	if err := application.Services.Article.Delete(old.ID, author.ID); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	if article := create("New"); article.Slug != "release-notes" {
+		t.Errorf("Expected the deleted article's slug release-notes, got %s", article.Slug)
+	}
+	var trashed models.Article
+	if err := application.DB.GetDB().Unscoped().First(&trashed, old.ID).Error; err != nil {
+		t.Fatalf("Failed to load deleted article: %v", err)
+	}
+	if expected := fmt.Sprintf("deleted:%d", old.ID); trashed.Slug != expected {
+		t.Errorf("Expected the deleted article's slug to be released as %s, got %s", expected, trashed.Slug)
+	}
+
+	// Deleted categories and tags give up their names and slugs
+	category, err := application.Services.Category.Create(&services.CreateCategoryRequest{Name: "Releases"})
+	if err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	if err := application.Services.Category.Delete(category.ID); err != nil {
+		t.Fatalf("Failed to delete category: %v", err)
+	}
+	if category, err := application.Services.Category.Create(&services.CreateCategoryRequest{Name: "Releases"}); err != nil || category.Slug != "releases" {
+		t.Errorf("Expected to recreate the category, got %v (%v)", category, err)
+	}
+	// Names cannot take the form of a released placeholder
+	var fields models.ValidationErrors
+	placeholder := fmt.Sprintf("deleted:%d", category.ID)
+	if _, err := application.Services.Category.Create(&services.CreateCategoryRequest{Name: placeholder}); !errors.As(err, &fields) {
+		t.Errorf("Expected a validation error for the category name %s, got %v", placeholder, err)
+	}
+	if _, err := application.Services.Tag.Create(&services.CreateTagRequest{Name: placeholder}); !errors.As(err, &fields) {
+		t.Errorf("Expected a validation error for the tag name %s, got %v", placeholder, err)
+	}
+	w := authRequest(t, application, author, http.MethodPost, "/api/tags", fmt.Sprintf(`{"name": %q}`, placeholder))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "name cannot contain ':'") {
+		t.Errorf("Expected status 400 for the tag name %s, got %d (%s)", placeholder, w.Code, w.Body.String())
+	}
+
+	tag, err := application.Services.Tag.Create(&services.CreateTagRequest{Name: "Changelog"})
+	if err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	if err := application.Repositories.Tag.Delete(tag.ID); err != nil {
+		t.Fatalf("Failed to delete tag: %v", err)
+	}
+	if tag, err := application.Services.Tag.Create(&services.CreateTagRequest{Name: "Changelog"}); err != nil || tag.Slug != "changelog" {
+		t.Errorf("Expected to recreate the tag, got %v (%v)", tag, err)
+	}
+
+	// Deleting an account with its articles releases their slugs too
+	result, err := application.Repositories.User.DeleteAccount(&repositories.AccountDeletion{UserID: author.ID})
+	if err != nil || result.Articles != 1 {
+		t.Fatalf("Expected one deleted article, got %v (%v)", result, err)
+	}
+	other := &models.User{Username: "other", Email: "other@example.com"}
+	createUsers(t, application, other)
+	article, err := application.Services.Article.Create(other.ID, &services.CreateArticleRequest{Title: "Release notes", Content: "Again"})
+	if err != nil || article.Slug != "release-notes" {
+		t.Errorf("Expected the slug release-notes to be free again, got %v (%v)", article, err)
+	}
+}
+
+func TestContentSanitization(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+
+	article := createArticle(t, application, author.ID, &services.CreateArticleRequest{
+		Title:   "Safe HTML",
+		Content: `<h2>Intro</h2><p onclick="alert(1)">Read <a href="https://go.dev">this</a></p><script>alert(1)</script>`,
+		Excerpt: `<b>Short</b> summary`,
+	})
+	if article.Content != `<h2>Intro</h2><p>Read <a href="https://go.dev">this</a></p>` || article.Excerpt != "Short summary" {
+		t.Errorf("Expected sanitized article content, got %q and %q", article.Content, article.Excerpt)
+	}
+	if _, err := application.Services.Article.Create(author.ID, &services.CreateArticleRequest{
+		Title:   "Only script",
+		Content: `<script>alert(1)</script>`,
+	}); err == nil {
+		t.Errorf("Expected content that is only markup to be rejected")
+	}
+
+	updated, err := application.Services.Article.Update(article.ID, author.ID, &services.UpdateArticleRequest{
+		Content: ptr(`<img src="https://example.com/a.png" onerror="alert(1)">`),
+	})
+	if err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if updated.Content != `<img src="https://example.com/a.png">` {
+		t.Errorf("Expected sanitized updated content, got %q", updated.Content)
+	}
+
+	// Commenters get a stricter policy than authors
+	commentsPath := fmt.Sprintf("/api/articles/%d/comments", article.ID)
+	w := authRequest(t, application, &author, http.MethodPost, commentsPath,
+		`{"content": "<h1>Hi</h1> <img src=x onerror=alert(1)><a href=\"https://go.dev\">go</a>"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.Comment `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if created.Data.Content != `Hi <a href="https://go.dev" rel="nofollow">go</a>` {
+		t.Errorf("Expected sanitized comment content, got %q", created.Data.Content)
+	}
+
+	if w := authRequest(t, application, &author, http.MethodPost, commentsPath, `{"content": "<script>alert(1)</script>"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a comment that is only markup, got %d", w.Code)
+	}
+
+	commentPath := fmt.Sprintf("/api/comments/%d", created.Data.ID)
+	w = authRequest(t, application, &author, http.MethodPut, commentPath, `{"content": "[see](javascript:alert(1)) <b onmouseover=alert(1)>here</b>"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if created.Data.Content != `[see](#alert(1)) <b>here</b>` {
+		t.Errorf("Expected sanitized edited comment, got %q", created.Data.Content)
+	}
+}
+
+func TestArticleMeta(t *testing.T) {
+	application := setupTestApp(t)
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, author, reader)
+	now := time.Now()
+	article := &models.Article{Title: "Episode 42", Slug: "episode-42", Content: "Content", AuthorID: author.ID,
+		Status: models.StatusPublished, PublishedAt: &now}
+	if err := application.DB.Create(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	path := fmt.Sprintf("/api/articles/%d/meta", article.ID)
+
+	body := `{"fields": [
+		{"key": "episode", "type": "number", "value": 42},
+		{"key": "explicit", "type": "bool", "value": false},
+		{"key": "guests", "type": "json", "value": [ {"name": "Ada"} ]}
+	]}`
+	if w := authRequest(t, application, reader, http.MethodPut, path, body); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another user, got %d", w.Code)
+	}
+	if w := authRequest(t, application, author, http.MethodPut, path, body); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	for _, invalid := range []string{
+		`{"fields": [{"key": "episode", "type": "number", "value": "42"}]}`,
+		`{"fields": [{"key": "explicit", "type": "bool", "value": 1}]}`,
+		`{"fields": [{"key": "guests", "type": "json", "value": null}]}`,
+		`{"fields": [{"key": "Episode", "type": "number", "value": 1}]}`,
+		`{"fields": [{"key": "season", "type": "date", "value": "2024-01-01"}]}`,
+		`{"fields": [{"key": "season", "type": "number", "value": 1}, {"key": "season", "type": "number", "value": 2}]}`,
+	} {
+		if w := authRequest(t, application, author, http.MethodPut, path, invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d (%s)", invalid, w.Code, w.Body.String())
+		}
+	}
+
+	// Fields not listed keep their value
+	if w := authRequest(t, application, author, http.MethodPut, path, `{"fields": [{"key": "episode", "type": "number", "value": 43}]}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	w := tokenRequest(application, "", http.MethodGet, "/api/articles/episode-42", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(),
+		`"meta":[{"key":"episode","type":"number","value":43,`) ||
+		!strings.Contains(w.Body.String(), `{"key":"guests","type":"json","value":[{"name":"Ada"}],`) {
+		t.Errorf("Expected the custom fields in the article, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if w := authRequest(t, application, author, http.MethodDelete, path+"/explicit", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, author, http.MethodDelete, path+"/explicit", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a deleted field, got %d", w.Code)
+	}
+	w = tokenRequest(application, "", http.MethodGet, path, "")
+	var response struct {
+		Data []models.ArticleMeta `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || len(response.Data) != 2 || response.Data[0].Key != "episode" || response.Data[1].Key != "guests" {
+		t.Errorf("Expected the remaining custom fields by key, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestArticleExpiry(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	articles := application.Services.Article
+
+	past := time.Now().Add(-time.Hour)
+	if _, err := articles.Update(article.ID, author.ID, &services.UpdateArticleRequest{ExpiresAt: &past}); !errors.Is(err, services.ErrValidation) {
+		t.Errorf("Expected a past expiry to be rejected, got %v", err)
+	}
+
+	// An expired article drops out of lists before the job archives it
+	if err := application.DB.Exec("UPDATE articles SET expires_at = ? WHERE id = ?", past, article.ID); err != nil {
+		t.Fatalf("Failed to expire article: %v", err)
+	}
+	listed, total, err := articles.List(1, 10, &services.ArticleListFilters{Status: string(models.StatusPublished)}, nil)
+	if err != nil || total != 2 {
+		t.Fatalf("Expected 2 listed articles, got %d (%v)", total, err)
+	}
+	for _, a := range listed {
+		if a.ID == article.ID {
+			t.Errorf("Expected the expired article to be left out of the list")
+		}
+	}
+
+	archived, err := articles.ArchiveExpired()
+	if err != nil || archived != 1 {
+		t.Fatalf("Expected one archived article, got %d (%v)", archived, err)
+	}
+	expired, err := articles.GetByID(article.ID)
+	if err != nil || expired.Status != models.StatusArchived {
+		t.Fatalf("Expected the article to be archived, got %v (%v)", expired, err)
+	}
+
+	// Publishing again needs a new expiry
+	if _, err := articles.Publish(article.ID, author.ID); !errors.Is(err, services.ErrValidation) {
+		t.Errorf("Expected publishing an expired article to be rejected, got %v", err)
+	}
+	future := time.Now().Add(time.Hour)
+	updated, err := articles.Update(article.ID, author.ID, &services.UpdateArticleRequest{ExpiresAt: &future, Status: "published"})
+	if err != nil || updated.Status != models.StatusPublished {
+		t.Fatalf("Expected the article to be published again, got %v (%v)", updated, err)
+	}
+	if archived, _ := articles.ArchiveExpired(); archived != 0 {
+		t.Errorf("Expected nothing to expire yet, got %d", archived)
+	}
+}
+
+func TestArticleSettings(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	if !article.CommentsAllowed() || !article.LikesAllowed() || article.NoIndex {
+		t.Fatalf("Expected comments and likes to be allowed by default, got %+v", article)
+	}
+	if _, err := application.Services.Like.ToggleLike(reader.ID, article.ID); err != nil {
+		t.Fatalf("Failed to like article: %v", err)
+	}
+
+	path := fmt.Sprintf("/api/articles/%d/settings", article.ID)
+	body := `{"allow_comments": false, "allow_likes": false}`
+	if w := authRequest(t, application, reader, "PUT", path, body); w.Code != http.StatusForbidden {
+		t.Errorf("Expected a reader to be forbidden, got %d: %s", w.Code, w.Body.String())
+	}
+	w := authRequest(t, application, admin, "PUT", path, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected an admin to change the settings, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data models.ArticleSummary `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Data.AllowComments || resp.Data.AllowLikes || resp.Data.NoIndex {
+		t.Errorf("Expected comments and likes to be off, got %+v", resp.Data)
+	}
+
+	err := application.Services.Comment.Create(&models.Comment{ArticleID: article.ID, UserID: reader.ID, Content: "Hello"}, "")
+	if !errors.Is(err, services.ErrForbidden) {
+		t.Errorf("Expected commenting to be closed, got %v", err)
+	}
+	if _, err := application.Services.Like.ToggleLike(admin.ID, article.ID); !errors.Is(err, services.ErrForbidden) {
+		t.Errorf("Expected liking to be disabled, got %v", err)
+	}
+	// A like given earlier can still be taken back
+	if liked, err := application.Services.Like.ToggleLike(reader.ID, article.ID); err != nil || liked {
+		t.Errorf("Expected the like to be removed, got %v (%v)", liked, err)
+	}
+
+	// Omitted settings are left alone
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	noIndex := true
+	updated, err := application.Services.Article.UpdateSettings(article.ID, &author, &services.ArticleSettingsRequest{NoIndex: &noIndex})
+	if err != nil || !updated.NoIndex || updated.CommentsAllowed() || updated.LikesAllowed() {
+		t.Errorf("Expected only noindex to change, got %+v (%v)", updated, err)
+	}
+
+	allowLikes := false
+	created := createArticle(t, application, author.ID, &services.CreateArticleRequest{
+		Title:    "Quiet",
+		Content:  "Content",
+		Settings: services.ArticleSettingsRequest{AllowLikes: &allowLikes},
+	})
+	var stored models.Article
+	if err := application.DB.GetByID(&stored, created.ID); err != nil || !stored.CommentsAllowed() || stored.LikesAllowed() {
+		t.Errorf("Expected the new article to take comments but not likes, got %+v (%v)", stored, err)
+	}
+}
+
+func TestArticleVisibility(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+	content := strings.Repeat("<p>Premium words</p> ", 40)
+	if err := application.DB.Exec("UPDATE articles SET visibility = ?, content = ? WHERE slug = ?", models.ArticleVisibilityPremium, content, "go-only"); err != nil {
+		t.Fatalf("Failed to make the article premium: %v", err)
+	}
+	if err := application.DB.Exec("UPDATE articles SET visibility = ? WHERE slug = ?", models.ArticleVisibilityMembers, "web-only"); err != nil {
+		t.Fatalf("Failed to make the article members-only: %v", err)
+	}
+
+	type articleResponse struct {
+		Data models.Article `json:"data"`
+	}
+	get := func(user *models.User, slug string) models.Article {
+		t.Helper()
+		var w *httptest.ResponseRecorder
+		if user == nil {
+			w = tokenRequest(application, "", "GET", "/api/articles/"+slug, "")
+		} else {
+			w = authRequest(t, application, user, "GET", "/api/articles/"+slug, "")
+		}
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected article %s, got %d: %s", slug, w.Code, w.Body.String())
+		}
+		var resp articleResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp.Data
+	}
+
+	locked := get(nil, "go-only")
+	if !locked.Locked || strings.Contains(locked.Content, "<p>") || len(strings.Fields(locked.Content)) != 51 {
+		t.Errorf("Expected anonymous readers to get a 50 word preview, got %q", locked.Content)
+	}
+	if public := get(nil, "go-web"); public.Locked || public.Content != "Content" {
+		t.Errorf("Expected public articles in full, got %+v", public)
+	}
+	if members := get(reader, "web-only"); !members.Locked {
+		t.Errorf("Expected free readers to be locked out of members-only articles")
+	}
+
+	// Admins grant tiers; members read members-only articles but not premium ones
+	path := fmt.Sprintf("/api/admin/users/%d/membership", reader.ID)
+	if w := authRequest(t, application, reader, "PUT", path, `{"tier": "premium"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected readers not to grant themselves a tier, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, "PUT", path, `{"tier": "member"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected the admin to set the tier, got %d: %s", w.Code, w.Body.String())
+	}
+	if members := get(reader, "web-only"); members.Locked || members.Content != "Content" {
+		t.Errorf("Expected members to read members-only articles, got %+v", members)
+	}
+	if premium := get(reader, "go-only"); !premium.Locked {
+		t.Errorf("Expected members to be locked out of premium articles")
+	}
+	if premium := get(admin, "go-only"); premium.Locked || premium.Content != content {
+		t.Errorf("Expected admins to read premium articles in full")
+	}
+
+	// Lists mark what the reader may not read
+	w := authRequest(t, application, reader, "GET", "/api/articles", "")
+	var list struct {
+		Data []models.ArticleSummary `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Data) != 3 {
+		t.Fatalf("Expected 3 listed articles, got %s (%v)", w.Body.String(), err)
+	}
+	for _, summary := range list.Data {
+		if summary.Locked != (summary.Visibility == models.ArticleVisibilityPremium) {
+			t.Errorf("Expected only the premium article to be locked, got %+v", summary)
+		}
+	}
+
+	// Drafts are only found by their author and admins
+	if err := application.DB.Exec("UPDATE articles SET status = ? WHERE slug = ?", models.StatusDraft, "go-web"); err != nil {
+		t.Fatalf("Failed to unpublish article: %v", err)
+	}
+	if w := tokenRequest(application, "", "GET", "/api/articles/go-web", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected drafts to be hidden, got %d", w.Code)
+	}
+	get(admin, "go-web")
+}
+
+func TestArchiveDayAndCalendar(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	for slug, published := range map[string]time.Time{
+		"go-web":   time.Date(2024, 2, 3, 9, 0, 0, 0, time.UTC),
+		"go-only":  time.Date(2024, 2, 3, 18, 0, 0, 0, time.UTC),
+		"web-only": time.Date(2024, 2, 29, 23, 30, 0, 0, time.UTC),
+	} {
+		if err := application.DB.Exec("UPDATE articles SET published_at = ? WHERE slug = ?", published, slug); err != nil {
+			t.Fatalf("Failed to date article: %v", err)
+		}
+	}
+
+	w := tokenRequest(application, "", "GET", "/api/archive/2024/2/calendar", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the calendar, got %d: %s", w.Code, w.Body.String())
+	}
+	var calendar struct {
+		Data services.ArchiveCalendar `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &calendar)
+	want := []services.ArchiveDay{{Day: 3, Date: "2024-02-03", ArticleCount: 2}, {Day: 29, Date: "2024-02-29", ArticleCount: 1}}
+	if !reflect.DeepEqual(calendar.Data.Days, want) || calendar.Data.Total != 3 {
+		t.Errorf("Expected days %v, got %+v", want, calendar.Data)
+	}
+
+	w = tokenRequest(application, "", "GET", "/api/archive/2024/2/3", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the day's articles, got %d: %s", w.Code, w.Body.String())
+	}
+	var day struct {
+		Data []models.ArticleSummary `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &day)
+	if len(day.Data) != 2 || day.Data[0].Slug != "go-only" || day.Data[1].Slug != "go-web" {
+		t.Errorf("Expected go-only and go-web newest first, got %+v", day.Data)
+	}
+
+	for _, path := range []string{"/api/archive/2023/2/29", "/api/archive/2024/2/30", "/api/archive/2024/2/x", "/api/archive/2024/13/calendar"} {
+		if w := tokenRequest(application, "", "GET", path, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected %s to be rejected, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestArticleUpdatePartialFields(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	articles := application.Services.Article
+
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	category := &models.Category{Name: "Backend", Slug: "backend"}
+	if err := application.DB.Create(category); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	if _, err := articles.Update(article.ID, article.AuthorID, &services.UpdateArticleRequest{
+		Excerpt:    ptr("A short summary"),
+		CategoryID: services.NullableOf(category.ID),
+	}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+
+	// Fields left out of the request are kept
+	var req services.UpdateArticleRequest
+	if err := json.Unmarshal([]byte(`{"title": "Go only, revised"}`), &req); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	updated, err := articles.Update(article.ID, article.AuthorID, &req)
+	if err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if updated.Excerpt != "A short summary" || updated.CategoryID == nil || *updated.CategoryID != category.ID {
+		t.Errorf("Expected the excerpt and category to be kept, got %q and %v", updated.Excerpt, updated.CategoryID)
+	}
+
+	// An empty excerpt and a null category clear them
+	req = services.UpdateArticleRequest{}
+	if err := json.Unmarshal([]byte(`{"excerpt": "", "category_id": null}`), &req); err != nil {
+		t.Fatalf("Failed to decode request: %v", err)
+	}
+	if updated, err = articles.Update(article.ID, article.AuthorID, &req); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	stored, err := application.Repositories.Article.GetByID(article.ID)
+	if err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	if stored.Excerpt != "" || stored.CategoryID != nil || stored.Title != "Go only, revised" {
+		t.Errorf("Expected the excerpt and category to be cleared, got %+v", stored.Summary())
+	}
+
+	for name, invalid := range map[string]*services.UpdateArticleRequest{
+		"empty title":   {Title: ptr(" ")},
+		"empty content": {Content: ptr("")},
+		"category zero": {CategoryID: services.NullableOf(uint(0))},
+	} {
+		if _, err := articles.Update(article.ID, article.AuthorID, invalid); !errors.Is(err, services.ErrValidation) {
+			t.Errorf("Expected %s to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestArticleTransfer(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	db := application.DB
+
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	heir := &models.User{Username: "heir", Email: "heir@example.com"}
+	createUsers(t, application, admin, heir)
+	var article models.Article
+	if err := db.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	path := fmt.Sprintf("/api/admin/articles/%d/transfer", article.ID)
+	body := fmt.Sprintf(`{"author_id": %d}`, heir.ID)
+	if w := authRequest(t, application, &author, "POST", path, body); w.Code != http.StatusForbidden {
+		t.Errorf("Expected non-admins to be refused, got %d", w.Code)
+	}
+	for _, invalid := range []string{`{"author_id": 0}`, fmt.Sprintf(`{"author_id": %d}`, author.ID), `{"author_id": 999}`} {
+		if w := authRequest(t, application, admin, "POST", path, invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected %s to be rejected, got %d", invalid, w.Code)
+		}
+	}
+
+	w := authRequest(t, application, admin, "POST", path, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the article to be transferred, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data services.TransferResult `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Data.Articles != 1 || response.Data.From.ArticleCount != 2 || response.Data.To.ArticleCount != 1 {
+		t.Errorf("Expected one article to move with the statistics following it, got %+v", response.Data)
+	}
+
+	// The rest of the author's articles, trashed ones included
+	if err := db.Exec("UPDATE articles SET deleted_at = ? WHERE slug = ?", time.Now(), "web-only"); err != nil {
+		t.Fatalf("Failed to trash article: %v", err)
+	}
+	w = authRequest(t, application, admin, "POST", fmt.Sprintf("/api/admin/users/%d/articles/transfer", author.ID), body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the articles to be transferred, got %d: %s", w.Code, w.Body.String())
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Data.Articles != 2 || response.Data.From.ArticleCount != 0 {
+		t.Errorf("Expected the remaining two articles to move, got %+v", response.Data)
+	}
+	var remaining int64
+	if err := db.GetDB().Unscoped().Model(&models.Article{}).Where("author_id = ?", author.ID).Count(&remaining).Error; err != nil || remaining != 0 {
+		t.Errorf("Expected no articles left with the author, got %d (%v)", remaining, err)
+	}
+
+	var audits []models.AuditLog
+	if err := db.GetDB().Where("action = ?", models.AuditArticleTransfer).Order("id").Find(&audits).Error; err != nil {
+		t.Fatalf("Failed to load audit logs: %v", err)
+	}
+	if len(audits) != 2 || audits[0].TargetType != "article" || audits[0].TargetID != article.ID ||
+		audits[1].TargetType != "user" || audits[1].TargetID != author.ID || audits[1].ActorID != admin.ID {
+		t.Errorf("Expected both transfers to be audited, got %+v", audits)
+	}
+}
+
+func TestCompactArticleList(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	w := tokenRequest(application, "", "GET", "/api/articles?compact=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected articles, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data []map[string]any `json:"data"`
+		Meta utils.Meta       `json:"meta"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Data) == 0 || response.Meta.Pagination == nil {
+		t.Fatalf("Expected a paginated list, got %s", w.Body.String())
+	}
+	allowed := map[string]bool{
+		"id": true, "title": true, "slug": true, "excerpt": true, "cover_url": true, "view_count": true,
+		"like_count": true, "comment_count": true, "published_at": true, "updated_at": true,
+	}
+	for _, article := range response.Data {
+		for field := range article {
+			if !allowed[field] {
+				t.Errorf("Unexpected field %q in the compact list", field)
+			}
+		}
+		if article["slug"] == "" || article["title"] == "" {
+			t.Errorf("Expected the title and slug, got %v", article)
+		}
+	}
+
+	w = tokenRequest(application, "", "GET", "/api/articles", "")
+	response.Data = nil
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if _, ok := response.Data[0]["author"]; !ok {
+		t.Errorf("Expected full summaries without compact, got %v", response.Data[0])
+	}
+}
@@ -0,0 +1,419 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+	"go-blog/pkg/config"
+)
+
+func TestRegisterReturnsFieldErrors(t *testing.T) {
+	application := setupTestApp(t)
+
+	body := `{"username": "ab", "email": "not-an-email", "password": "short"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	application.Router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status 400, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var response utils.APIResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(response.Errors) != 3 {
+		t.Errorf("Expected 3 field errors, got %v", response.Errors)
+	}
+}
+
+func TestChangePasswordAndEmail(t *testing.T) {
+	application := setupTestApp(t)
+	mailer := &recordingMailer{}
+	application.Services.Auth.SetMailer(mailer)
+	application.Services.Auth.SetPublicURL("https://blog.example.com/")
+
+	registered := registerUser(t, application, "writer", "old@example.com")
+	oldTokens := registered.Tokens
+
+	w := tokenRequest(application, oldTokens.AccessToken, http.MethodPost, "/api/users/me/password",
+		`{"current_password": "wrong-password", "new_password": "new-password456"}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a wrong current password, got %d", w.Code)
+	}
+
+	w = tokenRequest(application, oldTokens.AccessToken, http.MethodPost, "/api/users/me/password",
+		`{"current_password": "password123", "new_password": "new-password456"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var changed struct {
+		Data utils.TokenPair `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &changed); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	// Sessions issued before the change are signed out
+	if w := tokenRequest(application, oldTokens.AccessToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the old access token to be rejected, got %d", w.Code)
+	}
+	body := fmt.Sprintf(`{"refresh_token": %q}`, oldTokens.RefreshToken)
+	if w := tokenRequest(application, "", http.MethodPost, "/api/auth/refresh", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the old refresh token to be rejected, got %d", w.Code)
+	}
+	token := changed.Data.AccessToken
+	if w := tokenRequest(application, token, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected the new access token to work, got %d", w.Code)
+	}
+	// The new password signs in
+	login(t, application, "old@example.com", "new-password456")
+
+	// Profile updates can no longer change the email directly
+	userPath := fmt.Sprintf("/api/users/%d", registered.User.ID)
+	if w := tokenRequest(application, token, http.MethodPut, userPath, `{"email": "new@example.com"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a direct email change, got %d", w.Code)
+	}
+
+	w = tokenRequest(application, token, http.MethodPost, "/api/users/me/email", `{"email": "new@example.com", "password": "new-password456"}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status 202, got %d (%s)", w.Code, w.Body.String())
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0] != "new@example.com: Confirm your new email address" {
+		t.Fatalf("Expected a confirmation email to the new address, got %v", mailer.sent)
+	}
+	match := regexp.MustCompile(`https://blog\.example\.com(/api/auth/confirm-email\?token=\w+)`).FindStringSubmatch(mailer.bodies[0])
+	if match == nil {
+		t.Fatalf("Expected a confirmation link in %q", mailer.bodies[0])
+	}
+
+	w = tokenRequest(application, token, http.MethodGet, "/api/auth/me", "")
+	if !strings.Contains(w.Body.String(), `"email":"old@example.com"`) {
+		t.Errorf("Expected the email to stay unchanged until confirmed, got %s", w.Body.String())
+	}
+
+	if w := tokenRequest(application, "", http.MethodGet, match[1], ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, "", http.MethodGet, match[1], ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected a used link to be rejected, got %d", w.Code)
+	}
+	if len(mailer.sent) != 2 || mailer.sent[1] != "old@example.com: Your email address was changed" {
+		t.Errorf("Expected a notice to the old address, got %v", mailer.sent)
+	}
+
+	// and so does the new email
+	login(t, application, "new@example.com", "new-password456")
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	application := setupTestApp(t)
+
+	registered := registerUser(t, application, "reader", "reader@example.com")
+
+	refresh := func(token string) (*httptest.ResponseRecorder, utils.TokenPair) {
+		w := tokenRequest(application, "", http.MethodPost, "/api/auth/refresh", fmt.Sprintf(`{"refresh_token": %q}`, token))
+		var refreshed struct {
+			Data utils.TokenPair `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &refreshed)
+		return w, refreshed.Data
+	}
+
+	first := registered.Tokens.RefreshToken
+	w, second := refresh(first)
+	if w.Code != http.StatusOK || second.RefreshToken == "" || second.RefreshToken == first {
+		t.Fatalf("Expected a new refresh token, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Reusing the first token revokes the whole sign-in, including the second token
+	if w, _ := refresh(first); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a reused token, got %d", w.Code)
+	}
+	if w, _ := refresh(second.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the reuse to revoke the rotated token, got %d", w.Code)
+	}
+
+	// Logging out revokes the session of the presented token
+	signedIn := login(t, application, "reader@example.com", testPassword)
+	body := fmt.Sprintf(`{"refresh_token": %q}`, signedIn.Tokens.RefreshToken)
+	if w := tokenRequest(application, "", http.MethodPost, "/api/auth/logout", body); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w, _ := refresh(signedIn.Tokens.RefreshToken); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a logged out token to be rejected, got %d", w.Code)
+	}
+}
+
+func TestRefreshTokenCookie(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.JWT.RefreshCookie = true
+		cfg.JWT.CookieSecure = true
+		cfg.JWT.CookieSameSite = "strict"
+	})
+
+	refreshCookie := func(w *httptest.ResponseRecorder) *http.Cookie {
+		for _, c := range w.Result().Cookies() {
+			if c.Name == "refresh_token" {
+				return c
+			}
+		}
+		return nil
+	}
+	post := func(path string, cookie *http.Cookie, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		application.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post("/api/auth/register", nil, `{"username": "reader", "email": "reader@example.com", "password": "password123"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	cookie := refreshCookie(w)
+	if cookie == nil || !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode || cookie.Path != "/api" {
+		t.Fatalf("Expected an HttpOnly Secure SameSite=Strict refresh cookie, got %v", cookie)
+	}
+	if strings.Contains(w.Body.String(), "refresh_token") || !strings.Contains(w.Body.String(), "access_token") {
+		t.Errorf("Expected only the access token in the body, got %s", w.Body.String())
+	}
+
+	w = post("/api/auth/refresh", cookie, "")
+	rotated := refreshCookie(w)
+	if w.Code != http.StatusOK || rotated == nil || rotated.Value == cookie.Value {
+		t.Fatalf("Expected a rotated refresh cookie, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Replaying the old cookie is detected and clears the cookie
+	w = post("/api/auth/refresh", cookie, "")
+	if cleared := refreshCookie(w); w.Code != http.StatusUnauthorized || cleared == nil || cleared.MaxAge >= 0 {
+		t.Errorf("Expected status 401 and a cleared cookie, got %d and %v", w.Code, cleared)
+	}
+	if w := post("/api/auth/refresh", rotated, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the reuse to revoke the rotated cookie, got %d", w.Code)
+	}
+}
+
+func TestTokenTypesAreNotInterchangeable(t *testing.T) {
+	application := setupTestApp(t)
+
+	tokens := registerUser(t, application, "reader", "reader@example.com").Tokens
+
+	if w := tokenRequest(application, tokens.RefreshToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a refresh token to be rejected as an access token, got %d", w.Code)
+	}
+	body := fmt.Sprintf(`{"refresh_token": %q}`, tokens.AccessToken)
+	if w := tokenRequest(application, "", http.MethodPost, "/api/auth/refresh", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an access token to be rejected as a refresh token, got %d", w.Code)
+	}
+	if w := tokenRequest(application, tokens.AccessToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected the access token to work, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestSessions(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Sessions.GeoHeader = "CF-IPCountry"
+		cfg.Sessions.NewDeviceAlerts = true
+	})
+	mailer := &recordingMailer{}
+	application.Services.Auth.SetMailer(mailer)
+
+	const laptop = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+	const phone = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	signIn := func(path, body, userAgent, country string) *utils.TokenPair {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
+		req.Header.Set("CF-IPCountry", country)
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		var response struct {
+			Data services.AuthResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Data.Tokens == nil {
+			t.Fatalf("Failed to sign in, got %d (%s)", w.Code, w.Body.String())
+		}
+		return response.Data.Tokens
+	}
+	credentials := `{"email": "reader@example.com", "password": "password123"}`
+
+	first := signIn("/api/auth/register", `{"username": "reader", "email": "reader@example.com", "password": "password123"}`, laptop, "DE")
+	second := signIn("/api/auth/login", credentials, laptop, "DE")
+	if len(mailer.sent) != 0 {
+		t.Errorf("Expected no alert for a known device, got %v", mailer.sent)
+	}
+	mobile := signIn("/api/auth/login", credentials, phone, "US")
+	if len(mailer.sent) != 1 || mailer.sent[0] != "reader@example.com: New sign-in to your account" ||
+		!strings.Contains(mailer.bodies[0], "Safari on iOS") || !strings.Contains(mailer.bodies[0], "Location: US") {
+		t.Errorf("Expected a new device alert, got %v %v", mailer.sent, mailer.bodies)
+	}
+
+	listSessions := func(token string) []models.Session {
+		w := tokenRequest(application, token, http.MethodGet, "/api/users/me/sessions", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []models.Session `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data
+	}
+
+	sessions := listSessions(mobile.AccessToken)
+	if len(sessions) != 3 {
+		t.Fatalf("Expected 3 sessions, got %+v", sessions)
+	}
+	var current *models.Session
+	for i := range sessions {
+		if sessions[i].Current {
+			current = &sessions[i]
+		}
+	}
+	if current == nil || current.Device != "Safari on iOS" || current.Location != "US" || current.IP == "" || current.UserAgent != phone {
+		t.Errorf("Expected the phone session to be current, got %+v", sessions)
+	}
+
+	// Signing out one session rejects its access token straight away
+	secondID := uint(0)
+	for _, session := range sessions {
+		if !session.Current && session.Device == "Firefox on Linux" && secondID < session.ID {
+			secondID = session.ID
+		}
+	}
+	if w := tokenRequest(application, mobile.AccessToken, http.MethodDelete, fmt.Sprintf("/api/users/me/sessions/%d", secondID), ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, second.AccessToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the signed out session's token to be rejected, got %d", w.Code)
+	}
+	if w := tokenRequest(application, mobile.AccessToken, http.MethodDelete, fmt.Sprintf("/api/users/me/sessions/%d", secondID), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a signed out session, got %d", w.Code)
+	}
+
+	// Logging out other devices keeps only the session of the request
+	w := tokenRequest(application, mobile.AccessToken, http.MethodDelete, "/api/users/me/sessions", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"revoked":1`) {
+		t.Fatalf("Expected one other session to be signed out, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, first.AccessToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the other device's token to be rejected, got %d", w.Code)
+	}
+	body := fmt.Sprintf(`{"refresh_token": %q}`, first.RefreshToken)
+	if w := tokenRequest(application, "", http.MethodPost, "/api/auth/refresh", body); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the other device's refresh token to be rejected, got %d", w.Code)
+	}
+	if sessions := listSessions(mobile.AccessToken); len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("Expected only the current session to remain, got %+v", sessions)
+	}
+
+	// Sessions of other users cannot be signed out
+	other := &models.User{Username: "other", Email: "other@example.com"}
+	createUsers(t, application, other)
+	path := fmt.Sprintf("/api/users/me/sessions/%d", listSessions(mobile.AccessToken)[0].ID)
+	if w := authRequest(t, application, other, http.MethodDelete, path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for another user's session, got %d", w.Code)
+	}
+}
+
+func TestMagicLinks(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.MagicLinks = config.MagicLinksConfig{Enabled: true, TTL: 15, MaxPerHour: 3, LinkURL: "https://blog.example.com/magic-link"}
+	})
+	mailer := &recordingMailer{}
+	application.Services.Auth.SetMailer(mailer)
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+
+	const laptop = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+	const phone = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	send := func(path, body, userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", userAgent)
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		return w
+	}
+	requestLink := func() string {
+		t.Helper()
+		sent := len(mailer.bodies)
+		if w := send("/api/auth/magic-link", `{"email": "reader@example.com"}`, laptop); w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status 202, got %d (%s)", w.Code, w.Body.String())
+		}
+		if len(mailer.bodies) != sent+1 {
+			t.Fatalf("Expected a sign-in link email, got %v", mailer.sent)
+		}
+		match := regexp.MustCompile(`https://blog\.example\.com/magic-link\?token=(\w+)`).FindStringSubmatch(mailer.bodies[sent])
+		if match == nil {
+			t.Fatalf("Expected a sign-in link in %q", mailer.bodies[sent])
+		}
+		return match[1]
+	}
+
+	// Unknown addresses get the same answer and no email
+	if w := send("/api/auth/magic-link", `{"email": "nobody@example.com"}`, laptop); w.Code != http.StatusAccepted || len(mailer.sent) != 0 {
+		t.Errorf("Expected unknown addresses to be answered alike, got %d and %v", w.Code, mailer.sent)
+	}
+
+	token := requestLink()
+	if w := send("/api/auth/magic-link/verify", `{"token": "not-a-token"}`, laptop); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an unknown token to be refused, got %d", w.Code)
+	}
+	// Another device must confirm
+	if w := send("/api/auth/magic-link/verify", fmt.Sprintf(`{"token": %q}`, token), phone); w.Code != http.StatusConflict {
+		t.Errorf("Expected another device to need confirmation, got %d (%s)", w.Code, w.Body.String())
+	}
+	w := send("/api/auth/magic-link/verify", fmt.Sprintf(`{"token": %q}`, token), laptop)
+	var response struct {
+		Data services.AuthResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || response.Data.Tokens == nil || response.Data.User.ID != reader.ID {
+		t.Fatalf("Expected to sign in, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, response.Data.Tokens.AccessToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected the access token to work, got %d", w.Code)
+	}
+	if w := send("/api/auth/magic-link/verify", fmt.Sprintf(`{"token": %q}`, token), laptop); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected a used link to be refused, got %d", w.Code)
+	}
+
+	token = requestLink()
+	if w := send("/api/auth/magic-link/verify", fmt.Sprintf(`{"token": %q, "confirm_device": true}`, token), phone); w.Code != http.StatusOK {
+		t.Errorf("Expected a confirmed device to sign in, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Three links an hour from one address
+	requestLink()
+	if w := send("/api/auth/magic-link", `{"email": "reader@example.com"}`, laptop); w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429 past the limit, got %d", w.Code)
+	}
+	// No proxy is trusted, so a forged X-Forwarded-For counts against the same address
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/magic-link", strings.NewReader(`{"email": "reader@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected a forged X-Forwarded-For to be ignored, got %d", w.Code)
+	}
+}
@@ -0,0 +1,413 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+)
+
+func TestCommentMentions(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	mailer := &recordingMailer{}
+	application.Services.Notification.SetMailer(mailer)
+
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	reader := &models.User{Username: "Reader", Email: "reader@example.com"}
+	editor := &models.User{Username: "editor", Email: "editor@example.com"}
+	createUsers(t, application, reader, editor)
+
+	commentsPath := fmt.Sprintf("/api/articles/%d/comments", article.ID)
+	body := `{"content": "Thanks @Author! Not @nobody, nor mail@author.com, and twice @author. Me: @reader"}`
+	w := authRequest(t, application, reader, http.MethodPost, commentsPath, body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.Comment `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	want := []models.MentionRef{
+		{UserID: author.ID, Username: "author", Handle: "author"},
+		{UserID: reader.ID, Username: "Reader", Handle: "reader"},
+	}
+	if fmt.Sprint(created.Data.Mentions) != fmt.Sprint(want) {
+		t.Errorf("Expected mentions %v, got %v", want, created.Data.Mentions)
+	}
+	if strings.Contains(w.Body.String(), "reader@example.com") {
+		t.Errorf("Expected the commenter's email to be hidden")
+	}
+
+	// Only the author is notified; mentioning yourself is not a notification
+	if len(mailer.sent) != 1 || mailer.sent[0] != "author@example.com: Reader mentioned you in a comment" {
+		t.Errorf("Expected one mention email to the author, got %v", mailer.sent)
+	}
+
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, commentsPath, nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"mentions":[{"user_id":`) {
+		t.Errorf("Expected listed comments to carry mentions, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Editing notifies only users who were not mentioned before
+	commentPath := fmt.Sprintf("/api/comments/%d", created.Data.ID)
+	w = authRequest(t, application, reader, http.MethodPut, commentPath, `{"content": "Thanks @author and @editor"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if len(mailer.sent) != 2 || mailer.sent[1] != "editor@example.com: Reader mentioned you in a comment" {
+		t.Errorf("Expected a mention email to the editor only, got %v", mailer.sent)
+	}
+	var updated struct {
+		Data models.Comment `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	mentions := updated.Data.Mentions
+	if len(mentions) != 2 || mentions[0].Handle != "author" || mentions[1].Handle != "editor" {
+		t.Errorf("Expected mentions to follow the edit, got %v", mentions)
+	}
+
+	w = authRequest(t, application, editor, http.MethodGet, "/api/notifications", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"type":"mention"`) {
+		t.Errorf("Expected an in-app mention notification, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestCommentReports(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	application.Services.Comment.SetReportThreshold(2)
+
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	comment := &models.Comment{ArticleID: article.ID, UserID: author.ID, Content: "Buy cheap watches"}
+	if err := application.DB.Create(comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	admin := &models.User{Username: "moderator", Email: "moderator@example.com", Role: models.RoleAdmin}
+	first := &models.User{Username: "first", Email: "first@example.com"}
+	second := &models.User{Username: "second", Email: "second@example.com"}
+	createUsers(t, application, admin, first, second)
+
+	visible := func() bool {
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/articles/%d/comments", article.ID), nil))
+		return strings.Contains(w.Body.String(), "Buy cheap watches")
+	}
+
+	reportPath := fmt.Sprintf("/api/comments/%d/report", comment.ID)
+	tests := []struct {
+		user   *models.User
+		body   string
+		status int
+	}{
+		{first, `{"reason": "rude"}`, http.StatusBadRequest},
+		{&author, `{"reason": "spam"}`, http.StatusBadRequest},
+		{first, `{"reason": "spam", "details": "advertising"}`, http.StatusCreated},
+		{first, `{"reason": "abuse"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		if w := authRequest(t, application, tt.user, http.MethodPost, reportPath, tt.body); w.Code != tt.status {
+			t.Errorf("%s reporting %s: expected status %d, got %d (%s)", tt.user.Username, tt.body, tt.status, w.Code, w.Body.String())
+		}
+	}
+	if !visible() {
+		t.Fatalf("Expected the comment to stay visible below the threshold")
+	}
+
+	if w := authRequest(t, application, second, http.MethodPost, reportPath, `{"reason": "spam"}`); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	if visible() {
+		t.Errorf("Expected the comment to be hidden at the threshold")
+	}
+
+	if w := authRequest(t, application, first, http.MethodGet, "/api/admin/comment-reports", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+	w := authRequest(t, application, admin, http.MethodGet, "/api/admin/comment-reports", "")
+	var queue struct {
+		Data []services.ReportQueueItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &queue); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(queue.Data) != 1 || queue.Data[0].ReportCount != 2 || len(queue.Data[0].Reports) != 2 || !queue.Data[0].Comment.Hidden {
+		t.Fatalf("Expected one hidden comment with two reports, got %s", w.Body.String())
+	}
+
+	reviewPath := fmt.Sprintf("/api/admin/comments/%d/review", comment.ID)
+	if w := authRequest(t, application, admin, http.MethodPost, reviewPath, `{"action": "dismiss"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if !visible() {
+		t.Errorf("Expected a dismissed comment to be visible again")
+	}
+	w = authRequest(t, application, admin, http.MethodGet, "/api/admin/comment-reports", "")
+	if !strings.Contains(w.Body.String(), `"data":[]`) {
+		t.Errorf("Expected an empty queue after review, got %s", w.Body.String())
+	}
+}
+
+func TestCommentDeletionKeepsThreads(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+
+	comments := application.Services.Comment
+	create := func(userID, articleID uint, parentID *uint) *models.Comment {
+		t.Helper()
+		comment := &models.Comment{ArticleID: articleID, UserID: userID, ParentID: parentID, Content: "Nice article"}
+		if err := comments.Create(comment, ""); err != nil {
+			t.Fatalf("Failed to create comment: %v", err)
+		}
+		return comment
+	}
+	thread := func() []models.Comment {
+		t.Helper()
+		list, err := comments.GetByArticle(article.ID)
+		if err != nil {
+			t.Fatalf("Failed to list comments: %v", err)
+		}
+		return list
+	}
+
+	// A comment with a reply becomes a tombstone keeping the reply in place
+	root := create(reader.ID, article.ID, nil)
+	reply := create(author.ID, article.ID, &root.ID)
+	if err := comments.Delete(root.ID, reader.ID); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	list := thread()
+	if len(list) != 1 || !list[0].Tombstone || list[0].Content != "" || list[0].UserID != 0 || len(list[0].Replies) != 1 {
+		t.Fatalf("Expected a tombstone with one reply, got %+v", list)
+	}
+	if _, err := comments.Update(root.ID, reader.ID, "Back again"); !errors.Is(err, services.ErrNotFound) {
+		t.Errorf("Expected editing a tombstone to fail with not found, got %v", err)
+	}
+
+	// Deleting the last reply removes the tombstone too
+	if err := comments.Delete(reply.ID, author.ID); err != nil {
+		t.Fatalf("Failed to delete reply: %v", err)
+	}
+	if list := thread(); len(list) != 0 {
+		t.Errorf("Expected the thread to be gone, got %+v", list)
+	}
+
+	// Deleting an article deletes its comments
+	create(reader.ID, article.ID, nil)
+	if err := application.Services.Article.Delete(article.ID, author.ID); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	var count int64
+	if err := application.DB.GetDB().Model(&models.Comment{}).Where("article_id = ?", article.ID).Count(&count).Error; err != nil || count != 0 {
+		t.Errorf("Expected the article's comments to be deleted, got %d (%v)", count, err)
+	}
+}
+
+func TestCommentCounts(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	admin := &models.User{Username: "moderator", Email: "moderator@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+
+	comments := application.Services.Comment
+	expectCount := func(want uint) {
+		t.Helper()
+		_, _, count, err := application.Repositories.Article.GetStatistics(article.ID)
+		if err != nil || count != want {
+			t.Errorf("Expected comment count %d, got %d (%v)", want, count, err)
+		}
+	}
+
+	// Posted comments count, inserts bypassing the service do not
+	root := &models.Comment{ArticleID: article.ID, UserID: author.ID, Content: "First comment"}
+	if err := comments.Create(root, ""); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	reply := &models.Comment{ArticleID: article.ID, UserID: author.ID, ParentID: &root.ID, Content: "A reply"}
+	if err := comments.Create(reply, ""); err != nil {
+		t.Fatalf("Failed to create reply: %v", err)
+	}
+	if err := application.DB.Create(&models.Comment{ArticleID: article.ID, UserID: author.ID, Content: "Imported"}); err != nil {
+		t.Fatalf("Failed to insert comment: %v", err)
+	}
+	expectCount(2)
+
+	// Hiding takes a comment out of the count once, approving it again counts it
+	for _, action := range []services.ReviewAction{services.ReviewHide, services.ReviewHide} {
+		if err := comments.ReviewReports(root.ID, action); err != nil {
+			t.Fatalf("Failed to review comment: %v", err)
+		}
+	}
+	expectCount(1)
+	if err := comments.ReviewReports(root.ID, services.ReviewDismiss); err != nil {
+		t.Fatalf("Failed to review comment: %v", err)
+	}
+	expectCount(2)
+
+	// A deleted comment no longer counts, even as a tombstone
+	if err := comments.Delete(root.ID, author.ID); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+	expectCount(1)
+
+	report := func() services.CommentCountReport {
+		t.Helper()
+		w := authRequest(t, application, admin, http.MethodGet, "/api/admin/stats/comment-counts", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data services.CommentCountReport `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data
+	}
+	if w := authRequest(t, application, &author, http.MethodGet, "/api/admin/stats/comment-counts", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+	drift := report()
+	if drift.Consistent || drift.Drifted != 1 || len(drift.Articles) != 1 {
+		t.Fatalf("Expected one drifted article, got %+v", drift)
+	}
+	if got := drift.Articles[0]; got.ArticleID != article.ID || got.StoredCount != 1 || got.ActualCount != 2 {
+		t.Errorf("Expected the imported comment to be missing from the count, got %+v", got)
+	}
+
+	corrected, err := application.Services.Statistics.ReconcileCommentCounts()
+	if err != nil || corrected != 1 {
+		t.Fatalf("Expected one article corrected, got %d (%v)", corrected, err)
+	}
+	expectCount(2)
+	if drift := report(); !drift.Consistent || drift.Drifted != 0 || len(drift.Articles) != 0 {
+		t.Errorf("Expected consistent counts after reconciling, got %+v", drift)
+	}
+}
+
+func TestCommentSubscriptions(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	mailer := &recordingMailer{}
+	application.Services.Notification.SetMailer(mailer)
+	application.Services.Comment.SetPublicURL("https://blog.example.com/")
+
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	editor := &models.User{Username: "editor", Email: "editor@example.com"}
+	createUsers(t, application, reader, editor)
+
+	subscriptionPath := fmt.Sprintf("/api/articles/%d/subscription", article.ID)
+	commentsPath := fmt.Sprintf("/api/articles/%d/comments", article.ID)
+	comment := func(user *models.User) {
+		t.Helper()
+		if w := authRequest(t, application, user, http.MethodPost, commentsPath, `{"content": "Nice article"}`); w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+		}
+	}
+
+	if w := authRequest(t, application, &author, http.MethodPost, subscriptionPath, ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, &author, http.MethodGet, subscriptionPath, ""); !strings.Contains(w.Body.String(), `"subscribed":true`) {
+		t.Errorf("Expected the author to be subscribed, got %s", w.Body.String())
+	}
+	if w := authRequest(t, application, &author, http.MethodPost, "/api/articles/999/subscription", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing article, got %d", w.Code)
+	}
+
+	// Commenting subscribes the reader, who is not notified of their own comment
+	comment(reader)
+	if w := authRequest(t, application, reader, http.MethodGet, subscriptionPath, ""); !strings.Contains(w.Body.String(), `"subscribed":true`) {
+		t.Errorf("Expected commenting to subscribe the reader, got %s", w.Body.String())
+	}
+	if len(mailer.sent) != 1 || mailer.sent[0] != `author@example.com: reader commented on "Go web"` {
+		t.Fatalf("Expected one email to the author, got %v", mailer.sent)
+	}
+	match := regexp.MustCompile(`Unsubscribe: https://blog\.example\.com(/api/comment-subscriptions/unsubscribe\?token=\w+)`).FindStringSubmatch(mailer.bodies[0])
+	if match == nil {
+		t.Fatalf("Expected an unsubscribe link in %q", mailer.bodies[0])
+	}
+
+	comment(editor)
+	if len(mailer.sent) != 3 {
+		t.Fatalf("Expected emails to the author and the reader, got %v", mailer.sent)
+	}
+
+	// The email link unsubscribes without signing in, once
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, match[1], nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, match[1], nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a used link, got %d", w.Code)
+	}
+
+	mailer.sent = nil
+	comment(editor)
+	if len(mailer.sent) != 1 || mailer.sent[0] != `reader@example.com: editor commented on "Go web"` {
+		t.Errorf("Expected only the reader to be notified, got %v", mailer.sent)
+	}
+
+	if w := authRequest(t, application, reader, http.MethodDelete, subscriptionPath, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, reader, http.MethodDelete, subscriptionPath, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 when not subscribed, got %d", w.Code)
+	}
+}
@@ -0,0 +1,468 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/handlers"
+	"go-blog/internal/models"
+	"go-blog/internal/queue"
+	"go-blog/internal/repositories"
+	"go-blog/internal/routes"
+	"go-blog/internal/scheduler"
+	"go-blog/internal/services"
+	"go-blog/internal/storage"
+	"go-blog/pkg/config"
+)
+
+// Repositories holds every repository used by the application
+type Repositories struct {
+	User                repositories.UserRepository
+	RefreshToken        repositories.RefreshTokenRepository
+	Session             repositories.SessionRepository
+	MagicLink           repositories.MagicLinkRepository
+	Article             repositories.ArticleRepository
+	ArticleRevision     repositories.ArticleRevisionRepository
+	ShortLink           repositories.ShortLinkRepository
+	ArticleView         repositories.ArticleViewRepository
+	ArticleRanking      repositories.ArticleRankingRepository
+	ArticleLink         repositories.ArticleLinkRepository
+	AuthorReport        repositories.AuthorReportRepository
+	Category            repositories.CategoryRepository
+	Tag                 repositories.TagRepository
+	TagAlias            repositories.TagAliasRepository
+	Comment             repositories.CommentRepository
+	Mention             repositories.MentionRepository
+	CommentReport       repositories.CommentReportRepository
+	CommentSubscription repositories.CommentSubscriptionRepository
+	ContentFilterRule   repositories.ContentFilterRuleRepository
+	Like                repositories.LikeRepository
+	Follow              repositories.FollowRepository
+	SavedSearch         repositories.SavedSearchRepository
+	Notification        repositories.NotificationRepository
+	UserSettings        repositories.UserSettingsRepository
+	ArticleMeta         repositories.ArticleMetaRepository
+	Page                repositories.PageRepository
+	HomepageSection     repositories.HomepageSectionRepository
+	Policy              repositories.PolicyRepository
+	JobRun              repositories.JobRunRepository
+	QueuedJob           repositories.QueuedJobRepository
+	Transactor          repositories.Transactor
+}
+
+// Services holds every service used by the application
+type Services struct {
+	Auth          *services.AuthService
+	User          *services.UserService
+	Article       *services.ArticleService
+	Category      *services.CategoryService
+	Tag           *services.TagService
+	Comment       *services.CommentService
+	ContentFilter *services.ContentFilterService
+	Follow        *services.FollowService
+	Archive       *services.ArchiveService
+	Statistics    *services.StatisticsService
+	Like          *services.LikeService
+	Search        *services.SearchService
+	SavedSearch   *services.SavedSearchService
+	Notification  *services.NotificationService
+	UserSettings  *services.UserSettingsService
+	Maintenance   *services.MaintenanceService
+	Site          *services.SiteVisibilityService
+	Page          *services.PageService
+	Homepage      *services.HomepageService
+	Policy        *services.PolicyService
+	ShortLink     *services.ShortLinkService
+	AuthorReport  *services.AuthorReportService
+	SLO           *services.SLOService
+	LinkCheck     *services.LinkCheckService
+	SearchEngines *services.SearchEngineNotifier // nil unless enabled
+	Embed         *services.EmbedService         // nil unless enabled
+	OGImage       *services.OGImageService       // nil unless enabled with storage
+}
+
+// newRepositories creates all repositories on top of db
+func newRepositories(db *database.DB) *Repositories {
+	return &Repositories{
+		User:                repositories.NewUserRepository(db),
+		RefreshToken:        repositories.NewRefreshTokenRepository(db),
+		Session:             repositories.NewSessionRepository(db),
+		MagicLink:           repositories.NewMagicLinkRepository(db),
+		Article:             repositories.NewArticleRepository(db),
+		ArticleRevision:     repositories.NewArticleRevisionRepository(db),
+		ShortLink:           repositories.NewShortLinkRepository(db),
+		ArticleView:         repositories.NewArticleViewRepository(db),
+		ArticleRanking:      repositories.NewArticleRankingRepository(db),
+		ArticleLink:         repositories.NewArticleLinkRepository(db),
+		AuthorReport:        repositories.NewAuthorReportRepository(db),
+		Category:            repositories.NewCategoryRepository(db),
+		Tag:                 repositories.NewTagRepository(db),
+		TagAlias:            repositories.NewTagAliasRepository(db),
+		Comment:             repositories.NewCommentRepository(db),
+		Mention:             repositories.NewMentionRepository(db),
+		CommentReport:       repositories.NewCommentReportRepository(db),
+		CommentSubscription: repositories.NewCommentSubscriptionRepository(db),
+		ContentFilterRule:   repositories.NewContentFilterRuleRepository(db),
+		Like:                repositories.NewLikeRepository(db),
+		Follow:              repositories.NewFollowRepository(db),
+		SavedSearch:         repositories.NewSavedSearchRepository(db),
+		Notification:        repositories.NewNotificationRepository(db),
+		UserSettings:        repositories.NewUserSettingsRepository(db),
+		ArticleMeta:         repositories.NewArticleMetaRepository(db),
+		Page:                repositories.NewPageRepository(db),
+		HomepageSection:     repositories.NewHomepageSectionRepository(db),
+		Policy:              repositories.NewPolicyRepository(db),
+		JobRun:              repositories.NewJobRunRepository(db),
+		QueuedJob:           repositories.NewQueuedJobRepository(db),
+		Transactor:          repositories.NewTransactor(db),
+	}
+}
+
+// newStorage creates the configured file storage, or nil when uploads are not configured
+func newStorage(cfg *config.Config) (storage.Storage, error) {
+	if cfg.Storage.Driver == "s3" {
+		s3 := cfg.Storage.S3
+		publicURL := cfg.Storage.BaseURL
+		if cfg.Storage.CDNURL != "" {
+			publicURL = cfg.Storage.CDNURL
+		}
+		bucket, err := storage.NewS3(storage.S3Options{
+			Endpoint:     s3.Endpoint,
+			Region:       s3.Region,
+			Bucket:       s3.Bucket,
+			AccessKey:    s3.AccessKey,
+			SecretKey:    s3.SecretKey,
+			PathStyle:    s3.PathStyle,
+			BaseURL:      publicURL,
+			CacheControl: storage.ImmutableCacheControl(cfg.Storage.CacheMaxAge),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return bucket, nil
+	}
+	if cfg.Storage.LocalPath == "" {
+		return nil, nil
+	}
+	local := storage.NewLocal(cfg.Storage.LocalPath, cfg.Storage.BaseURL)
+	local.CDNURL = cfg.Storage.CDNURL
+	return local, nil
+}
+
+// newQueue creates the background job queue; services register their job handlers on it
+func newQueue(cfg *config.Config, repos *Repositories) *queue.Queue {
+	seconds := func(n int) time.Duration { return time.Duration(n) * time.Second }
+	return queue.New(repos.QueuedJob, queue.Options{
+		Workers:      cfg.Queue.Workers,
+		MaxAttempts:  cfg.Queue.MaxAttempts,
+		Backoff:      seconds(cfg.Queue.Backoff),
+		MaxBackoff:   seconds(cfg.Queue.MaxBackoff),
+		PollInterval: seconds(cfg.Queue.PollInterval),
+		Timeout:      seconds(cfg.Queue.Timeout),
+	})
+}
+
+// newServices creates all services and injects their optional dependencies
+func newServices(cfg *config.Config, repos *Repositories, store storage.Storage, jobs *queue.Queue) *Services {
+	settingsService := services.NewUserSettingsService(repos.UserSettings)
+	mailer := services.NewQueuedMailer(jobs, services.LogMailer{}) // Deliver email from the job queue, retrying failures
+
+	authService := services.NewAuthService(repos.User, cfg.JWT.Secret)
+	authService.SetPublicURL(cfg.Server.PublicURL) // Base of email confirmation links
+	authService.SetRefreshTokenRepository(repos.RefreshToken)
+	authService.SetSessionRepository(repos.Session) // Record sign-ins with their device for session management
+	authService.SetNewDeviceAlerts(cfg.Sessions.NewDeviceAlerts)
+	authService.SetMailer(mailer)
+	if cfg.MagicLinks.Enabled {
+		// Sign in with single-use links emailed to the account's address
+		authService.SetMagicLinks(repos.MagicLink, services.MagicLinkOptions{
+			TTL:        time.Duration(cfg.MagicLinks.TTL) * time.Minute,
+			MaxPerHour: cfg.MagicLinks.MaxPerHour,
+			LinkURL:    cfg.MagicLinks.LinkURL,
+		})
+	}
+	policyService := services.NewPolicyService(repos.Policy)
+	authService.SetPolicyService(policyService) // Registering accepts the current terms and policies
+
+	userService := services.NewUserService(repos.User)
+	userService.SetArticleRepository(repos.Article) // Inject article repository for user articles
+	userService.SetLikeRepository(repos.Like)       // Count the likes users gave on their profiles
+	userService.SetSettingsService(settingsService)
+	if store != nil {
+		userService.SetStorage(store)
+	}
+
+	tagService := services.NewTagService(repos.Tag)
+	tagService.SetAliasRepository(repos.TagAlias) // Resolve tag synonyms to canonical tags
+	tagService.SetProtectedTags(cfg.Tags.Protected)
+
+	// Rules administrators manage for comments and article titles
+	contentFilter := services.NewContentFilterService(repos.ContentFilterRule)
+
+	articleService := services.NewArticleService(repos.Article, repos.User, repos.Category, repos.Tag)
+	articleService.SetTagService(tagService)
+	articleService.SetTransactor(repos.Transactor)              // Write articles and their tags atomically
+	articleService.SetRevisionRepository(repos.ArticleRevision) // Keep a revision per title or content change
+	articleService.SetContentFilter(contentFilter)              // Block, hold or mask titles
+	articleService.SetLikeRepository(repos.Like)                // Tell signed-in readers which articles they liked
+	articleService.SetMetaRepository(repos.ArticleMeta)         // Custom fields for integrators
+	// Starts in the configured visibility; admins change it at runtime
+	siteService := services.NewSiteVisibilityService(services.SiteVisibilityState{
+		Visibility: services.SiteVisibility(cfg.Site.Visibility),
+		Message:    cfg.Site.Message,
+	})
+	searchEngines := newSearchEngineNotifier(cfg)
+	if searchEngines != nil {
+		searchEngines.SetQueue(jobs)
+		searchEngines.SetSiteVisibility(siteService)          // Keep unlisted and private blogs out of search engines
+		articleService.SetSearchEngineNotifier(searchEngines) // Ping sitemaps and IndexNow on publish
+	}
+
+	searchService := services.NewSearchService(repos.Article, repos.Category, repos.Tag, repos.User)
+	searchService.SetTagService(tagService) // Resolve tag aliases of drafts asking for interlinks
+	searchService.SetAnalyzer(services.NewSearchAnalyzer(cfg.Search.StopWords, cfg.Search.Synonyms))
+	notificationService := services.NewNotificationService(repos.Notification, repos.User)
+	notificationService.SetSettingsService(settingsService)
+	notificationService.SetMailer(mailer)
+
+	commentService := services.NewCommentService(repos.Comment, repos.Article, repos.User)
+	commentService.SetMentionRepository(repos.Mention) // Record and notify @handle mentions
+	commentService.SetNotificationService(notificationService)
+	commentService.SetReportRepository(repos.CommentReport)
+	commentService.SetReportThreshold(cfg.Comments.ReportThreshold)
+	commentService.SetSubscriptionRepository(repos.CommentSubscription) // Watch threads, auto-subscribing commenters
+	commentService.SetPublicURL(cfg.Server.PublicURL)                   // Base of unsubscribe links
+	commentService.SetContentFilter(contentFilter)                      // Block, hold for review or mask comments
+
+	likeService := services.NewLikeService(repos.Like, repos.Article, repos.User)
+	likeService.SetTransactor(repos.Transactor) // Keep article like counters in step with likes
+
+	statisticsService := services.NewStatisticsService(repos.Article, repos.Like, repos.Comment)
+	statisticsService.SetShortLinkRepository(repos.ShortLink)     // Report short link clicks
+	statisticsService.SetArticleViewRepository(repos.ArticleView) // Break views down by referrer and UTM parameters
+	if cfg.Stats.RankingInterval > 0 {
+		// Read popular and trending articles from rankings the article_rankings job refreshes
+		statisticsService.SetArticleRankingRepository(repos.ArticleRanking, time.Duration(cfg.Stats.TrendingWindow)*day)
+	}
+
+	shortLinkService := services.NewShortLinkService(repos.ShortLink, repos.Article)
+	shortLinkService.SetPublicURL(cfg.Server.PublicURL)
+	shortLinkService.SetArticleURL(articleURLTemplate(cfg)) // Where short links redirect to
+
+	authorReportService := services.NewAuthorReportService(repos.AuthorReport, repos.User, settingsService, mailer)
+	authorReportService.SetArticleURL(articleURLTemplate(cfg)) // Link top articles from reports
+
+	if cfg.Suggestions.Enabled {
+		// Suggest excerpts, tags and meta descriptions of drafts with a language model
+		articleService.SetContentSuggester(services.NewOpenAISuggester(services.OpenAIOptions{
+			BaseURL: cfg.Suggestions.BaseURL,
+			APIKey:  cfg.Suggestions.APIKey,
+			Model:   cfg.Suggestions.Model,
+			Timeout: time.Duration(cfg.Suggestions.Timeout) * time.Second,
+		}))
+	}
+
+	ogImageService := newOGImageService(cfg.OGImages, repos, store)
+	if ogImageService != nil {
+		ogImageService.SetQueue(jobs)
+		articleService.SetOGImageService(ogImageService) // Render share images on publish and title changes
+	}
+
+	if cfg.Quotas.Enabled {
+		quotaService := newQuotaService(cfg.Quotas, repos)
+		articleService.SetQuotaService(quotaService) // Refuse articles over quota
+		commentService.SetQuotaService(quotaService) // Refuse comments over quota
+	}
+
+	// Starts in the configured mode; admins toggle it at runtime
+	maintenanceService := services.NewMaintenanceService(services.MaintenanceState{
+		Enabled:    cfg.Maintenance.Enabled,
+		BlockReads: cfg.Maintenance.BlockReads,
+		Message:    cfg.Maintenance.Message,
+		RetryAfter: cfg.Maintenance.RetryAfter,
+	})
+
+	return &Services{
+		Auth:          authService,
+		User:          userService,
+		Article:       articleService,
+		Category:      services.NewCategoryService(repos.Category, repos.Article),
+		Tag:           tagService,
+		Comment:       commentService,
+		ContentFilter: contentFilter,
+		Follow:        services.NewFollowService(repos.Follow, repos.Category, repos.Tag, repos.Article),
+		Archive:       services.NewArchiveService(repos.Article),
+		Statistics:    statisticsService,
+		Like:          likeService,
+		Search:        searchService,
+		SavedSearch:   services.NewSavedSearchService(repos.SavedSearch, searchService, notificationService),
+		Notification:  notificationService,
+		UserSettings:  settingsService,
+		Maintenance:   maintenanceService,
+		Site:          siteService,
+		Page:          services.NewPageService(repos.Page),
+		Homepage:      services.NewHomepageService(repos.HomepageSection, repos.Article, repos.Category, repos.Tag),
+		Policy:        policyService,
+		ShortLink:     shortLinkService,
+		AuthorReport:  authorReportService,
+		SLO:           newSLOService(cfg.SLO),
+		LinkCheck:     newLinkCheckService(cfg, repos, notificationService),
+		SearchEngines: searchEngines,
+		Embed:         newEmbedService(cfg.Embeds),
+		OGImage:       ogImageService,
+	}
+}
+
+// newOGImageService creates the renderer of article share images, or nil when
+// they are disabled or there is no storage to keep them in
+func newOGImageService(cfg config.OGImagesConfig, repos *Repositories, store storage.Storage) *services.OGImageService {
+	if !cfg.Enabled || store == nil {
+		return nil
+	}
+	ogImages, err := services.NewOGImageService(repos.Article, store, services.OGImageOptions{
+		SiteName:   cfg.SiteName,
+		Template:   cfg.Template,
+		Background: cfg.Background,
+		Foreground: cfg.Foreground,
+		Accent:     cfg.Accent,
+	})
+	if err != nil {
+		slog.Error("OG images are disabled: template is unusable", "error", err)
+		return nil
+	}
+	return ogImages
+}
+
+// newEmbedService creates the oEmbed proxy of the allowed providers, or nil when
+// embeds are disabled
+func newEmbedService(cfg config.EmbedsConfig) *services.EmbedService {
+	if !cfg.Enabled {
+		return nil
+	}
+	return services.NewEmbedService(services.EmbedOptions{
+		Providers: cfg.Providers,
+		CacheTTL:  time.Duration(cfg.CacheTTL) * time.Minute,
+		Privacy:   cfg.Privacy,
+	})
+}
+
+// newLinkCheckService creates the checker of external links in published
+// articles, which tells authors about broken links
+func newLinkCheckService(cfg *config.Config, repos *Repositories, notificationService *services.NotificationService) *services.LinkCheckService {
+	return services.NewLinkCheckService(repos.ArticleLink, notificationService, services.LinkCheckOptions{
+		RequestInterval: time.Duration(cfg.LinkCheck.RequestInterval) * time.Millisecond,
+		Timeout:         time.Duration(cfg.LinkCheck.Timeout) * time.Second,
+		PublicURL:       cfg.Server.PublicURL,
+		ArticleURL:      articleURLTemplate(cfg),
+	})
+}
+
+// newQuotaService creates the quota service of the configured role and IP quotas
+func newQuotaService(cfg config.QuotasConfig, repos *Repositories) *services.QuotaService {
+	roles := make(map[models.UserRole]services.Quota, len(cfg.Roles))
+	for role, quota := range cfg.Roles {
+		roles[models.UserRole(role)] = services.Quota{ArticlesPerDay: quota.ArticlesPerDay, CommentsPerHour: quota.CommentsPerHour}
+	}
+	ip := services.Quota{ArticlesPerDay: cfg.IP.ArticlesPerDay, CommentsPerHour: cfg.IP.CommentsPerHour}
+	return services.NewQuotaService(repos.Article, repos.Comment, roles, ip)
+}
+
+// newSLOService creates the SLO service of the configured route groups
+func newSLOService(cfg config.SLOConfig) *services.SLOService {
+	objectives := make([]services.SLObjective, len(cfg.Groups))
+	for i, group := range cfg.Groups {
+		objectives[i] = services.SLObjective{
+			Name:             group.Name,
+			Prefix:           group.Prefix,
+			Availability:     group.Availability,
+			LatencyThreshold: time.Duration(group.LatencyThreshold) * time.Millisecond,
+			LatencyTarget:    group.LatencyTarget,
+		}
+	}
+	windows := make([]time.Duration, len(cfg.Windows))
+	for i, window := range cfg.Windows {
+		windows[i] = time.Duration(window) * time.Minute
+	}
+	return services.NewSLOService(objectives, windows, cfg.AlertBurnRate)
+}
+
+// articleURLTemplate returns the public URL of articles, {slug} standing for
+// the article's slug
+func articleURLTemplate(cfg *config.Config) string {
+	if cfg.SearchEngines.ArticleURL != "" {
+		return cfg.SearchEngines.ArticleURL
+	}
+	return strings.TrimSuffix(cfg.Server.PublicURL, "/") + "/articles/{slug}"
+}
+
+// newSearchEngineNotifier creates the notifier told about published articles, or
+// nil when search engine notifications are disabled
+func newSearchEngineNotifier(cfg *config.Config) *services.SearchEngineNotifier {
+	if !cfg.SearchEngines.Enabled {
+		return nil
+	}
+
+	return services.NewSearchEngineNotifier(services.SearchEngineOptions{
+		ArticleURL:       articleURLTemplate(cfg),
+		SitemapURL:       cfg.SearchEngines.SitemapURL,
+		PingURLs:         cfg.SearchEngines.PingURLs,
+		IndexNowKey:      cfg.SearchEngines.IndexNowKey,
+		IndexNowEndpoint: cfg.SearchEngines.IndexNowEndpoint,
+	})
+}
+
+// newHandlers creates all HTTP handlers
+func newHandlers(cfg *config.Config, svc *Services, jobs *scheduler.Scheduler, q *queue.Queue) *routes.Handlers {
+	authHandler := handlers.NewAuthHandler(svc.Auth)
+	authHandler.SetGeoHeader(cfg.Sessions.GeoHeader)
+	if cfg.JWT.RefreshCookie {
+		authHandler.SetRefreshCookie(&handlers.RefreshCookie{
+			Secure:   cfg.JWT.CookieSecure,
+			SameSite: cookieSameSite(cfg.JWT.CookieSameSite),
+		})
+	}
+
+	return &routes.Handlers{
+		Auth:          authHandler,
+		User:          handlers.NewUserHandler(svc.User),
+		Article:       handlers.NewArticleHandler(svc.Article),
+		Category:      handlers.NewCategoryHandler(svc.Category),
+		Tag:           handlers.NewTagHandler(svc.Tag),
+		Comment:       handlers.NewCommentHandler(svc.Comment),
+		Follow:        handlers.NewFollowHandler(svc.Follow),
+		Archive:       handlers.NewArchiveHandler(svc.Archive),
+		Statistics:    handlers.NewStatisticsHandler(svc.Statistics),
+		Like:          handlers.NewLikeHandler(svc.Like),
+		Search:        handlers.NewSearchHandler(svc.Search),
+		SavedSearch:   handlers.NewSavedSearchHandler(svc.SavedSearch),
+		Notification:  handlers.NewNotificationHandler(svc.Notification),
+		Settings:      handlers.NewSettingsHandler(svc.UserSettings),
+		Maintenance:   handlers.NewMaintenanceHandler(svc.Maintenance),
+		Site:          handlers.NewSiteVisibilityHandler(svc.Site),
+		Page:          handlers.NewPageHandler(svc.Page),
+		Homepage:      handlers.NewHomepageHandler(svc.Homepage),
+		Job:           handlers.NewJobHandler(jobs),
+		Queue:         handlers.NewQueueHandler(q),
+		ShortLink:     handlers.NewShortLinkHandler(svc.ShortLink),
+		SLO:           handlers.NewSLOHandler(svc.SLO),
+		LinkCheck:     handlers.NewLinkCheckHandler(svc.LinkCheck),
+		ContentFilter: handlers.NewContentFilterHandler(svc.ContentFilter),
+		Policy:        handlers.NewPolicyHandler(svc.Policy),
+		Embed:         handlers.NewEmbedHandler(svc.Embed),
+	}
+}
+
+// cookieSameSite maps a configured SameSite mode to its cookie attribute
+func cookieSameSite(mode string) http.SameSite {
+	switch mode {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
@@ -0,0 +1,345 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestEmbeds(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Embeds = config.EmbedsConfig{Enabled: true, Providers: []string{"youtube", "twitter", "gist"}, CacheTTL: 60, Privacy: true}
+	})
+
+	// Stand in for the oEmbed endpoints of YouTube and Twitter
+	var requests []*url.URL
+	application.Services.Embed.SetClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.URL)
+		link := req.URL.Query().Get("url")
+		var status int
+		var body string
+		switch {
+		case strings.Contains(link, "AAAAAAAAAAA"):
+			return nil, errors.New("connection refused")
+		case req.URL.Host == "www.youtube.com":
+			status, body = http.StatusOK, `{"type": "video", "title": "A talk", "html": "<iframe src=\"https://www.youtube.com/embed/dQw4w9WgXcQ?feature=oembed\"></iframe>", "width": 200, "height": 113}`
+		case strings.HasSuffix(link, "/status/404"):
+			status, body = http.StatusNotFound, ""
+		default:
+			status, body = http.StatusOK, `{"type": "rich", "author_name": "jack", "html": "<blockquote class=\"twitter-tweet\">just setting up</blockquote>"}`
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+	})})
+
+	resolve := func(link string, status int) services.Embed {
+		t.Helper()
+		w := tokenRequest(application, "", http.MethodGet, "/api/embeds?url="+url.QueryEscape(link), "")
+		if w.Code != status {
+			t.Fatalf("Resolving %s: expected status %d, got %d (%s)", link, status, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data services.Embed `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+
+	// Tracking parameters are dropped and the no-cookie player is embedded
+	video := resolve("https://youtu.be/dQw4w9WgXcQ?si=tracking&t=42", http.StatusOK)
+	if video.Provider != "youtube" || video.URL != "https://www.youtube.com/watch?t=42&v=dQw4w9WgXcQ" || video.Title != "A talk" {
+		t.Errorf("Unexpected video embed %+v", video)
+	}
+	if !strings.Contains(video.HTML, "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ") {
+		t.Errorf("Expected the no-cookie player, got %s", video.HTML)
+	}
+	if len(requests) != 1 || requests[0].Query().Get("url") != video.URL {
+		t.Fatalf("Expected the canonical link to be resolved, got %v", requests)
+	}
+	resolve("https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42&utm_source=feed", http.StatusOK)
+	if len(requests) != 1 {
+		t.Errorf("Expected the same video to be served from the cache, got %d requests", len(requests))
+	}
+
+	tweet := resolve("https://x.com/jack/status/20?s=20", http.StatusOK)
+	if tweet.Provider != "twitter" || tweet.URL != "https://twitter.com/jack/status/20" || tweet.AuthorName != "jack" {
+		t.Errorf("Unexpected tweet embed %+v", tweet)
+	}
+	if query := requests[len(requests)-1].Query(); query.Get("dnt") != "true" || query.Get("omit_script") != "true" {
+		t.Errorf("Expected Twitter to be asked not to track, got %v", query)
+	}
+	resolve("https://twitter.com/jack/status/404", http.StatusNotFound)
+
+	// Gists have no oEmbed endpoint and are embedded with their script
+	calls := len(requests)
+	gist := resolve("https://gist.github.com/octocat/6cad326836d38bd3a7ae/", http.StatusOK)
+	if gist.Provider != "gist" || gist.HTML != `<script src="https://gist.github.com/octocat/6cad326836d38bd3a7ae.js"></script>` {
+		t.Errorf("Unexpected gist embed %+v", gist)
+	}
+	if len(requests) != calls {
+		t.Errorf("Expected gists to be embedded without a request")
+	}
+
+	resolve("https://www.youtube.com/watch?v=AAAAAAAAAAA", http.StatusBadGateway)
+	for _, link := range []string{"https://vimeo.com/76979871", "https://www.youtube.com/feed/trending", "javascript:alert(1)", ""} {
+		resolve(link, http.StatusBadRequest)
+	}
+
+	disabled := setupTestApp(t)
+	if w := tokenRequest(disabled, "", http.MethodGet, "/api/embeds?url=https://youtu.be/dQw4w9WgXcQ", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 with embeds disabled, got %d", w.Code)
+	}
+}
+
+func TestLinkCheck(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Server.PublicURL = "https://blog.example.com"
+	})
+	seedArticles(t, application)
+	admin := &models.User{Username: "link_admin", Email: "link_admin@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	db := application.DB.GetDB()
+	db.Model(&article).UpdateColumn("content", "See [the docs](https://go.dev/doc/), https://example.com/gone. "+
+		"and <a href=\"https://down.example.com/\">this</a>, again https://go.dev/doc/ or [home](https://blog.example.com/about)")
+	db.Model(&models.Article{}).Where("slug = ?", "web-only").UpdateColumn("content", "Also https://example.com/gone")
+
+	// Stand in for the linked sites: one page is gone, one server refuses HEAD
+	// and one cannot be reached
+	var requests []string
+	gone := map[string]bool{"/gone": true}
+	application.Services.LinkCheck.SetClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests = append(requests, req.Method+" "+req.URL.String())
+		status := http.StatusOK
+		switch {
+		case req.URL.Host == "down.example.com":
+			return nil, errors.New("connection refused")
+		case gone[req.URL.Path]:
+			status = http.StatusNotFound
+		case req.URL.Host == "go.dev" && req.Method == http.MethodHead:
+			status = http.StatusMethodNotAllowed
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+	})})
+
+	report, err := application.Services.LinkCheck.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Link check failed: %v", err)
+	}
+	if report.Articles != 2 || report.Links != 4 || report.Broken != 3 || report.NewlyBroken != 3 || report.Notified != 2 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	// Each link is requested once a run, internal links never
+	if len(requests) != 4 {
+		t.Errorf("Expected 4 requests, got %v", requests)
+	}
+
+	var notifications []models.Notification
+	db.Where("type = ?", models.NotificationBrokenLinks).Find(&notifications)
+	if len(notifications) != 2 || !strings.Contains(notifications[0].Body, "https://example.com/gone (404 Not Found)") ||
+		!strings.Contains(notifications[0].Body, "https://down.example.com/ (connection refused)") ||
+		notifications[0].Link != "https://blog.example.com/articles/go-web" {
+		t.Errorf("Unexpected notifications %+v", notifications)
+	}
+
+	listBroken := func() []repositories.BrokenLink {
+		t.Helper()
+		w := authRequest(t, application, admin, http.MethodGet, "/api/admin/link-checks/broken", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []repositories.BrokenLink `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	broken := listBroken()
+	if len(broken) != 3 || broken[0].BrokenSince == nil {
+		t.Fatalf("Expected 3 broken links, got %+v", broken)
+	}
+
+	// Links still broken keep the time they broke and notify nobody again, a
+	// fixed link is no longer reported
+	delete(gone, "/gone")
+	gone["/doc/"] = true
+	if _, err := application.Services.LinkCheck.Run(context.Background()); err != nil {
+		t.Fatalf("Link check failed: %v", err)
+	}
+	var count int64
+	db.Model(&models.Notification{}).Where("type = ?", models.NotificationBrokenLinks).Count(&count)
+	if count != 3 {
+		t.Errorf("Expected one more notification for the newly broken link, got %d", count)
+	}
+	var since time.Time
+	for _, link := range broken {
+		if link.URL == "https://down.example.com/" {
+			since = *link.BrokenSince
+		}
+	}
+	broken = listBroken()
+	if len(broken) != 2 || broken[0].URL != "https://down.example.com/" || !broken[0].BrokenSince.Equal(since) || broken[1].URL != "https://go.dev/doc/" {
+		t.Errorf("Unexpected broken links %+v", broken)
+	}
+
+	// Deleted articles drop out of the report
+	db.Delete(&article)
+	if broken := listBroken(); len(broken) != 0 {
+		t.Errorf("Expected no broken links of deleted articles, got %+v", broken)
+	}
+}
+
+func TestContentSuggestions(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var author models.User
+	if err := application.DB.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	other := &models.User{Username: "other", Email: "other@example.com"}
+	createUsers(t, application, other)
+	path := fmt.Sprintf("/api/articles/%d/suggestions", article.ID)
+
+	if w := authRequest(t, application, &author, http.MethodPost, path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 with suggestions disabled, got %d", w.Code)
+	}
+
+	// Stand in for an OpenAI compatible API
+	var request struct {
+		Model    string `json:"model"`
+		Messages []struct {
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	var authorization string
+	status := http.StatusOK
+	suggester := services.NewOpenAISuggester(services.OpenAIOptions{BaseURL: "http://llm.local/v1/", APIKey: "secret", Model: "test-model"})
+	suggester.SetClient(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() != "http://llm.local/v1/chat/completions" {
+			return nil, fmt.Errorf("unexpected request to %s", req.URL)
+		}
+		authorization = req.Header.Get("Authorization")
+		json.NewDecoder(req.Body).Decode(&request)
+		suggestions, _ := json.Marshal(map[string]interface{}{
+			"excerpt":          "A <b>short</b> tour of Go.",
+			"tags":             []string{"Go", "concurrency", " Concurrency ", ""},
+			"meta_description": strings.Repeat("word ", 50),
+		})
+		body, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": "```json\n" + string(suggestions) + "\n```"}}},
+		})
+		return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewReader(body)), Header: http.Header{}}, nil
+	})})
+	application.Services.Article.SetContentSuggester(suggester)
+
+	w := authRequest(t, application, &author, http.MethodPost, path, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data services.ContentSuggestions `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	got := response.Data
+	if got.Excerpt != "A short tour of Go." || !reflect.DeepEqual(got.Tags, []string{"concurrency"}) ||
+		len(got.MetaDescription) != 159 || !strings.HasSuffix(got.MetaDescription, " word") {
+		t.Errorf("Unexpected suggestions %+v", got)
+	}
+	if authorization != "Bearer secret" || request.Model != "test-model" || len(request.Messages) != 2 ||
+		!strings.Contains(request.Messages[1].Content, "Title: Go only") || !strings.Contains(request.Messages[1].Content, "Current tags: go") {
+		t.Errorf("Unexpected completion request %+v (authorization %q)", request, authorization)
+	}
+
+	if w := authRequest(t, application, other, http.MethodPost, path, ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for another user's article, got %d", w.Code)
+	}
+	status = http.StatusTooManyRequests
+	if w := authRequest(t, application, &author, http.MethodPost, path, ""); w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502 when the API fails, got %d", w.Code)
+	}
+}
+
+func TestInterlinkSuggestions(t *testing.T) {
+	application := setupTestApp(t)
+	goTag, _ := seedArticles(t, application)
+	db := application.DB
+
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var edited models.Article
+	if err := db.GetByField(&edited, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	raw := db.GetDB()
+	raw.Model(&models.Article{}).Where("slug = ?", "go-only").UpdateColumn("content", "Goroutines and channels make concurrency simple")
+	raw.Model(&models.Article{}).Where("slug = ?", "web-only").UpdateColumn("content", "Routing requests through middleware")
+	if err := db.Create(&models.TagAlias{Alias: "golang", TagID: goTag.ID}); err != nil {
+		t.Fatalf("Failed to create alias: %v", err)
+	}
+	now := time.Now()
+	for _, article := range []*models.Article{
+		{Title: "Channels explained", Slug: "channels-explained", Content: "Buffered channels", Status: models.StatusPublished, PublishedAt: &now},
+		{Title: "Concurrency draft", Slug: "concurrency-draft", Content: "Goroutines and channels", Status: models.StatusDraft},
+	} {
+		article.AuthorID = author.ID
+		if err := db.Create(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	suggest := func(body string, status int) []services.InterlinkSuggestion {
+		t.Helper()
+		w := authRequest(t, application, &author, http.MethodPost, "/api/search/interlinks", body)
+		if w.Code != status {
+			t.Fatalf("Expected status %d, got %d (%s)", status, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []services.InterlinkSuggestion `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+
+	// Sharing keywords and a tag, through its alias, beats sharing keywords
+	// only; drafts and the edited article itself are never suggested
+	got := suggest(fmt.Sprintf(`{"title": "Concurrency in practice", "content": "The goroutines and channels of [Go](https://go.dev)",
+		"tags": ["golang"], "exclude_article_id": %d}`, edited.ID), http.StatusOK)
+	want := []services.InterlinkSuggestion{{Title: "Go only", Slug: "go-only"}, {Title: "Channels explained", Slug: "channels-explained"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	// Tags alone suggest the articles carrying them
+	got = suggest(`{"tags": ["Go", "unknown"], "limit": 1}`, http.StatusOK)
+	if len(got) != 1 || (got[0].Slug != "go-web" && got[0].Slug != "go-only") {
+		t.Errorf("Expected one Go article, got %v", got)
+	}
+
+	suggest(`{"title": "The and of", "tags": ["unknown"]}`, http.StatusBadRequest)
+	suggest(`{"content": "Channels", "limit": 50}`, http.StatusBadRequest)
+	if w := tokenRequest(application, "", http.MethodPost, "/api/search/interlinks", `{"content": "Channels"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", w.Code)
+	}
+}
@@ -0,0 +1,223 @@
+package app_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-blog/internal/app"
+	"go-blog/internal/database"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+	"go-blog/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// envelope is the response body every API endpoint answers with
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Message string          `json:"message"`
+	Errors  []string        `json:"errors"`
+	Meta    *utils.Meta     `json:"meta"`
+}
+
+// client talks to the application over HTTP as an API client would
+type client struct {
+	t       *testing.T
+	baseURL string
+	token   string
+}
+
+// startServer serves the full application on a local port, against MySQL
+// when the tests run with TEST_DOCKER or TEST_DATABASE_URL set
+func startServer(t *testing.T) (*app.App, *client) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	t.Cleanup(func() { database.CleanupTestDB(db) })
+
+	cfg := &config.Config{
+		JWT:     config.JWTConfig{Secret: "e2e-secret", ExpireTime: 1},
+		Storage: config.StorageConfig{Driver: "local", LocalPath: t.TempDir(), BaseURL: "http://example.com/uploads"},
+	}
+	application := app.NewWithDB(cfg, db)
+
+	server := httptest.NewServer(application.Router)
+	t.Cleanup(server.Close)
+	return application, &client{t: t, baseURL: server.URL}
+}
+
+// do sends a request with body encoded as JSON, requires status and returns
+// the decoded envelope, whose data is decoded into data when not nil
+func (c *client) do(method, path string, body interface{}, status int, data interface{}) *envelope {
+	c.t.Helper()
+
+	var reader bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&reader).Encode(body); err != nil {
+			c.t.Fatalf("Failed to encode request body: %v", err)
+		}
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, &reader)
+	if err != nil {
+		c.t.Fatalf("Failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	var response envelope
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		c.t.Fatalf("%s %s answered with a body that is not an API response: %v", method, path, err)
+	}
+	if resp.StatusCode != status {
+		c.t.Fatalf("%s %s: expected status %d, got %d: %s", method, path, status, resp.StatusCode, response.Message)
+	}
+	if want := status < 400; response.Success != want {
+		c.t.Fatalf("%s %s: expected success %v with status %d", method, path, want, status)
+	}
+	if response.Message == "" {
+		c.t.Fatalf("%s %s: expected a message", method, path)
+	}
+	if data != nil {
+		if err := json.Unmarshal(response.Data, data); err != nil {
+			c.t.Fatalf("%s %s: failed to decode data: %v", method, path, err)
+		}
+	}
+	return &response
+}
+
+// as returns a client sending requests with token
+func (c *client) as(token string) *client {
+	return &client{t: c.t, baseURL: c.baseURL, token: token}
+}
+
+func TestEndToEndBlogFlow(t *testing.T) {
+	application, anonymous := startServer(t)
+
+	// Register, then log in again with the same credentials
+	var registered struct {
+		User struct {
+			ID       uint   `json:"id"`
+			Username string `json:"username"`
+		} `json:"user"`
+		Tokens utils.TokenPair `json:"tokens"`
+	}
+	anonymous.do("POST", "/api/auth/register", map[string]string{
+		"username": "writer", "email": "writer@example.com", "password": "password123",
+	}, http.StatusCreated, &registered)
+	if registered.User.ID == 0 || registered.User.Username != "writer" || registered.Tokens.AccessToken == "" {
+		t.Fatalf("Expected the registered user with tokens, got %+v", registered)
+	}
+
+	invalid := anonymous.do("POST", "/api/auth/register", map[string]string{
+		"username": "x", "email": "not-an-email", "password": "short",
+	}, http.StatusBadRequest, nil)
+	if len(invalid.Errors) == 0 {
+		t.Error("Expected validation errors for an invalid registration")
+	}
+	anonymous.do("POST", "/api/auth/login", map[string]string{
+		"email": "writer@example.com", "password": "wrong-password",
+	}, http.StatusUnauthorized, nil)
+
+	var login struct {
+		Tokens utils.TokenPair `json:"tokens"`
+	}
+	anonymous.do("POST", "/api/auth/login", map[string]string{
+		"email": "writer@example.com", "password": "password123",
+	}, http.StatusOK, &login)
+	writer := anonymous.as(login.Tokens.AccessToken)
+
+	var me struct {
+		ID uint `json:"id"`
+	}
+	writer.do("GET", "/api/auth/me", nil, http.StatusOK, &me)
+	if me.ID != registered.User.ID {
+		t.Fatalf("Expected to be signed in as user %d, got %d", registered.User.ID, me.ID)
+	}
+
+	// POST /api/articles is not implemented yet, so the article is written
+	// through the article service
+	article, err := application.Services.Article.Create(me.ID, &services.CreateArticleRequest{
+		Title:   "Testing Go services end to end",
+		Content: "Booting the whole application and talking to it over HTTP.",
+		Status:  "published",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	articlePath := fmt.Sprintf("/api/articles/%d", article.ID)
+
+	var fetched struct {
+		ID    uint   `json:"id"`
+		Title string `json:"title"`
+	}
+	anonymous.do("GET", "/api/articles/"+article.Slug, nil, http.StatusOK, &fetched)
+	if fetched.ID != article.ID || fetched.Title != article.Title {
+		t.Errorf("Expected the published article, got %+v", fetched)
+	}
+
+	// Comment
+	anonymous.do("POST", articlePath+"/comments", map[string]string{"content": "Anonymous"}, http.StatusUnauthorized, nil)
+	var comment struct {
+		ID      uint   `json:"id"`
+		Content string `json:"content"`
+	}
+	writer.do("POST", articlePath+"/comments", map[string]string{"content": "Great write-up"}, http.StatusCreated, &comment)
+	if comment.ID == 0 || comment.Content != "Great write-up" {
+		t.Errorf("Expected the created comment, got %+v", comment)
+	}
+	var comments []struct {
+		ID uint `json:"id"`
+	}
+	anonymous.do("GET", articlePath+"/comments", nil, http.StatusOK, &comments)
+	if len(comments) != 1 || comments[0].ID != comment.ID {
+		t.Errorf("Expected the comment to be listed, got %+v", comments)
+	}
+
+	// Like, then unlike
+	var like struct {
+		Liked     bool  `json:"liked"`
+		LikeCount int64 `json:"like_count"`
+	}
+	writer.do("POST", articlePath+"/like", nil, http.StatusOK, &like)
+	if !like.Liked || like.LikeCount != 1 {
+		t.Errorf("Expected the article to be liked once, got %+v", like)
+	}
+	writer.do("POST", articlePath+"/like", nil, http.StatusOK, &like)
+	if like.Liked || like.LikeCount != 0 {
+		t.Errorf("Expected the like to be taken back, got %+v", like)
+	}
+
+	// Search
+	var found struct {
+		Articles []struct {
+			ID uint `json:"id"`
+		} `json:"articles"`
+		Total int64 `json:"total"`
+	}
+	response := anonymous.do("GET", "/api/articles/search?q=services", nil, http.StatusOK, &found)
+	if found.Total != 1 || len(found.Articles) != 1 || found.Articles[0].ID != article.ID {
+		t.Errorf("Expected the article to be found, got %+v", found)
+	}
+	if response.Meta == nil || response.Meta.Pagination == nil || response.Meta.Pagination.Total != 1 {
+		t.Errorf("Expected search results to be paginated, got %+v", response.Meta)
+	}
+	anonymous.do("GET", "/api/articles/999999", nil, http.StatusNotFound, nil)
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+)
+
+func TestFollows(t *testing.T) {
+	application := setupTestApp(t)
+	goTag, webTag := seedArticles(t, application)
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	other := &models.User{Username: "other", Email: "other@example.com"}
+	createUsers(t, application, reader, other)
+	tutorials := &models.Category{Name: "Tutorials", Slug: "tutorials"}
+	if err := application.DB.Create(tutorials); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	createArticle(t, application, reader.ID, &services.CreateArticleRequest{Title: "Category post", Content: "Content", CategoryID: &tutorials.ID, Status: "published"})
+	createArticle(t, application, reader.ID, &services.CreateArticleRequest{Title: "Category draft", Content: "Content", CategoryID: &tutorials.ID})
+	categoryPath := fmt.Sprintf("/api/categories/%d/follow", tutorials.ID)
+
+	follow := func(user *models.User, method, path string, status int) services.FollowStatus {
+		t.Helper()
+		w := authRequest(t, application, user, method, path, "")
+		if w.Code != status {
+			t.Fatalf("%s %s: expected status %d, got %d (%s)", method, path, status, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data services.FollowStatus `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	feed := func(user *models.User) string {
+		t.Helper()
+		w := authRequest(t, application, user, http.MethodGet, "/api/feed", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []models.ArticleSummary `json:"data"`
+			Meta utils.Meta              `json:"meta"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		var titles []string
+		for _, article := range response.Data {
+			titles = append(titles, article.Title)
+		}
+		if response.Meta.Pagination == nil || response.Meta.Pagination.Total != int64(len(titles)) {
+			t.Errorf("Expected a total of %d in the feed pagination, got %+v", len(titles), response.Meta.Pagination)
+		}
+		return strings.Join(titles, ", ")
+	}
+
+	if w := tokenRequest(application, "", http.MethodPost, "/api/tags/web/follow", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", w.Code)
+	}
+	if titles := feed(reader); titles != "" {
+		t.Errorf("Expected an empty feed before following anything, got %q", titles)
+	}
+
+	// Categories are followed by ID, tags by slug
+	status := follow(reader, http.MethodPost, categoryPath, http.StatusOK)
+	if !status.Following || status.TargetID != tutorials.ID || status.FollowerCount != 1 {
+		t.Errorf("Unexpected category follow status %+v", status)
+	}
+	status = follow(reader, http.MethodPost, "/api/tags/web/follow", http.StatusOK)
+	if !status.Following || status.TargetID != webTag.ID || status.FollowerCount != 1 {
+		t.Errorf("Unexpected tag follow status %+v", status)
+	}
+	follow(reader, http.MethodPost, "/api/tags/web/follow", http.StatusConflict)
+	follow(reader, http.MethodPost, "/api/tags/rust/follow", http.StatusNotFound)
+	follow(reader, http.MethodPost, "/api/categories/999/follow", http.StatusNotFound)
+	follow(reader, http.MethodPost, "/api/categories/tutorials/follow", http.StatusNotFound)
+	if status := follow(other, http.MethodPost, "/api/tags/web/follow", http.StatusOK); status.FollowerCount != 2 {
+		t.Errorf("Expected a second follower, got %+v", status)
+	}
+
+	// Follower counts are kept on the followed tags and categories
+	var tag struct {
+		Data models.Tag `json:"data"`
+	}
+	json.Unmarshal(tokenRequest(application, "", http.MethodGet, "/api/tags/web", "").Body.Bytes(), &tag)
+	var category models.Category
+	if err := application.DB.GetByID(&category, tutorials.ID); err != nil {
+		t.Fatalf("Failed to load category: %v", err)
+	}
+	if tag.Data.FollowerCount != 2 || category.FollowerCount != 1 {
+		t.Errorf("Expected 2 tag and 1 category followers, got %d and %d", tag.Data.FollowerCount, category.FollowerCount)
+	}
+
+	w := authRequest(t, application, reader, http.MethodGet, "/api/users/me/follows", "")
+	var follows struct {
+		Data []models.Follow `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &follows)
+	if len(follows.Data) != 2 {
+		t.Errorf("Expected 2 follows, got %d (%s)", len(follows.Data), w.Body.String())
+	}
+
+	// The feed holds the published articles of the followed category and tags only
+	if titles := feed(reader); !sameTitles(titles, "Category post, Go web, Web only") {
+		t.Errorf("Unexpected feed %q", titles)
+	}
+	if titles := feed(other); !sameTitles(titles, "Go web, Web only") {
+		t.Errorf("Unexpected feed %q", titles)
+	}
+
+	status = follow(reader, http.MethodDelete, "/api/tags/web/follow", http.StatusOK)
+	if status.Following || status.FollowerCount != 1 {
+		t.Errorf("Unexpected unfollow status %+v", status)
+	}
+	follow(reader, http.MethodDelete, "/api/tags/web/follow", http.StatusBadRequest)
+	follow(reader, http.MethodPost, "/api/tags/"+goTag.Slug+"/follow", http.StatusOK)
+	if titles := feed(reader); !sameTitles(titles, "Category post, Go web, Go only") {
+		t.Errorf("Unexpected feed after switching tags %q", titles)
+	}
+	if status := follow(reader, http.MethodDelete, categoryPath, http.StatusOK); status.Following || status.FollowerCount != 0 {
+		t.Errorf("Unexpected category unfollow status %+v", status)
+	}
+}
+
+// sameTitles reports whether two comma separated title lists hold the same titles
+func sameTitles(got, want string) bool {
+	gotTitles, wantTitles := strings.Split(got, ", "), strings.Split(want, ", ")
+	if len(gotTitles) != len(wantTitles) {
+		return false
+	}
+	seen := make(map[string]int)
+	for _, title := range gotTitles {
+		seen[title]++
+	}
+	for _, title := range wantTitles {
+		if seen[title] == 0 {
+			return false
+		}
+		seen[title]--
+	}
+	return true
+}
@@ -0,0 +1,205 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+	"go-blog/pkg/config"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// setupTestApp boots the application on a test database; configure may
+// adjust the test configuration first
+func setupTestApp(t *testing.T, configure ...func(cfg *config.Config)) *App {
+	gin.SetMode(gin.TestMode)
+
+	// In-memory SQLite unless the tests run against MySQL
+	db, err := database.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	t.Cleanup(func() { database.CleanupTestDB(db) })
+
+	cfg := &config.Config{
+		JWT:     config.JWTConfig{Secret: "test-secret", ExpireTime: 1},
+		Storage: config.StorageConfig{Driver: "local", LocalPath: t.TempDir(), BaseURL: "http://example.com/uploads", CacheMaxAge: 3600},
+	}
+	for _, fn := range configure {
+		fn(cfg)
+	}
+
+	return NewWithDB(cfg, db)
+}
+
+// testPassword is the password of the users the helpers create
+const testPassword = "password123"
+
+// createUsers stores users, giving those without a password testPassword
+// hashed so that they can also sign in
+func createUsers(t *testing.T, application *App, users ...*models.User) {
+	t.Helper()
+	for _, user := range users {
+		if user.Password == "" {
+			// The lowest cost keeps the many users the tests create cheap
+			hash, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+			if err != nil {
+				t.Fatalf("Failed to hash password: %v", err)
+			}
+			user.Password = string(hash)
+		}
+		if err := application.DB.Create(user); err != nil {
+			t.Fatalf("Failed to create user %s: %v", user.Username, err)
+		}
+	}
+}
+
+// registerUser signs up through the API with testPassword and returns the
+// signed-in user and their tokens
+func registerUser(t *testing.T, application *App, username, email string) services.AuthResponse {
+	t.Helper()
+	body := fmt.Sprintf(`{"username": %q, "email": %q, "password": %q}`, username, email, testPassword)
+	return authResponse(t, tokenRequest(application, "", http.MethodPost, "/api/auth/register", body), http.StatusCreated)
+}
+
+// login signs in through the API and returns the user and their tokens
+func login(t *testing.T, application *App, email, password string) services.AuthResponse {
+	t.Helper()
+	body := fmt.Sprintf(`{"email": %q, "password": %q}`, email, password)
+	return authResponse(t, tokenRequest(application, "", http.MethodPost, "/api/auth/login", body), http.StatusOK)
+}
+
+// authResponse decodes the answer of a sign-up or sign-in expected to have status
+func authResponse(t *testing.T, w *httptest.ResponseRecorder, status int) services.AuthResponse {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("Expected status %d, got %d (%s)", status, w.Code, w.Body.String())
+	}
+	var response struct {
+		Data services.AuthResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return response.Data
+}
+
+// createArticle creates an article written by authorID through the article service
+func createArticle(t *testing.T, application *App, authorID uint, req *services.CreateArticleRequest) *models.Article {
+	t.Helper()
+	article, err := application.Services.Article.Create(authorID, req)
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	return article
+}
+
+// seedArticles creates published articles tagged go+web (100 views), go (10 views) and web (50 views)
+func seedArticles(t *testing.T, application *App) (goTag, webTag models.Tag) {
+	t.Helper()
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	goTag = models.Tag{Name: "go", Slug: "go"}
+	webTag = models.Tag{Name: "web", Slug: "web"}
+	for _, tag := range []*models.Tag{&goTag, &webTag} {
+		if err := application.DB.Create(tag); err != nil {
+			t.Fatalf("Failed to create tag: %v", err)
+		}
+	}
+
+	now := time.Now()
+	articles := []models.Article{
+		{Title: "Go web", Slug: "go-web", Tags: []models.Tag{goTag, webTag}, ViewCount: 100},
+		{Title: "Go only", Slug: "go-only", Tags: []models.Tag{goTag}, ViewCount: 10},
+		{Title: "Web only", Slug: "web-only", Tags: []models.Tag{webTag}, ViewCount: 50},
+	}
+	for i := range articles {
+		articles[i].Content = "Content"
+		articles[i].AuthorID = author.ID
+		articles[i].Status = models.StatusPublished
+		articles[i].PublishedAt = &now
+		if err := application.DB.Create(&articles[i]); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	return goTag, webTag
+}
+
+// ptr returns a pointer to value, for optional request fields
+func ptr[T any](value T) *T {
+	return &value
+}
+
+// recordingMailer captures sent email for assertions
+type recordingMailer struct {
+	sent   []string
+	bodies []string
+}
+
+func (m *recordingMailer) Send(to, subject, body string) error {
+	m.sent = append(m.sent, to+": "+subject)
+	m.bodies = append(m.bodies, body)
+	return nil
+}
+
+// authRequest serves a request authenticated as user and returns the recorder
+func authRequest(t *testing.T, application *App, user *models.User, method, path, body string) *httptest.ResponseRecorder {
+	token, err := utils.GenerateJWT(user.ID, user.Username, user.Email, application.Config.JWT.Secret)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	return w
+}
+
+// tokenRequest serves a JSON request with token as bearer credentials, if set
+func tokenRequest(application *App, token, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	return w
+}
+
+// roundTripFunc answers the HTTP requests of a client without a network
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// authRequestWithType sends a POST request with body of contentType on behalf of user
+func authRequestWithType(t *testing.T, application *App, user *models.User, path, contentType string, body io.Reader) *httptest.ResponseRecorder {
+	t.Helper()
+	token, err := utils.GenerateJWT(user.ID, user.Username, user.Email, application.Config.JWT.Secret)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, body)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	return w
+}
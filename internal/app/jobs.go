@@ -0,0 +1,220 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-blog/internal/scheduler"
+	"go-blog/pkg/config"
+)
+
+// day is the unit of the retention settings
+const day = 24 * time.Hour
+
+// newScheduler registers the recurring jobs, applying the per-job overrides of
+// the configuration. Jobs are registered even when disabled, so admins can
+// still run them by hand.
+func newScheduler(cfg *config.Config, repos *Repositories, svc *Services) *scheduler.Scheduler {
+	jobs := scheduler.New(repos.JobRun)
+
+	known := make(map[string]bool)
+	for _, job := range defaultJobs(cfg, repos, svc) {
+		known[job.Name] = true
+		if override, ok := cfg.Scheduler.Jobs[job.Name]; ok {
+			job = applyJobConfig(job, override)
+		}
+		if err := jobs.Add(job); err != nil {
+			slog.Warn("Not scheduling job", "error", err)
+		}
+	}
+	for name := range cfg.Scheduler.Jobs {
+		if !known[name] {
+			slog.Warn("Ignoring configuration of unknown job", "job", name)
+		}
+	}
+
+	return jobs
+}
+
+// defaultJobs returns the recurring jobs with their default schedules
+func defaultJobs(cfg *config.Config, repos *Repositories, svc *Services) []scheduler.Job {
+	trashRetention := time.Duration(cfg.Scheduler.TrashRetention) * day
+	runRetention := time.Duration(cfg.Scheduler.RunRetention) * day
+
+	return []scheduler.Job{
+		{
+			Name:     "orphan_tag_cleanup",
+			Schedule: everyOr(cfg.Tags.OrphanCleanupInterval, time.Hour, "@daily"),
+			Enabled:  cfg.Tags.OrphanCleanupInterval > 0,
+			Run: func(ctx context.Context) (string, error) {
+				report, err := svc.Tag.CleanupOrphanTags(!cfg.Tags.OrphanCleanupDelete)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d orphans, %d deleted, %d protected",
+					len(report.Orphans), report.Deleted, len(report.Protected)), nil
+			},
+		},
+		{
+			Name:     "saved_search_alerts",
+			Schedule: everyOr(cfg.Search.AlertInterval, time.Minute, "@hourly"),
+			Enabled:  cfg.Search.AlertInterval > 0,
+			Run: func(ctx context.Context) (string, error) {
+				report, err := svc.SavedSearch.CheckAlerts()
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d checked, %d notified, %d failed",
+					report.Checked, report.Notified, report.Failed), nil
+			},
+		},
+		{
+			Name:     "article_rankings",
+			Schedule: everyOr(cfg.Stats.RankingInterval, time.Minute, "@every 15m"),
+			Enabled:  cfg.Stats.RankingInterval > 0,
+			Run: func(ctx context.Context) (string, error) {
+				ranked, err := svc.Statistics.RefreshRankings()
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d articles ranked", ranked), nil
+			},
+		},
+		{
+			Name:     "link_check",
+			Schedule: everyOr(cfg.LinkCheck.Interval, time.Hour, "@daily"),
+			Jitter:   10 * time.Minute,
+			Enabled:  cfg.LinkCheck.Interval > 0,
+			Run: func(ctx context.Context) (string, error) {
+				report, err := svc.LinkCheck.Run(ctx)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d articles, %d links checked, %d broken, %d newly broken",
+					report.Articles, report.Links, report.Broken, report.NewlyBroken), nil
+			},
+		},
+		{
+			Name:     "stats_recount",
+			Schedule: "0 3 * * *",
+			Jitter:   10 * time.Minute,
+			Enabled:  true,
+			Run: func(ctx context.Context) (string, error) {
+				updated, err := svc.Statistics.RecountStatistics()
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d articles recounted", updated), nil
+			},
+		},
+		{
+			Name:     "comment_count_reconcile",
+			Schedule: "30 * * * *",
+			Jitter:   5 * time.Minute,
+			Enabled:  true,
+			Run: func(ctx context.Context) (string, error) {
+				corrected, err := svc.Statistics.ReconcileCommentCounts()
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d comment counts corrected", corrected), nil
+			},
+		},
+		{
+			Name:     "article_expiry",
+			Schedule: "*/5 * * * *",
+			Enabled:  true,
+			Run: func(ctx context.Context) (string, error) {
+				archived, err := svc.Article.ArchiveExpired()
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d expired articles archived", archived), nil
+			},
+		},
+		{
+			Name:     "author_monthly_report",
+			Schedule: "0 6 1 * *",
+			Jitter:   30 * time.Minute,
+			Enabled:  true,
+			Run: func(ctx context.Context) (string, error) {
+				run, err := svc.AuthorReport.SendMonthlyReports(time.Now())
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%s: %d sent, %d skipped, %d failed",
+					run.Month, run.Sent, run.Skipped, run.Failed), nil
+			},
+		},
+		{
+			Name:     "trash_purge",
+			Schedule: "0 4 * * *",
+			Jitter:   10 * time.Minute,
+			Enabled:  trashRetention > 0,
+			Run: func(ctx context.Context) (string, error) {
+				if trashRetention <= 0 {
+					return "trash retention is disabled", nil
+				}
+				purged, err := svc.Article.PurgeTrash(trashRetention)
+				if err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d deleted articles purged", purged), nil
+			},
+		},
+		{
+			Name:     "housekeeping",
+			Schedule: "30 4 * * *",
+			Jitter:   10 * time.Minute,
+			Enabled:  true,
+			Run: func(ctx context.Context) (string, error) {
+				now := time.Now()
+				tokens, err := repos.RefreshToken.DeleteExpired(now)
+				if err != nil {
+					return "", fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+				}
+				// Links requested within the hour count towards the request limit
+				links, err := repos.MagicLink.DeleteExpired(now.Add(-time.Hour))
+				if err != nil {
+					return "", fmt.Errorf("failed to delete expired sign-in links: %w", err)
+				}
+				var runs, queued int64
+				if runRetention > 0 {
+					if runs, err = repos.JobRun.DeleteBefore(now.Add(-runRetention)); err != nil {
+						return "", fmt.Errorf("failed to delete old job runs: %w", err)
+					}
+					if queued, err = repos.QueuedJob.DeleteFinishedBefore(now.Add(-runRetention)); err != nil {
+						return "", fmt.Errorf("failed to delete finished queued jobs: %w", err)
+					}
+				}
+				return fmt.Sprintf("%d expired refresh tokens, %d expired sign-in links, %d old job runs and %d finished queued jobs deleted",
+					tokens, links, runs, queued), nil
+			},
+		},
+	}
+}
+
+// applyJobConfig overrides the defaults of job with its configuration
+func applyJobConfig(job scheduler.Job, override config.JobConfig) scheduler.Job {
+	if override.Enabled != nil {
+		job.Enabled = *override.Enabled
+	}
+	if override.Schedule != "" {
+		job.Schedule = override.Schedule
+	}
+	if override.Jitter > 0 {
+		job.Jitter = time.Duration(override.Jitter) * time.Second
+	}
+	return job
+}
+
+// everyOr returns an "@every" schedule of interval units, or fallback when the
+// interval is not positive
+func everyOr(interval int, unit time.Duration, fallback string) string {
+	if interval <= 0 {
+		return fallback
+	}
+	return "@every " + (time.Duration(interval) * unit).String()
+}
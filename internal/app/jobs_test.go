@@ -0,0 +1,181 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestScheduledJobs(t *testing.T) {
+	disabled := false
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Scheduler.TrashRetention = 30
+		cfg.Scheduler.Jobs = map[string]config.JobConfig{"housekeeping": {Enabled: &disabled}}
+	})
+	seedArticles(t, application)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+	db := application.DB.GetDB()
+
+	// Counters that drifted from the stored likes and comments
+	db.Model(&models.Article{}).Where("slug = ?", "go-web").UpdateColumn("like_count", 7)
+	// One article deleted long ago, one deleted just now
+	db.Where("slug = ?", "go-only").Delete(&models.Article{})
+	db.Unscoped().Model(&models.Article{}).Where("slug = ?", "go-only").UpdateColumn("deleted_at", time.Now().AddDate(0, 0, -60))
+	db.Where("slug = ?", "web-only").Delete(&models.Article{})
+
+	if w := authRequest(t, application, reader, http.MethodGet, "/api/admin/jobs", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for non-admins, got %d", w.Code)
+	}
+
+	w := authRequest(t, application, admin, http.MethodGet, "/api/admin/jobs", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var listed struct {
+		Data []struct {
+			Name    string `json:"name"`
+			Enabled bool   `json:"enabled"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &listed)
+	enabled := make(map[string]bool)
+	for _, job := range listed.Data {
+		enabled[job.Name] = job.Enabled
+	}
+	if len(enabled) != 10 || !enabled["stats_recount"] || !enabled["comment_count_reconcile"] || !enabled["trash_purge"] || !enabled["article_expiry"] || enabled["housekeeping"] || enabled["article_rankings"] {
+		t.Errorf("Unexpected jobs %+v", listed.Data)
+	}
+
+	run := func(name string) models.JobRun {
+		w := authRequest(t, application, admin, http.MethodPost, "/api/admin/jobs/"+name+"/run", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.JobRun `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+
+	if recount := run("stats_recount"); recount.Status != models.JobRunSucceeded || recount.Trigger != models.JobRunManual {
+		t.Errorf("Expected a successful manual run, got %+v", recount)
+	}
+	var article models.Article
+	db.Where("slug = ?", "go-web").First(&article)
+	if article.LikeCount != 0 {
+		t.Errorf("Expected the like count to be recounted, got %d", article.LikeCount)
+	}
+
+	if purge := run("trash_purge"); purge.Status != models.JobRunSucceeded || purge.Summary != "1 deleted articles purged" {
+		t.Errorf("Unexpected purge run %+v", purge)
+	}
+	var remaining int64
+	db.Unscoped().Model(&models.Article{}).Where("slug IN ?", []string{"go-only", "web-only"}).Count(&remaining)
+	if remaining != 1 {
+		t.Errorf("Expected only the recently deleted article to be kept, got %d", remaining)
+	}
+
+	w = authRequest(t, application, admin, http.MethodPut, "/api/admin/jobs/trash_purge", `{"enabled": false}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"enabled":false`) {
+		t.Errorf("Expected the job to be disabled, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = authRequest(t, application, admin, http.MethodGet, "/api/admin/jobs/trash_purge/runs", "")
+	var runs struct {
+		Data []models.JobRun `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &runs)
+	if w.Code != http.StatusOK || len(runs.Data) != 1 || runs.Data[0].Job != "trash_purge" {
+		t.Errorf("Expected the recorded run, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if w := authRequest(t, application, admin, http.MethodPost, "/api/admin/jobs/unknown/run", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown jobs, got %d", w.Code)
+	}
+}
+
+// flakyMailer fails until it has failed failures times, then records like recordingMailer
+type flakyMailer struct {
+	recordingMailer
+	failures int
+}
+
+func (m *flakyMailer) Send(to, subject, body string) error {
+	if m.failures > 0 {
+		m.failures--
+		return fmt.Errorf("mail server unavailable")
+	}
+	return m.recordingMailer.Send(to, subject, body)
+}
+
+func TestJobQueue(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Queue.MaxAttempts = 2
+	})
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+
+	flaky := &flakyMailer{failures: 2}
+	mailer := services.NewQueuedMailer(application.Queue, flaky)
+	if err := mailer.Send("reader@example.com", "Welcome", "Secret confirmation link"); err != nil {
+		t.Fatalf("Failed to queue email: %v", err)
+	}
+	if len(flaky.sent) != 0 {
+		t.Fatalf("Expected sending to wait for a worker, got %v", flaky.sent)
+	}
+
+	// Both attempts fail, so the job ends up in the dead letters
+	if processed, err := application.Queue.ProcessDue(); processed != 2 || err != nil {
+		t.Fatalf("Expected two attempts, got %d (%v)", processed, err)
+	}
+	w := authRequest(t, application, admin, http.MethodGet, "/api/admin/queue", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"dead":1`) {
+		t.Fatalf("Expected one dead job, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = authRequest(t, application, admin, http.MethodGet, "/api/admin/queue/jobs?status=dead", "")
+	var dead struct {
+		Data []models.QueuedJob `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &dead)
+	if w.Code != http.StatusOK || len(dead.Data) != 1 || dead.Data[0].Type != services.EmailJob ||
+		dead.Data[0].Attempts != 2 || dead.Data[0].LastError != "mail server unavailable" {
+		t.Fatalf("Unexpected dead letters %d (%s)", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "Secret confirmation link") {
+		t.Errorf("Expected job payloads to stay hidden, got %s", w.Body.String())
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, "/api/admin/queue/jobs?status=lost", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown status, got %d", w.Code)
+	}
+
+	path := fmt.Sprintf("/api/admin/queue/jobs/%d", dead.Data[0].ID)
+	if w := authRequest(t, application, admin, http.MethodPost, path+"/retry", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if processed, err := application.Queue.ProcessDue(); processed != 1 || err != nil {
+		t.Fatalf("Expected the retried job to run, got %d (%v)", processed, err)
+	}
+	if len(flaky.sent) != 1 || flaky.sent[0] != "reader@example.com: Welcome" {
+		t.Errorf("Expected the email to be delivered, got %v", flaky.sent)
+	}
+	if w := authRequest(t, application, admin, http.MethodPost, path+"/retry", ""); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 retrying a job that succeeded, got %d", w.Code)
+	}
+
+	if w := authRequest(t, application, admin, http.MethodDelete, path, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, admin, http.MethodDelete, path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a deleted job, got %d", w.Code)
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+)
+
+func TestLikeToggle(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	article, err := application.Repositories.Article.GetBySlug("go-only")
+	if err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+
+	// Liking again after unliking works with the unique index in place
+	for i, expected := range []bool{true, false, true} {
+		liked, err := application.Services.Like.ToggleLike(reader.ID, article.ID)
+		if err != nil {
+			t.Fatalf("Toggle %d failed: %v", i+1, err)
+		}
+		if liked != expected {
+			t.Errorf("Expected toggle %d to leave liked %v, got %v", i+1, expected, liked)
+		}
+
+		_, likes, _, err := application.Repositories.Article.GetStatistics(article.ID)
+		wantLikes := uint(0)
+		if expected {
+			wantLikes = 1
+		}
+		if err != nil || likes != wantLikes {
+			t.Errorf("Expected like counter %d after toggle %d, got %d (%v)", wantLikes, i+1, likes, err)
+		}
+	}
+
+	if err := application.Repositories.Like.Create(&models.Like{UserID: reader.ID, ArticleID: article.ID}); !errors.Is(err, repositories.ErrDuplicate) {
+		t.Errorf("Expected a duplicate like to hit the unique index, got %v", err)
+	}
+}
+
+func TestLikedArticles(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	db := application.DB
+
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+	for _, slug := range []string{"go-web", "web-only", "go-only"} {
+		var article models.Article
+		if err := db.GetByField(&article, "slug", slug); err != nil {
+			t.Fatalf("Failed to load article: %v", err)
+		}
+		if w := authRequest(t, application, reader, "POST", fmt.Sprintf("/api/articles/%d/like", article.ID), ""); w.Code != http.StatusOK {
+			t.Fatalf("Failed to like %s: %d %s", slug, w.Code, w.Body.String())
+		}
+	}
+	// Likes of articles that are no longer published are not listed
+	if err := db.Exec("UPDATE articles SET status = ? WHERE slug = ?", models.StatusDraft, "web-only"); err != nil {
+		t.Fatalf("Failed to unpublish article: %v", err)
+	}
+
+	if w := tokenRequest(application, "", "GET", "/api/users/me/likes", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected anonymous requests to be refused, got %d", w.Code)
+	}
+	w := authRequest(t, application, reader, "GET", "/api/users/me/likes?limit=1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected liked articles, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data []models.ArticleSummary `json:"data"`
+		Meta utils.Meta              `json:"meta"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if len(response.Data) != 1 || response.Data[0].Slug != "go-only" || response.Meta.Pagination.Total != 2 {
+		t.Errorf("Expected the latest liked article of 2, got %+v (%+v)", response.Data, response.Meta.Pagination)
+	}
+
+	w = tokenRequest(application, "", "GET", "/api/authors/reader", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the author page, got %d: %s", w.Code, w.Body.String())
+	}
+	var page struct {
+		Data services.AuthorPage `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &page)
+	if page.Data.LikesGiven != 3 {
+		t.Errorf("Expected 3 likes given on the profile, got %d", page.Data.LikesGiven)
+	}
+}
+
+func TestArticleLikeState(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	db := application.DB
+
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+	var liked models.Article
+	if err := db.GetByField(&liked, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	if w := authRequest(t, application, reader, "POST", fmt.Sprintf("/api/articles/%d/like", liked.ID), ""); w.Code != http.StatusOK {
+		t.Fatalf("Failed to like the article: %d %s", w.Code, w.Body.String())
+	}
+
+	var list struct {
+		Data []map[string]any `json:"data"`
+	}
+	w := authRequest(t, application, reader, "GET", "/api/articles", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected articles, got %d: %s", w.Code, w.Body.String())
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Data) < 2 {
+		t.Fatalf("Expected several articles, got %d", len(list.Data))
+	}
+	for _, article := range list.Data {
+		if want := article["slug"] == "go-web"; article["is_liked"] != want {
+			t.Errorf("Expected is_liked %v on %v, got %v", want, article["slug"], article["is_liked"])
+		}
+	}
+
+	var single struct {
+		Data map[string]any `json:"data"`
+	}
+	w = authRequest(t, application, reader, "GET", "/api/articles/go-web", "")
+	json.Unmarshal(w.Body.Bytes(), &single)
+	if single.Data["is_liked"] != true {
+		t.Errorf("Expected the liked article to be marked, got %v", single.Data["is_liked"])
+	}
+
+	// Anonymous readers get no like state
+	w = tokenRequest(application, "", "GET", "/api/articles", "")
+	list.Data = nil
+	json.Unmarshal(w.Body.Bytes(), &list)
+	for _, article := range list.Data {
+		if _, ok := article["is_liked"]; ok {
+			t.Errorf("Expected no like state for anonymous readers, got %v", article)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+package app
+
+import (
+	"testing"
+
+	"go-blog/internal/testenv"
+)
+
+func TestMain(m *testing.M) {
+	testenv.Main(m)
+}
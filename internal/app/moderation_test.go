@@ -0,0 +1,223 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+)
+
+func TestPolicyAcceptance(t *testing.T) {
+	application := setupTestApp(t)
+	admin := &models.User{Username: "policyadmin", Email: "policyadmin@example.com", Role: models.RoleAdmin}
+	existing := &models.User{Username: "existing", Email: "existing@example.com"}
+	createUsers(t, application, admin, existing)
+
+	publish := func(body string, status int) models.Policy {
+		t.Helper()
+		w := authRequest(t, application, admin, http.MethodPost, "/api/admin/policies", body)
+		if w.Code != status {
+			t.Fatalf("Publishing %s: expected status %d, got %d (%s)", body, status, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.Policy `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	terms := publish(`{"kind": "terms", "version": "2026-01", "title": "Terms of Service", "content": "Be nice."}`, http.StatusCreated)
+	privacy := publish(`{"kind": "privacy", "version": "1", "title": "Privacy Policy", "content": "We keep little."}`, http.StatusCreated)
+	publish(`{"kind": "terms", "version": "2026-01", "title": "Terms of Service", "content": "Again."}`, http.StatusConflict)
+	publish(`{"kind": "cookies", "version": "1", "title": "Cookies", "content": "Some."}`, http.StatusBadRequest)
+
+	// Registering requires accepting the current policies
+	register := `{"username": "reader", "email": "reader@example.com", "password": "password123"%s}`
+	if w := tokenRequest(application, "", http.MethodPost, "/api/auth/register", fmt.Sprintf(register, "")); w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected registering without accepting to fail, got %d (%s)", w.Code, w.Body.String())
+	}
+	w := tokenRequest(application, "", http.MethodPost, "/api/auth/register", fmt.Sprintf(register, `, "accept_policies": true`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var registered struct {
+		Data services.AuthResponse `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &registered)
+	var acceptances []models.PolicyAcceptance
+	if err := application.DB.GetDB().Where("user_id = ?", registered.Data.User.ID).Find(&acceptances).Error; err != nil {
+		t.Fatalf("Failed to load acceptances: %v", err)
+	}
+	if len(acceptances) != 2 || acceptances[0].IP == "" || acceptances[0].AcceptedAt.IsZero() {
+		t.Errorf("Expected both policies accepted with the IP and time, got %+v", acceptances)
+	}
+
+	pendingUsers := func() []models.User {
+		t.Helper()
+		w := authRequest(t, application, admin, http.MethodGet, "/api/admin/policies/pending-users", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected pending users, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []models.User `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	if users := pendingUsers(); len(users) != 2 || users[0].ID != admin.ID || users[1].ID != existing.ID {
+		t.Errorf("Expected the accounts older than the policies pending, got %+v", users)
+	}
+
+	// Accepting only current versions, and only the ones named
+	if w := authRequest(t, application, existing, http.MethodPost, "/api/policies/accept", `{"policy_ids": [999]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected accepting an unknown policy to fail, got %d", w.Code)
+	}
+	w = authRequest(t, application, existing, http.MethodPost, "/api/policies/accept", fmt.Sprintf(`{"policy_ids": [%d]}`, terms.ID))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"kind":"privacy"`) || strings.Contains(w.Body.String(), `"kind":"terms"`) {
+		t.Errorf("Expected the privacy policy still pending, got %d (%s)", w.Code, w.Body.String())
+	}
+	authRequest(t, application, existing, http.MethodPost, "/api/policies/accept", fmt.Sprintf(`{"policy_ids": [%d]}`, privacy.ID))
+	if users := pendingUsers(); len(users) != 1 || users[0].ID != admin.ID {
+		t.Errorf("Expected only the admin pending, got %+v", users)
+	}
+
+	// A new version must be accepted again
+	updated := publish(`{"kind": "terms", "version": "2026-02", "title": "Terms of Service", "content": "Be kind."}`, http.StatusCreated)
+	if w := tokenRequest(application, "", http.MethodGet, "/api/policies", ""); !strings.Contains(w.Body.String(), `"version":"2026-02"`) || strings.Contains(w.Body.String(), `"version":"2026-01"`) {
+		t.Errorf("Expected the new terms to be current, got %s", w.Body.String())
+	}
+	if users := pendingUsers(); len(users) != 3 {
+		t.Errorf("Expected every user pending after a policy update, got %+v", users)
+	}
+	w = authRequest(t, application, existing, http.MethodGet, "/api/policies/pending", "")
+	if !strings.Contains(w.Body.String(), fmt.Sprintf(`"id":%d`, updated.ID)) || strings.Contains(w.Body.String(), `"kind":"privacy"`) {
+		t.Errorf("Expected only the new terms pending, got %s", w.Body.String())
+	}
+}
+
+func TestContentFilter(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	admin := &models.User{Username: "moderator", Email: "moderator@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+
+	rules := []struct {
+		body   string
+		status int
+	}{
+		{`{"pattern": "darn", "action": "mask"}`, http.StatusCreated},
+		{`{"pattern": "buy cheap", "action": "hold"}`, http.StatusCreated},
+		{`{"pattern": "casino\\d+", "regex": true, "action": "block", "scope": "comments"}`, http.StatusCreated},
+		{`{"pattern": "free money", "action": "hold", "scope": "titles"}`, http.StatusCreated},
+		{`{"pattern": "(unclosed", "regex": true, "action": "block"}`, http.StatusBadRequest},
+		{`{"pattern": "darn", "action": "ban"}`, http.StatusBadRequest},
+	}
+	for _, tt := range rules {
+		if w := authRequest(t, application, admin, http.MethodPost, "/api/admin/content-filters", tt.body); w.Code != tt.status {
+			t.Errorf("Creating %s: expected status %d, got %d (%s)", tt.body, tt.status, w.Code, w.Body.String())
+		}
+	}
+	if w := authRequest(t, application, reader, http.MethodGet, "/api/admin/content-filters", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, "/api/admin/content-filters", ""); strings.Count(w.Body.String(), `"pattern"`) != 4 {
+		t.Fatalf("Expected four rules, got %s", w.Body.String())
+	}
+
+	commentsPath := fmt.Sprintf("/api/articles/%d/comments", article.ID)
+	comment := func(content string, status int) models.Comment {
+		t.Helper()
+		w := authRequest(t, application, reader, http.MethodPost, commentsPath, fmt.Sprintf(`{"content": %q}`, content))
+		if w.Code != status {
+			t.Fatalf("Commenting %q: expected status %d, got %d (%s)", content, status, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.Comment `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	commentCount := func() uint {
+		t.Helper()
+		var current models.Article
+		if err := application.DB.GetByField(&current, "id", article.ID); err != nil {
+			t.Fatalf("Failed to load article: %v", err)
+		}
+		return current.CommentCount
+	}
+
+	comment("Visit casino777 now", http.StatusBadRequest)
+	if masked := comment("Darn good read, but not darning socks", http.StatusCreated); masked.Content != "**** good read, but not darning socks" {
+		t.Errorf("Expected whole words to be masked, got %q", masked.Content)
+	}
+	held := comment("You can BUY  cheap watches here", http.StatusCreated)
+	if !held.Hidden {
+		t.Errorf("Expected the comment to be held, got %+v", held)
+	}
+	if count := commentCount(); count != 1 {
+		t.Errorf("Expected only the shown comment to be counted, got %d", count)
+	}
+	if w := tokenRequest(application, "", http.MethodGet, commentsPath, ""); strings.Contains(w.Body.String(), "watches") {
+		t.Errorf("Expected the held comment to stay out of the thread, got %s", w.Body.String())
+	}
+
+	w := authRequest(t, application, admin, http.MethodGet, "/api/admin/comment-reports", "")
+	var queue struct {
+		Data []services.ReportQueueItem `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &queue); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(queue.Data) != 1 || queue.Data[0].Comment.ID != held.ID || queue.Data[0].Reports[0].Reason != models.ReportContentFilter {
+		t.Fatalf("Expected the held comment in the review queue, got %s", w.Body.String())
+	}
+	reviewPath := fmt.Sprintf("/api/admin/comments/%d/review", held.ID)
+	if w := authRequest(t, application, admin, http.MethodPost, reviewPath, `{"action": "dismiss"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if count := commentCount(); count != 2 {
+		t.Errorf("Expected the approved comment to be counted, got %d", count)
+	}
+
+	// Comment-only rules leave titles alone; titles held by a rule stay drafts
+	articles := application.Services.Article
+	created, err := articles.Create(author.ID, &services.CreateArticleRequest{Title: "Casino1 and free money", Content: "Text", Status: "published"})
+	if err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	if created.Status != models.StatusDraft || created.PublishedAt != nil {
+		t.Errorf("Expected a held title to keep the article a draft, got %+v", created)
+	}
+	updated, err := articles.Update(created.ID, author.ID, &services.UpdateArticleRequest{Status: "published"})
+	if err != nil || updated.Status != models.StatusDraft {
+		t.Errorf("Expected publishing a held title to be refused, got %+v (%v)", updated, err)
+	}
+	title := "A darn fine title"
+	updated, err = articles.Update(created.ID, author.ID, &services.UpdateArticleRequest{Title: &title, Status: "published"})
+	if err != nil || updated.Title != "A **** fine title" || updated.Status != models.StatusPublished {
+		t.Errorf("Expected a masked title to be published, got %+v (%v)", updated, err)
+	}
+
+	// Changing rules applies at once
+	if w := authRequest(t, application, admin, http.MethodDelete, "/api/admin/content-filters/1", ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if unmasked := comment("Darn", http.StatusCreated); unmasked.Content != "Darn" {
+		t.Errorf("Expected a deleted rule to stop masking, got %q", unmasked.Content)
+	}
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/admin/content-filters/99", `{"pattern": "x", "action": "mask"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing rule, got %d", w.Code)
+	}
+}
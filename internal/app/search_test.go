@@ -0,0 +1,386 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestSearchTypes(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	comment := &models.Comment{ArticleID: article.ID, UserID: article.AuthorID, Content: "Goroutines leak here"}
+	if err := application.DB.Create(comment); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		articles []string
+		users    []string
+	}{
+		{"articles by default", "q=goroutines", []string{}, []string{}},
+		{"comments return their article", "q=goroutines&type=comments", []string{"Go only"}, []string{}},
+		{"users", "q=author&type=users", []string{}, []string{"author"}},
+		{"merged", "q=web&type=articles,users", []string{"Web only", "Go web"}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+			}
+
+			var response struct {
+				Data struct {
+					Articles []models.ArticleSummary  `json:"articles"`
+					Users    []map[string]interface{} `json:"users"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if len(response.Data.Articles) != len(tt.articles) {
+				t.Fatalf("Expected %d articles, got %d", len(tt.articles), len(response.Data.Articles))
+			}
+			for i, title := range tt.articles {
+				if response.Data.Articles[i].Title != title {
+					t.Errorf("Expected article %d to be %q, got %q", i, title, response.Data.Articles[i].Title)
+				}
+			}
+			if len(response.Data.Users) != len(tt.users) {
+				t.Fatalf("Expected %d users, got %d", len(tt.users), len(response.Data.Users))
+			}
+			for i, username := range tt.users {
+				if response.Data.Users[i]["username"] != username {
+					t.Errorf("Expected user %d to be %q, got %v", i, username, response.Data.Users[i]["username"])
+				}
+				if _, ok := response.Data.Users[i]["email"]; ok {
+					t.Errorf("Expected user profile to omit email")
+				}
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?q=go&type=pages", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown type, got %d", w.Code)
+	}
+}
+
+func TestSearchStopWordsAndSynonyms(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Search.StopWords = []string{"only"}
+		cfg.Search.Synonyms = [][]string{{"www", "Web"}}
+	})
+	seedArticles(t, application)
+
+	tests := []struct {
+		query    string
+		mode     string
+		articles []string
+	}{
+		{"www", "", []string{"Go web", "Web only"}},    // synonyms are found
+		{"go only", "", []string{"Go only", "Go web"}}, // stop words are dropped
+		{"only", "", []string{"Go only", "Web only"}},  // unless nothing else is left
+		{"www", "boolean", []string{"Go web", "Web only"}},
+	}
+
+	for _, tt := range tests {
+		params := url.Values{"q": {tt.query}}
+		if tt.mode != "" {
+			params.Set("mode", tt.mode)
+		}
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?"+params.Encode(), nil))
+		var response struct {
+			Data struct {
+				Articles []models.ArticleSummary `json:"articles"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v (%s)", err, w.Body.String())
+		}
+		titles := make([]string, len(response.Data.Articles))
+		for i, article := range response.Data.Articles {
+			titles[i] = article.Title
+		}
+		sort.Strings(titles)
+		if !reflect.DeepEqual(titles, tt.articles) {
+			t.Errorf("Search %q: expected %v, got %v", tt.query, tt.articles, titles)
+		}
+	}
+}
+
+func TestSearchSpellingSuggestions(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	tests := []struct {
+		query       string
+		suggestions []string
+	}{
+		{"wbe onyl", []string{"web only"}}, // swapped letters of title words
+		{"gol", []string{"go"}},            // one letter off a title word or tag
+		{"kubernetes", []string{}},         // nothing close in the vocabulary
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?q="+url.QueryEscape(tt.query), nil))
+		var response struct {
+			Data struct {
+				Total       int64    `json:"total"`
+				Suggestions []string `json:"suggestions"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v (%s)", err, w.Body.String())
+		}
+		if response.Data.Total != 0 || len(response.Data.Suggestions) != len(tt.suggestions) {
+			t.Errorf("Search %q: expected suggestions %v, got %v", tt.query, tt.suggestions, response.Data.Suggestions)
+			continue
+		}
+		for i, suggestion := range tt.suggestions {
+			if response.Data.Suggestions[i] != suggestion {
+				t.Errorf("Search %q: expected suggestions %v, got %v", tt.query, tt.suggestions, response.Data.Suggestions)
+			}
+		}
+	}
+}
+
+func TestInstantSearch(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search/instant?q=on", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "public, max-age=30" {
+		t.Errorf("Expected instant results to be cacheable, got %q", cc)
+	}
+	var response struct {
+		Data services.InstantResults `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	// Any title word may start with the prefix; more viewed articles come first
+	if len(response.Data.Articles) != 2 || response.Data.Articles[0].Title != "Web only" || response.Data.Articles[1].Title != "Go only" {
+		t.Errorf("Unexpected articles %+v", response.Data.Articles)
+	}
+
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search/instant?q=G&limit=1", nil))
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data.Articles) != 1 || response.Data.Articles[0].Title != "Go web" ||
+		len(response.Data.Tags) != 1 || response.Data.Tags[0].Name != "go" || response.Data.Tags[0].ArticleCount != 2 {
+		t.Errorf("Unexpected results %+v", response.Data)
+	}
+
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search/instant?q=+", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an empty query, got %d", w.Code)
+	}
+}
+
+func TestSearchFailsFastWhenDatabaseIsUnavailable(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	// Every statement times out, so the first search opens the breaker
+	err := application.DB.UseResilience(database.ResilienceOptions{
+		StatementTimeout: time.Nanosecond,
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("Failed to enable resilience: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?q=go", nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected search %d to return 503, got %d (%s)", i+1, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestSavedSearchAlerts(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	mailer := &recordingMailer{}
+	application.Services.Notification.SetMailer(mailer)
+
+	var user models.User
+	if err := application.DB.GetByField(&user, "username", "author"); err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+
+	w := authRequest(t, application, &user, http.MethodPost, "/api/users/me/saved-searches",
+		`{"name": "Generics", "query": "generics", "alert": true, "email_alert": true}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = authRequest(t, application, &user, http.MethodPost, "/api/users/me/saved-searches",
+		`{"name": "Bad", "query": "generics", "email_alert": true}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for email alerts without alerts, got %d", w.Code)
+	}
+
+	report, err := application.Services.SavedSearch.CheckAlerts()
+	if err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if report.Checked != 1 || report.Notified != 0 {
+		t.Errorf("Expected no alert before a matching article is published, got %+v", report)
+	}
+
+	now := time.Now()
+	article := &models.Article{
+		Title: "Generics in practice", Slug: "generics-in-practice", Content: "Type parameters",
+		AuthorID: user.ID, Status: models.StatusPublished, PublishedAt: &now,
+	}
+	if err := application.DB.Create(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	if report, err = application.Services.SavedSearch.CheckAlerts(); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if report.Notified != 1 || report.Failed != 0 {
+		t.Errorf("Expected one alert, got %+v", report)
+	}
+	if len(mailer.sent) != 1 || !strings.HasPrefix(mailer.sent[0], "author@example.com: ") {
+		t.Errorf("Expected one email to the author, got %v", mailer.sent)
+	}
+
+	// The checkpoint advanced, so the same article is not reported twice
+	if report, err = application.Services.SavedSearch.CheckAlerts(); err != nil {
+		t.Fatalf("CheckAlerts failed: %v", err)
+	}
+	if report.Notified != 0 {
+		t.Errorf("Expected no repeated alert, got %+v", report)
+	}
+
+	w = authRequest(t, application, &user, http.MethodGet, "/api/notifications?unread=true", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data struct {
+			Notifications []models.Notification `json:"notifications"`
+			Unread        int64                 `json:"unread"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Unread != 1 || len(response.Data.Notifications) != 1 {
+		t.Fatalf("Expected one unread notification, got %+v", response.Data)
+	}
+	if !strings.Contains(response.Data.Notifications[0].Body, "Generics in practice") {
+		t.Errorf("Expected notification to list the article, got %q", response.Data.Notifications[0].Body)
+	}
+
+	w = authRequest(t, application, &user, http.MethodPost, fmt.Sprintf("/api/notifications/%d/read", response.Data.Notifications[0].ID), "")
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	count, err := application.Repositories.Notification.CountUnread(user.ID)
+	if err != nil || count != 0 {
+		t.Errorf("Expected no unread notifications, got %d (%v)", count, err)
+	}
+}
+
+func TestScopedSearch(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	db := application.DB
+
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	other := &models.User{Username: "other", Email: "other@example.com"}
+	createUsers(t, application, other)
+	category := &models.Category{Name: "Backend", Slug: "backend"}
+	if err := db.Create(category); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	now := time.Now()
+	foreign := &models.Article{Title: "Go elsewhere", Slug: "go-elsewhere", Content: "Content", AuthorID: other.ID,
+		CategoryID: &category.ID, Status: models.StatusPublished, PublishedAt: &now}
+	if err := db.Create(foreign); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	search := func(path string) []string {
+		t.Helper()
+		w := tokenRequest(application, "", "GET", path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d (%s)", path, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data services.SearchResponse `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		titles := []string{}
+		for _, article := range response.Data.Articles {
+			titles = append(titles, article.Title)
+		}
+		sort.Strings(titles)
+		return titles
+	}
+
+	if got := search(fmt.Sprintf("/api/users/%d/articles/search?q=go", author.ID)); !reflect.DeepEqual(got, []string{"Go only", "Go web"}) {
+		t.Errorf("Expected the author's Go articles, got %v", got)
+	}
+	// The path scope wins over a conflicting filter
+	if got := search(fmt.Sprintf("/api/users/%d/articles/search?q=go&author_id=%d", other.ID, author.ID)); !reflect.DeepEqual(got, []string{"Go elsewhere"}) {
+		t.Errorf("Expected the other user's article, got %v", got)
+	}
+	if got := search("/api/categories/backend/articles/search?q=go&type=articles,users"); !reflect.DeepEqual(got, []string{"Go elsewhere"}) {
+		t.Errorf("Expected the category's article, got %v", got)
+	}
+
+	for path, code := range map[string]int{
+		"/api/users/999/articles/search?q=go":              http.StatusNotFound,
+		"/api/users/abc/articles/search?q=go":              http.StatusBadRequest,
+		"/api/categories/missing/articles/search?q=go":     http.StatusNotFound,
+		"/api/categories/backend/articles/search?tag_id=x": http.StatusBadRequest,
+	} {
+		if w := tokenRequest(application, "", "GET", path, ""); w.Code != code {
+			t.Errorf("Expected %d for %s, got %d", code, path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-blog/internal/middleware"
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+	"go-blog/pkg/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Security = config.SecurityConfig{
+			ContentSecurityPolicy: "default-src 'none'",
+			FrameOptions:          "DENY",
+			ReferrerPolicy:        "no-referrer",
+			HSTSMaxAge:            60,
+		}
+	})
+
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags", nil))
+	want := map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"Content-Security-Policy":   "default-src 'none'",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "no-referrer",
+		"Strict-Transport-Security": "",
+	}
+	for header, value := range want {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("Expected %s %q, got %q", header, value, got)
+		}
+	}
+
+	// HSTS is only sent over HTTPS
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/tags", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	application.Router.ServeHTTP(w, req)
+	if got := w.Header().Get("Strict-Transport-Security"); got != "max-age=60; includeSubDomains" {
+		t.Errorf("Expected HSTS over HTTPS, got %q", got)
+	}
+}
+
+func TestCSRF(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Security.CSRF = true
+	})
+	body := `{"username": "reader", "email": "reader@example.com", "password": "password123"}`
+
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags", nil))
+	var cookie *http.Cookie
+	for _, c := range w.Result().Cookies() {
+		if c.Name == "csrf_token" {
+			cookie = c
+		}
+	}
+	if cookie == nil || cookie.Value == "" || cookie.HttpOnly {
+		t.Fatalf("Expected a script-readable CSRF cookie, got %v", w.Result().Cookies())
+	}
+
+	post := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/register", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(cookie)
+		if token != "" {
+			req.Header.Set("X-CSRF-Token", token)
+		}
+		application.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post(""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 without a token, got %d", w.Code)
+	}
+	if w := post("wrong"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a wrong token, got %d", w.Code)
+	}
+	if w := post(cookie.Value); w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201 with the token, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Bearer-authenticated requests cannot be forged cross-site and need no token
+	user := &models.User{Username: "writer", Email: "writer@example.com"}
+	createUsers(t, application, user)
+	if w := authRequest(t, application, user, http.MethodPut, "/api/users/me/settings", `{"editor_mode": "rich_text"}`); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a bearer request, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestCSRFFormBodiesAreLimited(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Security.CSRF = true
+		cfg.Limits = config.LimitsConfig{MaxBodySize: 256}
+	})
+
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tags", nil))
+	cookies := w.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatalf("Expected a CSRF cookie")
+	}
+
+	post := func(body io.Reader) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/register", body)
+		req.ContentLength = -1 // streamed, so only reading tells the size
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(cookies[0])
+		application.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	// The token is looked up in the form only within the body limit
+	body := &countingReader{r: strings.NewReader("csrf_token=" + cookies[0].Value + "&bio=" + strings.Repeat("a", 1<<20))}
+	if w := post(body); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for an oversized form, got %d (%s)", w.Code, w.Body.String())
+	}
+	if body.n > 257 {
+		t.Errorf("Expected at most 257 bytes of the form to be read, read %d", body.n)
+	}
+
+	// A form within the limit carries the token
+	form := "csrf_token=" + cookies[0].Value + "&username=reader&email=reader%40example.com&password=password123"
+	if w := post(strings.NewReader(form)); w.Code == http.StatusForbidden {
+		t.Errorf("Expected the form token to be accepted, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestCORS(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.CORS = config.CORSConfig{
+			AllowedOrigins:   []string{"https://blog.example.com", "https://*.example.org"},
+			ExposedHeaders:   []string{"X-Request-ID"},
+			AllowCredentials: true,
+			MaxAge:           600,
+		}
+		cfg.Cache = config.CacheConfig{Enabled: true, MaxEntries: 100}
+	})
+
+	request := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/tags", nil)
+		req.Header.Set("Origin", origin)
+		if preflight {
+			req.Header.Set("Access-Control-Request-Method", "POST")
+		}
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request("OPTIONS", "https://blog.example.com", true)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://blog.example.com" ||
+		w.Header().Get("Access-Control-Allow-Credentials") != "true" || w.Header().Get("Access-Control-Max-Age") != "600" ||
+		!strings.Contains(w.Header().Get("Access-Control-Allow-Methods"), "DELETE") {
+		t.Errorf("Expected an allowed preflight, got %d %v", w.Code, w.Header())
+	}
+
+	w = request("GET", "https://news.example.org", false)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://news.example.org" ||
+		w.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+		t.Errorf("Expected a subdomain to be allowed, got %d %v", w.Code, w.Header())
+	}
+	// Cached responses carry the CORS headers of the request they answer
+	w = request("GET", "https://blog.example.com", false)
+	if w.Header().Get(middleware.CacheHeader) != "HIT" || w.Header().Get("Access-Control-Allow-Origin") != "https://blog.example.com" {
+		t.Errorf("Expected a cache hit for the other origin, got %v", w.Header())
+	}
+
+	for _, origin := range []string{"https://evil.example.com", "https://example.org.evil.com", "http://blog.example.com"} {
+		if w := request("GET", origin, false); w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("Expected %s to be refused, got %v", origin, w.Header())
+		}
+	}
+}
+
+func TestRequestLimits(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Limits = config.LimitsConfig{
+			MaxBodySize:   256,
+			MaxUploadSize: 4096,
+			UploadTypes:   []string{"image/png"},
+			Routes:        map[string]int64{"/auth/login": 64},
+		}
+	})
+	seedArticles(t, application)
+	var user models.User
+	if err := application.DB.GetByField(&user, "username", "author"); err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+
+	profile := fmt.Sprintf(`{"bio":%q}`, strings.Repeat("a", 300))
+	w := authRequest(t, application, &user, "PUT", fmt.Sprintf("/api/users/%d", user.ID), profile)
+	if w.Code != http.StatusRequestEntityTooLarge || !strings.Contains(w.Body.String(), "256 bytes") {
+		t.Errorf("Expected an oversized profile to be refused with its limit, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, &user, "PUT", fmt.Sprintf("/api/users/%d", user.ID), `{"bio":"Short"}`); w.Code != http.StatusOK {
+		t.Errorf("Expected a small profile update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	// Route overrides replace the default limit
+	login := `{"email":"author@example.com","password":"password123","remember":false}`
+	if w := tokenRequest(application, "", "POST", "/api/auth/login", login); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected the login limit to apply, got %d: %s", w.Code, w.Body.String())
+	}
+
+	upload := func(content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, _ := form.CreateFormFile("avatar", "avatar.png")
+		part.Write(content)
+		form.Close()
+		return authRequestWithType(t, application, &user, "/api/users/me/avatar", form.FormDataContentType(), &body)
+	}
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 64, 64))); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+
+	if w := upload(img.Bytes()); w.Code != http.StatusOK {
+		t.Errorf("Expected an image over the default limit to be uploaded, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := upload([]byte("GIF89a not really")); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected a file of another type to be refused, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := upload(append(img.Bytes(), make([]byte, 5000)...)); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected an upload over its limit to be refused, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := authRequestWithType(t, application, &user, "/api/users/me/avatar", "application/json", strings.NewReader("{}")); w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("Expected uploads to require multipart form data, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWriteQuotas(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Quotas = config.QuotasConfig{
+			Enabled: true,
+			Roles:   map[string]config.QuotaConfig{"user": {ArticlesPerDay: 1, CommentsPerHour: 2}},
+			IP:      config.QuotaConfig{CommentsPerHour: 3},
+		}
+	})
+	seedArticles(t, application)
+	db := application.DB
+
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := db.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	// Seeded articles were created today
+	_, err := application.Services.Article.Create(author.ID, &services.CreateArticleRequest{Title: "More", Content: "Content"})
+	if !errors.Is(err, services.ErrQuota) {
+		t.Errorf("Expected the daily article quota to be used up, got %v", err)
+	}
+
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	other := &models.User{Username: "other", Email: "other@example.com"}
+	createUsers(t, application, reader, other)
+
+	comment := func(user *models.User) *httptest.ResponseRecorder {
+		return authRequest(t, application, user, "POST", fmt.Sprintf("/api/articles/%d/comments", article.ID), `{"content":"Hello"}`)
+	}
+	for i := 0; i < 2; i++ {
+		if w := comment(reader); w.Code != http.StatusCreated {
+			t.Fatalf("Expected comment %d to be created, got %d: %s", i+1, w.Code, w.Body.String())
+		}
+	}
+	w := comment(reader)
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "2 comments per hour") {
+		t.Errorf("Expected the user's comment quota to be used up, got %d: %s", w.Code, w.Body.String())
+	}
+	if retry, _ := strconv.Atoi(w.Header().Get("Retry-After")); retry < 3500 || retry > 3600 {
+		t.Errorf("Expected to retry in about an hour, got %q", w.Header().Get("Retry-After"))
+	}
+
+	// The client IP is shared by every test request
+	if w := comment(other); w.Code != http.StatusCreated {
+		t.Fatalf("Expected another user to comment, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := comment(other); w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "your network") {
+		t.Errorf("Expected the IP comment quota to be used up, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// No proxy is trusted, so a forged X-Forwarded-For does not start a new count
+	spoofer := &models.User{Username: "spoofer", Email: "spoofer@example.com"}
+	createUsers(t, application, spoofer)
+	token, err := utils.GenerateJWT(spoofer.ID, spoofer.Username, spoofer.Email, application.Config.JWT.Secret)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/articles/%d/comments", article.ID), strings.NewReader(`{"content":"Hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "your network") {
+		t.Errorf("Expected a forged X-Forwarded-For to be ignored, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		proxies []string
+		ip      string
+	}{
+		{"none trusted", nil, "192.0.2.1"},
+		{"other proxy", []string{"10.0.0.0/8"}, "192.0.2.1"},
+		{"trusted proxy", []string{"192.0.2.0/24"}, "203.0.113.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			application := setupTestApp(t, func(cfg *config.Config) {
+				cfg.Server.TrustedProxies = tt.proxies
+			})
+			application.Router.GET("/test/client-ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			// httptest requests come from 192.0.2.1
+			req := httptest.NewRequest(http.MethodGet, "/test/client-ip", nil)
+			req.Header.Set("X-Forwarded-For", "203.0.113.7")
+			w := httptest.NewRecorder()
+			application.Router.ServeHTTP(w, req)
+			if w.Body.String() != tt.ip {
+				t.Errorf("Expected client IP %s, got %q", tt.ip, w.Body.String())
+			}
+		})
+	}
+}
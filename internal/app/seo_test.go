@@ -0,0 +1,221 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestSearchEngineNotifications(t *testing.T) {
+	var mu sync.Mutex
+	var pings []string
+	var submissions []map[string]interface{}
+	engine := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/ping":
+			pings = append(pings, r.URL.Query().Get("sitemap"))
+		case "/indexnow":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			submissions = append(submissions, body)
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer engine.Close()
+
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Server.PublicURL = "https://blog.example.com"
+		cfg.SearchEngines.Enabled = true
+		cfg.SearchEngines.SitemapURL = "https://blog.example.com/sitemap.xml"
+		cfg.SearchEngines.PingURLs = []string{engine.URL + "/ping"}
+		cfg.SearchEngines.IndexNowKey = "0123456789abcdef"
+		cfg.SearchEngines.IndexNowEndpoint = engine.URL + "/indexnow"
+	})
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	draft := createArticle(t, application, author.ID, &services.CreateArticleRequest{Title: "Draft news", Content: "Soon"})
+	if processed, _ := application.Queue.ProcessDue(); processed != 0 || len(pings) != 0 || len(submissions) != 0 {
+		t.Fatalf("Expected no notifications for a draft, got %v %v", pings, submissions)
+	}
+
+	if _, err := application.Services.Article.Publish(draft.ID, author.ID); err != nil {
+		t.Fatalf("Failed to publish article: %v", err)
+	}
+	// Notifications are sent by the job queue workers
+	if processed, err := application.Queue.ProcessDue(); processed != 1 || err != nil {
+		t.Fatalf("Expected one queued notification, got %d (%v)", processed, err)
+	}
+	if len(pings) != 1 || pings[0] != "https://blog.example.com/sitemap.xml" {
+		t.Errorf("Expected one sitemap ping, got %v", pings)
+	}
+	if len(submissions) != 1 {
+		t.Fatalf("Expected one IndexNow submission, got %v", submissions)
+	}
+	submission := submissions[0]
+	urls, _ := submission["urlList"].([]interface{})
+	if submission["host"] != "blog.example.com" || submission["key"] != "0123456789abcdef" ||
+		submission["keyLocation"] != "https://blog.example.com/0123456789abcdef.txt" ||
+		len(urls) != 1 || urls[0] != "https://blog.example.com/articles/draft-news" {
+		t.Errorf("Unexpected IndexNow submission %v", submission)
+	}
+
+	// Search engines verify submissions by fetching the key file
+	if w := tokenRequest(application, "", http.MethodGet, "/0123456789abcdef.txt", ""); w.Code != http.StatusOK || w.Body.String() != "0123456789abcdef" {
+		t.Errorf("Expected the key file to be served, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestOGImages(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.OGImages = config.OGImagesConfig{Enabled: true, SiteName: "Test Blog", Background: "#1f2937", Foreground: "#f9fafb", Accent: "#38bdf8"}
+	})
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	draft := createArticle(t, application, author.ID, &services.CreateArticleRequest{Title: "Share me", Content: "Soon"})
+	if processed, _ := application.Queue.ProcessDue(); processed != 0 {
+		t.Fatalf("Expected no image for a draft, got %d jobs", processed)
+	}
+
+	ogImage := func() string {
+		t.Helper()
+		// Articles are served by slug, which follows the title
+		var article models.Article
+		if err := application.DB.GetByID(&article, draft.ID); err != nil {
+			t.Fatalf("Failed to load article: %v", err)
+		}
+		var response struct {
+			Data models.Article `json:"data"`
+		}
+		w := tokenRequest(application, "", http.MethodGet, "/api/articles/"+article.Slug, "")
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode article: %v (%s)", err, w.Body.String())
+		}
+		return response.Data.OGImageURL
+	}
+
+	if _, err := application.Services.Article.Publish(draft.ID, author.ID); err != nil {
+		t.Fatalf("Failed to publish article: %v", err)
+	}
+	// Images are rendered by the job queue workers
+	if processed, err := application.Queue.ProcessDue(); processed != 1 || err != nil {
+		t.Fatalf("Expected one queued render, got %d (%v)", processed, err)
+	}
+	first := ogImage()
+	if !strings.HasPrefix(first, "http://example.com/uploads/og/") || !strings.HasSuffix(first, ".png") {
+		t.Fatalf("Expected the share image URL, got %q", first)
+	}
+	firstFile := filepath.Join(application.Config.Storage.LocalPath, filepath.FromSlash(strings.TrimPrefix(first, "http://example.com/uploads/")))
+	data, err := os.ReadFile(firstFile)
+	if err != nil {
+		t.Fatalf("Expected the image to be stored: %v", err)
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width != services.OGImageWidth || cfg.Height != services.OGImageHeight {
+		t.Errorf("Expected a %dx%d PNG, got %dx%d (%v)", services.OGImageWidth, services.OGImageHeight, cfg.Width, cfg.Height, err)
+	}
+
+	// Unchanged articles keep their image
+	if err := application.Services.OGImage.Render(draft.ID); err != nil || ogImage() != first {
+		t.Errorf("Expected the image to be kept, got %q (%v)", ogImage(), err)
+	}
+
+	// A new title renders a new image and deletes the old one
+	title := "Share me again, with a much longer title that needs to wrap over several lines of the image"
+	if _, err := application.Services.Article.Update(draft.ID, author.ID, &services.UpdateArticleRequest{Title: &title}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if processed, err := application.Queue.ProcessDue(); processed != 1 || err != nil {
+		t.Fatalf("Expected one queued render, got %d (%v)", processed, err)
+	}
+	if second := ogImage(); second == first || second == "" {
+		t.Errorf("Expected a new image after the title changed, got %q", second)
+	}
+	if _, err := os.Stat(firstFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the previous image to be deleted, got %v", err)
+	}
+}
+
+func TestShortLinks(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Server.PublicURL = "https://blog.example.com/"
+	})
+	seedArticles(t, application)
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	shortLink := func() models.ShortLink {
+		t.Helper()
+		w := tokenRequest(application, "", "GET", fmt.Sprintf("/api/articles/%d/shortlink", article.ID), "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected a short link, got %d: %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data models.ShortLink `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp.Data
+	}
+	link := shortLink()
+	if len(link.Code) != 7 || link.URL != "https://blog.example.com/s/"+link.Code {
+		t.Fatalf("Expected a 7 character code under the public URL, got %+v", link)
+	}
+	if again := shortLink(); again.Code != link.Code {
+		t.Errorf("Expected the article to keep its code, got %s and %s", link.Code, again.Code)
+	}
+
+	for _, referrer := range []string{"https://News.example.com/item?id=1", "https://news.example.com/other", ""} {
+		req := httptest.NewRequest("GET", "/s/"+link.Code, nil)
+		if referrer != "" {
+			req.Header.Set("Referer", referrer)
+		}
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		if w.Code != http.StatusFound || w.Header().Get("Location") != "https://blog.example.com/articles/go-only" {
+			t.Fatalf("Expected a redirect to the article, got %d to %q", w.Code, w.Header().Get("Location"))
+		}
+	}
+	if w := tokenRequest(application, "", "GET", "/s/unknown", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected unknown codes to be not found, got %d", w.Code)
+	}
+
+	stats, err := application.Services.Statistics.GetShortLinkStats(article.ID, 10)
+	if err != nil {
+		t.Fatalf("Failed to get short link statistics: %v", err)
+	}
+	want := []models.ReferrerCount{{Referrer: "news.example.com", Clicks: 2}, {Referrer: "", Clicks: 1}}
+	if stats.Clicks != 3 || !reflect.DeepEqual(stats.Referrers, want) {
+		t.Errorf("Expected 3 clicks from %v, got %d from %v", want, stats.Clicks, stats.Referrers)
+	}
+	if articleStats, err := application.Services.Statistics.GetArticleStats(article.ID); err != nil || articleStats.ShortLinkClicks != 3 {
+		t.Errorf("Expected the article statistics to count 3 short link clicks, got %+v (%v)", articleStats, err)
+	}
+
+	// Short links stop working when the article is unpublished
+	if err := application.DB.Exec("UPDATE articles SET status = ? WHERE id = ?", models.StatusDraft, article.ID); err != nil {
+		t.Fatalf("Failed to unpublish article: %v", err)
+	}
+	if w := tokenRequest(application, "", "GET", "/s/"+link.Code, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected the short link of a draft to be not found, got %d", w.Code)
+	}
+}
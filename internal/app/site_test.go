@@ -0,0 +1,380 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Maintenance.Enabled = true
+		cfg.Maintenance.Message = "Upgrading the database"
+		cfg.Maintenance.RetryAfter = 120
+	})
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+	register := `{"username": "reader", "email": "reader@example.com", "password": "password123"}`
+
+	w := tokenRequest(application, "", http.MethodPost, "/api/auth/register", register)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "120" ||
+		!strings.Contains(w.Body.String(), "Upgrading the database") {
+		t.Fatalf("Expected status 503 with the banner, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, "", http.MethodGet, "/api/articles", ""); w.Code != http.StatusOK || w.Header().Get("X-Maintenance-Mode") != "on" {
+		t.Errorf("Expected reads to be served with the maintenance header, got %d", w.Code)
+	}
+	// Signing in stays available so admins can reach the admin routes
+	if w := tokenRequest(application, "", http.MethodPost, "/api/auth/login", `{"email": "admin@example.com", "password": "wrong-password"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected login to be served, got %d", w.Code)
+	}
+
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/v1/admin/maintenance", `{"block_reads": true}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, "", http.MethodGet, "/api/articles", ""); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected reads to be blocked, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, "/api/admin/maintenance", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"block_reads":true`) {
+		t.Errorf("Expected admin routes to be served, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/admin/maintenance", `{"enabled": false}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = tokenRequest(application, "", http.MethodPost, "/api/auth/register", register)
+	if w.Code != http.StatusCreated || w.Header().Get("X-Maintenance-Mode") != "" {
+		t.Errorf("Expected writes to work again, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestSiteVisibility(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Site.Visibility = "private"
+		cfg.Site.Message = "Launching next week"
+	})
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+	seedArticles(t, application)
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	w := authRequest(t, application, admin, http.MethodGet, fmt.Sprintf("/api/articles/%d/shortlink", article.ID), "")
+	var shortLink struct {
+		Data models.ShortLink `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &shortLink); err != nil || shortLink.Data.Code == "" {
+		t.Fatalf("Expected a short link, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = tokenRequest(application, "", http.MethodGet, "/api/articles", "")
+	if w.Code != http.StatusUnauthorized || !strings.Contains(w.Body.String(), "Launching next week") ||
+		w.Header().Get("X-Robots-Tag") != "noindex, nofollow" {
+		t.Fatalf("Expected status 401 with the coming soon message, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, "invalid-token", http.MethodGet, "/api/v1/tags", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an invalid token to be refused, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, "/api/articles", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected signed-in readers to be served, got %d (%s)", w.Code, w.Body.String())
+	}
+	// Short links do not reveal article slugs either
+	shortPath := "/s/" + shortLink.Data.Code
+	if w := tokenRequest(application, "", http.MethodGet, shortPath, ""); w.Code != http.StatusUnauthorized || w.Header().Get("Location") != "" {
+		t.Errorf("Expected short links to be refused, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, shortPath, ""); w.Code != http.StatusFound {
+		t.Errorf("Expected short links to be followed by signed-in readers, got %d", w.Code)
+	}
+	// Signing in stays available
+	login(t, application, "admin@example.com", testPassword)
+
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/admin/site-visibility", `{"visibility": "hidden"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown visibility, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/admin/site-visibility", `{"visibility": "unlisted"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	w = tokenRequest(application, "", http.MethodGet, "/api/articles", "")
+	if w.Code != http.StatusOK || w.Header().Get("X-Robots-Tag") != "noindex, nofollow" {
+		t.Errorf("Expected unlisted blogs to be served but not indexed, got %d", w.Code)
+	}
+	if application.Services.Site.Listed() {
+		t.Error("Expected an unlisted blog to be kept out of search engines")
+	}
+	if w := tokenRequest(application, "", http.MethodGet, shortPath, ""); w.Code != http.StatusFound {
+		t.Errorf("Expected short links of unlisted blogs to be followed, got %d", w.Code)
+	}
+
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/admin/site-visibility", `{"visibility": "public"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, "", http.MethodGet, "/api/articles", ""); w.Code != http.StatusOK || w.Header().Get("X-Robots-Tag") != "" {
+		t.Errorf("Expected public blogs to be indexed, got %d", w.Code)
+	}
+}
+
+func TestCategoryAppearance(t *testing.T) {
+	application := setupTestApp(t)
+	db := application.DB
+	author := &models.User{Username: "author", Email: "author@example.com"}
+	createUsers(t, application, author)
+
+	if err := db.Create(&models.Category{Name: "Frontend", Slug: "frontend", AccentColor: "blue"}); err == nil {
+		t.Error("Expected an accent color that is not #rrggbb to be refused")
+	}
+	if err := db.Create(&models.Category{Name: "Frontend", Slug: "frontend", IconURL: "icon.svg"}); err == nil {
+		t.Error("Expected an icon that is not a URL to be refused")
+	}
+	category := &models.Category{Name: "Backend", Slug: "backend", CoverURL: "https://cdn.example.com/backend.jpg",
+		IconURL: "https://cdn.example.com/backend.svg", AccentColor: "#1a73e8"}
+	if err := db.Create(category); err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	now := time.Now()
+	article := &models.Article{Title: "Go services", Slug: "go-services", Content: "Content", AuthorID: author.ID,
+		CategoryID: &category.ID, Status: models.StatusPublished, PublishedAt: &now}
+	if err := db.Create(article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	w := tokenRequest(application, "", http.MethodGet, "/api/articles/go-services", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	var response struct {
+		Data struct {
+			Category *models.Category `json:"category"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if got := response.Data.Category; got == nil || got.CoverURL != category.CoverURL ||
+		got.IconURL != category.IconURL || got.AccentColor != "#1a73e8" {
+		t.Errorf("Expected the category cover, icon and accent color, got %+v", got)
+	}
+}
+
+func TestTagDetails(t *testing.T) {
+	application := setupTestApp(t)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+	tag := &models.Tag{Name: "golang", Slug: "golang"}
+	if err := application.DB.Create(tag); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	path := fmt.Sprintf("/api/admin/tags/%d", tag.ID)
+
+	if w := authRequest(t, application, reader, http.MethodPut, path, `{"description": "Intro"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodPut, "/api/admin/tags/999", `{"description": "Intro"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing tag, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodPut, path, fmt.Sprintf(`{"meta_title": %q}`, strings.Repeat("a", 101))); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a long meta title, got %d", w.Code)
+	}
+
+	body := `{"description": "  Articles about the Go language.  ", "meta_title": "Go  articles", "meta_description": "Tutorials and news about Go"}`
+	if w := authRequest(t, application, admin, http.MethodPut, path, body); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	// Omitted fields are left unchanged
+	if w := authRequest(t, application, admin, http.MethodPut, path, `{"meta_description": "Go tutorials"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w := tokenRequest(application, "", http.MethodGet, "/api/tags/golang", "")
+	var response struct {
+		Data models.Tag `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if w.Code != http.StatusOK || response.Data.Description != "Articles about the Go language." ||
+		response.Data.MetaTitle != "Go articles" || response.Data.MetaDescription != "Go tutorials" {
+		t.Errorf("Expected the tag page text in the tag detail, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestHomepageSections(t *testing.T) {
+	application := setupTestApp(t)
+	_, webTag := seedArticles(t, application)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	createUsers(t, application, admin)
+	var goOnly, webOnly models.Article
+	if err := application.DB.GetByField(&goOnly, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	if err := application.DB.GetByField(&webOnly, "slug", "web-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	create := func(body string) uint {
+		t.Helper()
+		w := authRequest(t, application, admin, http.MethodPost, "/api/admin/homepage-sections", body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.HomepageSection `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data.ID
+	}
+	create(`{"title": "Latest", "type": "latest", "max_articles": 1, "position": 2}`)
+	tagged := create(fmt.Sprintf(`{"title": "Web", "type": "tag", "tag_id": %d, "position": 1}`, webTag.ID))
+	picks := create(fmt.Sprintf(`{"title": "Editor's picks", "type": "manual", "article_ids": [%d, %d]}`, goOnly.ID, webOnly.ID))
+
+	for _, body := range []string{
+		`{"title": "Backend", "type": "category"}`,
+		`{"title": "Picks", "type": "manual", "article_ids": [999]}`,
+		fmt.Sprintf(`{"title": "Picks", "type": "manual", "article_ids": [%d, %d]}`, goOnly.ID, goOnly.ID),
+		`{"title": "Popular", "type": "popular"}`,
+	} {
+		if w := authRequest(t, application, admin, http.MethodPost, "/api/admin/homepage-sections", body); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", body, w.Code)
+		}
+	}
+
+	homepage := func() map[string][]string {
+		t.Helper()
+		w := tokenRequest(application, "", http.MethodGet, "/api/homepage", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []services.HomepageBlock `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		sections := map[string][]string{}
+		order := []string{}
+		for _, block := range response.Data {
+			order = append(order, block.Title)
+			titles := []string{}
+			for _, article := range block.Articles {
+				titles = append(titles, article.Title)
+			}
+			sections[block.Title] = titles
+		}
+		sections["order"] = order
+		return sections
+	}
+
+	want := map[string][]string{
+		"order":          {"Editor's picks", "Web", "Latest"},
+		"Editor's picks": {"Go only", "Web only"},
+		"Web":            {"Web only", "Go web"},
+		"Latest":         {"Web only"},
+	}
+	if got := homepage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected homepage %v, got %v", want, got)
+	}
+
+	path := fmt.Sprintf("/api/admin/homepage-sections/%d", picks)
+	if w := authRequest(t, application, admin, http.MethodPut, path, fmt.Sprintf(`{"article_ids": [%d], "position": 3}`, webOnly.ID)); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, admin, http.MethodDelete, fmt.Sprintf("/api/admin/homepage-sections/%d", tagged), ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, admin, http.MethodDelete, fmt.Sprintf("/api/admin/homepage-sections/%d", tagged), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a deleted section, got %d", w.Code)
+	}
+
+	want = map[string][]string{
+		"order":          {"Latest", "Editor's picks"},
+		"Latest":         {"Web only"},
+		"Editor's picks": {"Web only"},
+	}
+	if got := homepage(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected homepage %v, got %v", want, got)
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, "/api/admin/homepage-sections", ""); w.Code != http.StatusOK ||
+		!strings.Contains(w.Body.String(), fmt.Sprintf(`"article_ids":[%d]`, webOnly.ID)) {
+		t.Errorf("Expected the sections with their articles, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestPages(t *testing.T) {
+	application := setupTestApp(t)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+
+	create := func(body string) models.Page {
+		w := authRequest(t, application, admin, http.MethodPost, "/api/admin/pages", body)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data models.Page `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data
+	}
+
+	about := create(`{"title": "About Us", "content": "<p>Who we are</p><script>alert(1)</script>", "status": "published", "show_in_nav": true, "nav_order": 2}`)
+	if about.Slug != "about-us" || about.PublishedAt == nil || strings.Contains(about.Content, "script") {
+		t.Errorf("Expected a sanitized published page with a derived slug, got %+v", about)
+	}
+	create(`{"title": "Contact", "slug": "contact", "content": "Write to us", "status": "published", "show_in_nav": true, "nav_order": 1}`)
+	create(`{"title": "Imprint", "content": "Legal notice", "status": "published"}`)
+	draft := create(`{"title": "Roadmap", "content": "Coming soon"}`)
+
+	if w := authRequest(t, application, admin, http.MethodPost, "/api/admin/pages", `{"title": "About", "slug": "about-us", "content": "Again"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a taken slug, got %d", w.Code)
+	}
+	if w := authRequest(t, application, reader, http.MethodPost, "/api/admin/pages", `{"title": "Mine", "content": "Mine"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+
+	w := tokenRequest(application, "", http.MethodGet, "/api/pages?nav=true", "")
+	var nav struct {
+		Data []models.PageLink `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &nav); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(nav.Data) != 2 || nav.Data[0].Slug != "contact" || nav.Data[1].Slug != "about-us" {
+		t.Errorf("Expected navigation in order contact, about-us, got %+v", nav.Data)
+	}
+	if w := tokenRequest(application, "", http.MethodGet, "/api/pages", ""); strings.Count(w.Body.String(), `"slug"`) != 3 {
+		t.Errorf("Expected the three published pages, got %s", w.Body.String())
+	}
+
+	// Drafts are hidden from readers and previewable by admins
+	if w := tokenRequest(application, "", http.MethodGet, "/api/pages/roadmap", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a draft, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodGet, "/api/pages/roadmap", ""); w.Code != http.StatusOK {
+		t.Errorf("Expected admins to preview drafts, got %d", w.Code)
+	}
+	if w := authRequest(t, application, admin, http.MethodPut, fmt.Sprintf("/api/admin/pages/%d", draft.ID), `{"status": "published"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := tokenRequest(application, "", http.MethodGet, "/api/pages/roadmap", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "Coming soon") {
+		t.Errorf("Expected the published page, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Pages stay out of the article timeline
+	if w := tokenRequest(application, "", http.MethodGet, "/api/articles", ""); strings.Contains(w.Body.String(), "About Us") {
+		t.Errorf("Expected pages to stay out of article lists, got %s", w.Body.String())
+	}
+
+	if w := authRequest(t, application, admin, http.MethodDelete, fmt.Sprintf("/api/admin/pages/%d", about.ID), ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if w := tokenRequest(application, "", http.MethodGet, "/api/pages/about-us", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after deletion, got %d", w.Code)
+	}
+}
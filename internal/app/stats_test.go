@@ -0,0 +1,314 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestArticleViewTraffic(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	path := fmt.Sprintf("/api/articles/%d/views", article.ID)
+
+	// Explicit tracking data from the client
+	body := `{"referrer": "https://www.Google.com/search?q=go", "utm_source": "Newsletter", "utm_medium": "email", "utm_campaign": "launch"}`
+	for i := 0; i < 2; i++ {
+		if w := tokenRequest(application, "", "POST", path, body); w.Code != http.StatusOK {
+			t.Fatalf("Expected the view to be recorded, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	// Without a body the Referer header and query string are used
+	req := httptest.NewRequest("POST", path+"?utm_source=twitter", nil)
+	req.Header.Set("Referer", "https://t.co/abc")
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected the view to be recorded, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if err := application.DB.Exec("UPDATE articles SET status = ? WHERE slug = ?", models.StatusDraft, "web-only"); err != nil {
+		t.Fatalf("Failed to unpublish article: %v", err)
+	}
+	var draft models.Article
+	if err := application.DB.GetByField(&draft, "slug", "web-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	if w := tokenRequest(application, "", "POST", fmt.Sprintf("/api/articles/%d/views", draft.ID), ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected views of drafts to be rejected, got %d", w.Code)
+	}
+
+	var counted models.Article
+	if err := application.DB.GetByID(&counted, article.ID); err != nil || counted.ViewCount != article.ViewCount+3 {
+		t.Errorf("Expected 3 more views, got %d (%v)", counted.ViewCount, err)
+	}
+
+	w = tokenRequest(application, "", "GET", fmt.Sprintf("/api/stats/authors/%d?days=7", article.AuthorID), "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected author statistics, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			Traffic services.AuthorTraffic `json:"traffic"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	traffic := resp.Data.Traffic
+	if want := []models.TrafficCount{{Value: "www.google.com", Views: 2}, {Value: "t.co", Views: 1}}; !reflect.DeepEqual(traffic.Referrers, want) {
+		t.Errorf("Expected referrers %v, got %v", want, traffic.Referrers)
+	}
+	if want := []models.TrafficCount{{Value: "newsletter", Views: 2}, {Value: "twitter", Views: 1}}; !reflect.DeepEqual(traffic.Sources, want) {
+		t.Errorf("Expected sources %v, got %v", want, traffic.Sources)
+	}
+	if want := []models.TrafficCount{{Value: "launch", Views: 2}, {Value: "", Views: 1}}; !reflect.DeepEqual(traffic.Campaigns, want) {
+		t.Errorf("Expected campaigns %v, got %v", want, traffic.Campaigns)
+	}
+}
+
+func TestAuthorMonthlyReport(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	db := application.DB
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := db.GetByField(&article, "slug", "go-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	// A second author who opted out of the reports
+	quiet := &models.User{Username: "quiet", Email: "quiet@example.com"}
+	createUsers(t, application, quiet)
+	now := time.Now()
+	quietArticle := &models.Article{Title: "Quiet", Slug: "quiet", Content: "Content", AuthorID: quiet.ID, Status: models.StatusPublished, PublishedAt: &now}
+	if err := db.Create(quietArticle); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+	off := false
+	if _, err := application.Services.UserSettings.Update(quiet.ID, &services.UpdateSettingsRequest{EmailMonthlyReport: &off}); err != nil {
+		t.Fatalf("Failed to opt out: %v", err)
+	}
+
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 6, 0, 0, 0, time.UTC)
+	lastMonth := thisMonth.AddDate(0, -1, 10)
+	for _, view := range []models.ArticleView{
+		{ArticleID: article.ID, CreatedAt: lastMonth},
+		{ArticleID: article.ID, CreatedAt: lastMonth},
+		{ArticleID: article.ID, CreatedAt: thisMonth}, // outside the reported month
+		{ArticleID: quietArticle.ID, CreatedAt: lastMonth},
+	} {
+		if err := db.Create(&view); err != nil {
+			t.Fatalf("Failed to create view: %v", err)
+		}
+	}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, reader)
+	if err := db.Create(&models.Like{UserID: reader.ID, ArticleID: article.ID, CreatedAt: lastMonth}); err != nil {
+		t.Fatalf("Failed to create like: %v", err)
+	}
+
+	mailer := &recordingMailer{}
+	reports := services.NewAuthorReportService(application.Repositories.AuthorReport, application.Repositories.User,
+		application.Services.UserSettings, mailer)
+	run, err := reports.SendMonthlyReports(thisMonth)
+	if err != nil {
+		t.Fatalf("Failed to send reports: %v", err)
+	}
+	if run.Sent != 1 || run.Skipped != 1 || run.Failed != 0 {
+		t.Fatalf("Expected one report sent and one skipped, got %+v", run)
+	}
+	if len(mailer.sent) != 1 || !strings.HasPrefix(mailer.sent[0], "author@example.com: ") {
+		t.Fatalf("Expected the report to be mailed to the author, got %v", mailer.sent)
+	}
+	body := mailer.bodies[0]
+	for _, want := range []string{"Views: 2 ", "Likes: 1 ", "1. Go only (2 views)"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the report to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	// Rerunning the job does not send the month's report again
+	run, err = reports.SendMonthlyReports(thisMonth)
+	if err != nil || run.Sent != 0 || len(mailer.sent) != 1 {
+		t.Errorf("Expected no report to be sent twice, got %+v (%v)", run, err)
+	}
+}
+
+func TestAuthorLeaderboard(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	db := application.DB
+
+	// "author" keeps 2 published articles with 150 views in all; "writer" scores
+	// more with fewer views and "twin" ties "author" on score
+	now := time.Now()
+	for _, seed := range []struct {
+		username     string
+		views, likes uint
+	}{{"writer", 50, 40}, {"twin", 150, 0}} {
+		user := &models.User{Username: seed.username, Email: seed.username + "@example.com"}
+		createUsers(t, application, user)
+		article := &models.Article{Title: seed.username, Slug: seed.username, Content: "Content", AuthorID: user.ID,
+			Status: models.StatusPublished, PublishedAt: &now, ViewCount: seed.views, LikeCount: seed.likes}
+		if err := db.Create(article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+	// Drafts count towards no total
+	if err := db.Exec("UPDATE articles SET status = ? WHERE slug = ?", models.StatusDraft, "go-only"); err != nil {
+		t.Fatalf("Failed to unpublish article: %v", err)
+	}
+
+	leaderboard := func(query string) []repositories.AuthorRank {
+		t.Helper()
+		w := tokenRequest(application, "", "GET", "/api/stats/authors"+query, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected the leaderboard, got %d: %s", w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []repositories.AuthorRank `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+
+	ranks := leaderboard("")
+	if len(ranks) != 3 || ranks[0].Username != "writer" || ranks[0].Score != 170 || ranks[0].Rank != 1 {
+		t.Fatalf("Expected writer to lead on score, got %+v", ranks)
+	}
+	if ranks[1].Username != "author" || ranks[1].Rank != 2 || ranks[2].Username != "twin" || ranks[2].Rank != 2 {
+		t.Errorf("Expected author and twin to share second place, got %+v", ranks[1:])
+	}
+	if ranks[1].ArticleCount != 2 || ranks[1].TotalViews != 150 {
+		t.Errorf("Expected only published articles in the totals, got %+v", ranks[1])
+	}
+
+	ranks = leaderboard("?by=articles&limit=1")
+	if len(ranks) != 1 || ranks[0].Username != "author" {
+		t.Errorf("Expected author to lead on articles, got %+v", ranks)
+	}
+
+	if w := tokenRequest(application, "", "GET", "/api/stats/authors?by=followers", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unknown metric to be rejected, got %d", w.Code)
+	}
+
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	w := tokenRequest(application, "", "GET", fmt.Sprintf("/api/stats/authors/%d", author.ID), "")
+	var response struct {
+		Data struct {
+			Summary services.AuthorStats `json:"summary"`
+		} `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Data.Summary.ArticleCount != 2 || response.Data.Summary.TotalViews != 150 {
+		t.Errorf("Expected the summary of published articles, got %+v", response.Data.Summary)
+	}
+}
+
+func TestArticleRankings(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Stats = config.StatsConfig{RankingInterval: 15, TrendingWindow: 7}
+	})
+	seedArticles(t, application)
+	db := application.DB
+
+	var author models.User
+	if err := db.GetByField(&author, "username", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	// An older article is the most popular but has lost its trending score
+	published := time.Now().AddDate(0, 0, -21)
+	old := &models.Article{Title: "Old", Slug: "old", Content: "Content", AuthorID: author.ID,
+		Status: models.StatusPublished, PublishedAt: &published, ViewCount: 300}
+	if err := db.Create(old); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	type ranked struct {
+		Title         string  `json:"title"`
+		Score         float64 `json:"score"`
+		TrendingScore float64 `json:"trending_score"`
+	}
+	list := func(path string) []ranked {
+		t.Helper()
+		w := tokenRequest(application, "", "GET", path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d: %s", path, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []ranked `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	titles := func(articles []ranked) []string {
+		names := make([]string, len(articles))
+		for i, article := range articles {
+			names[i] = article.Title
+		}
+		return names
+	}
+
+	// The first listing ranks the articles before the job ever ran
+	popular := list("/api/stats/popular")
+	if got := titles(popular); !reflect.DeepEqual(got, []string{"Old", "Go web", "Web only", "Go only"}) {
+		t.Fatalf("Unexpected popular articles %v", got)
+	}
+	if popular[0].Score != 300 {
+		t.Errorf("Expected the popularity score to weigh views once, got %v", popular[0].Score)
+	}
+	if got := titles(list("/api/stats/trending")); !reflect.DeepEqual(got, []string{"Go web", "Web only", "Go only"}) {
+		t.Errorf("Expected only articles of the last week to trend, got %v", got)
+	}
+	trending := list("/api/stats/trending?days=30")
+	if got := titles(trending); !reflect.DeepEqual(got, []string{"Go web", "Old", "Web only", "Go only"}) {
+		t.Fatalf("Expected the old article to trend less than its views suggest, got %v", got)
+	}
+	if score := trending[1].TrendingScore; score < 74.9 || score > 75.1 {
+		t.Errorf("Expected three weeks to divide the score by four, got %v", score)
+	}
+
+	// Listings read the rankings until they are refreshed, except for articles
+	// deleted or unpublished since
+	if err := db.Exec("UPDATE articles SET view_count = 1000 WHERE slug = ?", "go-only"); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if err := db.Exec("UPDATE articles SET status = ? WHERE slug = ?", models.StatusDraft, "web-only"); err != nil {
+		t.Fatalf("Failed to unpublish article: %v", err)
+	}
+	if got := titles(list("/api/stats/popular")); !reflect.DeepEqual(got, []string{"Old", "Go web", "Go only"}) {
+		t.Errorf("Expected stale rankings without the unpublished article, got %v", got)
+	}
+
+	count, err := application.Services.Statistics.RefreshRankings()
+	if err != nil {
+		t.Fatalf("Failed to refresh rankings: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 articles ranked, got %d", count)
+	}
+	if got := titles(list("/api/stats/popular?limit=2")); !reflect.DeepEqual(got, []string{"Go only", "Old"}) {
+		t.Errorf("Expected refreshed rankings, got %v", got)
+	}
+}
@@ -0,0 +1,227 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/pkg/config"
+)
+
+func TestTagAliases(t *testing.T) {
+	application := setupTestApp(t)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	writer := &models.User{Username: "writer", Email: "writer@example.com"}
+	createUsers(t, application, admin, writer)
+	goTag := &models.Tag{Name: "Go", Slug: "go"}
+	if err := application.DB.Create(goTag); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	body := fmt.Sprintf(`{"alias": "GoLang", "tag_id": %d}`, goTag.ID)
+	if w := authRequest(t, application, writer, http.MethodPost, "/api/admin/tag-aliases", body); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+	w := authRequest(t, application, admin, http.MethodPost, "/api/admin/tag-aliases", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created struct {
+		Data models.TagAlias `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Data.Alias != "golang" || created.Data.TagID != goTag.ID {
+		t.Errorf("Expected the alias stored lowercase for the Go tag, got %+v", created.Data)
+	}
+
+	// An alias may not shadow a tag name or another alias, whatever their case
+	for _, alias := range []string{"GO", "golang"} {
+		body := fmt.Sprintf(`{"alias": %q, "tag_id": %d}`, alias, goTag.ID)
+		if w := authRequest(t, application, admin, http.MethodPost, "/api/admin/tag-aliases", body); w.Code != http.StatusConflict {
+			t.Errorf("Expected status 409 for alias %q, got %d", alias, w.Code)
+		}
+	}
+	if w := authRequest(t, application, admin, http.MethodPost, "/api/admin/tag-aliases", `{"alias": "go-lang", "tag_id": 999}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a missing tag, got %d", w.Code)
+	}
+
+	// Tag names resolve case-insensitively and through aliases to the canonical tag
+	article := createArticle(t, application, writer.ID, &services.CreateArticleRequest{
+		Title:    "Generics",
+		Content:  "Type parameters",
+		TagNames: []string{"golang", "GO", " go ", "GOLANG"},
+	})
+	if len(article.Tags) != 1 || article.Tags[0].ID != goTag.ID {
+		t.Errorf("Expected only the Go tag, got %+v", article.Tags)
+	}
+	if w := authRequest(t, application, writer, http.MethodPost, "/api/tags", `{"name": "Golang"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a tag named like an alias, got %d", w.Code)
+	}
+
+	// Deleting the alias frees the name
+	path := fmt.Sprintf("/api/admin/tag-aliases/%d", created.Data.ID)
+	if w := authRequest(t, application, admin, http.MethodDelete, path, ""); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if w := authRequest(t, application, admin, http.MethodDelete, path, ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a deleted alias, got %d", w.Code)
+	}
+	if w := authRequest(t, application, writer, http.MethodPost, "/api/tags", `{"name": "Golang"}`); w.Code != http.StatusCreated {
+		t.Errorf("Expected the former alias to be usable as a tag name, got %d (%s)", w.Code, w.Body.String())
+	}
+}
+
+func TestTagAutocompleteAndUsage(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+	if err := application.DB.Create(&models.Tag{Name: "gopher", Slug: "gopher"}); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+	// Deleted articles no longer count towards the usage of their tags
+	var webOnly models.Article
+	if err := application.DB.GetByField(&webOnly, "slug", "web-only"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+	if err := application.DB.Delete(&models.Article{}, webOnly.ID); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+
+	usage := func(path string) string {
+		t.Helper()
+		w := tokenRequest(application, "", http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected status 200, got %d (%s)", path, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data []services.TagWithStats `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		var tags []string
+		for _, tag := range response.Data {
+			tags = append(tags, fmt.Sprintf("%s:%d", tag.Name, tag.ArticleCount))
+		}
+		return strings.Join(tags, " ")
+	}
+
+	tests := []struct {
+		path string
+		tags string
+	}{
+		{"/api/tags/autocomplete?q=go", "go:2 gopher:0"}, // most used first
+		{"/api/tags/autocomplete?q=GO", "go:2 gopher:0"},
+		{"/api/tags/autocomplete?q=gop", "gopher:0"},
+		{"/api/tags/autocomplete?q=go&limit=1", "go:2"},
+		{"/api/tags/autocomplete?q=rust", ""},
+		{"/api/tags/autocomplete?q=", ""},
+		{"/api/tags?popular=true", "go:2 web:1 gopher:0"},
+		{"/api/tags?popular=true&limit=2", "go:2 web:1"},
+	}
+	for _, tt := range tests {
+		if tags := usage(tt.path); tags != tt.tags {
+			t.Errorf("GET %s: expected %q, got %q", tt.path, tt.tags, tags)
+		}
+	}
+
+	long := "/api/tags/autocomplete?q=" + strings.Repeat("g", 51)
+	if w := tokenRequest(application, "", http.MethodGet, long, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a long prefix, got %d", w.Code)
+	}
+}
+
+func TestOrphanTagCleanup(t *testing.T) {
+	application := setupTestApp(t, func(cfg *config.Config) {
+		cfg.Tags.Protected = []string{"Featured"}
+	})
+	seedArticles(t, application)
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	reader := &models.User{Username: "reader", Email: "reader@example.com"}
+	createUsers(t, application, admin, reader)
+	db := application.DB
+
+	rust := &models.Tag{Name: "rust", Slug: "rust"}
+	featured := &models.Tag{Name: "featured", Slug: "featured"}
+	legacy := &models.Tag{Name: "legacy", Slug: "legacy"}
+	for _, tag := range []*models.Tag{rust, featured, legacy} {
+		if err := db.Create(tag); err != nil {
+			t.Fatalf("Failed to create tag: %v", err)
+		}
+	}
+	if err := db.Create(&models.TagAlias{Alias: "rustlang", TagID: rust.ID}); err != nil {
+		t.Fatalf("Failed to create alias: %v", err)
+	}
+	// A tag used only by a deleted article is an orphan too
+	removed := createArticle(t, application, admin.ID, &services.CreateArticleRequest{Title: "Old news", Content: "Content", TagNames: []string{"legacy"}})
+	if err := db.Delete(&models.Article{}, removed.ID); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+
+	cleanup := func(method string) services.OrphanTagReport {
+		t.Helper()
+		w := authRequest(t, application, admin, method, "/api/admin/tags/orphans", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d (%s)", method, w.Code, w.Body.String())
+		}
+		var response struct {
+			Data services.OrphanTagReport `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response.Data
+	}
+	names := func(tags []models.Tag) string {
+		var result []string
+		for _, tag := range tags {
+			result = append(result, tag.Name)
+		}
+		return strings.Join(result, " ")
+	}
+	remaining := func() string {
+		t.Helper()
+		tags, err := application.Services.Tag.List()
+		if err != nil {
+			t.Fatalf("Failed to list tags: %v", err)
+		}
+		return names(tags)
+	}
+
+	if w := authRequest(t, application, reader, http.MethodDelete, "/api/admin/tags/orphans", ""); w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+
+	// The scheduled task only reports orphans unless configured to delete them
+	w := authRequest(t, application, admin, http.MethodPost, "/api/admin/jobs/orphan_tag_cleanup/run", "")
+	var run struct {
+		Data models.JobRun `json:"data"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &run)
+	if w.Code != http.StatusOK || run.Data.Summary != "2 orphans, 0 deleted, 1 protected" {
+		t.Errorf("Unexpected cleanup task run %d (%s)", w.Code, w.Body.String())
+	}
+
+	// Listing orphans deletes nothing either
+	report := cleanup(http.MethodGet)
+	if !report.DryRun || names(report.Orphans) != "legacy rust" || names(report.Protected) != "featured" || report.Deleted != 0 {
+		t.Errorf("Unexpected orphan report %+v", report)
+	}
+	if tags := remaining(); tags != "featured go legacy rust web" {
+		t.Errorf("Expected a dry run to keep every tag, got %q", tags)
+	}
+
+	// Cleanup deletes the unreferenced tags with their aliases only
+	report = cleanup(http.MethodDelete)
+	if report.DryRun || names(report.Orphans) != "legacy rust" || report.Deleted != 2 {
+		t.Errorf("Unexpected cleanup report %+v", report)
+	}
+	if tags := remaining(); tags != "featured go web" {
+		t.Errorf("Expected the used and protected tags to be kept, got %q", tags)
+	}
+	if aliases, err := application.Services.Tag.ListAliases(0); err != nil || len(aliases) != 0 {
+		t.Errorf("Expected the aliases of deleted tags to be removed, got %+v (%v)", aliases, err)
+	}
+	if report := cleanup(http.MethodDelete); len(report.Orphans) != 0 || report.Deleted != 0 {
+		t.Errorf("Expected nothing left to clean up, got %+v", report)
+	}
+}
@@ -0,0 +1,408 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go-blog/internal/models"
+	"go-blog/internal/utils"
+)
+
+func TestUserSettings(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	var user models.User
+	if err := application.DB.GetByField(&user, "username", "author"); err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+
+	decode := func(w *httptest.ResponseRecorder) models.UserSettings {
+		var response struct {
+			Data models.UserSettings `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data
+	}
+
+	w := authRequest(t, application, &user, http.MethodGet, "/api/users/me/settings", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if settings := decode(w); !settings.EmailNotifications || settings.ProfileVisibility != models.ProfileVisibilityPublic {
+		t.Errorf("Expected default settings, got %+v", settings)
+	}
+
+	w = authRequest(t, application, &user, http.MethodPut, "/api/users/me/settings", `{"profile_visibility": "hidden"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown visibility, got %d", w.Code)
+	}
+
+	w = authRequest(t, application, &user, http.MethodPut, "/api/users/me/settings",
+		`{"email_search_alerts": false, "profile_visibility": "private"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	settings := decode(w)
+	if settings.EmailSearchAlerts || !settings.EmailNotifications || settings.EditorMode != models.EditorMarkdown {
+		t.Errorf("Expected only the given fields to change, got %+v", settings)
+	}
+
+	profilePath := fmt.Sprintf("/api/users/%d", user.ID)
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, profilePath, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a private profile, got %d", w.Code)
+	}
+	if w = authRequest(t, application, &user, http.MethodGet, profilePath, ""); w.Code != http.StatusOK {
+		t.Errorf("Expected the owner to see their private profile, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/search?q=author&type=users", nil))
+	if strings.Contains(w.Body.String(), `"username":"author"`) {
+		t.Errorf("Expected user search to skip private profiles, got %s", w.Body.String())
+	}
+
+	// Search alert emails are off, so the alert is delivered in-app only
+	mailer := &recordingMailer{}
+	application.Services.Notification.SetMailer(mailer)
+	err := application.Services.Notification.Notify(&models.Notification{
+		UserID: user.ID, Type: models.NotificationSavedSearch, Title: "New matches",
+	}, true)
+	if err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if len(mailer.sent) != 0 {
+		t.Errorf("Expected no email, got %v", mailer.sent)
+	}
+}
+
+func TestAuthorPage(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	var response struct {
+		Data struct {
+			Profile  map[string]interface{}  `json:"profile"`
+			Stats    map[string]int64        `json:"stats"`
+			Articles []models.ArticleSummary `json:"articles"`
+		} `json:"data"`
+		Meta utils.Meta `json:"meta"`
+	}
+
+	w := httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/authors/Author", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Data.Profile["handle"] != "author" {
+		t.Errorf("Expected handle author, got %v", response.Data.Profile["handle"])
+	}
+	if _, ok := response.Data.Profile["email"]; ok {
+		t.Errorf("Expected the public profile to omit email")
+	}
+	if response.Data.Stats["article_count"] != 3 || response.Data.Stats["total_views"] != 160 {
+		t.Errorf("Expected 3 articles and 160 views, got %v", response.Data.Stats)
+	}
+	if len(response.Data.Articles) != 3 || response.Meta.Pagination.Total != 3 {
+		t.Errorf("Expected 3 published articles, got %d (total %d)", len(response.Data.Articles), response.Meta.Pagination.Total)
+	}
+
+	var user models.User
+	if err := application.DB.GetByField(&user, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+	userPath := fmt.Sprintf("/api/users/%d", user.ID)
+	if w = authRequest(t, application, &user, http.MethodPut, userPath, `{"handle": "me"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a reserved handle, got %d", w.Code)
+	}
+	if w = authRequest(t, application, &user, http.MethodPut, userPath, `{"handle": "go-writer"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	for path, status := range map[string]int{
+		"/api/authors/go-writer": http.StatusOK,
+		"/api/authors/author":    http.StatusNotFound,
+	} {
+		w = httptest.NewRecorder()
+		application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != status {
+			t.Errorf("GET %s: expected status %d, got %d", path, status, w.Code)
+		}
+	}
+}
+
+func TestProfileSocialLinks(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	var user models.User
+	if err := application.DB.GetByField(&user, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+	userPath := fmt.Sprintf("/api/users/%d", user.ID)
+
+	w := authRequest(t, application, &user, http.MethodPut, userPath, `{"twitter": "not a handle!"}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "twitter") {
+		t.Errorf("Expected a twitter field error, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = authRequest(t, application, &user, http.MethodPut, userPath,
+		`{"website": "https://go.dev", "twitter": "@gopher", "github": "https://github.com/golang", "location": "Remote"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/articles?limit=1", nil))
+	var response struct {
+		Data []models.ArticleSummary `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Data) != 1 {
+		t.Fatalf("Expected one article, got %d", len(response.Data))
+	}
+
+	author := response.Data[0].Author
+	want := models.SocialLinks{Website: "https://go.dev", Twitter: "https://x.com/gopher", GitHub: "https://github.com/golang"}
+	if author.Links != want || author.Location != "Remote" {
+		t.Errorf("Expected links %+v in Remote, got %+v in %q", want, author.Links, author.Location)
+	}
+	if strings.Contains(w.Body.String(), "author@example.com") {
+		t.Errorf("Expected the author block to omit the email")
+	}
+}
+
+func TestAvatarUpload(t *testing.T) {
+	application := setupTestApp(t)
+	seedArticles(t, application)
+
+	var user models.User
+	if err := application.DB.GetByField(&user, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load user: %v", err)
+	}
+
+	profileAvatar := func() string {
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/users/%d", user.ID), nil))
+		var response struct {
+			Data models.User `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return response.Data.AvatarURL
+	}
+
+	gravatar := models.GravatarURL("author@example.com", models.AvatarSize)
+	if got := profileAvatar(); got != gravatar {
+		t.Errorf("Expected Gravatar fallback %q, got %q", gravatar, got)
+	}
+
+	upload := func(filename string, content []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("avatar", filename)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(content)
+		form.Close()
+
+		token, err := utils.GenerateJWT(user.ID, user.Username, user.Email, application.Config.JWT.Secret)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/api/users/me/avatar", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		application.Router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := upload("avatar.txt", []byte("not an image")); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a non-image, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var img bytes.Buffer
+	if err := png.Encode(&img, image.NewRGBA(image.Rect(0, 0, 300, 200))); err != nil {
+		t.Fatalf("Failed to encode image: %v", err)
+	}
+
+	var uploaded struct {
+		Data struct {
+			AvatarURL string            `json:"avatar_url"`
+			Sizes     map[string]string `json:"sizes"`
+		} `json:"data"`
+	}
+	for i := 0; i < 2; i++ {
+		w := upload("avatar.png", img.Bytes())
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &uploaded); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+	}
+
+	if len(uploaded.Data.Sizes) != 3 || uploaded.Data.AvatarURL != uploaded.Data.Sizes["256"] {
+		t.Fatalf("Expected three sizes with the 256px one as avatar, got %+v", uploaded.Data)
+	}
+	if got := profileAvatar(); got != uploaded.Data.AvatarURL {
+		t.Errorf("Expected profile avatar %q, got %q", uploaded.Data.AvatarURL, got)
+	}
+
+	// Only the second upload's files remain, and each is a square of its size
+	root := application.Config.Storage.LocalPath
+	files, _ := filepath.Glob(filepath.Join(root, "avatars", fmt.Sprint(user.ID), "*.jpg"))
+	if len(files) != 3 {
+		t.Errorf("Expected the previous upload to be replaced, found %v", files)
+	}
+
+	served := httptest.NewRecorder()
+	path := strings.TrimPrefix(uploaded.Data.Sizes["64"], "http://example.com")
+	application.Router.ServeHTTP(served, httptest.NewRequest(http.MethodGet, path, nil))
+	if served.Code != http.StatusOK {
+		t.Fatalf("Expected the avatar to be served at %s, got %d", path, served.Code)
+	}
+	if cc := served.Header().Get("Cache-Control"); cc != "public, max-age=3600, immutable" {
+		t.Errorf("Expected the avatar to be cached as immutable, got %q", cc)
+	}
+	cfg, _, err := image.DecodeConfig(served.Body)
+	if err != nil || cfg.Width != 64 || cfg.Height != 64 {
+		t.Errorf("Expected a 64x64 image, got %dx%d (%v)", cfg.Width, cfg.Height, err)
+	}
+
+	w := authRequest(t, application, &user, http.MethodDelete, "/api/users/me/avatar", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	if got := profileAvatar(); got != gravatar {
+		t.Errorf("Expected Gravatar fallback after removal, got %q", got)
+	}
+	if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected avatar files to be deleted, got %v", err)
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	application := setupTestApp(t)
+	goTag, _ := seedArticles(t, application)
+
+	var author models.User
+	if err := application.DB.GetByField(&author, "handle", "author"); err != nil {
+		t.Fatalf("Failed to load author: %v", err)
+	}
+	var article models.Article
+	if err := application.DB.GetByField(&article, "slug", "go-web"); err != nil {
+		t.Fatalf("Failed to load article: %v", err)
+	}
+
+	register := func(username string) string {
+		return registerUser(t, application, username, username+"@example.com").Tokens.AccessToken
+	}
+
+	// The reader comments, likes and follows, then deletes their account with everything
+	readerToken := register("reader")
+	var reader models.User
+	if err := application.DB.GetByField(&reader, "username", "reader"); err != nil {
+		t.Fatalf("Failed to load reader: %v", err)
+	}
+	if err := application.DB.Create(&models.Comment{ArticleID: article.ID, UserID: reader.ID, Content: "Nice"}); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+	if err := application.DB.Create(&models.Like{ArticleID: article.ID, UserID: reader.ID}); err != nil {
+		t.Fatalf("Failed to create like: %v", err)
+	}
+	if err := application.Repositories.Follow.Create(&models.Follow{UserID: reader.ID, TargetType: models.FollowTargetTag, TargetID: goTag.ID}); err != nil {
+		t.Fatalf("Failed to create follow: %v", err)
+	}
+	if err := application.DB.Exec("UPDATE articles SET like_count = 1, comment_count = 1 WHERE id = ?", article.ID); err != nil {
+		t.Fatalf("Failed to set statistics: %v", err)
+	}
+
+	w := tokenRequest(application, readerToken, http.MethodDelete, "/api/users/me", `{"password": "wrong-password", "policy": "delete"}`)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 for a wrong password, got %d", w.Code)
+	}
+	w = tokenRequest(application, readerToken, http.MethodDelete, "/api/users/me", `{"password": "password123", "policy": "delete"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"comments":1`) {
+		t.Fatalf("Expected one deleted comment, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	if w := tokenRequest(application, readerToken, http.MethodGet, "/api/auth/me", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the deleted account to be signed out, got %d", w.Code)
+	}
+	_, likes, comments, err := application.Repositories.Article.GetStatistics(article.ID)
+	if err != nil || likes != 0 || comments != 0 {
+		t.Errorf("Expected like and comment counts to drop to 0, got %d and %d (%v)", likes, comments, err)
+	}
+	var tag models.Tag
+	if err := application.DB.GetByID(&tag, goTag.ID); err != nil || tag.FollowerCount != 0 {
+		t.Errorf("Expected the tag to lose its follower, got %d (%v)", tag.FollowerCount, err)
+	}
+	register("reader") // the username and email are free again
+
+	// An admin transfers the author's articles to an editor
+	admin := &models.User{Username: "admin_user", Email: "admin@example.com", Role: models.RoleAdmin}
+	editor := &models.User{Username: "editor", Email: "editor@example.com"}
+	createUsers(t, application, admin, editor)
+
+	authorPath := fmt.Sprintf("/api/admin/users/%d", author.ID)
+	w = authRequest(t, application, editor, http.MethodDelete, authorPath, `{"policy": "delete"}`)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-admin, got %d", w.Code)
+	}
+	w = authRequest(t, application, admin, http.MethodDelete, authorPath, fmt.Sprintf(`{"policy": "transfer", "transfer_to": %d}`, editor.ID))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"articles":3`) {
+		t.Fatalf("Expected three transferred articles, got %d (%s)", w.Code, w.Body.String())
+	}
+	if count, _ := application.Repositories.Article.CountByAuthorID(editor.ID); count != 3 {
+		t.Errorf("Expected the editor to own 3 articles, got %d", count)
+	}
+
+	// Anonymizing hands the editor's articles to the anonymous system user
+	w = authRequest(t, application, admin, http.MethodDelete, fmt.Sprintf("/api/admin/users/%d", editor.ID), `{"policy": "anonymize"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d (%s)", w.Code, w.Body.String())
+	}
+	anonymous, err := application.Repositories.User.GetByUsername(models.AnonymousUsername)
+	if err != nil || anonymous.Role != models.RoleSystem {
+		t.Fatalf("Expected an anonymous system user, got %+v (%v)", anonymous, err)
+	}
+	if count, _ := application.Repositories.Article.CountByAuthorID(anonymous.ID); count != 3 {
+		t.Errorf("Expected the anonymous user to own 3 articles, got %d", count)
+	}
+	w = authRequest(t, application, admin, http.MethodDelete, fmt.Sprintf("/api/admin/users/%d", anonymous.ID), `{"policy": "delete"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected the system user to be protected, got %d", w.Code)
+	}
+
+	var audits []models.AuditLog
+	if err := application.DB.GetDB().Order("id").Find(&audits).Error; err != nil {
+		t.Fatalf("Failed to load audit log: %v", err)
+	}
+	if len(audits) != 3 || audits[0].ActorID != reader.ID || audits[1].ActorID != admin.ID ||
+		audits[1].TargetID != author.ID || !strings.Contains(audits[1].Details, `"policy":"transfer"`) {
+		t.Errorf("Unexpected audit log %+v", audits)
+	}
+}
@@ -0,0 +1,114 @@
+// Package breaker is a circuit breaker for calls to backends that can become
+// slow or unreachable. After a run of consecutive failures the breaker opens and
+// rejects calls immediately; once the open period has passed it lets a single
+// trial call through and closes again if it succeeds.
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by Allow while the breaker rejects calls
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a breaker
+type State string
+
+const (
+	StateClosed   State = "closed"    // calls pass
+	StateOpen     State = "open"      // calls are rejected
+	StateHalfOpen State = "half_open" // one trial call decides whether to close again
+)
+
+// Breaker counts consecutive failures of a backend and stops calls to it while
+// it is failing. A zero threshold disables it.
+type Breaker struct {
+	threshold int
+	openFor   time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time // when the breaker opened or its trial call started
+}
+
+// New creates a breaker that opens after threshold consecutive failures and
+// stays open for openFor
+func New(threshold int, openFor time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		openFor:   openFor,
+		now:       time.Now,
+		state:     StateClosed,
+	}
+}
+
+// Allow reports whether a call may proceed, returning ErrOpen when it may not.
+// Every allowed call must be followed by Success or Failure.
+func (b *Breaker) Allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.openFor {
+			return ErrOpen
+		}
+		b.state = StateHalfOpen
+		b.openedAt = b.now()
+		return nil
+	case StateHalfOpen:
+		// Another trial is let through if the previous one never reported back
+		if b.now().Sub(b.openedAt) < b.openFor {
+			return ErrOpen
+		}
+		b.openedAt = b.now()
+		return nil
+	}
+	return nil
+}
+
+// Success records a call that reached the backend, closing the breaker
+func (b *Breaker) Success() {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = StateClosed
+	b.failures = 0
+}
+
+// Failure records a call that failed because of the backend, opening the
+// breaker once the threshold is reached or when the trial call failed
+func (b *Breaker) Failure() {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.openFor {
+		return StateHalfOpen
+	}
+	return b.state
+}
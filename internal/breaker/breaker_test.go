@@ -0,0 +1,78 @@
+package breaker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestBreaker returns a breaker whose clock is advanced by the returned function
+func newTestBreaker(threshold int, openFor time.Duration) (*Breaker, func(time.Duration)) {
+	now := time.Date(2024, time.January, 17, 10, 0, 0, 0, time.UTC)
+	b := New(threshold, openFor)
+	b.now = func() time.Time { return now }
+	return b, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Minute)
+
+	b.Failure()
+	b.Failure()
+	b.Success() // resets the run
+	b.Failure()
+	b.Failure()
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, StateClosed, b.State())
+
+	b.Failure()
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+}
+
+func TestBreakerTrialCall(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	b.Failure()
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	// One trial call is let through once the open period has passed
+	advance(time.Minute)
+	assert.NoError(t, b.Allow())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	// A failed trial opens the breaker again
+	b.Failure()
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+
+	// A successful one closes it
+	advance(time.Minute)
+	assert.NoError(t, b.Allow())
+	b.Success()
+	assert.Equal(t, StateClosed, b.State())
+	assert.NoError(t, b.Allow())
+	assert.NoError(t, b.Allow())
+}
+
+func TestBreakerAbandonedTrial(t *testing.T) {
+	b, advance := newTestBreaker(1, time.Minute)
+	b.Failure()
+	advance(time.Minute)
+	assert.NoError(t, b.Allow())
+
+	// The trial never reported back; another is allowed after the open period
+	advance(30 * time.Second)
+	assert.ErrorIs(t, b.Allow(), ErrOpen)
+	advance(30 * time.Second)
+	assert.NoError(t, b.Allow())
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b, _ := newTestBreaker(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		b.Failure()
+	}
+	assert.NoError(t, b.Allow())
+	assert.Equal(t, StateClosed, b.State())
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnknownColumn is returned when a filter, search or lookup field is not a
+// column callers may use on the queried model
+var ErrUnknownColumn = errors.New("unknown column")
+
+// Filterable is implemented by models that allow filtering and searching on
+// only some of their columns, keeping secrets such as password hashes out of
+// reach of filter-driven queries. Other models allow all of their columns.
+type Filterable interface {
+	FilterableColumns() []string
+}
+
+// columnSet holds the columns of a model that callers may name
+type columnSet struct {
+	table   string
+	allowed map[string]bool
+}
+
+// columns returns the columns of model that may be looked up, or only the
+// filterable ones when filter is set. model may also be a pointer to a slice of
+// the model.
+func (db *DB) columns(model interface{}, filter bool) (*columnSet, error) {
+	stmt := &gorm.Statement{DB: db.DB}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+
+	set := &columnSet{table: stmt.Schema.Table, allowed: make(map[string]bool)}
+	for _, name := range stmt.Schema.DBNames {
+		set.allowed[name] = true
+	}
+	if !filter {
+		return set, nil
+	}
+
+	if filterable, ok := reflect.New(stmt.Schema.ModelType).Interface().(Filterable); ok {
+		restricted := make(map[string]bool)
+		for _, name := range filterable.FilterableColumns() {
+			restricted[name] = set.allowed[name]
+		}
+		set.allowed = restricted
+	}
+	return set, nil
+}
+
+// column resolves field, optionally qualified with the model's table name, to
+// a quoted column reference
+func (s *columnSet) column(field string) (clause.Column, error) {
+	name := field
+	if table, column, qualified := strings.Cut(field, "."); qualified {
+		if table != s.table {
+			return clause.Column{}, fmt.Errorf("%w %q of table %s", ErrUnknownColumn, field, s.table)
+		}
+		name = column
+	}
+	if !s.allowed[name] {
+		return clause.Column{}, fmt.Errorf("%w %q of table %s", ErrUnknownColumn, field, s.table)
+	}
+	if name != field {
+		return clause.Column{Table: s.table, Name: name}, nil
+	}
+	return clause.Column{Name: name}, nil
+}
+
+// check verifies that every field is a column callers may name
+func (s *columnSet) check(fields ...string) error {
+	for _, field := range fields {
+		if _, err := s.column(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// equal returns the condition field = value
+func (s *columnSet) equal(field string, value interface{}) (clause.Expression, error) {
+	column, err := s.column(field)
+	if err != nil {
+		return nil, err
+	}
+	return clause.Eq{Column: column, Value: value}, nil
+}
+
+// equalAll returns the conditions requiring every field to equal its value
+func (s *columnSet) equalAll(conditions map[string]interface{}) ([]clause.Expression, error) {
+	exprs := make([]clause.Expression, 0, len(conditions))
+	for field, value := range conditions {
+		expr, err := s.equal(field, value)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs, nil
+}
+
+// likeAny returns the condition that any of fields contains text
+func (s *columnSet) likeAny(fields []string, text string) (clause.Expression, error) {
+	exprs := make([]clause.Expression, 0, len(fields))
+	for _, field := range fields {
+		column, err := s.column(field)
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, clause.Like{Column: column, Value: "%" + text + "%"})
+	}
+	return clause.Or(exprs...), nil
+}
@@ -30,5 +30,20 @@ func ConnectWithConfig(cfg *config.Config) (*DB, error) {
 	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
 	sqlDB.SetConnMaxLifetime(time.Duration(cfg.Database.MaxLifetime) * time.Second)
 
-	return NewDB(db), nil
+	wrapped := NewDB(db)
+	err = wrapped.UseResilience(ResilienceOptions{
+		StatementTimeout: time.Duration(cfg.Database.StatementTimeout) * time.Second,
+		BreakerThreshold: cfg.Database.BreakerThreshold,
+		BreakerCooldown:  time.Duration(cfg.Database.BreakerCooldown) * time.Second,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Database.ExplainThreshold > 0 {
+		if err := wrapped.UseExplain(time.Duration(cfg.Database.ExplainThreshold) * time.Millisecond); err != nil {
+			return nil, err
+		}
+	}
+
+	return wrapped, nil
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"fmt"
+
 	"go-blog/internal/models"
 
 	"gorm.io/driver/mysql"
@@ -32,14 +34,143 @@ func Connect(databaseURL string) (*DB, error) {
 
 // Migrate runs database migrations using GORM AutoMigrate
 func Migrate(db *DB) error {
-	return db.AutoMigrate(
+	if err := addUserHandles(db); err != nil {
+		return err
+	}
+	if err := dedupeLikes(db); err != nil {
+		return err
+	}
+
+	err := db.AutoMigrate(
 		&models.User{},
 		&models.Category{},
 		&models.Tag{},
+		&models.TagAlias{},
 		&models.Article{},
+		&models.ArticleRevision{},
+		&models.ShortLink{},
+		&models.ShortLinkClick{},
+		&models.ArticleView{},
+		&models.ArticleRanking{},
+		&models.ArticleLink{},
 		&models.Comment{},
 		&models.Like{},
+		&models.Follow{},
+		&models.SavedSearch{},
+		&models.Notification{},
+		&models.UserSettings{},
+		&models.AuthorReportDelivery{},
+		&models.AuditLog{},
+		&models.Mention{},
+		&models.CommentReport{},
+		&models.CommentSubscription{},
+		&models.ContentFilterRule{},
+		&models.RefreshToken{},
+		&models.Session{},
+		&models.MagicLink{},
+		&models.ArticleMeta{},
+		&models.Page{},
+		&models.HomepageSection{},
+		&models.HomepageSectionArticle{},
+		&models.Policy{},
+		&models.PolicyAcceptance{},
+		&models.JobRun{},
+		&models.QueuedJob{},
 	)
+	if err != nil {
+		return err
+	}
+
+	// SQLite rebuilds altered tables and does not carry the added column's data over
+	if err := backfillUserHandles(db); err != nil {
+		return err
+	}
+	if err := enableMonthlyReports(db); err != nil {
+		return err
+	}
+	if err := createArticleTagsIndex(db); err != nil {
+		return err
+	}
+	return releaseDeletedUniqueValues(db)
+}
+
+// uniqueColumns lists the unique columns of the soft-deletable tables, whose
+// values are released when a row is deleted
+var uniqueColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"users", []string{"username", "handle", "email"}},
+	{"categories", []string{"name", "slug"}},
+	{"tags", []string{"name", "slug"}},
+	{"articles", []string{"slug"}},
+}
+
+// releaseDeletedUniqueValues replaces the unique values of rows soft-deleted
+// before deletes released them, so new rows can take the values over
+func releaseDeletedUniqueValues(db *DB) error {
+	placeholder := db.DeletedPlaceholderExpr("id")
+	for _, unique := range uniqueColumns {
+		for _, column := range unique.columns {
+			query := fmt.Sprintf("UPDATE %s SET %s = %s WHERE deleted_at IS NOT NULL AND %s <> %s",
+				unique.table, column, placeholder, column, placeholder)
+			if err := db.Exec(query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addUserHandles adds and backfills users.handle on existing databases before
+// AutoMigrate creates its unique index, which empty handles would violate
+func addUserHandles(db *DB) error {
+	migrator := db.DB.Migrator()
+	if !migrator.HasTable(&models.User{}) || migrator.HasColumn(&models.User{}, "Handle") {
+		return nil
+	}
+
+	if err := migrator.AddColumn(&models.User{}, "Handle"); err != nil {
+		return err
+	}
+	return backfillUserHandles(db)
+}
+
+// dedupeLikes prepares existing databases for the unique index on likes
+// (user_id, article_id): unliking used to soft-delete the row and likes were
+// only deduplicated by a racy lookup. It drops the soft-deleted rows and the
+// duplicates, then recounts the article like counters, which are maintained
+// incrementally from now on.
+func dedupeLikes(db *DB) error {
+	migrator := db.DB.Migrator()
+	if !migrator.HasTable(&models.Like{}) || migrator.HasIndex(&models.Like{}, "idx_likes_user_article") {
+		return nil
+	}
+
+	if err := db.Exec("DELETE FROM likes WHERE deleted_at IS NOT NULL"); err != nil {
+		return err
+	}
+	// The derived table lets MySQL delete from the table the subquery reads
+	if err := db.Exec("DELETE FROM likes WHERE id NOT IN " +
+		"(SELECT id FROM (SELECT MIN(id) AS id FROM likes GROUP BY user_id, article_id) AS kept)"); err != nil {
+		return err
+	}
+	if !migrator.HasTable(&models.Article{}) {
+		return nil
+	}
+	return db.Exec("UPDATE articles SET like_count = " +
+		"(SELECT COUNT(*) FROM likes WHERE likes.article_id = articles.id)")
+}
+
+// backfillUserHandles derives a handle from the username for users without one
+func backfillUserHandles(db *DB) error {
+	return db.Exec("UPDATE users SET handle = LOWER(username) WHERE handle IS NULL OR handle = ''")
+}
+
+// enableMonthlyReports opts users whose settings predate the monthly report
+// setting in, as for users without stored settings
+func enableMonthlyReports(db *DB) error {
+	return db.Exec("UPDATE user_settings SET email_monthly_report = ? WHERE email_monthly_report IS NULL", true)
 }
 
 // Close closes the database connection
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"testing"
+
+	"go-blog/internal/models"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMigrateBackfillsUserHandles(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	db := NewDB(gormDB)
+
+	// A users table from before handles existed
+	err = db.Exec(`CREATE TABLE users (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		username TEXT NOT NULL UNIQUE,
+		email TEXT NOT NULL UNIQUE,
+		password_hash TEXT NOT NULL,
+		avatar_url TEXT,
+		bio TEXT,
+		role TEXT DEFAULT 'user',
+		created_at DATETIME,
+		updated_at DATETIME,
+		deleted_at DATETIME
+	)`)
+	if err != nil {
+		t.Fatalf("Failed to create legacy table: %v", err)
+	}
+	for _, username := range []string{"Alice", "bob"} {
+		if err := db.Exec("INSERT INTO users (username, email, password_hash) VALUES (?, ?, 'x')", username, username+"@example.com"); err != nil {
+			t.Fatalf("Failed to insert legacy user: %v", err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	var users []models.User
+	if err := db.DB.Order("id").Find(&users).Error; err != nil {
+		t.Fatalf("Failed to load users: %v", err)
+	}
+	if len(users) != 2 || users[0].Handle != "alice" || users[1].Handle != "bob" {
+		t.Errorf("Expected backfilled handles alice and bob, got %+v", users)
+	}
+}
+
+func TestMigrateDedupesLikes(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	db := NewDB(gormDB)
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	// Likes from before the unique index: a soft-deleted unlike and a duplicate
+	statements := []string{
+		"DROP INDEX idx_likes_user_article",
+		"INSERT INTO users (id, username, handle, email, password_hash) VALUES (1, 'reader', 'reader', 'reader@example.com', 'x')",
+		"INSERT INTO articles (id, title, slug, content, author_id, status, like_count) VALUES (1, 'Post', 'post', 'Content', 1, 'published', 5)",
+		"INSERT INTO likes (user_id, article_id, deleted_at) VALUES (1, 1, CURRENT_TIMESTAMP)",
+		"INSERT INTO likes (user_id, article_id) VALUES (1, 1)",
+		"INSERT INTO likes (user_id, article_id) VALUES (1, 1)",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to set up legacy likes (%s): %v", statement, err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	var likes int64
+	db.DB.Unscoped().Model(&models.Like{}).Count(&likes)
+	if likes != 1 {
+		t.Errorf("Expected one like left, got %d", likes)
+	}
+	var article models.Article
+	if err := db.DB.First(&article, 1).Error; err != nil || article.LikeCount != 1 {
+		t.Errorf("Expected the like counter to be recounted to 1, got %d (%v)", article.LikeCount, err)
+	}
+	if err := db.Create(&models.Like{UserID: 1, ArticleID: 1}); !IsDuplicateEntry(err) {
+		t.Errorf("Expected the unique index to reject a second like, got %v", err)
+	}
+}
+
+func TestMigrateReleasesDeletedUniqueValues(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to connect to test database: %v", err)
+	}
+	db := NewDB(gormDB)
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	// Rows soft-deleted before deletes released their unique values
+	statements := []string{
+		"INSERT INTO users (id, username, handle, email, password_hash) VALUES (1, 'author', 'author', 'author@example.com', 'x')",
+		"INSERT INTO tags (id, name, slug, deleted_at) VALUES (3, 'Go', 'go', CURRENT_TIMESTAMP)",
+		"INSERT INTO articles (id, title, slug, content, author_id, status, deleted_at) VALUES (2, 'Post', 'post', 'Content', 1, 'draft', CURRENT_TIMESTAMP)",
+		"INSERT INTO articles (id, title, slug, content, author_id, status) VALUES (4, 'Live', 'live', 'Content', 1, 'draft')",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to set up deleted rows (%s): %v", statement, err)
+		}
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	var slugs []string
+	db.DB.Unscoped().Model(&models.Article{}).Order("id").Pluck("slug", &slugs)
+	if len(slugs) != 2 || slugs[0] != "deleted:2" || slugs[1] != "live" {
+		t.Errorf("Expected only the deleted article's slug to be released, got %v", slugs)
+	}
+	var tag models.Tag
+	if err := db.DB.Unscoped().First(&tag, 3).Error; err != nil || tag.Name != "deleted:3" || tag.Slug != "deleted:3" {
+		t.Errorf("Expected the deleted tag's name and slug to be released, got %q and %q (%v)", tag.Name, tag.Slug, err)
+	}
+	if err := db.Create(&models.Tag{Name: "Go", Slug: "go"}); err != nil {
+		t.Errorf("Expected the released tag name to be reusable, got %v", err)
+	}
+}
+
+func TestMigrateCreatesExpectedIndexes(t *testing.T) {
+	db, err := SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer CleanupTestDB(db)
+
+	if missing := MissingIndexes(db); len(missing) != 0 {
+		t.Fatalf("Expected every index after migrating, missing %v", missing)
+	}
+
+	for _, index := range []Index{{"article_tags", articleTagsTagIndex}, {"articles", "idx_articles_status_published"}} {
+		if err := db.DB.Migrator().DropIndex(index.Table, index.Name); err != nil {
+			t.Fatalf("Failed to drop index: %v", err)
+		}
+	}
+	missing := MissingIndexes(db)
+	if len(missing) != 2 || missing[0].Name != "idx_articles_status_published" || missing[1].Table != "article_tags" {
+		t.Errorf("Expected the dropped indexes to be reported, got %v", missing)
+	}
+
+	if err := Migrate(db); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if missing := MissingIndexes(db); len(missing) != 0 {
+		t.Errorf("Expected migrating again to recreate the indexes, missing %v", missing)
+	}
+}
@@ -0,0 +1,112 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DialectName returns the name of the underlying SQL dialect (mysql, sqlite, postgres)
+func (db *DB) DialectName() string {
+	return db.DB.Dialector.Name()
+}
+
+// YearExpr returns a dialect-specific SQL expression extracting the year from column
+func (db *DB) YearExpr(column string) string {
+	return db.datePartExpr("year", column)
+}
+
+// MonthExpr returns a dialect-specific SQL expression extracting the month from column
+func (db *DB) MonthExpr(column string) string {
+	return db.datePartExpr("month", column)
+}
+
+// DayExpr returns a dialect-specific SQL expression extracting the day of the month from column
+func (db *DB) DayExpr(column string) string {
+	return db.datePartExpr("day", column)
+}
+
+// datePartExpr builds an integer date-part extraction expression for the current dialect
+func (db *DB) datePartExpr(part, column string) string {
+	switch db.DialectName() {
+	case "sqlite":
+		formats := map[string]string{"year": "%Y", "month": "%m", "day": "%d"}
+		return fmt.Sprintf("CAST(strftime('%s', %s) AS INTEGER)", formats[part], column)
+	case "postgres":
+		return fmt.Sprintf("CAST(EXTRACT(%s FROM %s) AS INTEGER)", part, column)
+	default:
+		return fmt.Sprintf("%s(%s)", map[string]string{"year": "YEAR", "month": "MONTH", "day": "DAY"}[part], column)
+	}
+}
+
+// DaysSinceExpr returns a dialect-specific SQL expression of the fractional
+// days from the time in column to the time bound to its one placeholder
+func (db *DB) DaysSinceExpr(column string) string {
+	switch db.DialectName() {
+	case "sqlite":
+		return fmt.Sprintf("(julianday(?) - julianday(%s))", column)
+	case "postgres":
+		return fmt.Sprintf("(EXTRACT(EPOCH FROM (? - %s)) / 86400.0)", column)
+	default:
+		return fmt.Sprintf("(TIMESTAMPDIFF(SECOND, %s, ?) / 86400.0)", column)
+	}
+}
+
+// DeletedPlaceholderExpr returns a SQL expression evaluating to deleted:<id>
+// for the id in idColumn. The ':' is rejected by slug, username, handle, and
+// tag and category name validation, and emails need an '@', so the
+// placeholder never collides with a live value.
+func (db *DB) DeletedPlaceholderExpr(idColumn string) string {
+	if db.DialectName() == "mysql" {
+		return fmt.Sprintf("CONCAT('deleted:', %s)", idColumn)
+	}
+	return fmt.Sprintf("('deleted:' || %s)", idColumn)
+}
+
+// FullTextMatch returns a relevance expression for a full-text search of query over
+// columns, together with its bind arguments. The expression is positive for matching
+// rows. MySQL uses MATCH ... AGAINST and expects a FULLTEXT index covering exactly
+// columns; other dialects score one point per term and column matched with LIKE, with
+// boolean operators stripped from the query.
+func (db *DB) FullTextMatch(columns []string, query string, boolean bool) (string, []interface{}) {
+	if db.DialectName() == "mysql" {
+		mode := "NATURAL LANGUAGE"
+		if boolean {
+			mode = "BOOLEAN"
+		}
+		return fmt.Sprintf("MATCH(%s) AGAINST(? IN %s MODE)", strings.Join(columns, ", "), mode), []interface{}{query}
+	}
+
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`+-*"~<>()@`, r)
+	})
+	if len(terms) == 0 {
+		return "0", nil
+	}
+
+	parts := make([]string, 0, len(terms)*len(columns))
+	args := make([]interface{}, 0, len(terms)*len(columns))
+	for _, term := range terms {
+		pattern := "%" + EscapeLike(term) + "%"
+		for _, column := range columns {
+			parts = append(parts, fmt.Sprintf(`CASE WHEN LOWER(%s) LIKE ? ESCAPE '\' THEN 1 ELSE 0 END`, column))
+			args = append(args, pattern)
+		}
+	}
+	return "(" + strings.Join(parts, " + ") + ")", args
+}
+
+// EscapeLike escapes LIKE wildcards so term is matched literally by a
+// pattern followed by LikeEscape
+func EscapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+// LikeEscape returns the ESCAPE clause for patterns escaped with EscapeLike.
+// MySQL reads a backslash in a string literal as an escape itself.
+func (db *DB) LikeEscape() string {
+	if db.DialectName() == "mysql" {
+		return `ESCAPE '\\'`
+	}
+	return `ESCAPE '\'`
+}
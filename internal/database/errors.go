@@ -0,0 +1,61 @@
+package database
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is returned when a statement finds no record
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when a write violates a unique index
+	ErrDuplicate = errors.New("duplicate entry")
+)
+
+// translateErrorsCallback names the callbacks that translate statement errors
+const translateErrorsCallback = "errors:translate"
+
+// translatedError tags a GORM or driver error with the storage neutral error
+// it stands for, keeping the original in the chain for callers inspecting it
+type translatedError struct {
+	kind error
+	err  error
+}
+
+func (e *translatedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *translatedError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// useErrorTranslation makes statements fail with ErrNotFound and ErrDuplicate
+// instead of GORM's and the drivers' own errors, so callers need not know
+// which database they talk to. Sessions and transactions share the callbacks,
+// so registering them once per connection is enough.
+func useErrorTranslation(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if callbacks.Query().Get(translateErrorsCallback) != nil {
+		return nil
+	}
+	return errors.Join(
+		callbacks.Create().After("gorm:commit_or_rollback_transaction").Register(translateErrorsCallback, translateError),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Register(translateErrorsCallback, translateError),
+		callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register(translateErrorsCallback, translateError),
+		callbacks.Query().After("gorm:after_query").Register(translateErrorsCallback, translateError),
+		callbacks.Raw().After("gorm:raw").Register(translateErrorsCallback, translateError),
+	)
+}
+
+// translateError replaces the statement's error with its translation
+func translateError(db *gorm.DB) {
+	switch err := db.Error; {
+	case err == nil, errors.Is(err, ErrNotFound), errors.Is(err, ErrDuplicate):
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		db.Error = &translatedError{kind: ErrNotFound, err: err}
+	case IsDuplicateEntry(err):
+		db.Error = &translatedError{kind: ErrDuplicate, err: err}
+	}
+}
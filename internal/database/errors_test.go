@@ -0,0 +1,55 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// uniqueModel has a unique column for duplicate entry tests
+type uniqueModel struct {
+	ID   uint   `gorm:"primaryKey"`
+	Code string `gorm:"size:20;uniqueIndex"`
+}
+
+func TestErrorTranslation(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.DB.AutoMigrate(&uniqueModel{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	var model TestModel
+	err := db.GetByID(&model, 42)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing record, got %v", err)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected the GORM error to stay in the chain, got %v", err)
+	}
+	if err := db.DB.Where("id = ?", 42).Find(&[]TestModel{}).Error; err != nil {
+		t.Errorf("Expected an empty result not to be an error, got %v", err)
+	}
+
+	if err := db.Create(&uniqueModel{Code: "a"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	err = db.Create(&uniqueModel{Code: "a"})
+	if !errors.Is(err, ErrDuplicate) || errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrDuplicate for a repeated unique value, got %v", err)
+	}
+
+	// Transactions and raw statements share the translation
+	err = db.Transaction(func(tx *DB) error {
+		return tx.Exec("INSERT INTO unique_models (code) VALUES (?)", "a")
+	})
+	if !errors.Is(err, ErrDuplicate) {
+		t.Errorf("Expected ErrDuplicate from a transaction, got %v", err)
+	}
+
+	// Wrapping a connection again does not register the callbacks twice
+	again := NewDB(db.DB)
+	if err := again.GetByID(&model, 42); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound through a second wrapper, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// explainStartKey is the statement instance key of the time a query started
+const explainStartKey = "explain:start"
+
+// explainer logs the query plans of slow queries
+type explainer struct {
+	threshold time.Duration
+	root      *gorm.DB // explains outside the transaction the query ran in, which may be over
+}
+
+// UseExplain logs the query plan of every SELECT taking longer than threshold,
+// with EXPLAIN on MySQL and EXPLAIN QUERY PLAN on SQLite. Plans are looked up
+// in the background once the query finished; the extra queries are meant for
+// development, not production.
+func (db *DB) UseExplain(threshold time.Duration) error {
+	e := &explainer{threshold: threshold, root: db.DB}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Query().Before("gorm:query").Register("explain:before_query", e.before),
+		callbacks.Query().After("gorm:after_query").Register("explain:after_query", e.after),
+		// The rows of Row and Rows are read after the callbacks ran, so only
+		// the time until the first row counts
+		callbacks.Row().Before("gorm:row").Register("explain:before_row", e.before),
+		callbacks.Row().After("gorm:row").Register("explain:after_row", e.after),
+	)
+}
+
+func (e *explainer) before(db *gorm.DB) {
+	db.InstanceSet(explainStartKey, time.Now())
+}
+
+func (e *explainer) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(explainStartKey)
+	if !ok || db.Error != nil {
+		return
+	}
+	latency := time.Since(value.(time.Time))
+	query := db.Statement.SQL.String()
+	if latency < e.threshold || !isSelect(query) {
+		return
+	}
+
+	ctx := context.Background()
+	if db.Statement.Context != nil {
+		// The plan is looked up after the statement's deadline is released
+		ctx = context.WithoutCancel(db.Statement.Context)
+	}
+	vars := append([]interface{}(nil), db.Statement.Vars...)
+	session := e.root.Session(&gorm.Session{NewDB: true, Context: ctx})
+	go func() {
+		plan, err := explain(session, query, vars)
+		if err != nil {
+			slog.WarnContext(ctx, "Slow query", "latency", latency, "sql", query, "explain_error", err)
+			return
+		}
+		slog.WarnContext(ctx, "Slow query", "latency", latency, "sql", query, "plan", plan)
+	}()
+}
+
+// isSelect reports whether query reads rows, which EXPLAIN leaves untouched
+func isSelect(query string) bool {
+	keyword, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return strings.EqualFold(keyword, "SELECT") || strings.EqualFold(keyword, "WITH")
+}
+
+// explain returns the plan of query, one line per row of the EXPLAIN output
+func explain(db *gorm.DB, query string, vars []interface{}) ([]string, error) {
+	prefix := "EXPLAIN "
+	if db.Dialector.Name() == "sqlite" {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+	rows, err := db.Raw(prefix+query, vars...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.RawBytes, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	var plan []string
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		fields := make([]string, 0, len(columns))
+		for i, column := range columns {
+			if values[i] != nil {
+				fields = append(fields, fmt.Sprintf("%s=%s", column, values[i]))
+			}
+		}
+		plan = append(plan, strings.Join(fields, " "))
+	}
+	return plan, rows.Err()
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer collects log output written from other goroutines
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestUseExplainLogsSlowQueryPlans(t *testing.T) {
+	db := setupTestDB(t)
+	// Every connection to an in-memory database sees a database of its own
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		t.Fatalf("Failed to get connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	var logs lockedBuffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(previous)
+
+	// Every query is slow with a zero threshold
+	if err := db.UseExplain(0); err != nil {
+		t.Fatalf("UseExplain failed: %v", err)
+	}
+	if err := db.Create(&TestModel{Name: "Alice", Age: 30}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	var models []TestModel
+	if err := db.DB.Where("age > ?", 20).Find(&models).Error; err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(logs.String(), "Slow query") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	output := logs.String()
+	if !strings.Contains(output, "FROM `test_models` WHERE age > ?") || !strings.Contains(output, "SCAN test_models") {
+		t.Errorf("Expected the plan of the slow select to be logged, got %q", output)
+	}
+	if strings.Contains(output, "INSERT") || strings.Contains(output, "explain_error") {
+		t.Errorf("Expected only the select to be explained, got %q", output)
+	}
+}
@@ -13,8 +13,12 @@ type DB struct {
 	*gorm.DB
 }
 
-// NewDB creates a new DB wrapper
+// NewDB creates a new DB wrapper whose statements fail with ErrNotFound and
+// ErrDuplicate rather than driver specific errors
 func NewDB(db *gorm.DB) *DB {
+	if err := useErrorTranslation(db); err != nil {
+		panic(fmt.Sprintf("database: failed to register error translation: %v", err))
+	}
 	return &DB{DB: db}
 }
 
@@ -35,6 +39,7 @@ type QueryOptions struct {
 	Filters  map[string]interface{} `json:"filters"`
 	Preloads []string               `json:"preloads"`
 	Search   *SearchOptions         `json:"search"`
+	Select   []string               `json:"select"` // columns to load; all when empty
 }
 
 // SearchOptions represents search configuration
@@ -67,13 +72,22 @@ func (db *DB) GetByID(dest interface{}, id interface{}, preloads ...string) erro
 	return query.First(dest, id).Error
 }
 
-// GetByField retrieves a record by a specific field
+// GetByField retrieves a record by a specific field, which must be a column of dest
 func (db *DB) GetByField(dest interface{}, field string, value interface{}, preloads ...string) error {
+	columns, err := db.columns(dest, false)
+	if err != nil {
+		return err
+	}
+	condition, err := columns.equal(field, value)
+	if err != nil {
+		return err
+	}
+
 	query := db.DB
 	for _, preload := range preloads {
 		query = query.Preload(preload)
 	}
-	return query.Where(field+" = ?", value).First(dest).Error
+	return query.Where(condition).First(dest).Error
 }
 
 // Update updates a record
@@ -91,6 +105,38 @@ func (db *DB) Delete(model interface{}, id interface{}) error {
 	return db.DB.Delete(model, id).Error
 }
 
+// SoftDeleteReleasing soft-deletes the records of model matching query after
+// replacing their values in the unique columns with a deleted:<id>
+// placeholder, so new records can take the values over. It returns how many
+// records were deleted.
+func (db *DB) SoftDeleteReleasing(model interface{}, columns []string, query interface{}, args ...interface{}) (int64, error) {
+	set, err := db.columns(model, false)
+	if err != nil {
+		return 0, err
+	}
+	if err := set.check(columns...); err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	err = db.Transaction(func(tx *DB) error {
+		placeholder := gorm.Expr(tx.DeletedPlaceholderExpr("id"))
+		updates := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			updates[column] = placeholder
+		}
+		if err := tx.DB.Model(model).Where(query, args...).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+
+		// The released values no longer match a query on those columns
+		result := tx.DB.Where(query, args...).Delete(model)
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
 // HardDelete permanently deletes a record
 func (db *DB) HardDelete(model interface{}, id interface{}) error {
 	return db.DB.Unscoped().Delete(model, id).Error
@@ -122,8 +168,67 @@ func (db *DB) Count(model interface{}, conditions ...interface{}) (int64, error)
 	return count, err
 }
 
-// List retrieves records with pagination and filtering
+// List retrieves records with pagination and filtering. dest must be a pointer
+// to a slice of models; prefer ListOf, which needs no reflection.
 func (db *DB) List(dest interface{}, options *QueryOptions) (*PaginationResult, error) {
+	options = normalizeQueryOptions(options)
+
+	// Get the model type for counting
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("dest must be a pointer to slice")
+	}
+
+	sliceType := destValue.Elem().Type()
+	elementType := sliceType.Elem()
+	
+	// Create a new instance for counting
+	modelInstance := reflect.New(elementType).Interface()
+
+	total, err := db.list(modelInstance, dest, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginationResult{
+		Data:       dest,
+		Total:      total,
+		Page:       options.Page,
+		Limit:      options.Limit,
+		TotalPages: totalPages(total, options.Limit),
+	}, nil
+}
+
+// Page is a page of records of type T
+type Page[T any] struct {
+	Items      []T   `json:"items"`
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// ListOf retrieves records of type T with pagination and filtering
+func ListOf[T any](db *DB, options *QueryOptions) (*Page[T], error) {
+	options = normalizeQueryOptions(options)
+
+	items := make([]T, 0)
+	total, err := db.list(new(T), &items, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page[T]{
+		Items:      items,
+		Total:      total,
+		Page:       options.Page,
+		Limit:      options.Limit,
+		TotalPages: totalPages(total, options.Limit),
+	}, nil
+}
+
+// normalizeQueryOptions defaults missing options and clamps the pagination
+func normalizeQueryOptions(options *QueryOptions) *QueryOptions {
 	if options == nil {
 		options = DefaultQueryOptions()
 	}
@@ -138,49 +243,57 @@ func (db *DB) List(dest interface{}, options *QueryOptions) (*PaginationResult,
 	if options.Limit > 100 {
 		options.Limit = 100 // Prevent excessive queries
 	}
+	return options
+}
 
-	// Get the model type for counting
-	destValue := reflect.ValueOf(dest)
-	if destValue.Kind() != reflect.Ptr || destValue.Elem().Kind() != reflect.Slice {
-		return nil, errors.New("dest must be a pointer to slice")
+// list counts the records of model matching options and loads the requested
+// page of them into dest. Filter, search and select fields must be columns of
+// the model; unknown ones are rejected with ErrUnknownColumn.
+func (db *DB) list(model interface{}, dest interface{}, options *QueryOptions) (int64, error) {
+	filterable, err := db.columns(model, true)
+	if err != nil {
+		return 0, err
 	}
 
-	sliceType := destValue.Elem().Type()
-	elementType := sliceType.Elem()
-	
-	// Create a new instance for counting
-	modelInstance := reflect.New(elementType).Interface()
-
 	// Build query
-	query := db.DB.Model(modelInstance)
+	query := db.DB.Model(model)
 
 	// Apply filters
-	for field, value := range options.Filters {
-		query = query.Where(field+" = ?", value)
+	conditions, err := filterable.equalAll(options.Filters)
+	if err != nil {
+		return 0, err
+	}
+	for _, condition := range conditions {
+		query = query.Where(condition)
 	}
 
 	// Apply search if provided
 	if options.Search != nil && options.Search.Query != "" {
 		if len(options.Search.Fields) > 0 {
-			searchQuery := ""
-			searchArgs := make([]interface{}, 0)
-			
-			for i, field := range options.Search.Fields {
-				if i > 0 {
-					searchQuery += " OR "
-				}
-				searchQuery += field + " LIKE ?"
-				searchArgs = append(searchArgs, "%"+options.Search.Query+"%")
+			condition, err := filterable.likeAny(options.Search.Fields, options.Search.Query)
+			if err != nil {
+				return 0, err
 			}
-			
-			query = query.Where(searchQuery, searchArgs...)
+			query = query.Where(condition)
 		}
 	}
 
 	// Count total records
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
-		return nil, err
+		return 0, err
+	}
+
+	// Apply column projection after counting so it does not affect COUNT
+	if len(options.Select) > 0 {
+		selectable, err := db.columns(model, false)
+		if err != nil {
+			return 0, err
+		}
+		if err := selectable.check(options.Select...); err != nil {
+			return 0, err
+		}
+		query = query.Select(options.Select)
 	}
 
 	// Apply preloads
@@ -199,23 +312,27 @@ func (db *DB) List(dest interface{}, options *QueryOptions) (*PaginationResult,
 
 	// Execute query
 	if err := query.Find(dest).Error; err != nil {
-		return nil, err
+		return 0, err
 	}
+	return total, nil
+}
 
-	// Calculate total pages
-	totalPages := int((total + int64(options.Limit) - 1) / int64(options.Limit))
-
-	return &PaginationResult{
-		Data:       dest,
-		Total:      total,
-		Page:       options.Page,
-		Limit:      options.Limit,
-		TotalPages: totalPages,
-	}, nil
+// totalPages returns the number of pages of limit records needed for total
+func totalPages(total int64, limit int) int {
+	return int((total + int64(limit) - 1) / int64(limit))
 }
 
-// FindWithConditions finds records with complex conditions
+// FindWithConditions finds records whose filterable columns equal the given values
 func (db *DB) FindWithConditions(dest interface{}, conditions map[string]interface{}, preloads ...string) error {
+	columns, err := db.columns(dest, true)
+	if err != nil {
+		return err
+	}
+	exprs, err := columns.equalAll(conditions)
+	if err != nil {
+		return err
+	}
+
 	query := db.DB
 	
 	// Apply preloads
@@ -224,8 +341,8 @@ func (db *DB) FindWithConditions(dest interface{}, conditions map[string]interfa
 	}
 	
 	// Apply conditions
-	for field, value := range conditions {
-		query = query.Where(field+" = ?", value)
+	for _, expr := range exprs {
+		query = query.Where(expr)
 	}
 	
 	return query.Find(dest).Error
@@ -290,7 +407,7 @@ func (db *DB) Exec(sql string, values ...interface{}) error {
 
 // IsRecordNotFound checks if error is record not found
 func IsRecordNotFound(err error) bool {
-	return errors.Is(err, gorm.ErrRecordNotFound)
+	return errors.Is(err, ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound)
 }
 
 // IsDuplicateEntry checks if error is duplicate entry
@@ -298,7 +415,12 @@ func IsDuplicateEntry(err error) bool {
 	if err == nil {
 		return false
 	}
-	return contains(err.Error(), "Duplicate entry") || contains(err.Error(), "duplicate key")
+	if errors.Is(err, ErrDuplicate) || errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
+	}
+	// MySQL, PostgreSQL and SQLite report violations in their own words
+	return contains(err.Error(), "Duplicate entry") || contains(err.Error(), "duplicate key") ||
+		contains(err.Error(), "UNIQUE constraint failed")
 }
 
 // contains checks if string contains substring (case insensitive)
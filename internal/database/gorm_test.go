@@ -1,6 +1,8 @@
 package database
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"gorm.io/driver/sqlite"
@@ -14,7 +16,7 @@ type TestModel struct {
 	Age  int
 }
 
-func setupTestDB(t *testing.T) *DB {
+func setupTestDB(t testing.TB) *DB {
 	// Use in-memory SQLite for testing
 	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	if err != nil {
@@ -270,6 +272,132 @@ func TestListWithFilters(t *testing.T) {
 	}
 }
 
+func TestListOf(t *testing.T) {
+	db := setupTestDB(t)
+
+	for _, model := range []*TestModel{
+		{Name: "Typed1", Age: 25},
+		{Name: "Typed2", Age: 30},
+		{Name: "Typed3", Age: 35},
+	} {
+		db.Create(model)
+	}
+
+	page, err := ListOf[TestModel](db, &QueryOptions{
+		Page:    2,
+		Limit:   2,
+		OrderBy: "age ASC",
+	})
+	if err != nil {
+		t.Fatalf("ListOf failed: %v", err)
+	}
+
+	if page.Total != 3 || page.TotalPages != 2 || page.Page != 2 {
+		t.Errorf("Expected page 2 of 2 with total 3, got %+v", page)
+	}
+	if len(page.Items) != 1 || page.Items[0].Age != 35 {
+		t.Errorf("Expected the oldest model on the second page, got %+v", page.Items)
+	}
+
+	// An empty page has no items rather than a nil slice
+	page, err = ListOf[TestModel](db, &QueryOptions{Filters: map[string]interface{}{"age": 99}})
+	if err != nil {
+		t.Fatalf("ListOf with filters failed: %v", err)
+	}
+	if page.Items == nil || len(page.Items) != 0 || page.Total != 0 {
+		t.Errorf("Expected an empty page, got %+v", page)
+	}
+}
+
+// SecretModel restricts filtering to some of its columns
+type SecretModel struct {
+	ID     uint   `gorm:"primaryKey"`
+	Name   string `gorm:"size:100"`
+	Secret string `gorm:"size:100"`
+}
+
+func (SecretModel) FilterableColumns() []string {
+	return []string{"id", "name"}
+}
+
+func TestListRejectsUnknownColumns(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.AutoMigrate(&SecretModel{}); err != nil {
+		t.Fatalf("Failed to migrate secret model: %v", err)
+	}
+	db.Create(&TestModel{Name: "Safe", Age: 25})
+	db.Create(&SecretModel{Name: "Safe", Secret: "hunter2"})
+
+	tests := []struct {
+		name    string
+		list    func() error
+		allowed bool
+	}{
+		{"filter column", func() error {
+			_, err := ListOf[TestModel](db, &QueryOptions{Filters: map[string]interface{}{"age": 25}})
+			return err
+		}, true},
+		{"table qualified column", func() error {
+			_, err := ListOf[TestModel](db, &QueryOptions{Filters: map[string]interface{}{"test_models.age": 25}})
+			return err
+		}, true},
+		{"injected filter", func() error {
+			_, err := ListOf[TestModel](db, &QueryOptions{Filters: map[string]interface{}{"1 = 1 OR age": 25}})
+			return err
+		}, false},
+		{"other table", func() error {
+			_, err := ListOf[TestModel](db, &QueryOptions{Filters: map[string]interface{}{"users.age": 25}})
+			return err
+		}, false},
+		{"injected search field", func() error {
+			_, err := ListOf[TestModel](db, &QueryOptions{Search: &SearchOptions{Query: "a", Fields: []string{"name) OR (1"}}})
+			return err
+		}, false},
+		{"unknown select", func() error {
+			_, err := ListOf[TestModel](db, &QueryOptions{Select: []string{"name", "(SELECT 1)"}})
+			return err
+		}, false},
+		{"filterable column", func() error {
+			_, err := ListOf[SecretModel](db, &QueryOptions{Filters: map[string]interface{}{"name": "Safe"}})
+			return err
+		}, true},
+		{"column outside the filterable ones", func() error {
+			_, err := ListOf[SecretModel](db, &QueryOptions{Filters: map[string]interface{}{"secret": "hunter2"}})
+			return err
+		}, false},
+		{"search outside the filterable ones", func() error {
+			var results []SecretModel
+			_, err := db.List(&results, &QueryOptions{Search: &SearchOptions{Query: "hunter", Fields: []string{"secret"}}})
+			return err
+		}, false},
+		{"find with unknown condition", func() error {
+			var results []TestModel
+			return db.FindWithConditions(&results, map[string]interface{}{"age = 25 OR 1": 1})
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.list()
+			if tt.allowed && err != nil {
+				t.Errorf("Expected the query to succeed, got %v", err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrUnknownColumn) {
+				t.Errorf("Expected ErrUnknownColumn, got %v", err)
+			}
+		})
+	}
+
+	// Lookups by a single field may use any column
+	var secret SecretModel
+	if err := db.GetByField(&secret, "secret", "hunter2"); err != nil {
+		t.Errorf("Expected lookup by any column to succeed, got %v", err)
+	}
+	if err := db.GetByField(&secret, "secret = secret OR 1", 1); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Expected ErrUnknownColumn for an injected lookup field, got %v", err)
+	}
+}
+
 func TestTransaction(t *testing.T) {
 	db := setupTestDB(t)
 
@@ -349,4 +477,81 @@ func TestIsRecordNotFound(t *testing.T) {
 	if !IsRecordNotFound(err) {
 		t.Error("Expected IsRecordNotFound to return true for non-existent record")
 	}
-}
\ No newline at end of file
+}
+
+func TestIsDuplicateEntry(t *testing.T) {
+	db := setupTestDB(t)
+	if err := db.Exec("CREATE UNIQUE INDEX idx_test_models_name ON test_models(name)"); err != nil {
+		t.Fatalf("Failed to create index: %v", err)
+	}
+
+	if err := db.Create(&TestModel{Name: "Unique"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	err := db.Create(&TestModel{Name: "Unique"})
+	if !IsDuplicateEntry(err) {
+		t.Errorf("Expected IsDuplicateEntry to return true for %v", err)
+	}
+	if IsDuplicateEntry(nil) || IsDuplicateEntry(gorm.ErrRecordNotFound) {
+		t.Error("Expected IsDuplicateEntry to return false for other errors")
+	}
+}
+
+func TestDatePartExpressions(t *testing.T) {
+	db := setupTestDB(t)
+
+	var result struct {
+		Year  int
+		Month int
+		Day   int
+	}
+	query := "SELECT " + db.YearExpr("'2023-07-15 10:00:00'") + " AS year, " +
+		db.MonthExpr("'2023-07-15 10:00:00'") + " AS month, " +
+		db.DayExpr("'2023-07-15 10:00:00'") + " AS day"
+	if err := db.Raw(query).Scan(&result).Error; err != nil {
+		t.Fatalf("Date part query failed: %v", err)
+	}
+
+	if result.Year != 2023 || result.Month != 7 || result.Day != 15 {
+		t.Errorf("Expected 2023-07-15, got %d-%d-%d", result.Year, result.Month, result.Day)
+	}
+}
+
+// seedBenchmarkModels creates enough rows for list benchmarks to fill their pages
+func seedBenchmarkModels(b *testing.B, db *DB) {
+	models := make([]TestModel, 200)
+	for i := range models {
+		models[i] = TestModel{Name: fmt.Sprintf("Model %d", i), Age: i % 80}
+	}
+	if err := db.DB.CreateInBatches(models, 100).Error; err != nil {
+		b.Fatalf("Failed to seed models: %v", err)
+	}
+}
+
+// BenchmarkList measures the reflection path of List, to compare with ListOf
+func BenchmarkList(b *testing.B) {
+	db := setupTestDB(b)
+	seedBenchmarkModels(b, db)
+	options := &QueryOptions{Page: 2, Limit: 20, OrderBy: "age DESC", Search: &SearchOptions{Query: "Model", Fields: []string{"name"}}}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		var models []TestModel
+		if _, err := db.List(&models, options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkListOf(b *testing.B) {
+	db := setupTestDB(b)
+	seedBenchmarkModels(b, db)
+	options := &QueryOptions{Page: 2, Limit: 20, OrderBy: "age DESC", Search: &SearchOptions{Query: "Model", Fields: []string{"name"}}}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := ListOf[TestModel](db, options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
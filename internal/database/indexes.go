@@ -0,0 +1,47 @@
+package database
+
+import "fmt"
+
+// Index names an index list and search queries rely on
+type Index struct {
+	Table string
+	Name  string
+}
+
+// ExpectedIndexes are the indexes the hot queries need. Model tags declare
+// most of them; the article_tags one is created by Migrate since GORM owns the
+// join table.
+var ExpectedIndexes = []Index{
+	{"articles", "idx_articles_status_published"}, // published listings by date
+	{"articles", "idx_articles_author_status"},    // author pages and statistics
+	{"articles", "idx_articles_category_status"},  // category listings
+	{"article_tags", articleTagsTagIndex},         // tag listings
+	{"comments", "idx_comments_article_parent"},   // comment threads
+	{"likes", "idx_likes_user_article"},           // like status and toggling
+}
+
+// articleTagsTagIndex lets tag listings find articles by tag; the primary key
+// of the join table leads with article_id
+const articleTagsTagIndex = "idx_article_tags_tag"
+
+// createArticleTagsIndex creates the tag index of the article_tags join table
+func createArticleTagsIndex(db *DB) error {
+	migrator := db.DB.Migrator()
+	if !migrator.HasTable("article_tags") || migrator.HasIndex("article_tags", articleTagsTagIndex) {
+		return nil
+	}
+	return db.Exec(fmt.Sprintf("CREATE INDEX %s ON article_tags (tag_id)", articleTagsTagIndex))
+}
+
+// MissingIndexes returns the expected indexes the database lacks, such as ones
+// dropped by hand or left out by a failed migration
+func MissingIndexes(db *DB) []Index {
+	migrator := db.DB.Migrator()
+	var missing []Index
+	for _, index := range ExpectedIndexes {
+		if !migrator.HasIndex(index.Table, index.Name) {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
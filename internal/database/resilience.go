@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"time"
+
+	"go-blog/internal/breaker"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// ErrUnavailable is returned without querying the database while its circuit
+// breaker is open
+var ErrUnavailable = errors.New("database temporarily unavailable")
+
+// statementKey is the statement instance key of the state kept between the
+// resilience callbacks
+const statementKey = "resilience:statement"
+
+// ResilienceOptions configures statement timeouts and the circuit breaker
+type ResilienceOptions struct {
+	StatementTimeout time.Duration // run time after which a statement is cancelled, 0 disables
+	BreakerThreshold int           // consecutive connection failures or timeouts that open the breaker, 0 disables
+	BreakerCooldown  time.Duration // how long the open breaker refuses statements before letting one through
+}
+
+// resilience holds the callbacks guarding each statement
+type resilience struct {
+	timeout time.Duration
+	breaker *breaker.Breaker
+}
+
+// statementState is what the before callback hands to the after callback
+type statementState struct {
+	parent  context.Context
+	cancel  context.CancelFunc
+	allowed bool
+}
+
+// UseResilience bounds the run time of every statement and stops sending
+// statements to a database that keeps timing out or dropping connections, so
+// slow queries fail fast with ErrUnavailable instead of piling up requests
+func (db *DB) UseResilience(options ResilienceOptions) error {
+	r := &resilience{
+		timeout: options.StatementTimeout,
+		breaker: breaker.New(options.BreakerThreshold, options.BreakerCooldown),
+	}
+
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Create().Before("gorm:begin_transaction").Register("resilience:before_create", r.before),
+		callbacks.Create().After("gorm:commit_or_rollback_transaction").Register("resilience:after_create", r.after(true)),
+		callbacks.Update().Before("gorm:begin_transaction").Register("resilience:before_update", r.before),
+		callbacks.Update().After("gorm:commit_or_rollback_transaction").Register("resilience:after_update", r.after(true)),
+		callbacks.Delete().Before("gorm:begin_transaction").Register("resilience:before_delete", r.before),
+		callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register("resilience:after_delete", r.after(true)),
+		callbacks.Query().Before("gorm:query").Register("resilience:before_query", r.before),
+		callbacks.Query().After("gorm:after_query").Register("resilience:after_query", r.after(true)),
+		callbacks.Raw().Before("gorm:raw").Register("resilience:before_raw", r.before),
+		callbacks.Raw().After("gorm:raw").Register("resilience:after_raw", r.after(true)),
+		// The rows of Row and Rows are read after the callbacks ran, so their
+		// context is left to expire instead of being cancelled
+		callbacks.Row().Before("gorm:row").Register("resilience:before_row", r.before),
+		callbacks.Row().After("gorm:row").Register("resilience:after_row", r.after(false)),
+	)
+}
+
+// before refuses the statement while the breaker is open and gives it a deadline
+func (r *resilience) before(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	if err := r.breaker.Allow(); err != nil {
+		db.AddError(ErrUnavailable)
+		return
+	}
+
+	state := &statementState{parent: db.Statement.Context, allowed: true}
+	if r.timeout > 0 {
+		parent := state.parent
+		if parent == nil {
+			parent = context.Background()
+		}
+		db.Statement.Context, state.cancel = context.WithTimeout(parent, r.timeout)
+	}
+	db.InstanceSet(statementKey, state)
+}
+
+// after releases the statement's deadline and records the outcome with the breaker
+func (r *resilience) after(cancel bool) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(statementKey)
+		if !ok {
+			return
+		}
+		state := value.(*statementState)
+		db.InstanceSet(statementKey, &statementState{})
+		if !state.allowed {
+			return
+		}
+
+		// Chained calls reuse the statement, so the next one must not inherit
+		// this deadline
+		if state.cancel != nil {
+			if cancel {
+				state.cancel()
+			}
+			db.Statement.Context = state.parent
+		}
+
+		if isBackendFailure(db.Error) {
+			r.breaker.Failure()
+		} else {
+			r.breaker.Success()
+		}
+	}
+}
+
+// isBackendFailure reports whether err means the database is slow or
+// unreachable, as opposed to rejecting the statement itself
+func isBackendFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.As(err, &netErr)
+}
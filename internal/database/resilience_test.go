@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatementTimeoutOpensBreaker(t *testing.T) {
+	db := setupTestDB(t)
+	// Every statement times out before it reaches the database
+	err := db.UseResilience(ResilienceOptions{
+		StatementTimeout: time.Nanosecond,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("UseResilience failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := db.Create(&TestModel{Name: "Slow"}); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected statement %d to time out, got %v", i+1, err)
+		}
+	}
+
+	var result TestModel
+	if err := db.GetByID(&result, 1); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected the open breaker to refuse the statement, got %v", err)
+	}
+}
+
+func TestResilienceKeepsHealthyStatementsWorking(t *testing.T) {
+	db := setupTestDB(t)
+	err := db.UseResilience(ResilienceOptions{
+		StatementTimeout: time.Minute,
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("UseResilience failed: %v", err)
+	}
+
+	for _, model := range []*TestModel{{Name: "Alice", Age: 30}, {Name: "Bob", Age: 40}} {
+		if err := db.Create(model); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	// Missing rows are not a database failure
+	var missing TestModel
+	if err := db.GetByID(&missing, 999); !IsRecordNotFound(err) {
+		t.Fatalf("Expected record not found, got %v", err)
+	}
+
+	// Chained calls share a statement and must not inherit a cancelled deadline
+	var total int64
+	var models []TestModel
+	query := db.DB.Model(&TestModel{}).Where("age > ?", 20)
+	if err := query.Count(&total).Error; err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		t.Fatalf("Find after Count failed: %v", err)
+	}
+	if total != 2 || len(models) != 2 {
+		t.Errorf("Expected 2 models, got total %d and %d rows", total, len(models))
+	}
+
+	rows, err := db.DB.Model(&TestModel{}).Select("name").Rows()
+	if err != nil {
+		t.Fatalf("Rows failed: %v", err)
+	}
+	defer rows.Close()
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	if err := rows.Err(); err != nil || count != 2 {
+		t.Errorf("Expected to read 2 rows, got %d (%v)", count, err)
+	}
+}
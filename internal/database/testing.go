@@ -0,0 +1,64 @@
+package database
+
+import (
+	"os"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SetupTestDB opens and migrates a database for integration tests. When
+// TEST_DATABASE_URL is set it must point at a disposable MySQL database, such
+// as the container testenv.Main starts with TEST_DOCKER set; otherwise an
+// in-memory SQLite database is used.
+func SetupTestDB() (*DB, error) {
+	var dialector gorm.Dialector
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		dialector = mysql.Open(dsn)
+	} else {
+		dialector = sqlite.Open("file::memory:")
+	}
+
+	gormDB, err := gorm.Open(dialector, &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A single connection keeps every query on the same in-memory database
+	sqlDB, err := gormDB.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	db := NewDB(gormDB)
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// CleanupTestDB drops the migrated tables and closes a database opened by SetupTestDB
+func CleanupTestDB(db *DB) error {
+	if db.DialectName() == "mysql" {
+		db.DB.Exec("SET FOREIGN_KEY_CHECKS = 0")
+	}
+
+	tables, err := db.DB.Migrator().GetTables()
+	if err == nil {
+		for _, table := range tables {
+			if dropErr := db.DB.Migrator().DropTable(table); dropErr != nil && err == nil {
+				err = dropErr
+			}
+		}
+	}
+	if closeErr := db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,170 @@
+// Package diff computes word-level differences between texts with Myers'
+// shortest edit script algorithm. Texts are split into words and the
+// whitespace between them, so the blocks of a difference join back into either
+// text.
+package diff
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxEdits bounds the search for the shortest edit script, whose time and
+// memory grow with the square of the number of edits. Texts further apart
+// are reported as replaced.
+const maxEdits = 1000
+
+// tokenPattern splits text into words and runs of whitespace
+var tokenPattern = regexp.MustCompile(`\s+|\S+`)
+
+// Op says what happened to the text of a block
+type Op string
+
+const (
+	OpEqual  Op = "equal"  // in both texts
+	OpInsert Op = "insert" // only in the new text
+	OpDelete Op = "delete" // only in the old text
+)
+
+// Block is a run of text kept, added or removed between two texts
+type Block struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Words returns the blocks turning text a into text b
+func Words(a, b string) []Block {
+	return compare(tokenPattern.FindAllString(a, -1), tokenPattern.FindAllString(b, -1))
+}
+
+// Count returns how many words the blocks with op contain
+func Count(blocks []Block, op Op) int {
+	count := 0
+	for _, block := range blocks {
+		if block.Op == op {
+			count += len(strings.Fields(block.Text))
+		}
+	}
+	return count
+}
+
+// builder collects tokens into blocks, merging consecutive tokens with the same op
+type builder struct {
+	blocks []Block
+}
+
+func (b *builder) add(op Op, tokens ...string) {
+	if len(tokens) == 0 {
+		return
+	}
+	text := strings.Join(tokens, "")
+	if n := len(b.blocks); n > 0 && b.blocks[n-1].Op == op {
+		b.blocks[n-1].Text += text
+		return
+	}
+	b.blocks = append(b.blocks, Block{Op: op, Text: text})
+}
+
+// compare diffs token lists, leaving the common prefix and suffix out of the search
+func compare(a, b []string) []Block {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	result := &builder{}
+	result.add(OpEqual, a[:prefix]...)
+	result.shortestEdit(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	result.add(OpEqual, a[len(a)-suffix:]...)
+	return result.blocks
+}
+
+// shortestEdit adds the blocks of the shortest edit script turning a into b
+func (b *builder) shortestEdit(a, c []string) {
+	n, m := len(a), len(c)
+	if n == 0 || m == 0 {
+		b.add(OpDelete, a...)
+		b.add(OpInsert, c...)
+		return
+	}
+
+	// v[offset+k] is the furthest x reached on diagonal k = x - y; trace keeps
+	// the diagonals -d..d of v after each round d for backtracking
+	offset := n + m
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+	for d := 0; d <= offset && d <= maxEdits; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // down: insert c[y]
+			} else {
+				x = v[offset+k-1] + 1 // right: delete a[x]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == c[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+				b.backtrack(a, c, trace)
+				return
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	b.add(OpDelete, a...)
+	b.add(OpInsert, c...)
+}
+
+// backtrack walks the rounds of the search back from the end of both texts
+// and adds the edits in order
+func (b *builder) backtrack(a, c []string, trace [][]int) {
+	type edit struct {
+		op    Op
+		token string
+	}
+	var edits []edit // in reverse
+
+	x, y := len(a), len(c)
+	for d := len(trace) - 1; d > 0; d-- {
+		previous := trace[d-1] // diagonal k of round d-1 is at previous[k+d-1]
+		k := x - y
+		var previousK, startX int
+		if k == -d || (k != d && previous[k-1+d-1] < previous[k+1+d-1]) {
+			previousK = k + 1
+			startX = previous[previousK+d-1]
+		} else {
+			previousK = k - 1
+			startX = previous[previousK+d-1] + 1
+		}
+
+		for x > startX {
+			edits = append(edits, edit{OpEqual, a[x-1]})
+			x--
+			y--
+		}
+		previousX := previous[previousK+d-1]
+		if previousK == k+1 {
+			edits = append(edits, edit{OpInsert, c[y-1]})
+		} else {
+			edits = append(edits, edit{OpDelete, a[x-1]})
+		}
+		x, y = previousX, previousX-previousK
+	}
+	for x > 0 {
+		edits = append(edits, edit{OpEqual, a[x-1]})
+		x--
+	}
+
+	for i := len(edits) - 1; i >= 0; i-- {
+		b.add(edits[i].op, edits[i].token)
+	}
+}
@@ -0,0 +1,66 @@
+package diff
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// join returns the text the blocks keep from the old (OpDelete) or new (OpInsert) text
+func join(blocks []Block, side Op) string {
+	var text strings.Builder
+	for _, block := range blocks {
+		if block.Op == OpEqual || block.Op == side {
+			text.WriteString(block.Text)
+		}
+	}
+	return text.String()
+}
+
+func TestWords(t *testing.T) {
+	blocks := Words("The quick brown fox jumps", "The slow brown fox jumps high")
+	assert.Equal(t, []Block{
+		{Op: OpEqual, Text: "The "},
+		{Op: OpDelete, Text: "quick"},
+		{Op: OpInsert, Text: "slow"},
+		{Op: OpEqual, Text: " brown fox jumps"},
+		{Op: OpInsert, Text: " high"},
+	}, blocks)
+	assert.Equal(t, 2, Count(blocks, OpInsert))
+	assert.Equal(t, 1, Count(blocks, OpDelete))
+
+	assert.Equal(t, []Block{{Op: OpEqual, Text: "same text"}}, Words("same text", "same text"))
+	assert.Empty(t, Words("", ""))
+	assert.Equal(t, []Block{{Op: OpInsert, Text: "new"}}, Words("", "new"))
+}
+
+func TestWordsRebuildsBothTexts(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	words := []string{"a", "b", "c", "d", " ", "\n"}
+	text := func() string {
+		var text strings.Builder
+		for i := random.Intn(30); i > 0; i-- {
+			text.WriteString(words[random.Intn(len(words))])
+		}
+		return text.String()
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b := text(), text()
+		blocks := Words(a, b)
+		assert.Equal(t, a, join(blocks, OpDelete))
+		assert.Equal(t, b, join(blocks, OpInsert))
+	}
+}
+
+func TestWordsReplacesTextsTooFarApart(t *testing.T) {
+	a := strings.Repeat("old ", maxEdits)
+	b := strings.Repeat("new ", maxEdits)
+	assert.Equal(t, []Block{
+		{Op: OpDelete, Text: strings.TrimSuffix(a, " ")},
+		{Op: OpInsert, Text: strings.TrimSuffix(b, " ")},
+		{Op: OpEqual, Text: " "},
+	}, Words(a, b))
+}
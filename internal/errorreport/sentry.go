@@ -0,0 +1,51 @@
+package errorreport
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Sentry reports recovered panics to Sentry. Request cookies and credentials
+// are not sent. A nil *Sentry reports nothing, so it can be wired in whether or
+// not reporting is configured.
+type Sentry struct {
+	client *sentry.Client
+}
+
+// NewSentry creates a reporter sending events to the project of dsn, tagged
+// with environment
+func NewSentry(dsn, environment string) (*Sentry, error) {
+	return newSentry(sentry.ClientOptions{Dsn: dsn, Environment: environment})
+}
+
+func newSentry(options sentry.ClientOptions) (*Sentry, error) {
+	client, err := sentry.NewClient(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sentry client: %w", err)
+	}
+	return &Sentry{client: client}, nil
+}
+
+// ReportPanic sends a panic recovered while serving r, tagged with its request ID
+func (s *Sentry) ReportPanic(r *http.Request, recovered any, requestID string) {
+	if s == nil {
+		return
+	}
+	scope := sentry.NewScope()
+	scope.SetRequest(r)
+	if requestID != "" {
+		scope.SetTag("request_id", requestID)
+	}
+	sentry.NewHub(s.client, scope).RecoverWithContext(r.Context(), recovered)
+}
+
+// Flush waits up to timeout for queued events to be sent
+func (s *Sentry) Flush(timeout time.Duration) bool {
+	if s == nil {
+		return true
+	}
+	return s.client.Flush(timeout)
+}
@@ -0,0 +1,58 @@
+package errorreport
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTransport keeps the events sent instead of delivering them
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Configure(sentry.ClientOptions)        {}
+func (t *recordingTransport) Flush(time.Duration) bool              { return true }
+func (t *recordingTransport) FlushWithContext(context.Context) bool { return true }
+func (t *recordingTransport) Close()                                {}
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func TestReportPanic(t *testing.T) {
+	transport := &recordingTransport{}
+	reporter, err := newSentry(sentry.ClientOptions{Dsn: "https://key@sentry.example.com/1", Transport: transport})
+	require.NoError(t, err)
+
+	r := httptest.NewRequest("GET", "/api/articles/hello", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	reporter.ReportPanic(r, "boom", "req-1")
+
+	require.Len(t, transport.events, 1)
+	event := transport.events[0]
+	assert.Equal(t, "req-1", event.Tags["request_id"])
+	assert.Equal(t, "boom", event.Message)
+	require.NotNil(t, event.Request)
+	assert.Contains(t, event.Request.URL, "/api/articles/hello")
+	assert.NotEqual(t, "Bearer secret", event.Request.Headers["Authorization"])
+}
+
+func TestNilSentryReportsNothing(t *testing.T) {
+	var reporter *Sentry
+	reporter.ReportPanic(httptest.NewRequest("GET", "/", nil), "boom", "")
+	assert.True(t, reporter.Flush(time.Second))
+}
+
+func TestNewSentryRejectsInvalidDSN(t *testing.T) {
+	_, err := NewSentry("not a dsn", "")
+	assert.Error(t, err)
+}
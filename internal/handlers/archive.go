@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ArchiveHandler struct {
+	archiveService *services.ArchiveService
+}
+
+// NewArchiveHandler creates a new archive handler
+func NewArchiveHandler(archiveService *services.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveService: archiveService,
+	}
+}
+
+// GetArchive handles archive listing grouped by year and month
+// GET /api/archive
+func (h *ArchiveHandler) GetArchive(c *gin.Context) {
+	archive, err := h.archiveService.GetArchive()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve archive"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Archive retrieved successfully", archive))
+}
+
+// GetArchiveByMonth handles listing articles published in a given month
+// GET /api/archive/:year/:month
+func (h *ArchiveHandler) GetArchiveByMonth(c *gin.Context) {
+	year, month, ok := archiveMonthParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.archiveService.ValidateArchiveRequest(year, month); err != nil {
+		respondError(c, err, "Invalid archive request")
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	articles, total, err := h.archiveService.GetArchiveByMonth(year, month, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve articles")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Articles retrieved successfully", articleList(c, articles), &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Filters:    map[string]interface{}{"year": year, "month": month},
+		Sort:       "-published_at",
+	}))
+}
+
+// GetArchiveByDay handles listing articles published on a given day
+// GET /api/archive/:year/:month/:day
+func (h *ArchiveHandler) GetArchiveByDay(c *gin.Context) {
+	year, month, ok := archiveMonthParams(c)
+	if !ok {
+		return
+	}
+
+	day, err := strconv.Atoi(c.Param("day"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid day"))
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	articles, total, err := h.archiveService.GetArchiveByDay(year, month, day, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve articles")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Articles retrieved successfully", articleList(c, articles), &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Filters:    map[string]interface{}{"year": year, "month": month, "day": day},
+		Sort:       "-published_at",
+	}))
+}
+
+// GetCalendar handles listing the days of a month that have published articles
+// GET /api/archive/:year/:month/calendar
+func (h *ArchiveHandler) GetCalendar(c *gin.Context) {
+	year, month, ok := archiveMonthParams(c)
+	if !ok {
+		return
+	}
+
+	calendar, err := h.archiveService.GetCalendar(year, month)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve archive calendar")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Archive calendar retrieved successfully", calendar))
+}
+
+// archiveMonthParams parses the year and month path parameters, responding
+// with 400 when either is not a number
+func archiveMonthParams(c *gin.Context) (year, month int, ok bool) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid year"))
+		return 0, 0, false
+	}
+
+	month, err = strconv.Atoi(c.Param("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid month"))
+		return 0, 0, false
+	}
+
+	return year, month, true
+}
+
+// GetStatistics handles archive summary statistics
+// GET /api/archive/stats
+func (h *ArchiveHandler) GetStatistics(c *gin.Context) {
+	stats, err := h.archiveService.GetArchiveStatistics()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve archive statistics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Archive statistics retrieved successfully", stats))
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Meta handles listing the custom fields of an article
+// GET /api/articles/:id/meta
+func (h *ArticleHandler) Meta(c *gin.Context) {
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	fields, err := h.articleService.GetMeta(articleID, optionalUser(c))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve custom fields")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Custom fields retrieved successfully", fields))
+}
+
+// SetMeta handles setting custom fields of an article; fields not listed keep
+// their value
+// PUT /api/articles/:id/meta
+func (h *ArticleHandler) SetMeta(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	var req services.SetArticleMetaRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	fields, err := h.articleService.SetMeta(articleID, user, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update custom fields")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Custom fields updated successfully", fields))
+}
+
+// DeleteMeta handles removing a custom field of an article
+// DELETE /api/articles/:id/meta/:key
+func (h *ArticleHandler) DeleteMeta(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	if err := h.articleService.DeleteMeta(articleID, user, c.Param("key")); err != nil {
+		respondError(c, err, "Failed to delete custom field")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Custom field deleted successfully", nil))
+}
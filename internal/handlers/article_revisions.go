@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Revisions handles listing the revisions of one of the user's articles
+// GET /api/articles/:id/revisions
+func (h *ArticleHandler) Revisions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	revisions, err := h.articleService.ListRevisions(articleID, user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Revisions retrieved successfully", revisions))
+}
+
+// CompareRevisions handles the word-level diff between two revisions of one of
+// the user's articles
+// GET /api/articles/:id/revisions/:a/compare/:b
+func (h *ArticleHandler) CompareRevisions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+	from, ok := parseIDParam(c, "a", "revision")
+	if !ok {
+		return
+	}
+	to, ok := parseIDParam(c, "b", "revision")
+	if !ok {
+		return
+	}
+
+	comparison, err := h.articleService.CompareRevisions(articleID, user.ID, from, to)
+	if err != nil {
+		respondError(c, err, "Failed to compare revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Revisions compared successfully", comparison))
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateSettings handles changing whether an article takes comments and likes
+// and whether it is kept out of search engines
+// PUT /api/articles/:id/settings
+func (h *ArticleHandler) UpdateSettings(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	var req services.ArticleSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	article, err := h.articleService.UpdateSettings(articleID, user, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update article settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Article settings updated successfully", article.Summary()))
+}
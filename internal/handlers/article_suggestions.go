@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Suggestions handles suggesting an excerpt, tags and a meta description for
+// one of the user's articles; nothing is saved until the author updates the
+// article with them
+// POST /api/articles/:id/suggestions
+func (h *ArticleHandler) Suggestions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	suggestions, err := h.articleService.SuggestMetadata(c.Request.Context(), articleID, user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to suggest metadata")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Suggestions retrieved successfully", suggestions))
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TransferArticle handles giving an article to another author (admin only)
+// POST /api/admin/articles/:id/transfer
+func (h *ArticleHandler) TransferArticle(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	var req services.TransferArticlesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.articleService.TransferArticle(admin.ID, articleID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to transfer article")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Article transferred successfully", result))
+}
+
+// TransferUserArticles handles giving all of a user's articles to another
+// author (admin only)
+// POST /api/admin/users/:id/articles/transfer
+func (h *ArticleHandler) TransferUserArticles(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	userID, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req services.TransferArticlesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.articleService.TransferUserArticles(admin.ID, userID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to transfer articles")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Articles transferred successfully", result))
+}
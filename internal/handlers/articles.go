@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
+	"go-blog/internal/models"
 	"go-blog/internal/services"
+	"go-blog/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,56 +23,105 @@ func NewArticleHandler(articleService *services.ArticleService) *ArticleHandler
 	}
 }
 
-// List handles article listing
+// List handles published article listing with filters, sorting and pagination
+// GET /api/articles?category_id=1,2&tag_id=3,4&tag_match=all&author_id=5&published_from=2024-01-01&published_to=2024-12-31&min_views=100&sort=-view_count
 func (h *ArticleHandler) List(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "List endpoint not implemented yet"})
+	filters := &services.ArticleListFilters{
+		Status:   string(models.StatusPublished),
+		TagMatch: c.Query("tag_match"),
+		Sort:     c.Query("sort"),
+	}
+
+	var err error
+	if filters.CategoryIDs, err = parseUintListQuery(c, "category_id"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid category_id"))
+		return
+	}
+	if filters.TagIDs, err = parseUintListQuery(c, "tag_id"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid tag_id"))
+		return
+	}
+	if filters.AuthorID, err = parseUintQuery(c, "author_id"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid author_id"))
+		return
+	}
+	if filters.PublishedFrom, err = parseDateQuery(c, "published_from"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("published_from must be in YYYY-MM-DD format"))
+		return
+	}
+	if filters.PublishedTo, err = parseDateQuery(c, "published_to"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("published_to must be in YYYY-MM-DD format"))
+		return
+	}
+	if !filters.PublishedTo.IsZero() {
+		// Include the whole end day
+		filters.PublishedTo = filters.PublishedTo.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	minViews, err := parseUintQuery(c, "min_views")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid min_views"))
+		return
+	}
+	filters.MinViews = minViews
+
+	page, limit := paginationParams(c)
+
+	articles, total, err := h.articleService.List(page, limit, filters, optionalUser(c))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve articles")
+		return
+	}
+
+	sort := filters.Sort
+	if sort == "" {
+		sort = "-created_at"
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Articles retrieved successfully", articleList(c, articles), &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Filters:    articleListFilters(c),
+		Sort:       sort,
+	}))
+}
+
+// articleListFilters reports the list filters present in the query string
+func articleListFilters(c *gin.Context) map[string]interface{} {
+	applied := map[string]interface{}{}
+	for _, name := range []string{"category_id", "tag_id", "tag_match", "author_id", "published_from", "published_to", "min_views"} {
+		if values := c.QueryArray(name); len(values) > 0 {
+			applied[name] = strings.Join(values, ",")
+		}
+	}
+	return applied
 }
 
 // Create handles article creation
 func (h *ArticleHandler) Create(c *gin.Context) {
 	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Create endpoint not implemented yet"})
+	c.JSON(http.StatusNotImplemented, utils.ErrorResponse("Create endpoint not implemented yet"))
 }
 
-// GetBySlug handles getting article by slug
+// GetBySlug handles getting an article by slug, its content cut to a preview
+// for readers without the membership it needs
+// GET /api/articles/:slug
 func (h *ArticleHandler) GetBySlug(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "GetBySlug endpoint not implemented yet"})
+	article, err := h.articleService.GetBySlug(c.Param("id"), optionalUser(c))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve article")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Article retrieved successfully", article))
 }
 
 // Update handles article updates
 func (h *ArticleHandler) Update(c *gin.Context) {
 	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Update endpoint not implemented yet"})
+	c.JSON(http.StatusNotImplemented, utils.ErrorResponse("Update endpoint not implemented yet"))
 }
 
 // Delete handles article deletion
 func (h *ArticleHandler) Delete(c *gin.Context) {
 	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Delete endpoint not implemented yet"})
-}
-
-// Search handles article search
-func (h *ArticleHandler) Search(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Search endpoint not implemented yet"})
-}
-
-// ToggleLike handles article like/unlike
-func (h *ArticleHandler) ToggleLike(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "ToggleLike endpoint not implemented yet"})
-}
-
-// GetArchive handles archive listing
-func (h *ArticleHandler) GetArchive(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "GetArchive endpoint not implemented yet"})
-}
-
-// GetArchiveByMonth handles archive by month
-func (h *ArticleHandler) GetArchiveByMonth(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "GetArchiveByMonth endpoint not implemented yet"})
+	c.JSON(http.StatusNotImplemented, utils.ErrorResponse("Delete endpoint not implemented yet"))
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"time"
 
 	"go-blog/internal/models"
 	"go-blog/internal/services"
@@ -10,8 +11,23 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// RefreshCookieName is the cookie refresh tokens are delivered in when enabled
+const RefreshCookieName = "refresh_token"
+
+// refreshCookiePath limits the refresh cookie to API requests
+const refreshCookiePath = "/api"
+
+// RefreshCookie configures delivering refresh tokens in an HttpOnly cookie, so
+// browser frontends never have to store them in script-readable storage
+type RefreshCookie struct {
+	Secure   bool
+	SameSite http.SameSite
+}
+
 type AuthHandler struct {
-	authService *services.AuthService
+	authService   *services.AuthService
+	refreshCookie *RefreshCookie
+	geoHeader     string
 }
 
 // NewAuthHandler creates a new auth handler
@@ -21,19 +37,31 @@ func NewAuthHandler(authService *services.AuthService) *AuthHandler {
 	}
 }
 
+// SetRefreshCookie makes the handler send refresh tokens in a cookie instead of
+// the response body and read them back from it
+func (h *AuthHandler) SetRefreshCookie(cookie *RefreshCookie) {
+	h.refreshCookie = cookie
+}
+
+// SetGeoHeader sets the request header a proxy or CDN puts the client's coarse
+// location in, such as CF-IPCountry, to record it on sessions
+func (h *AuthHandler) SetGeoHeader(header string) {
+	h.geoHeader = header
+}
+
 // Register handles user registration
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req services.RegisterRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	response, err := h.authService.Register(&req)
+	response, err := h.authService.Register(&req, h.clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		respondError(c, err, "Failed to register user")
 		return
 	}
+	h.deliverTokens(c, response.Tokens)
 
 	c.JSON(http.StatusCreated, utils.SuccessResponse("User registered successfully", response))
 }
@@ -41,24 +69,76 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // Login handles user login
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req services.LoginRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	response, err := h.authService.Login(&req)
+	response, err := h.authService.Login(&req, h.clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse(err.Error()))
+		respondError(c, err, "Failed to log in")
 		return
 	}
+	h.deliverTokens(c, response.Tokens)
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Login successful", response))
 }
 
-// Logout handles user logout
+// RequestMagicLink handles emailing a single-use sign-in link. The response is
+// the same whether or not the address belongs to an account.
+// POST /api/auth/magic-link
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req services.MagicLinkRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.RequestMagicLink(&req, h.clientInfo(c)); err != nil {
+		respondError(c, err, "Failed to send sign-in link")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.SuccessResponse("If the address belongs to an account, a sign-in link was sent to it", nil))
+}
+
+// VerifyMagicLink handles signing in with the token of an emailed link
+// POST /api/auth/magic-link/verify
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	var req services.VerifyMagicLinkRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	response, err := h.authService.VerifyMagicLink(&req, h.clientInfo(c))
+	if err != nil {
+		respondError(c, err, "Failed to sign in")
+		return
+	}
+	h.deliverTokens(c, response.Tokens)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Login successful", response))
+}
+
+// Logout handles user logout. The refresh token, from the cookie or the optional
+// body, is revoked together with every token rotated from the same sign-in; the
+// client discards its access token.
+// POST /api/auth/logout
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// For JWT tokens, logout is typically handled client-side by removing the token
-	// In a more advanced implementation, you might maintain a blacklist of tokens
+	refreshToken := h.refreshTokenFromCookie(c)
+	if refreshToken == "" {
+		var req struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		// The body is optional; without a token there is nothing to revoke
+		_ = c.ShouldBindJSON(&req)
+		refreshToken = req.RefreshToken
+	}
+
+	if err := h.authService.Logout(refreshToken); err != nil {
+		respondError(c, err, "Failed to log out")
+		return
+	}
+	h.clearRefreshCookie(c)
+
 	c.JSON(http.StatusOK, utils.SuccessResponse("Logout successful", nil))
 }
 
@@ -76,25 +156,208 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		return
 	}
 
+	userModel.AvatarURL = userModel.Avatar()
 	c.JSON(http.StatusOK, utils.SuccessResponse("User information retrieved", userModel))
 }
 
-// RefreshToken handles token refresh
+// RefreshToken handles token refresh. The refresh token is read from the cookie
+// when cookies are enabled and present, otherwise from the body.
+// POST /api/auth/refresh
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	var req struct {
-		RefreshToken string `json:"refresh_token" binding:"required"`
+	refreshToken := h.refreshTokenFromCookie(c)
+	if refreshToken == "" {
+		var req struct {
+			RefreshToken string `json:"refresh_token" validate:"required"`
+		}
+		if !bindJSON(c, &req) {
+			return
+		}
+		refreshToken = req.RefreshToken
+	}
+
+	tokens, err := h.authService.RefreshToken(refreshToken, h.clientInfo(c))
+	if err != nil {
+		h.clearRefreshCookie(c)
+		respondError(c, err, "Failed to refresh token")
+		return
+	}
+	h.deliverTokens(c, tokens)
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Token refreshed successfully", tokens))
+}
+
+// ChangePassword handles changing the current user's password. Every existing
+// session is signed out; the response carries a new token pair.
+// POST /api/users/me/password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Refresh token is required"))
+	var req services.ChangePasswordRequest
+	if !bindJSON(c, &req) {
 		return
 	}
 
-	tokens, err := h.authService.RefreshToken(req.RefreshToken)
+	tokens, err := h.authService.ChangePassword(user.ID, &req, h.clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, utils.ErrorResponse(err.Error()))
+		respondError(c, err, "Failed to change password")
 		return
 	}
+	h.deliverTokens(c, tokens)
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Token refreshed successfully", tokens))
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, utils.SuccessResponse("Password changed successfully", tokens))
+}
+
+// ListSessions handles listing the current user's signed-in sessions with their
+// device, IP and location. The session of the request is flagged as current.
+// GET /api/users/me/sessions
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.authService.ListSessions(user.ID, currentSessionID(c))
+	if err != nil {
+		respondError(c, err, "Failed to retrieve sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Sessions retrieved successfully", sessions))
+}
+
+// RevokeSession handles signing out one of the current user's sessions
+// DELETE /api/users/me/sessions/:id
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "session")
+	if !ok {
+		return
+	}
+
+	if err := h.authService.RevokeSession(user.ID, id); err != nil {
+		respondError(c, err, "Failed to sign out session")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Session signed out successfully", nil))
+}
+
+// RevokeOtherSessions handles signing out every session of the current user
+// except the one the request is made with ("log out other devices")
+// DELETE /api/users/me/sessions
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	revoked, err := h.authService.RevokeOtherSessions(user.ID, currentSessionID(c))
+	if err != nil {
+		respondError(c, err, "Failed to sign out other sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Other sessions signed out successfully", gin.H{"revoked": revoked}))
+}
+
+// RequestEmailChange handles starting an email change; a confirmation link is sent
+// to the new address
+// POST /api/users/me/email
+func (h *AuthHandler) RequestEmailChange(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.ChangeEmailRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.authService.RequestEmailChange(user.ID, &req); err != nil {
+		respondError(c, err, "Failed to change email")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, utils.SuccessResponse("Confirmation email sent to the new address", nil))
+}
+
+// ConfirmEmailChange handles the confirmation link sent by RequestEmailChange
+// GET /api/auth/confirm-email?token=...
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	user, err := h.authService.ConfirmEmailChange(c.Query("token"))
+	if err != nil {
+		respondError(c, err, "Failed to confirm email change")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Email changed successfully", user))
+}
+
+// clientInfo describes the client of the request for its session record
+func (h *AuthHandler) clientInfo(c *gin.Context) services.ClientInfo {
+	client := services.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if h.geoHeader != "" {
+		client.Location = c.GetHeader(h.geoHeader)
+	}
+	return client
+}
+
+// deliverTokens moves the refresh token from the response body into an HttpOnly
+// cookie when cookies are enabled
+func (h *AuthHandler) deliverTokens(c *gin.Context, tokens *utils.TokenPair) {
+	if h.refreshCookie == nil || tokens == nil {
+		return
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     RefreshCookieName,
+		Value:    tokens.RefreshToken,
+		Path:     refreshCookiePath,
+		Expires:  tokens.RefreshExpiresAt,
+		MaxAge:   int(time.Until(tokens.RefreshExpiresAt).Seconds()),
+		HttpOnly: true,
+		Secure:   h.refreshCookie.Secure,
+		SameSite: h.refreshCookie.SameSite,
+	})
+	tokens.RefreshToken = ""
+}
+
+// clearRefreshCookie removes the refresh cookie when cookies are enabled
+func (h *AuthHandler) clearRefreshCookie(c *gin.Context) {
+	if h.refreshCookie == nil {
+		return
+	}
+
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     RefreshCookieName,
+		Path:     refreshCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   h.refreshCookie.Secure,
+		SameSite: h.refreshCookie.SameSite,
+	})
+}
+
+// refreshTokenFromCookie returns the refresh token cookie, or "" when cookies
+// are disabled or the request has none
+func (h *AuthHandler) refreshTokenFromCookie(c *gin.Context) string {
+	if h.refreshCookie == nil {
+		return ""
+	}
+	token, err := c.Cookie(RefreshCookieName)
+	if err != nil {
+		return ""
+	}
+	return token
+}
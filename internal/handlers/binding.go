@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"go-blog/internal/models"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindJSON binds the JSON request body into req and runs its validate tags.
+// On failure it writes a 400 response, with per-field details for validation
+// errors, or a 413 response for bodies over the size limit, and returns false.
+func bindJSON(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(
+				fmt.Sprintf("Request body is too large; the limit is %d bytes", maxBytesErr.Limit)))
+			return false
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+		return false
+	}
+
+	if err := models.ValidateStruct(req); err != nil {
+		var fields models.ValidationErrors
+		if errors.As(err, &fields) && len(fields) > 0 {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponseWithDetails("Validation failed", fieldMessages(fields)))
+			return false
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data"))
+		return false
+	}
+
+	return true
+}
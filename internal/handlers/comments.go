@@ -3,7 +3,9 @@ package handlers
 import (
 	"net/http"
 
+	"go-blog/internal/models"
 	"go-blog/internal/services"
+	"go-blog/internal/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,6 +14,18 @@ type CommentHandler struct {
 	commentService *services.CommentService
 }
 
+// CreateCommentRequest represents a new comment or reply. @handle mentions in the
+// content notify the mentioned users.
+type CreateCommentRequest struct {
+	Content  string `json:"content" validate:"required,min=1,max=2000"`
+	ParentID *uint  `json:"parent_id,omitempty" validate:"omitempty,min=1"`
+}
+
+// UpdateCommentRequest represents an edit of a comment's content
+type UpdateCommentRequest struct {
+	Content string `json:"content" validate:"required,min=1,max=2000"`
+}
+
 // NewCommentHandler creates a new comment handler
 func NewCommentHandler(commentService *services.CommentService) *CommentHandler {
 	return &CommentHandler{
@@ -19,26 +33,257 @@ func NewCommentHandler(commentService *services.CommentService) *CommentHandler
 	}
 }
 
-// GetByArticle handles getting comments by article
+// GetByArticle handles getting the threaded comments of an article
+// GET /api/articles/:id/comments
 func (h *CommentHandler) GetByArticle(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "GetByArticle endpoint not implemented yet"})
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	comments, err := h.commentService.GetByArticle(articleID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve comments")
+		return
+	}
+
+	for i := range comments {
+		hideCommenterEmail(&comments[i])
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Comments retrieved successfully", comments))
 }
 
 // Create handles comment creation
+// POST /api/articles/:id/comments
 func (h *CommentHandler) Create(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Create endpoint not implemented yet"})
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	var req CreateCommentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	comment := &models.Comment{
+		ArticleID: articleID,
+		UserID:    user.ID,
+		Content:   req.Content,
+		ParentID:  req.ParentID,
+	}
+	if err := h.commentService.Create(comment, c.ClientIP()); err != nil {
+		respondError(c, err, "Failed to create comment")
+		return
+	}
+
+	hideCommenterEmail(comment)
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Comment created successfully", comment))
 }
 
-// Update handles comment updates
+// Update handles comment updates by their author
+// PUT /api/comments/:id
 func (h *CommentHandler) Update(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Update endpoint not implemented yet"})
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "comment")
+	if !ok {
+		return
+	}
+
+	var req UpdateCommentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	comment, err := h.commentService.Update(id, user.ID, req.Content)
+	if err != nil {
+		respondError(c, err, "Failed to update comment")
+		return
+	}
+
+	hideCommenterEmail(comment)
+	c.JSON(http.StatusOK, utils.SuccessResponse("Comment updated successfully", comment))
 }
 
-// Delete handles comment deletion
+// Delete handles comment deletion by their author
+// DELETE /api/comments/:id
 func (h *CommentHandler) Delete(c *gin.Context) {
-	// Implementation placeholder - will be implemented in later tasks
-	c.JSON(http.StatusNotImplemented, gin.H{"message": "Delete endpoint not implemented yet"})
-}
\ No newline at end of file
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "comment")
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Delete(id, user.ID); err != nil {
+		respondError(c, err, "Failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Comment deleted successfully", nil))
+}
+
+// GetSubscription handles checking whether the current user watches an article's comments
+// GET /api/articles/:id/subscription
+func (h *CommentHandler) GetSubscription(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	subscribed, err := h.commentService.IsSubscribed(user.ID, articleID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve subscription")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Subscription retrieved successfully", gin.H{
+		"subscribed": subscribed,
+	}))
+}
+
+// Subscribe handles watching an article's comment thread
+// POST /api/articles/:id/subscription
+func (h *CommentHandler) Subscribe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	subscription, err := h.commentService.Subscribe(user.ID, articleID)
+	if err != nil {
+		respondError(c, err, "Failed to subscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Subscribed to comments successfully", subscription))
+}
+
+// Unsubscribe handles no longer watching an article's comment thread
+// DELETE /api/articles/:id/subscription
+func (h *CommentHandler) Unsubscribe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	if err := h.commentService.Unsubscribe(user.ID, articleID); err != nil {
+		respondError(c, err, "Failed to unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Unsubscribed from comments successfully", nil))
+}
+
+// UnsubscribeByToken handles the unsubscribe link in comment notification emails
+// GET /api/comment-subscriptions/unsubscribe?token=...
+func (h *CommentHandler) UnsubscribeByToken(c *gin.Context) {
+	if err := h.commentService.UnsubscribeByToken(c.Query("token")); err != nil {
+		respondError(c, err, "Failed to unsubscribe")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Unsubscribed from comments successfully", nil))
+}
+
+// Report handles reporting a comment for review
+// POST /api/comments/:id/report
+func (h *CommentHandler) Report(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "comment")
+	if !ok {
+		return
+	}
+
+	var req services.ReportCommentRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	report, err := h.commentService.ReportComment(id, user.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to report comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Comment reported successfully", report))
+}
+
+// ReportQueue handles listing reported comments awaiting review (admin only)
+// GET /api/admin/comment-reports?page=1&limit=10
+func (h *CommentHandler) ReportQueue(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	queue, total, err := h.commentService.GetReportQueue(page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve reported comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Reported comments retrieved successfully", queue, &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Sort:       "-report_count",
+	}))
+}
+
+// ReviewReports handles an administrator's decision on a reported comment
+// POST /api/admin/comments/:id/review
+func (h *CommentHandler) ReviewReports(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "comment")
+	if !ok {
+		return
+	}
+
+	var req services.ReviewReportsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	if err := h.commentService.ReviewReports(id, req.Action); err != nil {
+		respondError(c, err, "Failed to review comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Comment reviewed successfully", nil))
+}
+
+// hideCommenterEmail clears the preloaded commenters' email addresses, which are
+// not public, from a comment and its replies
+func hideCommenterEmail(comment *models.Comment) {
+	comment.User.Email = ""
+	for i := range comment.Replies {
+		hideCommenterEmail(&comment.Replies[i])
+	}
+}
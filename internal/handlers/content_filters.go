@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ContentFilterHandler struct {
+	contentFilterService *services.ContentFilterService
+}
+
+// NewContentFilterHandler creates a new content filter handler
+func NewContentFilterHandler(contentFilterService *services.ContentFilterService) *ContentFilterHandler {
+	return &ContentFilterHandler{
+		contentFilterService: contentFilterService,
+	}
+}
+
+// List handles listing the content filter rules (admin only)
+// GET /api/admin/content-filters
+func (h *ContentFilterHandler) List(c *gin.Context) {
+	rules, err := h.contentFilterService.List()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve content filter rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Content filter rules retrieved successfully", rules))
+}
+
+// Create handles adding a word, phrase or regular expression to the content
+// filter, with the action taken on comments or titles matching it (admin only)
+// POST /api/admin/content-filters
+func (h *ContentFilterHandler) Create(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.ContentFilterRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.contentFilterService.Create(admin.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to create content filter rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Content filter rule created successfully", rule))
+}
+
+// Update handles replacing a content filter rule (admin only)
+// PUT /api/admin/content-filters/:id
+func (h *ContentFilterHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "content filter rule")
+	if !ok {
+		return
+	}
+
+	var req services.ContentFilterRuleRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	rule, err := h.contentFilterService.Update(id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update content filter rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Content filter rule updated successfully", rule))
+}
+
+// Delete handles deleting a content filter rule (admin only)
+// DELETE /api/admin/content-filters/:id
+func (h *ContentFilterHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "content filter rule")
+	if !ok {
+		return
+	}
+
+	if err := h.contentFilterService.Delete(id); err != nil {
+		respondError(c, err, "Failed to delete content filter rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Content filter rule deleted successfully", nil))
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser returns the authenticated user set by the Auth middleware.
+// It writes an error response and returns false when no user is available.
+func currentUser(c *gin.Context) (*models.User, bool) {
+	user, exists := c.Get("user")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User not authenticated"))
+		return nil, false
+	}
+
+	userModel, ok := user.(*models.User)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Invalid user data"))
+		return nil, false
+	}
+
+	return userModel, true
+}
+
+// optionalUser returns the authenticated user, or nil for anonymous requests
+// on routes behind OptionalAuth
+func optionalUser(c *gin.Context) *models.User {
+	if user, ok := c.Get("user"); ok {
+		if userModel, ok := user.(*models.User); ok {
+			return userModel
+		}
+	}
+	return nil
+}
+
+// articleList returns the list representation of articles: the compact one
+// when the request asks for compact=true, the summaries otherwise
+func articleList(c *gin.Context, articles []models.Article) interface{} {
+	if c.Query("compact") == "true" {
+		return models.CompactArticles(articles)
+	}
+	return models.SummarizeArticles(articles)
+}
+
+// optionalUserID returns the ID of the authenticated user, or 0 for anonymous
+// requests on routes behind OptionalAuth
+func optionalUserID(c *gin.Context) uint {
+	if user := optionalUser(c); user != nil {
+		return user.ID
+	}
+	return 0
+}
+
+// currentSessionID returns the session of the access token the Auth middleware
+// accepted, or "" when the token carries none
+func currentSessionID(c *gin.Context) string {
+	return c.GetString("sessionID")
+}
+
+// parseIDParam parses a numeric route parameter such as :id.
+// It writes a 400 response and returns false when the value is not a valid ID.
+func parseIDParam(c *gin.Context, name, label string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid "+label+" ID"))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// paginationParams reads page and limit query parameters with the standard defaults
+func paginationParams(c *gin.Context) (int, int) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	return page, limit
+}
+
+// parseUintQuery parses an optional numeric query parameter, returning 0 when absent
+func parseUintQuery(c *gin.Context, name string) (uint, error) {
+	value := c.Query(name)
+	if value == "" {
+		return 0, nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint(id), nil
+}
+
+// parseDateQuery parses an optional YYYY-MM-DD query parameter, returning the zero time when absent
+func parseDateQuery(c *gin.Context, name string) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// parseListQuery parses a multi-value query parameter given either as a
+// comma-separated list (?type=a,b) or repeated (?type=a&type=b)
+func parseListQuery(c *gin.Context, name string) []string {
+	var values []string
+	for _, value := range c.QueryArray(name) {
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// parseUintListQuery parses a multi-value numeric query parameter given either
+// as a comma-separated list (?tag_id=1,2) or repeated (?tag_id=1&tag_id=2)
+func parseUintListQuery(c *gin.Context, name string) ([]uint, error) {
+	var ids []uint
+	for _, part := range parseListQuery(c, name) {
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, nil
+}
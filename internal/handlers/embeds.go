@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EmbedHandler struct {
+	embedService *services.EmbedService
+}
+
+// NewEmbedHandler creates a new embed handler; embedService is nil when embeds
+// are disabled
+func NewEmbedHandler(embedService *services.EmbedService) *EmbedHandler {
+	return &EmbedHandler{
+		embedService: embedService,
+	}
+}
+
+// Resolve handles resolving a YouTube, Twitter/X or Gist link of article content
+// to its embed
+// GET /api/embeds?url=https://www.youtube.com/watch?v=...
+func (h *EmbedHandler) Resolve(c *gin.Context) {
+	if h.embedService == nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Embeds are not enabled"))
+		return
+	}
+
+	link := c.Query("url")
+	if link == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("url is required"))
+		return
+	}
+
+	embed, err := h.embedService.Resolve(c.Request.Context(), link)
+	if err != nil {
+		respondError(c, err, "Failed to resolve embed")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Embed resolved successfully", embed))
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorStatus maps service error kinds to HTTP status codes
+var errorStatus = []struct {
+	kind   error
+	status int
+}{
+	{services.ErrNotFound, http.StatusNotFound},
+	{services.ErrConflict, http.StatusConflict},
+	{services.ErrForbidden, http.StatusForbidden},
+	{services.ErrUnauthorized, http.StatusUnauthorized},
+	{services.ErrValidation, http.StatusBadRequest},
+	{services.ErrQuota, http.StatusTooManyRequests},
+	{services.ErrUpstream, http.StatusBadGateway},
+}
+
+// respondError writes the error response for err.
+// Typed service errors and model validation errors are returned to the client with
+// their own message and status; a slow or unavailable database is answered with a
+// 503; anything else is logged and answered with a 500 carrying fallback, so
+// internal details never leak.
+func respondError(c *gin.Context, err error, fallback string) {
+	var serviceErr *services.Error
+	if errors.As(err, &serviceErr) {
+		status := http.StatusInternalServerError
+		for _, mapping := range errorStatus {
+			if errors.Is(serviceErr.Kind, mapping.kind) {
+				status = mapping.status
+				break
+			}
+		}
+
+		if serviceErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(serviceErr.RetryAfter.Seconds()))))
+		}
+		if len(serviceErr.Fields) > 0 {
+			c.JSON(status, utils.ErrorResponseWithDetails(serviceErr.Message, fieldMessages(serviceErr.Fields)))
+			return
+		}
+		c.JSON(status, utils.ErrorResponse(serviceErr.Message))
+		return
+	}
+
+	var validationErrs models.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponseWithDetails("validation failed", fieldMessages(validationErrs)))
+		return
+	}
+
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Resource not found"))
+		return
+	}
+
+	if errors.Is(err, database.ErrUnavailable) || errors.Is(err, context.DeadlineExceeded) {
+		slog.ErrorContext(c.Request.Context(), "Request failed", "method", c.Request.Method, "route", c.FullPath(), "error", err)
+		c.JSON(http.StatusServiceUnavailable, utils.ErrorResponse("Service temporarily unavailable, please try again shortly"))
+		return
+	}
+
+	slog.ErrorContext(c.Request.Context(), "Request failed", "method", c.Request.Method, "route", c.FullPath(), "error", err)
+	c.JSON(http.StatusInternalServerError, utils.ErrorResponse(fallback))
+}
+
+// fieldMessages flattens validation errors into response detail messages
+func fieldMessages(fields models.ValidationErrors) []string {
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, field.Message)
+	}
+	return messages
+}
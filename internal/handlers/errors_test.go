@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRespondError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		err     error
+		status  int
+		message string
+		errors  []string
+	}{
+		{"not found", &services.Error{Kind: services.ErrNotFound, Message: "Article not found"}, http.StatusNotFound, "Article not found", nil},
+		{"conflict", &services.Error{Kind: services.ErrConflict, Message: "Tag already exists"}, http.StatusConflict, "Tag already exists", nil},
+		{"forbidden", &services.Error{Kind: services.ErrForbidden, Message: "Not your article"}, http.StatusForbidden, "Not your article", nil},
+		{"unauthorized", &services.Error{Kind: services.ErrUnauthorized, Message: "Invalid credentials"}, http.StatusUnauthorized, "Invalid credentials", nil},
+		{"validation", &services.Error{Kind: services.ErrValidation, Message: "Invalid month"}, http.StatusBadRequest, "Invalid month", nil},
+		{"quota", &services.Error{Kind: services.ErrQuota, Message: "Too many articles"}, http.StatusTooManyRequests, "Too many articles", nil},
+		{"upstream", &services.Error{Kind: services.ErrUpstream, Message: "Embed provider failed"}, http.StatusBadGateway, "Embed provider failed", nil},
+		{"wrapped", fmt.Errorf("create tag: %w", &services.Error{Kind: services.ErrConflict, Message: "Tag already exists"}), http.StatusConflict, "Tag already exists", nil},
+		{"unknown kind", &services.Error{Kind: errors.New("other"), Message: "Odd failure"}, http.StatusInternalServerError, "Odd failure", nil},
+		{
+			"field details",
+			&services.Error{Kind: services.ErrValidation, Message: "validation failed", Fields: models.ValidationErrors{{Field: "title", Message: "title is required"}}},
+			http.StatusBadRequest, "validation failed", []string{"title is required"},
+		},
+		{
+			"model validation",
+			models.ValidationErrors{{Field: "name", Message: "name is too long"}, {Field: "slug", Message: "slug is invalid"}},
+			http.StatusBadRequest, "validation failed", []string{"name is too long", "slug is invalid"},
+		},
+		{"repository not found", fmt.Errorf("load: %w", repositories.ErrNotFound), http.StatusNotFound, "Resource not found", nil},
+		{"database unavailable", database.ErrUnavailable, http.StatusServiceUnavailable, "Service temporarily unavailable, please try again shortly", nil},
+		{"internal", errors.New("connection reset by peer"), http.StatusInternalServerError, "Failed to do the thing", nil},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+		respondError(c, tt.err, "Failed to do the thing")
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.name, tt.status, w.Code)
+		}
+		var response utils.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", tt.name, err)
+		}
+		if response.Success || response.Message != tt.message || !reflect.DeepEqual(response.Errors, tt.errors) {
+			t.Errorf("%s: unexpected response %+v", tt.name, response)
+		}
+	}
+}
+
+func TestRespondErrorRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+	respondError(c, &services.Error{Kind: services.ErrQuota, Message: "Too many comments", RetryAfter: 1500 * time.Millisecond}, "Failed")
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Expected Retry-After rounded up to 2 seconds, got %q", got)
+	}
+}
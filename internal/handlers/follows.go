@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FollowHandler struct {
+	followService *services.FollowService
+}
+
+// NewFollowHandler creates a new follow handler
+func NewFollowHandler(followService *services.FollowService) *FollowHandler {
+	return &FollowHandler{
+		followService: followService,
+	}
+}
+
+// FollowCategory handles following a category
+// POST /api/categories/:id/follow
+func (h *FollowHandler) FollowCategory(c *gin.Context) {
+	h.follow(c, models.FollowTargetCategory, c.Param("id"), true)
+}
+
+// UnfollowCategory handles unfollowing a category
+// DELETE /api/categories/:id/follow
+func (h *FollowHandler) UnfollowCategory(c *gin.Context) {
+	h.follow(c, models.FollowTargetCategory, c.Param("id"), false)
+}
+
+// FollowTag handles following a tag
+// POST /api/tags/:slug/follow
+func (h *FollowHandler) FollowTag(c *gin.Context) {
+	h.follow(c, models.FollowTargetTag, c.Param("slug"), true)
+}
+
+// UnfollowTag handles unfollowing a tag
+// DELETE /api/tags/:slug/follow
+func (h *FollowHandler) UnfollowTag(c *gin.Context) {
+	h.follow(c, models.FollowTargetTag, c.Param("slug"), false)
+}
+
+// ListFollows handles listing the categories and tags the current user follows
+// GET /api/users/me/follows
+func (h *FollowHandler) ListFollows(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	follows, err := h.followService.GetFollows(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve follows"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Follows retrieved successfully", follows))
+}
+
+// Feed handles the personalized article feed built from followed categories and tags
+// GET /api/feed
+func (h *FollowHandler) Feed(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	articles, total, err := h.followService.GetFeed(user, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve feed"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Feed retrieved successfully", articleList(c, articles), &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Sort:       "-published_at",
+	}))
+}
+
+// follow toggles a follow on the category with ID key or the tag with slug key
+func (h *FollowHandler) follow(c *gin.Context, targetType models.FollowTargetType, key string, follow bool) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var status *services.FollowStatus
+	var err error
+	if follow {
+		status, err = h.followService.Follow(user.ID, targetType, key)
+	} else {
+		status, err = h.followService.Unfollow(user.ID, targetType, key)
+	}
+
+	if err != nil {
+		respondError(c, err, "Failed to update follow")
+		return
+	}
+
+	message := "Unfollowed successfully"
+	if follow {
+		message = "Followed successfully"
+	}
+	c.JSON(http.StatusOK, utils.SuccessResponse(message, status))
+}
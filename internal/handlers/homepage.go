@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HomepageHandler struct {
+	homepageService *services.HomepageService
+}
+
+// NewHomepageHandler creates a new homepage handler
+func NewHomepageHandler(homepageService *services.HomepageService) *HomepageHandler {
+	return &HomepageHandler{
+		homepageService: homepageService,
+	}
+}
+
+// Get handles composing the homepage: every section in order with its articles
+// GET /api/homepage
+func (h *HomepageHandler) Get(c *gin.Context) {
+	blocks, err := h.homepageService.Compose()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve homepage")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Homepage retrieved successfully", blocks))
+}
+
+// List handles listing the homepage sections (admin only)
+// GET /api/admin/homepage-sections
+func (h *HomepageHandler) List(c *gin.Context) {
+	sections, err := h.homepageService.List()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve homepage sections")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Homepage sections retrieved successfully", sections))
+}
+
+// Create handles creating a homepage section (admin only)
+// POST /api/admin/homepage-sections
+func (h *HomepageHandler) Create(c *gin.Context) {
+	var req services.CreateHomepageSectionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	section, err := h.homepageService.Create(&req)
+	if err != nil {
+		respondError(c, err, "Failed to create homepage section")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Homepage section created successfully", section))
+}
+
+// Update handles updating a homepage section (admin only); omitted fields are
+// left unchanged
+// PUT /api/admin/homepage-sections/:id
+func (h *HomepageHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "homepage section")
+	if !ok {
+		return
+	}
+
+	var req services.UpdateHomepageSectionRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	section, err := h.homepageService.Update(id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update homepage section")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Homepage section updated successfully", section))
+}
+
+// Delete handles deleting a homepage section (admin only)
+// DELETE /api/admin/homepage-sections/:id
+func (h *HomepageHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "homepage section")
+	if !ok {
+		return
+	}
+
+	if err := h.homepageService.Delete(id); err != nil {
+		respondError(c, err, "Failed to delete homepage section")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Homepage section deleted successfully", nil))
+}
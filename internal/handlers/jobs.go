@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-blog/internal/scheduler"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	scheduler *scheduler.Scheduler
+}
+
+// UpdateJobRequest represents a change to a scheduled job
+type UpdateJobRequest struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+// NewJobHandler creates a new scheduled job handler
+func NewJobHandler(scheduler *scheduler.Scheduler) *JobHandler {
+	return &JobHandler{
+		scheduler: scheduler,
+	}
+}
+
+// List handles listing the scheduled jobs with their next and last runs (admin only)
+// GET /api/admin/jobs
+func (h *JobHandler) List(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse("Jobs retrieved successfully", h.scheduler.Jobs()))
+}
+
+// Update handles enabling or disabling the scheduled runs of a job until the
+// next restart (admin only)
+// PUT /api/admin/jobs/:name
+func (h *JobHandler) Update(c *gin.Context) {
+	var req UpdateJobRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	job, err := h.scheduler.SetEnabled(c.Param("name"), *req.Enabled)
+	if err != nil {
+		respondJobError(c, err, "Failed to update job")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Job updated successfully", job))
+}
+
+// Run handles running a job immediately, waiting for it to finish (admin only)
+// POST /api/admin/jobs/:name/run
+func (h *JobHandler) Run(c *gin.Context) {
+	run, err := h.scheduler.RunNow(c.Param("name"))
+	if err != nil {
+		respondJobError(c, err, "Failed to run job")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Job run completed", run))
+}
+
+// Runs handles listing the most recent runs of a job (admin only)
+// GET /api/admin/jobs/:name/runs
+func (h *JobHandler) Runs(c *gin.Context) {
+	_, limit := paginationParams(c)
+
+	runs, err := h.scheduler.Runs(c.Param("name"), limit)
+	if err != nil {
+		respondJobError(c, err, "Failed to retrieve job runs")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Job runs retrieved successfully", runs))
+}
+
+// respondJobError maps scheduler errors to their status codes
+func respondJobError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Job not found"))
+	case errors.Is(err, scheduler.ErrJobRunning):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Job is already running"))
+	default:
+		respondError(c, err, fallback)
+	}
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LikeHandler struct {
+	likeService *services.LikeService
+}
+
+// NewLikeHandler creates a new like handler
+func NewLikeHandler(likeService *services.LikeService) *LikeHandler {
+	return &LikeHandler{
+		likeService: likeService,
+	}
+}
+
+// ToggleLike handles article like/unlike
+// POST /api/articles/:id/like
+func (h *LikeHandler) ToggleLike(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	liked, err := h.likeService.ToggleLike(user.ID, articleID)
+	if err != nil {
+		respondError(c, err, "Failed to update like")
+		return
+	}
+
+	count, err := h.likeService.GetLikeCount(articleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve like count"))
+		return
+	}
+
+	message := "Article unliked successfully"
+	if liked {
+		message = "Article liked successfully"
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse(message, gin.H{
+		"liked":      liked,
+		"like_count": count,
+	}))
+}
+
+// GetLikeStatus handles the like count and, for authenticated users, whether they liked the article
+// GET /api/articles/:id/like
+func (h *LikeHandler) GetLikeStatus(c *gin.Context) {
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	var userID *uint
+	if id, exists := c.Get("userID"); exists {
+		if uid, ok := id.(uint); ok {
+			userID = &uid
+		}
+	}
+
+	count, liked, err := h.likeService.GetArticleLikeStatus(articleID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve like status"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Like status retrieved successfully", gin.H{
+		"liked":      liked,
+		"like_count": count,
+	}))
+}
+
+// ListMine handles listing the articles the current user liked
+// GET /api/users/me/likes?page=1&limit=10
+func (h *LikeHandler) ListMine(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	articles, total, err := h.likeService.ListLikedArticles(user, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve liked articles")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Liked articles retrieved successfully", articleList(c, articles), &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Sort:       "-liked_at",
+	}))
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LinkCheckHandler struct {
+	linkCheckService *services.LinkCheckService
+}
+
+// NewLinkCheckHandler creates a new link check handler
+func NewLinkCheckHandler(linkCheckService *services.LinkCheckService) *LinkCheckHandler {
+	return &LinkCheckHandler{
+		linkCheckService: linkCheckService,
+	}
+}
+
+// ListBroken handles listing the broken external links the link_check job
+// found in published articles, longest broken first
+// GET /api/admin/link-checks/broken?page=1&limit=10
+func (h *LinkCheckHandler) ListBroken(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	links, total, err := h.linkCheckService.ListBroken(page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve broken links")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Broken links retrieved successfully", links, &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Sort:       "broken_since",
+	}))
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MaintenanceHandler struct {
+	maintenanceService *services.MaintenanceService
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(maintenanceService *services.MaintenanceService) *MaintenanceHandler {
+	return &MaintenanceHandler{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// Get handles reading the maintenance mode (admin only)
+// GET /api/admin/maintenance
+func (h *MaintenanceHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse("Maintenance mode retrieved successfully", h.maintenanceService.State()))
+}
+
+// Update handles turning maintenance mode on or off (admin only); omitted
+// fields are left unchanged
+// PUT /api/admin/maintenance
+func (h *MaintenanceHandler) Update(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateMaintenanceRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	state, err := h.maintenanceService.Update(admin.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update maintenance mode")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Maintenance mode updated successfully", state))
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// List handles listing the current user's notifications, newest first
+// GET /api/notifications?unread=true&page=1&limit=10
+func (h *NotificationHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+	unreadOnly := c.Query("unread") == "true"
+
+	list, total, err := h.notificationService.List(user.ID, unreadOnly, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve notifications")
+		return
+	}
+
+	var filters map[string]interface{}
+	if unreadOnly {
+		filters = map[string]interface{}{"unread": true}
+	}
+	c.JSON(http.StatusOK, utils.ListResponse("Notifications retrieved successfully", list, &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Filters:    filters,
+		Sort:       "-created_at",
+	}))
+}
+
+// MarkRead handles marking one of the current user's notifications as read
+// POST /api/notifications/:id/read
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "notification")
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkRead(user.ID, id); err != nil {
+		respondError(c, err, "Failed to mark notification read")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Notification marked read", nil))
+}
+
+// MarkAllRead handles marking all of the current user's notifications as read
+// POST /api/notifications/read-all
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.notificationService.MarkAllRead(user.ID); err != nil {
+		respondError(c, err, "Failed to mark notifications read")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Notifications marked read", nil))
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/models"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PageHandler struct {
+	pageService *services.PageService
+}
+
+// NewPageHandler creates a new page handler
+func NewPageHandler(pageService *services.PageService) *PageHandler {
+	return &PageHandler{
+		pageService: pageService,
+	}
+}
+
+// List handles listing published pages as navigation links; nav=true limits
+// the list to pages shown in navigation
+// GET /api/pages?nav=true
+func (h *PageHandler) List(c *gin.Context) {
+	links, err := h.pageService.ListPublished(c.Query("nav") == "true")
+	if err != nil {
+		respondError(c, err, "Failed to retrieve pages")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Pages retrieved successfully", links))
+}
+
+// GetBySlug handles getting a published page by slug; admins can preview drafts
+// GET /api/pages/:slug
+func (h *PageHandler) GetBySlug(c *gin.Context) {
+	includeDrafts := false
+	if user, ok := c.Get("user"); ok {
+		if userModel, ok := user.(*models.User); ok {
+			includeDrafts = userModel.IsAdmin()
+		}
+	}
+
+	page, err := h.pageService.GetBySlug(c.Param("slug"), includeDrafts)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve page")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Page retrieved successfully", page))
+}
+
+// AdminList handles listing every page, drafts included (admin only)
+// GET /api/admin/pages
+func (h *PageHandler) AdminList(c *gin.Context) {
+	pages, err := h.pageService.List()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve pages")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Pages retrieved successfully", pages))
+}
+
+// Create handles creating a page (admin only)
+// POST /api/admin/pages
+func (h *PageHandler) Create(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.CreatePageRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	page, err := h.pageService.Create(admin.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to create page")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Page created successfully", page))
+}
+
+// Update handles updating a page (admin only); omitted fields are left unchanged
+// PUT /api/admin/pages/:id
+func (h *PageHandler) Update(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "page")
+	if !ok {
+		return
+	}
+
+	var req services.UpdatePageRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	page, err := h.pageService.Update(id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update page")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Page updated successfully", page))
+}
+
+// Delete handles deleting a page (admin only)
+// DELETE /api/admin/pages/:id
+func (h *PageHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "page")
+	if !ok {
+		return
+	}
+
+	if err := h.pageService.Delete(id); err != nil {
+		respondError(c, err, "Failed to delete page")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Page deleted successfully", nil))
+}
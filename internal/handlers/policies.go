@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyHandler struct {
+	policyService *services.PolicyService
+}
+
+// NewPolicyHandler creates a new policy handler
+func NewPolicyHandler(policyService *services.PolicyService) *PolicyHandler {
+	return &PolicyHandler{
+		policyService: policyService,
+	}
+}
+
+// Current handles the current version of each policy, which registering accepts
+// GET /api/policies
+func (h *PolicyHandler) Current(c *gin.Context) {
+	policies, err := h.policyService.Current()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve policies")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Policies retrieved successfully", policies))
+}
+
+// Pending handles the current policies the signed-in user has not accepted
+// GET /api/policies/pending
+func (h *PolicyHandler) Pending(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	policies, err := h.policyService.Pending(user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve pending policies")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Pending policies retrieved successfully", policies))
+}
+
+// Accept handles the signed-in user accepting current policy versions, and
+// responds with the policies still pending
+// POST /api/policies/accept
+func (h *PolicyHandler) Accept(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.AcceptPoliciesRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	pending, err := h.policyService.Accept(user.ID, &req, c.ClientIP())
+	if err != nil {
+		respondError(c, err, "Failed to accept policies")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Policies accepted successfully", pending))
+}
+
+// List handles listing every version of every policy (admin only)
+// GET /api/admin/policies
+func (h *PolicyHandler) List(c *gin.Context) {
+	policies, err := h.policyService.List()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve policies")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Policies retrieved successfully", policies))
+}
+
+// Publish handles publishing a new version of a policy, which every user must
+// accept again (admin only)
+// POST /api/admin/policies
+func (h *PolicyHandler) Publish(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.PolicyRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	policy, err := h.policyService.Publish(admin.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to publish policy")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Policy published successfully", policy))
+}
+
+// PendingUsers handles listing the users who must accept the current policies
+// again (admin only)
+// GET /api/admin/policies/pending-users
+func (h *PolicyHandler) PendingUsers(c *gin.Context) {
+	page, limit := paginationParams(c)
+
+	users, total, err := h.policyService.PendingUsers(page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve users pending policy acceptance")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Users retrieved successfully", users, &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+	}))
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go-blog/internal/models"
+	"go-blog/internal/queue"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type QueueHandler struct {
+	queue *queue.Queue
+}
+
+// NewQueueHandler creates a new background job queue handler
+func NewQueueHandler(q *queue.Queue) *QueueHandler {
+	return &QueueHandler{
+		queue: q,
+	}
+}
+
+// Stats handles counting the queued jobs in each status (admin only)
+// GET /api/admin/queue
+func (h *QueueHandler) Stats(c *gin.Context) {
+	stats, err := h.queue.Stats()
+	if err != nil {
+		respondError(c, err, "Failed to retrieve queue statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Queue statistics retrieved successfully", stats))
+}
+
+// List handles listing queued jobs, most recently updated first (admin only).
+// ?status=dead lists the dead letters.
+// GET /api/admin/queue/jobs
+func (h *QueueHandler) List(c *gin.Context) {
+	status := models.QueuedJobStatus(c.Query("status"))
+	switch status {
+	case "", models.QueuedJobPending, models.QueuedJobRunning, models.QueuedJobSucceeded, models.QueuedJobDead:
+	default:
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid status: must be pending, running, succeeded or dead"))
+		return
+	}
+	page, limit := paginationParams(c)
+
+	jobs, total, err := h.queue.List(status, (page-1)*limit, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve jobs")
+		return
+	}
+
+	meta := &utils.Meta{Pagination: utils.NewPagination(page, limit, total), Sort: "-updated_at"}
+	if status != "" {
+		meta.Filters = map[string]interface{}{"status": status}
+	}
+	c.JSON(http.StatusOK, utils.ListResponse("Jobs retrieved successfully", jobs, meta))
+}
+
+// Retry handles giving a dead job a fresh set of attempts (admin only)
+// POST /api/admin/queue/jobs/:id/retry
+func (h *QueueHandler) Retry(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "job")
+	if !ok {
+		return
+	}
+
+	job, err := h.queue.Retry(id)
+	if err != nil {
+		respondQueueError(c, err, "Failed to retry job")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Job queued for retry", job))
+}
+
+// Delete handles removing a queued job (admin only)
+// DELETE /api/admin/queue/jobs/:id
+func (h *QueueHandler) Delete(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "job")
+	if !ok {
+		return
+	}
+
+	if err := h.queue.Delete(id); err != nil {
+		respondQueueError(c, err, "Failed to delete job")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Job deleted successfully", nil))
+}
+
+// respondQueueError maps queue errors to their status codes
+func respondQueueError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, queue.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Job not found"))
+	case errors.Is(err, queue.ErrJobNotDead):
+		c.JSON(http.StatusConflict, utils.ErrorResponse("Only dead jobs can be retried"))
+	default:
+		respondError(c, err, fallback)
+	}
+}
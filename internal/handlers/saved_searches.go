@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedSearchHandler struct {
+	savedSearchService *services.SavedSearchService
+}
+
+// NewSavedSearchHandler creates a new saved search handler
+func NewSavedSearchHandler(savedSearchService *services.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{
+		savedSearchService: savedSearchService,
+	}
+}
+
+// List handles listing the current user's saved searches
+// GET /api/users/me/saved-searches
+func (h *SavedSearchHandler) List(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	searches, err := h.savedSearchService.List(user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve saved searches")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Saved searches retrieved successfully", searches))
+}
+
+// Create handles saving a search for the current user
+// POST /api/users/me/saved-searches
+func (h *SavedSearchHandler) Create(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.SavedSearchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	search, err := h.savedSearchService.Create(user.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to save search")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Search saved successfully", search))
+}
+
+// Update handles replacing one of the current user's saved searches
+// PUT /api/users/me/saved-searches/:id
+func (h *SavedSearchHandler) Update(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "saved search")
+	if !ok {
+		return
+	}
+
+	var req services.SavedSearchRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	search, err := h.savedSearchService.Update(user.ID, id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Saved search updated successfully", search))
+}
+
+// Delete handles deleting one of the current user's saved searches
+// DELETE /api/users/me/saved-searches/:id
+func (h *SavedSearchHandler) Delete(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "saved search")
+	if !ok {
+		return
+	}
+
+	if err := h.savedSearchService.Delete(user.ID, id); err != nil {
+		respondError(c, err, "Failed to delete saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Saved search deleted successfully", nil))
+}
+
+// Results handles running one of the current user's saved searches
+// GET /api/users/me/saved-searches/:id/results?page=1&limit=10
+func (h *SavedSearchHandler) Results(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "saved search")
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	result, err := h.savedSearchService.Run(user.ID, id, page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to run saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Search completed successfully", result, &utils.Meta{
+		Pagination: utils.NewPagination(result.Page, result.Limit, result.Total),
+	}))
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search handles full-text search over published articles
+// GET /api/search?q=golang&type=articles,comments,users&category_id=1&tag_id=2&author_id=3&date_from=2024-01-01&date_to=2024-12-31&mode=natural
+func (h *SearchHandler) Search(c *gin.Context) {
+	req, ok := searchRequest(c)
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	result, err := h.searchService.Search(req, page, limit)
+	h.respond(c, req, result, err)
+}
+
+// SearchUserArticles handles search within the articles of a user
+// GET /api/users/:id/articles/search?q=golang
+func (h *SearchHandler) SearchUserArticles(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+	req, ok := searchRequest(c)
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	result, err := h.searchService.SearchAuthorArticles(id, req, page, limit)
+	h.respond(c, req, result, err)
+}
+
+// SearchCategoryArticles handles search within the articles of a category
+// GET /api/categories/:slug/articles/search?q=golang
+func (h *SearchHandler) SearchCategoryArticles(c *gin.Context) {
+	req, ok := searchRequest(c)
+	if !ok {
+		return
+	}
+
+	page, limit := paginationParams(c)
+
+	result, err := h.searchService.SearchCategoryArticles(c.Param("slug"), req, page, limit)
+	h.respond(c, req, result, err)
+}
+
+// respond writes the outcome of a search
+func (h *SearchHandler) respond(c *gin.Context, req *services.SearchRequest, result *services.SearchResponse, err error) {
+	if err != nil {
+		respondError(c, err, "Failed to search articles")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Search completed successfully", result, &utils.Meta{
+		Pagination: utils.NewPagination(result.Page, result.Limit, result.Total),
+		Filters:    searchFilters(req),
+	}))
+}
+
+// searchRequest builds a search of published articles from the query string,
+// responding with 400 when a filter is malformed
+func searchRequest(c *gin.Context) (*services.SearchRequest, bool) {
+	req := &services.SearchRequest{
+		Query:      c.Query("q"),
+		Status:     "published",
+		SearchMode: c.DefaultQuery("mode", "natural"),
+		Types:      parseListQuery(c, "type"),
+	}
+
+	var err error
+	if req.CategoryID, err = parseUintQuery(c, "category_id"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid category_id"))
+		return nil, false
+	}
+	if req.TagID, err = parseUintQuery(c, "tag_id"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid tag_id"))
+		return nil, false
+	}
+	if req.AuthorID, err = parseUintQuery(c, "author_id"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid author_id"))
+		return nil, false
+	}
+	if req.DateFrom, err = parseDateQuery(c, "date_from"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("date_from must be in YYYY-MM-DD format"))
+		return nil, false
+	}
+	if req.DateTo, err = parseDateQuery(c, "date_to"); err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("date_to must be in YYYY-MM-DD format"))
+		return nil, false
+	}
+
+	return req, true
+}
+
+// Suggestions handles search suggestions for a partial query
+// GET /api/search/suggestions?q=go&limit=10
+func (h *SearchHandler) Suggestions(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Query parameter 'q' is required"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	suggestions, err := h.searchService.GetSearchSuggestions(query, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve search suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Search suggestions retrieved successfully", suggestions))
+}
+
+// Instant handles search-as-you-type: published articles and tags matching a
+// prefix, cacheable by browsers for a short while
+// GET /api/search/instant?q=go&limit=5
+func (h *SearchHandler) Instant(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if err != nil {
+		limit = 5
+	}
+
+	results, err := h.searchService.Instant(c.Query("q"), limit)
+	if err != nil {
+		respondError(c, err, "Failed to search")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, utils.SuccessResponse("Instant results retrieved successfully", results))
+}
+
+// Interlinks handles suggesting published articles a draft could link to,
+// from the keywords and tags it shares with them
+// POST /api/search/interlinks
+func (h *SearchHandler) Interlinks(c *gin.Context) {
+	var req services.InterlinkRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	suggestions, err := h.searchService.SuggestInterlinks(&req)
+	if err != nil {
+		respondError(c, err, "Failed to suggest interlinks")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Interlink suggestions retrieved successfully", suggestions))
+}
+
+// searchFilters reports the filters applied to a search request
+func searchFilters(req *services.SearchRequest) map[string]interface{} {
+	filters := map[string]interface{}{
+		"q":    req.Query,
+		"mode": req.SearchMode,
+	}
+	if len(req.Types) > 0 {
+		filters["type"] = req.Types
+	}
+	if req.CategoryID != 0 {
+		filters["category_id"] = req.CategoryID
+	}
+	if req.TagID != 0 {
+		filters["tag_id"] = req.TagID
+	}
+	if req.AuthorID != 0 {
+		filters["author_id"] = req.AuthorID
+	}
+	if !req.DateFrom.IsZero() {
+		filters["date_from"] = req.DateFrom.Format("2006-01-02")
+	}
+	if !req.DateTo.IsZero() {
+		filters["date_to"] = req.DateTo.Format("2006-01-02")
+	}
+	return filters
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SettingsHandler struct {
+	settingsService *services.UserSettingsService
+}
+
+// NewSettingsHandler creates a new settings handler
+func NewSettingsHandler(settingsService *services.UserSettingsService) *SettingsHandler {
+	return &SettingsHandler{
+		settingsService: settingsService,
+	}
+}
+
+// Get handles reading the current user's settings
+// GET /api/users/me/settings
+func (h *SettingsHandler) Get(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	settings, err := h.settingsService.Get(user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Settings retrieved successfully", settings))
+}
+
+// Update handles changing the current user's settings; omitted fields are left unchanged
+// PUT /api/users/me/settings
+func (h *SettingsHandler) Update(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateSettingsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	settings, err := h.settingsService.Update(user.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Settings updated successfully", settings))
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShortLinkHandler struct {
+	shortLinkService *services.ShortLinkService
+}
+
+// NewShortLinkHandler creates a new short link handler
+func NewShortLinkHandler(shortLinkService *services.ShortLinkService) *ShortLinkHandler {
+	return &ShortLinkHandler{
+		shortLinkService: shortLinkService,
+	}
+}
+
+// Get handles getting the short link of a published article, generating it on first use
+// GET /api/articles/:id/shortlink
+func (h *ShortLinkHandler) Get(c *gin.Context) {
+	articleID, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	link, err := h.shortLinkService.Get(articleID)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve short link")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Short link retrieved successfully", link))
+}
+
+// Redirect handles following a short link to its article, counting the click
+// GET /s/:code
+func (h *ShortLinkHandler) Redirect(c *gin.Context) {
+	target, err := h.shortLinkService.Resolve(c.Param("code"), c.Request.Referer())
+	if err != nil {
+		respondError(c, err, "Failed to follow short link")
+		return
+	}
+
+	c.Redirect(http.StatusFound, target)
+}
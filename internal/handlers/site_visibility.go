@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SiteVisibilityHandler struct {
+	siteService *services.SiteVisibilityService
+}
+
+// NewSiteVisibilityHandler creates a new site visibility handler
+func NewSiteVisibilityHandler(siteService *services.SiteVisibilityService) *SiteVisibilityHandler {
+	return &SiteVisibilityHandler{
+		siteService: siteService,
+	}
+}
+
+// Get handles reading the site visibility (admin only)
+// GET /api/admin/site-visibility
+func (h *SiteVisibilityHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse("Site visibility retrieved successfully", h.siteService.State()))
+}
+
+// Update handles making the blog public, unlisted or private (admin only);
+// omitted fields are left unchanged
+// PUT /api/admin/site-visibility
+func (h *SiteVisibilityHandler) Update(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.UpdateSiteVisibilityRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	state, err := h.siteService.Update(admin.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update site visibility")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Site visibility updated successfully", state))
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SLOHandler struct {
+	sloService *services.SLOService
+}
+
+// NewSLOHandler creates a new SLO handler
+func NewSLOHandler(sloService *services.SLOService) *SLOHandler {
+	return &SLOHandler{
+		sloService: sloService,
+	}
+}
+
+// Get handles reporting the error budget burn rates of the configured service
+// level objectives (admin only)
+// GET /api/admin/slo
+func (h *SLOHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, utils.SuccessResponse("Service level objectives retrieved successfully", h.sloService.Report(time.Now())))
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatisticsHandler struct {
+	statisticsService *services.StatisticsService
+}
+
+// NewStatisticsHandler creates a new statistics handler
+func NewStatisticsHandler(statisticsService *services.StatisticsService) *StatisticsHandler {
+	return &StatisticsHandler{
+		statisticsService: statisticsService,
+	}
+}
+
+// GetPopular handles listing the most popular articles
+// GET /api/stats/popular?limit=10
+func (h *StatisticsHandler) GetPopular(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	articles, err := h.statisticsService.GetPopularArticles(limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve popular articles"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Popular articles retrieved successfully", articles))
+}
+
+// GetTrending handles listing trending articles
+// GET /api/stats/trending?limit=10&days=7
+func (h *StatisticsHandler) GetTrending(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 365 {
+		days = 7
+	}
+
+	articles, err := h.statisticsService.GetTrendingArticles(limit, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve trending articles"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Trending articles retrieved successfully", articles))
+}
+
+// GetArticleStats handles statistics for a single article
+// GET /api/stats/articles/:id
+func (h *StatisticsHandler) GetArticleStats(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	stats, err := h.statisticsService.GetArticleStats(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.ErrorResponse("Article not found"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Article statistics retrieved successfully", stats))
+}
+
+// GetShortLinkStats handles the clicks on an article's short link and their top referrers
+// GET /api/stats/articles/:id/shortlink?limit=20
+func (h *StatisticsHandler) GetShortLinkStats(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	stats, err := h.statisticsService.GetShortLinkStats(id, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve short link statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Short link statistics retrieved successfully", stats))
+}
+
+// RecordView handles counting a read of a published article. The body may give
+// the referrer and UTM parameters of the page the reader landed on; without one
+// they are taken from the Referer header and the query string.
+// POST /api/articles/:id/views?utm_source=newsletter&utm_medium=email&utm_campaign=launch
+func (h *StatisticsHandler) RecordView(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "article")
+	if !ok {
+		return
+	}
+
+	req := services.RecordViewRequest{
+		Referrer:    c.Request.Referer(),
+		UTMSource:   c.Query("utm_source"),
+		UTMMedium:   c.Query("utm_medium"),
+		UTMCampaign: c.Query("utm_campaign"),
+	}
+	if c.Request.ContentLength != 0 {
+		req = services.RecordViewRequest{}
+		if !bindJSON(c, &req) {
+			return
+		}
+	}
+
+	if err := h.statisticsService.RecordView(id, &req); err != nil {
+		respondError(c, err, "Failed to record view")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("View recorded successfully", nil))
+}
+
+// GetAuthorStats handles summary and per-article statistics for an author, with
+// where the views of the last days came from
+// GET /api/stats/authors/:id?days=30
+func (h *StatisticsHandler) GetAuthorStats(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "author")
+	if !ok {
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > 365 {
+		days = 30
+	}
+
+	summary, err := h.statisticsService.GetAuthorSummaryStats(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve author statistics"))
+		return
+	}
+
+	articles, err := h.statisticsService.GetAuthorStats(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve author statistics"))
+		return
+	}
+
+	traffic, err := h.statisticsService.GetAuthorTraffic(id, days, 10)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve author statistics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Author statistics retrieved successfully", gin.H{
+		"summary":  summary,
+		"articles": articles,
+		"traffic":  traffic,
+	}))
+}
+
+// GetAuthorLeaderboard handles ranking the top authors by a total of their
+// published articles
+// GET /api/stats/authors?by=score&limit=10
+func (h *StatisticsHandler) GetAuthorLeaderboard(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	ranks, err := h.statisticsService.GetAuthorLeaderboard(c.DefaultQuery("by", "score"), limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve author leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Author leaderboard retrieved successfully", ranks))
+}
+
+// GetPeriodStats handles statistics aggregated by time period
+// GET /api/stats/periods?period=monthly&limit=12
+func (h *StatisticsHandler) GetPeriodStats(c *gin.Context) {
+	period := c.DefaultQuery("period", "monthly")
+	if period != "daily" && period != "monthly" && period != "yearly" {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("period must be one of: daily, monthly, yearly"))
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "12"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 12
+	}
+
+	stats, err := h.statisticsService.GetPeriodStats(period, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve period statistics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Period statistics retrieved successfully", stats))
+}
+
+// GetCommentCountReport handles checking the stored comment counters of
+// articles against their comments
+// GET /api/admin/stats/comment-counts?limit=50
+func (h *StatisticsHandler) GetCommentCountReport(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	report, err := h.statisticsService.GetCommentCountReport(limit)
+	if err != nil {
+		respondError(c, err, "Failed to check comment counts")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Comment count report retrieved successfully", report))
+}
@@ -43,23 +43,13 @@ func (h *TagHandler) List(c *gin.Context) {
 // POST /api/tags
 func (h *TagHandler) Create(c *gin.Context) {
 	var req services.CreateTagRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request data"))
+	if !bindJSON(c, &req) {
 		return
 	}
 
 	tag, err := h.tagService.Create(&req)
 	if err != nil {
-		if err.Error() == "tag name is required" || 
-		   err.Error() == "tag name must be less than 50 characters" {
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid tag data"))
-			return
-		}
-		if err.Error() == "tag with name '"+req.Name+"' already exists" {
-			c.JSON(http.StatusConflict, utils.ErrorResponse("Tag already exists"))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to create tag"))
+		respondError(c, err, "Failed to create tag")
 		return
 	}
 
@@ -77,11 +67,7 @@ func (h *TagHandler) GetBySlug(c *gin.Context) {
 
 	tag, err := h.tagService.GetBySlug(slug)
 	if err != nil {
-		if err.Error() == "slug cannot be empty" {
-			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid slug"))
-			return
-		}
-		c.JSON(http.StatusNotFound, utils.ErrorResponse("Tag not found"))
+		respondError(c, err, "Failed to retrieve tag")
 		return
 	}
 
@@ -110,28 +96,32 @@ func (h *TagHandler) GetTagArticles(c *gin.Context) {
 
 	articles, total, err := h.tagService.GetArticlesBySlug(slug, page, limit)
 	if err != nil {
-		if err.Error() == "tag not found" {
-			c.JSON(http.StatusNotFound, utils.ErrorResponse("Tag not found"))
-			return
-		}
-		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve articles"))
+		respondError(c, err, "Failed to retrieve articles")
 		return
 	}
 
-	// Calculate pagination info
-	totalPages := (int(total) + limit - 1) / limit
-	
-	response := gin.H{
-		"articles": articles,
-		"pagination": gin.H{
-			"page":        page,
-			"limit":       limit,
-			"total":       total,
-			"total_pages": totalPages,
-		},
+	c.JSON(http.StatusOK, utils.ListResponse("Articles retrieved successfully", articleList(c, articles), &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Filters:    map[string]interface{}{"tag": slug},
+		Sort:       "-created_at",
+	}))
+}
+
+// Autocomplete handles prefix tag lookups for editor tag pickers
+// GET /api/tags/autocomplete?q=go&limit=10
+func (h *TagHandler) Autocomplete(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	tags, err := h.tagService.Autocomplete(c.Query("q"), limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve tags")
+		return
 	}
 
-	c.JSON(http.StatusOK, utils.SuccessResponse("Articles retrieved successfully", response))
+	c.JSON(http.StatusOK, utils.SuccessResponse("Tags retrieved successfully", tags))
 }
 
 // getPopularTags handles getting popular tags with usage statistics
@@ -149,4 +139,107 @@ func (h *TagHandler) getPopularTags(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Popular tags retrieved successfully", tags))
-}
\ No newline at end of file
+}
+
+// UpdateDetails handles changing the description and search engine text of a tag
+// PUT /api/admin/tags/:id
+func (h *TagHandler) UpdateDetails(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid tag ID"))
+		return
+	}
+
+	var req services.UpdateTagDetailsRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	tag, err := h.tagService.UpdateDetails(uint(id), &req)
+	if err != nil {
+		respondError(c, err, "Failed to update tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Tag updated successfully", tag))
+}
+
+// ListOrphans reports tags that are not attached to any article
+// GET /api/admin/tags/orphans
+func (h *TagHandler) ListOrphans(c *gin.Context) {
+	report, err := h.tagService.CleanupOrphanTags(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to find orphan tags"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Orphan tags retrieved successfully", report))
+}
+
+// CleanupOrphans deletes tags that are not attached to any article, keeping protected tags
+// DELETE /api/admin/tags/orphans
+func (h *TagHandler) CleanupOrphans(c *gin.Context) {
+	report, err := h.tagService.CleanupOrphanTags(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to clean up orphan tags"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Orphan tags cleaned up successfully", report))
+}
+
+// ListAliases handles listing tag aliases
+// GET /api/admin/tag-aliases?tag_id=1
+func (h *TagHandler) ListAliases(c *gin.Context) {
+	var tagID uint64
+	if tagIDParam := c.Query("tag_id"); tagIDParam != "" {
+		var err error
+		tagID, err = strconv.ParseUint(tagIDParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid tag ID"))
+			return
+		}
+	}
+
+	aliases, err := h.tagService.ListAliases(uint(tagID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ErrorResponse("Failed to retrieve tag aliases"))
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Tag aliases retrieved successfully", aliases))
+}
+
+// CreateAlias handles tag alias creation
+// POST /api/admin/tag-aliases
+func (h *TagHandler) CreateAlias(c *gin.Context) {
+	var req services.CreateTagAliasRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	alias, err := h.tagService.CreateAlias(&req)
+	if err != nil {
+		respondError(c, err, "Failed to create tag alias")
+		return
+	}
+
+	c.JSON(http.StatusCreated, utils.SuccessResponse("Tag alias created successfully", alias))
+}
+
+// DeleteAlias handles tag alias deletion
+// DELETE /api/admin/tag-aliases/:id
+func (h *TagHandler) DeleteAlias(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid alias ID"))
+		return
+	}
+
+	if err := h.tagService.DeleteAlias(uint(id)); err != nil {
+		respondError(c, err, "Failed to delete tag alias")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Tag alias deleted successfully", nil))
+}
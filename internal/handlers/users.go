@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -22,7 +23,8 @@ func NewUserHandler(userService *services.UserService) *UserHandler {
 	}
 }
 
-// GetByID handles getting user by ID
+// GetByID handles getting user by ID, honoring the user's profile visibility
+// GET /api/users/:id
 func (h *UserHandler) GetByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.ParseUint(idParam, 10, 32)
@@ -31,14 +33,17 @@ func (h *UserHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.GetByID(uint(id))
+	viewerID := optionalUserID(c)
+
+	user, err := h.userService.GetProfile(viewerID, uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, utils.ErrorResponse("User not found"))
+		respondError(c, err, "Failed to retrieve user")
 		return
 	}
 
 	// Remove password from response
 	user.Password = ""
+	user.AvatarURL = user.Avatar()
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("User retrieved successfully", user))
 }
@@ -71,23 +76,169 @@ func (h *UserHandler) Update(c *gin.Context) {
 	}
 
 	var updateReq services.UpdateUserRequest
-	if err := c.ShouldBindJSON(&updateReq); err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Invalid request format"))
+	if !bindJSON(c, &updateReq) {
 		return
 	}
 
 	updatedUser, err := h.userService.UpdateProfile(uint(id), &updateReq)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, utils.ErrorResponse(err.Error()))
+		respondError(c, err, "Failed to update profile")
 		return
 	}
 
 	// Remove password from response
 	updatedUser.Password = ""
+	updatedUser.AvatarURL = updatedUser.Avatar()
 
 	c.JSON(http.StatusOK, utils.SuccessResponse("Profile updated successfully", updatedUser))
 }
 
+// UploadAvatar handles uploading the current user's avatar as the multipart "avatar" field
+// POST /api/users/me/avatar
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	// Leave room for the multipart envelope around the file itself
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxAvatarBytes+1<<20)
+
+	header, err := c.FormFile("avatar")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse("Avatar file is too large"))
+			return
+		}
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Avatar file is required"))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read avatar file"))
+		return
+	}
+	defer file.Close()
+
+	avatar, err := h.userService.SetAvatar(user.ID, file)
+	if err != nil {
+		respondError(c, err, "Failed to upload avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Avatar uploaded successfully", avatar))
+}
+
+// DeleteAvatar handles removing the current user's avatar, reverting to Gravatar
+// DELETE /api/users/me/avatar
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	avatar, err := h.userService.RemoveAvatar(user.ID)
+	if err != nil {
+		respondError(c, err, "Failed to remove avatar")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Avatar removed successfully", avatar))
+}
+
+// DeleteMe handles deleting the current user's account. The body chooses what
+// happens to their content and confirms the password.
+// DELETE /api/users/me
+func (h *UserHandler) DeleteMe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req services.DeleteAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.userService.DeleteOwnAccount(user.ID, &req)
+	if err != nil {
+		respondError(c, err, "Failed to delete account")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Account deleted successfully", result))
+}
+
+// Delete handles deleting any user's account (admin only)
+// DELETE /api/admin/users/:id
+func (h *UserHandler) Delete(c *gin.Context) {
+	admin, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req services.DeleteAccountRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	result, err := h.userService.DeleteAccount(admin.ID, id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to delete account")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Account deleted successfully", result))
+}
+
+// SetMembership handles changing a user's membership tier (admin only)
+// PUT /api/admin/users/:id/membership
+func (h *UserHandler) SetMembership(c *gin.Context) {
+	id, ok := parseIDParam(c, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req services.SetMembershipRequest
+	if !bindJSON(c, &req) {
+		return
+	}
+
+	user, err := h.userService.SetMembershipTier(id, &req)
+	if err != nil {
+		respondError(c, err, "Failed to update membership")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.SuccessResponse("Membership updated successfully", user))
+}
+
+// GetAuthor handles the public author page: profile, stats and published articles
+// GET /api/authors/:handle?page=1&limit=10
+func (h *UserHandler) GetAuthor(c *gin.Context) {
+	viewerID := optionalUserID(c)
+
+	page, limit := paginationParams(c)
+
+	author, total, err := h.userService.GetAuthorPage(viewerID, c.Param("handle"), page, limit)
+	if err != nil {
+		respondError(c, err, "Failed to retrieve author")
+		return
+	}
+
+	c.JSON(http.StatusOK, utils.ListResponse("Author retrieved successfully", author, &utils.Meta{
+		Pagination: utils.NewPagination(page, limit, total),
+		Sort:       "-published_at",
+	}))
+}
+
 // GetUserArticles handles getting articles by user
 func (h *UserHandler) GetUserArticles(c *gin.Context) {
 	idParam := c.Param("id")
@@ -114,5 +265,10 @@ func (h *UserHandler) GetUserArticles(c *gin.Context) {
 		return
 	}
 
-	utils.PaginatedSuccessResponse(c, articles, page, limit, total)
+	summaries := make([]models.ArticleSummary, 0, len(articles))
+	for _, article := range articles {
+		summaries = append(summaries, article.Summary())
+	}
+
+	utils.PaginatedSuccessResponse(c, "User articles retrieved successfully", summaries, page, limit, total)
 }
\ No newline at end of file
@@ -0,0 +1,45 @@
+package logging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxDebugTokenLifetime bounds how far ahead debug tokens may expire, so a
+// leaked token does not turn on debug logging for good
+const MaxDebugTokenLifetime = 24 * time.Hour
+
+// SignDebugToken returns the token turning on debug logging for requests sent
+// until expires: the expiry in Unix seconds, a dot and the hex HMAC-SHA256 of
+// the expiry under secret
+func SignDebugToken(secret string, expires time.Time) string {
+	expiry := strconv.FormatInt(expires.Unix(), 10)
+	return expiry + "." + debugSignature(secret, expiry)
+}
+
+// VerifyDebugToken reports whether token was signed with secret and has not
+// expired at now, nor expires beyond MaxDebugTokenLifetime
+func VerifyDebugToken(secret, token string, now time.Time) bool {
+	if secret == "" {
+		return false
+	}
+	expiry, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() > expires || expires > now.Add(MaxDebugTokenLifetime).Unix() {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(debugSignature(secret, expiry)))
+}
+
+func debugSignature(secret, expiry string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(expiry))
+	return hex.EncodeToString(mac.Sum(nil))
+}
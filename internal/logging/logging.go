@@ -0,0 +1,166 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go-blog/pkg/config"
+)
+
+// New creates a logger writing to w at the configured level and format. With
+// sampling on, repeated info and debug messages are thinned out; warnings and
+// errors are always written.
+func New(cfg config.LogConfig, w io.Writer) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	// The inner handler writes everything; levels are filtered by handler so
+	// requests with debug logging turned on get through
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	var inner slog.Handler
+	switch strings.ToLower(cfg.Format) {
+	case "", "json":
+		inner = slog.NewJSONHandler(w, options)
+	case "text":
+		inner = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+
+	h := &handler{Handler: inner, level: level}
+	if cfg.Sampling.Enabled {
+		h.sampler = newSampler(cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	}
+	return slog.New(h), nil
+}
+
+// Setup makes the configured logger writing to stderr the default of slog and
+// of the standard log package
+func Setup(cfg config.LogConfig) error {
+	logger, err := New(cfg, os.Stderr)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	return nil
+}
+
+// ParseLevel parses debug, info, warn or error; empty means info
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+type debugKey struct{}
+
+// WithDebug returns ctx with debug logging turned on, whatever the configured level
+func WithDebug(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugKey{}, true)
+}
+
+// DebugEnabled reports whether debug logging is turned on for ctx
+func DebugEnabled(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	on, _ := ctx.Value(debugKey{}).(bool)
+	return on
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns ctx carrying the ID of the request it serves, which is
+// added to every record logged with it
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or an empty string
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// handler filters records by level, except in debug contexts, samples them and
+// adds the request ID of their context
+type handler struct {
+	slog.Handler
+	level   slog.Level
+	sampler *sampler // nil without sampling
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level || DebugEnabled(ctx)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	if h.sampler != nil && record.Level < slog.LevelWarn && !DebugEnabled(ctx) && !h.sampler.keep(record.Message, record.Time) {
+		return nil
+	}
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{Handler: h.Handler.WithAttrs(attrs), level: h.level, sampler: h.sampler}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{Handler: h.Handler.WithGroup(name), level: h.level, sampler: h.sampler}
+}
+
+// sampler keeps the first initial records of a message each second, then one
+// in thereafter
+type sampler struct {
+	mu         sync.Mutex
+	initial    int
+	thereafter int
+	second     int64
+	counts     map[string]int // records of each message during second
+}
+
+func newSampler(initial, thereafter int) *sampler {
+	if thereafter < 1 {
+		thereafter = 1
+	}
+	return &sampler{initial: initial, thereafter: thereafter, counts: make(map[string]int)}
+}
+
+// keep reports whether a record of message logged at t is written
+func (s *sampler) keep(message string, t time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if second := t.Unix(); second != s.second {
+		s.second = second
+		s.counts = make(map[string]int)
+	}
+	s.counts[message]++
+	n := s.counts[message]
+	return n <= s.initial || (n-s.initial)%s.thereafter == 0
+}
@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelAndDebugContext(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(config.LogConfig{Level: "warn", Format: "text"}, &out)
+	require.NoError(t, err)
+
+	logger.Info("hidden")
+	logger.Warn("shown")
+	logger.DebugContext(WithDebug(context.Background()), "debugging", "id", 7)
+
+	assert.NotContains(t, out.String(), "hidden")
+	assert.Contains(t, out.String(), "msg=shown")
+	assert.Contains(t, out.String(), "msg=debugging id=7")
+}
+
+func TestRequestIDAttribute(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(config.LogConfig{}, &out)
+	require.NoError(t, err)
+
+	logger.InfoContext(WithRequestID(context.Background(), "abc123"), "handled")
+
+	assert.Contains(t, out.String(), `"request_id":"abc123"`)
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	_, err := New(config.LogConfig{Level: "verbose"}, &bytes.Buffer{})
+	assert.Error(t, err)
+	_, err = New(config.LogConfig{Format: "xml"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestSampling(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(config.LogConfig{Sampling: config.LogSamplingConfig{Enabled: true, Initial: 2, Thereafter: 3}}, &out)
+	require.NoError(t, err)
+
+	for i := 0; i < 8; i++ {
+		logger.Info("repeated")
+		logger.Error("failing")
+	}
+
+	// The first 2, then the 3rd and 6th after them; errors are never sampled
+	assert.Equal(t, 4, strings.Count(out.String(), `"msg":"repeated"`))
+	assert.Equal(t, 8, strings.Count(out.String(), `"msg":"failing"`))
+}
+
+func TestSamplerStartsOverEachSecond(t *testing.T) {
+	s := newSampler(1, 10)
+	now := time.Unix(1700000000, 0)
+
+	assert.True(t, s.keep("message", now))
+	assert.False(t, s.keep("message", now))
+	assert.True(t, s.keep("other", now))
+	assert.True(t, s.keep("message", now.Add(time.Second)))
+}
+
+func TestDebugToken(t *testing.T) {
+	now := time.Now()
+	token := SignDebugToken("secret", now.Add(time.Hour))
+
+	assert.True(t, VerifyDebugToken("secret", token, now))
+	assert.False(t, VerifyDebugToken("other", token, now), "wrong secret")
+	assert.False(t, VerifyDebugToken("", token, now), "debug logging disabled")
+	assert.False(t, VerifyDebugToken("secret", token, now.Add(2*time.Hour)), "expired")
+	assert.False(t, VerifyDebugToken("secret", SignDebugToken("secret", now.Add(48*time.Hour)), now), "too long lived")
+	assert.False(t, VerifyDebugToken("secret", "garbage", now))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelInfo, level)
+
+	level, err = ParseLevel("DEBUG")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, level)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"go-blog/internal/models"
 	"go-blog/internal/services"
 	"go-blog/internal/utils"
 
@@ -29,7 +30,7 @@ func Auth(authService *services.AuthService) gin.HandlerFunc {
 		}
 
 		token := tokenParts[1]
-		user, err := authService.GetUserFromToken(token)
+		user, sessionID, err := authService.Authenticate(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("Invalid or expired token"))
 			c.Abort()
@@ -39,6 +40,7 @@ func Auth(authService *services.AuthService) gin.HandlerFunc {
 		// Set user information in context for use in handlers
 		c.Set("userID", user.ID)
 		c.Set("user", user)
+		c.Set("sessionID", sessionID)
 		c.Next()
 	}
 }
@@ -60,7 +62,7 @@ func OptionalAuth(authService *services.AuthService) gin.HandlerFunc {
 		}
 
 		token := tokenParts[1]
-		user, err := authService.GetUserFromToken(token)
+		user, sessionID, err := authService.Authenticate(token)
 		if err != nil {
 			c.Next()
 			return
@@ -69,6 +71,38 @@ func OptionalAuth(authService *services.AuthService) gin.HandlerFunc {
 		// Set user information in context if valid
 		c.Set("userID", user.ID)
 		c.Set("user", user)
+		c.Set("sessionID", sessionID)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// bearerToken returns the access token of the request's Authorization header
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(c.GetHeader("Authorization"), " ")
+	if !ok || scheme != "Bearer" || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// RequireAdmin middleware restricts access to administrators.
+// It must be registered after Auth so the user is present in the context.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, exists := c.Get("user")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, utils.ErrorResponse("User not authenticated"))
+			c.Abort()
+			return
+		}
+
+		userModel, ok := user.(*models.User)
+		if !ok || !userModel.IsAdmin() {
+			c.JSON(http.StatusForbidden, utils.ErrorResponse("Administrator access required"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
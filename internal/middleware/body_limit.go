@@ -0,0 +1,184 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bodyLimitContextKey is the gin context key of the request's limited body
+const bodyLimitContextKey = "bodyLimit"
+
+// BodyLimits caps the size of request bodies: maxBody for most routes and
+// maxUpload for upload routes, with per-route overrides. A limit of 0 or less
+// leaves bodies unlimited.
+type BodyLimits struct {
+	maxBody     int64
+	maxUpload   int64
+	uploadTypes []string
+	overrides   map[string]int64 // limits replacing the defaults, keyed by path after /api
+}
+
+// NewBodyLimits creates body limits. uploadTypes are the content types files
+// sent to upload routes may have, sniffed from their content; "image/*"
+// matches every image type.
+func NewBodyLimits(maxBody, maxUpload int64, uploadTypes []string, overrides map[string]int64) *BodyLimits {
+	return &BodyLimits{
+		maxBody:     maxBody,
+		maxUpload:   maxUpload,
+		uploadTypes: uploadTypes,
+		overrides:   overrides,
+	}
+}
+
+// Limit returns the middleware capping request bodies at the limit of their
+// route. Reading past it fails with an *http.MaxBytesError, which handlers
+// answer with 413; bodies declaring a larger Content-Length fail on the first
+// read without being read at all.
+func (l *BodyLimits) Limit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{body: c.Request.Body, limit: l.maxBody, declared: c.Request.ContentLength}
+		if route, ok := apiPath(c.FullPath()); ok {
+			if override, ok := l.overrides[route]; ok {
+				body.limit = override
+				body.fixed = true
+			}
+		}
+		c.Request.Body = body
+		c.Set(bodyLimitContextKey, body)
+		c.Next()
+	}
+}
+
+// Upload returns the middleware of upload routes. It raises the body limit to
+// the upload limit unless the route's limit is overridden, requires multipart
+// form data, and refuses files whose sniffed content type is not allowed with
+// 415.
+func (l *BodyLimits) Upload() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if body, ok := c.Get(bodyLimitContextKey); ok {
+			if limited := body.(*limitedBody); !limited.fixed {
+				limited.limit = l.maxUpload
+			}
+		}
+
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err != nil || mediaType != "multipart/form-data" {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, utils.ErrorResponse("Uploads must be sent as multipart/form-data"))
+			return
+		}
+
+		// Files beyond 32 MB in memory spill to temporary files
+		if err := c.Request.ParseMultipartForm(32 << 20); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, utils.ErrorResponse(
+					fmt.Sprintf("Request body is too large; the limit is %d bytes", maxBytesErr.Limit)))
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, utils.ErrorResponse("Invalid multipart form"))
+			return
+		}
+
+		for _, files := range c.Request.MultipartForm.File {
+			for _, header := range files {
+				contentType, err := sniffContentType(header)
+				if err != nil {
+					c.AbortWithStatusJSON(http.StatusBadRequest, utils.ErrorResponse("Failed to read uploaded file"))
+					return
+				}
+				if !l.uploadTypeAllowed(contentType) {
+					c.AbortWithStatusJSON(http.StatusUnsupportedMediaType,
+						utils.ErrorResponse("Files of type "+contentType+" cannot be uploaded"))
+					return
+				}
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// uploadTypeAllowed reports whether files of contentType may be uploaded; any
+// type may when none are configured
+func (l *BodyLimits) uploadTypeAllowed(contentType string) bool {
+	if len(l.uploadTypes) == 0 {
+		return true
+	}
+	for _, allowed := range l.uploadTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok {
+			if strings.HasPrefix(contentType, prefix+"/") {
+				return true
+			}
+		} else if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// sniffContentType returns the content type of an uploaded file from its
+// first bytes, without parameters such as the charset
+func sniffContentType(header *multipart.FileHeader) (string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	contentType, _, _ := strings.Cut(http.DetectContentType(head[:n]), ";")
+	return contentType, nil
+}
+
+// limitedBody fails reads past limit with an *http.MaxBytesError. The limit
+// may be raised until reading starts.
+type limitedBody struct {
+	body     io.ReadCloser
+	limit    int64 // 0 or less is unlimited
+	declared int64 // Content-Length, -1 when unknown
+	read     int64
+	fixed    bool // set by a route override, which upload routes keep
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.limit <= 0 {
+		return b.body.Read(p)
+	}
+	if b.declared > b.limit || b.read > b.limit {
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+
+	// Read one byte past the limit to tell a body of exactly limit bytes from
+	// a longer one
+	if remaining := b.limit - b.read + 1; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := b.body.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n - int(b.read-b.limit), &http.MaxBytesError{Limit: b.limit}
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
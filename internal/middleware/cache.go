@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheHeader reports whether a response was served from the response cache
+const CacheHeader = "X-Cache"
+
+// requestHeaders are response headers describing one request, such as whether
+// its origin may read the response; they are not stored with cached responses
+var requestHeaders = []string{
+	RequestIDHeader,
+	"Access-Control-Allow-Origin",
+	"Access-Control-Allow-Credentials",
+	"Access-Control-Expose-Headers",
+	"Vary",
+}
+
+// ResponseCache keeps the responses of anonymous GET requests in memory for a
+// per-route time to live. Any successful write request empties it, so cached
+// reads never outlive the change that made them stale.
+type ResponseCache struct {
+	mu         sync.Mutex
+	entries    map[string]*cachedResponse
+	generation uint64                   // bumped by every purge, so reads racing a write are not stored
+	overrides  map[string]time.Duration // TTLs replacing the route defaults, keyed by path after /api
+	maxEntries int
+}
+
+// cachedResponse is a stored response with its expiry
+type cachedResponse struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// NewResponseCache creates a response cache holding at most maxEntries
+// responses. overrides replaces the TTL of routes, keyed by their path after
+// the /api or /api/<version> prefix, such as "/articles/:id"; a zero TTL turns
+// caching of the route off.
+func NewResponseCache(maxEntries int, overrides map[string]time.Duration) *ResponseCache {
+	return &ResponseCache{
+		entries:    make(map[string]*cachedResponse),
+		overrides:  overrides,
+		maxEntries: maxEntries,
+	}
+}
+
+// Route returns the middleware caching the successful anonymous GET responses
+// of a route for ttl, unless the configuration overrides it. Requests carrying
+// an Authorization header may be personalized and always reach the handler.
+func (rc *ResponseCache) Route(ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+		ttl := ttl
+		if route, ok := apiPath(c.FullPath()); ok {
+			if override, ok := rc.overrides[route]; ok {
+				ttl = override
+			}
+		}
+		if ttl <= 0 {
+			c.Next()
+			return
+		}
+
+		key := c.Request.URL.RequestURI()
+		cached, generation := rc.get(key)
+		if cached != nil {
+			for name, values := range cached.header {
+				c.Writer.Header()[name] = values
+			}
+			c.Header(CacheHeader, "HIT")
+			c.Writer.WriteHeader(cached.status)
+			c.Writer.Write(cached.body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Header(CacheHeader, "MISS")
+		c.Next()
+
+		// Responses setting cookies belong to one client
+		if recorder.Status() != http.StatusOK || recorder.Header().Get("Set-Cookie") != "" {
+			return
+		}
+		header := recorder.Header().Clone()
+		for _, name := range requestHeaders {
+			header.Del(name)
+		}
+		rc.put(key, generation, &cachedResponse{
+			status:  recorder.Status(),
+			header:  header,
+			body:    recorder.body.Bytes(),
+			expires: time.Now().Add(ttl),
+		})
+	}
+}
+
+// Invalidate returns the middleware emptying the cache after every successful
+// write request
+func (rc *ResponseCache) Invalidate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if !isSafeMethod(c.Request.Method) && c.Writer.Status() < http.StatusBadRequest {
+			rc.Purge()
+		}
+	}
+}
+
+// Purge empties the cache
+func (rc *ResponseCache) Purge() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries = make(map[string]*cachedResponse)
+	rc.generation++
+}
+
+// get returns the live response stored under key, or nil, with the current
+// generation of the cache
+func (rc *ResponseCache) get(key string) (*cachedResponse, uint64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	cached, ok := rc.entries[key]
+	if !ok || time.Now().After(cached.expires) {
+		return nil, rc.generation
+	}
+	return cached, rc.generation
+}
+
+// put stores response under key unless the cache was purged since generation.
+// When full, expired responses are dropped first and response is not stored
+// if none were.
+func (rc *ResponseCache) put(key string, generation uint64, response *cachedResponse) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if generation != rc.generation {
+		return
+	}
+	if _, ok := rc.entries[key]; !ok && len(rc.entries) >= rc.maxEntries {
+		now := time.Now()
+		for stored, cached := range rc.entries {
+			if now.After(cached.expires) {
+				delete(rc.entries, stored)
+			}
+		}
+		if len(rc.entries) >= rc.maxEntries {
+			return
+		}
+	}
+	rc.entries[key] = response
+}
+
+// responseRecorder copies the body written to the response
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
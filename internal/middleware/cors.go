@@ -1,22 +1,109 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-blog/pkg/config"
+
 	"github.com/gin-gonic/gin"
 )
 
-// CORS middleware for handling Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
-	return gin.HandlerFunc(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+// defaultCORSMethods and defaultCORSHeaders are allowed when the configuration
+// lists none
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	defaultCORSHeaders = []string{"Content-Type", "Authorization", "X-CSRF-Token", "X-Requested-With", "Cache-Control"}
+)
+
+// CORS middleware handles Cross-Origin Resource Sharing for the configured
+// origins. Requests from other origins get no CORS headers, so browsers keep
+// them from reading responses. The allowed origin is echoed rather than "*"
+// whenever credentials are allowed, as browsers require.
+func CORS(cfg config.CORSConfig) gin.HandlerFunc {
+	methods := cfg.AllowedMethods
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := cfg.AllowedHeaders
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(headers, ", ")
+	exposeHeaders := strings.Join(cfg.ExposedHeaders, ", ")
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Origin")
+		if !originAllowed(cfg.AllowedOrigins, origin) {
+			if preflight {
+				c.AbortWithStatus(http.StatusNoContent)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if isAnyOrigin(cfg.AllowedOrigins) && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		if exposeHeaders != "" {
+			c.Header("Access-Control-Expose-Headers", exposeHeaders)
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", strconv.Itoa(cfg.MaxAge))
+			}
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
-	})
-}
\ No newline at end of file
+	}
+}
+
+// originAllowed reports whether origin matches one of allowed: "*" for any
+// origin, an exact origin, or a pattern such as "https://*.example.com" for
+// its subdomains
+func originAllowed(allowed []string, origin string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" || strings.EqualFold(pattern, origin) {
+			return true
+		}
+		scheme, domain, ok := strings.Cut(pattern, "://*.")
+		if !ok {
+			continue
+		}
+		host, ok := strings.CutPrefix(strings.ToLower(origin), strings.ToLower(scheme)+"://")
+		if ok && strings.HasSuffix(host, "."+strings.ToLower(domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAnyOrigin reports whether allowed lets any origin in
+func isAnyOrigin(allowed []string) bool {
+	for _, pattern := range allowed {
+		if pattern == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeprecationNotice tells the clients of a deprecated route when it was
+// deprecated, when it goes away and what to use instead
+type DeprecationNotice struct {
+	Since   time.Time // sent as the Deprecation header
+	Sunset  time.Time // sent as the Sunset header; zero until a removal date is set
+	Link    string    // documentation of the replacement, sent as a Link with rel="deprecation"
+	Warning string    // added to the warnings of the JSON envelope
+}
+
+// Deprecated returns the middleware announcing notice on every response of a route
+func Deprecated(notice DeprecationNotice) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		notice.Serve(c, nil)
+	}
+}
+
+// Reshape rewrites the data of a response envelope into another shape
+type Reshape func(data json.RawMessage) (json.RawMessage, error)
+
+// Serve sets the deprecation headers of notice, runs the rest of the chain and
+// adds the warning to the JSON envelope it writes, after rewriting the
+// envelope's data with reshape when it is set. The response is buffered to do
+// so, which suits the JSON routes of the API but not streamed ones.
+func (n DeprecationNotice) Serve(c *gin.Context, reshape Reshape) {
+	header := c.Writer.Header()
+	if !n.Since.IsZero() {
+		header.Set("Deprecation", fmt.Sprintf("@%d", n.Since.Unix()))
+	}
+	if !n.Sunset.IsZero() {
+		header.Set("Sunset", n.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if n.Link != "" {
+		header.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", n.Link))
+	}
+	if n.Warning == "" && reshape == nil {
+		c.Next()
+		return
+	}
+
+	writer := c.Writer
+	buffer := &bufferedWriter{ResponseWriter: writer}
+	c.Writer = buffer
+	c.Next()
+	c.Writer = writer
+
+	body := buffer.body.Bytes()
+	if strings.HasPrefix(writer.Header().Get("Content-Type"), "application/json") {
+		body = rewriteEnvelope(c, body, n.Warning, reshape)
+	}
+	if len(body) > 0 {
+		writer.Write(body)
+	}
+}
+
+// rewriteEnvelope reshapes the data of the JSON envelope body and adds
+// warning to its warnings; bodies that are not envelopes are left as they are
+func rewriteEnvelope(c *gin.Context, body []byte, warning string, reshape Reshape) []byte {
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope == nil {
+		return body
+	}
+
+	if data, ok := envelope["data"]; ok && reshape != nil {
+		reshaped, err := reshape(data)
+		if err != nil {
+			slog.Error("Failed to reshape response", "path", c.FullPath(), "error", err)
+			return body
+		}
+		envelope["data"] = reshaped
+	}
+	if warning != "" {
+		var warnings []string
+		if raw, ok := envelope["warnings"]; ok {
+			json.Unmarshal(raw, &warnings)
+		}
+		encoded, err := json.Marshal(append(warnings, warning))
+		if err != nil {
+			return body
+		}
+		envelope["warnings"] = encoded
+	}
+
+	rewritten, err := json.Marshal(envelope)
+	if err != nil {
+		return body
+	}
+	return rewritten
+}
+
+// bufferedWriter holds the body back so it can be rewritten before it is sent
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
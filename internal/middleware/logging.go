@@ -1,25 +1,63 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"go-blog/internal/logging"
+
 	"github.com/gin-gonic/gin"
 )
 
-// Logger middleware for request logging
-func Logger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return log.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
-}
\ No newline at end of file
+// DebugLogHeader carries a token signed with the log debug secret that turns
+// on debug logging for one request, whatever the configured level
+const DebugLogHeader = "X-Debug-Log"
+
+// redactedHeaders are request headers left out of debug logs
+var redactedHeaders = map[string]bool{"Authorization": true, "Cookie": true, "X-Csrf-Token": true, DebugLogHeader: true}
+
+// Logger middleware writes an access log record per request through the
+// structured logger. Requests with a valid DebugLogHeader also log their
+// headers, and everything logged with their context at debug level.
+func Logger(debugSecret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := c.Request.Context()
+		if token := c.GetHeader(DebugLogHeader); token != "" && logging.VerifyDebugToken(debugSecret, token, start) {
+			ctx = logging.WithDebug(ctx)
+			c.Request = c.Request.WithContext(ctx)
+			slog.DebugContext(ctx, "Request received",
+				"method", c.Request.Method,
+				"uri", c.Request.URL.RequestURI(),
+				"headers", loggedHeaders(c.Request.Header),
+			)
+		}
+
+		c.Next()
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+		slog.InfoContext(ctx, "Request", attrs...)
+	}
+}
+
+// loggedHeaders returns the request headers with credentials left out
+func loggedHeaders(header http.Header) map[string]string {
+	logged := make(map[string]string, len(header))
+	for name := range header {
+		if !redactedHeaders[name] {
+			logged[name] = header.Get(name)
+		}
+	}
+	return logged
+}
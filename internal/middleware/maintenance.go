@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaintenanceHeader is set on every response while in maintenance, so clients
+// can show a banner on the reads that are still served
+const MaintenanceHeader = "X-Maintenance-Mode"
+
+// maintenanceExemptPaths are API paths, after the /api or /api/<version> prefix,
+// served during maintenance: admin routes and the sign-in admins need to reach them
+var maintenanceExemptPaths = []string{"/admin/", "/auth/login", "/auth/refresh"}
+
+// Maintenance middleware refuses requests with 503 and the maintenance banner
+// while maintenance mode is on. Writes are refused, and reads as well when the
+// mode blocks them; admin routes are always served.
+func Maintenance(maintenanceService *services.MaintenanceService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := maintenanceService.State()
+		if !state.Enabled {
+			c.Next()
+			return
+		}
+
+		c.Header(MaintenanceHeader, "on")
+		if isMaintenanceExempt(c.Request.URL.Path) || (!state.BlockReads && isSafeMethod(c.Request.Method)) {
+			c.Next()
+			return
+		}
+
+		if state.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(state.RetryAfter))
+		}
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, utils.APIResponse{
+			Success: false,
+			Message: state.Message,
+			Data:    gin.H{"maintenance": state},
+		})
+	}
+}
+
+// isMaintenanceExempt reports whether path is served during maintenance
+func isMaintenanceExempt(path string) bool {
+	rest, ok := apiPath(path)
+	if !ok {
+		return false
+	}
+
+	for _, exempt := range maintenanceExemptPaths {
+		if strings.HasPrefix(rest, exempt) {
+			return true
+		}
+	}
+	return false
+}
+
+// apiPath returns path after its /api or /api/<version> prefix, and false for
+// paths outside the API
+func apiPath(path string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api")
+	if !ok {
+		return "", false
+	}
+	// Skip a version segment such as /v1
+	if len(rest) > 2 && rest[1] == 'v' {
+		if end := strings.IndexByte(rest[1:], '/'); end > 1 {
+			if _, err := strconv.Atoi(rest[2 : end+1]); err == nil {
+				rest = rest[end+1:]
+			}
+		}
+	}
+	return rest, true
+}
+
+// isSafeMethod reports whether method only reads
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
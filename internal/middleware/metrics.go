@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"go-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics middleware counts the outcome and latency of API requests towards the
+// service level objectives of their route group. Requests matching no route are
+// left out, so probing unknown paths does not burn error budgets. Panics count
+// as server errors before they reach the recovery middleware.
+func Metrics(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		defer func() {
+			recovered := recover()
+			if route, ok := apiPath(c.FullPath()); ok {
+				status := c.Writer.Status()
+				if recovered != nil {
+					status = http.StatusInternalServerError
+				}
+				sloService.Observe(route, status, time.Since(start), start)
+			}
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+
+		c.Next()
+	}
+}
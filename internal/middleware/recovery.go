@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PanicReporter sends panics recovered while serving requests to an error tracker
+type PanicReporter interface {
+	ReportPanic(r *http.Request, recovered any, requestID string)
+}
+
+// Recovery middleware turns panics in handlers into a 500 response carrying
+// the request ID, logs them with their stack and reports them when reporter is
+// not nil. Panics with http.ErrAbortHandler keep aborting the connection.
+func Recovery(reporter PanicReporter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			requestID := GetRequestID(c)
+			slog.ErrorContext(c.Request.Context(), "Panic recovered",
+				"method", c.Request.Method,
+				"route", c.FullPath(),
+				"panic", recovered,
+				"stack", string(debug.Stack()),
+			)
+			if reporter != nil {
+				reporter.ReportPanic(c.Request, recovered, requestID)
+			}
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, utils.APIResponse{
+				Success: false,
+				Message: "Internal server error",
+				Data:    gin.H{"request_id": requestID},
+			})
+		}()
+
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"go-blog/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID of a request, echoed on its response
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDContextKey is the gin context key of the request ID
+const requestIDContextKey = "requestID"
+
+// validRequestID matches request IDs accepted from clients and proxies
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID middleware gives every request an ID, keeping one set by a proxy
+// in front when it is well formed. The ID is echoed in the response header and
+// added to everything logged with the request's context.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDContextKey, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID returns the ID of the request, or an empty string outside the
+// RequestID middleware
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDContextKey)
+}
+
+// newRequestID returns a random 128-bit hex ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// privateExemptPaths are API paths, after the /api or /api/<version> prefix,
+// served to signed-out clients of a private blog: signing in and up, the
+// policies registering accepts, and links sent by email
+var privateExemptPaths = []string{"/auth/", "/policies", "/comment-subscriptions/unsubscribe"}
+
+// privatePaths are paths outside the API that private blogs restrict too:
+// short links redirect to article slugs
+var privatePaths = []string{"/s/"}
+
+// SiteVisibility middleware enforces the visibility of the blog. Unlisted and
+// private blogs ask search engines not to index any response; private blogs
+// refuse API requests and short links without a valid access token with 401
+// and the coming soon message.
+func SiteVisibility(siteService *services.SiteVisibilityService, authService *services.AuthService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := siteService.State()
+		if state.Visibility == services.SiteVisibilityPublic {
+			c.Next()
+			return
+		}
+
+		c.Header("X-Robots-Tag", "noindex, nofollow")
+		if state.Visibility != services.SiteVisibilityPrivate || isPrivateExempt(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+		if token, ok := bearerToken(c); ok {
+			if _, _, err := authService.Authenticate(token); err == nil {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, utils.APIResponse{
+			Success: false,
+			Message: state.Message,
+			Data:    gin.H{"site": state},
+		})
+	}
+}
+
+// isPrivateExempt reports whether path is served to signed-out clients of a
+// private blog. Paths outside the API, such as uploads, are not restricted
+// unless listed in privatePaths.
+func isPrivateExempt(path string) bool {
+	rest, ok := apiPath(path)
+	if !ok {
+		for _, private := range privatePaths {
+			if strings.HasPrefix(path, private) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, exempt := range privateExemptPaths {
+		if strings.HasPrefix(rest, exempt) {
+			return true
+		}
+	}
+	return false
+}
@@ -16,27 +16,73 @@ const (
 	StatusArchived  ArticleStatus = "archived"
 )
 
+// ArticleVisibility decides which readers get the full content of an article
+type ArticleVisibility string
+
+const (
+	ArticleVisibilityPublic  ArticleVisibility = "public"  // every reader
+	ArticleVisibilityMembers ArticleVisibility = "members" // members on any paid tier
+	ArticleVisibilityPremium ArticleVisibility = "premium" // premium members only
+)
+
 type Article struct {
-	ID           uint           `json:"id" gorm:"primaryKey"`
-	Title        string         `json:"title" gorm:"size:255;not null" validate:"required,min=1,max=255"`
-	Slug         string         `json:"slug" gorm:"uniqueIndex;size:255;not null" validate:"required,slug,max=255"`
-	Content      string         `json:"content" gorm:"type:longtext;not null" validate:"required,min=1"`
-	Excerpt      string         `json:"excerpt" gorm:"type:text" validate:"omitempty,max=500"`
-	AuthorID     uint           `json:"author_id" gorm:"not null" validate:"required,min=1"`
-	Author       User           `json:"author" gorm:"foreignKey:AuthorID"`
-	CategoryID   *uint          `json:"category_id" validate:"omitempty,min=1"`
-	Category     *Category      `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
-	Tags         []Tag          `json:"tags,omitempty" gorm:"many2many:article_tags"`
-	Comments     []Comment      `json:"comments,omitempty"`
-	Likes        []Like         `json:"likes,omitempty"`
-	Status       ArticleStatus  `json:"status" gorm:"type:enum('draft','published','archived');default:'draft'" validate:"required,article_status"`
-	ViewCount    uint           `json:"view_count" gorm:"default:0"`
-	LikeCount    uint           `json:"like_count" gorm:"default:0"`
-	CommentCount uint           `json:"comment_count" gorm:"default:0"`
-	PublishedAt  *time.Time     `json:"published_at"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uint              `json:"id" gorm:"primaryKey"`
+	Title         string            `json:"title" gorm:"size:255;not null" validate:"required,min=1,max=255"`
+	Slug          string            `json:"slug" gorm:"uniqueIndex;size:255;not null" validate:"required,slug,max=255"`
+	Content       string            `json:"content" gorm:"type:longtext;not null" validate:"required,min=1"`
+	Excerpt       string            `json:"excerpt" gorm:"type:text" validate:"omitempty,max=500"`
+	AuthorID      uint              `json:"author_id" gorm:"not null;index:idx_articles_author_status,priority:1" validate:"required,min=1"`
+	Author        User              `json:"author" gorm:"foreignKey:AuthorID" validate:"-"`
+	CategoryID    *uint             `json:"category_id" gorm:"index:idx_articles_category_status,priority:1" validate:"omitempty,min=1"`
+	Category      *Category         `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Tags          []Tag             `json:"tags,omitempty" gorm:"many2many:article_tags"`
+	Comments      []Comment         `json:"comments,omitempty"`
+	Likes         []Like            `json:"likes,omitempty"`
+	Status        ArticleStatus     `json:"status" gorm:"size:20;default:'draft';index:idx_articles_status_published,priority:1;index:idx_articles_author_status,priority:2;index:idx_articles_category_status,priority:2" validate:"required,article_status"`
+	ViewCount     uint              `json:"view_count" gorm:"default:0"`
+	LikeCount     uint              `json:"like_count" gorm:"default:0"`
+	CommentCount  uint              `json:"comment_count" gorm:"default:0"`
+	PublishedAt   *time.Time        `json:"published_at" gorm:"index:idx_articles_status_published,priority:2"`
+	ExpiresAt     *time.Time        `json:"expires_at" gorm:"index"`                     // archived by the scheduler once passed
+	AllowComments *bool             `json:"allow_comments" gorm:"not null;default:true"` // nil counts as allowed
+	AllowLikes    *bool             `json:"allow_likes" gorm:"not null;default:true"`
+	NoIndex       bool              `json:"noindex" gorm:"not null;default:false"` // kept out of search engines
+	OGImageKey    string            `json:"-" gorm:"size:255;column:og_image_key"`
+	OGImageURL    string            `json:"og_image_url,omitempty" gorm:"size:255;column:og_image_url"`
+	Visibility    ArticleVisibility `json:"visibility" gorm:"size:20;not null;default:'public'" validate:"omitempty,oneof=public members premium"`
+	Locked        bool              `json:"locked" gorm:"-"`             // content cut to a preview for the reader
+	Liked         *bool             `json:"is_liked,omitempty" gorm:"-"` // whether the reader liked it, unset for anonymous readers
+	Meta          []ArticleMeta     `json:"meta,omitempty" gorm:"-"`     // custom fields, set on the detail response
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt    `json:"-" gorm:"index"`
+}
+
+// Expired reports whether the article's expiry has passed at now
+func (a *Article) Expired(now time.Time) bool {
+	return a.ExpiresAt != nil && !a.ExpiresAt.After(now)
+}
+
+// CommentsAllowed reports whether readers may comment on the article
+func (a *Article) CommentsAllowed() bool {
+	return a.AllowComments == nil || *a.AllowComments
+}
+
+// LikesAllowed reports whether readers may like the article
+func (a *Article) LikesAllowed() bool {
+	return a.AllowLikes == nil || *a.AllowLikes
+}
+
+// ReadableBy reports whether viewer, nil for anonymous readers, gets the full
+// content of the article. Its author and admins always do.
+func (a *Article) ReadableBy(viewer *User) bool {
+	if a.Visibility == "" || a.Visibility == ArticleVisibilityPublic {
+		return true
+	}
+	if viewer == nil {
+		return false
+	}
+	return viewer.ID == a.AuthorID || viewer.IsAdmin() || viewer.Entitled(a.Visibility)
 }
 
 // TableName specifies the table name for the Article model
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// ArticleLink is the outcome of the last check of an external link in a
+// published article. The links of an article are replaced on every check.
+type ArticleLink struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	ArticleID   uint       `json:"article_id" gorm:"not null;index"`
+	URL         string     `json:"url" gorm:"size:2048;not null"`
+	StatusCode  int        `json:"status_code"`                     // 0 when no response was received
+	Error       string     `json:"error,omitempty" gorm:"size:255"` // why the request failed, if it did
+	Broken      bool       `json:"broken" gorm:"not null;index"`    // not found, gone, server error or unreachable
+	BrokenSince *time.Time `json:"broken_since,omitempty"`          // first check that found the link broken
+	CheckedAt   time.Time  `json:"checked_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for the ArticleLink model
+func (ArticleLink) TableName() string {
+	return "article_links"
+}
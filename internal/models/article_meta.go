@@ -0,0 +1,84 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// MaxArticleMetaValueSize bounds the JSON value of a custom field, in bytes
+const MaxArticleMetaValueSize = 16 << 10
+
+// ArticleMetaType is the type of the value of a custom field
+type ArticleMetaType string
+
+const (
+	ArticleMetaString ArticleMetaType = "string"
+	ArticleMetaNumber ArticleMetaType = "number"
+	ArticleMetaBool   ArticleMetaType = "bool"
+	ArticleMetaJSON   ArticleMetaType = "json" // any JSON value but null, such as an object
+)
+
+// ArticleMeta is a custom field of an article, such as the episode number of a
+// podcast: a typed value under a key, kept as JSON so integrators can attach
+// structured data without schema changes
+type ArticleMeta struct {
+	ID        uint            `json:"-" gorm:"primaryKey"`
+	ArticleID uint            `json:"-" gorm:"not null;uniqueIndex:idx_article_meta_key"`
+	Key       string          `json:"key" gorm:"column:meta_key;size:64;not null;uniqueIndex:idx_article_meta_key" validate:"required,meta_key"`
+	Type      ArticleMetaType `json:"type" gorm:"size:10;not null" validate:"required,oneof=string number bool json"`
+	Value     json.RawMessage `json:"value" gorm:"type:text;not null"`
+	CreatedAt time.Time       `json:"-"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TableName specifies the table name for the ArticleMeta model
+func (ArticleMeta) TableName() string {
+	return "article_meta"
+}
+
+// Validate validates the ArticleMeta model and that its value is of its type.
+// The value is compacted.
+func (m *ArticleMeta) Validate() error {
+	if err := ValidateStruct(m); err != nil {
+		return err
+	}
+	if len(m.Value) > MaxArticleMetaValueSize {
+		return errors.New("value must be at most 16 KB")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(m.Value))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return errors.New("value must be valid JSON")
+	}
+
+	var ok bool
+	var expected string
+	switch m.Type {
+	case ArticleMetaString:
+		_, ok = value.(string)
+		expected = "a string"
+	case ArticleMetaNumber:
+		_, ok = value.(json.Number)
+		expected = "a number"
+	case ArticleMetaBool:
+		_, ok = value.(bool)
+		expected = "true or false"
+	case ArticleMetaJSON:
+		ok = value != nil
+		expected = "JSON other than null"
+	}
+	if !ok {
+		return errors.New("value of " + m.Key + " must be " + expected)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, m.Value); err != nil {
+		return errors.New("value must be valid JSON")
+	}
+	m.Value = compact.Bytes()
+	return nil
+}
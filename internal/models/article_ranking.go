@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ArticleRanking holds the precomputed scores of a published article, so the
+// popular and trending listings are indexed reads. Every row is recomputed when
+// the rankings are refreshed.
+type ArticleRanking struct {
+	ArticleID       uint      `json:"article_id" gorm:"primaryKey;autoIncrement:false"`
+	PopularityScore float64   `json:"popularity_score" gorm:"not null;index"` // views + 3 × likes + 5 × comments
+	TrendingScore   float64   `json:"trending_score" gorm:"not null;index"`   // popularity decayed by age
+	PublishedAt     time.Time `json:"published_at" gorm:"not null"`
+	RefreshedAt     time.Time `json:"refreshed_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for the ArticleRanking model
+func (ArticleRanking) TableName() string {
+	return "article_rankings"
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ArticleRevision is a snapshot of an article's title and content, recorded
+// each time either changes. Numbers count up from 1 per article.
+type ArticleRevision struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ArticleID uint      `json:"article_id" gorm:"not null;uniqueIndex:idx_article_revisions_number"`
+	Number    uint      `json:"number" gorm:"not null;uniqueIndex:idx_article_revisions_number"`
+	EditorID  uint      `json:"editor_id" gorm:"not null"`
+	Title     string    `json:"title" gorm:"size:255;not null"`
+	Content   string    `json:"content,omitempty" gorm:"type:longtext;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the ArticleRevision model
+func (ArticleRevision) TableName() string {
+	return "article_revisions"
+}
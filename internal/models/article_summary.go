@@ -0,0 +1,121 @@
+package models
+
+import "time"
+
+// ArticleSummary is the list representation of an article: everything except the
+// content, with the author reduced to their public profile
+type ArticleSummary struct {
+	ID            uint              `json:"id"`
+	Title         string            `json:"title"`
+	Slug          string            `json:"slug"`
+	Excerpt       string            `json:"excerpt"`
+	AuthorID      uint              `json:"author_id"`
+	Author        AuthorProfile     `json:"author"`
+	CategoryID    *uint             `json:"category_id"`
+	Category      *Category         `json:"category,omitempty"`
+	Tags          []Tag             `json:"tags,omitempty"`
+	Status        ArticleStatus     `json:"status"`
+	ViewCount     uint              `json:"view_count"`
+	LikeCount     uint              `json:"like_count"`
+	CommentCount  uint              `json:"comment_count"`
+	PublishedAt   *time.Time        `json:"published_at"`
+	ExpiresAt     *time.Time        `json:"expires_at"`
+	AllowComments bool              `json:"allow_comments"`
+	AllowLikes    bool              `json:"allow_likes"`
+	NoIndex       bool              `json:"noindex"`
+	Visibility    ArticleVisibility `json:"visibility"`
+	Locked        bool              `json:"locked"`             // the reader is not entitled to the content
+	Liked         *bool             `json:"is_liked,omitempty"` // whether the reader liked it, unset for anonymous readers
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Summary returns the list representation of the article
+func (a *Article) Summary() ArticleSummary {
+	return ArticleSummary{
+		ID:            a.ID,
+		Title:         a.Title,
+		Slug:          a.Slug,
+		Excerpt:       a.Excerpt,
+		AuthorID:      a.AuthorID,
+		Author:        a.Author.Profile(),
+		CategoryID:    a.CategoryID,
+		Category:      a.Category,
+		Tags:          a.Tags,
+		Status:        a.Status,
+		ViewCount:     a.ViewCount,
+		LikeCount:     a.LikeCount,
+		CommentCount:  a.CommentCount,
+		PublishedAt:   a.PublishedAt,
+		ExpiresAt:     a.ExpiresAt,
+		AllowComments: a.CommentsAllowed(),
+		AllowLikes:    a.LikesAllowed(),
+		NoIndex:       a.NoIndex,
+		Visibility:    a.Visibility,
+		Locked:        a.Locked,
+		Liked:         a.Liked,
+		CreatedAt:     a.CreatedAt,
+		UpdatedAt:     a.UpdatedAt,
+	}
+}
+
+// SummarizeArticles converts articles to their list representation
+func SummarizeArticles(articles []Article) []ArticleSummary {
+	summaries := make([]ArticleSummary, 0, len(articles))
+	for i := range articles {
+		summaries = append(summaries, articles[i].Summary())
+	}
+	return summaries
+}
+
+// ArticleCompact is the compact list representation of an article, returned by
+// the list endpoints for compact=true. Its fields are a stable schema for
+// mobile clients: fields may be added but are never renamed or removed.
+//
+//	id             article ID
+//	title          title
+//	slug           URL slug
+//	excerpt        plain text excerpt, possibly empty
+//	cover_url      share image URL, omitted until one is rendered
+//	view_count     views
+//	like_count     likes
+//	comment_count  approved comments
+//	published_at   publication time, null for unpublished articles
+//	updated_at     last change
+type ArticleCompact struct {
+	ID           uint       `json:"id"`
+	Title        string     `json:"title"`
+	Slug         string     `json:"slug"`
+	Excerpt      string     `json:"excerpt"`
+	CoverURL     string     `json:"cover_url,omitempty"`
+	ViewCount    uint       `json:"view_count"`
+	LikeCount    uint       `json:"like_count"`
+	CommentCount uint       `json:"comment_count"`
+	PublishedAt  *time.Time `json:"published_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Compact returns the compact list representation of the article
+func (a *Article) Compact() ArticleCompact {
+	return ArticleCompact{
+		ID:           a.ID,
+		Title:        a.Title,
+		Slug:         a.Slug,
+		Excerpt:      a.Excerpt,
+		CoverURL:     a.OGImageURL,
+		ViewCount:    a.ViewCount,
+		LikeCount:    a.LikeCount,
+		CommentCount: a.CommentCount,
+		PublishedAt:  a.PublishedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+// CompactArticles converts articles to their compact list representation
+func CompactArticles(articles []Article) []ArticleCompact {
+	compact := make([]ArticleCompact, 0, len(articles))
+	for i := range articles {
+		compact = append(compact, articles[i].Compact())
+	}
+	return compact
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchmarkArticles returns a page of articles as list and search endpoints load them
+func benchmarkArticles(n int) []Article {
+	now := time.Now()
+	categoryID := uint(1)
+	articles := make([]Article, n)
+	for i := range articles {
+		articles[i] = Article{
+			ID:          uint(i + 1),
+			Title:       fmt.Sprintf("Article number %d about Go performance", i),
+			Slug:        fmt.Sprintf("article-number-%d-about-go-performance", i),
+			Content:     strings.Repeat("Lorem ipsum dolor sit amet, consectetur adipiscing elit. ", 200),
+			Excerpt:     strings.Repeat("A short excerpt. ", 10),
+			AuthorID:    1,
+			Author:      User{ID: 1, Username: "author", Handle: "author", Email: "author@example.com"},
+			CategoryID:  &categoryID,
+			Category:    &Category{ID: categoryID, Name: "Go", Slug: "go"},
+			Tags:        []Tag{{ID: 1, Name: "go", Slug: "go"}, {ID: 2, Name: "performance", Slug: "performance"}},
+			Status:      StatusPublished,
+			ViewCount:   uint(i * 10),
+			PublishedAt: &now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	return articles
+}
+
+// BenchmarkArticleListSerialization measures turning a page of articles into
+// the JSON of list and search responses
+func BenchmarkArticleListSerialization(b *testing.B) {
+	articles := benchmarkArticles(20)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(SummarizeArticles(articles)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkArticleSerialization measures the JSON of a single article with its content
+func BenchmarkArticleSerialization(b *testing.B) {
+	article := benchmarkArticles(1)[0]
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := json.Marshal(&article); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ArticleView is one tracked read of an article with where the reader came from
+type ArticleView struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ArticleID   uint      `json:"article_id" gorm:"not null;index"`
+	Referrer    string    `json:"referrer" gorm:"size:255"` // host of the referring page
+	UTMSource   string    `json:"utm_source" gorm:"column:utm_source;size:100"`
+	UTMMedium   string    `json:"utm_medium" gorm:"column:utm_medium;size:100"`
+	UTMCampaign string    `json:"utm_campaign" gorm:"column:utm_campaign;size:100"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for the ArticleView model
+func (ArticleView) TableName() string {
+	return "article_views"
+}
+
+// TrafficCount is how many views came with one value of a traffic source, such
+// as one referrer or one utm_campaign
+type TrafficCount struct {
+	Value string `json:"value"`
+	Views int64  `json:"views"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditAction string
+
+const (
+	AuditUserDelete      AuditAction = "user.delete"
+	AuditArticleTransfer AuditAction = "article.transfer"
+)
+
+// AuditLog records a sensitive action and who performed it. Details holds a JSON
+// object describing the action; it must not contain secrets.
+type AuditLog struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	ActorID    uint        `json:"actor_id" gorm:"not null;index"`
+	Action     AuditAction `json:"action" gorm:"size:50;not null;index" validate:"required,max=50"`
+	TargetType string      `json:"target_type" gorm:"size:50;not null;index:idx_audit_logs_target" validate:"required,max=50"`
+	TargetID   uint        `json:"target_id" gorm:"not null;index:idx_audit_logs_target"`
+	Details    string      `json:"details" gorm:"type:text"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// TableName specifies the table name for the AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// Validate validates the AuditLog model
+func (a *AuditLog) Validate() error {
+	return ValidateStruct(a)
+}
+
+// BeforeCreate hook for GORM
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	return a.Validate()
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuthorProfile is the public representation of a user; it leaves out the email and role
+type AuthorProfile struct {
+	ID        uint        `json:"id"`
+	Username  string      `json:"username"`
+	Handle    string      `json:"handle"`
+	AvatarURL string      `json:"avatar_url"`
+	Bio       string      `json:"bio"`
+	Location  string      `json:"location,omitempty"`
+	Links     SocialLinks `json:"links"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// SocialLinks holds the absolute URLs of an author's external profiles
+type SocialLinks struct {
+	Website string `json:"website,omitempty"`
+	Twitter string `json:"twitter,omitempty"`
+	GitHub  string `json:"github,omitempty"`
+}
+
+// Profile returns the public representation of the user
+func (u *User) Profile() AuthorProfile {
+	return AuthorProfile{
+		ID:        u.ID,
+		Username:  u.Username,
+		Handle:    u.Handle,
+		AvatarURL: u.Avatar(),
+		Bio:       u.Bio,
+		Location:  u.Location,
+		Links:     u.SocialLinks(),
+		CreatedAt: u.CreatedAt,
+	}
+}
+
+// AvatarSize is the pixel size of the avatar returned in profiles
+const AvatarSize = 256
+
+// Avatar returns the user's avatar URL, falling back to Gravatar when none is set
+func (u *User) Avatar() string {
+	if u.AvatarURL != "" {
+		return u.AvatarURL
+	}
+	return GravatarURL(u.Email, AvatarSize)
+}
+
+// GravatarURL returns the Gravatar image for email at size pixels. Addresses
+// without a Gravatar get a generated identicon.
+func GravatarURL(email string, size int) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return "https://www.gravatar.com/avatar/" + hex.EncodeToString(sum[:]) +
+		"?d=identicon&s=" + strconv.Itoa(size)
+}
+
+// SocialLinks returns the user's external profile URLs
+func (u *User) SocialLinks() SocialLinks {
+	links := SocialLinks{Website: u.Website}
+	if u.Twitter != "" {
+		links.Twitter = "https://x.com/" + u.Twitter
+	}
+	if u.GitHub != "" {
+		links.GitHub = "https://github.com/" + u.GitHub
+	}
+	return links
+}
+
+// ProfileUsers converts users to their public representation
+func ProfileUsers(users []User) []AuthorProfile {
+	profiles := make([]AuthorProfile, 0, len(users))
+	for i := range users {
+		profiles = append(profiles, users[i].Profile())
+	}
+	return profiles
+}
+
+// NormalizeSocialHandle accepts a handle as "name", "@name" or a profile URL on one
+// of hosts and returns the bare handle. Values it cannot interpret are returned
+// trimmed so validation reports them.
+func NormalizeSocialHandle(value string, hosts ...string) string {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		raw := value
+		if !strings.Contains(raw, "://") {
+			raw = "https://" + raw
+		}
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			return value
+		}
+		host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+		for _, h := range hosts {
+			if host == h {
+				if segments := strings.Split(strings.Trim(parsed.Path, "/"), "/"); len(segments) == 1 {
+					return strings.TrimPrefix(segments[0], "@")
+				}
+			}
+		}
+		return value
+	}
+	return strings.TrimPrefix(value, "@")
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// AuthorReportDelivery records that an author was sent the report of a month,
+// so running the report job again does not send it twice
+type AuthorReportDelivery struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_author_report_deliveries_user_month"`
+	Month     string    `json:"month" gorm:"size:7;not null;uniqueIndex:idx_author_report_deliveries_user_month"` // YYYY-MM
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the AuthorReportDelivery model
+func (AuthorReportDelivery) TableName() string {
+	return "author_report_deliveries"
+}
+
+// AuthorActivity is what happened on an author's articles over a period.
+// Views are the tracked views of that period; followers are readers who
+// started following the discussion of one of the articles.
+type AuthorActivity struct {
+	Views        int64 `json:"views"`
+	Likes        int64 `json:"likes"`
+	Comments     int64 `json:"comments"`
+	NewFollowers int64 `json:"new_followers"`
+}
+
+// Empty reports whether nothing happened
+func (a *AuthorActivity) Empty() bool {
+	return a.Views == 0 && a.Likes == 0 && a.Comments == 0 && a.NewFollowers == 0
+}
+
+// ArticleActivity is how many views one article had over a period
+type ArticleActivity struct {
+	ArticleID uint   `json:"article_id"`
+	Title     string `json:"title"`
+	Slug      string `json:"slug"`
+	Views     int64  `json:"views"`
+}
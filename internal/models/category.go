@@ -9,14 +9,18 @@ import (
 )
 
 type Category struct {
-	ID          uint           `json:"id" gorm:"primaryKey"`
-	Name        string         `json:"name" gorm:"uniqueIndex;size:100;not null" validate:"required,min=1,max=100"`
-	Description string         `json:"description" gorm:"type:text" validate:"omitempty,max=1000"`
-	Slug        string         `json:"slug" gorm:"uniqueIndex;size:100;not null" validate:"required,slug,max=100"`
-	Articles    []Article      `json:"articles,omitempty" gorm:"foreignKey:CategoryID"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	Name          string         `json:"name" gorm:"uniqueIndex;size:100;not null" validate:"required,min=1,max=100,excludes=:"`
+	Description   string         `json:"description" gorm:"type:text" validate:"omitempty,max=1000"`
+	Slug          string         `json:"slug" gorm:"uniqueIndex;size:100;not null" validate:"required,slug,max=100"`
+	CoverURL      string         `json:"cover_url" gorm:"size:255;column:cover_url" validate:"omitempty,url,max=255"` // banner of the category page
+	IconURL       string         `json:"icon_url" gorm:"size:255;column:icon_url" validate:"omitempty,url,max=255"`
+	AccentColor   string         `json:"accent_color" gorm:"size:7" validate:"omitempty,hex_color"` // #rrggbb, lowercase
+	Articles      []Article      `json:"articles,omitempty" gorm:"foreignKey:CategoryID"`
+	FollowerCount uint           `json:"follower_count" gorm:"default:0"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName specifies the table name for the Category model
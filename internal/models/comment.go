@@ -9,18 +9,22 @@ import (
 )
 
 type Comment struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	ArticleID uint           `json:"article_id" gorm:"not null" validate:"required,min=1"`
-	Article   Article        `json:"article,omitempty" gorm:"foreignKey:ArticleID"`
-	UserID    uint           `json:"user_id" gorm:"not null" validate:"required,min=1"`
-	User      User           `json:"user" gorm:"foreignKey:UserID"`
-	Content   string         `json:"content" gorm:"type:text;not null" validate:"required,min=1,max=2000"`
-	ParentID  *uint          `json:"parent_id" validate:"omitempty,min=1"`
-	Parent    *Comment       `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
-	Replies   []Comment      `json:"replies,omitempty" gorm:"foreignKey:ParentID"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	ArticleID  uint           `json:"article_id" gorm:"not null;index:idx_comments_article_parent,priority:1" validate:"required,min=1"`
+	Article    Article        `json:"article,omitempty" gorm:"foreignKey:ArticleID" validate:"-"`
+	UserID     uint           `json:"user_id" gorm:"not null" validate:"required,min=1"`
+	User       User           `json:"user" gorm:"foreignKey:UserID" validate:"-"`
+	Content    string         `json:"content" gorm:"type:text;not null" validate:"required,min=1,max=2000"`
+	Hidden     bool           `json:"hidden" gorm:"not null;default:false"`    // hidden after too many reports
+	Tombstone  bool           `json:"tombstone" gorm:"not null;default:false"` // deleted but kept for its replies, without content
+	ParentID   *uint          `json:"parent_id" gorm:"index:idx_comments_article_parent,priority:2" validate:"omitempty,min=1"`
+	Parent     *Comment       `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
+	Replies    []Comment      `json:"replies,omitempty" gorm:"foreignKey:ParentID"`
+	ReplyCount int            `json:"reply_count" gorm:"-"` // replies nested below the comment
+	Mentions   []MentionRef   `json:"mentions,omitempty" gorm:"-"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName specifies the table name for the Comment model
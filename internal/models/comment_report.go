@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportReason string
+
+const (
+	ReportSpam       ReportReason = "spam"
+	ReportAbuse      ReportReason = "abuse"
+	ReportHarassment ReportReason = "harassment"
+	ReportOffTopic   ReportReason = "off_topic"
+	ReportOther      ReportReason = "other"
+	// ReportContentFilter is filed for comments a content filter rule held for review
+	ReportContentFilter ReportReason = "content_filter"
+)
+
+// CommentReport is a user's flag on a comment. Reports stay pending until an
+// administrator reviews the comment, which resolves all of its pending reports.
+type CommentReport struct {
+	ID         uint         `json:"id" gorm:"primaryKey"`
+	CommentID  uint         `json:"comment_id" gorm:"not null;uniqueIndex:idx_comment_reports_comment_reporter" validate:"required,min=1"`
+	ReporterID uint         `json:"reporter_id" gorm:"not null;uniqueIndex:idx_comment_reports_comment_reporter" validate:"required,min=1"`
+	Reason     ReportReason `json:"reason" gorm:"size:20;not null" validate:"required,oneof=spam abuse harassment off_topic other content_filter"`
+	Details    string       `json:"details,omitempty" gorm:"size:500" validate:"omitempty,max=500"`
+	ResolvedAt *time.Time   `json:"resolved_at" gorm:"index"`
+	CreatedAt  time.Time    `json:"created_at"`
+}
+
+// TableName specifies the table name for the CommentReport model
+func (CommentReport) TableName() string {
+	return "comment_reports"
+}
+
+// Validate validates the CommentReport model
+func (r *CommentReport) Validate() error {
+	return ValidateStruct(r)
+}
+
+// BeforeCreate hook for GORM
+func (r *CommentReport) BeforeCreate(tx *gorm.DB) error {
+	return r.Validate()
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CommentSubscription records a user watching an article's comment thread. The
+// token identifies the subscription in unsubscribe links; it only grants
+// unsubscribing, so it is stored as is and reused in every email.
+type CommentSubscription struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	UserID           uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_comment_subscriptions_user_article" validate:"required,min=1"`
+	ArticleID        uint      `json:"article_id" gorm:"not null;uniqueIndex:idx_comment_subscriptions_user_article;index" validate:"required,min=1"`
+	UnsubscribeToken string    `json:"-" gorm:"size:64;not null;uniqueIndex" validate:"required,len=64"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the CommentSubscription model
+func (CommentSubscription) TableName() string {
+	return "comment_subscriptions"
+}
+
+// Validate validates the CommentSubscription model
+func (s *CommentSubscription) Validate() error {
+	return ValidateStruct(s)
+}
+
+// BeforeCreate hook for GORM
+func (s *CommentSubscription) BeforeCreate(tx *gorm.DB) error {
+	return s.Validate()
+}
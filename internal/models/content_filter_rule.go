@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// FilterAction is what happens to text matching a content filter rule
+type FilterAction string
+
+const (
+	FilterBlock FilterAction = "block" // the comment or article is refused
+	FilterHold  FilterAction = "hold"  // comments are hidden and articles kept as drafts until reviewed
+	FilterMask  FilterAction = "mask"  // the matched words are replaced with asterisks
+)
+
+// FilterScope is the text a content filter rule applies to
+type FilterScope string
+
+const (
+	FilterScopeAll      FilterScope = "all"
+	FilterScopeComments FilterScope = "comments" // comment content
+	FilterScopeTitles   FilterScope = "titles"   // article titles
+)
+
+// ContentFilterRule is a word or regular expression that comments and article
+// titles are checked against
+type ContentFilterRule struct {
+	ID      uint         `json:"id" gorm:"primaryKey"`
+	Pattern string       `json:"pattern" gorm:"size:255;not null" validate:"required,min=1,max=255"`
+	Regex   bool         `json:"regex" gorm:"not null;default:false"` // a regular expression instead of a whole word or phrase
+	Action  FilterAction `json:"action" gorm:"size:10;not null" validate:"required,oneof=block hold mask"`
+	Scope   FilterScope  `json:"scope" gorm:"size:10;not null;default:'all'" validate:"required,oneof=all comments titles"`
+	// CreatedBy is the administrator who added the rule; comments the rule holds
+	// are reported in their name
+	CreatedBy uint      `json:"created_by" gorm:"not null" validate:"required,min=1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for the ContentFilterRule model
+func (ContentFilterRule) TableName() string {
+	return "content_filter_rules"
+}
+
+// Validate validates the ContentFilterRule model
+func (r *ContentFilterRule) Validate() error {
+	return ValidateStruct(r)
+}
+
+// Applies reports whether the rule checks text of scope
+func (r *ContentFilterRule) Applies(scope FilterScope) bool {
+	return r.Scope == FilterScopeAll || r.Scope == scope
+}
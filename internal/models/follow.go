@@ -0,0 +1,59 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type FollowTargetType string
+
+const (
+	FollowTargetCategory FollowTargetType = "category"
+	FollowTargetTag      FollowTargetType = "tag"
+)
+
+// Follow records a user following a taxonomy target (category or tag)
+type Follow struct {
+	ID         uint             `json:"id" gorm:"primaryKey"`
+	UserID     uint             `json:"user_id" gorm:"not null;uniqueIndex:idx_follows_user_target" validate:"required,min=1"`
+	TargetType FollowTargetType `json:"target_type" gorm:"size:20;not null;uniqueIndex:idx_follows_user_target;index:idx_follows_target" validate:"required,oneof=category tag"`
+	TargetID   uint             `json:"target_id" gorm:"not null;uniqueIndex:idx_follows_user_target;index:idx_follows_target" validate:"required,min=1"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+// TableName specifies the table name for the Follow model
+func (Follow) TableName() string {
+	return "follows"
+}
+
+// Validate validates the Follow model
+func (f *Follow) Validate() error {
+	if err := ValidateStruct(f); err != nil {
+		return err
+	}
+
+	if f.TargetType.TableName() == "" {
+		return errors.New("unsupported follow target type")
+	}
+
+	return nil
+}
+
+// TableName returns the table holding the follow target's follower_count column
+func (t FollowTargetType) TableName() string {
+	switch t {
+	case FollowTargetCategory:
+		return "categories"
+	case FollowTargetTag:
+		return "tags"
+	default:
+		return ""
+	}
+}
+
+// BeforeCreate hook for GORM
+func (f *Follow) BeforeCreate(tx *gorm.DB) error {
+	return f.Validate()
+}
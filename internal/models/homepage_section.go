@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HomepageSectionType decides which articles a homepage section shows
+type HomepageSectionType string
+
+const (
+	HomepageSectionLatest   HomepageSectionType = "latest"   // most recently published
+	HomepageSectionFeatured HomepageSectionType = "featured" // most liked
+	HomepageSectionCategory HomepageSectionType = "category" // latest of CategoryID
+	HomepageSectionTag      HomepageSectionType = "tag"      // latest tagged TagID
+	HomepageSectionManual   HomepageSectionType = "manual"   // ArticleIDs, in their order
+)
+
+// HomepageSection is a block of articles on the homepage, curated by admins
+type HomepageSection struct {
+	ID          uint                     `json:"id" gorm:"primaryKey"`
+	Title       string                   `json:"title" gorm:"size:100;not null" validate:"required,min=1,max=100"`
+	Type        HomepageSectionType      `json:"type" gorm:"size:20;not null" validate:"required,oneof=latest featured category tag manual"`
+	CategoryID  *uint                    `json:"category_id,omitempty"`
+	TagID       *uint                    `json:"tag_id,omitempty"`
+	MaxArticles int                      `json:"max_articles" gorm:"default:6" validate:"min=1,max=20"` // ignored by manual sections
+	Position    int                      `json:"position" gorm:"default:0;index"`                       // order on the homepage, lowest first
+	Items       []HomepageSectionArticle `json:"-" gorm:"foreignKey:SectionID"`
+	ArticleIDs  []uint                   `json:"article_ids,omitempty" gorm:"-"` // articles of a manual section, in order, filled from Items
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+}
+
+// HomepageSectionArticle places an article in a manual homepage section
+type HomepageSectionArticle struct {
+	ID        uint `json:"id" gorm:"primaryKey"`
+	SectionID uint `json:"section_id" gorm:"not null;uniqueIndex:idx_homepage_section_article"`
+	ArticleID uint `json:"article_id" gorm:"not null;uniqueIndex:idx_homepage_section_article"`
+	Position  int  `json:"position" gorm:"default:0"`
+}
+
+// TableName specifies the table name for the HomepageSection model
+func (HomepageSection) TableName() string {
+	return "homepage_sections"
+}
+
+// TableName specifies the table name for the HomepageSectionArticle model
+func (HomepageSectionArticle) TableName() string {
+	return "homepage_section_articles"
+}
+
+// Validate validates the HomepageSection model
+func (s *HomepageSection) Validate() error {
+	return ValidateStruct(s)
+}
+
+// BeforeSave hook for GORM
+func (s *HomepageSection) BeforeSave(tx *gorm.DB) error {
+	return s.Validate()
+}
+
+// FillArticleIDs sets ArticleIDs from the loaded items
+func (s *HomepageSection) FillArticleIDs() {
+	s.ArticleIDs = nil
+	for _, item := range s.Items {
+		s.ArticleIDs = append(s.ArticleIDs, item.ArticleID)
+	}
+}
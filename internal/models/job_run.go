@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JobRunStatus is the outcome of a scheduled job run
+type JobRunStatus string
+
+const (
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRunTrigger tells what started a job run
+type JobRunTrigger string
+
+const (
+	JobRunScheduled JobRunTrigger = "schedule"
+	JobRunManual    JobRunTrigger = "manual"
+)
+
+// JobRun records one run of a scheduled job
+type JobRun struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	Job        string        `json:"job" gorm:"size:100;not null;index:idx_job_runs_job_started" validate:"required,max=100"`
+	Trigger    JobRunTrigger `json:"trigger" gorm:"size:20;not null" validate:"required,oneof=schedule manual"`
+	Status     JobRunStatus  `json:"status" gorm:"size:20;not null" validate:"required,oneof=succeeded failed"`
+	Summary    string        `json:"summary,omitempty" gorm:"size:500" validate:"max=500"`
+	Error      string        `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  time.Time     `json:"started_at" gorm:"not null;index:idx_job_runs_job_started"`
+	FinishedAt time.Time     `json:"finished_at" gorm:"not null"`
+	DurationMS int64         `json:"duration_ms"`
+}
+
+// TableName specifies the table name for the JobRun model
+func (JobRun) TableName() string {
+	return "job_runs"
+}
+
+// Validate validates the JobRun model
+func (r *JobRun) Validate() error {
+	return ValidateStruct(r)
+}
+
+// BeforeCreate hook for GORM
+func (r *JobRun) BeforeCreate(tx *gorm.DB) error {
+	return r.Validate()
+}
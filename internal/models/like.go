@@ -9,10 +9,10 @@ import (
 
 type Like struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
-	UserID    uint           `json:"user_id" gorm:"not null" validate:"required,min=1"`
-	User      User           `json:"user" gorm:"foreignKey:UserID"`
-	ArticleID uint           `json:"article_id" gorm:"not null" validate:"required,min=1"`
-	Article   Article        `json:"article" gorm:"foreignKey:ArticleID"`
+	UserID    uint           `json:"user_id" gorm:"not null;uniqueIndex:idx_likes_user_article" validate:"required,min=1"`
+	User      User           `json:"user" gorm:"foreignKey:UserID" validate:"-"`
+	ArticleID uint           `json:"article_id" gorm:"not null;uniqueIndex:idx_likes_user_article" validate:"required,min=1"`
+	Article   Article        `json:"article" gorm:"foreignKey:ArticleID" validate:"-"`
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
@@ -49,20 +49,10 @@ func (l *Like) validateUserArticleCombination() error {
 	return nil
 }
 
-// BeforeCreate hook for GORM
+// BeforeCreate hook for GORM. Duplicate likes are rejected by the unique
+// index on (user_id, article_id).
 func (l *Like) BeforeCreate(tx *gorm.DB) error {
-	if err := l.Validate(); err != nil {
-		return err
-	}
-	
-	// Check for existing like to prevent duplicates
-	var existingLike Like
-	result := tx.Where("user_id = ? AND article_id = ?", l.UserID, l.ArticleID).First(&existingLike)
-	if result.Error == nil {
-		return errors.New("user has already liked this article")
-	}
-	
-	return nil
+	return l.Validate()
 }
 
 // BeforeUpdate hook for GORM
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MagicLink is a single-use sign-in link emailed to a user. Only the hash of
+// its token is stored; the device and address it was requested from are kept
+// to confirm sign-ins on another device and to limit requests.
+type MagicLink struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index" validate:"required,min=1"`
+	TokenHash string     `json:"-" gorm:"size:64;not null;uniqueIndex" validate:"required,max=64"`
+	IP        string     `json:"ip" gorm:"size:45;index" validate:"max=45"`
+	Device    string     `json:"device" gorm:"size:100" validate:"max=100"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+}
+
+// TableName specifies the table name for the MagicLink model
+func (MagicLink) TableName() string {
+	return "magic_links"
+}
+
+// Validate validates the MagicLink model
+func (l *MagicLink) Validate() error {
+	return ValidateStruct(l)
+}
+
+// BeforeCreate hook for GORM
+func (l *MagicLink) BeforeCreate(tx *gorm.DB) error {
+	return l.Validate()
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxMentions caps how many users one comment can mention
+const MaxMentions = 10
+
+// mentionPattern matches @handle where the @ does not follow a word character,
+// so email addresses are not taken for mentions
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z0-9_-]{3,50})`)
+
+// Mention records that a comment mentions a user
+type Mention struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CommentID uint      `json:"comment_id" gorm:"not null;uniqueIndex:idx_mentions_comment_user"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_mentions_comment_user;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the Mention model
+func (Mention) TableName() string {
+	return "mentions"
+}
+
+// MentionRef is the mention metadata returned with comments so frontends can link
+// each @handle in the content to the user's profile
+type MentionRef struct {
+	CommentID uint   `json:"-"`
+	UserID    uint   `json:"user_id"`
+	Username  string `json:"username"`
+	Handle    string `json:"handle"`
+}
+
+// ParseMentions returns the distinct handles mentioned in content, lowercased and in
+// order of appearance, up to MaxMentions
+func ParseMentions(content string) []string {
+	var handles []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		handle := strings.ToLower(match[1])
+		if seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+		if len(handles) == MaxMentions {
+			break
+		}
+	}
+	return handles
+}
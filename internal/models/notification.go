@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationType string
+
+const (
+	NotificationSavedSearch NotificationType = "saved_search"
+	NotificationMention     NotificationType = "mention"
+	NotificationComment     NotificationType = "comment"      // new comment on a watched thread
+	NotificationBrokenLinks NotificationType = "broken_links" // links of an author's article stopped working
+)
+
+// Notification is an in-app message for a user
+type Notification struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"user_id" gorm:"not null;index:idx_notifications_user_read" validate:"required,min=1"`
+	Type      NotificationType `json:"type" gorm:"size:50;not null" validate:"required,max=50"`
+	Title     string           `json:"title" gorm:"size:255;not null" validate:"required,max=255"`
+	Body      string           `json:"body" gorm:"type:text"`
+	Link      string           `json:"link,omitempty" gorm:"size:255" validate:"omitempty,max=255"`
+	ReadAt    *time.Time       `json:"read_at" gorm:"index:idx_notifications_user_read"`
+	CreatedAt time.Time        `json:"created_at"`
+
+	// UnsubscribeURL is appended to the email copy only; it is not stored
+	UnsubscribeURL string `json:"-" gorm:"-"`
+}
+
+// TableName specifies the table name for the Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// Validate validates the Notification model
+func (n *Notification) Validate() error {
+	return ValidateStruct(n)
+}
+
+// BeforeCreate hook for GORM
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	return n.Validate()
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PageStatus is the publication status of a page
+type PageStatus string
+
+const (
+	PageStatusDraft     PageStatus = "draft"
+	PageStatusPublished PageStatus = "published"
+)
+
+// Page is standalone content such as an about or contact page. Pages have their
+// own slug namespace and stay out of the article timeline, feeds and archives.
+type Page struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	Title       string     `json:"title" gorm:"size:255;not null" validate:"required,min=1,max=255"`
+	Slug        string     `json:"slug" gorm:"uniqueIndex;size:100;not null" validate:"required,slug,max=100"`
+	Content     string     `json:"content" gorm:"type:longtext;not null" validate:"required,min=1"`
+	Status      PageStatus `json:"status" gorm:"size:20;default:'draft'" validate:"required,oneof=draft published"`
+	ShowInNav   bool       `json:"show_in_nav" gorm:"default:false"`
+	NavOrder    int        `json:"nav_order" gorm:"default:0;index"` // position in navigation, lowest first
+	AuthorID    uint       `json:"author_id" gorm:"not null" validate:"required,min=1"`
+	PublishedAt *time.Time `json:"published_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// PageLink is the navigation representation of a page
+type PageLink struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	NavOrder int    `json:"nav_order"`
+}
+
+// TableName specifies the table name for the Page model
+func (Page) TableName() string {
+	return "pages"
+}
+
+// Link returns the navigation representation of the page
+func (p *Page) Link() PageLink {
+	return PageLink{ID: p.ID, Title: p.Title, Slug: p.Slug, NavOrder: p.NavOrder}
+}
+
+// IsPublished reports whether the page is visible to readers
+func (p *Page) IsPublished() bool {
+	return p.Status == PageStatusPublished
+}
+
+// Validate validates the Page model
+func (p *Page) Validate() error {
+	return ValidateStruct(p)
+}
+
+// BeforeSave hook for GORM; it stamps the first publication
+func (p *Page) BeforeSave(tx *gorm.DB) error {
+	if p.IsPublished() && p.PublishedAt == nil {
+		now := time.Now()
+		p.PublishedAt = &now
+	}
+	return p.Validate()
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PolicyKind is the kind of policy document users accept
+type PolicyKind string
+
+const (
+	PolicyTerms   PolicyKind = "terms"   // terms of service
+	PolicyPrivacy PolicyKind = "privacy" // privacy policy
+)
+
+// Policy is one version of a policy document. Versions are never edited: an
+// update publishes a new version, and the latest version of each kind is the
+// current one every user must have accepted.
+type Policy struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Kind      PolicyKind `json:"kind" gorm:"size:20;not null;uniqueIndex:idx_policies_kind_version" validate:"required,oneof=terms privacy"`
+	Version   string     `json:"version" gorm:"size:50;not null;uniqueIndex:idx_policies_kind_version" validate:"required,min=1,max=50"`
+	Title     string     `json:"title" gorm:"size:255;not null" validate:"required,min=1,max=255"`
+	Content   string     `json:"content" gorm:"type:longtext;not null" validate:"required,min=1"`
+	CreatedBy uint       `json:"created_by" gorm:"not null" validate:"required,min=1"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for the Policy model
+func (Policy) TableName() string {
+	return "policies"
+}
+
+// Validate validates the Policy model
+func (p *Policy) Validate() error {
+	return ValidateStruct(p)
+}
+
+// BeforeCreate hook for GORM
+func (p *Policy) BeforeCreate(tx *gorm.DB) error {
+	return p.Validate()
+}
+
+// PolicyAcceptance records a user accepting a version of a policy, with the
+// address they accepted it from
+type PolicyAcceptance struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	UserID     uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_policy_acceptances_user_policy"`
+	PolicyID   uint      `json:"policy_id" gorm:"not null;uniqueIndex:idx_policy_acceptances_user_policy;index"`
+	IP         string    `json:"ip" gorm:"size:45"`
+	AcceptedAt time.Time `json:"accepted_at" gorm:"not null"`
+}
+
+// TableName specifies the table name for the PolicyAcceptance model
+func (PolicyAcceptance) TableName() string {
+	return "policy_acceptances"
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// QueuedJobStatus is the state of a background job
+type QueuedJobStatus string
+
+const (
+	QueuedJobPending   QueuedJobStatus = "pending"   // waiting for its run time or a retry
+	QueuedJobRunning   QueuedJobStatus = "running"   // claimed by a worker
+	QueuedJobSucceeded QueuedJobStatus = "succeeded" // done, kept until housekeeping removes it
+	QueuedJobDead      QueuedJobStatus = "dead"      // out of attempts, waiting for an admin
+)
+
+// QueuedJob is a unit of background work in the durable job queue. The payload
+// may hold email bodies with confirmation links, so it is never serialized.
+type QueuedJob struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Type        string          `json:"type" gorm:"size:100;not null;index" validate:"required,max=100"`
+	Payload     string          `json:"-" gorm:"type:text;not null"`
+	Status      QueuedJobStatus `json:"status" gorm:"size:20;not null;index:idx_jobs_status_run_at,priority:1" validate:"required,oneof=pending running succeeded dead"`
+	Attempts    int             `json:"attempts" gorm:"not null;default:0"`
+	MaxAttempts int             `json:"max_attempts" gorm:"not null" validate:"min=1"`
+	RunAt       time.Time       `json:"run_at" gorm:"not null;index:idx_jobs_status_run_at,priority:2"`
+	LockedAt    *time.Time      `json:"locked_at,omitempty"`
+	LastError   string          `json:"last_error,omitempty" gorm:"type:text"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// TableName specifies the table name for the QueuedJob model
+func (QueuedJob) TableName() string {
+	return "jobs"
+}
+
+// Validate validates the QueuedJob model
+func (j *QueuedJob) Validate() error {
+	return ValidateStruct(j)
+}
+
+// BeforeCreate hook for GORM
+func (j *QueuedJob) BeforeCreate(tx *gorm.DB) error {
+	return j.Validate()
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RefreshToken tracks an issued refresh token by its jti. Refreshing uses up the
+// presented token and issues the next one in the same family; presenting a used
+// token again means it was copied, so the whole family is revoked.
+type RefreshToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	TokenID   string     `json:"-" gorm:"size:64;not null;uniqueIndex" validate:"required,max=64"`
+	FamilyID  string     `json:"-" gorm:"size:64;not null;index" validate:"required,max=64"`
+	UserID    uint       `json:"user_id" gorm:"not null;index" validate:"required,min=1"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for the RefreshToken model
+func (RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
+
+// Validate validates the RefreshToken model
+func (t *RefreshToken) Validate() error {
+	return ValidateStruct(t)
+}
+
+// BeforeCreate hook for GORM
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	return t.Validate()
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SavedSearch is a named search query a user can re-run and subscribe to.
+// When Alert is set, newly published articles matching the query produce a
+// notification, and an email as well when EmailAlert is set.
+type SavedSearch struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	UserID        uint       `json:"user_id" gorm:"not null;index" validate:"required,min=1"`
+	Name          string     `json:"name" gorm:"size:100;not null" validate:"required,min=1,max=100"`
+	Query         string     `json:"query" gorm:"size:255;not null" validate:"required,min=1,max=255"`
+	CategoryID    uint       `json:"category_id,omitempty"`
+	AuthorID      uint       `json:"author_id,omitempty"`
+	TagID         uint       `json:"tag_id,omitempty"`
+	SearchMode    string     `json:"search_mode" gorm:"size:20;default:'natural'" validate:"omitempty,oneof=natural boolean"`
+	Alert         bool       `json:"alert" gorm:"index"`
+	EmailAlert    bool       `json:"email_alert"`
+	LastCheckedAt *time.Time `json:"last_checked_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for the SavedSearch model
+func (SavedSearch) TableName() string {
+	return "saved_searches"
+}
+
+// Validate validates the SavedSearch model
+func (s *SavedSearch) Validate() error {
+	return ValidateStruct(s)
+}
+
+// BeforeCreate hook for GORM
+func (s *SavedSearch) BeforeCreate(tx *gorm.DB) error {
+	return s.Validate()
+}
+
+// BeforeUpdate hook for GORM
+func (s *SavedSearch) BeforeUpdate(tx *gorm.DB) error {
+	return s.Validate()
+}
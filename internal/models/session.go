@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session is one sign-in of a user on a device. Its refresh tokens form one
+// rotation family, and the tokens carry FamilyID so signing the session out
+// rejects them straight away.
+type Session struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"-" gorm:"not null;index" validate:"required,min=1"`
+	FamilyID   string     `json:"-" gorm:"size:64;not null;uniqueIndex" validate:"required,max=64"`
+	IP         string     `json:"ip" gorm:"size:45" validate:"max=45"`
+	UserAgent  string     `json:"user_agent" gorm:"size:255" validate:"max=255"`
+	Device     string     `json:"device" gorm:"size:100" validate:"max=100"`
+	Location   string     `json:"location,omitempty" gorm:"size:100" validate:"max=100"` // coarse, e.g. a country code
+	Current    bool       `json:"current" gorm:"-"`                                      // whether the request was made with this session
+	LastUsedAt time.Time  `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"-"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for the Session model
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// Validate validates the Session model
+func (s *Session) Validate() error {
+	return ValidateStruct(s)
+}
+
+// BeforeCreate hook for GORM
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	return s.Validate()
+}
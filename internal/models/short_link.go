@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// ShortLink is the short code an article is shared under; /s/<code>
+// redirects to the article and counts the click
+type ShortLink struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ArticleID  uint      `json:"article_id" gorm:"uniqueIndex;not null"`
+	Code       string    `json:"code" gorm:"uniqueIndex;size:16;not null"`
+	ClickCount uint      `json:"click_count" gorm:"not null;default:0"`
+	URL        string    `json:"url" gorm:"-"` // the short URL, set by the service
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the ShortLink model
+func (ShortLink) TableName() string {
+	return "short_links"
+}
+
+// ShortLinkClick is one follow of a short link
+type ShortLinkClick struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	ShortLinkID uint      `json:"short_link_id" gorm:"not null;index"`
+	Referrer    string    `json:"referrer" gorm:"size:255"` // host of the referring page, empty when none was sent
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for the ShortLinkClick model
+func (ShortLinkClick) TableName() string {
+	return "short_link_clicks"
+}
+
+// ReferrerCount is how many clicks on a short link came from one referrer
+type ReferrerCount struct {
+	Referrer string `json:"referrer"`
+	Clicks   int64  `json:"clicks"`
+}
@@ -9,13 +9,17 @@ import (
 )
 
 type Tag struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Name      string         `json:"name" gorm:"uniqueIndex;size:50;not null" validate:"required,min=1,max=50"`
-	Slug      string         `json:"slug" gorm:"uniqueIndex;size:50;not null" validate:"required,slug,max=50"`
-	Articles  []Article      `json:"articles,omitempty" gorm:"many2many:article_tags"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	Name            string         `json:"name" gorm:"uniqueIndex;size:50;not null" validate:"required,min=1,max=50,excludes=:"`
+	Slug            string         `json:"slug" gorm:"uniqueIndex;size:50;not null" validate:"required,slug,max=50"`
+	Description     string         `json:"description" gorm:"type:text" validate:"omitempty,max=2000"` // intro text of the tag page
+	MetaTitle       string         `json:"meta_title" gorm:"size:100" validate:"omitempty,max=100"`    // page title for search engines, empty uses the name
+	MetaDescription string         `json:"meta_description" gorm:"size:300" validate:"omitempty,max=300"`
+	Articles        []Article      `json:"articles,omitempty" gorm:"many2many:article_tags"`
+	FollowerCount   uint           `json:"follower_count" gorm:"default:0"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName specifies the table name for the Tag model
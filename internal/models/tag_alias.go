@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TagAlias maps an alternative spelling (e.g. "golang", "go-lang") onto a canonical tag
+type TagAlias struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Alias     string    `json:"alias" gorm:"uniqueIndex;size:50;not null" validate:"required,min=1,max=50"`
+	TagID     uint      `json:"tag_id" gorm:"not null;index" validate:"required,min=1"`
+	Tag       *Tag      `json:"tag,omitempty" gorm:"foreignKey:TagID"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for the TagAlias model
+func (TagAlias) TableName() string {
+	return "tag_aliases"
+}
+
+// Validate validates the TagAlias model
+func (a *TagAlias) Validate() error {
+	if err := ValidateStruct(a); err != nil {
+		return err
+	}
+
+	// Aliases are stored in normalized (lowercase) form for case-insensitive lookups
+	if a.Alias != strings.ToLower(strings.TrimSpace(a.Alias)) {
+		return errors.New("alias must be stored in normalized lowercase form")
+	}
+
+	return nil
+}
+
+// BeforeCreate hook for GORM
+func (a *TagAlias) BeforeCreate(tx *gorm.DB) error {
+	return a.Validate()
+}
+
+// BeforeUpdate hook for GORM
+func (a *TagAlias) BeforeUpdate(tx *gorm.DB) error {
+	return a.Validate()
+}
@@ -8,19 +8,65 @@ import (
 	"gorm.io/gorm"
 )
 
+type UserRole string
+
+const (
+	RoleUser  UserRole = "user"
+	RoleAdmin UserRole = "admin"
+	// RoleSystem marks accounts owned by the application, such as the anonymous
+	// author of content left behind by deleted accounts. They cannot sign in.
+	RoleSystem UserRole = "system"
+)
+
+// MembershipTier is the paid membership level of a user
+type MembershipTier string
+
+const (
+	TierFree    MembershipTier = "free"
+	TierMember  MembershipTier = "member"
+	TierPremium MembershipTier = "premium"
+)
+
+// AnonymousUsername is the system account that receives anonymized content
+const AnonymousUsername = "anonymous"
+
+// ContentPolicy decides what happens to a deleted account's articles and comments
+type ContentPolicy string
+
+const (
+	// ContentPolicyDelete deletes the articles and comments
+	ContentPolicyDelete ContentPolicy = "delete"
+	// ContentPolicyTransfer moves the articles to another author and anonymizes the comments
+	ContentPolicyTransfer ContentPolicy = "transfer"
+	// ContentPolicyAnonymize moves the articles and comments to the anonymous system user
+	ContentPolicyAnonymize ContentPolicy = "anonymize"
+)
+
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Username  string         `json:"username" gorm:"uniqueIndex;size:50;not null" validate:"required,username"`
-	Email     string         `json:"email" gorm:"uniqueIndex;size:100;not null" validate:"required,email,max=100"`
-	Password  string         `json:"-" gorm:"size:255;not null;column:password_hash" validate:"required,min=8,max=255"`
-	AvatarURL string         `json:"avatar_url" gorm:"size:255;column:avatar_url" validate:"omitempty,url,max=255"`
-	Bio       string         `json:"bio" gorm:"type:text" validate:"omitempty,max=1000"`
-	Articles  []Article      `json:"articles,omitempty" gorm:"foreignKey:AuthorID"`
-	Comments  []Comment      `json:"comments,omitempty"`
-	Likes     []Like         `json:"likes,omitempty"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                   uint           `json:"id" gorm:"primaryKey"`
+	Username             string         `json:"username" gorm:"uniqueIndex;size:50;not null" validate:"required,username"`
+	Handle               string         `json:"handle" gorm:"uniqueIndex;size:50" validate:"omitempty,handle"`
+	Email                string         `json:"email" gorm:"uniqueIndex;size:100;not null" validate:"required,email,max=100"`
+	PendingEmail         string         `json:"pending_email,omitempty" gorm:"size:100" validate:"omitempty,email,max=100"` // awaiting confirmation
+	EmailChangeToken     string         `json:"-" gorm:"size:64;index"`                                                     // SHA-256 of the confirmation token
+	EmailChangeExpiresAt *time.Time     `json:"-"`
+	Password             string         `json:"-" gorm:"size:255;not null;column:password_hash" validate:"required,min=8,max=255"`
+	AvatarURL            string         `json:"avatar_url" gorm:"size:255;column:avatar_url" validate:"omitempty,url,max=255"`
+	AvatarKey            string         `json:"-" gorm:"size:255"` // storage key prefix of an uploaded avatar
+	Bio                  string         `json:"bio" gorm:"type:text" validate:"omitempty,max=1000"`
+	Website              string         `json:"website" gorm:"size:255" validate:"omitempty,url,max=255"`
+	Twitter              string         `json:"twitter" gorm:"size:15" validate:"omitempty,twitter_handle"`
+	GitHub               string         `json:"github" gorm:"column:github;size:39" validate:"omitempty,github_handle"`
+	Location             string         `json:"location" gorm:"size:100" validate:"omitempty,max=100"`
+	Role                 UserRole       `json:"role" gorm:"size:20;default:'user'" validate:"omitempty,oneof=user admin system"`
+	MembershipTier       MembershipTier `json:"membership_tier" gorm:"size:20;not null;default:'free'" validate:"omitempty,oneof=free member premium"`
+	TokenVersion         uint           `json:"-" gorm:"not null;default:0"` // bumped to invalidate every issued token
+	Articles             []Article      `json:"articles,omitempty" gorm:"foreignKey:AuthorID"`
+	Comments             []Comment      `json:"comments,omitempty"`
+	Likes                []Like         `json:"likes,omitempty"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 // TableName specifies the table name for the User model
@@ -28,6 +74,34 @@ func (User) TableName() string {
 	return "users"
 }
 
+// FilterableColumns lists the columns list queries may filter and search on;
+// password hashes and email change tokens are left out
+func (User) FilterableColumns() []string {
+	return []string{
+		"id", "username", "handle", "email", "avatar_url", "bio", "website", "twitter",
+		"github", "location", "role", "membership_tier", "created_at", "updated_at", "deleted_at",
+	}
+}
+
+// IsAdmin reports whether the user has administrative privileges
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// Entitled reports whether the user's membership tier covers articles of the
+// given visibility
+func (u *User) Entitled(visibility ArticleVisibility) bool {
+	switch visibility {
+	case "", ArticleVisibilityPublic:
+		return true
+	case ArticleVisibilityMembers:
+		return u.MembershipTier == TierMember || u.MembershipTier == TierPremium
+	case ArticleVisibilityPremium:
+		return u.MembershipTier == TierPremium
+	}
+	return false
+}
+
 // Validate validates the User model
 func (u *User) Validate() error {
 	if err := ValidateStruct(u); err != nil {
@@ -49,19 +123,41 @@ func (u *User) validateUsername() error {
 		return errors.New("username cannot have leading or trailing spaces")
 	}
 	
-	// Check for reserved usernames
-	reservedUsernames := []string{"admin", "root", "api", "www", "mail", "ftp", "blog", "user", "test"}
-	for _, reserved := range reservedUsernames {
-		if strings.ToLower(username) == reserved {
-			return errors.New("username is reserved and cannot be used")
-		}
+	// Check for reserved usernames; system accounts are the reason names are reserved
+	if u.Role != RoleSystem && IsReservedName(username) {
+		return errors.New("username is reserved and cannot be used")
+	}
+	if u.Role != RoleSystem && u.Handle != "" && IsReservedName(u.Handle) {
+		return errors.New("handle is reserved and cannot be used")
 	}
 	
 	return nil
 }
 
+// reservedNames cannot be used as usernames or profile handles
+var reservedNames = []string{"admin", "root", "api", "www", "mail", "ftp", "blog", "user", "test", "me", AnonymousUsername}
+
+// IsReservedName reports whether name is reserved for usernames and handles
+func IsReservedName(name string) bool {
+	name = strings.ToLower(name)
+	for _, reserved := range reservedNames {
+		if name == reserved {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultHandle returns the profile handle derived from a username
+func DefaultHandle(username string) string {
+	return strings.ToLower(username)
+}
+
 // BeforeCreate hook for GORM
 func (u *User) BeforeCreate(tx *gorm.DB) error {
+	if u.Handle == "" {
+		u.Handle = DefaultHandle(u.Username)
+	}
 	return u.Validate()
 }
 
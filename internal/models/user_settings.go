@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ProfileVisibility string
+
+const (
+	ProfileVisibilityPublic  ProfileVisibility = "public"  // anyone can view the profile
+	ProfileVisibilityMembers ProfileVisibility = "members" // only signed-in users can view it
+	ProfileVisibilityPrivate ProfileVisibility = "private" // only the owner can view it
+)
+
+type EditorMode string
+
+const (
+	EditorMarkdown EditorMode = "markdown"
+	EditorRichText EditorMode = "rich_text"
+)
+
+// UserSettings holds a user's notification and privacy preferences. Users without a
+// row get DefaultUserSettings; the bool columns deliberately have no database default
+// so that false survives an insert.
+type UserSettings struct {
+	UserID             uint              `json:"user_id" gorm:"primaryKey;autoIncrement:false" validate:"required,min=1"`
+	EmailNotifications bool              `json:"email_notifications"`
+	EmailSearchAlerts  bool              `json:"email_search_alerts"`
+	EmailMonthlyReport bool              `json:"email_monthly_report"` // monthly statistics of the user's articles
+	ProfileVisibility  ProfileVisibility `json:"profile_visibility" gorm:"size:20;not null" validate:"required,oneof=public members private"`
+	EditorMode         EditorMode        `json:"editor_mode" gorm:"size:20;not null" validate:"required,oneof=markdown rich_text"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// TableName specifies the table name for the UserSettings model
+func (UserSettings) TableName() string {
+	return "user_settings"
+}
+
+// DefaultUserSettings returns the settings of a user who has not changed any
+func DefaultUserSettings(userID uint) *UserSettings {
+	return &UserSettings{
+		UserID:             userID,
+		EmailNotifications: true,
+		EmailSearchAlerts:  true,
+		EmailMonthlyReport: true,
+		ProfileVisibility:  ProfileVisibilityPublic,
+		EditorMode:         EditorMarkdown,
+	}
+}
+
+// WantsEmail reports whether the user accepts email for notifications of type t.
+// EmailNotifications is the master switch; each type may add its own toggle.
+func (s *UserSettings) WantsEmail(t NotificationType) bool {
+	if !s.EmailNotifications {
+		return false
+	}
+	switch t {
+	case NotificationSavedSearch:
+		return s.EmailSearchAlerts
+	default:
+		return true
+	}
+}
+
+// WantsMonthlyReport reports whether the user accepts the monthly email about
+// their articles; EmailNotifications turns it off too
+func (s *UserSettings) WantsMonthlyReport() bool {
+	return s.EmailNotifications && s.EmailMonthlyReport
+}
+
+// CanView reports whether a viewer may see the profile; viewerID is 0 for anonymous viewers
+func (s *UserSettings) CanView(viewerID uint) bool {
+	switch s.ProfileVisibility {
+	case ProfileVisibilityPrivate:
+		return viewerID == s.UserID
+	case ProfileVisibilityMembers:
+		return viewerID != 0
+	default:
+		return true
+	}
+}
+
+// Validate validates the UserSettings model
+func (s *UserSettings) Validate() error {
+	return ValidateStruct(s)
+}
+
+// BeforeSave hook for GORM
+func (s *UserSettings) BeforeSave(tx *gorm.DB) error {
+	return s.Validate()
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"errors"
+	"reflect"
 	"regexp"
 	"strings"
 
@@ -12,11 +13,25 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// Report fields by their JSON name so errors match the request payload
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
 	
 	// Register custom validation functions
 	validate.RegisterValidation("username", validateUsername)
+	validate.RegisterValidation("handle", validateHandle)
+	validate.RegisterValidation("twitter_handle", validateTwitterHandle)
+	validate.RegisterValidation("github_handle", validateGitHubHandle)
 	validate.RegisterValidation("slug", validateSlug)
 	validate.RegisterValidation("article_status", validateArticleStatus)
+	validate.RegisterValidation("hex_color", validateHexColor)
+	validate.RegisterValidation("meta_key", validateMetaKey)
 }
 
 // GetValidator returns the global validator instance
@@ -35,6 +50,25 @@ func validateUsername(fl validator.FieldLevel) bool {
 	return matched
 }
 
+// validateHandle validates a profile handle: a lowercase username
+func validateHandle(fl validator.FieldLevel) bool {
+	matched, _ := regexp.MatchString(`^[a-z0-9_-]{3,50}$`, fl.Field().String())
+	return matched
+}
+
+// validateTwitterHandle validates a Twitter/X handle without the leading @
+func validateTwitterHandle(fl validator.FieldLevel) bool {
+	matched, _ := regexp.MatchString(`^[A-Za-z0-9_]{1,15}$`, fl.Field().String())
+	return matched
+}
+
+// validateGitHubHandle validates a GitHub username: alphanumerics and single inner hyphens
+func validateGitHubHandle(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	matched, _ := regexp.MatchString(`^[A-Za-z0-9](-?[A-Za-z0-9])*$`, value)
+	return matched && len(value) <= 39
+}
+
 // validateSlug validates slug format
 func validateSlug(fl validator.FieldLevel) bool {
 	slug := fl.Field().String()
@@ -52,6 +86,18 @@ func validateArticleStatus(fl validator.FieldLevel) bool {
 	return status == string(StatusDraft) || status == string(StatusPublished) || status == string(StatusArchived)
 }
 
+// validateHexColor validates a #rrggbb color
+func validateHexColor(fl validator.FieldLevel) bool {
+	matched, _ := regexp.MatchString(`^#[0-9A-Fa-f]{6}$`, fl.Field().String())
+	return matched
+}
+
+// validateMetaKey validates the key of an article custom field
+func validateMetaKey(fl validator.FieldLevel) bool {
+	matched, _ := regexp.MatchString(`^[a-z][a-z0-9_]{0,63}$`, fl.Field().String())
+	return matched
+}
+
 // ValidationError represents a validation error with field details
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -92,10 +138,26 @@ func FormatValidationErrors(err error) ValidationErrors {
 				validationError.Message = fieldError.Field() + " must be at most " + fieldError.Param() + " characters long"
 			case "username":
 				validationError.Message = fieldError.Field() + " must be 3-50 characters long and contain only letters, numbers, underscores, and hyphens"
+			case "handle":
+				validationError.Message = fieldError.Field() + " must be 3-50 characters long and contain only lowercase letters, numbers, underscores, and hyphens"
+			case "twitter_handle":
+				validationError.Message = fieldError.Field() + " must be a Twitter/X handle of up to 15 letters, numbers, or underscores"
+			case "github_handle":
+				validationError.Message = fieldError.Field() + " must be a GitHub username of up to 39 letters, numbers, or single hyphens"
 			case "slug":
 				validationError.Message = fieldError.Field() + " must contain only lowercase letters, numbers, and hyphens"
 			case "article_status":
 				validationError.Message = fieldError.Field() + " must be one of: draft, published, archived"
+			case "hex_color":
+				validationError.Message = fieldError.Field() + " must be a color such as #1a73e8"
+			case "meta_key":
+				validationError.Message = fieldError.Field() + " must be up to 64 lowercase letters, numbers, and underscores, starting with a letter"
+			case "oneof":
+				validationError.Message = fieldError.Field() + " must be one of: " + strings.ReplaceAll(fieldError.Param(), " ", ", ")
+			case "url":
+				validationError.Message = fieldError.Field() + " must be a valid URL"
+			case "excludes":
+				validationError.Message = fieldError.Field() + " cannot contain '" + fieldError.Param() + "'"
 			default:
 				validationError.Message = fieldError.Field() + " is invalid"
 			}
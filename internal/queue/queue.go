@@ -0,0 +1,349 @@
+// Package queue is a durable background job queue backed by the jobs table.
+// Workers retry failed jobs with exponential backoff and move jobs that run out
+// of attempts to the dead letters, where admins can retry or delete them.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+var (
+	// ErrJobNotFound is returned for job IDs that do not exist
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobNotDead is returned when retrying a job that has not run out of attempts
+	ErrJobNotDead = errors.New("job is not dead")
+)
+
+// Handler performs one job of a type. The payload is the JSON encoding of the
+// value passed to Enqueue. Returning an error retries the job unless it is
+// wrapped with Permanent.
+type Handler func(ctx context.Context, payload []byte) error
+
+// permanentError marks an error retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job is moved to the dead letters without further
+// attempts, for failures such as malformed payloads
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Options configures a queue
+type Options struct {
+	Workers      int           // jobs processed concurrently
+	MaxAttempts  int           // attempts before a job is dead
+	Backoff      time.Duration // delay before the first retry, doubled for each further one
+	MaxBackoff   time.Duration // cap of the retry delay
+	PollInterval time.Duration // how often idle workers look for due jobs
+	Timeout      time.Duration // run time after which a job counts as abandoned and is claimed again
+}
+
+// Stats counts the jobs in each status
+type Stats struct {
+	Pending   int64 `json:"pending"`
+	Running   int64 `json:"running"`
+	Succeeded int64 `json:"succeeded"`
+	Dead      int64 `json:"dead"`
+}
+
+// Queue stores jobs in the database and runs them on a pool of workers
+type Queue struct {
+	jobs     repositories.QueuedJobRepository
+	options  Options
+	handlers map[string]Handler
+	mu       sync.RWMutex
+
+	wake    chan struct{}
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// New creates a queue storing its jobs in jobs
+func New(jobs repositories.QueuedJobRepository, options Options) *Queue {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = time.Second
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Minute
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		jobs:     jobs,
+		options:  options,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Register sets the handler of a job type
+func (q *Queue) Register(jobType string, handler Handler) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = handler
+}
+
+// Enqueue stores a job of jobType to run as soon as a worker is free
+func (q *Queue) Enqueue(jobType string, payload interface{}) error {
+	return q.EnqueueAt(jobType, payload, time.Now())
+}
+
+// EnqueueAt stores a job of jobType to run at runAt
+func (q *Queue) EnqueueAt(jobType string, payload interface{}, runAt time.Time) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s job: %w", jobType, err)
+	}
+
+	job := &models.QueuedJob{
+		Type:        jobType,
+		Payload:     string(data),
+		Status:      models.QueuedJobPending,
+		MaxAttempts: q.options.MaxAttempts,
+		RunAt:       runAt,
+	}
+	if err := q.jobs.Create(job); err != nil {
+		return fmt.Errorf("failed to enqueue %s job: %w", jobType, err)
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start starts the workers
+func (q *Queue) Start() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		return
+	}
+	q.started = true
+
+	for i := 0; i < q.options.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+}
+
+// Stop stops the workers, cancelling the jobs they run, and waits for them to
+// return. Cancelled jobs are retried after the next start.
+func (q *Queue) Stop() {
+	q.cancel()
+	q.wg.Wait()
+}
+
+// ProcessDue runs the jobs that are due on the calling goroutine until none is
+// left and returns how many ran. Failed jobs are rescheduled as usual, so they
+// are not run again within the same call unless their retry is already due.
+func (q *Queue) ProcessDue() (int, error) {
+	processed := 0
+	for {
+		ran, err := q.processNext()
+		if err != nil || !ran {
+			return processed, err
+		}
+		processed++
+	}
+}
+
+// Stats counts the jobs in each status
+func (q *Queue) Stats() (*Stats, error) {
+	counts, err := q.jobs.CountByStatus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return &Stats{
+		Pending:   counts[models.QueuedJobPending],
+		Running:   counts[models.QueuedJobRunning],
+		Succeeded: counts[models.QueuedJobSucceeded],
+		Dead:      counts[models.QueuedJobDead],
+	}, nil
+}
+
+// List lists jobs, optionally of one status, most recently updated first
+func (q *Queue) List(status models.QueuedJobStatus, offset, limit int) ([]models.QueuedJob, int64, error) {
+	jobs, total, err := q.jobs.List(status, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, total, nil
+}
+
+// Retry gives a dead job a fresh set of attempts
+func (q *Queue) Retry(id uint) (*models.QueuedJob, error) {
+	requeued, err := q.jobs.Requeue(id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+	if !requeued {
+		if _, err := q.get(id); err != nil {
+			return nil, err
+		}
+		return nil, ErrJobNotDead
+	}
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return q.get(id)
+}
+
+// Delete removes a job, typically a dead one that is not worth retrying
+func (q *Queue) Delete(id uint) error {
+	if err := q.jobs.Delete(id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return ErrJobNotFound
+		}
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	return nil
+}
+
+// work runs due jobs until the queue stops, sleeping while there are none
+func (q *Queue) work() {
+	defer q.wg.Done()
+
+	ticker := time.NewTicker(q.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		ran, err := q.processNext()
+		if err != nil {
+			slog.Error("Job queue failed", "error", err)
+		}
+		if ran {
+			continue
+		}
+
+		select {
+		case <-q.wake:
+		case <-ticker.C:
+		case <-q.ctx.Done():
+			return
+		}
+	}
+}
+
+// processNext claims the next due job and runs it, reporting whether there was one
+func (q *Queue) processNext() (bool, error) {
+	if q.ctx.Err() != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	job, err := q.jobs.Claim(now, now.Add(-q.options.Timeout))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return true, q.run(job)
+}
+
+// run runs a claimed job and records its outcome
+func (q *Queue) run(job *models.QueuedJob) error {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	var err error
+	if !ok {
+		err = Permanent(fmt.Errorf("no handler for job type %s", job.Type))
+	} else {
+		ctx, cancel := context.WithTimeout(q.ctx, q.options.Timeout)
+		err = safeRun(ctx, handler, []byte(job.Payload))
+		cancel()
+	}
+
+	now := time.Now()
+	if err == nil {
+		if err := q.jobs.Complete(job.ID, now); err != nil {
+			return fmt.Errorf("failed to complete job %d: %w", job.ID, err)
+		}
+		return nil
+	}
+
+	var permanent *permanentError
+	if errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts {
+		slog.Error("Job is dead", "job_id", job.ID, "type", job.Type, "attempts", job.Attempts, "error", err)
+		if err := q.jobs.Bury(job.ID, now, err.Error()); err != nil {
+			return fmt.Errorf("failed to bury job %d: %w", job.ID, err)
+		}
+		return nil
+	}
+
+	if err := q.jobs.Reschedule(job.ID, now.Add(q.backoff(job.Attempts)), err.Error()); err != nil {
+		return fmt.Errorf("failed to reschedule job %d: %w", job.ID, err)
+	}
+	return nil
+}
+
+// backoff returns the delay before retrying a job that failed attempts times:
+// the base delay doubled for each earlier failure, capped, plus up to 20% jitter
+// so jobs that failed together do not retry together
+func (q *Queue) backoff(attempts int) time.Duration {
+	delay := q.options.Backoff
+	for i := 1; i < attempts && delay < q.options.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if q.options.MaxBackoff > 0 && delay > q.options.MaxBackoff {
+		delay = q.options.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
+// get loads a job, mapping a missing row to ErrJobNotFound
+func (q *Queue) get(id uint) (*models.QueuedJob, error) {
+	job, err := q.jobs.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return job, nil
+}
+
+// safeRun runs a handler, turning a panic into an error
+func safeRun(ctx context.Context, handler Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
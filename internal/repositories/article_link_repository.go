@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BrokenLink is a broken link found in a published article, with the article
+// and its author
+type BrokenLink struct {
+	ArticleID   uint       `json:"article_id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	AuthorID    uint       `json:"author_id"`
+	URL         string     `json:"url"`
+	StatusCode  int        `json:"status_code"`
+	Error       string     `json:"error,omitempty"`
+	BrokenSince *time.Time `json:"broken_since"`
+	CheckedAt   time.Time  `json:"checked_at"`
+}
+
+type articleLinkRepository struct {
+	*Repository[models.ArticleLink]
+}
+
+// NewArticleLinkRepository creates a new article link repository
+func NewArticleLinkRepository(db *database.DB) ArticleLinkRepository {
+	return &articleLinkRepository{
+		Repository: NewRepository[models.ArticleLink](db),
+	}
+}
+
+// PublishedAfter returns up to limit published articles with an ID above
+// afterID, in ID order, with only the columns the link check reads
+func (r *articleLinkRepository) PublishedAfter(afterID uint, limit int) ([]models.Article, error) {
+	var articles []models.Article
+	err := r.GetDB().GetDB().
+		Select("id, title, slug, content, author_id").
+		Where("id > ? AND status = ?", afterID, models.StatusPublished).
+		Order("id").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// GetByArticle returns the links found in the article by its last check
+func (r *articleLinkRepository) GetByArticle(articleID uint) ([]models.ArticleLink, error) {
+	var links []models.ArticleLink
+	err := r.GetDB().GetDB().Where("article_id = ?", articleID).Order("id").Find(&links).Error
+	return links, err
+}
+
+// Replace swaps the links of the article for the ones of a new check in one
+// transaction
+func (r *articleLinkRepository) Replace(articleID uint, links []models.ArticleLink) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		if err := tx.GetDB().Where("article_id = ?", articleID).Delete(&models.ArticleLink{}).Error; err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		for i := range links {
+			links[i].ArticleID = articleID
+		}
+		return tx.BulkCreate(&links, len(links))
+	})
+}
+
+// ListBroken returns broken links of published articles, longest broken first,
+// leaving out articles deleted or unpublished since they were checked
+func (r *articleLinkRepository) ListBroken(offset, limit int) ([]BrokenLink, int64, error) {
+	base := r.GetDB().GetDB().Model(&models.ArticleLink{}).
+		Joins("JOIN articles ON articles.id = article_links.article_id AND articles.deleted_at IS NULL").
+		Where("article_links.broken = ? AND articles.status = ?", true, models.StatusPublished)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var links []BrokenLink
+	err := base.
+		Select("article_links.article_id, articles.title, articles.slug, articles.author_id, article_links.url, " +
+			"article_links.status_code, article_links.error, article_links.broken_since, article_links.checked_at").
+		Order("article_links.broken_since, article_links.id").
+		Offset(offset).Limit(limit).
+		Scan(&links).Error
+	return links, total, err
+}
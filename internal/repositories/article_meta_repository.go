@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type articleMetaRepository struct {
+	*Repository[models.ArticleMeta]
+}
+
+// NewArticleMetaRepository creates a new article custom field repository
+func NewArticleMetaRepository(db *database.DB) ArticleMetaRepository {
+	return &articleMetaRepository{
+		Repository: NewRepository[models.ArticleMeta](db),
+	}
+}
+
+// ListByArticle returns the custom fields of an article ordered by key
+func (r *articleMetaRepository) ListByArticle(articleID uint) ([]models.ArticleMeta, error) {
+	var fields []models.ArticleMeta
+	err := r.GetDB().GetDB().Where("article_id = ?", articleID).Order("meta_key ASC").Find(&fields).Error
+	return fields, err
+}
+
+// Save sets the custom fields of an article in one transaction, replacing the
+// values of the keys it already has
+func (r *articleMetaRepository) Save(articleID uint, fields []models.ArticleMeta) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		for i := range fields {
+			fields[i].ArticleID = articleID
+
+			var existing models.ArticleMeta
+			err := tx.GetDB().Where("article_id = ? AND meta_key = ?", articleID, fields[i].Key).First(&existing).Error
+			if errors.Is(err, ErrNotFound) {
+				if err := tx.Create(&fields[i]); err != nil {
+					return err
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			fields[i].ID = existing.ID
+			fields[i].CreatedAt = existing.CreatedAt
+			if err := tx.Update(&fields[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteKey removes a custom field of an article, returning ErrNotFound when it has none under key
+func (r *articleMetaRepository) DeleteKey(articleID uint, key string) error {
+	result := r.GetDB().GetDB().Where("article_id = ? AND meta_key = ?", articleID, key).Delete(&models.ArticleMeta{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
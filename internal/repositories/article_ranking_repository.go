@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// popularityScoreExpr weighs the engagement of an article: views once, likes
+// three times and comments five times
+const popularityScoreExpr = "articles.view_count + 3 * articles.like_count + 5 * articles.comment_count"
+
+// RankedArticle is a published article with its precomputed scores
+type RankedArticle struct {
+	ArticleID       uint      `json:"article_id"`
+	Title           string    `json:"title"`
+	Slug            string    `json:"slug"`
+	ViewCount       uint      `json:"view_count"`
+	LikeCount       uint      `json:"like_count"`
+	CommentCount    uint      `json:"comment_count"`
+	PopularityScore float64   `json:"popularity_score"`
+	TrendingScore   float64   `json:"trending_score"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type articleRankingRepository struct {
+	*Repository[models.ArticleRanking]
+}
+
+// NewArticleRankingRepository creates a new article ranking repository
+func NewArticleRankingRepository(db *database.DB) ArticleRankingRepository {
+	return &articleRankingRepository{
+		Repository: NewRepository[models.ArticleRanking](db),
+	}
+}
+
+// Refresh recomputes the rankings of every published article at now in one
+// transaction. The trending score divides the popularity score by one plus
+// the article's age in trending windows.
+func (r *articleRankingRepository) Refresh(now time.Time, trendingWindow time.Duration) (int64, error) {
+	windowDays := trendingWindow.Hours() / 24
+	if windowDays <= 0 {
+		return 0, fmt.Errorf("trending window must be positive, got %s", trendingWindow)
+	}
+
+	var refreshed int64
+	err := r.GetDB().Transaction(func(tx *database.DB) error {
+		if err := tx.Exec("DELETE FROM article_rankings"); err != nil {
+			return err
+		}
+
+		age := tx.DaysSinceExpr("articles.published_at")
+		result := tx.GetDB().Exec(fmt.Sprintf(`INSERT INTO article_rankings
+			(article_id, popularity_score, trending_score, published_at, refreshed_at)
+			SELECT articles.id, %[1]s,
+				(%[1]s) / (1 + CASE WHEN %[2]s > 0 THEN %[2]s ELSE 0 END / ?),
+				articles.published_at, ?
+			FROM articles
+			WHERE articles.status = ? AND articles.published_at IS NOT NULL AND articles.deleted_at IS NULL`,
+			popularityScoreExpr, age),
+			now, now, windowDays, now, models.StatusPublished)
+		refreshed = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return refreshed, nil
+}
+
+// Popular returns the published articles with the highest popularity scores
+func (r *articleRankingRepository) Popular(limit int) ([]RankedArticle, error) {
+	var ranked []RankedArticle
+	err := r.ranked().
+		Order("article_rankings.popularity_score DESC, article_rankings.article_id").
+		Limit(limit).
+		Scan(&ranked).Error
+	return ranked, err
+}
+
+// Trending returns the articles published since the given time with the
+// highest trending scores
+func (r *articleRankingRepository) Trending(limit int, since time.Time) ([]RankedArticle, error) {
+	var ranked []RankedArticle
+	err := r.ranked().
+		Where("article_rankings.published_at >= ?", since).
+		Order("article_rankings.trending_score DESC, article_rankings.article_id").
+		Limit(limit).
+		Scan(&ranked).Error
+	return ranked, err
+}
+
+// Count returns the number of ranked articles
+func (r *articleRankingRepository) Count() (int64, error) {
+	return r.Repository.Count()
+}
+
+// ranked joins the rankings to their articles, leaving out articles deleted or
+// unpublished since the last refresh
+func (r *articleRankingRepository) ranked() *gorm.DB {
+	return r.GetDB().GetDB().Model(&models.ArticleRanking{}).
+		Select("article_rankings.article_id, articles.title, articles.slug, articles.view_count, "+
+			"articles.like_count, articles.comment_count, article_rankings.popularity_score, "+
+			"article_rankings.trending_score, articles.created_at").
+		Joins("JOIN articles ON articles.id = article_rankings.article_id AND articles.deleted_at IS NULL").
+		Where("articles.status = ?", models.StatusPublished)
+}
@@ -1,58 +1,64 @@
 package repositories
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"go-blog/internal/database"
 	"go-blog/internal/models"
+
+	"gorm.io/gorm"
 )
 
-// SearchFilters represents advanced search filters
-type SearchFilters struct {
-	Status     string    `json:"status,omitempty"`
-	CategoryID uint      `json:"category_id,omitempty"`
-	AuthorID   uint      `json:"author_id,omitempty"`
-	TagID      uint      `json:"tag_id,omitempty"`
-	DateFrom   time.Time `json:"date_from,omitempty"`
-	DateTo     time.Time `json:"date_to,omitempty"`
+// articleSummaryColumns is the projection used by list queries. It leaves out the
+// longtext content, which only the single-article lookups load.
+var articleSummaryColumns = []string{
+	"articles.id", "articles.title", "articles.slug", "articles.excerpt",
+	"articles.author_id", "articles.category_id", "articles.status",
+	"articles.view_count", "articles.like_count", "articles.comment_count",
+	"articles.published_at", "articles.expires_at", "articles.allow_comments", "articles.allow_likes", "articles.no_index",
+	"articles.visibility", "articles.og_image_url",
+	"articles.created_at", "articles.updated_at", "articles.deleted_at",
+}
+
+// notExpired leaves out articles whose expiry has passed, which stay published
+// until the expiry job archives them
+func notExpired(query *gorm.DB) *gorm.DB {
+	return query.Where("(articles.expires_at IS NULL OR articles.expires_at > ?)", time.Now())
 }
 
 type articleRepository struct {
-	*BaseRepository
+	*Repository[models.Article]
 }
 
 // NewArticleRepository creates a new article repository
 func NewArticleRepository(db *database.DB) ArticleRepository {
 	return &articleRepository{
-		BaseRepository: NewBaseRepository(db),
+		Repository: NewRepository[models.Article](db),
 	}
 }
 
-func (r *articleRepository) Create(article *models.Article) error {
-	return r.BaseRepository.Create(article)
+func (r *articleRepository) GetByID(id uint) (*models.Article, error) {
+	return r.Get(id, "Author", "Category", "Tags")
 }
 
-func (r *articleRepository) GetByID(id uint) (*models.Article, error) {
-	var article models.Article
-	err := r.BaseRepository.GetByID(&article, id, "Author", "Category", "Tags")
-	if err != nil {
-		return nil, err
-	}
-	return &article, nil
+// Delete soft-deletes an article with its comments and frees its slug for new
+// articles
+func (r *articleRepository) Delete(id uint) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		if _, err := tx.SoftDeleteReleasing(&models.Article{}, []string{"slug"}, "id = ?", id); err != nil {
+			return err
+		}
+		return tx.GetDB().Where("article_id = ?", id).Delete(&models.Comment{}).Error
+	})
 }
 
 func (r *articleRepository) GetBySlug(slug string) (*models.Article, error) {
-	var article models.Article
-	err := r.GetDB().GetByField(&article, "slug", slug, "Author", "Category", "Tags")
-	if err != nil {
-		return nil, err
-	}
-	return &article, nil
+	return r.GetBy("slug", slug, "Author", "Category", "Tags")
 }
 
 func (r *articleRepository) List(offset, limit int, filters map[string]interface{}) ([]models.Article, int64, error) {
-	var articles []models.Article
-	
 	// Convert offset/limit to page-based pagination
 	page := (offset / limit) + 1
 	if page < 1 {
@@ -62,260 +68,290 @@ func (r *articleRepository) List(offset, limit int, filters map[string]interface
 	options := &database.QueryOptions{
 		Page:     page,
 		Limit:    limit,
-		OrderBy:  "created_at DESC",
+		OrderBy:  DefaultArticleSort.orderClause(),
 		Filters:  filters,
 		Preloads: []string{"Author", "Category", "Tags"},
+		Select:   articleSummaryColumns,
 	}
 	
-	result, err := r.BaseRepository.List(&articles, options)
+	result, err := r.Repository.List(options)
 	if err != nil {
 		return nil, 0, err
 	}
 	
-	return articles, result.Total, nil
+	return result.Items, result.Total, nil
 }
 
-func (r *articleRepository) Update(article *models.Article) error {
-	return r.BaseRepository.Update(article)
-}
-
-func (r *articleRepository) Delete(id uint) error {
-	return r.BaseRepository.Delete(&models.Article{}, id)
-}
-
-func (r *articleRepository) Search(query string, offset, limit int) ([]models.Article, int64, error) {
-	return r.AdvancedSearch(query, offset, limit, nil)
-}
-
-func (r *articleRepository) AdvancedSearch(query string, offset, limit int, filters *SearchFilters) ([]models.Article, int64, error) {
+func (r *articleRepository) ListFiltered(offset, limit int, filter *ArticleFilter, sortBy ArticleSort) ([]models.Article, int64, error) {
 	var articles []models.Article
-	
-	// Convert offset/limit to page-based pagination
-	page := (offset / limit) + 1
-	if page < 1 {
-		page = 1
-	}
-	
-	// Build the search query using MySQL FULLTEXT search
+
 	db := r.GetDB().GetDB()
-	
-	// Start with base query
-	searchQuery := db.Model(&models.Article{}).
-		Preload("Author").
-		Preload("Category").
-		Preload("Tags")
-	
-	// Apply FULLTEXT search if query is provided
-	if query != "" {
-		// Use MySQL FULLTEXT search with relevance scoring
-		searchQuery = searchQuery.Where("MATCH(title, content, excerpt) AGAINST(? IN NATURAL LANGUAGE MODE)", query).
-			Select("*, MATCH(title, content, excerpt) AGAINST(? IN NATURAL LANGUAGE MODE) as relevance_score", query).
-			Order("relevance_score DESC, created_at DESC")
-	} else {
-		searchQuery = searchQuery.Order("created_at DESC")
-	}
-	
-	// Apply filters if provided
-	if filters != nil {
-		if filters.Status != "" {
-			searchQuery = searchQuery.Where("status = ?", filters.Status)
+	query := db.Model(&models.Article{})
+
+	if filter != nil {
+		if filter.Status != "" {
+			query = query.Where("articles.status = ?", filter.Status)
+			if filter.Status == string(models.StatusPublished) {
+				query = notExpired(query)
+			}
+		}
+		if filter.AuthorID > 0 {
+			query = query.Where("articles.author_id = ?", filter.AuthorID)
 		}
-		if filters.CategoryID > 0 {
-			searchQuery = searchQuery.Where("category_id = ?", filters.CategoryID)
+		if len(filter.CategoryIDs) > 0 {
+			query = query.Where("articles.category_id IN ?", filter.CategoryIDs)
 		}
-		if filters.AuthorID > 0 {
-			searchQuery = searchQuery.Where("author_id = ?", filters.AuthorID)
+		if len(filter.TagIDs) > 0 {
+			tagged := db.Table("article_tags").Select("article_id").Where("tag_id IN ?", filter.TagIDs)
+			if filter.TagMatch == TagMatchAll {
+				tagged = tagged.Group("article_id").Having("COUNT(DISTINCT tag_id) = ?", len(uniqueIDs(filter.TagIDs)))
+			}
+			query = query.Where("articles.id IN (?)", tagged)
 		}
-		if filters.TagID > 0 {
-			searchQuery = searchQuery.Joins("JOIN article_tags ON articles.id = article_tags.article_id").
-				Where("article_tags.tag_id = ?", filters.TagID)
+		if !filter.PublishedFrom.IsZero() {
+			query = query.Where("articles.published_at >= ?", filter.PublishedFrom)
 		}
-		if !filters.DateFrom.IsZero() {
-			searchQuery = searchQuery.Where("created_at >= ?", filters.DateFrom)
+		if !filter.PublishedTo.IsZero() {
+			query = query.Where("articles.published_at <= ?", filter.PublishedTo)
 		}
-		if !filters.DateTo.IsZero() {
-			searchQuery = searchQuery.Where("created_at <= ?", filters.DateTo)
+		if filter.MinViews > 0 {
+			query = query.Where("articles.view_count >= ?", filter.MinViews)
 		}
 	}
-	
-	// Count total results
+
 	var total int64
-	countQuery := searchQuery
-	if err := countQuery.Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
-	
-	// Apply pagination
-	if err := searchQuery.Offset(offset).Limit(limit).Find(&articles).Error; err != nil {
+
+	err := query.Select(articleSummaryColumns).
+		Preload("Author").Preload("Category").Preload("Tags").
+		Order(sortBy.orderClause()).
+		Offset(offset).Limit(limit).
+		Find(&articles).Error
+	if err != nil {
 		return nil, 0, err
 	}
-	
+
 	return articles, total, nil
 }
 
+// uniqueIDs returns ids without duplicates, preserving order
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// ReplaceTags sets the tags of an article, removing associations to tags not in tags
+func (r *articleRepository) ReplaceTags(article *models.Article, tags []models.Tag) error {
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+	if err := r.GetDB().GetDB().Model(article).Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	article.Tags = tags
+	return nil
+}
+
+func (r *articleRepository) Search(query string, offset, limit int) ([]models.Article, int64, error) {
+	return r.AdvancedSearch(query, offset, limit, nil)
+}
+
+func (r *articleRepository) AdvancedSearch(query string, offset, limit int, filters *SearchFilters) ([]models.Article, int64, error) {
+	return r.search(query, false, offset, limit, filters)
+}
+
 func (r *articleRepository) SearchWithBoolean(query string, offset, limit int, filters *SearchFilters) ([]models.Article, int64, error) {
+	return r.search(query, true, offset, limit, filters)
+}
+
+// search runs a full-text article search. The count and the page are built from
+// separate sessions of the same filtered base query, so the relevance projection,
+// ordering and preloads never leak into the count.
+func (r *articleRepository) search(query string, boolean bool, offset, limit int, filters *SearchFilters) ([]models.Article, int64, error) {
 	var articles []models.Article
-	
-	// Convert offset/limit to page-based pagination
-	page := (offset / limit) + 1
-	if page < 1 {
-		page = 1
-	}
-	
-	// Build the search query using MySQL FULLTEXT Boolean search
-	db := r.GetDB().GetDB()
-	
-	// Start with base query
-	searchQuery := db.Model(&models.Article{}).
-		Preload("Author").
-		Preload("Category").
-		Preload("Tags")
-	
-	// Apply FULLTEXT Boolean search if query is provided
+	var total int64
+
+	db := r.GetDB()
+	base := r.searchBase(filters)
+
+	// Relevance is scored on the article text; matches found only in comments score zero
+	var matchExpr string
+	var matchArgs []interface{}
 	if query != "" {
-		// Use MySQL FULLTEXT Boolean search for advanced operators
-		searchQuery = searchQuery.Where("MATCH(title, content, excerpt) AGAINST(? IN BOOLEAN MODE)", query).
-			Select("*, MATCH(title, content, excerpt) AGAINST(? IN BOOLEAN MODE) as relevance_score", query).
-			Order("relevance_score DESC, created_at DESC")
-	} else {
-		searchQuery = searchQuery.Order("created_at DESC")
-	}
-	
-	// Apply filters if provided
-	if filters != nil {
-		if filters.Status != "" {
-			searchQuery = searchQuery.Where("status = ?", filters.Status)
-		}
-		if filters.CategoryID > 0 {
-			searchQuery = searchQuery.Where("category_id = ?", filters.CategoryID)
+		matchExpr, matchArgs = "0", nil
+		var conditions []string
+		var conditionArgs []interface{}
+		if searchesTarget(filters, SearchArticles) {
+			matchExpr, matchArgs = db.FullTextMatch([]string{"articles.title", "articles.content", "articles.excerpt"}, query, boolean)
+			conditions = append(conditions, matchExpr+" > 0")
+			conditionArgs = append(conditionArgs, matchArgs...)
 		}
-		if filters.AuthorID > 0 {
-			searchQuery = searchQuery.Where("author_id = ?", filters.AuthorID)
+		if searchesTarget(filters, SearchComments) {
+			commentExpr, commentArgs := db.FullTextMatch([]string{"comments.content"}, query, boolean)
+			conditions = append(conditions, "articles.id IN (SELECT comments.article_id FROM comments WHERE comments.deleted_at IS NULL AND "+commentExpr+" > 0)")
+			conditionArgs = append(conditionArgs, commentArgs...)
 		}
-		if filters.TagID > 0 {
-			searchQuery = searchQuery.Joins("JOIN article_tags ON articles.id = article_tags.article_id").
-				Where("article_tags.tag_id = ?", filters.TagID)
-		}
-		if !filters.DateFrom.IsZero() {
-			searchQuery = searchQuery.Where("created_at >= ?", filters.DateFrom)
-		}
-		if !filters.DateTo.IsZero() {
-			searchQuery = searchQuery.Where("created_at <= ?", filters.DateTo)
+		if len(conditions) == 0 {
+			return []models.Article{}, 0, nil
 		}
+		base = base.Where("("+strings.Join(conditions, " OR ")+")", conditionArgs...)
 	}
-	
-	// Count total results
-	var total int64
-	countQuery := searchQuery
-	if err := countQuery.Count(&total).Error; err != nil {
+
+	if err := base.Session(&gorm.Session{}).Distinct("articles.id").Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
-	
-	// Apply pagination
-	if err := searchQuery.Offset(offset).Limit(limit).Find(&articles).Error; err != nil {
+
+	find := base.Session(&gorm.Session{}).Preload("Author").Preload("Category").Preload("Tags")
+	if query != "" {
+		find = find.Select(strings.Join(articleSummaryColumns, ", ")+", "+matchExpr+" AS relevance_score", matchArgs...).
+			Order("relevance_score DESC, articles.created_at DESC, articles.id DESC")
+	} else {
+		find = find.Select(articleSummaryColumns).Order("articles.created_at DESC, articles.id DESC")
+	}
+
+	if err := find.Offset(offset).Limit(limit).Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
-	
+
 	return articles, total, nil
 }
 
-func (r *articleRepository) GetArchive() (map[string]interface{}, error) {
-	db := r.GetDB().GetDB()
-	
-	// Query to get article counts grouped by year and month
-	// Only include published articles
-	var results []struct {
-		Year  int   `json:"year"`
-		Month int   `json:"month"`
-		Count int64 `json:"count"`
-	}
-	
-	query := `
-		SELECT 
-			YEAR(published_at) as year,
-			MONTH(published_at) as month,
-			COUNT(*) as count
-		FROM articles 
-		WHERE status = 'published' 
-			AND published_at IS NOT NULL
-			AND deleted_at IS NULL
-		GROUP BY YEAR(published_at), MONTH(published_at)
-		ORDER BY year DESC, month DESC
-	`
-	
-	if err := db.Raw(query).Scan(&results).Error; err != nil {
-		return nil, err
+// searchesTarget reports whether an article search matches against target. Without
+// explicit targets only the article text is searched.
+func searchesTarget(filters *SearchFilters, target SearchTarget) bool {
+	if filters == nil || len(filters.Targets) == 0 {
+		return target == SearchArticles
 	}
-	
-	// Organize results by year
-	yearMap := make(map[int][]map[string]interface{})
-	
-	for _, result := range results {
-		if _, exists := yearMap[result.Year]; !exists {
-			yearMap[result.Year] = []map[string]interface{}{}
-		}
-		
-		monthData := map[string]interface{}{
-			"month": int64(result.Month),
-			"count": result.Count,
+	for _, t := range filters.Targets {
+		if t == target {
+			return true
 		}
-		
-		yearMap[result.Year] = append(yearMap[result.Year], monthData)
 	}
-	
-	// Convert to the expected format
-	var years []map[string]interface{}
-	
-	for year, months := range yearMap {
-		yearData := map[string]interface{}{
-			"year":   int64(year),
-			"months": months,
+	return false
+}
+
+// searchBase applies the search filters without any projection or ordering. The tag
+// filter is a subquery rather than a join so an article is never counted twice.
+func (r *articleRepository) searchBase(filters *SearchFilters) *gorm.DB {
+	query := r.GetDB().GetDB().Model(&models.Article{})
+	if filters == nil {
+		return query
+	}
+
+	if filters.Status != "" {
+		query = query.Where("articles.status = ?", filters.Status)
+		if filters.Status == string(models.StatusPublished) {
+			query = notExpired(query)
 		}
-		years = append(years, yearData)
 	}
-	
-	response := map[string]interface{}{
-		"years": years,
+	if filters.CategoryID > 0 {
+		query = query.Where("articles.category_id = ?", filters.CategoryID)
 	}
-	
-	return response, nil
+	if filters.AuthorID > 0 {
+		query = query.Where("articles.author_id = ?", filters.AuthorID)
+	}
+	if filters.TagID > 0 {
+		query = query.Where("articles.id IN (?)",
+			r.GetDB().GetDB().Table("article_tags").Select("article_id").Where("tag_id = ?", filters.TagID))
+	}
+	if !filters.DateFrom.IsZero() {
+		query = query.Where("articles.created_at >= ?", filters.DateFrom)
+	}
+	if !filters.DateTo.IsZero() {
+		query = query.Where("articles.created_at <= ?", filters.DateTo)
+	}
+	if !filters.PublishedAfter.IsZero() {
+		query = query.Where("articles.published_at > ?", filters.PublishedAfter)
+	}
+	return query
+}
+
+// GetArchive returns published article counts per year and month, newest first,
+// computed in a single GROUP BY query
+func (r *articleRepository) GetArchive() ([]ArchiveEntry, error) {
+	db := r.GetDB()
+	var entries []ArchiveEntry
+
+	err := notExpired(db.GetDB().Model(&models.Article{})).
+		Select(db.YearExpr("published_at")+" AS year, "+db.MonthExpr("published_at")+" AS month, COUNT(*) AS count").
+		Where("status = ? AND published_at IS NOT NULL", models.StatusPublished).
+		Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
 }
 
 func (r *articleRepository) GetByMonth(year, month int, offset, limit int) ([]models.Article, int64, error) {
-	var articles []models.Article
-	
-	db := r.GetDB().GetDB()
-	
-	// Create date range for the specified month
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
-	endDate := startDate.AddDate(0, 1, 0).Add(-time.Second) // Last second of the month
-	
-	// Build query for articles in the specified month
-	query := db.Model(&models.Article{}).
-		Where("status = ?", "published").
-		Where("published_at >= ? AND published_at <= ?", startDate, endDate).
+	return r.publishedBetween(startDate, startDate.AddDate(0, 1, 0), offset, limit)
+}
+
+// GetByDay returns the articles published on the given day, newest first
+func (r *articleRepository) GetByDay(year, month, day int, offset, limit int) ([]models.Article, int64, error) {
+	startDate := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return r.publishedBetween(startDate, startDate.AddDate(0, 0, 1), offset, limit)
+}
+
+// publishedBetween returns a page of the articles published from start up to
+// but excluding end, newest first, with their total
+func (r *articleRepository) publishedBetween(start, end time.Time, offset, limit int) ([]models.Article, int64, error) {
+	var articles []models.Article
+
+	query := notExpired(r.GetDB().GetDB().Model(&models.Article{})).
+		Where("status = ?", models.StatusPublished).
+		Where("published_at >= ? AND published_at < ?", start, end).
 		Preload("Author").
 		Preload("Category").
 		Preload("Tags").
 		Order("published_at DESC")
-	
-	// Count total results
+
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
-	
-	// Apply pagination and get results
-	if err := query.Offset(offset).Limit(limit).Find(&articles).Error; err != nil {
+
+	if err := query.Select(articleSummaryColumns).Offset(offset).Limit(limit).Find(&articles).Error; err != nil {
 		return nil, 0, err
 	}
-	
+
 	return articles, total, nil
 }
 
+// GetCalendar returns published article counts per day of the given month,
+// skipping days without articles, computed in a single GROUP BY query
+func (r *articleRepository) GetCalendar(year, month int) ([]CalendarDay, error) {
+	db := r.GetDB()
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	var days []CalendarDay
+
+	err := notExpired(db.GetDB().Model(&models.Article{})).
+		Select(db.DayExpr("published_at")+" AS day, COUNT(*) AS count").
+		Where("status = ? AND published_at >= ? AND published_at < ?",
+			models.StatusPublished, startDate, startDate.AddDate(0, 1, 0)).
+		Group("day").
+		Order("day").
+		Scan(&days).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return days, nil
+}
+
 func (r *articleRepository) GetByAuthorID(authorID uint, limit, offset int) ([]*models.Article, error) {
-	var articles []*models.Article
-	
 	// Convert offset/limit to page-based pagination
 	page := (offset / limit) + 1
 	if page < 1 {
@@ -333,27 +369,207 @@ func (r *articleRepository) GetByAuthorID(authorID uint, limit, offset int) ([]*
 		OrderBy:  "created_at DESC",
 		Filters:  filters,
 		Preloads: []string{"Author", "Category", "Tags"},
+		Select:   articleSummaryColumns,
 	}
 	
-	result, err := r.BaseRepository.List(&articles, options)
+	result, err := r.Repository.List(options)
 	if err != nil {
 		return nil, err
 	}
 	
+	articles := make([]*models.Article, len(result.Items))
+	for i := range result.Items {
+		articles[i] = &result.Items[i]
+	}
 	return articles, nil
 }
 
+// CreatedSince returns when the author created articles since since, oldest
+// first. Trashed articles count, so deleting them does not free up a quota.
+func (r *articleRepository) CreatedSince(authorID uint, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.GetDB().GetDB().Unscoped().Model(&models.Article{}).
+		Where("author_id = ? AND created_at >= ?", authorID, since).
+		Order("created_at").
+		Pluck("created_at", &times).Error
+	if err != nil {
+		return nil, err
+	}
+	return times, nil
+}
+
 func (r *articleRepository) CountByAuthorID(authorID uint) (int64, error) {
 	filters := map[string]interface{}{
 		"author_id": authorID,
 		"status":    "published", // Only count published articles
 	}
 	
-	return r.BaseRepository.Count(&models.Article{}, filters)
+	return r.Count(filters)
+}
+
+// GetAuthorTotals sums the counters of an author's published articles in one query
+func (r *articleRepository) GetAuthorTotals(authorID uint) (*AuthorTotals, error) {
+	var totals AuthorTotals
+	err := r.GetDB().GetDB().Model(&models.Article{}).
+		Select("COUNT(*) AS article_count, COALESCE(SUM(view_count), 0) AS total_views, "+
+			"COALESCE(SUM(like_count), 0) AS total_likes, COALESCE(SUM(comment_count), 0) AS total_comments").
+		Where("author_id = ? AND status = ?", authorID, models.StatusPublished).
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return &totals, nil
+}
+
+// Transfer moves articles to another author in one transaction, trashed ones
+// included so they stay with the rest when restored. Author statistics are
+// summed from the articles, so they follow the articles without recounting.
+func (r *articleRepository) Transfer(transfer *ArticleTransfer) (int64, error) {
+	var moved int64
+	err := r.GetDB().Transaction(func(tx *database.DB) error {
+		query := tx.GetDB().Unscoped().Model(&models.Article{}).Where("author_id = ?", transfer.FromAuthorID)
+		if transfer.ArticleID != 0 {
+			query = query.Where("id = ?", transfer.ArticleID)
+		}
+		res := query.UpdateColumn("author_id", transfer.ToAuthorID)
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = res.RowsAffected
+
+		if transfer.Audit != nil && moved > 0 {
+			return tx.Create(transfer.Audit)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// AuthorMetric is a total authors are ranked by on the leaderboard
+type AuthorMetric string
+
+const (
+	AuthorMetricScore    AuthorMetric = "score"
+	AuthorMetricViews    AuthorMetric = "views"
+	AuthorMetricLikes    AuthorMetric = "likes"
+	AuthorMetricComments AuthorMetric = "comments"
+	AuthorMetricArticles AuthorMetric = "articles"
+)
+
+// authorMetricColumns maps leaderboard metrics to the aggregate they rank by
+var authorMetricColumns = map[AuthorMetric]string{
+	AuthorMetricScore:    "score",
+	AuthorMetricViews:    "total_views",
+	AuthorMetricLikes:    "total_likes",
+	AuthorMetricComments: "total_comments",
+	AuthorMetricArticles: "article_count",
+}
+
+// GetAuthorLeaderboard ranks the authors of published articles by metric in
+// one aggregate query, using RANK() so tied authors share a place. System
+// accounts are left out.
+func (r *articleRepository) GetAuthorLeaderboard(metric AuthorMetric, limit int) ([]AuthorRank, error) {
+	column, ok := authorMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown author metric %q", metric)
+	}
+
+	totals := r.GetDB().GetDB().Model(&models.Article{}).
+		Select("articles.author_id, users.username, users.handle, COUNT(*) AS article_count, "+
+			"COALESCE(SUM(articles.view_count), 0) AS total_views, "+
+			"COALESCE(SUM(articles.like_count), 0) AS total_likes, "+
+			"COALESCE(SUM(articles.comment_count), 0) AS total_comments, "+
+			"COALESCE(SUM("+popularityScoreExpr+"), 0) AS score").
+		Joins("JOIN users ON users.id = articles.author_id AND users.deleted_at IS NULL").
+		Where("articles.status = ? AND users.role <> ?", models.StatusPublished, models.RoleSystem).
+		Group("articles.author_id, users.username, users.handle")
+
+	var ranks []AuthorRank
+	err := r.GetDB().GetDB().Table("(?) AS totals", totals).
+		Select("totals.*, RANK() OVER (ORDER BY " + column + " DESC) AS author_rank").
+		Order("author_rank, author_id").
+		Limit(limit).
+		Scan(&ranks).Error
+	if err != nil {
+		return nil, err
+	}
+	return ranks, nil
+}
+
+// AdjustLikeCount adds delta to the like counter, never taking it below zero
+func (r *articleRepository) AdjustLikeCount(id uint, delta int) error {
+	query := r.GetDB().GetDB().Model(&models.Article{}).Where("id = ?", id)
+	if delta < 0 {
+		query = query.Where("like_count >= ?", -delta)
+	}
+	return query.UpdateColumn("like_count", gorm.Expr("like_count + ?", delta)).Error
+}
+
+// AdjustCommentCount adds delta to the comment counter, never taking it below zero
+func (r *articleRepository) AdjustCommentCount(id uint, delta int) error {
+	return adjustCommentCount(r.GetDB().GetDB(), id, delta)
+}
+
+// adjustCommentCount adds delta to the comment counter of an article through db,
+// which may be a transaction
+func adjustCommentCount(db *gorm.DB, id uint, delta int) error {
+	query := db.Model(&models.Article{}).Where("id = ?", id)
+	if delta < 0 {
+		query = query.Where("comment_count >= ?", -delta)
+	}
+	return query.UpdateColumn("comment_count", gorm.Expr("comment_count + ?", delta)).Error
+}
+
+// PublishedTitles returns the titles of the limit most recently published
+// articles, the vocabulary of spelling suggestions
+func (r *articleRepository) PublishedTitles(limit int) ([]string, error) {
+	var titles []string
+	err := notExpired(r.GetDB().GetDB().Model(&models.Article{})).
+		Where("articles.status = ?", models.StatusPublished).
+		Order("articles.published_at DESC").
+		Limit(limit).
+		Pluck("articles.title", &titles).Error
+	return titles, err
+}
+
+// SearchTitlePrefix returns published articles with a title word starting with
+// prefix, titles starting with it first and then the most viewed. Only the id,
+// title and slug are loaded, keeping search-as-you-type responses small.
+func (r *articleRepository) SearchTitlePrefix(prefix string, limit int) ([]models.Article, error) {
+	pattern := database.EscapeLike(prefix) + "%"
+	escape := r.GetDB().LikeEscape()
+	var articles []models.Article
+	err := notExpired(r.GetDB().GetDB().Model(&models.Article{})).
+		Select("articles.id, articles.title, articles.slug, CASE WHEN articles.title LIKE ? "+escape+" THEN 0 ELSE 1 END AS prefix_rank", pattern).
+		Where("articles.status = ?", models.StatusPublished).
+		Where("(articles.title LIKE ? "+escape+" OR articles.title LIKE ? "+escape+")", pattern, "% "+pattern).
+		Order("prefix_rank, articles.view_count DESC, articles.id DESC").
+		Limit(limit).
+		Find(&articles).Error
+	return articles, err
+}
+
+// SetOGImage records the rendered share image of an article, leaving updated_at
+// alone since the article itself did not change
+func (r *articleRepository) SetOGImage(id uint, key, url string) error {
+	return r.GetDB().GetDB().Model(&models.Article{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"og_image_key": key, "og_image_url": url}).Error
 }
 
+// IncrementViewCount bumps the view counter, returning ErrNotFound for an unknown article
 func (r *articleRepository) IncrementViewCount(id uint) error {
-	return r.GetDB().Increment(&models.Article{}, id, "view_count", 1)
+	result := r.GetDB().GetDB().Model(&models.Article{}).Where("id = ?", id).
+		UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
 }
 
 func (r *articleRepository) UpdateStatistics(id uint, viewCount, likeCount, commentCount uint) error {
@@ -366,10 +582,153 @@ func (r *articleRepository) UpdateStatistics(id uint, viewCount, likeCount, comm
 }
 
 func (r *articleRepository) GetStatistics(id uint) (viewCount, likeCount, commentCount uint, err error) {
-	var article models.Article
-	err = r.GetDB().GetByID(&article, id)
+	article, err := r.Get(id)
 	if err != nil {
 		return 0, 0, 0, err
 	}
 	return article.ViewCount, article.LikeCount, article.CommentCount, nil
-}
\ No newline at end of file
+}
+
+// countedComments counts the comments of an article its comment counter
+// tracks: neither deleted, tombstones nor hidden by moderation
+const countedComments = "(SELECT COUNT(*) FROM comments WHERE comments.article_id = articles.id " +
+	"AND comments.deleted_at IS NULL AND comments.tombstone = FALSE AND comments.hidden = FALSE)"
+
+// RecountStatistics recomputes the like and comment counters of every article
+// from the likes and comments tables and returns how many articles were updated
+func (r *articleRepository) RecountStatistics() (int64, error) {
+	result := r.GetDB().GetDB().Exec("UPDATE articles SET " +
+		"like_count = (SELECT COUNT(*) FROM likes WHERE likes.article_id = articles.id AND likes.deleted_at IS NULL), " +
+		"comment_count = " + countedComments + " " +
+		"WHERE deleted_at IS NULL")
+	return result.RowsAffected, result.Error
+}
+
+// ReconcileCommentCounts corrects the comment counters that drifted from the
+// counted comments and returns how many articles were corrected
+func (r *articleRepository) ReconcileCommentCounts() (int64, error) {
+	result := r.GetDB().GetDB().Exec("UPDATE articles SET comment_count = " + countedComments +
+		" WHERE deleted_at IS NULL AND comment_count <> " + countedComments)
+	return result.RowsAffected, result.Error
+}
+
+// CommentCountDrift returns up to limit articles whose comment counter differs
+// from their counted comments, and how many such articles there are in total
+func (r *articleRepository) CommentCountDrift(limit int) ([]CommentCountDrift, int64, error) {
+	base := r.GetDB().GetDB().Model(&models.Article{}).
+		Where("comment_count <> " + countedComments)
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var drift []CommentCountDrift
+	err := base.Select("articles.id AS article_id, articles.title, articles.comment_count AS stored_count, " +
+		countedComments + " AS actual_count").
+		Order("articles.id").
+		Limit(limit).
+		Scan(&drift).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return drift, total, nil
+}
+
+// ArchiveExpired archives the published articles whose expiry passed by now and
+// returns how many were archived
+func (r *articleRepository) ArchiveExpired(now time.Time) (int64, error) {
+	result := r.GetDB().GetDB().Model(&models.Article{}).
+		Where("status = ? AND expires_at IS NOT NULL AND expires_at <= ?", models.StatusPublished, now).
+		UpdateColumns(map[string]interface{}{"status": models.StatusArchived, "updated_at": now})
+	return result.RowsAffected, result.Error
+}
+
+// PurgeDeleted permanently removes articles soft-deleted before the given time,
+// together with their comments, likes, tags, comment subscriptions and
+// revisions, and
+// returns how many articles were removed
+func (r *articleRepository) PurgeDeleted(before time.Time) (int64, error) {
+	var purged int64
+	err := r.GetDB().Transaction(func(tx *database.DB) error {
+		db := tx.GetDB()
+
+		var ids []uint
+		if err := db.Unscoped().Model(&models.Article{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		comments := db.Unscoped().Model(&models.Comment{}).Select("id").Where("article_id IN ?", ids)
+		if err := db.Where("comment_id IN (?)", comments).Delete(&models.Mention{}).Error; err != nil {
+			return err
+		}
+		if err := db.Where("comment_id IN (?)", comments).Delete(&models.CommentReport{}).Error; err != nil {
+			return err
+		}
+		links := db.Model(&models.ShortLink{}).Select("id").Where("article_id IN ?", ids)
+		if err := db.Where("short_link_id IN (?)", links).Delete(&models.ShortLinkClick{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&models.Comment{}, &models.Like{}, &models.CommentSubscription{}, &models.ArticleRevision{}, &models.ShortLink{}, &models.ArticleView{}, &models.ArticleLink{}, &models.ArticleMeta{}} {
+			if err := db.Unscoped().Where("article_id IN ?", ids).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("DELETE FROM article_tags WHERE article_id IN ?", ids); err != nil {
+			return err
+		}
+
+		result := db.Unscoped().Where("id IN ?", ids).Delete(&models.Article{})
+		if result.Error != nil {
+			return result.Error
+		}
+		purged = result.RowsAffected
+		return nil
+	})
+	return purged, err
+}
+
+// ListByTaxonomies returns published articles in any of the given categories or
+// tagged with any of the given tags, newest first
+func (r *articleRepository) ListByTaxonomies(categoryIDs, tagIDs []uint, offset, limit int) ([]models.Article, int64, error) {
+	var articles []models.Article
+
+	if len(categoryIDs) == 0 && len(tagIDs) == 0 {
+		return articles, 0, nil
+	}
+
+	db := r.GetDB().GetDB()
+	query := notExpired(db.Model(&models.Article{})).Where("status = ?", models.StatusPublished)
+
+	switch {
+	case len(categoryIDs) > 0 && len(tagIDs) > 0:
+		query = query.Where("category_id IN ? OR id IN (?)", categoryIDs,
+			db.Table("article_tags").Select("article_id").Where("tag_id IN ?", tagIDs))
+	case len(categoryIDs) > 0:
+		query = query.Where("category_id IN ?", categoryIDs)
+	default:
+		query = query.Where("id IN (?)",
+			db.Table("article_tags").Select("article_id").Where("tag_id IN ?", tagIDs))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Select(articleSummaryColumns).
+		Preload("Author").Preload("Category").Preload("Tags").
+		Order("published_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
@@ -464,4 +464,138 @@ func TestArticleRepository_StatusFiltering(t *testing.T) {
 			assert.Equal(t, status, article.Status)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestArticleRepository_SearchCounts(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	// Setup test database
+	db, err := database.SetupTestDB()
+	require.NoError(t, err)
+	defer database.CleanupTestDB(db)
+
+	articleRepo := NewArticleRepository(db)
+	userRepo := NewUserRepository(db)
+	tagRepo := NewTagRepository(db)
+
+	user := &models.User{
+		Username: "searchtest",
+		Email:    "searchtest@example.com",
+		Password: "hashedpassword",
+	}
+	require.NoError(t, userRepo.Create(user))
+
+	golang := &models.Tag{Name: "golang", Slug: "golang"}
+	web := &models.Tag{Name: "web", Slug: "web"}
+	require.NoError(t, tagRepo.Create(golang))
+	require.NoError(t, tagRepo.Create(web))
+
+	// Every article carries both tags, so a join on article_tags would yield two rows each
+	for i := 1; i <= 3; i++ {
+		article := &models.Article{
+			Title:    fmt.Sprintf("Concurrency in Go part %d", i),
+			Slug:     fmt.Sprintf("concurrency-%d", i),
+			Content:  "Goroutines and channels",
+			AuthorID: user.ID,
+			Status:   models.StatusPublished,
+			Tags:     []models.Tag{*golang, *web},
+		}
+		require.NoError(t, articleRepo.Create(article))
+	}
+	require.NoError(t, articleRepo.Create(&models.Article{
+		Title:    "Baking bread",
+		Slug:     "baking-bread",
+		Content:  "Flour and water",
+		AuthorID: user.ID,
+		Status:   models.StatusPublished,
+		Tags:     []models.Tag{*web},
+	}))
+
+	t.Run("Tag filter counts each article once", func(t *testing.T) {
+		articles, total, err := articleRepo.AdvancedSearch("", 0, 10, &SearchFilters{TagID: web.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Len(t, articles, 4)
+	})
+
+	t.Run("Query and tag filter", func(t *testing.T) {
+		articles, total, err := articleRepo.AdvancedSearch("concurrency", 0, 10, &SearchFilters{
+			Status: string(models.StatusPublished),
+			TagID:  golang.ID,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		assert.Len(t, articles, 3)
+		for _, article := range articles {
+			assert.Empty(t, article.Content)
+			assert.Len(t, article.Tags, 2)
+		}
+	})
+
+	t.Run("Total is independent of the page", func(t *testing.T) {
+		seen := map[uint]bool{}
+		for offset := 0; offset < 4; offset += 2 {
+			articles, total, err := articleRepo.SearchWithBoolean("", offset, 2, &SearchFilters{TagID: web.ID})
+			require.NoError(t, err)
+			assert.Equal(t, int64(4), total)
+			for _, article := range articles {
+				assert.False(t, seen[article.ID], "article %d returned twice", article.ID)
+				seen[article.ID] = true
+			}
+		}
+		assert.Len(t, seen, 4)
+	})
+
+	t.Run("Boolean operators", func(t *testing.T) {
+		articles, total, err := articleRepo.SearchWithBoolean("+bread", 0, 10, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, articles, 1)
+		assert.Equal(t, "baking-bread", articles[0].Slug)
+	})
+}
+
+func TestArticleRepository_SearchTitlePrefix(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	db, err := database.SetupTestDB()
+	require.NoError(t, err)
+	defer database.CleanupTestDB(db)
+
+	articleRepo := NewArticleRepository(db)
+	user := &models.User{Username: "prefixtest", Email: "prefixtest@example.com", Password: "hashedpassword"}
+	require.NoError(t, NewUserRepository(db).Create(user))
+
+	for i, title := range []string{"100% Go", "1000 tips", "snake_case names", "Naming in snakeXcase", `C:\ paths`, "Clean paths"} {
+		require.NoError(t, articleRepo.Create(&models.Article{
+			Title:    title,
+			Slug:     fmt.Sprintf("prefix-%d", i),
+			Content:  "Content",
+			AuthorID: user.ID,
+			Status:   models.StatusPublished,
+		}))
+	}
+
+	titles := func(prefix string) []string {
+		t.Helper()
+		articles, err := articleRepo.SearchTitlePrefix(prefix, 10)
+		require.NoError(t, err)
+		result := make([]string, len(articles))
+		for i, article := range articles {
+			result[i] = article.Title
+		}
+		return result
+	}
+
+	// Wildcards in the prefix are matched literally, at the start of the title or of a word
+	assert.Equal(t, []string{"100% Go"}, titles("100%"))
+	assert.Equal(t, []string{"snake_case names"}, titles("snake_"))
+	assert.Equal(t, []string{`C:\ paths`}, titles(`C:\`))
+	assert.Empty(t, titles("%"))
+	assert.ElementsMatch(t, []string{"snake_case names", "Naming in snakeXcase"}, titles("snake"))
+}
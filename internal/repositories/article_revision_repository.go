@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type articleRevisionRepository struct {
+	*Repository[models.ArticleRevision]
+}
+
+// NewArticleRevisionRepository creates a new article revision repository
+func NewArticleRevisionRepository(db *database.DB) ArticleRevisionRepository {
+	return &articleRevisionRepository{
+		Repository: NewRepository[models.ArticleRevision](db),
+	}
+}
+
+// Create numbers revision after the latest revision of its article. Concurrent
+// edits picking the same number are rejected by the unique index.
+func (r *articleRevisionRepository) Create(revision *models.ArticleRevision) error {
+	var latest uint
+	err := r.GetDB().GetDB().Model(&models.ArticleRevision{}).
+		Where("article_id = ?", revision.ArticleID).
+		Select("COALESCE(MAX(number), 0)").Scan(&latest).Error
+	if err != nil {
+		return err
+	}
+	revision.Number = latest + 1
+	return r.Repository.Create(revision)
+}
+
+func (r *articleRevisionRepository) CountByArticle(articleID uint) (int64, error) {
+	return r.Count("article_id = ?", articleID)
+}
+
+func (r *articleRevisionRepository) ListByArticle(articleID uint) ([]models.ArticleRevision, error) {
+	var revisions []models.ArticleRevision
+	err := r.GetDB().GetDB().Omit("content").
+		Where("article_id = ?", articleID).
+		Order("number DESC").Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *articleRevisionRepository) GetByNumber(articleID, number uint) (*models.ArticleRevision, error) {
+	var revision models.ArticleRevision
+	err := r.GetDB().GetDB().Where("article_id = ? AND number = ?", articleID, number).First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
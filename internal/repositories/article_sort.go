@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// articleSortColumns whitelists the fields article lists may be sorted by
+var articleSortColumns = map[string]string{
+	"created_at":    "created_at",
+	"published_at":  "published_at",
+	"view_count":    "view_count",
+	"like_count":    "like_count",
+	"comment_count": "comment_count",
+	"title":         "title",
+}
+
+// ArticleSort describes the ordering of an article list
+type ArticleSort struct {
+	Field string
+	Desc  bool
+}
+
+// DefaultArticleSort lists the newest articles first
+var DefaultArticleSort = ArticleSort{Field: "created_at", Desc: true}
+
+// ParseArticleSort parses a sort expression such as "view_count" or "-view_count".
+// A leading "-" sorts descending; an empty expression yields DefaultArticleSort.
+func ParseArticleSort(expr string) (ArticleSort, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return DefaultArticleSort, nil
+	}
+
+	sortBy := ArticleSort{Field: expr}
+	if strings.HasPrefix(expr, "-") {
+		sortBy = ArticleSort{Field: expr[1:], Desc: true}
+	}
+
+	if _, ok := articleSortColumns[sortBy.Field]; !ok {
+		return ArticleSort{}, fmt.Errorf("invalid sort field '%s', must be one of: %s",
+			sortBy.Field, strings.Join(ArticleSortFields(), ", "))
+	}
+	return sortBy, nil
+}
+
+// ArticleSortFields returns the sortable article fields in alphabetical order
+func ArticleSortFields() []string {
+	fields := make([]string, 0, len(articleSortColumns))
+	for field := range articleSortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// String returns the sort in its expression form, e.g. "-view_count"
+func (s ArticleSort) String() string {
+	if s.Desc {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+// orderClause returns the ORDER BY clause for the sort, using the article ID
+// as a tiebreaker so pagination stays stable across equal values
+func (s ArticleSort) orderClause() string {
+	column, ok := articleSortColumns[s.Field]
+	if !ok {
+		return DefaultArticleSort.orderClause()
+	}
+
+	direction := "ASC"
+	if s.Desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("articles.%s %s, articles.id %s", column, direction, direction)
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TrafficDimension is a column article views are grouped by
+type TrafficDimension string
+
+const (
+	TrafficReferrer    TrafficDimension = "referrer"
+	TrafficUTMSource   TrafficDimension = "utm_source"
+	TrafficUTMMedium   TrafficDimension = "utm_medium"
+	TrafficUTMCampaign TrafficDimension = "utm_campaign"
+)
+
+type articleViewRepository struct {
+	*Repository[models.ArticleView]
+}
+
+// NewArticleViewRepository creates a new article view repository
+func NewArticleViewRepository(db *database.DB) ArticleViewRepository {
+	return &articleViewRepository{
+		Repository: NewRepository[models.ArticleView](db),
+	}
+}
+
+// Record counts the view on its article and stores it
+func (r *articleViewRepository) Record(view *models.ArticleView) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		db := tx.GetDB()
+		result := db.Model(&models.Article{}).Where("id = ?", view.ArticleID).
+			UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return db.Create(view).Error
+	})
+}
+
+// CountByAuthor groups the views of an author's articles since the given time
+// by dimension, most views first
+func (r *articleViewRepository) CountByAuthor(authorID uint, dimension TrafficDimension, since time.Time, limit int) ([]models.TrafficCount, error) {
+	switch dimension {
+	case TrafficReferrer, TrafficUTMSource, TrafficUTMMedium, TrafficUTMCampaign:
+	default:
+		return nil, fmt.Errorf("unknown traffic dimension %q", dimension)
+	}
+
+	var counts []models.TrafficCount
+	err := r.GetDB().GetDB().Model(&models.ArticleView{}).
+		Select(fmt.Sprintf("article_views.%s AS value, COUNT(*) AS views", dimension)).
+		Joins("JOIN articles ON articles.id = article_views.article_id").
+		Where("articles.author_id = ? AND article_views.created_at >= ?", authorID, since).
+		Group("value").
+		Order("views DESC, value").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type authorReportRepository struct {
+	*Repository[models.AuthorReportDelivery]
+}
+
+// NewAuthorReportRepository creates a new author report repository
+func NewAuthorReportRepository(db *database.DB) AuthorReportRepository {
+	return &authorReportRepository{
+		Repository: NewRepository[models.AuthorReportDelivery](db),
+	}
+}
+
+// AuthorIDs returns the users with at least one published article, leaving out
+// system accounts
+func (r *authorReportRepository) AuthorIDs() ([]uint, error) {
+	var ids []uint
+	err := r.GetDB().GetDB().Model(&models.User{}).
+		Where("users.role <> ?", models.RoleSystem).
+		Where("EXISTS (?)", r.GetDB().GetDB().Model(&models.Article{}).Select("1").
+			Where("articles.author_id = users.id AND articles.status = ?", models.StatusPublished)).
+		Order("users.id").
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
+
+// Activity counts what readers other than the author did on the author's
+// articles between from and to
+func (r *authorReportRepository) Activity(authorID uint, from, to time.Time) (*models.AuthorActivity, error) {
+	db := r.GetDB().GetDB()
+	onArticles := func(query *gorm.DB, table string) *gorm.DB {
+		return query.Joins("JOIN articles ON articles.id = "+table+".article_id AND articles.deleted_at IS NULL").
+			Where("articles.author_id = ?", authorID).
+			Where(table+".created_at >= ? AND "+table+".created_at < ?", from, to)
+	}
+
+	activity := &models.AuthorActivity{}
+	if err := onArticles(db.Model(&models.ArticleView{}), "article_views").
+		Count(&activity.Views).Error; err != nil {
+		return nil, err
+	}
+	if err := onArticles(db.Model(&models.Like{}), "likes").
+		Where("likes.user_id <> ?", authorID).
+		Count(&activity.Likes).Error; err != nil {
+		return nil, err
+	}
+	if err := onArticles(db.Model(&models.Comment{}), "comments").
+		Where("comments.user_id <> ? AND comments.hidden = ? AND comments.tombstone = ?", authorID, false, false).
+		Count(&activity.Comments).Error; err != nil {
+		return nil, err
+	}
+	if err := onArticles(db.Model(&models.CommentSubscription{}), "comment_subscriptions").
+		Where("comment_subscriptions.user_id <> ?", authorID).
+		Distinct("comment_subscriptions.user_id").
+		Count(&activity.NewFollowers).Error; err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// TopArticles returns the author's articles with the most views between from
+// and to, most views first
+func (r *authorReportRepository) TopArticles(authorID uint, from, to time.Time, limit int) ([]models.ArticleActivity, error) {
+	var articles []models.ArticleActivity
+	err := r.GetDB().GetDB().Model(&models.ArticleView{}).
+		Select("articles.id AS article_id, articles.title, articles.slug, COUNT(*) AS views").
+		Joins("JOIN articles ON articles.id = article_views.article_id AND articles.deleted_at IS NULL").
+		Where("articles.author_id = ?", authorID).
+		Where("article_views.created_at >= ? AND article_views.created_at < ?", from, to).
+		Group("articles.id, articles.title, articles.slug").
+		Order("views DESC, articles.id").
+		Limit(limit).
+		Scan(&articles).Error
+	return articles, err
+}
+
+// MarkSent records that the user was sent the report of month, returning false
+// when it already was
+func (r *authorReportRepository) MarkSent(userID uint, month string) (bool, error) {
+	err := r.Create(&models.AuthorReportDelivery{UserID: userID, Month: month})
+	if errors.Is(err, ErrDuplicate) {
+		return false, nil
+	}
+	return err == nil, err
+}
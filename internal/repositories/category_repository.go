@@ -6,61 +6,44 @@ import (
 )
 
 type categoryRepository struct {
-	*BaseRepository
+	*Repository[models.Category]
 }
 
 // NewCategoryRepository creates a new category repository
 func NewCategoryRepository(db *database.DB) CategoryRepository {
 	return &categoryRepository{
-		BaseRepository: NewBaseRepository(db),
+		Repository: NewRepository[models.Category](db),
 	}
 }
 
-func (r *categoryRepository) Create(category *models.Category) error {
-	return r.BaseRepository.Create(category)
+func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
+	return r.Get(id)
 }
 
-func (r *categoryRepository) GetByID(id uint) (*models.Category, error) {
-	var category models.Category
-	err := r.BaseRepository.GetByID(&category, id)
-	if err != nil {
-		return nil, err
-	}
-	return &category, nil
+// Delete soft-deletes a category and frees its name and slug for new categories
+func (r *categoryRepository) Delete(id uint) error {
+	return r.DeleteReleasing(id, "name", "slug")
 }
 
 func (r *categoryRepository) GetBySlug(slug string) (*models.Category, error) {
-	var category models.Category
-	err := r.GetDB().GetByField(&category, "slug", slug)
-	if err != nil {
-		return nil, err
-	}
-	return &category, nil
+	return r.GetBy("slug", slug)
 }
 
 func (r *categoryRepository) List() ([]models.Category, error) {
-	var categories []models.Category
 	options := &database.QueryOptions{
 		Page:    1,
 		Limit:   1000, // Large limit for getting all categories
 		OrderBy: "name ASC",
 	}
 	
-	_, err := r.BaseRepository.List(&categories, options)
-	return categories, err
-}
-
-func (r *categoryRepository) Update(category *models.Category) error {
-	return r.BaseRepository.Update(category)
-}
-
-func (r *categoryRepository) Delete(id uint) error {
-	return r.BaseRepository.Delete(&models.Category{}, id)
+	result, err := r.Repository.List(options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
 }
 
 func (r *categoryRepository) GetArticles(categoryID uint, offset, limit int) ([]models.Article, int64, error) {
-	var articles []models.Article
-	
 	// Convert offset/limit to page-based pagination
 	page := (offset / limit) + 1
 	if page < 1 {
@@ -75,12 +58,13 @@ func (r *categoryRepository) GetArticles(categoryID uint, offset, limit int) ([]
 			"category_id": categoryID,
 		},
 		Preloads: []string{"Author", "Category", "Tags"},
+		Select:   articleSummaryColumns,
 	}
 	
-	result, err := r.BaseRepository.List(&articles, options)
+	result, err := database.ListOf[models.Article](r.GetDB(), options)
 	if err != nil {
 		return nil, 0, err
 	}
 	
-	return articles, result.Total, nil
+	return result.Items, result.Total, nil
 }
\ No newline at end of file
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type commentReportRepository struct {
+	*Repository[models.CommentReport]
+}
+
+// NewCommentReportRepository creates a new comment report repository
+func NewCommentReportRepository(db *database.DB) CommentReportRepository {
+	return &commentReportRepository{
+		Repository: NewRepository[models.CommentReport](db),
+	}
+}
+
+// Exists reports whether reporterID already reported the comment, resolved or not
+func (r *commentReportRepository) Exists(commentID, reporterID uint) (bool, error) {
+	return r.Repository.Exists("comment_id = ? AND reporter_id = ?", commentID, reporterID)
+}
+
+func (r *commentReportRepository) CountPending(commentID uint) (int64, error) {
+	return r.Count("comment_id = ? AND resolved_at IS NULL", commentID)
+}
+
+// ListQueue returns comments with pending reports, most reported first and then
+// most recently reported, leaving out comments that have been deleted
+func (r *commentReportRepository) ListQueue(offset, limit int) ([]ReportedComment, int64, error) {
+	base := r.GetDB().GetDB().Table("comment_reports").
+		Joins("JOIN comments ON comments.id = comment_reports.comment_id AND comments.deleted_at IS NULL").
+		Where("comment_reports.resolved_at IS NULL")
+
+	var total int64
+	if err := base.Session(&gorm.Session{}).Distinct("comment_reports.comment_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var queue []ReportedComment
+	err := base.
+		Select("comment_reports.comment_id, COUNT(*) AS report_count").
+		Group("comment_reports.comment_id").
+		Order("report_count DESC, MAX(comment_reports.id) DESC").
+		Offset(offset).Limit(limit).
+		Scan(&queue).Error
+	return queue, total, err
+}
+
+// ListPending returns the pending reports of the given comments, oldest first
+func (r *commentReportRepository) ListPending(commentIDs []uint) ([]models.CommentReport, error) {
+	var reports []models.CommentReport
+	if len(commentIDs) == 0 {
+		return reports, nil
+	}
+	err := r.GetDB().GetDB().Where("comment_id IN ? AND resolved_at IS NULL", commentIDs).
+		Order("created_at ASC, id ASC").Find(&reports).Error
+	return reports, err
+}
+
+// Resolve marks every pending report of a comment as resolved
+func (r *commentReportRepository) Resolve(commentID uint, resolvedAt time.Time) error {
+	return r.GetDB().GetDB().Model(&models.CommentReport{}).
+		Where("comment_id = ? AND resolved_at IS NULL", commentID).
+		UpdateColumn("resolved_at", resolvedAt).Error
+}
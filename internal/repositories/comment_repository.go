@@ -1,48 +1,235 @@
 package repositories
 
 import (
+	"errors"
+	"sort"
+	"time"
+
 	"go-blog/internal/database"
 	"go-blog/internal/models"
+
+	"gorm.io/gorm"
 )
 
+// CommentTreeDepth is how many levels of replies GetByArticle nests. Deeper
+// replies are listed in order under their ancestor on the last level.
+const CommentTreeDepth = 5
+
 type commentRepository struct {
-	*BaseRepository
+	*Repository[models.Comment]
 }
 
 // NewCommentRepository creates a new comment repository
 func NewCommentRepository(db *database.DB) CommentRepository {
 	return &commentRepository{
-		BaseRepository: NewBaseRepository(db),
+		Repository: NewRepository[models.Comment](db),
 	}
 }
 
-func (r *commentRepository) Create(comment *models.Comment) error {
-	return r.BaseRepository.Create(comment)
+func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
+	comment, err := r.Get(id, "User", "Replies")
+	if err != nil {
+		return nil, err
+	}
+	comment.ReplyCount = len(comment.Replies)
+	return comment, nil
 }
 
-func (r *commentRepository) GetByID(id uint) (*models.Comment, error) {
-	var comment models.Comment
-	err := r.BaseRepository.GetByID(&comment, id, "User", "Replies")
+// CreatedSince returns when the user wrote comments since since, oldest first.
+// Deleted comments count, so deleting them does not free up a quota.
+func (r *commentRepository) CreatedSince(userID uint, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.GetDB().GetDB().Unscoped().Model(&models.Comment{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Order("created_at").
+		Pluck("created_at", &times).Error
 	if err != nil {
 		return nil, err
 	}
-	return &comment, nil
+	return times, nil
 }
 
+// GetByArticle returns the visible comments of an article as a tree of
+// top-level comments and their nested replies, read in a single query. Replies
+// to hidden comments are left out with them.
 func (r *commentRepository) GetByArticle(articleID uint) ([]models.Comment, error) {
 	var comments []models.Comment
-	
-	// For complex queries with conditions, use the underlying GORM DB
-	err := r.GetDB().GetDB().Preload("User").Preload("Replies").
-		Where("article_id = ? AND parent_id IS NULL", articleID).
-		Order("created_at ASC").Find(&comments).Error
-	return comments, err
+	err := r.GetDB().GetDB().Preload("User").
+		Where("article_id = ? AND hidden = ?", articleID, false).
+		Order("created_at ASC, id ASC").Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	return buildCommentTree(comments, CommentTreeDepth), nil
 }
 
-func (r *commentRepository) Update(comment *models.Comment) error {
-	return r.BaseRepository.Update(comment)
+// buildCommentTree nests comments, ordered by creation, under their parents
+// down to depth levels of replies. Comments whose parent is not in comments
+// are dropped.
+func buildCommentTree(comments []models.Comment, depth int) []models.Comment {
+	var roots []int
+	children := make(map[uint][]int)
+	for i, comment := range comments {
+		if comment.ParentID == nil {
+			roots = append(roots, i)
+		} else {
+			children[*comment.ParentID] = append(children[*comment.ParentID], i)
+		}
+	}
+
+	// descendants collects the indexes of every reply below the comment at i
+	var descendants func(i int, into []int) []int
+	descendants = func(i int, into []int) []int {
+		for _, j := range children[comments[i].ID] {
+			into = descendants(j, append(into, j))
+		}
+		return into
+	}
+
+	var build func(i, level int) models.Comment
+	build = func(i, level int) models.Comment {
+		comment := comments[i]
+		comment.Replies = nil
+		if level == depth {
+			flattened := descendants(i, nil)
+			sort.Ints(flattened)
+			for _, j := range flattened {
+				reply := comments[j]
+				reply.Replies = nil
+				comment.Replies = append(comment.Replies, reply)
+			}
+			comment.ReplyCount = len(flattened)
+			return comment
+		}
+		for _, j := range children[comment.ID] {
+			reply := build(j, level+1)
+			comment.ReplyCount += 1 + reply.ReplyCount
+			comment.Replies = append(comment.Replies, reply)
+		}
+		return comment
+	}
+
+	tree := make([]models.Comment, 0, len(roots))
+	for _, i := range roots {
+		tree = append(tree, build(i, 0))
+	}
+	return tree
 }
 
+// SetHidden hides a comment from article listings or shows it again, taking it
+// out of or back into its article's comment counter
+func (r *commentRepository) SetHidden(id uint, hidden bool) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		db := tx.GetDB()
+		result := db.Model(&models.Comment{}).Where("id = ? AND hidden <> ?", id, hidden).
+			UpdateColumn("hidden", hidden)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var comment models.Comment
+		if err := db.Select("article_id", "tombstone").Where("id = ?", id).Take(&comment).Error; err != nil {
+			return err
+		}
+		if comment.Tombstone {
+			return nil
+		}
+		delta := 1
+		if hidden {
+			delta = -1
+		}
+		return adjustCommentCount(db, comment.ArticleID, delta)
+	})
+}
+
+// Delete removes a comment. A comment that still has replies is kept in the
+// thread as a tombstone without its content; removing the last reply of a
+// tombstone removes the tombstone as well. A counted comment is taken out of
+// its article's comment counter.
 func (r *commentRepository) Delete(id uint) error {
-	return r.BaseRepository.Delete(&models.Comment{}, id)
-}
\ No newline at end of file
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		var comment models.Comment
+		err := tx.GetDB().Select("article_id", "hidden", "tombstone").Where("id = ?", id).Take(&comment).Error
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if _, err := removeComments(tx, "id = ?", id); err != nil {
+			return err
+		}
+		if comment.Hidden || comment.Tombstone {
+			return nil
+		}
+		return adjustCommentCount(tx.GetDB(), comment.ArticleID, -1)
+	})
+}
+
+// removeComments soft-deletes the comments matching query, turning those that
+// still have live replies into tombstones and deleting tombstones left without
+// replies, and returns how many comments matched. It must run in a transaction.
+func removeComments(tx *database.DB, query interface{}, args ...interface{}) (int64, error) {
+	db := tx.GetDB()
+
+	var comments []models.Comment
+	if err := db.Select("id", "article_id").Where(query, args...).Find(&comments).Error; err != nil {
+		return 0, err
+	}
+	if len(comments) == 0 {
+		return 0, nil
+	}
+	ids := make([]uint, len(comments))
+	articleIDs := make([]uint, 0, len(comments))
+	seen := make(map[uint]bool, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+		if !seen[comment.ArticleID] {
+			seen[comment.ArticleID] = true
+			articleIDs = append(articleIDs, comment.ArticleID)
+		}
+	}
+
+	if err := db.Where("id IN ?", ids).Delete(&models.Comment{}).Error; err != nil {
+		return 0, err
+	}
+	if err := db.Where("comment_id IN ?", ids).Delete(&models.Mention{}).Error; err != nil {
+		return 0, err
+	}
+
+	// Restoring a tombstone can give its own parent a live reply again, so this
+	// repeats once per level of the thread
+	for {
+		res := db.Unscoped().Model(&models.Comment{}).
+			Where("id IN ? AND deleted_at IS NOT NULL AND id IN (?)", ids, answeredComments(db)).
+			UpdateColumns(map[string]interface{}{"deleted_at": nil, "tombstone": true, "content": ""})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if res.RowsAffected == 0 {
+			break
+		}
+	}
+
+	// Likewise deleting a tombstone can leave its parent tombstone without replies
+	for {
+		res := db.Where("article_id IN ? AND tombstone = ? AND id NOT IN (?)", articleIDs, true, answeredComments(db)).
+			Delete(&models.Comment{})
+		if res.Error != nil {
+			return 0, res.Error
+		}
+		if res.RowsAffected == 0 {
+			break
+		}
+	}
+	return int64(len(ids)), nil
+}
+
+// answeredComments selects the IDs of comments with live replies. The derived
+// table lets MySQL update the comments table the subquery reads.
+func answeredComments(db *gorm.DB) *gorm.DB {
+	replies := db.Session(&gorm.Session{NewDB: true}).Model(&models.Comment{}).
+		Select("parent_id").Where("parent_id IS NOT NULL")
+	return db.Session(&gorm.Session{NewDB: true}).Table("(?) AS replies", replies).Select("parent_id")
+}
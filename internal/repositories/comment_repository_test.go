@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"testing"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildCommentTree(t *testing.T) {
+	parent := func(id uint) *uint { return &id }
+	// 1 ─ 2 ─ 3 ─ 4, 1 ─ 5, 6, and 8 replying to the missing (hidden) 7
+	comments := []models.Comment{
+		{ID: 1},
+		{ID: 2, ParentID: parent(1)},
+		{ID: 3, ParentID: parent(2)},
+		{ID: 4, ParentID: parent(3)},
+		{ID: 5, ParentID: parent(1)},
+		{ID: 6},
+		{ID: 8, ParentID: parent(7)},
+	}
+
+	tree := buildCommentTree(comments, 1)
+	require.Len(t, tree, 2)
+
+	first := tree[0]
+	assert.Equal(t, uint(1), first.ID)
+	assert.Equal(t, 4, first.ReplyCount)
+	require.Len(t, first.Replies, 2)
+
+	// Replies below the depth are flattened onto their ancestor in order
+	nested := first.Replies[0]
+	assert.Equal(t, uint(2), nested.ID)
+	assert.Equal(t, 2, nested.ReplyCount)
+	require.Len(t, nested.Replies, 2)
+	assert.Equal(t, uint(3), nested.Replies[0].ID)
+	assert.Equal(t, uint(4), nested.Replies[1].ID)
+	assert.Empty(t, nested.Replies[0].Replies)
+
+	assert.Equal(t, uint(5), first.Replies[1].ID)
+	assert.Equal(t, 0, first.Replies[1].ReplyCount)
+	assert.Equal(t, uint(6), tree[1].ID)
+	assert.Empty(t, tree[1].Replies)
+}
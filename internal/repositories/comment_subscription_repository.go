@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type commentSubscriptionRepository struct {
+	*Repository[models.CommentSubscription]
+}
+
+// NewCommentSubscriptionRepository creates a new comment subscription repository
+func NewCommentSubscriptionRepository(db *database.DB) CommentSubscriptionRepository {
+	return &commentSubscriptionRepository{
+		Repository: NewRepository[models.CommentSubscription](db),
+	}
+}
+
+func (r *commentSubscriptionRepository) Get(userID, articleID uint) (*models.CommentSubscription, error) {
+	var subscription models.CommentSubscription
+	err := r.GetDB().GetDB().
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *commentSubscriptionRepository) GetByToken(token string) (*models.CommentSubscription, error) {
+	var subscription models.CommentSubscription
+	if err := r.GetDB().GetDB().Where("unsubscribe_token = ?", token).First(&subscription).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ListByArticle returns the subscriptions to an article's comments, leaving out
+// deleted users
+func (r *commentSubscriptionRepository) ListByArticle(articleID uint) ([]models.CommentSubscription, error) {
+	var subscriptions []models.CommentSubscription
+	err := r.GetDB().GetDB().
+		Joins("JOIN users ON users.id = comment_subscriptions.user_id AND users.deleted_at IS NULL").
+		Where("comment_subscriptions.article_id = ?", articleID).
+		Order("comment_subscriptions.id ASC").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Delete removes a user's subscription, returning ErrNotFound when
+// there is none
+func (r *commentSubscriptionRepository) Delete(userID, articleID uint) error {
+	result := r.GetDB().GetDB().
+		Where("user_id = ? AND article_id = ?", userID, articleID).
+		Delete(&models.CommentSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type contentFilterRuleRepository struct {
+	*Repository[models.ContentFilterRule]
+}
+
+// NewContentFilterRuleRepository creates a new content filter rule repository
+func NewContentFilterRuleRepository(db *database.DB) ContentFilterRuleRepository {
+	return &contentFilterRuleRepository{
+		Repository: NewRepository[models.ContentFilterRule](db),
+	}
+}
+
+func (r *contentFilterRuleRepository) GetByID(id uint) (*models.ContentFilterRule, error) {
+	return r.Get(id)
+}
+
+// List returns every rule, oldest first
+func (r *contentFilterRuleRepository) List() ([]models.ContentFilterRule, error) {
+	var rules []models.ContentFilterRule
+	err := r.GetDB().GetDB().Order("id").Find(&rules).Error
+	return rules, err
+}
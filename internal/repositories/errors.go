@@ -0,0 +1,13 @@
+package repositories
+
+import "go-blog/internal/database"
+
+// Repositories report missing and conflicting records with these errors
+// whatever stores them, so their callers never inspect storage errors.
+var (
+	// ErrNotFound is returned when a requested record does not exist
+	ErrNotFound = database.ErrNotFound
+	// ErrDuplicate is returned when a write conflicts with an existing record
+	// on a unique column
+	ErrDuplicate = database.ErrDuplicate
+)
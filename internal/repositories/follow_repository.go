@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type followRepository struct {
+	*Repository[models.Follow]
+}
+
+// NewFollowRepository creates a new follow repository
+func NewFollowRepository(db *database.DB) FollowRepository {
+	return &followRepository{
+		Repository: NewRepository[models.Follow](db),
+	}
+}
+
+// Create stores the follow and bumps the target's follower_count in one transaction
+func (r *followRepository) Create(follow *models.Follow) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		if err := tx.Create(follow); err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE "+follow.TargetType.TableName()+" SET follower_count = follower_count + 1 WHERE id = ?", follow.TargetID)
+	})
+}
+
+// Delete removes the follow and decrements the target's follower_count in one transaction
+func (r *followRepository) Delete(userID uint, targetType models.FollowTargetType, targetID uint) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		result := tx.GetDB().
+			Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+			Delete(&models.Follow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return tx.Exec("UPDATE "+targetType.TableName()+" SET follower_count = follower_count - 1 WHERE id = ? AND follower_count > 0", targetID)
+	})
+}
+
+func (r *followRepository) Get(userID uint, targetType models.FollowTargetType, targetID uint) (*models.Follow, error) {
+	var follow models.Follow
+	err := r.GetDB().GetDB().
+		Where("user_id = ? AND target_type = ? AND target_id = ?", userID, targetType, targetID).
+		First(&follow).Error
+	if err != nil {
+		return nil, err
+	}
+	return &follow, nil
+}
+
+func (r *followRepository) ListByUser(userID uint, targetType models.FollowTargetType) ([]models.Follow, error) {
+	var follows []models.Follow
+	query := r.GetDB().GetDB().Where("user_id = ?", userID)
+	if targetType != "" {
+		query = query.Where("target_type = ?", targetType)
+	}
+	err := query.Order("created_at DESC").Find(&follows).Error
+	return follows, err
+}
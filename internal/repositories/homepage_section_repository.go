@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type homepageSectionRepository struct {
+	*Repository[models.HomepageSection]
+}
+
+// NewHomepageSectionRepository creates a new homepage section repository
+func NewHomepageSectionRepository(db *database.DB) HomepageSectionRepository {
+	return &homepageSectionRepository{
+		Repository: NewRepository[models.HomepageSection](db),
+	}
+}
+
+// Create stores a section with the articles of its ArticleIDs
+func (r *homepageSectionRepository) Create(section *models.HomepageSection) error {
+	section.Items = sectionItems(0, section.ArticleIDs)
+	return r.GetDB().Create(section)
+}
+
+func (r *homepageSectionRepository) GetByID(id uint) (*models.HomepageSection, error) {
+	var section models.HomepageSection
+	if err := r.withItems().First(&section, id).Error; err != nil {
+		return nil, err
+	}
+	section.FillArticleIDs()
+	return &section, nil
+}
+
+// List returns every section in homepage order
+func (r *homepageSectionRepository) List() ([]models.HomepageSection, error) {
+	var sections []models.HomepageSection
+	if err := r.withItems().Order("position ASC, id ASC").Find(&sections).Error; err != nil {
+		return nil, err
+	}
+	for i := range sections {
+		sections[i].FillArticleIDs()
+	}
+	return sections, nil
+}
+
+// Update saves a section and replaces its articles with ArticleIDs in one transaction
+func (r *homepageSectionRepository) Update(section *models.HomepageSection) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		if err := tx.GetDB().Omit(clause.Associations).Save(section).Error; err != nil {
+			return err
+		}
+		if err := tx.GetDB().Where("section_id = ?", section.ID).Delete(&models.HomepageSectionArticle{}).Error; err != nil {
+			return err
+		}
+		section.Items = sectionItems(section.ID, section.ArticleIDs)
+		if len(section.Items) == 0 {
+			return nil
+		}
+		return tx.BulkCreate(&section.Items, len(section.Items))
+	})
+}
+
+// Delete removes a section with its articles
+func (r *homepageSectionRepository) Delete(id uint) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		if err := tx.GetDB().Where("section_id = ?", id).Delete(&models.HomepageSectionArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.HomepageSection{}, id)
+	})
+}
+
+// withItems loads the articles of manual sections in their order
+func (r *homepageSectionRepository) withItems() *gorm.DB {
+	return r.GetDB().GetDB().Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	})
+}
+
+// sectionItems places articleIDs in a section in their order
+func sectionItems(sectionID uint, articleIDs []uint) []models.HomepageSectionArticle {
+	items := make([]models.HomepageSectionArticle, len(articleIDs))
+	for i, articleID := range articleIDs {
+		items[i] = models.HomepageSectionArticle{SectionID: sectionID, ArticleID: articleID, Position: i}
+	}
+	return items
+}
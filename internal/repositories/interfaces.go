@@ -13,8 +13,37 @@ type UserRepository interface {
 	GetByID(id uint) (*models.User, error)
 	GetByEmail(email string) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
+	GetByHandle(handle string) (*models.User, error)
+	GetByEmailChangeToken(tokenHash string) (*models.User, error)
 	Update(user *models.User) error
 	Delete(id uint) error
+	Search(query string, offset, limit int) ([]models.User, int64, error)
+	DeleteAccount(deletion *AccountDeletion) (*AccountDeletionResult, error)
+}
+
+// AccountDeletion describes how DeleteAccount disposes of a user's content. A zero
+// owner ID deletes that kind of content instead of reassigning it.
+type AccountDeletion struct {
+	UserID         uint
+	ArticleOwnerID uint
+	CommentOwnerID uint
+	Audit          *models.AuditLog // stored in the same transaction
+}
+
+// ArticleTransfer describes articles moved to another author by Transfer: the
+// article ArticleID of FromAuthorID, or all of FromAuthorID's articles when
+// ArticleID is 0
+type ArticleTransfer struct {
+	ArticleID    uint
+	FromAuthorID uint
+	ToAuthorID   uint
+	Audit        *models.AuditLog // stored in the same transaction
+}
+
+// AccountDeletionResult counts the content affected by DeleteAccount
+type AccountDeletionResult struct {
+	Articles int64 `json:"articles"`
+	Comments int64 `json:"comments"`
 }
 
 // SearchFilters represents advanced search filters
@@ -25,6 +54,184 @@ type SearchFilters struct {
 	TagID      uint      `json:"tag_id,omitempty"`
 	DateFrom   time.Time `json:"date_from,omitempty"`
 	DateTo     time.Time `json:"date_to,omitempty"`
+	// PublishedAfter restricts results to articles published strictly after it
+	PublishedAfter time.Time `json:"published_after,omitempty"`
+	// Targets selects the text an article must match; empty means the article itself
+	Targets []SearchTarget `json:"targets,omitempty"`
+}
+
+// SearchTarget names a text index consulted by a search
+type SearchTarget string
+
+const (
+	SearchArticles SearchTarget = "articles" // article title, content and excerpt
+	SearchComments SearchTarget = "comments" // comment bodies, reported as their article
+	SearchUsers    SearchTarget = "users"    // usernames and bios
+)
+
+// TagMatchMode controls how multiple tag filters are combined
+type TagMatchMode string
+
+const (
+	TagMatchAny TagMatchMode = "any" // article has at least one of the tags
+	TagMatchAll TagMatchMode = "all" // article has every tag
+)
+
+// ArticleFilter represents typed filters for article listing.
+// Zero values leave the corresponding filter unapplied.
+type ArticleFilter struct {
+	Status        string
+	AuthorID      uint
+	CategoryIDs   []uint
+	TagIDs        []uint
+	TagMatch      TagMatchMode
+	PublishedFrom time.Time
+	PublishedTo   time.Time
+	MinViews      uint
+}
+
+// ArchiveEntry represents the number of published articles in a given month
+type ArchiveEntry struct {
+	Year  int   `json:"year"`
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// CalendarDay represents the number of published articles on a given day of a month
+type CalendarDay struct {
+	Day   int   `json:"day"`
+	Count int64 `json:"count"`
+}
+
+// AuthorTotals aggregates an author's published articles
+type AuthorTotals struct {
+	ArticleCount  int64 `json:"article_count"`
+	TotalViews    int64 `json:"total_views"`
+	TotalLikes    int64 `json:"total_likes"`
+	TotalComments int64 `json:"total_comments"`
+}
+
+// CommentCountDrift is an article whose stored comment counter differs from
+// the comments counted for it
+type CommentCountDrift struct {
+	ArticleID   uint   `json:"article_id"`
+	Title       string `json:"title"`
+	StoredCount uint   `json:"stored_count"`
+	ActualCount uint   `json:"actual_count"`
+}
+
+// AuthorRank is an author's place on the leaderboard with the totals of their
+// published articles
+type AuthorRank struct {
+	Rank          int64  `json:"rank" gorm:"column:author_rank"` // tied authors share a rank
+	AuthorID      uint   `json:"author_id"`
+	Username      string `json:"username"`
+	Handle        string `json:"handle"`
+	ArticleCount  int64  `json:"article_count"`
+	TotalViews    int64  `json:"total_views"`
+	TotalLikes    int64  `json:"total_likes"`
+	TotalComments int64  `json:"total_comments"`
+	Score         int64  `json:"score"` // views + 3 × likes + 5 × comments
+}
+
+// RefreshTokenRepository interface defines refresh token rotation data access methods
+type RefreshTokenRepository interface {
+	Create(token *models.RefreshToken) error
+	GetByTokenID(tokenID string) (*models.RefreshToken, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	RevokeFamily(familyID string, revokedAt time.Time) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// MagicLinkRepository interface defines emailed sign-in link data access methods
+type MagicLinkRepository interface {
+	Create(link *models.MagicLink) error
+	GetByTokenHash(tokenHash string) (*models.MagicLink, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	CountByUserSince(userID uint, since time.Time) (int64, error)
+	CountByIPSince(ip string, since time.Time) (int64, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// JobRunRepository interface defines scheduled job history data access methods
+type JobRunRepository interface {
+	Create(run *models.JobRun) error
+	ListByJob(job string, limit int) ([]models.JobRun, error)
+	GetLatest(job string) (*models.JobRun, error)
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// QueuedJobRepository interface defines background job queue data access methods
+type QueuedJobRepository interface {
+	Create(job *models.QueuedJob) error
+	GetByID(id uint) (*models.QueuedJob, error)
+	Claim(now, staleBefore time.Time) (*models.QueuedJob, error)
+	Complete(id uint, finishedAt time.Time) error
+	Reschedule(id uint, runAt time.Time, lastError string) error
+	Bury(id uint, finishedAt time.Time, lastError string) error
+	Requeue(id uint, runAt time.Time) (bool, error)
+	List(status models.QueuedJobStatus, offset, limit int) ([]models.QueuedJob, int64, error)
+	CountByStatus() (map[models.QueuedJobStatus]int64, error)
+	Delete(id uint) error
+	DeleteFinishedBefore(before time.Time) (int64, error)
+}
+
+// PageRepository interface defines static page data access methods
+type PageRepository interface {
+	Create(page *models.Page) error
+	GetByID(id uint) (*models.Page, error)
+	GetBySlug(slug string) (*models.Page, error)
+	List(publishedOnly bool) ([]models.Page, error)
+	ListNavigation() ([]models.Page, error)
+	Update(page *models.Page) error
+	Delete(id uint) error
+}
+
+// HomepageSectionRepository interface defines homepage section data access methods
+type HomepageSectionRepository interface {
+	Create(section *models.HomepageSection) error
+	GetByID(id uint) (*models.HomepageSection, error)
+	List() ([]models.HomepageSection, error)
+	Update(section *models.HomepageSection) error
+	Delete(id uint) error
+}
+
+// PolicyRepository interface defines policy document and acceptance data access methods
+type PolicyRepository interface {
+	Create(policy *models.Policy) error
+	GetByID(id uint) (*models.Policy, error)
+	// List returns every version of every policy, newest first
+	List() ([]models.Policy, error)
+	// Current returns the latest version of each kind of policy
+	Current() ([]models.Policy, error)
+	// Accept records acceptances, ignoring versions the user already accepted
+	Accept(acceptances []models.PolicyAcceptance) error
+	// AcceptedPolicyIDs returns which of policyIDs the user accepted
+	AcceptedPolicyIDs(userID uint, policyIDs []uint) ([]uint, error)
+	// UsersPendingAcceptance returns the users who have not accepted every one of
+	// policyIDs, oldest accounts first
+	UsersPendingAcceptance(policyIDs []uint, offset, limit int) ([]models.User, int64, error)
+}
+
+// ContentFilterRuleRepository interface defines content filter rule data access methods
+type ContentFilterRuleRepository interface {
+	Create(rule *models.ContentFilterRule) error
+	GetByID(id uint) (*models.ContentFilterRule, error)
+	List() ([]models.ContentFilterRule, error)
+	Update(rule *models.ContentFilterRule) error
+	Delete(id uint) error
+}
+
+// SessionRepository interface defines sign-in session data access methods
+type SessionRepository interface {
+	Create(session *models.Session) error
+	GetByID(id uint) (*models.Session, error)
+	GetByFamilyID(familyID string) (*models.Session, error)
+	ListActive(userID uint, now time.Time) ([]models.Session, error)
+	HasDevice(userID uint, device string) (bool, error)
+	CountByUser(userID uint) (int64, error)
+	Touch(familyID, ip string, usedAt, expiresAt time.Time) error
+	Revoke(familyID string, revokedAt time.Time) error
 }
 
 // ArticleRepository interface defines article data access methods
@@ -33,18 +240,119 @@ type ArticleRepository interface {
 	GetByID(id uint) (*models.Article, error)
 	GetBySlug(slug string) (*models.Article, error)
 	List(offset, limit int, filters map[string]interface{}) ([]models.Article, int64, error)
+	ListFiltered(offset, limit int, filter *ArticleFilter, sortBy ArticleSort) ([]models.Article, int64, error)
 	Update(article *models.Article) error
+	ReplaceTags(article *models.Article, tags []models.Tag) error
 	Delete(id uint) error
 	Search(query string, offset, limit int) ([]models.Article, int64, error)
 	AdvancedSearch(query string, offset, limit int, filters *SearchFilters) ([]models.Article, int64, error)
 	SearchWithBoolean(query string, offset, limit int, filters *SearchFilters) ([]models.Article, int64, error)
-	GetArchive() (map[string]interface{}, error)
+	GetArchive() ([]ArchiveEntry, error)
 	GetByMonth(year, month int, offset, limit int) ([]models.Article, int64, error)
+	GetByDay(year, month, day int, offset, limit int) ([]models.Article, int64, error)
+	GetCalendar(year, month int) ([]CalendarDay, error)
 	GetByAuthorID(authorID uint, limit, offset int) ([]*models.Article, error)
 	CountByAuthorID(authorID uint) (int64, error)
+	CreatedSince(authorID uint, since time.Time) ([]time.Time, error)
+	GetAuthorTotals(authorID uint) (*AuthorTotals, error)
+	GetAuthorLeaderboard(metric AuthorMetric, limit int) ([]AuthorRank, error)
 	IncrementViewCount(id uint) error
+	AdjustLikeCount(id uint, delta int) error
+	AdjustCommentCount(id uint, delta int) error
 	UpdateStatistics(id uint, viewCount, likeCount, commentCount uint) error
+	SetOGImage(id uint, key, url string) error
 	GetStatistics(id uint) (viewCount, likeCount, commentCount uint, err error)
+	RecountStatistics() (int64, error)
+	ReconcileCommentCounts() (int64, error)
+	CommentCountDrift(limit int) ([]CommentCountDrift, int64, error)
+	ArchiveExpired(now time.Time) (int64, error)
+	PurgeDeleted(before time.Time) (int64, error)
+	// Transfer moves articles to another author, returning how many moved
+	Transfer(transfer *ArticleTransfer) (int64, error)
+	ListByTaxonomies(categoryIDs, tagIDs []uint, offset, limit int) ([]models.Article, int64, error)
+	// PublishedTitles returns the titles of the most recently published articles
+	PublishedTitles(limit int) ([]string, error)
+	// SearchTitlePrefix returns published articles with a title word starting with prefix
+	SearchTitlePrefix(prefix string, limit int) ([]models.Article, error)
+}
+
+// ArticleRevisionRepository interface defines article revision data access methods
+type ArticleRevisionRepository interface {
+	// Create stores revision under the next number of its article
+	Create(revision *models.ArticleRevision) error
+	CountByArticle(articleID uint) (int64, error)
+	// ListByArticle returns the revisions of an article, newest first and without content
+	ListByArticle(articleID uint) ([]models.ArticleRevision, error)
+	GetByNumber(articleID, number uint) (*models.ArticleRevision, error)
+}
+
+// ArticleMetaRepository interface defines article custom field data access methods
+type ArticleMetaRepository interface {
+	ListByArticle(articleID uint) ([]models.ArticleMeta, error)
+	// Save sets fields on the article, replacing the values of existing keys
+	Save(articleID uint, fields []models.ArticleMeta) error
+	DeleteKey(articleID uint, key string) error
+}
+
+// ShortLinkRepository interface defines article short link data access methods
+type ShortLinkRepository interface {
+	Create(link *models.ShortLink) error
+	GetByCode(code string) (*models.ShortLink, error)
+	GetByArticle(articleID uint) (*models.ShortLink, error)
+	// RecordClick counts a click on the link and keeps its referrer
+	RecordClick(linkID uint, referrer string) error
+	// ReferrerCounts returns up to limit referrers of the link's clicks, most clicks first
+	ReferrerCounts(linkID uint, limit int) ([]models.ReferrerCount, error)
+}
+
+// ArticleViewRepository interface defines tracked article view data access methods
+type ArticleViewRepository interface {
+	// Record counts the view on its article and stores it
+	Record(view *models.ArticleView) error
+	// CountByAuthor groups the views of an author's articles since the given
+	// time by dimension, returning up to limit values with the most views first
+	CountByAuthor(authorID uint, dimension TrafficDimension, since time.Time, limit int) ([]models.TrafficCount, error)
+}
+
+// ArticleRankingRepository interface defines precomputed article ranking data access methods
+type ArticleRankingRepository interface {
+	// Refresh recomputes the rankings of every published article at now,
+	// decaying trending scores over trendingWindow, and returns how many
+	// articles are ranked
+	Refresh(now time.Time, trendingWindow time.Duration) (int64, error)
+	// Popular returns up to limit articles with the highest popularity scores
+	Popular(limit int) ([]RankedArticle, error)
+	// Trending returns up to limit articles published since the given time
+	// with the highest trending scores
+	Trending(limit int, since time.Time) ([]RankedArticle, error)
+	// Count returns the number of ranked articles
+	Count() (int64, error)
+}
+
+// ArticleLinkRepository interface defines checked article link data access methods
+type ArticleLinkRepository interface {
+	// PublishedAfter returns up to limit published articles with an ID above
+	// afterID, in ID order, for checking their links in batches
+	PublishedAfter(afterID uint, limit int) ([]models.Article, error)
+	// GetByArticle returns the links found in the article by its last check
+	GetByArticle(articleID uint) ([]models.ArticleLink, error)
+	// Replace swaps the links of the article for the ones of a new check
+	Replace(articleID uint, links []models.ArticleLink) error
+	// ListBroken returns a page of the broken links of published articles,
+	// longest broken first, and their total
+	ListBroken(offset, limit int) ([]BrokenLink, int64, error)
+}
+
+// AuthorReportRepository interface defines monthly author report data access methods
+type AuthorReportRepository interface {
+	// AuthorIDs returns the users with at least one published article
+	AuthorIDs() ([]uint, error)
+	// Activity counts what readers did on the author's articles between from and to
+	Activity(authorID uint, from, to time.Time) (*models.AuthorActivity, error)
+	// TopArticles returns the author's most viewed articles between from and to
+	TopArticles(authorID uint, from, to time.Time, limit int) ([]models.ArticleActivity, error)
+	// MarkSent records a sent report, returning false when it was already sent
+	MarkSent(userID uint, month string) (bool, error)
 }
 
 // CategoryRepository interface defines category data access methods
@@ -58,14 +366,37 @@ type CategoryRepository interface {
 	GetArticles(categoryID uint, offset, limit int) ([]models.Article, int64, error)
 }
 
+// TagCount represents a tag together with the number of articles using it
+type TagCount struct {
+	models.Tag
+	ArticleCount int64 `json:"article_count"`
+}
+
 // TagRepository interface defines tag data access methods
 type TagRepository interface {
 	Create(tag *models.Tag) error
 	GetByID(id uint) (*models.Tag, error)
 	GetBySlug(slug string) (*models.Tag, error)
 	GetByName(name string) (*models.Tag, error)
+	Update(tag *models.Tag) error
+	GetByNormalizedName(name string) (*models.Tag, error)
 	List() ([]models.Tag, error)
+	ListWithCounts(limit int) ([]TagCount, error)
+	Autocomplete(prefix string, limit int) ([]TagCount, error)
+	ListOrphans() ([]models.Tag, error)
 	GetArticles(tagID uint, offset, limit int) ([]models.Article, int64, error)
+	Delete(id uint) error
+}
+
+// TagAliasRepository interface defines tag alias data access methods
+type TagAliasRepository interface {
+	Create(alias *models.TagAlias) error
+	GetByID(id uint) (*models.TagAlias, error)
+	GetByAlias(alias string) (*models.TagAlias, error)
+	List() ([]models.TagAlias, error)
+	ListByTag(tagID uint) ([]models.TagAlias, error)
+	Delete(id uint) error
+	DeleteByTag(tagID uint) error
 }
 
 // CommentRepository interface defines comment data access methods
@@ -74,13 +405,86 @@ type CommentRepository interface {
 	GetByID(id uint) (*models.Comment, error)
 	GetByArticle(articleID uint) ([]models.Comment, error)
 	Update(comment *models.Comment) error
+	SetHidden(id uint, hidden bool) error
 	Delete(id uint) error
+	CreatedSince(userID uint, since time.Time) ([]time.Time, error)
+}
+
+// ReportedComment summarizes the pending reports of a comment in the review queue
+type ReportedComment struct {
+	CommentID   uint  `json:"comment_id"`
+	ReportCount int64 `json:"report_count"`
+}
+
+// CommentReportRepository interface defines comment report data access methods
+type CommentReportRepository interface {
+	Create(report *models.CommentReport) error
+	Exists(commentID, reporterID uint) (bool, error)
+	CountPending(commentID uint) (int64, error)
+	ListQueue(offset, limit int) ([]ReportedComment, int64, error)
+	ListPending(commentIDs []uint) ([]models.CommentReport, error)
+	Resolve(commentID uint, resolvedAt time.Time) error
+}
+
+// CommentSubscriptionRepository interface defines comment thread subscription data access methods
+type CommentSubscriptionRepository interface {
+	Create(subscription *models.CommentSubscription) error
+	Get(userID, articleID uint) (*models.CommentSubscription, error)
+	GetByToken(token string) (*models.CommentSubscription, error)
+	ListByArticle(articleID uint) ([]models.CommentSubscription, error)
+	Delete(userID, articleID uint) error
+}
+
+// MentionRepository interface defines comment mention data access methods
+type MentionRepository interface {
+	ListByComments(commentIDs []uint) ([]models.MentionRef, error)
+	Replace(commentID uint, userIDs []uint) error
 }
 
 // LikeRepository interface defines like data access methods
 type LikeRepository interface {
 	Create(like *models.Like) error
-	Delete(userID, articleID uint) error
+	Delete(userID, articleID uint) (bool, error)
 	GetByUserAndArticle(userID, articleID uint) (*models.Like, error)
+	// LikedArticleIDs returns which of articleIDs the user liked
+	LikedArticleIDs(userID uint, articleIDs []uint) ([]uint, error)
 	CountByArticle(articleID uint) (int64, error)
-}
\ No newline at end of file
+	CountByUser(userID uint) (int64, error)
+	// ListByUser returns the published articles the user liked, most recently liked first
+	ListByUser(userID uint, offset, limit int) ([]models.Article, int64, error)
+}
+
+// FollowRepository interface defines follow data access methods
+type FollowRepository interface {
+	Create(follow *models.Follow) error
+	Delete(userID uint, targetType models.FollowTargetType, targetID uint) error
+	Get(userID uint, targetType models.FollowTargetType, targetID uint) (*models.Follow, error)
+	ListByUser(userID uint, targetType models.FollowTargetType) ([]models.Follow, error)
+}
+
+// SavedSearchRepository interface defines saved search data access methods
+type SavedSearchRepository interface {
+	Create(search *models.SavedSearch) error
+	GetByID(id uint) (*models.SavedSearch, error)
+	ListByUser(userID uint) ([]models.SavedSearch, error)
+	CountByUser(userID uint) (int64, error)
+	ListAlerting() ([]models.SavedSearch, error)
+	Update(search *models.SavedSearch) error
+	MarkChecked(id uint, checkedAt time.Time) error
+	Delete(id uint) error
+}
+
+// NotificationRepository interface defines notification data access methods
+type NotificationRepository interface {
+	Create(notification *models.Notification) error
+	ListByUser(userID uint, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID, id uint, readAt time.Time) error
+	MarkAllRead(userID uint, readAt time.Time) error
+}
+
+// UserSettingsRepository interface defines user settings data access methods
+type UserSettingsRepository interface {
+	GetByUserID(userID uint) (*models.UserSettings, error)
+	Save(settings *models.UserSettings) error
+}
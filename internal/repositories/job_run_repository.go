@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type jobRunRepository struct {
+	*Repository[models.JobRun]
+}
+
+// NewJobRunRepository creates a new job run repository
+func NewJobRunRepository(db *database.DB) JobRunRepository {
+	return &jobRunRepository{
+		Repository: NewRepository[models.JobRun](db),
+	}
+}
+
+// ListByJob lists the most recent runs of a job, newest first
+func (r *jobRunRepository) ListByJob(job string, limit int) ([]models.JobRun, error) {
+	var runs []models.JobRun
+	err := r.GetDB().GetDB().
+		Where("job = ?", job).
+		Order("started_at DESC, id DESC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
+
+// GetLatest returns the most recent run of a job
+func (r *jobRunRepository) GetLatest(job string) (*models.JobRun, error) {
+	var run models.JobRun
+	err := r.GetDB().GetDB().
+		Where("job = ?", job).
+		Order("started_at DESC, id DESC").
+		First(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// DeleteBefore deletes runs started before the given time and returns how many were deleted
+func (r *jobRunRepository) DeleteBefore(before time.Time) (int64, error) {
+	result := r.GetDB().GetDB().Where("started_at < ?", before).Delete(&models.JobRun{})
+	return result.RowsAffected, result.Error
+}
@@ -6,22 +6,22 @@ import (
 )
 
 type likeRepository struct {
-	*BaseRepository
+	*Repository[models.Like]
 }
 
 // NewLikeRepository creates a new like repository
 func NewLikeRepository(db *database.DB) LikeRepository {
 	return &likeRepository{
-		BaseRepository: NewBaseRepository(db),
+		Repository: NewRepository[models.Like](db),
 	}
 }
 
-func (r *likeRepository) Create(like *models.Like) error {
-	return r.BaseRepository.Create(like)
-}
-
-func (r *likeRepository) Delete(userID, articleID uint) error {
-	return r.GetDB().BulkDelete(&models.Like{}, "user_id = ? AND article_id = ?", userID, articleID)
+// Delete permanently removes the user's like of an article, so the article can
+// be liked again under the unique index, and reports whether there was one
+func (r *likeRepository) Delete(userID, articleID uint) (bool, error) {
+	result := r.GetDB().GetDB().Unscoped().
+		Where("user_id = ? AND article_id = ?", userID, articleID).Delete(&models.Like{})
+	return result.RowsAffected > 0, result.Error
 }
 
 func (r *likeRepository) GetByUserAndArticle(userID, articleID uint) (*models.Like, error) {
@@ -35,6 +35,49 @@ func (r *likeRepository) GetByUserAndArticle(userID, articleID uint) (*models.Li
 	return &like, nil
 }
 
+// LikedArticleIDs returns which of articleIDs the user liked, in one query
+func (r *likeRepository) LikedArticleIDs(userID uint, articleIDs []uint) ([]uint, error) {
+	if len(articleIDs) == 0 {
+		return nil, nil
+	}
+	var liked []uint
+	err := r.GetDB().GetDB().Model(&models.Like{}).
+		Where("user_id = ? AND article_id IN ?", userID, articleIDs).
+		Pluck("article_id", &liked).Error
+	return liked, err
+}
+
 func (r *likeRepository) CountByArticle(articleID uint) (int64, error) {
-	return r.BaseRepository.Count(&models.Like{}, "article_id = ?", articleID)
-}
\ No newline at end of file
+	return r.Count("article_id = ?", articleID)
+}
+
+// CountByUser counts the likes the user has given
+func (r *likeRepository) CountByUser(userID uint) (int64, error) {
+	return r.Count("user_id = ?", userID)
+}
+
+// ListByUser returns the published articles the user liked, most recently
+// liked first, with their total
+func (r *likeRepository) ListByUser(userID uint, offset, limit int) ([]models.Article, int64, error) {
+	var articles []models.Article
+
+	query := notExpired(r.GetDB().GetDB().Model(&models.Article{})).
+		Joins("JOIN likes ON likes.article_id = articles.id AND likes.deleted_at IS NULL").
+		Where("likes.user_id = ? AND articles.status = ?", userID, models.StatusPublished)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Select(articleSummaryColumns).
+		Preload("Author").Preload("Category").Preload("Tags").
+		Order("likes.created_at DESC, likes.id DESC").
+		Offset(offset).Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
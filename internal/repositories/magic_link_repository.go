@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type magicLinkRepository struct {
+	*Repository[models.MagicLink]
+}
+
+// NewMagicLinkRepository creates a new magic link repository
+func NewMagicLinkRepository(db *database.DB) MagicLinkRepository {
+	return &magicLinkRepository{
+		Repository: NewRepository[models.MagicLink](db),
+	}
+}
+
+func (r *magicLinkRepository) GetByTokenHash(tokenHash string) (*models.MagicLink, error) {
+	return r.GetBy("token_hash", tokenHash)
+}
+
+// MarkUsed marks an unused link as used and reports whether it was unused. The
+// check and update are one statement, so a link signs in once.
+func (r *magicLinkRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.GetDB().GetDB().Model(&models.MagicLink{}).
+		Where("id = ? AND used_at IS NULL", id).
+		UpdateColumn("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountByUserSince counts the links requested for the user since the given time
+func (r *magicLinkRepository) CountByUserSince(userID uint, since time.Time) (int64, error) {
+	return r.Count("user_id = ? AND created_at >= ?", userID, since)
+}
+
+// CountByIPSince counts the links requested from ip since the given time
+func (r *magicLinkRepository) CountByIPSince(ip string, since time.Time) (int64, error) {
+	return r.Count("ip = ? AND created_at >= ?", ip, since)
+}
+
+// DeleteExpired deletes links that expired before the given time and returns how many were deleted
+func (r *magicLinkRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.GetDB().GetDB().Where("expires_at < ?", before).Delete(&models.MagicLink{})
+	return result.RowsAffected, result.Error
+}
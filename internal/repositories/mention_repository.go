@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type mentionRepository struct {
+	*Repository[models.Mention]
+}
+
+// NewMentionRepository creates a new mention repository
+func NewMentionRepository(db *database.DB) MentionRepository {
+	return &mentionRepository{
+		Repository: NewRepository[models.Mention](db),
+	}
+}
+
+// ListByComments returns the mentions of the given comments with the mentioned
+// users' current username and handle. Deleted users are left out.
+func (r *mentionRepository) ListByComments(commentIDs []uint) ([]models.MentionRef, error) {
+	var refs []models.MentionRef
+	if len(commentIDs) == 0 {
+		return refs, nil
+	}
+
+	err := r.GetDB().GetDB().Table("mentions").
+		Select("mentions.comment_id, users.id AS user_id, users.username, users.handle").
+		Joins("JOIN users ON users.id = mentions.user_id AND users.deleted_at IS NULL").
+		Where("mentions.comment_id IN ?", commentIDs).
+		Order("mentions.id ASC").
+		Scan(&refs).Error
+	return refs, err
+}
+
+// Replace sets the users mentioned by a comment in one transaction
+func (r *mentionRepository) Replace(commentID uint, userIDs []uint) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		if err := tx.GetDB().Where("comment_id = ?", commentID).Delete(&models.Mention{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		mentions := make([]models.Mention, 0, len(userIDs))
+		for _, userID := range userIDs {
+			mentions = append(mentions, models.Mention{CommentID: commentID, UserID: userID})
+		}
+		return tx.Create(&mentions)
+	})
+}
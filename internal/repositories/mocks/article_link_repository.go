@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ArticleLinkRepository is a mock implementation of repositories.ArticleLinkRepository
+type ArticleLinkRepository struct {
+	mock.Mock
+}
+
+func (m *ArticleLinkRepository) PublishedAfter(afterID uint, limit int) ([]models.Article, error) {
+	args := m.Called(afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Article), args.Error(1)
+}
+
+func (m *ArticleLinkRepository) GetByArticle(articleID uint) ([]models.ArticleLink, error) {
+	args := m.Called(articleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ArticleLink), args.Error(1)
+}
+
+func (m *ArticleLinkRepository) Replace(articleID uint, links []models.ArticleLink) error {
+	args := m.Called(articleID, links)
+	return args.Error(0)
+}
+
+func (m *ArticleLinkRepository) ListBroken(offset, limit int) ([]repositories.BrokenLink, int64, error) {
+	args := m.Called(offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]repositories.BrokenLink), args.Get(1).(int64), args.Error(2)
+}
@@ -0,0 +1,30 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ArticleMetaRepository is a mock implementation of repositories.ArticleMetaRepository
+type ArticleMetaRepository struct {
+	mock.Mock
+}
+
+func (m *ArticleMetaRepository) ListByArticle(articleID uint) ([]models.ArticleMeta, error) {
+	args := m.Called(articleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ArticleMeta), args.Error(1)
+}
+
+func (m *ArticleMetaRepository) Save(articleID uint, fields []models.ArticleMeta) error {
+	args := m.Called(articleID, fields)
+	return args.Error(0)
+}
+
+func (m *ArticleMetaRepository) DeleteKey(articleID uint, key string) error {
+	args := m.Called(articleID, key)
+	return args.Error(0)
+}
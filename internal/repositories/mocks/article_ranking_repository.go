@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/repositories"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ArticleRankingRepository is a mock implementation of repositories.ArticleRankingRepository
+type ArticleRankingRepository struct {
+	mock.Mock
+}
+
+func (m *ArticleRankingRepository) Refresh(now time.Time, trendingWindow time.Duration) (int64, error) {
+	args := m.Called(now, trendingWindow)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRankingRepository) Popular(limit int) ([]repositories.RankedArticle, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repositories.RankedArticle), args.Error(1)
+}
+
+func (m *ArticleRankingRepository) Trending(limit int, since time.Time) ([]repositories.RankedArticle, error) {
+	args := m.Called(limit, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]repositories.RankedArticle), args.Error(1)
+}
+
+func (m *ArticleRankingRepository) Count() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
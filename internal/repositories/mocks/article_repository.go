@@ -1,7 +1,10 @@
 package mocks
 
 import (
+	"time"
+
 	"go-blog/internal/models"
+	"go-blog/internal/repositories"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -37,11 +40,21 @@ func (m *ArticleRepository) List(offset, limit int, filters map[string]interface
 	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *ArticleRepository) ListFiltered(offset, limit int, filter *repositories.ArticleFilter, sortBy repositories.ArticleSort) ([]models.Article, int64, error) {
+	args := m.Called(offset, limit, filter, sortBy)
+	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *ArticleRepository) Update(article *models.Article) error {
 	args := m.Called(article)
 	return args.Error(0)
 }
 
+func (m *ArticleRepository) ReplaceTags(article *models.Article, tags []models.Tag) error {
+	args := m.Called(article, tags)
+	return args.Error(0)
+}
+
 func (m *ArticleRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
@@ -52,9 +65,19 @@ func (m *ArticleRepository) Search(query string, offset, limit int) ([]models.Ar
 	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *ArticleRepository) GetArchive() (map[string]interface{}, error) {
+func (m *ArticleRepository) AdvancedSearch(query string, offset, limit int, filters *repositories.SearchFilters) ([]models.Article, int64, error) {
+	args := m.Called(query, offset, limit, filters)
+	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *ArticleRepository) SearchWithBoolean(query string, offset, limit int, filters *repositories.SearchFilters) ([]models.Article, int64, error) {
+	args := m.Called(query, offset, limit, filters)
+	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *ArticleRepository) GetArchive() ([]repositories.ArchiveEntry, error) {
 	args := m.Called()
-	return args.Get(0).(map[string]interface{}), args.Error(1)
+	return args.Get(0).([]repositories.ArchiveEntry), args.Error(1)
 }
 
 func (m *ArticleRepository) GetByMonth(year, month int, offset, limit int) ([]models.Article, int64, error) {
@@ -62,6 +85,16 @@ func (m *ArticleRepository) GetByMonth(year, month int, offset, limit int) ([]mo
 	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *ArticleRepository) GetByDay(year, month, day int, offset, limit int) ([]models.Article, int64, error) {
+	args := m.Called(year, month, day, offset, limit)
+	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *ArticleRepository) GetCalendar(year, month int) ([]repositories.CalendarDay, error) {
+	args := m.Called(year, month)
+	return args.Get(0).([]repositories.CalendarDay), args.Error(1)
+}
+
 func (m *ArticleRepository) GetByAuthorID(authorID uint, limit, offset int) ([]*models.Article, error) {
 	args := m.Called(authorID, limit, offset)
 	if args.Get(0) == nil {
@@ -75,11 +108,65 @@ func (m *ArticleRepository) CountByAuthorID(authorID uint) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *ArticleRepository) Transfer(transfer *repositories.ArticleTransfer) (int64, error) {
+	args := m.Called(transfer)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRepository) CreatedSince(authorID uint, since time.Time) ([]time.Time, error) {
+	args := m.Called(authorID, since)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *ArticleRepository) GetAuthorLeaderboard(metric repositories.AuthorMetric, limit int) ([]repositories.AuthorRank, error) {
+	args := m.Called(metric, limit)
+	return args.Get(0).([]repositories.AuthorRank), args.Error(1)
+}
+
+func (m *ArticleRepository) GetAuthorTotals(authorID uint) (*repositories.AuthorTotals, error) {
+	args := m.Called(authorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.AuthorTotals), args.Error(1)
+}
+
 func (m *ArticleRepository) IncrementViewCount(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *ArticleRepository) AdjustLikeCount(id uint, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
+func (m *ArticleRepository) AdjustCommentCount(id uint, delta int) error {
+	args := m.Called(id, delta)
+	return args.Error(0)
+}
+
+func (m *ArticleRepository) PublishedTitles(limit int) ([]string, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *ArticleRepository) SearchTitlePrefix(prefix string, limit int) ([]models.Article, error) {
+	args := m.Called(prefix, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Article), args.Error(1)
+}
+
+func (m *ArticleRepository) SetOGImage(id uint, key, url string) error {
+	args := m.Called(id, key, url)
+	return args.Error(0)
+}
+
 func (m *ArticleRepository) UpdateStatistics(id uint, viewCount, likeCount, commentCount uint) error {
 	args := m.Called(id, viewCount, likeCount, commentCount)
 	return args.Error(0)
@@ -88,4 +175,37 @@ func (m *ArticleRepository) UpdateStatistics(id uint, viewCount, likeCount, comm
 func (m *ArticleRepository) GetStatistics(id uint) (viewCount, likeCount, commentCount uint, err error) {
 	args := m.Called(id)
 	return args.Get(0).(uint), args.Get(1).(uint), args.Get(2).(uint), args.Error(3)
+}
+
+func (m *ArticleRepository) RecountStatistics() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRepository) ReconcileCommentCounts() (int64, error) {
+	args := m.Called()
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRepository) CommentCountDrift(limit int) ([]repositories.CommentCountDrift, int64, error) {
+	args := m.Called(limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]repositories.CommentCountDrift), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *ArticleRepository) ArchiveExpired(now time.Time) (int64, error) {
+	args := m.Called(now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRepository) PurgeDeleted(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRepository) ListByTaxonomies(categoryIDs, tagIDs []uint, offset, limit int) ([]models.Article, int64, error) {
+	args := m.Called(categoryIDs, tagIDs, offset, limit)
+	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
 }
\ No newline at end of file
@@ -0,0 +1,38 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ArticleRevisionRepository is a mock implementation of repositories.ArticleRevisionRepository
+type ArticleRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *ArticleRevisionRepository) Create(revision *models.ArticleRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *ArticleRevisionRepository) CountByArticle(articleID uint) (int64, error) {
+	args := m.Called(articleID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *ArticleRevisionRepository) ListByArticle(articleID uint) ([]models.ArticleRevision, error) {
+	args := m.Called(articleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ArticleRevision), args.Error(1)
+}
+
+func (m *ArticleRevisionRepository) GetByNumber(articleID, number uint) (*models.ArticleRevision, error) {
+	args := m.Called(articleID, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ArticleRevision), args.Error(1)
+}
@@ -0,0 +1,28 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ArticleViewRepository is a mock implementation of repositories.ArticleViewRepository
+type ArticleViewRepository struct {
+	mock.Mock
+}
+
+func (m *ArticleViewRepository) Record(view *models.ArticleView) error {
+	args := m.Called(view)
+	return args.Error(0)
+}
+
+func (m *ArticleViewRepository) CountByAuthor(authorID uint, dimension repositories.TrafficDimension, since time.Time, limit int) ([]models.TrafficCount, error) {
+	args := m.Called(authorID, dimension, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TrafficCount), args.Error(1)
+}
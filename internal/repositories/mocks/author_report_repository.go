@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// AuthorReportRepository is a mock implementation of repositories.AuthorReportRepository
+type AuthorReportRepository struct {
+	mock.Mock
+}
+
+func (m *AuthorReportRepository) AuthorIDs() ([]uint, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *AuthorReportRepository) Activity(authorID uint, from, to time.Time) (*models.AuthorActivity, error) {
+	args := m.Called(authorID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AuthorActivity), args.Error(1)
+}
+
+func (m *AuthorReportRepository) TopArticles(authorID uint, from, to time.Time, limit int) ([]models.ArticleActivity, error) {
+	args := m.Called(authorID, from, to, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ArticleActivity), args.Error(1)
+}
+
+func (m *AuthorReportRepository) MarkSent(userID uint, month string) (bool, error) {
+	args := m.Called(userID, month)
+	return args.Bool(0), args.Error(1)
+}
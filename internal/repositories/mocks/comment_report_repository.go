@@ -0,0 +1,51 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// CommentReportRepository is a mock implementation of repositories.CommentReportRepository
+type CommentReportRepository struct {
+	mock.Mock
+}
+
+func (m *CommentReportRepository) Create(report *models.CommentReport) error {
+	args := m.Called(report)
+	return args.Error(0)
+}
+
+func (m *CommentReportRepository) Exists(commentID, reporterID uint) (bool, error) {
+	args := m.Called(commentID, reporterID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *CommentReportRepository) CountPending(commentID uint) (int64, error) {
+	args := m.Called(commentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *CommentReportRepository) ListQueue(offset, limit int) ([]repositories.ReportedComment, int64, error) {
+	args := m.Called(offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]repositories.ReportedComment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *CommentReportRepository) ListPending(commentIDs []uint) ([]models.CommentReport, error) {
+	args := m.Called(commentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CommentReport), args.Error(1)
+}
+
+func (m *CommentReportRepository) Resolve(commentID uint, resolvedAt time.Time) error {
+	args := m.Called(commentID, resolvedAt)
+	return args.Error(0)
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"go-blog/internal/models"
 
 	"github.com/stretchr/testify/mock"
@@ -34,7 +36,17 @@ func (m *CommentRepository) Update(comment *models.Comment) error {
 	return args.Error(0)
 }
 
+func (m *CommentRepository) SetHidden(id uint, hidden bool) error {
+	args := m.Called(id, hidden)
+	return args.Error(0)
+}
+
 func (m *CommentRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
+}
+
+func (m *CommentRepository) CreatedSince(userID uint, since time.Time) ([]time.Time, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).([]time.Time), args.Error(1)
 }
\ No newline at end of file
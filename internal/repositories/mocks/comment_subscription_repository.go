@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// CommentSubscriptionRepository is a mock implementation of repositories.CommentSubscriptionRepository
+type CommentSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *CommentSubscriptionRepository) Create(subscription *models.CommentSubscription) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *CommentSubscriptionRepository) Get(userID, articleID uint) (*models.CommentSubscription, error) {
+	args := m.Called(userID, articleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentSubscription), args.Error(1)
+}
+
+func (m *CommentSubscriptionRepository) GetByToken(token string) (*models.CommentSubscription, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CommentSubscription), args.Error(1)
+}
+
+func (m *CommentSubscriptionRepository) ListByArticle(articleID uint) ([]models.CommentSubscription, error) {
+	args := m.Called(articleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.CommentSubscription), args.Error(1)
+}
+
+func (m *CommentSubscriptionRepository) Delete(userID, articleID uint) error {
+	args := m.Called(userID, articleID)
+	return args.Error(0)
+}
@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ContentFilterRuleRepository is a mock implementation of repositories.ContentFilterRuleRepository
+type ContentFilterRuleRepository struct {
+	mock.Mock
+}
+
+func (m *ContentFilterRuleRepository) Create(rule *models.ContentFilterRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *ContentFilterRuleRepository) GetByID(id uint) (*models.ContentFilterRule, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ContentFilterRule), args.Error(1)
+}
+
+func (m *ContentFilterRuleRepository) List() ([]models.ContentFilterRule, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ContentFilterRule), args.Error(1)
+}
+
+func (m *ContentFilterRuleRepository) Update(rule *models.ContentFilterRule) error {
+	args := m.Called(rule)
+	return args.Error(0)
+}
+
+func (m *ContentFilterRuleRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,35 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// FollowRepository is a mock implementation of repositories.FollowRepository
+type FollowRepository struct {
+	mock.Mock
+}
+
+func (m *FollowRepository) Create(follow *models.Follow) error {
+	args := m.Called(follow)
+	return args.Error(0)
+}
+
+func (m *FollowRepository) Delete(userID uint, targetType models.FollowTargetType, targetID uint) error {
+	args := m.Called(userID, targetType, targetID)
+	return args.Error(0)
+}
+
+func (m *FollowRepository) Get(userID uint, targetType models.FollowTargetType, targetID uint) (*models.Follow, error) {
+	args := m.Called(userID, targetType, targetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Follow), args.Error(1)
+}
+
+func (m *FollowRepository) ListByUser(userID uint, targetType models.FollowTargetType) ([]models.Follow, error) {
+	args := m.Called(userID, targetType)
+	return args.Get(0).([]models.Follow), args.Error(1)
+}
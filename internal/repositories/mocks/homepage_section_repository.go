@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// HomepageSectionRepository is a mock implementation of repositories.HomepageSectionRepository
+type HomepageSectionRepository struct {
+	mock.Mock
+}
+
+func (m *HomepageSectionRepository) Create(section *models.HomepageSection) error {
+	args := m.Called(section)
+	return args.Error(0)
+}
+
+func (m *HomepageSectionRepository) GetByID(id uint) (*models.HomepageSection, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.HomepageSection), args.Error(1)
+}
+
+func (m *HomepageSectionRepository) List() ([]models.HomepageSection, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.HomepageSection), args.Error(1)
+}
+
+func (m *HomepageSectionRepository) Update(section *models.HomepageSection) error {
+	args := m.Called(section)
+	return args.Error(0)
+}
+
+func (m *HomepageSectionRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// JobRunRepository is a mock implementation of repositories.JobRunRepository
+type JobRunRepository struct {
+	mock.Mock
+}
+
+func (m *JobRunRepository) Create(run *models.JobRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *JobRunRepository) ListByJob(job string, limit int) ([]models.JobRun, error) {
+	args := m.Called(job, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.JobRun), args.Error(1)
+}
+
+func (m *JobRunRepository) GetLatest(job string) (*models.JobRun, error) {
+	args := m.Called(job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.JobRun), args.Error(1)
+}
+
+func (m *JobRunRepository) DeleteBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
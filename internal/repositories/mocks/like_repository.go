@@ -16,9 +16,9 @@ func (m *LikeRepository) Create(like *models.Like) error {
 	return args.Error(0)
 }
 
-func (m *LikeRepository) Delete(userID, articleID uint) error {
+func (m *LikeRepository) Delete(userID, articleID uint) (bool, error) {
 	args := m.Called(userID, articleID)
-	return args.Error(0)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *LikeRepository) GetByUserAndArticle(userID, articleID uint) (*models.Like, error) {
@@ -29,7 +29,25 @@ func (m *LikeRepository) GetByUserAndArticle(userID, articleID uint) (*models.Li
 	return args.Get(0).(*models.Like), args.Error(1)
 }
 
+func (m *LikeRepository) LikedArticleIDs(userID uint, articleIDs []uint) ([]uint, error) {
+	args := m.Called(userID, articleIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
 func (m *LikeRepository) CountByArticle(articleID uint) (int64, error) {
 	args := m.Called(articleID)
 	return args.Get(0).(int64), args.Error(1)
-}
\ No newline at end of file
+}
+
+func (m *LikeRepository) CountByUser(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *LikeRepository) ListByUser(userID uint, offset, limit int) ([]models.Article, int64, error) {
+	args := m.Called(userID, offset, limit)
+	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
+}
@@ -0,0 +1,47 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MagicLinkRepository is a mock implementation of repositories.MagicLinkRepository
+type MagicLinkRepository struct {
+	mock.Mock
+}
+
+func (m *MagicLinkRepository) Create(link *models.MagicLink) error {
+	args := m.Called(link)
+	return args.Error(0)
+}
+
+func (m *MagicLinkRepository) GetByTokenHash(tokenHash string) (*models.MagicLink, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MagicLink), args.Error(1)
+}
+
+func (m *MagicLinkRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MagicLinkRepository) CountByUserSince(userID uint, since time.Time) (int64, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MagicLinkRepository) CountByIPSince(ip string, since time.Time) (int64, error) {
+	args := m.Called(ip, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MagicLinkRepository) DeleteExpired(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MentionRepository is a mock implementation of repositories.MentionRepository
+type MentionRepository struct {
+	mock.Mock
+}
+
+func (m *MentionRepository) ListByComments(commentIDs []uint) ([]models.MentionRef, error) {
+	args := m.Called(commentIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MentionRef), args.Error(1)
+}
+
+func (m *MentionRepository) Replace(commentID uint, userIDs []uint) error {
+	args := m.Called(commentID, userIDs)
+	return args.Error(0)
+}
@@ -0,0 +1,39 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// NotificationRepository is a mock implementation of repositories.NotificationRepository
+type NotificationRepository struct {
+	mock.Mock
+}
+
+func (m *NotificationRepository) Create(notification *models.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *NotificationRepository) ListByUser(userID uint, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	args := m.Called(userID, unreadOnly, offset, limit)
+	return args.Get(0).([]models.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *NotificationRepository) CountUnread(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepository) MarkRead(userID, id uint, readAt time.Time) error {
+	args := m.Called(userID, id, readAt)
+	return args.Error(0)
+}
+
+func (m *NotificationRepository) MarkAllRead(userID uint, readAt time.Time) error {
+	args := m.Called(userID, readAt)
+	return args.Error(0)
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// PageRepository is a mock implementation of repositories.PageRepository
+type PageRepository struct {
+	mock.Mock
+}
+
+func (m *PageRepository) Create(page *models.Page) error {
+	args := m.Called(page)
+	return args.Error(0)
+}
+
+func (m *PageRepository) GetByID(id uint) (*models.Page, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page), args.Error(1)
+}
+
+func (m *PageRepository) GetBySlug(slug string) (*models.Page, error) {
+	args := m.Called(slug)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Page), args.Error(1)
+}
+
+func (m *PageRepository) List(publishedOnly bool) ([]models.Page, error) {
+	args := m.Called(publishedOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Page), args.Error(1)
+}
+
+func (m *PageRepository) ListNavigation() ([]models.Page, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Page), args.Error(1)
+}
+
+func (m *PageRepository) Update(page *models.Page) error {
+	args := m.Called(page)
+	return args.Error(0)
+}
+
+func (m *PageRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,59 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// PolicyRepository is a mock implementation of repositories.PolicyRepository
+type PolicyRepository struct {
+	mock.Mock
+}
+
+func (m *PolicyRepository) Create(policy *models.Policy) error {
+	args := m.Called(policy)
+	return args.Error(0)
+}
+
+func (m *PolicyRepository) GetByID(id uint) (*models.Policy, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Policy), args.Error(1)
+}
+
+func (m *PolicyRepository) List() ([]models.Policy, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Policy), args.Error(1)
+}
+
+func (m *PolicyRepository) Current() ([]models.Policy, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Policy), args.Error(1)
+}
+
+func (m *PolicyRepository) Accept(acceptances []models.PolicyAcceptance) error {
+	args := m.Called(acceptances)
+	return args.Error(0)
+}
+
+func (m *PolicyRepository) AcceptedPolicyIDs(userID uint, policyIDs []uint) ([]uint, error) {
+	args := m.Called(userID, policyIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uint), args.Error(1)
+}
+
+func (m *PolicyRepository) UsersPendingAcceptance(policyIDs []uint, offset, limit int) ([]models.User, int64, error) {
+	args := m.Called(policyIDs, offset, limit)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
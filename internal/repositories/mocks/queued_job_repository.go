@@ -0,0 +1,78 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// QueuedJobRepository is a mock implementation of repositories.QueuedJobRepository
+type QueuedJobRepository struct {
+	mock.Mock
+}
+
+func (m *QueuedJobRepository) Create(job *models.QueuedJob) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *QueuedJobRepository) GetByID(id uint) (*models.QueuedJob, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QueuedJob), args.Error(1)
+}
+
+func (m *QueuedJobRepository) Claim(now, staleBefore time.Time) (*models.QueuedJob, error) {
+	args := m.Called(now, staleBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.QueuedJob), args.Error(1)
+}
+
+func (m *QueuedJobRepository) Complete(id uint, finishedAt time.Time) error {
+	args := m.Called(id, finishedAt)
+	return args.Error(0)
+}
+
+func (m *QueuedJobRepository) Reschedule(id uint, runAt time.Time, lastError string) error {
+	args := m.Called(id, runAt, lastError)
+	return args.Error(0)
+}
+
+func (m *QueuedJobRepository) Bury(id uint, finishedAt time.Time, lastError string) error {
+	args := m.Called(id, finishedAt, lastError)
+	return args.Error(0)
+}
+
+func (m *QueuedJobRepository) Requeue(id uint, runAt time.Time) (bool, error) {
+	args := m.Called(id, runAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *QueuedJobRepository) List(status models.QueuedJobStatus, offset, limit int) ([]models.QueuedJob, int64, error) {
+	args := m.Called(status, offset, limit)
+	return args.Get(0).([]models.QueuedJob), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *QueuedJobRepository) CountByStatus() (map[models.QueuedJobStatus]int64, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[models.QueuedJobStatus]int64), args.Error(1)
+}
+
+func (m *QueuedJobRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *QueuedJobRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// RefreshTokenRepository is a mock implementation of repositories.RefreshTokenRepository
+type RefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *RefreshTokenRepository) Create(token *models.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *RefreshTokenRepository) GetByTokenID(tokenID string) (*models.RefreshToken, error) {
+	args := m.Called(tokenID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RefreshToken), args.Error(1)
+}
+
+func (m *RefreshTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	args := m.Called(id, usedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *RefreshTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *RefreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	args := m.Called(familyID, revokedAt)
+	return args.Error(0)
+}
@@ -0,0 +1,57 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// SavedSearchRepository is a mock implementation of repositories.SavedSearchRepository
+type SavedSearchRepository struct {
+	mock.Mock
+}
+
+func (m *SavedSearchRepository) Create(search *models.SavedSearch) error {
+	args := m.Called(search)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepository) GetByID(id uint) (*models.SavedSearch, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SavedSearch), args.Error(1)
+}
+
+func (m *SavedSearchRepository) ListByUser(userID uint) ([]models.SavedSearch, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *SavedSearchRepository) CountByUser(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *SavedSearchRepository) ListAlerting() ([]models.SavedSearch, error) {
+	args := m.Called()
+	return args.Get(0).([]models.SavedSearch), args.Error(1)
+}
+
+func (m *SavedSearchRepository) Update(search *models.SavedSearch) error {
+	args := m.Called(search)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepository) MarkChecked(id uint, checkedAt time.Time) error {
+	args := m.Called(id, checkedAt)
+	return args.Error(0)
+}
+
+func (m *SavedSearchRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
@@ -0,0 +1,63 @@
+package mocks
+
+import (
+	"time"
+
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// SessionRepository is a mock implementation of repositories.SessionRepository
+type SessionRepository struct {
+	mock.Mock
+}
+
+func (m *SessionRepository) Create(session *models.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *SessionRepository) GetByID(id uint) (*models.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *SessionRepository) GetByFamilyID(familyID string) (*models.Session, error) {
+	args := m.Called(familyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *SessionRepository) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	args := m.Called(userID, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *SessionRepository) HasDevice(userID uint, device string) (bool, error) {
+	args := m.Called(userID, device)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *SessionRepository) CountByUser(userID uint) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *SessionRepository) Touch(familyID, ip string, usedAt, expiresAt time.Time) error {
+	args := m.Called(familyID, ip, usedAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *SessionRepository) Revoke(familyID string, revokedAt time.Time) error {
+	args := m.Called(familyID, revokedAt)
+	return args.Error(0)
+}
@@ -0,0 +1,46 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// ShortLinkRepository is a mock implementation of repositories.ShortLinkRepository
+type ShortLinkRepository struct {
+	mock.Mock
+}
+
+func (m *ShortLinkRepository) Create(link *models.ShortLink) error {
+	args := m.Called(link)
+	return args.Error(0)
+}
+
+func (m *ShortLinkRepository) GetByCode(code string) (*models.ShortLink, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShortLink), args.Error(1)
+}
+
+func (m *ShortLinkRepository) GetByArticle(articleID uint) (*models.ShortLink, error) {
+	args := m.Called(articleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ShortLink), args.Error(1)
+}
+
+func (m *ShortLinkRepository) RecordClick(linkID uint, referrer string) error {
+	args := m.Called(linkID, referrer)
+	return args.Error(0)
+}
+
+func (m *ShortLinkRepository) ReferrerCounts(linkID uint, limit int) ([]models.ReferrerCount, error) {
+	args := m.Called(linkID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.ReferrerCount), args.Error(1)
+}
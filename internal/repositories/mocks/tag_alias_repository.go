@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// TagAliasRepository is a mock implementation of repositories.TagAliasRepository
+type TagAliasRepository struct {
+	mock.Mock
+}
+
+func (m *TagAliasRepository) Create(alias *models.TagAlias) error {
+	args := m.Called(alias)
+	return args.Error(0)
+}
+
+func (m *TagAliasRepository) GetByID(id uint) (*models.TagAlias, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TagAlias), args.Error(1)
+}
+
+func (m *TagAliasRepository) GetByAlias(alias string) (*models.TagAlias, error) {
+	args := m.Called(alias)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TagAlias), args.Error(1)
+}
+
+func (m *TagAliasRepository) List() ([]models.TagAlias, error) {
+	args := m.Called()
+	return args.Get(0).([]models.TagAlias), args.Error(1)
+}
+
+func (m *TagAliasRepository) ListByTag(tagID uint) ([]models.TagAlias, error) {
+	args := m.Called(tagID)
+	return args.Get(0).([]models.TagAlias), args.Error(1)
+}
+
+func (m *TagAliasRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *TagAliasRepository) DeleteByTag(tagID uint) error {
+	args := m.Called(tagID)
+	return args.Error(0)
+}
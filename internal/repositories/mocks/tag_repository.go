@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"go-blog/internal/models"
+	"go-blog/internal/repositories"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -40,12 +41,45 @@ func (m *TagRepository) GetByName(name string) (*models.Tag, error) {
 	return args.Get(0).(*models.Tag), args.Error(1)
 }
 
+func (m *TagRepository) GetByNormalizedName(name string) (*models.Tag, error) {
+	args := m.Called(name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Tag), args.Error(1)
+}
+
 func (m *TagRepository) List() ([]models.Tag, error) {
 	args := m.Called()
 	return args.Get(0).([]models.Tag), args.Error(1)
 }
 
+func (m *TagRepository) ListWithCounts(limit int) ([]repositories.TagCount, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]repositories.TagCount), args.Error(1)
+}
+
+func (m *TagRepository) Autocomplete(prefix string, limit int) ([]repositories.TagCount, error) {
+	args := m.Called(prefix, limit)
+	return args.Get(0).([]repositories.TagCount), args.Error(1)
+}
+
+func (m *TagRepository) ListOrphans() ([]models.Tag, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Tag), args.Error(1)
+}
+
 func (m *TagRepository) GetArticles(tagID uint, offset, limit int) ([]models.Article, int64, error) {
 	args := m.Called(tagID, offset, limit)
 	return args.Get(0).([]models.Article), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *TagRepository) Update(tag *models.Tag) error {
+	args := m.Called(tag)
+	return args.Error(0)
+}
+
+func (m *TagRepository) Delete(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package mocks
+
+import (
+	"go-blog/internal/repositories"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// Transactor is a mock implementation of repositories.Transactor. When the
+// expectation returns a *repositories.Tx, fn is run with it.
+type Transactor struct {
+	mock.Mock
+}
+
+func (m *Transactor) Transaction(fn func(tx *repositories.Tx) error) error {
+	args := m.Called(fn)
+	if tx, ok := args.Get(0).(*repositories.Tx); ok {
+		if err := fn(tx); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
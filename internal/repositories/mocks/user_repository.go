@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"go-blog/internal/models"
+	"go-blog/internal/repositories"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -48,4 +49,32 @@ func (m *UserRepository) Update(user *models.User) error {
 func (m *UserRepository) Delete(id uint) error {
 	args := m.Called(id)
 	return args.Error(0)
-}
\ No newline at end of file
+}
+func (m *UserRepository) Search(query string, offset, limit int) ([]models.User, int64, error) {
+	args := m.Called(query, offset, limit)
+	return args.Get(0).([]models.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *UserRepository) GetByHandle(handle string) (*models.User, error) {
+	args := m.Called(handle)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserRepository) GetByEmailChangeToken(tokenHash string) (*models.User, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *UserRepository) DeleteAccount(deletion *repositories.AccountDeletion) (*repositories.AccountDeletionResult, error) {
+	args := m.Called(deletion)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*repositories.AccountDeletionResult), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package mocks
+
+import (
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// UserSettingsRepository is a mock implementation of repositories.UserSettingsRepository
+type UserSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *UserSettingsRepository) GetByUserID(userID uint) (*models.UserSettings, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserSettings), args.Error(1)
+}
+
+func (m *UserSettingsRepository) Save(settings *models.UserSettings) error {
+	args := m.Called(settings)
+	return args.Error(0)
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	*Repository[models.Notification]
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *database.DB) NotificationRepository {
+	return &notificationRepository{
+		Repository: NewRepository[models.Notification](db),
+	}
+}
+
+// ListByUser returns the user's notifications, newest first
+func (r *notificationRepository) ListByUser(userID uint, unreadOnly bool, offset, limit int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.GetDB().GetDB().Model(&models.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notifications).Error
+	return notifications, total, err
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.GetDB().GetDB().Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications read, returning ErrNotFound
+// when it does not exist or belongs to someone else
+func (r *notificationRepository) MarkRead(userID, id uint, readAt time.Time) error {
+	var notification models.Notification
+	err := r.GetDB().GetDB().Where("id = ? AND user_id = ?", id, userID).First(&notification).Error
+	if err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.GetDB().GetDB().Model(&notification).UpdateColumn("read_at", readAt).Error
+}
+
+func (r *notificationRepository) MarkAllRead(userID uint, readAt time.Time) error {
+	return r.GetDB().GetDB().Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", readAt).Error
+}
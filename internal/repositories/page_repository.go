@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type pageRepository struct {
+	*Repository[models.Page]
+}
+
+// NewPageRepository creates a new page repository
+func NewPageRepository(db *database.DB) PageRepository {
+	return &pageRepository{
+		Repository: NewRepository[models.Page](db),
+	}
+}
+
+func (r *pageRepository) GetByID(id uint) (*models.Page, error) {
+	return r.Get(id)
+}
+
+func (r *pageRepository) GetBySlug(slug string) (*models.Page, error) {
+	return r.GetBy("slug", slug)
+}
+
+// List lists pages in navigation order, only published ones when publishedOnly is set
+func (r *pageRepository) List(publishedOnly bool) ([]models.Page, error) {
+	var pages []models.Page
+	query := r.GetDB().GetDB().Order("nav_order ASC, title ASC")
+	if publishedOnly {
+		query = query.Where("status = ?", models.PageStatusPublished)
+	}
+	err := query.Find(&pages).Error
+	return pages, err
+}
+
+// ListNavigation lists the published pages shown in navigation, in order
+func (r *pageRepository) ListNavigation() ([]models.Page, error) {
+	var pages []models.Page
+	err := r.GetDB().GetDB().
+		Where("status = ? AND show_in_nav = ?", models.PageStatusPublished, true).
+		Order("nav_order ASC, title ASC").
+		Find(&pages).Error
+	return pages, err
+}
+
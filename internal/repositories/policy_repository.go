@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"errors"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type policyRepository struct {
+	*Repository[models.Policy]
+}
+
+// NewPolicyRepository creates a new policy repository
+func NewPolicyRepository(db *database.DB) PolicyRepository {
+	return &policyRepository{
+		Repository: NewRepository[models.Policy](db),
+	}
+}
+
+func (r *policyRepository) GetByID(id uint) (*models.Policy, error) {
+	return r.Get(id)
+}
+
+// List returns every version of every policy, newest first
+func (r *policyRepository) List() ([]models.Policy, error) {
+	var policies []models.Policy
+	err := r.GetDB().GetDB().Order("id DESC").Find(&policies).Error
+	return policies, err
+}
+
+// Current returns the latest version of each kind of policy, ordered by kind
+func (r *policyRepository) Current() ([]models.Policy, error) {
+	var policies []models.Policy
+	db := r.GetDB().GetDB()
+	err := db.Where("id IN (?)", db.Model(&models.Policy{}).Select("MAX(id)").Group("kind")).
+		Order("kind").
+		Find(&policies).Error
+	return policies, err
+}
+
+// Accept records acceptances; a version the user already accepted keeps its
+// first acceptance
+func (r *policyRepository) Accept(acceptances []models.PolicyAcceptance) error {
+	db := r.GetDB()
+	for i := range acceptances {
+		if err := db.Create(&acceptances[i]); err != nil && !errors.Is(err, ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+// AcceptedPolicyIDs returns which of policyIDs the user accepted
+func (r *policyRepository) AcceptedPolicyIDs(userID uint, policyIDs []uint) ([]uint, error) {
+	if len(policyIDs) == 0 {
+		return nil, nil
+	}
+	var accepted []uint
+	err := r.GetDB().GetDB().Model(&models.PolicyAcceptance{}).
+		Where("user_id = ? AND policy_id IN ?", userID, policyIDs).
+		Pluck("policy_id", &accepted).Error
+	return accepted, err
+}
+
+// UsersPendingAcceptance returns the users who have not accepted every one of
+// policyIDs, oldest accounts first. System accounts cannot sign in and are left out.
+func (r *policyRepository) UsersPendingAcceptance(policyIDs []uint, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+	if len(policyIDs) == 0 {
+		return users, 0, nil
+	}
+
+	base := r.GetDB().GetDB().Model(&models.User{}).
+		Where("users.role <> ?", models.RoleSystem).
+		Where("(SELECT COUNT(*) FROM policy_acceptances WHERE policy_acceptances.user_id = users.id AND policy_acceptances.policy_id IN ?) < ?",
+			policyIDs, len(policyIDs))
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := base.Session(&gorm.Session{}).
+		Order("users.id").
+		Offset(offset).Limit(limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
@@ -0,0 +1,173 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// claimAttempts bounds how often Claim retries after losing a race for a job
+const claimAttempts = 3
+
+type queuedJobRepository struct {
+	*Repository[models.QueuedJob]
+}
+
+// NewQueuedJobRepository creates a new background job repository
+func NewQueuedJobRepository(db *database.DB) QueuedJobRepository {
+	return &queuedJobRepository{
+		Repository: NewRepository[models.QueuedJob](db),
+	}
+}
+
+func (r *queuedJobRepository) GetByID(id uint) (*models.QueuedJob, error) {
+	return r.Get(id)
+}
+
+// Claim takes the next due job for a worker: a pending job whose run time has
+// come, or a running job locked before staleBefore whose worker died. The job is
+// marked running and its attempt counted in one conditional update, so two
+// workers never claim the same job. Returns ErrNotFound when no job
+// is due.
+func (r *queuedJobRepository) Claim(now, staleBefore time.Time) (*models.QueuedJob, error) {
+	db := r.GetDB().GetDB()
+	due := func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("((status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?))",
+			models.QueuedJobPending, now, models.QueuedJobRunning, staleBefore)
+	}
+
+	for i := 0; i < claimAttempts; i++ {
+		var job models.QueuedJob
+		if err := due(db.Model(&models.QueuedJob{})).Order("run_at ASC, id ASC").First(&job).Error; err != nil {
+			return nil, err
+		}
+
+		result := due(db.Model(&models.QueuedJob{}).Where("id = ?", job.ID)).UpdateColumns(map[string]interface{}{
+			"status":     models.QueuedJobRunning,
+			"locked_at":  now,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"updated_at": now,
+		})
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return r.GetByID(job.ID)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Complete marks a job as succeeded
+func (r *queuedJobRepository) Complete(id uint, finishedAt time.Time) error {
+	return r.finish(id, map[string]interface{}{
+		"status":      models.QueuedJobSucceeded,
+		"locked_at":   nil,
+		"last_error":  "",
+		"finished_at": finishedAt,
+	})
+}
+
+// Reschedule puts a failed job back in the queue to be retried at runAt
+func (r *queuedJobRepository) Reschedule(id uint, runAt time.Time, lastError string) error {
+	return r.finish(id, map[string]interface{}{
+		"status":     models.QueuedJobPending,
+		"locked_at":  nil,
+		"last_error": lastError,
+		"run_at":     runAt,
+	})
+}
+
+// Bury moves a job that ran out of attempts to the dead letters
+func (r *queuedJobRepository) Bury(id uint, finishedAt time.Time, lastError string) error {
+	return r.finish(id, map[string]interface{}{
+		"status":      models.QueuedJobDead,
+		"locked_at":   nil,
+		"last_error":  lastError,
+		"finished_at": finishedAt,
+	})
+}
+
+// finish updates a job released by its worker
+func (r *queuedJobRepository) finish(id uint, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+	return r.GetDB().GetDB().Model(&models.QueuedJob{}).Where("id = ?", id).UpdateColumns(updates).Error
+}
+
+// Requeue gives a dead job a fresh set of attempts starting at runAt and reports
+// whether the job was dead
+func (r *queuedJobRepository) Requeue(id uint, runAt time.Time) (bool, error) {
+	result := r.GetDB().GetDB().Model(&models.QueuedJob{}).
+		Where("id = ? AND status = ?", id, models.QueuedJobDead).
+		UpdateColumns(map[string]interface{}{
+			"status":      models.QueuedJobPending,
+			"attempts":    0,
+			"run_at":      runAt,
+			"finished_at": nil,
+			"updated_at":  time.Now(),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// List lists jobs, optionally of one status, most recently updated first
+func (r *queuedJobRepository) List(status models.QueuedJobStatus, offset, limit int) ([]models.QueuedJob, int64, error) {
+	var jobs []models.QueuedJob
+	var total int64
+
+	query := r.GetDB().GetDB().Model(&models.QueuedJob{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("updated_at DESC, id DESC").Offset(offset).Limit(limit).Find(&jobs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// CountByStatus counts the jobs in each status
+func (r *queuedJobRepository) CountByStatus() (map[models.QueuedJobStatus]int64, error) {
+	var rows []struct {
+		Status models.QueuedJobStatus
+		Count  int64
+	}
+	err := r.GetDB().GetDB().Model(&models.QueuedJob{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.QueuedJobStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *queuedJobRepository) Delete(id uint) error {
+	result := r.GetDB().GetDB().Delete(&models.QueuedJob{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// DeleteFinishedBefore deletes jobs that succeeded before the given time and
+// returns how many were deleted. Dead jobs are kept until an admin handles them.
+func (r *queuedJobRepository) DeleteFinishedBefore(before time.Time) (int64, error) {
+	result := r.GetDB().GetDB().
+		Where("status = ? AND finished_at < ?", models.QueuedJobSucceeded, before).
+		Delete(&models.QueuedJob{})
+	return result.RowsAffected, result.Error
+}
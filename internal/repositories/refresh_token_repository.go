@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type refreshTokenRepository struct {
+	*Repository[models.RefreshToken]
+}
+
+// NewRefreshTokenRepository creates a new refresh token repository
+func NewRefreshTokenRepository(db *database.DB) RefreshTokenRepository {
+	return &refreshTokenRepository{
+		Repository: NewRepository[models.RefreshToken](db),
+	}
+}
+
+func (r *refreshTokenRepository) GetByTokenID(tokenID string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.GetDB().GetDB().Where("token_id = ?", tokenID).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed marks an unused token as used and reports whether it was unused.
+// The check and update are one statement, so concurrent refreshes with the same
+// token cannot both succeed.
+func (r *refreshTokenRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.GetDB().GetDB().Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		UpdateColumn("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteExpired deletes tokens that expired before the given time and returns how
+// many were deleted. Expired tokens fail validation, so their rows are no longer needed.
+func (r *refreshTokenRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.GetDB().GetDB().Where("expires_at < ?", before).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+// RevokeFamily revokes every token of a family that is not revoked yet
+func (r *refreshTokenRepository) RevokeFamily(familyID string, revokedAt time.Time) error {
+	return r.GetDB().GetDB().Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		UpdateColumn("revoked_at", revokedAt).Error
+}
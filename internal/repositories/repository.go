@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+)
+
+// Repository provides the common operations on records of model T. Model
+// repositories embed it and adapt it to their interfaces.
+type Repository[T any] struct {
+	db *database.DB
+}
+
+// NewRepository creates a new repository of model T
+func NewRepository[T any](db *database.DB) *Repository[T] {
+	return &Repository[T]{db: db}
+}
+
+// GetDB returns the database instance
+func (r *Repository[T]) GetDB() *database.DB {
+	return r.db
+}
+
+// Create creates a new record
+func (r *Repository[T]) Create(entity *T) error {
+	return r.db.Create(entity)
+}
+
+// Get retrieves a record by ID with optional preloads
+func (r *Repository[T]) Get(id uint, preloads ...string) (*T, error) {
+	var entity T
+	if err := r.db.GetByID(&entity, id, preloads...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// GetBy retrieves the first record whose field equals value, with optional preloads
+func (r *Repository[T]) GetBy(field string, value interface{}, preloads ...string) (*T, error) {
+	var entity T
+	if err := r.db.GetByField(&entity, field, value, preloads...); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Update saves all fields of a record
+func (r *Repository[T]) Update(entity *T) error {
+	return r.db.Update(entity)
+}
+
+// Delete deletes a record by ID, softly for models with a DeletedAt field
+func (r *Repository[T]) Delete(id uint) error {
+	return r.db.Delete(new(T), id)
+}
+
+// DeleteReleasing soft-deletes a record by ID, first replacing its values in
+// the unique columns with a placeholder so new records can reuse them
+func (r *Repository[T]) DeleteReleasing(id uint, columns ...string) error {
+	_, err := r.db.SoftDeleteReleasing(new(T), columns, "id = ?", id)
+	return err
+}
+
+// List retrieves a page of records
+func (r *Repository[T]) List(options *database.QueryOptions) (*database.Page[T], error) {
+	return database.ListOf[T](r.db, options)
+}
+
+// Exists checks if a record matching conditions exists
+func (r *Repository[T]) Exists(conditions ...interface{}) (bool, error) {
+	return r.db.Exists(new(T), conditions...)
+}
+
+// Count returns the count of records matching conditions
+func (r *Repository[T]) Count(conditions ...interface{}) (int64, error) {
+	return r.db.Count(new(T), conditions...)
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type savedSearchRepository struct {
+	*Repository[models.SavedSearch]
+}
+
+// NewSavedSearchRepository creates a new saved search repository
+func NewSavedSearchRepository(db *database.DB) SavedSearchRepository {
+	return &savedSearchRepository{
+		Repository: NewRepository[models.SavedSearch](db),
+	}
+}
+
+func (r *savedSearchRepository) GetByID(id uint) (*models.SavedSearch, error) {
+	return r.Get(id)
+}
+
+func (r *savedSearchRepository) ListByUser(userID uint) ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.GetDB().GetDB().Where("user_id = ?", userID).Order("name ASC").Find(&searches).Error
+	return searches, err
+}
+
+func (r *savedSearchRepository) CountByUser(userID uint) (int64, error) {
+	return r.Count("user_id = ?", userID)
+}
+
+// ListAlerting returns every saved search with alerts enabled
+func (r *savedSearchRepository) ListAlerting() ([]models.SavedSearch, error) {
+	var searches []models.SavedSearch
+	err := r.GetDB().GetDB().Where("alert = ?", true).Order("id ASC").Find(&searches).Error
+	return searches, err
+}
+
+// MarkChecked records when the search was last evaluated for alerts
+func (r *savedSearchRepository) MarkChecked(id uint, checkedAt time.Time) error {
+	return r.GetDB().GetDB().Model(&models.SavedSearch{}).Where("id = ?", id).
+		UpdateColumn("last_checked_at", checkedAt).Error
+}
+
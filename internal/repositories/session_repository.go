@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type sessionRepository struct {
+	*Repository[models.Session]
+}
+
+// NewSessionRepository creates a new session repository
+func NewSessionRepository(db *database.DB) SessionRepository {
+	return &sessionRepository{
+		Repository: NewRepository[models.Session](db),
+	}
+}
+
+func (r *sessionRepository) GetByID(id uint) (*models.Session, error) {
+	return r.Get(id)
+}
+
+func (r *sessionRepository) GetByFamilyID(familyID string) (*models.Session, error) {
+	var session models.Session
+	if err := r.GetDB().GetDB().Where("family_id = ?", familyID).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActive lists the user's sessions that are neither revoked nor expired at
+// now, most recently used first
+func (r *sessionRepository) ListActive(userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.GetDB().GetDB().
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC, id DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// HasDevice reports whether the user ever signed in from device, including
+// sessions that have since ended
+func (r *sessionRepository) HasDevice(userID uint, device string) (bool, error) {
+	var count int64
+	err := r.GetDB().GetDB().Model(&models.Session{}).
+		Where("user_id = ? AND device = ?", userID, device).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CountByUser counts every session the user ever had
+func (r *sessionRepository) CountByUser(userID uint) (int64, error) {
+	var count int64
+	err := r.GetDB().GetDB().Model(&models.Session{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Touch records a refresh of the session from ip and extends it to expiresAt
+func (r *sessionRepository) Touch(familyID, ip string, usedAt, expiresAt time.Time) error {
+	updates := map[string]interface{}{"last_used_at": usedAt, "expires_at": expiresAt}
+	if ip != "" {
+		updates["ip"] = ip
+	}
+	return r.GetDB().GetDB().Model(&models.Session{}).
+		Where("family_id = ?", familyID).
+		UpdateColumns(updates).Error
+}
+
+// Revoke signs a session out unless it is already revoked
+func (r *sessionRepository) Revoke(familyID string, revokedAt time.Time) error {
+	return r.GetDB().GetDB().Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		UpdateColumn("revoked_at", revokedAt).Error
+}
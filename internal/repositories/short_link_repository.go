@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type shortLinkRepository struct {
+	*Repository[models.ShortLink]
+}
+
+// NewShortLinkRepository creates a new short link repository
+func NewShortLinkRepository(db *database.DB) ShortLinkRepository {
+	return &shortLinkRepository{
+		Repository: NewRepository[models.ShortLink](db),
+	}
+}
+
+func (r *shortLinkRepository) GetByCode(code string) (*models.ShortLink, error) {
+	var link models.ShortLink
+	if err := r.GetDB().GetDB().Where("code = ?", code).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+func (r *shortLinkRepository) GetByArticle(articleID uint) (*models.ShortLink, error) {
+	var link models.ShortLink
+	if err := r.GetDB().GetDB().Where("article_id = ?", articleID).First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// RecordClick counts a click on the link and keeps its referrer
+func (r *shortLinkRepository) RecordClick(linkID uint, referrer string) error {
+	return r.GetDB().Transaction(func(tx *database.DB) error {
+		db := tx.GetDB()
+		result := db.Model(&models.ShortLink{}).Where("id = ?", linkID).
+			UpdateColumn("click_count", gorm.Expr("click_count + ?", 1))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return db.Create(&models.ShortLinkClick{ShortLinkID: linkID, Referrer: referrer}).Error
+	})
+}
+
+// ReferrerCounts returns the referrers of the link's clicks, most clicks first
+func (r *shortLinkRepository) ReferrerCounts(linkID uint, limit int) ([]models.ReferrerCount, error) {
+	var counts []models.ReferrerCount
+	err := r.GetDB().GetDB().Model(&models.ShortLinkClick{}).
+		Select("referrer, COUNT(*) AS clicks").
+		Where("short_link_id = ?", linkID).
+		Group("referrer").
+		Order("clicks DESC, referrer").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, err
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type tagAliasRepository struct {
+	*Repository[models.TagAlias]
+}
+
+// NewTagAliasRepository creates a new tag alias repository
+func NewTagAliasRepository(db *database.DB) TagAliasRepository {
+	return &tagAliasRepository{
+		Repository: NewRepository[models.TagAlias](db),
+	}
+}
+
+func (r *tagAliasRepository) GetByID(id uint) (*models.TagAlias, error) {
+	return r.Get(id, "Tag")
+}
+
+func (r *tagAliasRepository) GetByAlias(alias string) (*models.TagAlias, error) {
+	return r.GetBy("alias", alias, "Tag")
+}
+
+func (r *tagAliasRepository) List() ([]models.TagAlias, error) {
+	var aliases []models.TagAlias
+	err := r.GetDB().GetDB().Preload("Tag").Order("alias ASC").Find(&aliases).Error
+	return aliases, err
+}
+
+func (r *tagAliasRepository) ListByTag(tagID uint) ([]models.TagAlias, error) {
+	var aliases []models.TagAlias
+	err := r.GetDB().GetDB().Where("tag_id = ?", tagID).Order("alias ASC").Find(&aliases).Error
+	return aliases, err
+}
+
+func (r *tagAliasRepository) DeleteByTag(tagID uint) error {
+	return r.GetDB().BulkDelete(&models.TagAlias{}, "tag_id = ?", tagID)
+}
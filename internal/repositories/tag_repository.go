@@ -3,44 +3,42 @@ package repositories
 import (
 	"go-blog/internal/database"
 	"go-blog/internal/models"
+
+	"gorm.io/gorm"
 )
 
 type tagRepository struct {
-	*BaseRepository
+	*Repository[models.Tag]
 }
 
 // NewTagRepository creates a new tag repository
 func NewTagRepository(db *database.DB) TagRepository {
 	return &tagRepository{
-		BaseRepository: NewBaseRepository(db),
+		Repository: NewRepository[models.Tag](db),
 	}
 }
 
-func (r *tagRepository) Create(tag *models.Tag) error {
-	return r.BaseRepository.Create(tag)
+func (r *tagRepository) GetByID(id uint) (*models.Tag, error) {
+	return r.Get(id)
 }
 
-func (r *tagRepository) GetByID(id uint) (*models.Tag, error) {
-	var tag models.Tag
-	err := r.BaseRepository.GetByID(&tag, id)
-	if err != nil {
-		return nil, err
-	}
-	return &tag, nil
+// Delete soft-deletes a tag and frees its name and slug for new tags
+func (r *tagRepository) Delete(id uint) error {
+	return r.DeleteReleasing(id, "name", "slug")
 }
 
 func (r *tagRepository) GetBySlug(slug string) (*models.Tag, error) {
-	var tag models.Tag
-	err := r.GetDB().GetByField(&tag, "slug", slug)
-	if err != nil {
-		return nil, err
-	}
-	return &tag, nil
+	return r.GetBy("slug", slug)
 }
 
 func (r *tagRepository) GetByName(name string) (*models.Tag, error) {
+	return r.GetBy("name", name)
+}
+
+// GetByNormalizedName looks up a tag by name ignoring case; name must already be normalized
+func (r *tagRepository) GetByNormalizedName(name string) (*models.Tag, error) {
 	var tag models.Tag
-	err := r.GetDB().GetByField(&tag, "name", name)
+	err := r.GetDB().GetDB().Where("LOWER(name) = ?", name).First(&tag).Error
 	if err != nil {
 		return nil, err
 	}
@@ -48,17 +46,64 @@ func (r *tagRepository) GetByName(name string) (*models.Tag, error) {
 }
 
 func (r *tagRepository) List() ([]models.Tag, error) {
-	var tags []models.Tag
 	options := &database.QueryOptions{
 		Page:    1,
 		Limit:   1000, // Large limit for getting all tags
 		OrderBy: "name ASC",
 	}
 	
-	_, err := r.BaseRepository.List(&tags, options)
+	result, err := r.Repository.List(options)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// ListWithCounts returns tags ordered by usage, computed in a single aggregate query
+func (r *tagRepository) ListWithCounts(limit int) ([]TagCount, error) {
+	var tags []TagCount
+	err := r.countsQuery().
+		Order("article_count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&tags).Error
+	return tags, err
+}
+
+// Autocomplete returns tags whose name starts with prefix, most used first.
+// The prefix match is served by the unique index on tags.name.
+func (r *tagRepository) Autocomplete(prefix string, limit int) ([]TagCount, error) {
+	var tags []TagCount
+	err := r.countsQuery().
+		Where("tags.name LIKE ? "+r.GetDB().LikeEscape(), database.EscapeLike(prefix)+"%").
+		Order("article_count DESC, tags.name ASC").
+		Limit(limit).
+		Scan(&tags).Error
 	return tags, err
 }
 
+// ListOrphans returns tags that are not attached to any non-deleted article
+func (r *tagRepository) ListOrphans() ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.GetDB().GetDB().
+		Where(`NOT EXISTS (
+			SELECT 1 FROM article_tags
+			JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL
+			WHERE article_tags.tag_id = tags.id
+		)`).
+		Order("name ASC").
+		Find(&tags).Error
+	return tags, err
+}
+
+// countsQuery builds the base tag usage query counting non-deleted articles per tag
+func (r *tagRepository) countsQuery() *gorm.DB {
+	return r.GetDB().GetDB().Model(&models.Tag{}).
+		Select("tags.id, tags.name, tags.slug, tags.follower_count, tags.created_at, tags.updated_at, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.deleted_at IS NULL").
+		Group("tags.id, tags.name, tags.slug, tags.follower_count, tags.created_at, tags.updated_at")
+}
+
 func (r *tagRepository) GetArticles(tagID uint, offset, limit int) ([]models.Article, int64, error) {
 	var articles []models.Article
 	var total int64
@@ -79,6 +124,7 @@ func (r *tagRepository) GetArticles(tagID uint, offset, limit int) ([]models.Art
 	query.Count(&total)
 	
 	// Get paginated results
-	err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error
+	err := query.Select(articleSummaryColumns).Offset(offset).Limit(limit).Order("articles.created_at DESC").Find(&articles).Error
 	return articles, total, err
-}
\ No newline at end of file
+}
+
@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"fmt"
+	"testing"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagRepository_Autocomplete(t *testing.T) {
+	// Skip if not running integration tests
+	if testing.Short() {
+		t.Skip("Skipping integration test")
+	}
+
+	db, err := database.SetupTestDB()
+	require.NoError(t, err)
+	defer database.CleanupTestDB(db)
+
+	tagRepo := NewTagRepository(db)
+	for i, name := range []string{"go_lang", "go-lang", "golang", "go lang"} {
+		require.NoError(t, tagRepo.Create(&models.Tag{Name: name, Slug: fmt.Sprintf("tag-%d", i)}))
+	}
+
+	names := func(prefix string) []string {
+		t.Helper()
+		tags, err := tagRepo.Autocomplete(prefix, 10)
+		require.NoError(t, err)
+		result := make([]string, len(tags))
+		for i, tag := range tags {
+			result[i] = tag.Name
+		}
+		return result
+	}
+
+	// Wildcards in the prefix are matched literally
+	assert.Equal(t, []string{"go_lang"}, names("go_"))
+	assert.Equal(t, []string{"go_lang"}, names("go_lang"))
+	assert.Empty(t, names("%"))
+	assert.Empty(t, names("go%"))
+	assert.ElementsMatch(t, []string{"go_lang", "go-lang", "golang", "go lang"}, names("go"))
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+)
+
+// Tx holds repositories bound to one database transaction
+type Tx struct {
+	Articles   ArticleRepository
+	Tags       TagRepository
+	TagAliases TagAliasRepository
+	Likes      LikeRepository
+	Revisions  ArticleRevisionRepository
+}
+
+// Transactor runs work spanning several repositories in one database
+// transaction, for flows that must not leave partial writes behind
+type Transactor interface {
+	// Transaction runs fn in a transaction, committing when it returns nil and
+	// rolling back otherwise
+	Transaction(fn func(tx *Tx) error) error
+}
+
+type transactor struct {
+	db *database.DB
+}
+
+// NewTransactor creates a transactor on top of db
+func NewTransactor(db *database.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) Transaction(fn func(tx *Tx) error) error {
+	return t.db.Transaction(func(tx *database.DB) error {
+		return fn(&Tx{
+			Articles:   NewArticleRepository(tx),
+			Tags:       NewTagRepository(tx),
+			TagAliases: NewTagAliasRepository(tx),
+			Likes:      NewLikeRepository(tx),
+			Revisions:  NewArticleRevisionRepository(tx),
+		})
+	})
+}
@@ -1,56 +1,190 @@
 package repositories
 
 import (
+	"fmt"
+
 	"go-blog/internal/database"
 	"go-blog/internal/models"
+
+	"gorm.io/gorm"
 )
 
 type userRepository struct {
-	*BaseRepository
+	*Repository[models.User]
 }
 
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *database.DB) UserRepository {
 	return &userRepository{
-		BaseRepository: NewBaseRepository(db),
+		Repository: NewRepository[models.User](db),
 	}
 }
 
-func (r *userRepository) Create(user *models.User) error {
-	return r.BaseRepository.Create(user)
+func (r *userRepository) GetByID(id uint) (*models.User, error) {
+	return r.Get(id)
 }
 
-func (r *userRepository) GetByID(id uint) (*models.User, error) {
-	var user models.User
-	err := r.BaseRepository.GetByID(&user, id)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+// Delete soft-deletes a user and frees their username, handle and email
+func (r *userRepository) Delete(id uint) error {
+	return r.DeleteReleasing(id, "username", "handle", "email")
 }
 
 func (r *userRepository) GetByEmail(email string) (*models.User, error) {
-	var user models.User
-	err := r.GetDB().GetByField(&user, "email", email)
-	if err != nil {
-		return nil, err
-	}
-	return &user, nil
+	return r.GetBy("email", email)
 }
 
 func (r *userRepository) GetByUsername(username string) (*models.User, error) {
-	var user models.User
-	err := r.GetDB().GetByField(&user, "username", username)
+	return r.GetBy("username", username)
+}
+
+func (r *userRepository) GetByHandle(handle string) (*models.User, error) {
+	return r.GetBy("handle", handle)
+}
+
+// GetByEmailChangeToken finds the user with a pending email change for tokenHash
+func (r *userRepository) GetByEmailChangeToken(tokenHash string) (*models.User, error) {
+	return r.GetBy("email_change_token", tokenHash)
+}
+
+// Search matches users by username and bio, best match first. Users whose profile
+// is not public are left out.
+func (r *userRepository) Search(query string, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	db := r.GetDB()
+	matchExpr, matchArgs := db.FullTextMatch([]string{"users.username", "users.bio"}, query, false)
+	base := db.GetDB().Model(&models.User{}).
+		Where(matchExpr+" > 0", matchArgs...).
+		Where("NOT EXISTS (SELECT 1 FROM user_settings WHERE user_settings.user_id = users.id AND user_settings.profile_visibility <> ?)",
+			models.ProfileVisibilityPublic)
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := base.Session(&gorm.Session{}).
+		Select("users.*, "+matchExpr+" AS relevance_score", matchArgs...).
+		Order("relevance_score DESC, users.username ASC").
+		Offset(offset).Limit(limit).
+		Find(&users).Error
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
-	return &user, nil
+	return users, total, nil
 }
 
-func (r *userRepository) Update(user *models.User) error {
-	return r.BaseRepository.Update(user)
-}
+// DeleteAccount removes a user in one transaction. Their articles and comments are
+// reassigned or soft-deleted as requested, deleted articles taking their comments
+// with them and replied-to comments staying as tombstones; likes, follows, saved searches,
+// notifications, settings and policy acceptances are removed. The account is scrubbed
+// of personal data before it is soft-deleted, which also frees its username and email
+// for reuse.
+func (r *userRepository) DeleteAccount(deletion *AccountDeletion) (*AccountDeletionResult, error) {
+	result := &AccountDeletionResult{}
+	userID := deletion.UserID
 
-func (r *userRepository) Delete(id uint) error {
-	return r.BaseRepository.Delete(&models.User{}, id)
-}
\ No newline at end of file
+	err := r.GetDB().Transaction(func(tx *database.DB) error {
+		db := tx.GetDB()
+
+		var res *gorm.DB
+		if deletion.ArticleOwnerID != 0 {
+			res = db.Model(&models.Article{}).Where("author_id = ?", userID).UpdateColumn("author_id", deletion.ArticleOwnerID)
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Articles = res.RowsAffected
+		} else {
+			deleted, err := tx.SoftDeleteReleasing(&models.Article{}, []string{"slug"}, "author_id = ?", userID)
+			if err != nil {
+				return err
+			}
+			result.Articles = deleted
+			// Comments on the deleted articles go with them
+			authored := db.Unscoped().Model(&models.Article{}).Select("id").Where("author_id = ?", userID)
+			if err := db.Where("article_id IN (?)", authored).Delete(&models.Comment{}).Error; err != nil {
+				return err
+			}
+		}
+
+		var commentedIDs []uint
+		if err := db.Model(&models.Comment{}).Where("user_id = ?", userID).Distinct().Pluck("article_id", &commentedIDs).Error; err != nil {
+			return err
+		}
+		if deletion.CommentOwnerID != 0 {
+			res = db.Model(&models.Comment{}).Where("user_id = ?", userID).UpdateColumn("user_id", deletion.CommentOwnerID)
+			if res.Error != nil {
+				return res.Error
+			}
+			result.Comments = res.RowsAffected
+		} else {
+			// Comments other users replied to stay behind as tombstones
+			removed, err := removeComments(tx, "user_id = ?", userID)
+			if err != nil {
+				return err
+			}
+			result.Comments = removed
+		}
+		if deletion.CommentOwnerID == 0 && len(commentedIDs) > 0 {
+			if err := tx.Exec("UPDATE articles SET comment_count = "+countedComments+" WHERE id IN ?", commentedIDs); err != nil {
+				return err
+			}
+		}
+
+		var likedIDs []uint
+		if err := db.Model(&models.Like{}).Where("user_id = ?", userID).Pluck("article_id", &likedIDs).Error; err != nil {
+			return err
+		}
+		if err := db.Unscoped().Where("user_id = ?", userID).Delete(&models.Like{}).Error; err != nil {
+			return err
+		}
+		if len(likedIDs) > 0 {
+			if err := tx.Exec("UPDATE articles SET like_count = (SELECT COUNT(*) FROM likes "+
+				"WHERE likes.article_id = articles.id AND likes.deleted_at IS NULL) WHERE id IN ?", likedIDs); err != nil {
+				return err
+			}
+		}
+
+		var follows []models.Follow
+		if err := db.Where("user_id = ?", userID).Find(&follows).Error; err != nil {
+			return err
+		}
+		for _, follow := range follows {
+			if err := tx.Exec("UPDATE "+follow.TargetType.TableName()+" SET follower_count = follower_count - 1 WHERE id = ? AND follower_count > 0", follow.TargetID); err != nil {
+				return err
+			}
+		}
+
+		for _, model := range []interface{}{&models.Follow{}, &models.Mention{}, &models.CommentSubscription{}, &models.SavedSearch{}, &models.Notification{}, &models.UserSettings{}, &models.RefreshToken{}, &models.Session{}, &models.MagicLink{}, &models.PolicyAcceptance{}} {
+			if err := db.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Placeholders contain ':', which validation rejects, so they never collide with real accounts
+		placeholder := fmt.Sprintf("deleted:%d", userID)
+		res = db.Model(&models.User{}).Where("id = ?", userID).UpdateColumns(map[string]interface{}{
+			"username": placeholder, "handle": placeholder, "email": placeholder, "password_hash": "",
+			"pending_email": "", "email_change_token": "", "email_change_expires_at": nil,
+			"avatar_url": "", "avatar_key": "", "bio": "", "website": "", "twitter": "", "github": "", "location": "",
+		})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		if err := db.Delete(&models.User{}, userID).Error; err != nil {
+			return err
+		}
+
+		if deletion.Audit != nil {
+			return tx.Create(deletion.Audit)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
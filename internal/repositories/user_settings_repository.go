@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+type userSettingsRepository struct {
+	*Repository[models.UserSettings]
+}
+
+// NewUserSettingsRepository creates a new user settings repository
+func NewUserSettingsRepository(db *database.DB) UserSettingsRepository {
+	return &userSettingsRepository{
+		Repository: NewRepository[models.UserSettings](db),
+	}
+}
+
+// GetByUserID returns the stored settings, or ErrNotFound when the user has none
+func (r *userSettingsRepository) GetByUserID(userID uint) (*models.UserSettings, error) {
+	return r.GetBy("user_id", userID)
+}
+
+// Save inserts or replaces the user's settings row
+func (r *userSettingsRepository) Save(settings *models.UserSettings) error {
+	return r.GetDB().Update(settings)
+}
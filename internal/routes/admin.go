@@ -0,0 +1,58 @@
+package routes
+
+import (
+	"go-blog/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerAdmin registers administrator-only routes
+func registerAdmin(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	admin := rg.Group("/admin", d.Auth(), middleware.RequireAdmin())
+	{
+		admin.GET("/tag-aliases", h.Tag.ListAliases)
+		admin.POST("/tag-aliases", h.Tag.CreateAlias)
+		admin.DELETE("/tag-aliases/:id", h.Tag.DeleteAlias)
+		admin.GET("/tags/orphans", h.Tag.ListOrphans)
+		admin.DELETE("/tags/orphans", h.Tag.CleanupOrphans)
+		admin.PUT("/tags/:id", h.Tag.UpdateDetails)
+		admin.DELETE("/users/:id", h.User.Delete)
+		admin.PUT("/users/:id/membership", h.User.SetMembership)
+		admin.POST("/users/:id/articles/transfer", h.Article.TransferUserArticles)
+		admin.POST("/articles/:id/transfer", h.Article.TransferArticle)
+		admin.GET("/comment-reports", h.Comment.ReportQueue)
+		admin.POST("/comments/:id/review", h.Comment.ReviewReports)
+		admin.GET("/content-filters", h.ContentFilter.List)
+		admin.POST("/content-filters", h.ContentFilter.Create)
+		admin.PUT("/content-filters/:id", h.ContentFilter.Update)
+		admin.DELETE("/content-filters/:id", h.ContentFilter.Delete)
+		admin.GET("/stats/comment-counts", h.Statistics.GetCommentCountReport)
+		admin.GET("/link-checks/broken", h.LinkCheck.ListBroken)
+		admin.GET("/maintenance", h.Maintenance.Get)
+		admin.PUT("/maintenance", h.Maintenance.Update)
+		admin.GET("/site-visibility", h.Site.Get)
+		admin.PUT("/site-visibility", h.Site.Update)
+		admin.GET("/pages", h.Page.AdminList)
+		admin.POST("/pages", h.Page.Create)
+		admin.PUT("/pages/:id", h.Page.Update)
+		admin.DELETE("/pages/:id", h.Page.Delete)
+		admin.GET("/homepage-sections", h.Homepage.List)
+		admin.POST("/homepage-sections", h.Homepage.Create)
+		admin.PUT("/homepage-sections/:id", h.Homepage.Update)
+		admin.DELETE("/homepage-sections/:id", h.Homepage.Delete)
+		admin.GET("/policies", h.Policy.List)
+		admin.POST("/policies", h.Policy.Publish)
+		admin.GET("/policies/pending-users", h.Policy.PendingUsers)
+		admin.GET("/jobs", h.Job.List)
+		admin.PUT("/jobs/:name", h.Job.Update)
+		admin.POST("/jobs/:name/run", h.Job.Run)
+		admin.GET("/jobs/:name/runs", h.Job.Runs)
+		admin.GET("/slo", h.SLO.Get)
+		admin.GET("/queue", h.Queue.Stats)
+		admin.GET("/queue/jobs", h.Queue.List)
+		admin.POST("/queue/jobs/:id/retry", h.Queue.Retry)
+		admin.DELETE("/queue/jobs/:id", h.Queue.Delete)
+	}
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerArchive registers archive routes
+func registerArchive(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	archive := rg.Group("/archive")
+	{
+		archive.GET("", d.Cached(10*time.Minute), h.Archive.GetArchive)
+		archive.GET("/stats", d.Cached(10*time.Minute), h.Archive.GetStatistics)
+		archive.GET("/:year/:month", d.Cached(10*time.Minute), h.Archive.GetArchiveByMonth)
+		archive.GET("/:year/:month/calendar", d.Cached(10*time.Minute), h.Archive.GetCalendar)
+		archive.GET("/:year/:month/:day", d.Cached(10*time.Minute), h.Archive.GetArchiveByDay)
+	}
+}
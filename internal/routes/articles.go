@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerArticles registers article and article like routes
+func registerArticles(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	articles := rg.Group("/articles")
+	{
+		articles.GET("", d.Cached(time.Minute), d.OptionalAuth(), h.Article.List)
+		articles.POST("", d.Auth(), h.Article.Create)
+		articles.GET("/search", h.Search.Search)
+		// The detail route shares the :id wildcard with the nested GET routes below
+		// (gin rejects differently named wildcards at the same position); the value is the slug.
+		articles.GET("/:id", d.Cached(5*time.Minute), d.OptionalAuth(), h.Article.GetBySlug)
+		articles.PUT("/:id", d.Auth(), h.Article.Update)
+		articles.DELETE("/:id", d.Auth(), h.Article.Delete)
+		articles.PUT("/:id/settings", d.Auth(), h.Article.UpdateSettings)
+		articles.GET("/:id/shortlink", h.ShortLink.Get)
+		articles.POST("/:id/views", h.Statistics.RecordView)
+		articles.GET("/:id/revisions", d.Auth(), h.Article.Revisions)
+		articles.POST("/:id/suggestions", d.Auth(), h.Article.Suggestions)
+		articles.GET("/:id/revisions/:a/compare/:b", d.Auth(), h.Article.CompareRevisions)
+		articles.GET("/:id/like", d.OptionalAuth(), h.Like.GetLikeStatus)
+		articles.POST("/:id/like", d.Auth(), h.Like.ToggleLike)
+		articles.GET("/:id/meta", d.OptionalAuth(), h.Article.Meta)
+		articles.PUT("/:id/meta", d.Auth(), h.Article.SetMeta)
+		articles.DELETE("/:id/meta/:key", d.Auth(), h.Article.DeleteMeta)
+	}
+}
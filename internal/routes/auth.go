@@ -0,0 +1,20 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerAuth registers authentication routes
+func registerAuth(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	auth := rg.Group("/auth")
+	{
+		auth.POST("/register", h.Auth.Register)
+		auth.POST("/login", h.Auth.Login)
+		auth.POST("/magic-link", h.Auth.RequestMagicLink)
+		auth.POST("/magic-link/verify", h.Auth.VerifyMagicLink)
+		auth.POST("/logout", h.Auth.Logout)
+		auth.POST("/refresh", h.Auth.RefreshToken)
+		auth.GET("/confirm-email", h.Auth.ConfirmEmailChange)
+		auth.GET("/me", d.Auth(), h.Auth.Me)
+	}
+}
@@ -0,0 +1,8 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerAuthors registers public author pages, addressed by profile handle
+func registerAuthors(rg *gin.RouterGroup, d *Dependencies) {
+	rg.GET("/authors/:handle", d.OptionalAuth(), d.Handlers.User.GetAuthor)
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerCategories registers category routes
+func registerCategories(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	categories := rg.Group("/categories")
+	{
+		categories.GET("", d.Cached(5*time.Minute), h.Category.List)
+		categories.POST("", d.Auth(), h.Category.Create)
+		categories.GET("/:slug", d.Cached(5*time.Minute), h.Category.GetBySlug)
+		categories.PUT("/:id", d.Auth(), h.Category.Update)
+		categories.DELETE("/:id", d.Auth(), h.Category.Delete)
+		categories.GET("/:slug/articles", d.Cached(time.Minute), h.Category.GetCategoryArticles)
+		categories.GET("/:slug/articles/search", h.Search.SearchCategoryArticles)
+		categories.POST("/:id/follow", d.Auth(), h.Follow.FollowCategory)
+		categories.DELETE("/:id/follow", d.Auth(), h.Follow.UnfollowCategory)
+	}
+}
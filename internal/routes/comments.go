@@ -0,0 +1,19 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerComments registers comment routes
+func registerComments(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	rg.GET("/articles/:id/comments", h.Comment.GetByArticle)
+	rg.POST("/articles/:id/comments", d.Auth(), h.Comment.Create)
+	rg.PUT("/comments/:id", d.Auth(), h.Comment.Update)
+	rg.DELETE("/comments/:id", d.Auth(), h.Comment.Delete)
+	rg.POST("/comments/:id/report", d.Auth(), h.Comment.Report)
+
+	rg.GET("/articles/:id/subscription", d.Auth(), h.Comment.GetSubscription)
+	rg.POST("/articles/:id/subscription", d.Auth(), h.Comment.Subscribe)
+	rg.DELETE("/articles/:id/subscription", d.Auth(), h.Comment.Unsubscribe)
+	rg.GET("/comment-subscriptions/unsubscribe", h.Comment.UnsubscribeByToken)
+}
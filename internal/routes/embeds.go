@@ -0,0 +1,10 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerEmbeds registers the oEmbed proxy resolving links in article content
+func registerEmbeds(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	rg.GET("/embeds", h.Embed.Resolve)
+}
@@ -0,0 +1,8 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerFeed registers the personalized feed built from followed categories and tags
+func registerFeed(rg *gin.RouterGroup, d *Dependencies) {
+	rg.GET("/feed", d.Auth(), d.Handlers.Follow.Feed)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerHomepage registers the composed homepage; its sections are managed
+// under /admin/homepage-sections
+func registerHomepage(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	rg.GET("/homepage", d.Cached(time.Minute), h.Homepage.Get)
+}
@@ -0,0 +1,67 @@
+package routes
+
+import (
+	"go-blog/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShapeHeader is the request header asking a route in transition for its
+// previous response shape, with the value LegacyShape
+const (
+	ShapeHeader = "X-Response-Shape"
+	LegacyShape = "legacy"
+)
+
+// Deprecation marks a route of an API version deprecated. Its responses carry
+// the Deprecation, Sunset and Link headers of the notice and its warning.
+type Deprecation struct {
+	Method string
+	Path   string // as registered within the version, e.g. "/articles/:id"
+	Notice middleware.DeprecationNotice
+}
+
+// Transition serves the previous response shape of a route next to the new one
+// while clients migrate. The route answers in its new shape; requests sending
+// ShapeHeader: legacy get its data rewritten by Legacy into the previous shape,
+// and are told with Notice that the shape is deprecated.
+type Transition struct {
+	Method string
+	Path   string // as registered within the version, e.g. "/articles/:id"
+	Legacy middleware.Reshape
+	Notice middleware.DeprecationNotice
+}
+
+// lifecycle returns the middleware applying the version's deprecations and
+// transitions to its routes mounted under base
+func (v Version) lifecycle(base string) gin.HandlerFunc {
+	if len(v.Deprecations) == 0 && len(v.Transitions) == 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	deprecated := make(map[string]middleware.DeprecationNotice, len(v.Deprecations))
+	for _, d := range v.Deprecations {
+		deprecated[d.Method+" "+base+d.Path] = d.Notice
+	}
+	transitions := make(map[string]Transition, len(v.Transitions))
+	for _, t := range v.Transitions {
+		transitions[t.Method+" "+base+t.Path] = t
+	}
+
+	return func(c *gin.Context) {
+		key := c.Request.Method + " " + c.FullPath()
+		if t, ok := transitions[key]; ok {
+			// Both shapes share a URL, so caches must tell them apart
+			c.Writer.Header().Add("Vary", ShapeHeader)
+			if c.GetHeader(ShapeHeader) == LegacyShape {
+				t.Notice.Serve(c, t.Legacy)
+				return
+			}
+		}
+		if notice, ok := deprecated[key]; ok {
+			notice.Serve(c, nil)
+			return
+		}
+		c.Next()
+	}
+}
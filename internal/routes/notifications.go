@@ -0,0 +1,15 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerNotifications registers the current user's notification routes
+func registerNotifications(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	notifications := rg.Group("/notifications", d.Auth())
+	{
+		notifications.GET("", h.Notification.List)
+		notifications.POST("/read-all", h.Notification.MarkAllRead)
+		notifications.POST("/:id/read", h.Notification.MarkRead)
+	}
+}
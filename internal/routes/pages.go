@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerPages registers static page routes; pages are managed under /admin/pages
+func registerPages(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	pages := rg.Group("/pages")
+	{
+		pages.GET("", d.Cached(10*time.Minute), h.Page.List)
+		pages.GET("/:slug", d.Cached(10*time.Minute), d.OptionalAuth(), h.Page.GetBySlug)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerPolicies registers policy routes; new versions are published under /admin/policies
+func registerPolicies(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	policies := rg.Group("/policies")
+	{
+		policies.GET("", d.Cached(10*time.Minute), h.Policy.Current)
+		policies.GET("/pending", d.Auth(), h.Policy.Pending)
+		policies.POST("/accept", d.Auth(), h.Policy.Accept)
+	}
+}
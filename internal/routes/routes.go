@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"time"
+
+	"go-blog/internal/handlers"
+	"go-blog/internal/middleware"
+	"go-blog/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handlers groups the HTTP handlers that route modules register
+type Handlers struct {
+	Auth          *handlers.AuthHandler
+	User          *handlers.UserHandler
+	Article       *handlers.ArticleHandler
+	Category      *handlers.CategoryHandler
+	Tag           *handlers.TagHandler
+	Comment       *handlers.CommentHandler
+	Follow        *handlers.FollowHandler
+	Archive       *handlers.ArchiveHandler
+	Statistics    *handlers.StatisticsHandler
+	Like          *handlers.LikeHandler
+	Search        *handlers.SearchHandler
+	SavedSearch   *handlers.SavedSearchHandler
+	Notification  *handlers.NotificationHandler
+	Settings      *handlers.SettingsHandler
+	Maintenance   *handlers.MaintenanceHandler
+	Site          *handlers.SiteVisibilityHandler
+	Page          *handlers.PageHandler
+	Job           *handlers.JobHandler
+	Queue         *handlers.QueueHandler
+	ShortLink     *handlers.ShortLinkHandler
+	SLO           *handlers.SLOHandler
+	Embed         *handlers.EmbedHandler
+	LinkCheck     *handlers.LinkCheckHandler
+	ContentFilter *handlers.ContentFilterHandler
+	Policy        *handlers.PolicyHandler
+	Homepage      *handlers.HomepageHandler
+}
+
+// Dependencies holds everything route modules need to register their routes
+type Dependencies struct {
+	Handlers    *Handlers
+	AuthService *services.AuthService
+	Cache       *middleware.ResponseCache // nil when response caching is disabled
+	Limits      *middleware.BodyLimits    // nil when request bodies are not limited
+}
+
+// Auth returns the middleware requiring a valid access token
+func (d *Dependencies) Auth() gin.HandlerFunc {
+	return middleware.Auth(d.AuthService)
+}
+
+// OptionalAuth returns the middleware that loads the user when a valid token is present
+func (d *Dependencies) OptionalAuth() gin.HandlerFunc {
+	return middleware.OptionalAuth(d.AuthService)
+}
+
+// Cached returns the middleware caching anonymous reads of a public route for
+// ttl by default; it does nothing when caching is disabled
+func (d *Dependencies) Cached(ttl time.Duration) gin.HandlerFunc {
+	if d.Cache == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return d.Cache.Route(ttl)
+}
+
+// Upload returns the middleware of upload routes, which get the larger upload
+// body limit and only accept the configured file types; it does nothing when
+// bodies are not limited
+func (d *Dependencies) Upload() gin.HandlerFunc {
+	if d.Limits == nil {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return d.Limits.Upload()
+}
+
+// Module registers the routes of one domain on an API version group
+type Module func(rg *gin.RouterGroup, d *Dependencies)
+
+// Version is an API version mounted under /api/<Name>.
+// Versions are built from modules, so a new version can reuse the modules
+// that did not change and swap in new ones for breaking changes.
+type Version struct {
+	Name    string
+	Modules []Module
+	// Deprecations and Transitions retire routes of the version gradually:
+	// deprecated routes announce their sunset, and routes changing their
+	// response shape serve the previous one on request until clients moved.
+	Deprecations []Deprecation
+	Transitions  []Transition
+}
+
+// V1 returns the first API version
+func V1() Version {
+	return Version{
+		Name: "v1",
+		Modules: []Module{
+			registerAuth,
+			registerUsers,
+			registerAuthors,
+			registerArticles,
+			registerComments,
+			registerCategories,
+			registerTags,
+			registerFeed,
+			registerArchive,
+			registerStats,
+			registerSearch,
+			registerNotifications,
+			registerPages,
+			registerHomepage,
+			registerEmbeds,
+			registerPolicies,
+			registerAdmin,
+		},
+		// No v1 route is deprecated or changing shape yet
+		Deprecations: nil,
+		Transitions:  nil,
+	}
+}
+
+// Versions returns every API version served, oldest first.
+// To introduce v2, add a Version whose Modules start from V1's and
+// replace the modules that change; v1 clients are unaffected.
+func Versions() []Version {
+	return []Version{V1()}
+}
+
+// Setup mounts every API version under /api/<version>.
+// The unversioned /api prefix predates versioning and stays pinned to v1
+// so existing clients keep working.
+func Setup(router *gin.Engine, d *Dependencies) {
+	for _, version := range Versions() {
+		version.Mount(router.Group("/api/"+version.Name), d)
+	}
+
+	V1().Mount(router.Group("/api"), d)
+}
+
+// Mount registers all modules of the version on rg
+func (v Version) Mount(rg *gin.RouterGroup, d *Dependencies) {
+	rg.Use(versionHeader(v.Name), v.lifecycle(rg.BasePath()))
+	for _, register := range v.Modules {
+		register(rg, d)
+	}
+}
+
+// versionHeader reports the API version that served the request
+func versionHeader(name string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("X-API-Version", name)
+		c.Next()
+	}
+}
@@ -0,0 +1,155 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-blog/internal/handlers"
+	"go-blog/internal/middleware"
+	"go-blog/internal/services"
+	"go-blog/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func setupTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	Setup(router, &Dependencies{
+		Handlers: &Handlers{
+			Auth:       &handlers.AuthHandler{},
+			User:       &handlers.UserHandler{},
+			Article:    &handlers.ArticleHandler{},
+			Category:   &handlers.CategoryHandler{},
+			Tag:        &handlers.TagHandler{},
+			Comment:    &handlers.CommentHandler{},
+			Follow:     &handlers.FollowHandler{},
+			Archive:    &handlers.ArchiveHandler{},
+			Statistics: &handlers.StatisticsHandler{},
+			Like:       &handlers.LikeHandler{},
+			Search:     &handlers.SearchHandler{},
+		},
+		AuthService: &services.AuthService{},
+	})
+	return router
+}
+
+func TestSetupRegistersVersionedAndLegacyRoutes(t *testing.T) {
+	router := setupTestRouter()
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	expected := []string{
+		"GET /api/v1/tags",
+		"GET /api/tags",
+		"POST /api/v1/articles/:id/like",
+		"POST /api/articles/:id/like",
+		"GET /api/v1/admin/tags/orphans",
+	}
+	for _, route := range expected {
+		if !registered[route] {
+			t.Errorf("Expected route %s to be registered", route)
+		}
+	}
+}
+
+func TestVersionHeader(t *testing.T) {
+	router := setupTestRouter()
+
+	tests := []string{"/api/v1/feed", "/api/feed"}
+	for _, path := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if got := w.Header().Get("X-API-Version"); got != "v1" {
+			t.Errorf("GET %s: expected X-API-Version v1, got %q", path, got)
+		}
+	}
+}
+
+func TestDeprecationsAndTransitions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	version := Version{
+		Name: "v9",
+		Modules: []Module{func(rg *gin.RouterGroup, d *Dependencies) {
+			rg.GET("/old", func(c *gin.Context) { c.JSON(http.StatusOK, utils.SuccessResponse("ok", "old")) })
+			rg.GET("/things/:id", func(c *gin.Context) {
+				c.JSON(http.StatusOK, utils.SuccessResponse("ok", gin.H{"id": c.Param("id"), "name": "new"}))
+			})
+		}},
+		Deprecations: []Deprecation{{
+			Method: http.MethodGet,
+			Path:   "/old",
+			Notice: middleware.DeprecationNotice{Since: since, Sunset: sunset, Link: "https://example.com/migrate", Warning: "GET /old is deprecated"},
+		}},
+		Transitions: []Transition{{
+			Method: http.MethodGet,
+			Path:   "/things/:id",
+			Legacy: func(data json.RawMessage) (json.RawMessage, error) {
+				var thing struct {
+					ID string `json:"id"`
+				}
+				if err := json.Unmarshal(data, &thing); err != nil {
+					return nil, err
+				}
+				return json.Marshal(gin.H{"thing_id": thing.ID})
+			},
+			Notice: middleware.DeprecationNotice{Since: since, Warning: "the legacy thing shape is deprecated"},
+		}},
+	}
+	router := gin.New()
+	version.Mount(router.Group("/api/v9"), &Dependencies{})
+
+	serve := func(path, shape string) (*httptest.ResponseRecorder, utils.APIResponse) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if shape != "" {
+			req.Header.Set(ShapeHeader, shape)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var response utils.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("GET %s: failed to decode %q: %v", path, w.Body.String(), err)
+		}
+		return w, response
+	}
+
+	w, response := serve("/api/v9/old", "")
+	if got := w.Header().Get("Deprecation"); got != fmt.Sprintf("@%d", since.Unix()) {
+		t.Errorf("Expected the Deprecation header, got %q", got)
+	}
+	if got := w.Header().Get("Sunset"); got != "Wed, 01 Jul 2026 00:00:00 GMT" {
+		t.Errorf("Expected the Sunset header, got %q", got)
+	}
+	if got := w.Header().Get("Link"); got != `<https://example.com/migrate>; rel="deprecation"` {
+		t.Errorf("Expected the Link header, got %q", got)
+	}
+	if response.Data != "old" || len(response.Warnings) != 1 || response.Warnings[0] != "GET /old is deprecated" {
+		t.Errorf("Expected the response with a warning, got %+v", response)
+	}
+
+	w, response = serve("/api/v9/things/7", "")
+	if w.Header().Get("Deprecation") != "" || len(response.Warnings) != 0 || !strings.Contains(w.Body.String(), `"name":"new"`) {
+		t.Errorf("Expected the new shape without deprecation, got %s", w.Body.String())
+	}
+	if w.Header().Get("Vary") != ShapeHeader {
+		t.Errorf("Expected responses to vary by %s, got %q", ShapeHeader, w.Header().Get("Vary"))
+	}
+	w, response = serve("/api/v9/things/7", LegacyShape)
+	if !strings.Contains(w.Body.String(), `"thing_id":"7"`) || w.Header().Get("Deprecation") == "" ||
+		len(response.Warnings) != 1 || response.Warnings[0] != "the legacy thing shape is deprecated" {
+		t.Errorf("Expected the deprecated legacy shape, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerSearch registers search routes
+func registerSearch(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	search := rg.Group("/search")
+	{
+		search.GET("", h.Search.Search)
+		search.GET("/suggestions", h.Search.Suggestions)
+		search.GET("/instant", d.Cached(time.Minute), h.Search.Instant) // Search-as-you-type, a request per keystroke
+		search.POST("/interlinks", d.Auth(), h.Search.Interlinks)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerStats registers statistics routes
+func registerStats(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	stats := rg.Group("/stats")
+	{
+		stats.GET("/popular", h.Statistics.GetPopular)
+		stats.GET("/trending", h.Statistics.GetTrending)
+		stats.GET("/periods", h.Statistics.GetPeriodStats)
+		stats.GET("/articles/:id", h.Statistics.GetArticleStats)
+		stats.GET("/articles/:id/shortlink", h.Statistics.GetShortLinkStats)
+		stats.GET("/authors", h.Statistics.GetAuthorLeaderboard)
+		stats.GET("/authors/:id", h.Statistics.GetAuthorStats)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerTags registers tag routes
+func registerTags(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	tags := rg.Group("/tags")
+	{
+		tags.GET("", d.Cached(5*time.Minute), h.Tag.List)
+		tags.POST("", d.Auth(), h.Tag.Create)
+		tags.GET("/autocomplete", h.Tag.Autocomplete)
+		tags.GET("/:slug", d.Cached(5*time.Minute), h.Tag.GetBySlug)
+		tags.GET("/:slug/articles", d.Cached(time.Minute), h.Tag.GetTagArticles)
+		tags.POST("/:slug/follow", d.Auth(), h.Follow.FollowTag)
+		tags.DELETE("/:slug/follow", d.Auth(), h.Follow.UnfollowTag)
+	}
+}
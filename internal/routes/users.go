@@ -0,0 +1,33 @@
+package routes
+
+import "github.com/gin-gonic/gin"
+
+// registerUsers registers user profile routes
+func registerUsers(rg *gin.RouterGroup, d *Dependencies) {
+	h := d.Handlers
+
+	users := rg.Group("/users")
+	{
+		users.DELETE("/me", d.Auth(), h.User.DeleteMe)
+		users.GET("/me/follows", d.Auth(), h.Follow.ListFollows)
+		users.GET("/me/likes", d.Auth(), h.Like.ListMine)
+		users.POST("/me/avatar", d.Auth(), d.Upload(), h.User.UploadAvatar)
+		users.DELETE("/me/avatar", d.Auth(), h.User.DeleteAvatar)
+		users.POST("/me/password", d.Auth(), h.Auth.ChangePassword)
+		users.POST("/me/email", d.Auth(), h.Auth.RequestEmailChange)
+		users.GET("/me/sessions", d.Auth(), h.Auth.ListSessions)
+		users.DELETE("/me/sessions", d.Auth(), h.Auth.RevokeOtherSessions)
+		users.DELETE("/me/sessions/:id", d.Auth(), h.Auth.RevokeSession)
+		users.GET("/me/saved-searches", d.Auth(), h.SavedSearch.List)
+		users.POST("/me/saved-searches", d.Auth(), h.SavedSearch.Create)
+		users.PUT("/me/saved-searches/:id", d.Auth(), h.SavedSearch.Update)
+		users.DELETE("/me/saved-searches/:id", d.Auth(), h.SavedSearch.Delete)
+		users.GET("/me/saved-searches/:id/results", d.Auth(), h.SavedSearch.Results)
+		users.GET("/me/settings", d.Auth(), h.Settings.Get)
+		users.PUT("/me/settings", d.Auth(), h.Settings.Update)
+		users.GET("/:id", d.OptionalAuth(), h.User.GetByID)
+		users.PUT("/:id", d.Auth(), h.User.Update)
+		users.GET("/:id/articles", h.User.GetUserArticles)
+		users.GET("/:id/articles/search", h.Search.SearchUserArticles)
+	}
+}
@@ -0,0 +1,80 @@
+// Package sanitize removes unsafe HTML from user content before it is stored.
+// Content may be HTML or markdown; markdown is text to the policies and passes
+// through, apart from links to script URLs.
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+var (
+	articlePolicy = newArticlePolicy()
+	commentPolicy = newCommentPolicy()
+	textPolicy    = bluemonday.StrictPolicy()
+
+	// markdownScriptLink matches the destination of inline and reference-style
+	// markdown links that use a scheme able to run script
+	markdownScriptLink = regexp.MustCompile(`(?im)(\]\(\s*<?|^\s*\[[^\]]+\]:\s*<?)\s*(javascript|vbscript|data):`)
+
+	// textEscaper escapes the characters that are significant in HTML text
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;")
+)
+
+// Article sanitizes article content. Authors are trusted with rich formatting
+// such as headings, tables and images, and their links may be followed.
+func Article(content string) string {
+	return sanitize(articlePolicy, content)
+}
+
+// Comment sanitizes comment content. Commenters get basic formatting and links,
+// which are marked nofollow.
+func Comment(content string) string {
+	return sanitize(commentPolicy, content)
+}
+
+// Text strips all markup, for plain-text fields such as excerpts
+func Text(content string) string {
+	return sanitize(textPolicy, content)
+}
+
+func newArticlePolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(false)
+	// Highlighted code blocks are written as <code class="language-go">
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+func newCommentPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	p.AllowAttrs("href").OnElements("a")
+	p.RequireNoFollowOnLinks(true)
+	p.AllowElements("p", "br", "b", "strong", "i", "em", "del", "code", "pre", "blockquote", "ul", "ol", "li")
+	return p
+}
+
+// sanitize applies the policy and then rewrites the text between tags. The
+// policy escapes '>' and quotes in text, which HTML does not require and which
+// would break markdown blockquotes; only '&' and '<' are kept escaped.
+func sanitize(p *bluemonday.Policy, content string) string {
+	clean := p.Sanitize(strings.TrimSpace(content))
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(clean))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(b.String())
+		case html.TextToken:
+			text := markdownScriptLink.ReplaceAllString(string(z.Text()), "${1}#")
+			b.WriteString(textEscaper.Replace(text))
+		default:
+			b.Write(z.Raw())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+// xssVectors must not survive any policy
+var xssVectors = []string{
+	`<script>alert(1)</script>`,
+	`<img src=x onerror=alert(1)>`,
+	`<a href="javascript:alert(1)">x</a>`,
+	`<a href="JaVaScRiPt:alert(1)">x</a>`,
+	`<a href="&#106;avascript:alert(1)">x</a>`,
+	`<svg onload=alert(1)>`,
+	`<iframe src="https://evil.example.com"></iframe>`,
+	`<div style="background:url(javascript:alert(1))">x</div>`,
+	`<body onload=alert(1)>`,
+	`<scr<script>ipt>alert(1)</script>`,
+	`&lt;script&gt;alert(1)&lt;/script&gt;`,
+	`<p title="x&quot; onmouseover=&quot;alert(1)">x</p>`,
+	`<object data="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg=="></object>`,
+	`[click](javascript:alert(1))`,
+	`[click]( JAVASCRIPT:alert(1))`,
+	"[click][1]\n\n[1]: javascript:alert(1)",
+	`![img](data:text/html;base64,PHNjcmlwdD4=)`,
+}
+
+func TestPoliciesRemoveXSS(t *testing.T) {
+	policies := map[string]func(string) string{"Article": Article, "Comment": Comment, "Text": Text}
+	for name, sanitize := range policies {
+		for _, vector := range xssVectors {
+			got := strings.ToLower(sanitize(vector))
+			for _, unsafe := range []string{"<script", "onerror", "onload", "onmouseover", "javascript:", "<iframe", "<svg", "<object", "style=", "data:text"} {
+				if strings.Contains(got, unsafe) {
+					t.Errorf("%s(%q) = %q, expected no %q", name, vector, got, unsafe)
+				}
+			}
+		}
+	}
+}
+
+func TestArticle(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`<h2>Intro</h2><p>Hello <strong>world</strong></p>`, `<h2>Intro</h2><p>Hello <strong>world</strong></p>`},
+		{`<a href="https://go.dev">Go</a>`, `<a href="https://go.dev">Go</a>`},
+		{`<img src="https://example.com/a.png" alt="A">`, `<img src="https://example.com/a.png" alt="A">`},
+		{`<pre><code class="language-go">if a < b && c > d {}</code></pre>`, `<pre><code class="language-go">if a &lt; b &amp;&amp; c > d {}</code></pre>`},
+		{`<code class="evil">x</code>`, `<code>x</code>`},
+		{"# Title\n\n> quoted \"text\" isn't escaped", "# Title\n\n> quoted \"text\" isn't escaped"},
+		{`<p onclick="alert(1)">Hi</p><script>alert(1)</script>`, `<p>Hi</p>`},
+	}
+
+	for _, tt := range tests {
+		if got := Article(tt.input); got != tt.expected {
+			t.Errorf("Article(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestComment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`Nice <em>post</em>!`, `Nice <em>post</em>!`},
+		{`<a href="https://go.dev">Go</a>`, `<a href="https://go.dev" rel="nofollow">Go</a>`},
+		{`<h1>Loud</h1><img src="https://example.com/a.png">`, `Loud`},
+		{`<table><tr><td>x</td></tr></table>`, `x`},
+		{"> I don't agree\n\n`a < b`", "> I don't agree\n\n`a &lt; b`"},
+		{`[docs](https://go.dev) and [x](javascript:alert(1))`, `[docs](https://go.dev) and [x](#alert(1))`},
+		{`  <script>alert(1)</script>  `, ``},
+	}
+
+	for _, tt := range tests {
+		if got := Comment(tt.input); got != tt.expected {
+			t.Errorf("Comment(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	if got := Text(`A <b>short</b> summary & more`); got != `A short summary &amp; more` {
+		t.Errorf("Expected markup to be stripped, got %q", got)
+	}
+}
+
+// BenchmarkArticle measures rendering a markdown article with inline HTML
+// through the article policy, which every article save goes through
+func BenchmarkArticle(b *testing.B) {
+	section := "## Section\n\nSome *markdown* text with a [link](https://example.com) and `code`.\n\n" +
+		"<p>Inline <strong>HTML</strong> with <img src=\"https://example.com/a.png\" alt=\"a\"></p>\n\n" +
+		"> A quote & an <em>emphasis</em>\n\n```go\nfmt.Println(\"hello\")\n```\n\n"
+	content := strings.Repeat(section, 50)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	for b.Loop() {
+		Article(content)
+	}
+}
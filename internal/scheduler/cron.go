@@ -0,0 +1,196 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes when a job runs next
+type Schedule interface {
+	// Next returns the first activation after t, or the zero time if there is none
+	Next(t time.Time) time.Time
+}
+
+// descriptors are the predefined schedules accepted in place of a cron expression
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// Parse parses a schedule: a standard five-field cron expression (minute, hour,
+// day of month, month, day of week), a descriptor such as @daily, or
+// "@every <duration>" for a fixed interval.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, fmt.Errorf("invalid interval in %q: %w", spec, err)
+		}
+		if interval < time.Second {
+			return nil, fmt.Errorf("interval in %q must be at least one second", spec)
+		}
+		return every{interval: interval}, nil
+	}
+	if expr, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var c cron
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute in %q: %w", spec, err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour in %q: %w", spec, err)
+	}
+	if c.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month in %q: %w", spec, err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month in %q: %w", spec, err)
+	}
+	if c.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid day of week in %q: %w", spec, err)
+	}
+	// 7 is an alias for Sunday
+	if c.dow&(1<<7) != 0 {
+		c.dow |= 1
+	}
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+	return c, nil
+}
+
+// every is a fixed interval schedule
+type every struct {
+	interval time.Duration
+}
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(e.interval).Truncate(time.Second)
+}
+
+// cron is a parsed cron expression; each field is a bit set of allowed values
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+// maxSearch bounds the search for the next activation, so expressions that never
+// match (such as February 30th) end instead of looping forever
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+func (c cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule for days: when both day of month and day of
+// week are restricted, either one matching is enough
+func (c cron) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseField parses one comma-separated cron field into a bit set
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		start, end := min, max
+		if rangePart != "*" {
+			low, high, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseValue(low, min, max, names); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(high, min, max, names); err != nil {
+					return 0, err
+				}
+				if end < start {
+					return 0, fmt.Errorf("invalid range %q", rangePart)
+				}
+			} else if hasStep {
+				end = max // "5/15" means from 5 to the end in steps of 15
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single number or name within [min, max]
+func parseValue(value string, min, max int, names map[string]int) (int, error) {
+	if n, ok := names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+	}
+	return n, nil
+}
@@ -0,0 +1,73 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNext(t *testing.T) {
+	// A Wednesday
+	from := time.Date(2024, time.January, 17, 10, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 17, 10, 31, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, time.January, 18, 3, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 17, 10, 45, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, time.January, 17, 10, 45, 0, 0, time.UTC)},
+		{"0 9-17 * * mon-fri", time.Date(2024, time.January, 17, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * sun", time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 feb *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either matches
+		{"0 0 20 * mon", time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.January, 18, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 17, 11, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90m", time.Date(2024, time.January, 17, 12, 0, 15, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestParseNeverMatches(t *testing.T) {
+	schedule, err := Parse("0 0 30 feb *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"@every",
+		"@every soon",
+		"@every 10ms",
+		"@fortnightly",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// Package scheduler runs recurring jobs on cron schedules and records the
+// history of every run.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+var (
+	// ErrJobNotFound is returned for names no job is registered under
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when a job is started while it is still running
+	ErrJobRunning = errors.New("job is already running")
+)
+
+// Job is a recurring task
+type Job struct {
+	Name     string
+	Schedule string        // cron expression, descriptor such as @daily, or "@every 1h"
+	Jitter   time.Duration // random delay of up to Jitter before each scheduled run
+	Enabled  bool
+	// Run performs the job and returns a short summary for the run history.
+	// The context is cancelled when the scheduler stops.
+	Run func(ctx context.Context) (string, error)
+}
+
+// JobStatus describes a registered job
+type JobStatus struct {
+	Name     string         `json:"name"`
+	Schedule string         `json:"schedule"`
+	Enabled  bool           `json:"enabled"`
+	Running  bool           `json:"running"`
+	NextRun  *time.Time     `json:"next_run,omitempty"`
+	LastRun  *models.JobRun `json:"last_run,omitempty"`
+}
+
+// entry is a registered job with its parsed schedule and state
+type entry struct {
+	job      Job
+	schedule Schedule
+	enabled  atomic.Bool
+	running  atomic.Bool
+	next     atomic.Pointer[time.Time]
+}
+
+// Scheduler runs registered jobs on their schedules. A job never overlaps with
+// itself: a run that is due while the previous one is still going is skipped.
+type Scheduler struct {
+	runs    repositories.JobRunRepository
+	entries map[string]*entry
+	mu      sync.RWMutex
+
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	started bool
+}
+
+// New creates a scheduler recording run history in runs
+func New(runs repositories.JobRunRepository) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{
+		runs:    runs,
+		entries: make(map[string]*entry),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Add registers a job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return errors.New("job needs a name and a run function")
+	}
+	schedule, err := Parse(job.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", job.Name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("job %s: scheduler already started", job.Name)
+	}
+	if _, exists := s.entries[job.Name]; exists {
+		return fmt.Errorf("job %s is already registered", job.Name)
+	}
+
+	e := &entry{job: job, schedule: schedule}
+	e.enabled.Store(job.Enabled)
+	s.entries[job.Name] = e
+	return nil
+}
+
+// Start runs every registered job on its schedule until Stop is called
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+
+	for _, e := range s.entries {
+		s.wg.Add(1)
+		go s.loop(e)
+	}
+}
+
+// Stop stops scheduling, cancels running jobs and waits for them to return
+func (s *Scheduler) Stop() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+// Jobs describes every registered job, ordered by name
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]JobStatus, 0, len(s.entries))
+	for _, e := range s.entries {
+		statuses = append(statuses, s.status(e))
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Job describes the job registered under name
+func (s *Scheduler) Job(name string) (JobStatus, error) {
+	e, err := s.entry(name)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	return s.status(e), nil
+}
+
+// SetEnabled enables or disables the scheduled runs of a job until the next
+// restart. Disabled jobs can still be run by hand.
+func (s *Scheduler) SetEnabled(name string, enabled bool) (JobStatus, error) {
+	e, err := s.entry(name)
+	if err != nil {
+		return JobStatus{}, err
+	}
+	e.enabled.Store(enabled)
+	return s.status(e), nil
+}
+
+// RunNow runs a job immediately and returns the recorded run
+func (s *Scheduler) RunNow(name string) (*models.JobRun, error) {
+	e, err := s.entry(name)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(e, models.JobRunManual)
+}
+
+// Runs lists the most recent runs of a job, newest first
+func (s *Scheduler) Runs(name string, limit int) ([]models.JobRun, error) {
+	if _, err := s.entry(name); err != nil {
+		return nil, err
+	}
+	return s.runs.ListByJob(name, limit)
+}
+
+// loop waits for each activation of the job and runs it
+func (s *Scheduler) loop(e *entry) {
+	defer s.wg.Done()
+
+	for {
+		next := e.schedule.Next(time.Now())
+		if next.IsZero() {
+			slog.Warn("Job has no upcoming run; not scheduling it", "job", e.job.Name)
+			return
+		}
+		if e.job.Jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(e.job.Jitter))))
+		}
+		e.next.Store(&next)
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		if !e.enabled.Load() {
+			continue
+		}
+		if _, err := s.run(e, models.JobRunScheduled); err != nil && !errors.Is(err, ErrJobRunning) {
+			slog.Error("Job run not recorded", "job", e.job.Name, "error", err)
+		}
+	}
+}
+
+// run runs the job once, unless it is already running, and records the run
+func (s *Scheduler) run(e *entry, trigger models.JobRunTrigger) (*models.JobRun, error) {
+	if !e.running.CompareAndSwap(false, true) {
+		return nil, ErrJobRunning
+	}
+	defer e.running.Store(false)
+
+	run := &models.JobRun{Job: e.job.Name, Trigger: trigger, StartedAt: time.Now()}
+	summary, err := safeRun(s.ctx, e.job.Run)
+	run.FinishedAt = time.Now()
+	run.DurationMS = run.FinishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Summary = truncate(summary, 500)
+	run.Status = models.JobRunSucceeded
+	if err != nil {
+		run.Status = models.JobRunFailed
+		run.Error = err.Error()
+		slog.Error("Job failed", "job", e.job.Name, "error", err)
+	}
+
+	if err := s.runs.Create(run); err != nil {
+		return run, fmt.Errorf("failed to record run: %w", err)
+	}
+	return run, nil
+}
+
+// status describes e, including its latest recorded run
+func (s *Scheduler) status(e *entry) JobStatus {
+	status := JobStatus{
+		Name:     e.job.Name,
+		Schedule: e.job.Schedule,
+		Enabled:  e.enabled.Load(),
+		Running:  e.running.Load(),
+	}
+	if next := e.next.Load(); next != nil && status.Enabled {
+		status.NextRun = next
+	}
+	if last, err := s.runs.GetLatest(e.job.Name); err == nil {
+		status.LastRun = last
+	}
+	return status
+}
+
+// entry returns the job registered under name
+func (s *Scheduler) entry(name string) (*entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	e, ok := s.entries[name]
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return e, nil
+}
+
+// safeRun runs a job, turning a panic into an error so one broken job cannot
+// take the server down
+func safeRun(ctx context.Context, run func(ctx context.Context) (string, error)) (summary string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return run(ctx)
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
@@ -0,0 +1,367 @@
+// Package seed fills a database with large amounts of realistic looking data,
+// to check indexes and pagination hold up before real data grows that large.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+	"go-blog/internal/utils"
+)
+
+// Password is the password of every generated user
+const Password = "password123"
+
+// Options sizes the generated data
+type Options struct {
+	Users      int
+	Articles   int
+	Comments   int
+	Categories int
+	Tags       int
+	BatchSize  int   // rows per insert
+	Days       int   // how far back creation dates go
+	Seed       int64 // seeds the random distributions
+}
+
+// DefaultOptions generates a hundred thousand articles with ten comments each
+// on average
+var DefaultOptions = Options{
+	Users:      10000,
+	Articles:   100000,
+	Comments:   1000000,
+	Categories: 30,
+	Tags:       500,
+	BatchSize:  1000,
+	Days:       3 * 365,
+	Seed:       1,
+}
+
+// Result counts the generated rows
+type Result struct {
+	Users      int `json:"users"`
+	Articles   int `json:"articles"`
+	Comments   int `json:"comments"`
+	Categories int `json:"categories"`
+	Tags       int `json:"tags"`
+}
+
+// Progress is called after every batch with the rows of table inserted so far
+type Progress func(table string, done, total int)
+
+// vocabulary the titles and content are made of
+var vocabulary = strings.Fields(`go golang server database index query cache
+	latency pagination request response handler middleware router service
+	repository model migration schema transaction benchmark profile memory
+	goroutine channel context deadline error retry timeout queue worker
+	deploy container cluster metric trace log config secret token session
+	article comment author reader blog post draft publish archive search
+	the a an of to in for with on at by from and or but fast slow simple
+	better faster scalable reliable practical modern effective building
+	writing testing designing debugging measuring tuning understanding`)
+
+// generator draws the generated rows from skewed distributions: a few authors
+// write most articles, a few articles draw most comments and readers, and
+// recent days see more articles than older ones
+type generator struct {
+	db   *database.DB
+	opts Options
+	rng  *rand.Rand
+	now  time.Time
+	run  string // makes unique names unique across runs
+	hash string
+
+	userIDs     []uint
+	categoryIDs []uint
+	tags        []models.Tag
+	published   []publishedArticle // in random order, so popularity is not tied to age
+}
+
+type publishedArticle struct {
+	id      uint
+	created time.Time
+}
+
+// Generate inserts the rows opts asks for through db in batches, reporting
+// progress when not nil. Generated users, categories and tags get names unique
+// to the run, so it may be run again on the same database.
+func Generate(db *database.DB, opts Options, progress Progress) (*Result, error) {
+	if opts.Users < 1 || opts.Categories < 1 || opts.Tags < 1 {
+		return nil, errors.New("at least one user, category and tag are needed")
+	}
+	if opts.Articles < 0 || opts.Comments < 0 {
+		return nil, errors.New("article and comment counts must not be negative")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultOptions.BatchSize
+	}
+	if opts.Days <= 0 {
+		opts.Days = DefaultOptions.Days
+	}
+	if progress == nil {
+		progress = func(string, int, int) {}
+	}
+
+	hash, err := utils.HashPassword(Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	g := &generator{
+		db:   db,
+		opts: opts,
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+		now:  time.Now(),
+		run:  strconv.FormatInt(time.Now().Unix(), 36),
+		hash: hash,
+	}
+
+	steps := []func(Progress) error{g.createUsers, g.createCategories, g.createTags, g.createArticles, g.createComments}
+	for _, step := range steps {
+		if err := step(progress); err != nil {
+			return nil, err
+		}
+	}
+	return &Result{
+		Users:      opts.Users,
+		Articles:   opts.Articles,
+		Comments:   opts.Comments,
+		Categories: opts.Categories,
+		Tags:       opts.Tags,
+	}, nil
+}
+
+func (g *generator) createUsers(progress Progress) error {
+	g.userIDs = make([]uint, 0, g.opts.Users)
+	return inBatches(g.opts.Users, g.opts.BatchSize, func(from, to int) error {
+		users := make([]models.User, 0, to-from)
+		for i := from; i < to; i++ {
+			name := fmt.Sprintf("load_%s_%d", g.run, i)
+			users = append(users, models.User{
+				Username:  name,
+				Email:     name + "@example.com",
+				Password:  g.hash,
+				Bio:       g.sentence(8, 20),
+				CreatedAt: g.createdAt(),
+			})
+		}
+		if err := g.db.BulkCreate(&users, len(users)); err != nil {
+			return fmt.Errorf("failed to create users: %w", err)
+		}
+		for _, user := range users {
+			g.userIDs = append(g.userIDs, user.ID)
+		}
+		progress("users", to, g.opts.Users)
+		return nil
+	})
+}
+
+func (g *generator) createCategories(progress Progress) error {
+	categories := make([]models.Category, 0, g.opts.Categories)
+	for i := 0; i < g.opts.Categories; i++ {
+		name := fmt.Sprintf("Load %s %d", g.run, i)
+		categories = append(categories, models.Category{Name: name, Slug: utils.GenerateSlug(name)})
+	}
+	if err := g.db.BulkCreate(&categories, g.opts.BatchSize); err != nil {
+		return fmt.Errorf("failed to create categories: %w", err)
+	}
+	for _, category := range categories {
+		g.categoryIDs = append(g.categoryIDs, category.ID)
+	}
+	progress("categories", len(categories), g.opts.Categories)
+	return nil
+}
+
+func (g *generator) createTags(progress Progress) error {
+	g.tags = make([]models.Tag, 0, g.opts.Tags)
+	for i := 0; i < g.opts.Tags; i++ {
+		name := fmt.Sprintf("load-%s-%d", g.run, i)
+		g.tags = append(g.tags, models.Tag{Name: name, Slug: name})
+	}
+	if err := g.db.BulkCreate(&g.tags, g.opts.BatchSize); err != nil {
+		return fmt.Errorf("failed to create tags: %w", err)
+	}
+	progress("tags", len(g.tags), g.opts.Tags)
+	return nil
+}
+
+func (g *generator) createArticles(progress Progress) error {
+	authors := g.zipf(len(g.userIDs))
+	categories := g.zipf(len(g.categoryIDs))
+	tags := g.zipf(len(g.tags))
+
+	err := inBatches(g.opts.Articles, g.opts.BatchSize, func(from, to int) error {
+		articles := make([]models.Article, 0, to-from)
+		for i := from; i < to; i++ {
+			title := g.sentence(4, 10)
+			created := g.createdAt()
+			article := models.Article{
+				Title:      title,
+				Slug:       fmt.Sprintf("%s-%s-%d", utils.GenerateSlug(title), g.run, i),
+				Content:    g.content(),
+				Excerpt:    g.sentence(15, 30),
+				AuthorID:   g.userIDs[authors.Uint64()],
+				Status:     g.status(),
+				Visibility: g.visibility(),
+				ViewCount:  uint(math.Exp(g.rng.NormFloat64()*1.5 + 5)),
+				CreatedAt:  created,
+				UpdatedAt:  created,
+			}
+			if g.rng.Float64() < 0.9 {
+				article.CategoryID = &g.categoryIDs[categories.Uint64()]
+			}
+			if article.Status != models.StatusDraft {
+				article.PublishedAt = &created
+			}
+			seen := map[uint64]bool{}
+			for n := g.rng.Intn(5); n > 0; n-- {
+				if index := tags.Uint64(); !seen[index] {
+					seen[index] = true
+					article.Tags = append(article.Tags, g.tags[index])
+				}
+			}
+			articles = append(articles, article)
+		}
+		if err := g.db.BulkCreate(&articles, len(articles)); err != nil {
+			return fmt.Errorf("failed to create articles: %w", err)
+		}
+		for _, article := range articles {
+			if article.Status == models.StatusPublished {
+				g.published = append(g.published, publishedArticle{id: article.ID, created: article.CreatedAt})
+			}
+		}
+		progress("articles", to, g.opts.Articles)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.rng.Shuffle(len(g.published), func(i, j int) {
+		g.published[i], g.published[j] = g.published[j], g.published[i]
+	})
+	return nil
+}
+
+func (g *generator) createComments(progress Progress) error {
+	if g.opts.Comments == 0 {
+		return nil
+	}
+	if len(g.published) == 0 {
+		return errors.New("comments need published articles")
+	}
+
+	articles := g.zipf(len(g.published))
+	latest := make(map[uint]uint) // latest comment of each article, which replies answer
+	err := inBatches(g.opts.Comments, g.opts.BatchSize, func(from, to int) error {
+		comments := make([]models.Comment, 0, to-from)
+		for i := from; i < to; i++ {
+			article := g.published[articles.Uint64()]
+			age := g.now.Sub(article.created)
+			created := article.created.Add(time.Duration(g.rng.Float64() * g.rng.Float64() * float64(age)))
+			comment := models.Comment{
+				ArticleID: article.id,
+				UserID:    g.userIDs[g.rng.Intn(len(g.userIDs))],
+				Content:   g.sentence(5, 60),
+				CreatedAt: created,
+				UpdatedAt: created,
+			}
+			if parentID, ok := latest[article.id]; ok && g.rng.Float64() < 0.3 {
+				comment.ParentID = &parentID
+			}
+			comments = append(comments, comment)
+		}
+		if err := g.db.BulkCreate(&comments, len(comments)); err != nil {
+			return fmt.Errorf("failed to create comments: %w", err)
+		}
+		for _, comment := range comments {
+			latest[comment.ArticleID] = comment.ID
+		}
+		progress("comments", to, g.opts.Comments)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Keep the denormalized counts in line with the generated comments
+	return g.db.DB.Exec(`UPDATE articles SET comment_count =
+		(SELECT COUNT(*) FROM comments WHERE comments.article_id = articles.id AND comments.deleted_at IS NULL)
+		WHERE id IN (SELECT DISTINCT article_id FROM comments)`).Error
+}
+
+// zipf draws indexes below n, the first ones far more often than the last
+func (g *generator) zipf(n int) *rand.Zipf {
+	return rand.NewZipf(g.rng, 1.1, 1, uint64(n-1))
+}
+
+// createdAt draws a creation date within the configured days, recent ones
+// more often
+func (g *generator) createdAt() time.Time {
+	age := g.rng.Float64() * g.rng.Float64() * float64(g.opts.Days) * float64(24*time.Hour)
+	return g.now.Add(-time.Duration(age))
+}
+
+// status draws a status: most articles are published
+func (g *generator) status() models.ArticleStatus {
+	switch p := g.rng.Float64(); {
+	case p < 0.85:
+		return models.StatusPublished
+	case p < 0.95:
+		return models.StatusDraft
+	default:
+		return models.StatusArchived
+	}
+}
+
+// visibility draws a visibility: most articles are public
+func (g *generator) visibility() models.ArticleVisibility {
+	switch p := g.rng.Float64(); {
+	case p < 0.9:
+		return models.ArticleVisibilityPublic
+	case p < 0.97:
+		return models.ArticleVisibilityMembers
+	default:
+		return models.ArticleVisibilityPremium
+	}
+}
+
+// sentence returns between least and most words of the vocabulary, capitalized
+func (g *generator) sentence(least, most int) string {
+	words := make([]string, least+g.rng.Intn(most-least+1))
+	for i := range words {
+		words[i] = vocabulary[g.rng.Intn(len(vocabulary))]
+	}
+	words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+	return strings.Join(words, " ")
+}
+
+// content returns markdown paragraphs under a few headings, most articles
+// short and some long
+func (g *generator) content() string {
+	var b strings.Builder
+	paragraphs := 2 + int(math.Exp(g.rng.NormFloat64()*0.6+1.5))
+	for i := 0; i < paragraphs; i++ {
+		if i%4 == 0 {
+			b.WriteString("## " + g.sentence(2, 6) + "\n\n")
+		}
+		b.WriteString(g.sentence(30, 90) + ".\n\n")
+	}
+	return b.String()
+}
+
+// inBatches calls insert with the bounds of consecutive batches of total rows
+func inBatches(total, size int, insert func(from, to int) error) error {
+	for from := 0; from < total; from += size {
+		if err := insert(from, min(from+size, total)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
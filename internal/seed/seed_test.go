@@ -0,0 +1,73 @@
+package seed
+
+import (
+	"testing"
+
+	"go-blog/internal/database"
+	"go-blog/internal/models"
+)
+
+func TestGenerate(t *testing.T) {
+	db, err := database.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer database.CleanupTestDB(db)
+
+	opts := Options{Users: 20, Articles: 300, Comments: 1000, Categories: 5, Tags: 20, BatchSize: 64, Days: 30, Seed: 7}
+	var batches int
+	result, err := Generate(db, opts, func(table string, done, total int) {
+		if table == "comments" {
+			batches++
+		}
+	})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	if result.Articles != 300 || result.Comments != 1000 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if batches != 16 {
+		t.Errorf("Expected comments to be inserted in 16 batches, got %d", batches)
+	}
+
+	var users, articles, comments int64
+	db.Model(&models.User{}).Count(&users)
+	db.Model(&models.Article{}).Count(&articles)
+	db.Model(&models.Comment{}).Count(&comments)
+	if users != 20 || articles != 300 || comments != 1000 {
+		t.Errorf("Expected 20 users, 300 articles and 1000 comments, got %d, %d and %d", users, articles, comments)
+	}
+
+	// Comment counts match the comments, which only published articles get
+	var mismatched int64
+	db.Model(&models.Article{}).
+		Where("comment_count <> (SELECT COUNT(*) FROM comments WHERE comments.article_id = articles.id)").
+		Count(&mismatched)
+	if mismatched != 0 {
+		t.Errorf("Expected comment counts to match, %d articles differ", mismatched)
+	}
+	var onUnpublished int64
+	db.Model(&models.Comment{}).
+		Joins("JOIN articles ON articles.id = comments.article_id").
+		Where("articles.status <> ?", models.StatusPublished).
+		Count(&onUnpublished)
+	if onUnpublished != 0 {
+		t.Errorf("Expected comments on published articles only, %d are not", onUnpublished)
+	}
+	var strayReplies int64
+	db.Table("comments AS replies").
+		Joins("JOIN comments AS parents ON parents.id = replies.parent_id").
+		Where("parents.article_id <> replies.article_id").
+		Count(&strayReplies)
+	if strayReplies != 0 {
+		t.Errorf("Expected replies to answer comments of their article, %d do not", strayReplies)
+	}
+
+	// A few authors write most articles
+	var top struct{ Count int64 }
+	db.Model(&models.Article{}).Select("COUNT(*) AS count").Group("author_id").Order("count DESC").Limit(1).Scan(&top)
+	if top.Count < 300/20*3 {
+		t.Errorf("Expected the most prolific author to write far more than average, got %d articles", top.Count)
+	}
+}
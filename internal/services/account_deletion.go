@@ -0,0 +1,165 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/utils"
+)
+
+// DeleteAccountRequest chooses what happens to the content of a deleted account.
+// Password is required when users delete their own account.
+type DeleteAccountRequest struct {
+	Password   string               `json:"password,omitempty"`
+	Policy     models.ContentPolicy `json:"policy" validate:"required,oneof=delete transfer anonymize"`
+	TransferTo uint                 `json:"transfer_to,omitempty"` // receiving author for the transfer policy
+}
+
+// DeleteOwnAccount deletes the user's own account after checking their password
+func (s *UserService) DeleteOwnAccount(userID uint, req *DeleteAccountRequest) (*repositories.AccountDeletionResult, error) {
+	if req == nil || req.Password == "" {
+		return nil, validationError("password is required")
+	}
+
+	user, err := s.getDeletableUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !utils.CheckPassword(req.Password, user.Password) {
+		return nil, unauthorizedError("password is incorrect")
+	}
+
+	return s.deleteAccount(userID, user, req)
+}
+
+// DeleteAccount deletes another user's account on behalf of the administrator actorID
+func (s *UserService) DeleteAccount(actorID, userID uint, req *DeleteAccountRequest) (*repositories.AccountDeletionResult, error) {
+	if req == nil {
+		return nil, validationError("deletion request is required")
+	}
+
+	user, err := s.getDeletableUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deleteAccount(actorID, user, req)
+}
+
+// getDeletableUser loads a user that may be deleted; system accounts may not
+func (s *UserService) getDeletableUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, err
+	}
+	if user.Role == models.RoleSystem {
+		return nil, validationError("system accounts cannot be deleted")
+	}
+	return user, nil
+}
+
+// deleteAccount resolves the content policy into new owners and deletes the
+// account in one audited transaction
+func (s *UserService) deleteAccount(actorID uint, user *models.User, req *DeleteAccountRequest) (*repositories.AccountDeletionResult, error) {
+	deletion := &repositories.AccountDeletion{UserID: user.ID}
+
+	switch req.Policy {
+	case models.ContentPolicyDelete:
+	case models.ContentPolicyTransfer:
+		if req.TransferTo == 0 || req.TransferTo == user.ID {
+			return nil, validationError("transfer_to must be another user's ID")
+		}
+		if _, err := s.userRepo.GetByID(req.TransferTo); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return nil, validationError("transfer target user not found")
+			}
+			return nil, err
+		}
+		anonymous, err := s.anonymousUser()
+		if err != nil {
+			return nil, err
+		}
+		deletion.ArticleOwnerID = req.TransferTo
+		deletion.CommentOwnerID = anonymous.ID
+	case models.ContentPolicyAnonymize:
+		anonymous, err := s.anonymousUser()
+		if err != nil {
+			return nil, err
+		}
+		deletion.ArticleOwnerID = anonymous.ID
+		deletion.CommentOwnerID = anonymous.ID
+	default:
+		return nil, validationError("policy must be one of delete, transfer or anonymize")
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"username":    user.Username,
+		"policy":      req.Policy,
+		"transfer_to": req.TransferTo,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit details: %w", err)
+	}
+	deletion.Audit = &models.AuditLog{
+		ActorID:    actorID,
+		Action:     models.AuditUserDelete,
+		TargetType: "user",
+		TargetID:   user.ID,
+		Details:    string(details),
+	}
+
+	result, err := s.userRepo.DeleteAccount(deletion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	// Files live outside the transaction; the account is gone either way
+	if user.AvatarKey != "" && s.storage != nil {
+		s.deleteAvatarFiles(user.AvatarKey)
+	}
+
+	return result, nil
+}
+
+// anonymousUser returns the system account that owns anonymized content, creating
+// it on first use. It has an unusable random password and cannot sign in.
+func (s *UserService) anonymousUser() (*models.User, error) {
+	user, err := s.userRepo.GetByUsername(models.AnonymousUsername)
+	if err == nil {
+		if user.Role != models.RoleSystem {
+			return nil, fmt.Errorf("username %q belongs to a regular account", models.AnonymousUsername)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+	hashedPassword, err := utils.HashPassword(hex.EncodeToString(secret))
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	user = &models.User{
+		Username: models.AnonymousUsername,
+		Email:    models.AnonymousUsername + "@users.invalid",
+		Password: hashedPassword,
+		Role:     models.RoleSystem,
+	}
+	if err := s.userRepo.Create(user); err != nil {
+		return nil, fmt.Errorf("failed to create anonymous user: %w", err)
+	}
+	return user, nil
+}
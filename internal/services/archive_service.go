@@ -2,6 +2,7 @@ package services
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"go-blog/internal/models"
@@ -10,10 +11,10 @@ import (
 
 // ArchiveEntry represents a single archive entry with date and count
 type ArchiveEntry struct {
-	Year         int   `json:"year"`
-	Month        int   `json:"month"`
+	Year         int    `json:"year"`
+	Month        int    `json:"month"`
 	MonthName    string `json:"month_name"`
-	ArticleCount int64 `json:"article_count"`
+	ArticleCount int64  `json:"article_count"`
 }
 
 // ArchiveYear represents a year with its months
@@ -29,6 +30,22 @@ type ArchiveResponse struct {
 	Total int64         `json:"total"`
 }
 
+// ArchiveDay represents a day of a month with published articles
+type ArchiveDay struct {
+	Day          int    `json:"day"`
+	Date         string `json:"date"` // YYYY-MM-DD
+	ArticleCount int64  `json:"article_count"`
+}
+
+// ArchiveCalendar represents the days of a month that have published articles
+type ArchiveCalendar struct {
+	Year      int          `json:"year"`
+	Month     int          `json:"month"`
+	MonthName string       `json:"month_name"`
+	Days      []ArchiveDay `json:"days"`
+	Total     int64        `json:"total"`
+}
+
 // ArchiveService handles archive-related operations
 type ArchiveService struct {
 	articleRepo repositories.ArticleRepository
@@ -41,45 +58,52 @@ func NewArchiveService(articleRepo repositories.ArticleRepository) *ArchiveServi
 	}
 }
 
-// GetArchive returns the complete archive structure with year/month organization
+// GetArchive returns the complete archive structure with year/month organization.
+// Years and months are sorted newest first.
 func (s *ArchiveService) GetArchive() (*ArchiveResponse, error) {
 	// Get all published articles grouped by year and month
-	archiveData, err := s.articleRepo.GetArchive()
+	entries, err := s.articleRepo.GetArchive()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get archive data: %w", err)
 	}
 
-	// Convert the raw data to structured response
 	response := &ArchiveResponse{
 		Years: []ArchiveYear{},
 		Total: 0,
 	}
 
-	// Process the archive data
-	if yearData, ok := archiveData["years"].([]map[string]interface{}); ok {
-		for _, year := range yearData {
-			archiveYear := ArchiveYear{
-				Year:   int(year["year"].(int64)),
+	yearIndex := make(map[int]int)
+	for _, row := range entries {
+		idx, exists := yearIndex[row.Year]
+		if !exists {
+			response.Years = append(response.Years, ArchiveYear{
+				Year:   row.Year,
 				Months: []ArchiveEntry{},
-				Total:  0,
-			}
+			})
+			idx = len(response.Years) - 1
+			yearIndex[row.Year] = idx
+		}
 
-			if monthData, ok := year["months"].([]map[string]interface{}); ok {
-				for _, month := range monthData {
-					entry := ArchiveEntry{
-						Year:         archiveYear.Year,
-						Month:        int(month["month"].(int64)),
-						MonthName:    s.getMonthName(int(month["month"].(int64))),
-						ArticleCount: month["count"].(int64),
-					}
-					archiveYear.Months = append(archiveYear.Months, entry)
-					archiveYear.Total += entry.ArticleCount
-				}
-			}
+		archiveYear := &response.Years[idx]
+		archiveYear.Months = append(archiveYear.Months, ArchiveEntry{
+			Year:         row.Year,
+			Month:        row.Month,
+			MonthName:    s.getMonthName(row.Month),
+			ArticleCount: row.Count,
+		})
+		archiveYear.Total += row.Count
+		response.Total += row.Count
+	}
 
-			response.Years = append(response.Years, archiveYear)
-			response.Total += archiveYear.Total
-		}
+	// Sort deterministically regardless of the order rows were returned in
+	sort.Slice(response.Years, func(i, j int) bool {
+		return response.Years[i].Year > response.Years[j].Year
+	})
+	for i := range response.Years {
+		months := response.Years[i].Months
+		sort.Slice(months, func(a, b int) bool {
+			return months[a].Month > months[b].Month
+		})
 	}
 
 	return response, nil
@@ -88,11 +112,11 @@ func (s *ArchiveService) GetArchive() (*ArchiveResponse, error) {
 // GetArchiveByMonth returns articles for a specific year and month
 func (s *ArchiveService) GetArchiveByMonth(year, month, page, limit int) ([]models.Article, int64, error) {
 	if year < 1900 || year > time.Now().Year()+1 {
-		return nil, 0, fmt.Errorf("invalid year: %d", year)
+		return nil, 0, validationError("invalid year: %d", year)
 	}
 
 	if month < 1 || month > 12 {
-		return nil, 0, fmt.Errorf("invalid month: %d", month)
+		return nil, 0, validationError("invalid month: %d", month)
 	}
 
 	if page < 1 {
@@ -113,6 +137,60 @@ func (s *ArchiveService) GetArchiveByMonth(year, month, page, limit int) ([]mode
 	return articles, total, nil
 }
 
+// GetArchiveByDay returns articles published on a specific day
+func (s *ArchiveService) GetArchiveByDay(year, month, day, page, limit int) ([]models.Article, int64, error) {
+	if err := s.ValidateArchiveDay(year, month, day); err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	offset := (page - 1) * limit
+
+	articles, total, err := s.articleRepo.GetByDay(year, month, day, offset, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get articles for %d/%d/%d: %w", year, month, day, err)
+	}
+
+	return articles, total, nil
+}
+
+// GetCalendar returns the days of a month that have published articles, in
+// calendar order
+func (s *ArchiveService) GetCalendar(year, month int) (*ArchiveCalendar, error) {
+	if err := s.ValidateArchiveRequest(year, month); err != nil {
+		return nil, err
+	}
+
+	days, err := s.articleRepo.GetCalendar(year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar for %d/%d: %w", year, month, err)
+	}
+
+	calendar := &ArchiveCalendar{
+		Year:      year,
+		Month:     month,
+		MonthName: s.getMonthName(month),
+		Days:      make([]ArchiveDay, 0, len(days)),
+	}
+	for _, row := range days {
+		calendar.Days = append(calendar.Days, ArchiveDay{
+			Day:          row.Day,
+			Date:         fmt.Sprintf("%04d-%02d-%02d", year, month, row.Day),
+			ArticleCount: row.Count,
+		})
+		calendar.Total += row.Count
+	}
+
+	return calendar, nil
+}
+
 // GetArchiveStatistics returns statistics about the archive
 func (s *ArchiveService) GetArchiveStatistics() (map[string]interface{}, error) {
 	archive, err := s.GetArchive()
@@ -186,12 +264,27 @@ func (s *ArchiveService) ValidateArchiveRequest(year, month int) error {
 	currentYear := time.Now().Year()
 
 	if year < 1900 || year > currentYear+1 {
-		return fmt.Errorf("year must be between 1900 and %d", currentYear+1)
+		return validationError("year must be between 1900 and %d", currentYear+1)
 	}
 
 	if month < 1 || month > 12 {
-		return fmt.Errorf("month must be between 1 and 12")
+		return validationError("month must be between 1 and 12")
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ValidateArchiveDay validates the parameters of a day archive request
+func (s *ArchiveService) ValidateArchiveDay(year, month, day int) error {
+	if err := s.ValidateArchiveRequest(year, month); err != nil {
+		return err
+	}
+
+	// Day 0 of the next month is the last day of this one
+	daysInMonth := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day < 1 || day > daysInMonth {
+		return validationError("day must be between 1 and %d", daysInMonth)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"strings"
+
+	"go-blog/internal/models"
+	"go-blog/internal/sanitize"
+)
+
+// previewWords is how many words of an article readers without the membership
+// it needs get to see
+const previewWords = 50
+
+// restrictArticle cuts the content of article to a plain-text preview unless
+// viewer, nil for anonymous readers, is entitled to all of it
+func restrictArticle(article *models.Article, viewer *models.User) {
+	if article.ReadableBy(viewer) {
+		return
+	}
+	article.Locked = true
+	article.Content = contentPreview(article.Content)
+}
+
+// restrictArticles marks the articles viewer is not entitled to as locked and
+// cuts their content, when loaded, to a preview
+func restrictArticles(articles []models.Article, viewer *models.User) {
+	for i := range articles {
+		restrictArticle(&articles[i], viewer)
+	}
+}
+
+// validateVisibility checks an optional article visibility
+func validateVisibility(visibility string) error {
+	switch models.ArticleVisibility(visibility) {
+	case "", models.ArticleVisibilityPublic, models.ArticleVisibilityMembers, models.ArticleVisibilityPremium:
+		return nil
+	}
+	return validationError("visibility must be one of: public, members, premium")
+}
+
+// contentPreview returns the first previewWords words of content as text
+func contentPreview(content string) string {
+	words := strings.Fields(sanitize.Text(content))
+	if len(words) <= previewWords {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:previewWords], " ") + " …"
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// maxArticleMetaFields bounds the custom fields of an article
+const maxArticleMetaFields = 50
+
+// ArticleMetaField is a custom field to set on an article
+type ArticleMetaField struct {
+	Key   string          `json:"key" validate:"required,meta_key"`
+	Type  string          `json:"type" validate:"required,oneof=string number bool json"`
+	Value json.RawMessage `json:"value" validate:"required"`
+}
+
+// SetArticleMetaRequest sets custom fields of an article; fields not listed
+// keep their value
+type SetArticleMetaRequest struct {
+	Fields []ArticleMetaField `json:"fields" validate:"required,min=1,max=50,dive"`
+}
+
+// SetMetaRepository enables custom fields on articles
+func (s *ArticleService) SetMetaRepository(metaRepo repositories.ArticleMetaRepository) {
+	s.metaRepo = metaRepo
+}
+
+// GetMeta returns the custom fields of an article ordered by key. Custom fields
+// of unpublished articles are only returned to their author and admins.
+func (s *ArticleService) GetMeta(articleID uint, viewer *models.User) ([]models.ArticleMeta, error) {
+	article, err := s.metaArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != models.StatusPublished &&
+		(viewer == nil || (viewer.ID != article.AuthorID && !viewer.IsAdmin())) {
+		return nil, notFoundError("article not found")
+	}
+
+	fields, err := s.metaRepo.ListByArticle(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom fields: %w", err)
+	}
+	return fields, nil
+}
+
+// SetMeta sets custom fields of an article and returns all of them. Only its
+// author or an admin may.
+func (s *ArticleService) SetMeta(articleID uint, actor *models.User, req *SetArticleMetaRequest) ([]models.ArticleMeta, error) {
+	if req == nil || len(req.Fields) == 0 {
+		return nil, validationError("at least one custom field is required")
+	}
+	if err := s.checkMetaAccess(articleID, actor); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.metaRepo.ListByArticle(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom fields: %w", err)
+	}
+	keys := make(map[string]bool, len(existing)+len(req.Fields))
+	for _, field := range existing {
+		keys[field.Key] = true
+	}
+
+	fields := make([]models.ArticleMeta, len(req.Fields))
+	seen := make(map[string]bool, len(req.Fields))
+	for i, field := range req.Fields {
+		if seen[field.Key] {
+			return nil, validationError("custom field %s is listed twice", field.Key)
+		}
+		seen[field.Key] = true
+		keys[field.Key] = true
+
+		fields[i] = models.ArticleMeta{Key: field.Key, Type: models.ArticleMetaType(field.Type), Value: field.Value}
+		if err := fields[i].Validate(); err != nil {
+			var invalid models.ValidationErrors
+			if errors.As(err, &invalid) {
+				return nil, fieldValidationError(invalid)
+			}
+			return nil, validationError("%s", err.Error())
+		}
+	}
+	if len(keys) > maxArticleMetaFields {
+		return nil, validationError("articles can have at most %d custom fields", maxArticleMetaFields)
+	}
+
+	if err := s.metaRepo.Save(articleID, fields); err != nil {
+		return nil, fmt.Errorf("failed to save custom fields: %w", err)
+	}
+	return s.GetMeta(articleID, actor)
+}
+
+// DeleteMeta removes a custom field of an article. Only its author or an admin may.
+func (s *ArticleService) DeleteMeta(articleID uint, actor *models.User, key string) error {
+	if err := s.checkMetaAccess(articleID, actor); err != nil {
+		return err
+	}
+	if err := s.metaRepo.DeleteKey(articleID, key); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("custom field %s not found", key)
+		}
+		return fmt.Errorf("failed to delete custom field: %w", err)
+	}
+	return nil
+}
+
+// loadMeta sets the custom fields of article when they are enabled
+func (s *ArticleService) loadMeta(article *models.Article) error {
+	if s.metaRepo == nil {
+		return nil
+	}
+	fields, err := s.metaRepo.ListByArticle(article.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list custom fields: %w", err)
+	}
+	article.Meta = fields
+	return nil
+}
+
+// checkMetaAccess verifies that the article exists and that actor may change
+// its custom fields
+func (s *ArticleService) checkMetaAccess(articleID uint, actor *models.User) error {
+	article, err := s.metaArticle(articleID)
+	if err != nil {
+		return err
+	}
+	if article.AuthorID != actor.ID && !actor.IsAdmin() {
+		return forbiddenError("unauthorized: you can only change the custom fields of your own articles")
+	}
+	return nil
+}
+
+// metaArticle returns the article whose custom fields are read or changed
+func (s *ArticleService) metaArticle(articleID uint) (*models.Article, error) {
+	if s.metaRepo == nil {
+		return nil, errors.New("article meta repository not available")
+	}
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
+		}
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+	return article, nil
+}
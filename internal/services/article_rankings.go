@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"go-blog/internal/repositories"
+)
+
+// DefaultTrendingWindow is the age over which trending scores halve when none
+// is configured
+const DefaultTrendingWindow = 7 * 24 * time.Hour
+
+// SetArticleRankingRepository makes the popular and trending listings read
+// precomputed rankings, whose trending scores decay over trendingWindow,
+// instead of scoring articles on every request
+func (s *StatisticsService) SetArticleRankingRepository(rankingRepo repositories.ArticleRankingRepository, trendingWindow time.Duration) {
+	if trendingWindow <= 0 {
+		trendingWindow = DefaultTrendingWindow
+	}
+	s.rankingRepo = rankingRepo
+	s.trendingWindow = trendingWindow
+}
+
+// RefreshRankings recomputes the rankings of every published article and
+// returns how many are ranked
+func (s *StatisticsService) RefreshRankings() (int64, error) {
+	if s.rankingRepo == nil {
+		return 0, nil
+	}
+	ranked, err := s.rankingRepo.Refresh(time.Now(), s.trendingWindow)
+	if err != nil {
+		return 0, fmt.Errorf("failed to refresh article rankings: %w", err)
+	}
+	s.rankingsReady.Store(true)
+	return ranked, nil
+}
+
+// ensureRankings computes the rankings when there are none yet, so listings
+// are not empty until the refresh job first runs
+func (s *StatisticsService) ensureRankings() error {
+	if s.rankingsReady.Load() {
+		return nil
+	}
+	count, err := s.rankingRepo.Count()
+	if err != nil {
+		return fmt.Errorf("failed to count article rankings: %w", err)
+	}
+	if count > 0 {
+		s.rankingsReady.Store(true)
+		return nil
+	}
+	_, err = s.RefreshRankings()
+	return err
+}
+
+// rankedPopularArticles reads the most popular articles from the rankings
+func (s *StatisticsService) rankedPopularArticles(limit int) ([]*PopularArticle, error) {
+	if err := s.ensureRankings(); err != nil {
+		return nil, err
+	}
+	ranked, err := s.rankingRepo.Popular(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get popular articles: %w", err)
+	}
+
+	articles := make([]*PopularArticle, len(ranked))
+	for i, article := range ranked {
+		articles[i] = &PopularArticle{
+			ArticleID:    article.ArticleID,
+			Title:        article.Title,
+			Slug:         article.Slug,
+			ViewCount:    article.ViewCount,
+			LikeCount:    article.LikeCount,
+			CommentCount: article.CommentCount,
+			Score:        article.PopularityScore,
+			CreatedAt:    article.CreatedAt,
+		}
+	}
+	return articles, nil
+}
+
+// rankedTrendingArticles reads the trending articles published within the
+// last days from the rankings
+func (s *StatisticsService) rankedTrendingArticles(limit, days int) ([]*TrendingArticle, error) {
+	if err := s.ensureRankings(); err != nil {
+		return nil, err
+	}
+	ranked, err := s.rankingRepo.Trending(limit, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending articles: %w", err)
+	}
+
+	articles := make([]*TrendingArticle, len(ranked))
+	for i, article := range ranked {
+		articles[i] = &TrendingArticle{
+			ArticleID:     article.ArticleID,
+			Title:         article.Title,
+			Slug:          article.Slug,
+			ViewCount:     article.ViewCount,
+			LikeCount:     article.LikeCount,
+			CommentCount:  article.CommentCount,
+			TrendingScore: article.TrendingScore,
+			CreatedAt:     article.CreatedAt,
+		}
+	}
+	return articles, nil
+}
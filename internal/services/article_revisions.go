@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"go-blog/internal/diff"
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// RevisionComparison is the word-level difference between two revisions of an
+// article, the revisions given without their content
+type RevisionComparison struct {
+	From         models.ArticleRevision `json:"from"`
+	To           models.ArticleRevision `json:"to"`
+	Title        []diff.Block           `json:"title"`
+	Content      []diff.Block           `json:"content"`
+	WordsAdded   int                    `json:"words_added"`
+	WordsRemoved int                    `json:"words_removed"`
+}
+
+// SetRevisionRepository enables keeping a revision of the title and content of
+// articles on every change to them
+func (s *ArticleService) SetRevisionRepository(revisionRepo repositories.ArticleRevisionRepository) {
+	s.revisionRepo = revisionRepo
+}
+
+// ListRevisions returns the revisions of one of the user's articles, newest first
+func (s *ArticleService) ListRevisions(articleID, userID uint) ([]models.ArticleRevision, error) {
+	if err := s.checkRevisionAccess(articleID, userID); err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisionRepo.ListByArticle(articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+// CompareRevisions returns what changed in one of the user's articles from
+// revision from to revision to
+func (s *ArticleService) CompareRevisions(articleID, userID, from, to uint) (*RevisionComparison, error) {
+	if err := s.checkRevisionAccess(articleID, userID); err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*models.ArticleRevision, 0, 2)
+	for _, number := range []uint{from, to} {
+		revision, err := s.revisionRepo.GetByNumber(articleID, number)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return nil, notFoundError("revision %d not found", number)
+			}
+			return nil, fmt.Errorf("failed to get revision: %w", err)
+		}
+		revisions = append(revisions, revision)
+	}
+	older, newer := revisions[0], revisions[1]
+
+	content := diff.Words(older.Content, newer.Content)
+	comparison := &RevisionComparison{
+		From:         *older,
+		To:           *newer,
+		Title:        diff.Words(older.Title, newer.Title),
+		Content:      content,
+		WordsAdded:   diff.Count(content, diff.OpInsert),
+		WordsRemoved: diff.Count(content, diff.OpDelete),
+	}
+	comparison.From.Content = ""
+	comparison.To.Content = ""
+	return comparison, nil
+}
+
+// checkRevisionAccess verifies that revisions are kept and that the article
+// exists and belongs to the user
+func (s *ArticleService) checkRevisionAccess(articleID, userID uint) error {
+	if s.revisionRepo == nil {
+		return errors.New("article revision repository not available")
+	}
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("article not found")
+		}
+		return fmt.Errorf("failed to get article: %w", err)
+	}
+	if article.AuthorID != userID {
+		return forbiddenError("unauthorized: you can only view the revisions of your own articles")
+	}
+	return nil
+}
+
+// recordRevision stores the title and content of article as its next revision.
+// When previous is given and the article has no revisions yet, previous is
+// stored first as the text before the change. revisions may be nil when
+// revisions are not kept.
+func recordRevision(revisions repositories.ArticleRevisionRepository, article *models.Article, editorID uint, previous *models.ArticleRevision) error {
+	if revisions == nil {
+		return nil
+	}
+
+	if previous != nil {
+		count, err := revisions.CountByArticle(article.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count revisions: %w", err)
+		}
+		if count == 0 {
+			if err := revisions.Create(previous); err != nil {
+				return fmt.Errorf("failed to record revision: %w", err)
+			}
+		}
+	}
+
+	revision := &models.ArticleRevision{
+		ArticleID: article.ID,
+		EditorID:  editorID,
+		Title:     article.Title,
+		Content:   article.Content,
+	}
+	if err := revisions.Create(revision); err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
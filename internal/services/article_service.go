@@ -1,6 +1,8 @@
 package services
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
@@ -8,44 +10,72 @@ import (
 
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
+	"go-blog/internal/sanitize"
 	"go-blog/internal/utils"
+)
 
-	"gorm.io/gorm"
+const (
+	// slugNumberedAttempts bounds the slugs tried with a counter suffix (title-1, title-2, ...)
+	slugNumberedAttempts = 100
+	// slugRandomAttempts bounds the slugs tried with a random suffix once the numbered ones are used up
+	slugRandomAttempts = 5
 )
 
 type ArticleService struct {
-	articleRepo  repositories.ArticleRepository
-	userRepo     repositories.UserRepository
-	categoryRepo repositories.CategoryRepository
-	tagRepo      repositories.TagRepository
+	articleRepo   repositories.ArticleRepository
+	userRepo      repositories.UserRepository
+	categoryRepo  repositories.CategoryRepository
+	tagRepo       repositories.TagRepository
+	tagService    *TagService
+	searchEngines *SearchEngineNotifier
+	ogImages      *OGImageService
+	likeRepo      repositories.LikeRepository // optional, marks the articles readers liked
+	transactor    repositories.Transactor
+	revisionRepo  repositories.ArticleRevisionRepository
+	metaRepo      repositories.ArticleMetaRepository // optional, custom fields of articles
+	quotaService  *QuotaService
+	suggester     ContentSuggester // nil unless content suggestions are enabled
+	contentFilter *ContentFilterService
 }
 
 // CreateArticleRequest represents article creation data
 type CreateArticleRequest struct {
-	Title      string   `json:"title" validate:"required,min=1,max=255"`
-	Content    string   `json:"content" validate:"required,min=1"`
-	Excerpt    string   `json:"excerpt,omitempty" validate:"omitempty,max=500"`
-	CategoryID *uint    `json:"category_id,omitempty" validate:"omitempty,min=1"`
-	TagNames   []string `json:"tag_names,omitempty"`
-	Status     string   `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+	Title      string                 `json:"title" validate:"required,min=1,max=255"`
+	Content    string                 `json:"content" validate:"required,min=1"`
+	Excerpt    string                 `json:"excerpt,omitempty" validate:"omitempty,max=500"`
+	CategoryID *uint                  `json:"category_id,omitempty" validate:"omitempty,min=1"`
+	TagNames   []string               `json:"tag_names,omitempty"`
+	Status     string                 `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+	ExpiresAt  *time.Time             `json:"expires_at,omitempty"` // archive the article once passed
+	Settings   ArticleSettingsRequest `json:"settings"`
+	Visibility string                 `json:"visibility,omitempty" validate:"omitempty,oneof=public members premium"`
+	ClientIP   string                 `json:"-"` // set by handlers for per-IP quotas
 }
 
-// UpdateArticleRequest represents article update data
+// UpdateArticleRequest represents article update data. Fields left out are
+// kept; an empty excerpt clears it and a null category_id removes the category.
 type UpdateArticleRequest struct {
-	Title      string   `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
-	Content    string   `json:"content,omitempty" validate:"omitempty,min=1"`
-	Excerpt    string   `json:"excerpt,omitempty" validate:"omitempty,max=500"`
-	CategoryID *uint    `json:"category_id,omitempty" validate:"omitempty,min=1"`
-	TagNames   []string `json:"tag_names,omitempty"`
-	Status     string   `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
+	Title      *string        `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Content    *string        `json:"content,omitempty" validate:"omitempty,min=1"`
+	Excerpt    *string        `json:"excerpt,omitempty" validate:"omitempty,max=500"`
+	CategoryID Nullable[uint] `json:"category_id"`
+	TagNames   []string       `json:"tag_names,omitempty"`
+	Status     string         `json:"status,omitempty" validate:"omitempty,oneof=draft published archived"`
+	ExpiresAt  *time.Time     `json:"expires_at,omitempty"` // a zero time removes the expiry
+	Visibility string         `json:"visibility,omitempty" validate:"omitempty,oneof=public members premium"`
 }
 
 // ArticleListFilters represents filters for article listing
 type ArticleListFilters struct {
-	Status     string `json:"status,omitempty"`
-	CategoryID uint   `json:"category_id,omitempty"`
-	AuthorID   uint   `json:"author_id,omitempty"`
-	TagID      uint   `json:"tag_id,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	AuthorID      uint      `json:"author_id,omitempty"`
+	CategoryIDs   []uint    `json:"category_ids,omitempty"`
+	TagIDs        []uint    `json:"tag_ids,omitempty"`
+	TagMatch      string    `json:"tag_match,omitempty"` // "any" (default) or "all"
+	PublishedFrom time.Time `json:"published_from,omitempty"`
+	PublishedTo   time.Time `json:"published_to,omitempty"`
+	MinViews      uint      `json:"min_views,omitempty"`
+	Sort          string    `json:"sort,omitempty"` // e.g. "-view_count"; see repositories.ArticleSortFields
 }
 
 // NewArticleService creates a new article service
@@ -63,6 +93,44 @@ func NewArticleService(
 	}
 }
 
+// SetTagService sets the tag service used to resolve tag names and aliases (for dependency injection)
+func (s *ArticleService) SetTagService(tagService *TagService) {
+	s.tagService = tagService
+}
+
+// SetTransactor sets the transactor that makes article writes and their tag
+// changes atomic (for dependency injection)
+func (s *ArticleService) SetTransactor(transactor repositories.Transactor) {
+	s.transactor = transactor
+}
+
+// SetSearchEngineNotifier sets the notifier told about articles when they are published
+func (s *ArticleService) SetSearchEngineNotifier(notifier *SearchEngineNotifier) {
+	s.searchEngines = notifier
+}
+
+// SetOGImageService sets the renderer of the share images of published articles
+func (s *ArticleService) SetOGImageService(ogImages *OGImageService) {
+	s.ogImages = ogImages
+}
+
+// SetLikeRepository sets the likes articles are marked as liked by their
+// reader from
+func (s *ArticleService) SetLikeRepository(likeRepo repositories.LikeRepository) {
+	s.likeRepo = likeRepo
+}
+
+// SetQuotaService enables write quotas on article creation
+func (s *ArticleService) SetQuotaService(quotaService *QuotaService) {
+	s.quotaService = quotaService
+}
+
+// SetContentFilter enables checking article titles against the content filter
+// rules. Articles whose title a hold rule matches are kept as drafts.
+func (s *ArticleService) SetContentFilter(contentFilter *ContentFilterService) {
+	s.contentFilter = contentFilter
+}
+
 // Create creates a new article
 func (s *ArticleService) Create(authorID uint, req *CreateArticleRequest) (*models.Article, error) {
 	// Validate input
@@ -70,34 +138,37 @@ func (s *ArticleService) Create(authorID uint, req *CreateArticleRequest) (*mode
 		return nil, err
 	}
 
-	// Verify author exists
-	author, err := s.userRepo.GetByID(authorID)
-	if err != nil {
-		return nil, errors.New("author not found")
+	// Strip unsafe markup on write; content that was nothing but markup is rejected
+	content := sanitize.Article(req.Content)
+	if content == "" {
+		return nil, validationError("content is required")
 	}
 
-	// Generate unique slug
-	slug, err := s.generateUniqueSlug(req.Title)
+	// Verify author exists
+	author, err := s.userRepo.GetByID(authorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate slug: %w", err)
+		return nil, notFoundError("author not found")
 	}
-
 	// Create article model
 	article := &models.Article{
-		Title:    strings.TrimSpace(req.Title),
-		Slug:     slug,
-		Content:  strings.TrimSpace(req.Content),
-		Excerpt:  strings.TrimSpace(req.Excerpt),
-		AuthorID: authorID,
-		Author:   *author,
-		Status:   models.StatusDraft, // Default to draft
+		Title:      strings.TrimSpace(req.Title),
+		Content:    content,
+		Excerpt:    sanitize.Text(req.Excerpt),
+		AuthorID:   authorID,
+		Author:     *author,
+		Status:     models.StatusDraft, // Default to draft
+		Visibility: models.ArticleVisibilityPublic,
+	}
+	req.Settings.apply(article)
+	if req.Visibility != "" {
+		article.Visibility = models.ArticleVisibility(req.Visibility)
 	}
 
 	// Set category if provided
 	if req.CategoryID != nil {
 		category, err := s.categoryRepo.GetByID(*req.CategoryID)
 		if err != nil {
-			return nil, errors.New("category not found")
+			return nil, notFoundError("category not found")
 		}
 		article.CategoryID = req.CategoryID
 		article.Category = category
@@ -107,25 +178,62 @@ func (s *ArticleService) Create(authorID uint, req *CreateArticleRequest) (*mode
 	if req.Status != "" {
 		article.Status = models.ArticleStatus(req.Status)
 	}
+	filtered, err := s.filterTitle(article.Title)
+	if err != nil {
+		return nil, err
+	}
+	article.Title = filtered.Text
+	if filtered.Action == models.FilterHold {
+		article.Status = models.StatusDraft
+	}
 
 	// Handle publishing
 	if article.Status == models.StatusPublished {
 		now := time.Now()
 		article.PublishedAt = &now
 	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return nil, validationError("expires_at must be in the future")
+		}
+		article.ExpiresAt = req.ExpiresAt
+	}
 
-	// Process tags
-	if len(req.TagNames) > 0 {
-		tags, err := s.processTagNames(req.TagNames)
-		if err != nil {
-			return nil, fmt.Errorf("failed to process tags: %w", err)
+	// The article counts towards the quotas once it is valid
+	release := func() {}
+	if s.quotaService != nil {
+		if release, err = s.quotaService.ReserveArticle(author, req.ClientIP); err != nil {
+			return nil, err
 		}
-		article.Tags = tags
 	}
 
-	// Create article
-	if err := s.articleRepo.Create(article); err != nil {
-		return nil, fmt.Errorf("failed to create article: %w", err)
+	// Create missing tags and insert the article under a free slug atomically
+	err = s.inTransaction(func(articles repositories.ArticleRepository, revisions repositories.ArticleRevisionRepository, tagService *TagService) error {
+		if len(req.TagNames) > 0 {
+			tags, err := tagService.ProcessTagNames(req.TagNames)
+			if err != nil {
+				return fmt.Errorf("failed to process tags: %w", err)
+			}
+			article.Tags = tags
+		}
+
+		err := s.saveWithUniqueSlug(articles, article, func() error {
+			if err := articles.Create(article); err != nil {
+				return fmt.Errorf("failed to create article: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return recordRevision(revisions, article, authorID, nil)
+	})
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if article.Status == models.StatusPublished {
+		s.notifyPublished(article)
 	}
 
 	return article, nil
@@ -136,16 +244,79 @@ func (s *ArticleService) GetByID(id uint) (*models.Article, error) {
 	return s.articleRepo.GetByID(id)
 }
 
-// GetBySlug retrieves an article by slug
-func (s *ArticleService) GetBySlug(slug string) (*models.Article, error) {
+// GetBySlug retrieves an article by slug for viewer, nil for anonymous readers.
+// Unpublished articles are only found by their author and admins, and the
+// content is cut to a preview when viewer lacks the membership it needs.
+func (s *ArticleService) GetBySlug(slug string, viewer *models.User) (*models.Article, error) {
 	if strings.TrimSpace(slug) == "" {
-		return nil, errors.New("slug cannot be empty")
+		return nil, validationError("slug cannot be empty")
+	}
+	article, err := s.articleRepo.GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
+		}
+		return nil, err
+	}
+	if article.Status != models.StatusPublished &&
+		(viewer == nil || (viewer.ID != article.AuthorID && !viewer.IsAdmin())) {
+		return nil, notFoundError("article not found")
 	}
-	return s.articleRepo.GetBySlug(slug)
+	restrictArticle(article, viewer)
+	if err := s.markLiked([]*models.Article{article}, viewer); err != nil {
+		return nil, err
+	}
+	if err := s.loadMeta(article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// List retrieves articles with pagination and filters for viewer, nil for
+// anonymous readers, marking the ones viewer is not entitled to as locked
+func (s *ArticleService) List(page, limit int, filters *ArticleListFilters, viewer *models.User) ([]models.Article, int64, error) {
+	articles, total, err := s.list(page, limit, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+	restrictArticles(articles, viewer)
+	marked := make([]*models.Article, len(articles))
+	for i := range articles {
+		marked[i] = &articles[i]
+	}
+	if err := s.markLiked(marked, viewer); err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
 }
 
-// List retrieves articles with pagination and filters
-func (s *ArticleService) List(page, limit int, filters *ArticleListFilters) ([]models.Article, int64, error) {
+// markLiked sets whether viewer liked each of articles, with a single query.
+// Articles stay unmarked for anonymous readers.
+func (s *ArticleService) markLiked(articles []*models.Article, viewer *models.User) error {
+	if viewer == nil || s.likeRepo == nil || len(articles) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ID
+	}
+	likedIDs, err := s.likeRepo.LikedArticleIDs(viewer.ID, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load likes: %w", err)
+	}
+	liked := make(map[uint]bool, len(likedIDs))
+	for _, id := range likedIDs {
+		liked[id] = true
+	}
+	for _, article := range articles {
+		isLiked := liked[article.ID]
+		article.Liked = &isLiked
+	}
+	return nil
+}
+
+// list retrieves articles with pagination and filters
+func (s *ArticleService) list(page, limit int, filters *ArticleListFilters) ([]models.Article, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -154,25 +325,54 @@ func (s *ArticleService) List(page, limit int, filters *ArticleListFilters) ([]m
 	}
 
 	offset := (page - 1) * limit
-	
-	// Convert filters to map
-	filterMap := make(map[string]interface{})
-	if filters != nil {
-		if filters.Status != "" {
-			filterMap["status"] = filters.Status
-		}
-		if filters.CategoryID > 0 {
-			filterMap["category_id"] = filters.CategoryID
-		}
-		if filters.AuthorID > 0 {
-			filterMap["author_id"] = filters.AuthorID
-		}
-		if filters.TagID > 0 {
-			filterMap["tag_id"] = filters.TagID
-		}
+
+	if filters == nil {
+		return s.articleRepo.ListFiltered(offset, limit, nil, repositories.DefaultArticleSort)
+	}
+
+	sortBy, err := repositories.ParseArticleSort(filters.Sort)
+	if err != nil {
+		return nil, 0, validationError("%s", err.Error())
 	}
 
-	return s.articleRepo.List(offset, limit, filterMap)
+	filter, err := s.toArticleFilter(filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return s.articleRepo.ListFiltered(offset, limit, filter, sortBy)
+}
+
+// toArticleFilter validates list filters and converts them to repository filters
+func (s *ArticleService) toArticleFilter(filters *ArticleListFilters) (*repositories.ArticleFilter, error) {
+	tagMatch := repositories.TagMatchAny
+	switch filters.TagMatch {
+	case "", string(repositories.TagMatchAny):
+	case string(repositories.TagMatchAll):
+		tagMatch = repositories.TagMatchAll
+	default:
+		return nil, validationError("tag_match must be 'any' or 'all'")
+	}
+
+	if !filters.PublishedFrom.IsZero() && !filters.PublishedTo.IsZero() &&
+		filters.PublishedFrom.After(filters.PublishedTo) {
+		return nil, validationError("published_from must be before published_to")
+	}
+
+	if len(filters.CategoryIDs) > 50 || len(filters.TagIDs) > 50 {
+		return nil, validationError("at most 50 category or tag IDs may be given")
+	}
+
+	return &repositories.ArticleFilter{
+		Status:        filters.Status,
+		AuthorID:      filters.AuthorID,
+		CategoryIDs:   filters.CategoryIDs,
+		TagIDs:        filters.TagIDs,
+		TagMatch:      tagMatch,
+		PublishedFrom: filters.PublishedFrom,
+		PublishedTo:   filters.PublishedTo,
+		MinViews:      filters.MinViews,
+	}, nil
 }
 
 // Update updates an article
@@ -185,89 +385,183 @@ func (s *ArticleService) Update(id uint, authorID uint, req *UpdateArticleReques
 	// Get existing article
 	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
-		return nil, errors.New("article not found")
+		return nil, notFoundError("article not found")
 	}
 
 	// Check authorization
 	if article.AuthorID != authorID {
-		return nil, errors.New("unauthorized: you can only edit your own articles")
+		return nil, forbiddenError("unauthorized: you can only edit your own articles")
+	}
+
+	// Articles written before revisions were kept get this text as their first revision
+	previous := &models.ArticleRevision{
+		ArticleID: article.ID,
+		EditorID:  article.AuthorID,
+		Title:     article.Title,
+		Content:   article.Content,
+		CreatedAt: article.UpdatedAt,
 	}
 
 	// Update fields if provided
 	updated := false
 
-	if req.Title != "" && req.Title != article.Title {
-		article.Title = strings.TrimSpace(req.Title)
-		// Generate new slug if title changed
-		slug, err := s.generateUniqueSlug(article.Title)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate slug: %w", err)
+	titleChanged := false
+	if req.Title != nil {
+		if title := strings.TrimSpace(*req.Title); title != article.Title {
+			article.Title = title
+			titleChanged = true
+			updated = true
 		}
-		article.Slug = slug
-		updated = true
 	}
 
-	if req.Content != "" && req.Content != article.Content {
-		article.Content = strings.TrimSpace(req.Content)
-		updated = true
+	contentChanged := false
+	if req.Content != nil {
+		content := sanitize.Article(*req.Content)
+		if content == "" {
+			return nil, validationError("content cannot be empty")
+		}
+		if content != article.Content {
+			article.Content = content
+			contentChanged = true
+			updated = true
+		}
 	}
 
-	if req.Excerpt != article.Excerpt {
-		article.Excerpt = strings.TrimSpace(req.Excerpt)
-		updated = true
+	if req.Excerpt != nil {
+		if excerpt := sanitize.Text(*req.Excerpt); excerpt != article.Excerpt {
+			article.Excerpt = excerpt
+			updated = true
+		}
 	}
 
-	// Handle category change
-	if req.CategoryID != nil {
-		if (article.CategoryID == nil && *req.CategoryID != 0) ||
-			(article.CategoryID != nil && *article.CategoryID != *req.CategoryID) {
-			if *req.CategoryID == 0 {
+	// Handle category change; null removes the category
+	if req.CategoryID.Set {
+		categoryID := req.CategoryID.Value
+		switch {
+		case categoryID == nil:
+			if article.CategoryID != nil {
 				article.CategoryID = nil
 				article.Category = nil
-			} else {
-				category, err := s.categoryRepo.GetByID(*req.CategoryID)
-				if err != nil {
-					return nil, errors.New("category not found")
-				}
-				article.CategoryID = req.CategoryID
-				article.Category = category
+				updated = true
+			}
+		case article.CategoryID == nil || *article.CategoryID != *categoryID:
+			category, err := s.categoryRepo.GetByID(*categoryID)
+			if err != nil {
+				return nil, notFoundError("category not found")
 			}
+			article.CategoryID = categoryID
+			article.Category = category
 			updated = true
 		}
 	}
 
+	if req.Visibility != "" && models.ArticleVisibility(req.Visibility) != article.Visibility {
+		article.Visibility = models.ArticleVisibility(req.Visibility)
+		updated = true
+	}
+
+	if req.ExpiresAt != nil {
+		switch {
+		case req.ExpiresAt.IsZero():
+			if article.ExpiresAt != nil {
+				article.ExpiresAt = nil
+				updated = true
+			}
+		case !req.ExpiresAt.After(time.Now()):
+			return nil, validationError("expires_at must be in the future")
+		default:
+			article.ExpiresAt = req.ExpiresAt
+			updated = true
+		}
+	}
+
+	// Check the title when it changes or the article is published
+	status := req.Status
+	if titleChanged || (status == string(models.StatusPublished) && article.Status != models.StatusPublished) {
+		filtered, err := s.filterTitle(article.Title)
+		if err != nil {
+			return nil, err
+		}
+		if filtered.Text != article.Title {
+			article.Title = filtered.Text
+			titleChanged = true
+		}
+		if filtered.Action == models.FilterHold &&
+			(status == string(models.StatusPublished) || (status == "" && article.Status == models.StatusPublished)) {
+			status = string(models.StatusDraft)
+		}
+	}
+
 	// Handle status change
-	if req.Status != "" && string(article.Status) != req.Status {
+	published := false
+	if status != "" && string(article.Status) != status {
 		oldStatus := article.Status
-		article.Status = models.ArticleStatus(req.Status)
+		article.Status = models.ArticleStatus(status)
 
 		// Handle publishing
 		if article.Status == models.StatusPublished && oldStatus != models.StatusPublished {
+			if article.Expired(time.Now()) {
+				return nil, validationError("article has expired; set a new expires_at to publish it again")
+			}
 			if article.PublishedAt == nil {
 				now := time.Now()
 				article.PublishedAt = &now
 			}
+			published = true
 		}
 		updated = true
 	}
 
-	// Handle tags
 	if req.TagNames != nil {
-		tags, err := s.processTagNames(req.TagNames)
-		if err != nil {
-			return nil, fmt.Errorf("failed to process tags: %w", err)
-		}
-		article.Tags = tags
 		updated = true
 	}
-
 	if !updated {
 		return article, nil
 	}
 
-	// Update article
-	if err := s.articleRepo.Update(article); err != nil {
-		return nil, fmt.Errorf("failed to update article: %w", err)
+	// Regenerate the slug, create missing tags and write the article and its tag
+	// associations atomically
+	err = s.inTransaction(func(articles repositories.ArticleRepository, revisions repositories.ArticleRevisionRepository, tagService *TagService) error {
+		var tags []models.Tag
+		if req.TagNames != nil {
+			if tags, err = tagService.ProcessTagNames(req.TagNames); err != nil {
+				return fmt.Errorf("failed to process tags: %w", err)
+			}
+			article.Tags = tags
+		}
+
+		save := func() error {
+			if err := articles.Update(article); err != nil {
+				return fmt.Errorf("failed to update article: %w", err)
+			}
+			return nil
+		}
+		// A new title moves the article to a new slug
+		if titleChanged {
+			err = s.saveWithUniqueSlug(articles, article, save)
+		} else {
+			err = save()
+		}
+		if err != nil {
+			return err
+		}
+		if req.TagNames != nil {
+			if err := articles.ReplaceTags(article, tags); err != nil {
+				return fmt.Errorf("failed to update article tags: %w", err)
+			}
+		}
+		if titleChanged || contentChanged {
+			return recordRevision(revisions, article, authorID, previous)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if published {
+		s.notifyPublished(article)
+	} else if titleChanged && s.ogImages != nil {
+		s.ogImages.ArticleChanged(article)
 	}
 
 	return article, nil
@@ -278,17 +572,37 @@ func (s *ArticleService) Delete(id uint, authorID uint) error {
 	// Get existing article
 	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
-		return errors.New("article not found")
+		return notFoundError("article not found")
 	}
 
 	// Check authorization
 	if article.AuthorID != authorID {
-		return errors.New("unauthorized: you can only delete your own articles")
+		return forbiddenError("unauthorized: you can only delete your own articles")
 	}
 
 	return s.articleRepo.Delete(id)
 }
 
+// PurgeTrash permanently removes articles deleted longer than retention ago,
+// with their comments and likes, and returns how many were removed
+func (s *ArticleService) PurgeTrash(retention time.Duration) (int64, error) {
+	purged, err := s.articleRepo.PurgeDeleted(time.Now().Add(-retention))
+	if err != nil {
+		return purged, fmt.Errorf("failed to purge deleted articles: %w", err)
+	}
+	return purged, nil
+}
+
+// ArchiveExpired archives the published articles whose expiry has passed and
+// returns how many were archived
+func (s *ArticleService) ArchiveExpired() (int64, error) {
+	archived, err := s.articleRepo.ArchiveExpired(time.Now())
+	if err != nil {
+		return archived, fmt.Errorf("failed to archive expired articles: %w", err)
+	}
+	return archived, nil
+}
+
 // Publish publishes an article
 func (s *ArticleService) Publish(id uint, authorID uint) (*models.Article, error) {
 	return s.changeStatus(id, authorID, models.StatusPublished)
@@ -312,7 +626,7 @@ func (s *ArticleService) IncrementViewCount(id uint) error {
 // Search searches articles using basic search
 func (s *ArticleService) Search(query string, page, limit int) ([]models.Article, int64, error) {
 	if strings.TrimSpace(query) == "" {
-		return nil, 0, errors.New("search query cannot be empty")
+		return nil, 0, validationError("search query cannot be empty")
 	}
 
 	if page < 1 {
@@ -329,7 +643,7 @@ func (s *ArticleService) Search(query string, page, limit int) ([]models.Article
 // AdvancedSearch performs advanced search with filters
 func (s *ArticleService) AdvancedSearch(query string, page, limit int, filters *repositories.SearchFilters) ([]models.Article, int64, error) {
 	if strings.TrimSpace(query) == "" {
-		return nil, 0, errors.New("search query cannot be empty")
+		return nil, 0, validationError("search query cannot be empty")
 	}
 
 	if page < 1 {
@@ -348,12 +662,12 @@ func (s *ArticleService) changeStatus(id uint, authorID uint, status models.Arti
 	// Get existing article
 	article, err := s.articleRepo.GetByID(id)
 	if err != nil {
-		return nil, errors.New("article not found")
+		return nil, notFoundError("article not found")
 	}
 
 	// Check authorization
 	if article.AuthorID != authorID {
-		return nil, errors.New("unauthorized: you can only modify your own articles")
+		return nil, forbiddenError("unauthorized: you can only modify your own articles")
 	}
 
 	// Don't update if status is the same
@@ -361,6 +675,10 @@ func (s *ArticleService) changeStatus(id uint, authorID uint, status models.Arti
 		return article, nil
 	}
 
+	if status == models.StatusPublished && article.Expired(time.Now()) {
+		return nil, validationError("article has expired; set a new expires_at to publish it again")
+	}
+
 	// Update status
 	article.Status = status
 
@@ -374,101 +692,167 @@ func (s *ArticleService) changeStatus(id uint, authorID uint, status models.Arti
 	if err := s.articleRepo.Update(article); err != nil {
 		return nil, fmt.Errorf("failed to update article status: %w", err)
 	}
+	if status == models.StatusPublished {
+		s.notifyPublished(article)
+	}
 
 	return article, nil
 }
 
-// generateUniqueSlug generates a unique slug for an article
-func (s *ArticleService) generateUniqueSlug(title string) (string, error) {
-	baseSlug := utils.GenerateSlug(title)
-	if baseSlug == "" {
-		return "", errors.New("cannot generate slug from title")
+// notifyPublished tells search engines about an article that was just
+// published, unless it is kept out of their index, and renders its share image
+func (s *ArticleService) notifyPublished(article *models.Article) {
+	if s.searchEngines != nil && !article.NoIndex {
+		s.searchEngines.ArticlePublished(article)
 	}
+	if s.ogImages != nil {
+		s.ogImages.ArticleChanged(article)
+	}
+}
 
-	slug := baseSlug
-	counter := 1
+// inTransaction runs fn with the article and revision repositories and tag
+// service bound to one transaction, or with the plain ones when no transactor is
+// set. revisions is nil when revisions are not kept.
+func (s *ArticleService) inTransaction(fn func(articles repositories.ArticleRepository, revisions repositories.ArticleRevisionRepository, tagService *TagService) error) error {
+	if s.transactor == nil {
+		return fn(s.articleRepo, s.revisionRepo, s.tags())
+	}
+	return s.transactor.Transaction(func(tx *repositories.Tx) error {
+		var revisions repositories.ArticleRevisionRepository
+		if s.revisionRepo != nil {
+			revisions = tx.Revisions
+		}
+		return fn(tx.Articles, revisions, s.tags().withRepositories(tx.Tags, tx.TagAliases))
+	})
+}
 
-	// Check if slug exists and generate unique one
-	for {
-		_, err := s.articleRepo.GetBySlug(slug)
+// saveWithUniqueSlug gives article a slug derived from its title and writes it
+// with save, moving on to the next candidate when the write hits the unique
+// slug index. Candidates known to be taken are skipped without a write; the
+// index catches concurrent writers that picked the same one.
+func (s *ArticleService) saveWithUniqueSlug(articles repositories.ArticleRepository, article *models.Article, save func() error) error {
+	baseSlug := utils.GenerateSlug(article.Title)
+	if baseSlug == "" {
+		return validationError("cannot generate slug from title")
+	}
+
+	for attempt := 0; attempt < slugNumberedAttempts+slugRandomAttempts; attempt++ {
+		slug, err := slugCandidate(baseSlug, attempt)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				// Slug is available
-				break
+			return err
+		}
+		if attempt < slugNumberedAttempts {
+			existing, err := articles.GetBySlug(slug)
+			if err == nil && existing.ID != article.ID {
+				continue
+			}
+			if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+				return fmt.Errorf("error checking slug availability: %w", err)
 			}
-			return "", fmt.Errorf("error checking slug availability: %w", err)
 		}
 
-		// Slug exists, try with counter
-		slug = fmt.Sprintf("%s-%d", baseSlug, counter)
-		counter++
-
-		// Prevent infinite loop
-		if counter > 1000 {
-			return "", errors.New("unable to generate unique slug")
+		article.Slug = slug
+		err = save()
+		if err == nil || !errors.Is(err, repositories.ErrDuplicate) {
+			return err
 		}
 	}
 
-	return slug, nil
+	return conflictError("unable to generate a unique slug for this title")
 }
 
-// processTagNames processes tag names and returns tag models
-func (s *ArticleService) processTagNames(tagNames []string) ([]models.Tag, error) {
-	// Create a tag service instance to handle tag processing
-	tagService := NewTagService(s.tagRepo)
-	return tagService.ProcessTagNames(tagNames)
+// slugCandidate returns the slug to try on the given attempt: the base slug,
+// then numbered ones, then ones with a random suffix
+func slugCandidate(baseSlug string, attempt int) (string, error) {
+	if attempt == 0 {
+		return baseSlug, nil
+	}
+	if attempt < slugNumberedAttempts {
+		return fmt.Sprintf("%s-%d", baseSlug, attempt), nil
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate slug suffix: %w", err)
+	}
+	return baseSlug + "-" + hex.EncodeToString(suffix), nil
+}
+
+// tags returns the tag service resolving tag names
+func (s *ArticleService) tags() *TagService {
+	// Prefer the injected tag service so tag aliases are honoured
+	if s.tagService != nil {
+		return s.tagService
+	}
+	return NewTagService(s.tagRepo)
 }
 
 // validateCreateRequest validates article creation request
 func (s *ArticleService) validateCreateRequest(req *CreateArticleRequest) error {
 	if req == nil {
-		return errors.New("create request is required")
+		return validationError("create request is required")
 	}
 
 	if strings.TrimSpace(req.Title) == "" {
-		return errors.New("title is required")
+		return validationError("title is required")
 	}
 
 	if len(req.Title) > 255 {
-		return errors.New("title must be less than 255 characters")
+		return validationError("title must be less than 255 characters")
 	}
 
 	if strings.TrimSpace(req.Content) == "" {
-		return errors.New("content is required")
+		return validationError("content is required")
 	}
 
 	if req.Excerpt != "" && len(req.Excerpt) > 500 {
-		return errors.New("excerpt must be less than 500 characters")
+		return validationError("excerpt must be less than 500 characters")
 	}
 
 	if req.Status != "" && req.Status != "draft" && req.Status != "published" {
-		return errors.New("status must be either 'draft' or 'published'")
+		return validationError("status must be either 'draft' or 'published'")
+	}
+
+	if err := validateVisibility(req.Visibility); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// filterTitle checks an article title against the content filter, if enabled
+func (s *ArticleService) filterTitle(title string) (*FilterResult, error) {
+	if s.contentFilter == nil {
+		return &FilterResult{Text: title}, nil
+	}
+	return s.contentFilter.Check(models.FilterScopeTitles, title)
+}
+
 // validateUpdateRequest validates article update request
 func (s *ArticleService) validateUpdateRequest(req *UpdateArticleRequest) error {
 	if req == nil {
-		return errors.New("update request is required")
+		return validationError("update request is required")
 	}
 
-	if req.Title != "" {
-		if strings.TrimSpace(req.Title) == "" {
-			return errors.New("title cannot be empty")
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return validationError("title cannot be empty")
 		}
-		if len(req.Title) > 255 {
-			return errors.New("title must be less than 255 characters")
+		if len(*req.Title) > 255 {
+			return validationError("title must be less than 255 characters")
 		}
 	}
 
-	if req.Content != "" && strings.TrimSpace(req.Content) == "" {
-		return errors.New("content cannot be empty")
+	if req.Content != nil && strings.TrimSpace(*req.Content) == "" {
+		return validationError("content cannot be empty")
 	}
 
-	if req.Excerpt != "" && len(req.Excerpt) > 500 {
-		return errors.New("excerpt must be less than 500 characters")
+	if req.Excerpt != nil && len(*req.Excerpt) > 500 {
+		return validationError("excerpt must be less than 500 characters")
+	}
+
+	if req.CategoryID.Value != nil && *req.CategoryID.Value == 0 {
+		return validationError("category_id must be positive; use null to remove the category")
 	}
 
 	if req.Status != "" {
@@ -481,9 +865,9 @@ func (s *ArticleService) validateUpdateRequest(req *UpdateArticleRequest) error
 			}
 		}
 		if !valid {
-			return errors.New("status must be one of: draft, published, archived")
+			return validationError("status must be one of: draft, published, archived")
 		}
 	}
 
-	return nil
-}
\ No newline at end of file
+	return validateVisibility(req.Visibility)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"fmt"
+
+	"go-blog/internal/models"
+)
+
+// ArticleSettingsRequest changes the per-article settings; omitted settings
+// keep their value, or their default when the article is created
+type ArticleSettingsRequest struct {
+	AllowComments *bool `json:"allow_comments,omitempty"`
+	AllowLikes    *bool `json:"allow_likes,omitempty"`
+	NoIndex       *bool `json:"noindex,omitempty"`
+}
+
+// apply sets the given settings on article and fills in the defaults of
+// the ones it has never had
+func (r *ArticleSettingsRequest) apply(article *models.Article) {
+	allowComments, allowLikes := article.CommentsAllowed(), article.LikesAllowed()
+	if r.AllowComments != nil {
+		allowComments = *r.AllowComments
+	}
+	if r.AllowLikes != nil {
+		allowLikes = *r.AllowLikes
+	}
+	if r.NoIndex != nil {
+		article.NoIndex = *r.NoIndex
+	}
+	article.AllowComments = &allowComments
+	article.AllowLikes = &allowLikes
+}
+
+// UpdateSettings changes whether an article takes comments and likes and
+// whether search engines are told about it. Only its author or an admin may.
+func (s *ArticleService) UpdateSettings(articleID uint, actor *models.User, req *ArticleSettingsRequest) (*models.Article, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		return nil, notFoundError("article not found")
+	}
+	if article.AuthorID != actor.ID && !actor.IsAdmin() {
+		return nil, forbiddenError("unauthorized: you can only change the settings of your own articles")
+	}
+
+	req.apply(article)
+	if err := s.articleRepo.Update(article); err != nil {
+		return nil, fmt.Errorf("failed to update article settings: %w", err)
+	}
+	return article, nil
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// TransferArticlesRequest names the author articles are transferred to
+type TransferArticlesRequest struct {
+	AuthorID uint `json:"author_id" validate:"required"`
+}
+
+// TransferResult reports a transfer with the statistics of both authors after it
+type TransferResult struct {
+	Articles int64                      `json:"articles"`
+	From     *repositories.AuthorTotals `json:"from"`
+	To       *repositories.AuthorTotals `json:"to"`
+}
+
+// TransferArticle makes another user the author of an article on behalf of
+// the administrator actorID
+func (s *ArticleService) TransferArticle(actorID, articleID uint, req *TransferArticlesRequest) (*TransferResult, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
+		}
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+
+	result, err := s.transfer(actorID, &repositories.ArticleTransfer{ArticleID: article.ID, FromAuthorID: article.AuthorID}, req)
+	if err == nil && s.ogImages != nil {
+		s.ogImages.ArticleChanged(article) // The share image names the author
+	}
+	return result, err
+}
+
+// TransferUserArticles makes another user the author of all of a user's
+// articles on behalf of the administrator actorID
+func (s *ArticleService) TransferUserArticles(actorID, userID uint, req *TransferArticlesRequest) (*TransferResult, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return s.transfer(actorID, &repositories.ArticleTransfer{FromAuthorID: userID}, req)
+}
+
+// transfer checks the receiving author and moves the articles with an audit record
+func (s *ArticleService) transfer(actorID uint, transfer *repositories.ArticleTransfer, req *TransferArticlesRequest) (*TransferResult, error) {
+	if req == nil || req.AuthorID == 0 {
+		return nil, validationError("author_id is required")
+	}
+	if req.AuthorID == transfer.FromAuthorID {
+		return nil, validationError("articles already belong to this author")
+	}
+	to, err := s.userRepo.GetByID(req.AuthorID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, validationError("new author not found")
+		}
+		return nil, fmt.Errorf("failed to get new author: %w", err)
+	}
+	if to.Role == models.RoleSystem {
+		return nil, validationError("articles cannot be transferred to system accounts")
+	}
+
+	details, err := json.Marshal(map[string]interface{}{
+		"article_id": transfer.ArticleID,
+		"from":       transfer.FromAuthorID,
+		"to":         to.ID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode audit details: %w", err)
+	}
+	transfer.ToAuthorID = to.ID
+	transfer.Audit = &models.AuditLog{
+		ActorID:    actorID,
+		Action:     models.AuditArticleTransfer,
+		TargetType: "user",
+		TargetID:   transfer.FromAuthorID,
+		Details:    string(details),
+	}
+	if transfer.ArticleID != 0 {
+		transfer.Audit.TargetType = "article"
+		transfer.Audit.TargetID = transfer.ArticleID
+	}
+
+	moved, err := s.articleRepo.Transfer(transfer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transfer articles: %w", err)
+	}
+
+	result := &TransferResult{Articles: moved}
+	if result.From, err = s.articleRepo.GetAuthorTotals(transfer.FromAuthorID); err != nil {
+		return nil, fmt.Errorf("failed to get author statistics: %w", err)
+	}
+	if result.To, err = s.articleRepo.GetAuthorTotals(to.ID); err != nil {
+		return nil, fmt.Errorf("failed to get author statistics: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// maxUTMLength is the longest UTM parameter value kept; longer ones are cut
+const maxUTMLength = 100
+
+// RecordViewRequest describes where the reader of a tracked view came from
+type RecordViewRequest struct {
+	Referrer    string `json:"referrer,omitempty" validate:"omitempty,max=2048"` // URL of the referring page
+	UTMSource   string `json:"utm_source,omitempty" validate:"omitempty,max=255"`
+	UTMMedium   string `json:"utm_medium,omitempty" validate:"omitempty,max=255"`
+	UTMCampaign string `json:"utm_campaign,omitempty" validate:"omitempty,max=255"`
+}
+
+// AuthorTraffic is where the views of an author's articles came from over the
+// last Days days
+type AuthorTraffic struct {
+	Days      int                   `json:"days"`
+	Referrers []models.TrafficCount `json:"referrers"` // "" counts direct views
+	Sources   []models.TrafficCount `json:"utm_sources"`
+	Mediums   []models.TrafficCount `json:"utm_mediums"`
+	Campaigns []models.TrafficCount `json:"utm_campaigns"`
+}
+
+// SetArticleViewRepository enables keeping the referrer and UTM parameters of
+// tracked article views
+func (s *StatisticsService) SetArticleViewRepository(viewRepo repositories.ArticleViewRepository) {
+	s.viewRepo = viewRepo
+}
+
+// RecordView counts a read of a published article, keeping the host of its
+// referrer and its UTM parameters when view sources are tracked
+func (s *StatisticsService) RecordView(articleID uint, req *RecordViewRequest) error {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("article not found")
+		}
+		return err
+	}
+	if article.Status != models.StatusPublished {
+		return notFoundError("article not found")
+	}
+
+	if s.viewRepo == nil {
+		return s.articleRepo.IncrementViewCount(articleID)
+	}
+	view := &models.ArticleView{
+		ArticleID:   articleID,
+		Referrer:    referrerHost(req.Referrer),
+		UTMSource:   utmValue(req.UTMSource),
+		UTMMedium:   utmValue(req.UTMMedium),
+		UTMCampaign: utmValue(req.UTMCampaign),
+	}
+	if err := s.viewRepo.Record(view); err != nil {
+		return fmt.Errorf("failed to record view: %w", err)
+	}
+	return nil
+}
+
+// GetAuthorTraffic groups the tracked views of an author's articles over the
+// last days by referrer and UTM parameter, up to limit values each
+func (s *StatisticsService) GetAuthorTraffic(authorID uint, days, limit int) (*AuthorTraffic, error) {
+	traffic := &AuthorTraffic{Days: days}
+	if s.viewRepo == nil {
+		return traffic, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	for _, dimension := range []struct {
+		name   repositories.TrafficDimension
+		counts *[]models.TrafficCount
+	}{
+		{repositories.TrafficReferrer, &traffic.Referrers},
+		{repositories.TrafficUTMSource, &traffic.Sources},
+		{repositories.TrafficUTMMedium, &traffic.Mediums},
+		{repositories.TrafficUTMCampaign, &traffic.Campaigns},
+	} {
+		counts, err := s.viewRepo.CountByAuthor(authorID, dimension.name, since, limit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count views by %s: %w", dimension.name, err)
+		}
+		*dimension.counts = counts
+	}
+	return traffic, nil
+}
+
+// utmValue normalizes a UTM parameter; campaign tools disagree on case, so
+// values are grouped case-insensitively
+func utmValue(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if runes := []rune(value); len(runes) > maxUTMLength {
+		value = string(runes[:maxUTMLength])
+	}
+	return value
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/utils"
+)
+
+// magicLinkWindow is the period the requests of sign-in links are limited over
+const magicLinkWindow = time.Hour
+
+// MagicLinkOptions configure sign-in with emailed links
+type MagicLinkOptions struct {
+	TTL        time.Duration // how long a link can be used
+	MaxPerHour int           // links sent per account and per IP address in an hour
+	// LinkURL is the page the emailed link opens with the token in its query;
+	// it signs in with POST /api/auth/magic-link/verify
+	LinkURL string
+}
+
+// MagicLinkRequest asks for a sign-in link to be emailed
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email,max=100"`
+}
+
+// VerifyMagicLinkRequest exchanges the token of a sign-in link for tokens.
+// ConfirmDevice signs in on a device other than the one the link was
+// requested from.
+type VerifyMagicLinkRequest struct {
+	Token         string `json:"token" validate:"required"`
+	ConfirmDevice bool   `json:"confirm_device"`
+}
+
+// SetMagicLinks enables passwordless sign-in with single-use links emailed to
+// the account's address
+func (s *AuthService) SetMagicLinks(magicLinkRepo repositories.MagicLinkRepository, opts MagicLinkOptions) {
+	s.magicLinkRepo = magicLinkRepo
+	s.magicLinks = opts
+}
+
+// RequestMagicLink emails a sign-in link to the account of req.Email. Unknown
+// addresses are not reported, so the endpoint cannot be used to find accounts;
+// accounts that asked for too many links within the hour are silently skipped.
+func (s *AuthService) RequestMagicLink(req *MagicLinkRequest, client ClientInfo) error {
+	if s.magicLinkRepo == nil {
+		return notFoundError("sign-in links are not enabled")
+	}
+	if req == nil || strings.TrimSpace(req.Email) == "" {
+		return validationError("email is required")
+	}
+
+	since := time.Now().Add(-magicLinkWindow)
+	if client.IP != "" {
+		requested, err := s.magicLinkRepo.CountByIPSince(client.IP, since)
+		if err != nil {
+			return fmt.Errorf("failed to count sign-in links: %w", err)
+		}
+		if requested >= int64(s.magicLinks.MaxPerHour) {
+			return quotaError(magicLinkWindow, "too many sign-in links requested, try again later")
+		}
+	}
+
+	user, err := s.userRepo.GetByEmail(strings.TrimSpace(req.Email))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.Role == models.RoleSystem {
+		return nil
+	}
+	requested, err := s.magicLinkRepo.CountByUserSince(user.ID, since)
+	if err != nil {
+		return fmt.Errorf("failed to count sign-in links: %w", err)
+	}
+	if requested >= int64(s.magicLinks.MaxPerHour) {
+		slog.Warn("Sign-in link limit reached", "user_id", user.ID, "ip", client.IP)
+		return nil
+	}
+
+	token, err := generateEmailToken()
+	if err != nil {
+		return err
+	}
+	link := &models.MagicLink{
+		UserID:    user.ID,
+		TokenHash: hashEmailToken(token),
+		IP:        client.IP,
+		Device:    utils.DescribeUserAgent(client.UserAgent),
+		ExpiresAt: time.Now().Add(s.magicLinks.TTL),
+	}
+	if err := s.magicLinkRepo.Create(link); err != nil {
+		return fmt.Errorf("failed to save sign-in link: %w", err)
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nSign in to your account with this link:\n%s\n\n"+
+		"It was requested from %s and expires in %d minutes. It can be used once. "+
+		"If you did not ask to sign in, ignore this email.",
+		user.Username, s.magicLinkURL(token), link.Device, int(s.magicLinks.TTL.Minutes()))
+	if err := s.mailer.Send(user.Email, "Your sign-in link", body); err != nil {
+		return fmt.Errorf("failed to send sign-in link: %w", err)
+	}
+	return nil
+}
+
+// VerifyMagicLink signs in with the token of a sign-in link, using the link
+// up. A link opened on another device than the one that requested it needs
+// req.ConfirmDevice, so a forwarded or intercepted link does not sign in
+// unnoticed. Signing in from a new device sends an alert, as with passwords.
+func (s *AuthService) VerifyMagicLink(req *VerifyMagicLinkRequest, client ClientInfo) (*AuthResponse, error) {
+	if s.magicLinkRepo == nil {
+		return nil, notFoundError("sign-in links are not enabled")
+	}
+	if req == nil || strings.TrimSpace(req.Token) == "" {
+		return nil, validationError("token is required")
+	}
+
+	link, err := s.magicLinkRepo.GetByTokenHash(hashEmailToken(req.Token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, unauthorizedError("sign-in link is invalid or has expired")
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if link.UsedAt != nil || now.After(link.ExpiresAt) {
+		return nil, unauthorizedError("sign-in link is invalid or has expired")
+	}
+	device := utils.DescribeUserAgent(client.UserAgent)
+	if device != link.Device && !req.ConfirmDevice {
+		return nil, conflictError("this link was requested from %s; confirm to sign in on %s", link.Device, device)
+	}
+
+	fresh, err := s.magicLinkRepo.MarkUsed(link.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to use sign-in link: %w", err)
+	}
+	if !fresh {
+		return nil, unauthorizedError("sign-in link is invalid or has expired")
+	}
+
+	user, err := s.userRepo.GetByID(link.UserID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, unauthorizedError("sign-in link is invalid or has expired")
+		}
+		return nil, err
+	}
+	if user.Role == models.RoleSystem {
+		return nil, unauthorizedError("sign-in link is invalid or has expired")
+	}
+
+	newDevice, err := s.isNewDevice(user.ID, device)
+	if err != nil {
+		return nil, err
+	}
+	tokens, session, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+	if newDevice {
+		s.sendNewDeviceAlert(user, session)
+	}
+
+	user.Password = ""
+	return &AuthResponse{
+		User:   user,
+		Tokens: tokens,
+	}, nil
+}
+
+// magicLinkURL returns the emailed link of token
+func (s *AuthService) magicLinkURL(token string) string {
+	base := s.magicLinks.LinkURL
+	if base == "" {
+		base = s.publicURL + "/magic-link"
+	}
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	return base + separator + "token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// mailerFunc lets a function stand in for a mailer
+type mailerFunc func(to, subject, body string) error
+
+func (f mailerFunc) Send(to, subject, body string) error {
+	return f(to, subject, body)
+}
+
+const testUserAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"
+
+// requestTestMagicLink requests a sign-in link for reader and returns the
+// emailed token with the link as stored
+func requestTestMagicLink(t *testing.T, s *AuthService, magicLinkRepo *mocks.MagicLinkRepository) (string, *models.MagicLink) {
+	t.Helper()
+	var stored *models.MagicLink
+	magicLinkRepo.On("Create", mock.AnythingOfType("*models.MagicLink")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*models.MagicLink) }).
+		Return(nil).Once()
+	var body string
+	s.SetMailer(mailerFunc(func(to, subject, text string) error {
+		body = text
+		return nil
+	}))
+
+	require.NoError(t, s.RequestMagicLink(&MagicLinkRequest{Email: "reader@example.com"}, ClientInfo{UserAgent: testUserAgent}))
+	match := regexp.MustCompile(`token=(\w+)`).FindStringSubmatch(body)
+	require.NotNil(t, match, "no sign-in link in %q", body)
+	require.NotNil(t, stored)
+	return match[1], stored
+}
+
+func TestMagicLinkExpiry(t *testing.T) {
+	reader := &models.User{ID: 3, Username: "reader", Email: "reader@example.com", Role: models.RoleUser}
+	userRepo := new(mocks.UserRepository)
+	userRepo.On("GetByEmail", "reader@example.com").Return(reader, nil)
+	userRepo.On("GetByID", uint(3)).Return(reader, nil)
+	magicLinkRepo := new(mocks.MagicLinkRepository)
+	magicLinkRepo.On("CountByUserSince", uint(3), mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+
+	s := NewAuthService(userRepo, "test-secret")
+	s.SetMagicLinks(magicLinkRepo, MagicLinkOptions{TTL: 15 * time.Minute, MaxPerHour: 3, LinkURL: "https://blog.example.com/magic-link"})
+
+	// Links are stored hashed and expire after the TTL
+	before := time.Now()
+	token, link := requestTestMagicLink(t, s, magicLinkRepo)
+	assert.Equal(t, hashEmailToken(token), link.TokenHash)
+	assert.WithinDuration(t, before.Add(15*time.Minute), link.ExpiresAt, time.Second)
+
+	verify := &VerifyMagicLinkRequest{Token: token}
+	client := ClientInfo{UserAgent: testUserAgent}
+	magicLinkRepo.On("GetByTokenHash", link.TokenHash).Return(link, nil)
+
+	// An expired link is refused before it is used up
+	link.ExpiresAt = time.Now().Add(-time.Second)
+	_, err := s.VerifyMagicLink(verify, client)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	magicLinkRepo.AssertNotCalled(t, "MarkUsed", mock.Anything, mock.Anything)
+
+	// A link still in time signs in once
+	link.ExpiresAt = time.Now().Add(time.Minute)
+	magicLinkRepo.On("MarkUsed", link.ID, mock.AnythingOfType("time.Time")).Return(true, nil).Once()
+	response, err := s.VerifyMagicLink(verify, client)
+	require.NoError(t, err)
+	assert.Equal(t, reader.ID, response.User.ID)
+	assert.NotEmpty(t, response.Tokens.AccessToken)
+
+	usedAt := time.Now()
+	link.UsedAt = &usedAt
+	_, err = s.VerifyMagicLink(verify, client)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	// Unknown tokens are refused alike
+	magicLinkRepo.On("GetByTokenHash", hashEmailToken("not-a-token")).Return(nil, repositories.ErrNotFound).Once()
+	_, err = s.VerifyMagicLink(&VerifyMagicLinkRequest{Token: "not-a-token"}, client)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	magicLinkRepo.AssertExpectations(t)
+}
@@ -1,19 +1,35 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
 	"go-blog/internal/utils"
+	"log/slog"
+	"net/url"
 	"strings"
-
-	"gorm.io/gorm"
+	"time"
 )
 
+// emailChangeTTL is how long an email change confirmation link stays valid
+const emailChangeTTL = 24 * time.Hour
+
 // AuthService handles authentication operations
 type AuthService struct {
-	userRepo  repositories.UserRepository
-	jwtSecret string
+	userRepo         repositories.UserRepository
+	refreshTokenRepo repositories.RefreshTokenRepository
+	sessionRepo      repositories.SessionRepository
+	jwtSecret        string
+	mailer           Mailer
+	publicURL        string
+	newDeviceAlerts  bool
+	policies         *PolicyService // optional, requires accepting the current policies to register
+	magicLinkRepo    repositories.MagicLinkRepository
+	magicLinks       MagicLinkOptions
 }
 
 // RegisterRequest represents user registration data
@@ -21,6 +37,9 @@ type RegisterRequest struct {
 	Username string `json:"username" validate:"required,min=3,max=50"`
 	Email    string `json:"email" validate:"required,email,max=100"`
 	Password string `json:"password" validate:"required,min=8,max=255"`
+	// AcceptPolicies confirms the user accepts the current policies, which is
+	// required once any are published
+	AcceptPolicies bool `json:"accept_policies"`
 }
 
 // LoginRequest represents user login data
@@ -29,6 +48,25 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// ChangePasswordRequest represents a password change by the signed-in user
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=255"`
+}
+
+// ChangeEmailRequest starts an email change; the new address must be confirmed
+type ChangeEmailRequest struct {
+	Email    string `json:"email" validate:"required,email,max=100"`
+	Password string `json:"password" validate:"required"`
+}
+
+// ClientInfo describes the client a sign-in or token refresh comes from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Location  string // coarse location such as a country code, empty when unknown
+}
+
 // AuthResponse represents authentication response
 type AuthResponse struct {
 	User   *models.User       `json:"user"`
@@ -40,11 +78,46 @@ func NewAuthService(userRepo repositories.UserRepository, jwtSecret string) *Aut
 	return &AuthService{
 		userRepo:  userRepo,
 		jwtSecret: jwtSecret,
+		mailer:    LogMailer{},
 	}
 }
 
-// Register creates a new user account
-func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
+// SetMailer sets the mailer used for email change confirmations
+func (s *AuthService) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// SetRefreshTokenRepository enables refresh token rotation: each refresh token
+// can be used once, and reusing one revokes every token of its sign-in
+func (s *AuthService) SetRefreshTokenRepository(refreshTokenRepo repositories.RefreshTokenRepository) {
+	s.refreshTokenRepo = refreshTokenRepo
+}
+
+// SetSessionRepository enables session tracking: every sign-in is recorded with
+// its client, can be listed and signed out on its own
+func (s *AuthService) SetSessionRepository(sessionRepo repositories.SessionRepository) {
+	s.sessionRepo = sessionRepo
+}
+
+// SetNewDeviceAlerts sets whether users are emailed when they sign in from a
+// device they have not used before. It requires session tracking.
+func (s *AuthService) SetNewDeviceAlerts(enabled bool) {
+	s.newDeviceAlerts = enabled
+}
+
+// SetPolicyService makes registering require accepting the current policies,
+// and records the acceptance
+func (s *AuthService) SetPolicyService(policies *PolicyService) {
+	s.policies = policies
+}
+
+// SetPublicURL sets the base URL of links sent by email
+func (s *AuthService) SetPublicURL(publicURL string) {
+	s.publicURL = strings.TrimSuffix(publicURL, "/")
+}
+
+// Register creates a new user account and signs it in from client
+func (s *AuthService) Register(req *RegisterRequest, client ClientInfo) (*AuthResponse, error) {
 	// Validate input
 	if err := s.validateRegisterRequest(req); err != nil {
 		return nil, err
@@ -52,20 +125,40 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 
 	// Check if user already exists by email
 	existingUser, err := s.userRepo.GetByEmail(req.Email)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+		return nil, conflictError("user with this email already exists")
 	}
 
 	// Check if username is taken
 	existingUser, err = s.userRepo.GetByUsername(req.Username)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+	if existingUser != nil {
+		return nil, conflictError("username is already taken")
+	}
+
+	// The profile handle derives from the username and must be unique too
+	existingUser, err = s.userRepo.GetByHandle(models.DefaultHandle(req.Username))
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
 		return nil, err
 	}
 	if existingUser != nil {
-		return nil, errors.New("username is already taken")
+		return nil, conflictError("username is already taken")
+	}
+
+	// Registering accepts the current policies, which the client has shown
+	var policies []models.Policy
+	if s.policies != nil {
+		if policies, err = s.policies.Current(); err != nil {
+			return nil, err
+		}
+		if len(policies) > 0 && !req.AcceptPolicies {
+			return nil, validationError("the current terms and policies must be accepted")
+		}
 	}
 
 	// Hash password
@@ -84,11 +177,17 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, errors.New("failed to create user")
 	}
+	if s.policies != nil {
+		// The account exists already; a missing acceptance asks the user to accept again
+		if err := s.policies.record(user.ID, policies, client.IP); err != nil {
+			slog.Error("Failed to record policy acceptance at registration", "user_id", user.ID, "error", err)
+		}
+	}
 
 	// Generate tokens
-	tokens, err := utils.GenerateTokenPair(user.ID, user.Username, user.Email, s.jwtSecret)
+	tokens, _, err := s.startSession(user, client)
 	if err != nil {
-		return nil, errors.New("failed to generate tokens")
+		return nil, err
 	}
 
 	// Remove password from response
@@ -100,8 +199,9 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 	}, nil
 }
 
-// Login authenticates a user and returns tokens
-func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
+// Login authenticates a user and returns tokens of a new session for client.
+// Signing in from a device the user has not used before sends them an alert.
+func (s *AuthService) Login(req *LoginRequest, client ClientInfo) (*AuthResponse, error) {
 	// Validate input
 	if err := s.validateLoginRequest(req); err != nil {
 		return nil, err
@@ -110,21 +210,29 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 	// Find user by email
 	user, err := s.userRepo.GetByEmail(req.Email)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("invalid email or password")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, unauthorizedError("invalid email or password")
 		}
 		return nil, err
 	}
 
-	// Check password
-	if !utils.CheckPassword(req.Password, user.Password) {
-		return nil, errors.New("invalid email or password")
+	// Check password; system accounts never sign in
+	if user.Role == models.RoleSystem || !utils.CheckPassword(req.Password, user.Password) {
+		return nil, unauthorizedError("invalid email or password")
+	}
+
+	newDevice, err := s.isNewDevice(user.ID, utils.DescribeUserAgent(client.UserAgent))
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate tokens
-	tokens, err := utils.GenerateTokenPair(user.ID, user.Username, user.Email, s.jwtSecret)
+	tokens, session, err := s.startSession(user, client)
 	if err != nil {
-		return nil, errors.New("failed to generate tokens")
+		return nil, err
+	}
+	if newDevice {
+		s.sendNewDeviceAlert(user, session)
 	}
 
 	// Remove password from response
@@ -138,76 +246,388 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 
 // ValidateToken validates a JWT token and returns the user ID
 func (s *AuthService) ValidateToken(tokenString string) (uint, error) {
-	return utils.ExtractUserID(tokenString, s.jwtSecret)
+	claims, err := utils.ValidateJWTOfType(tokenString, s.jwtSecret, utils.TokenTypeAccess)
+	if err != nil {
+		return 0, err
+	}
+	return claims.UserID, nil
 }
 
 // GetUserFromToken validates token and returns user information
 func (s *AuthService) GetUserFromToken(tokenString string) (*models.User, error) {
-	claims, err := utils.ValidateJWT(tokenString, s.jwtSecret)
+	user, _, err := s.Authenticate(tokenString)
+	return user, err
+}
+
+// Authenticate validates an access token and returns its user and the session it
+// belongs to, which is empty for tokens issued without one
+func (s *AuthService) Authenticate(tokenString string) (*models.User, string, error) {
+	// Refresh tokens are only accepted by RefreshToken and Logout
+	claims, err := utils.ValidateJWTOfType(tokenString, s.jwtSecret, utils.TokenTypeAccess)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	user, err := s.userRepo.GetByID(claims.UserID)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	// Tokens issued before a password change carry a stale version
+	if claims.Version != user.TokenVersion {
+		return nil, "", unauthorizedError("token has been revoked")
+	}
+
+	// Access tokens of a signed out session stop working before they expire
+	if _, err := s.activeSession(claims.Session); err != nil {
+		return nil, "", err
 	}
 
 	// Remove password from response
 	user.Password = ""
-	return user, nil
+	return user, claims.Session, nil
 }
 
-// RefreshToken generates new tokens using refresh token
-func (s *AuthService) RefreshToken(refreshToken string) (*utils.TokenPair, error) {
-	claims, err := utils.ValidateJWT(refreshToken, s.jwtSecret)
+// RefreshToken generates new tokens using refresh token and records the refresh
+// on the token's session
+func (s *AuthService) RefreshToken(refreshToken string, client ClientInfo) (*utils.TokenPair, error) {
+	claims, err := utils.ValidateJWTOfType(refreshToken, s.jwtSecret, utils.TokenTypeRefresh)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, unauthorizedError("invalid refresh token")
 	}
 
 	// Verify user still exists
 	user, err := s.userRepo.GetByID(claims.UserID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, notFoundError("user not found")
+	}
+
+	if claims.Version != user.TokenVersion {
+		return nil, unauthorizedError("invalid refresh token")
+	}
+
+	// With rotation the presented token is used up and the new one joins its family
+	family := claims.Session
+	if s.refreshTokenRepo != nil {
+		family, err = s.useRefreshToken(claims.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	session, err := s.activeSession(family)
+	if err != nil {
+		return nil, err
 	}
 
 	// Generate new token pair
-	tokens, err := utils.GenerateTokenPair(user.ID, user.Username, user.Email, s.jwtSecret)
+	tokens, err := s.issueTokens(user, family)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.touchSession(user, family, session, client, tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Logout signs out the session of the refresh token, revoking every token rotated
+// from the same sign-in. Invalid or unknown tokens are ignored, as there is
+// nothing left to revoke.
+func (s *AuthService) Logout(refreshToken string) error {
+	if refreshToken == "" {
+		return nil
+	}
+
+	claims, err := utils.ValidateJWTOfType(refreshToken, s.jwtSecret, utils.TokenTypeRefresh)
+	if err != nil {
+		return nil
+	}
+
+	family := claims.Session
+	if s.refreshTokenRepo != nil && claims.ID != "" {
+		stored, err := s.refreshTokenRepo.GetByTokenID(claims.ID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			family = stored.FamilyID
+		}
+	}
+	if family == "" {
+		return nil
+	}
+
+	return s.revokeSession(family)
+}
+
+// ChangePassword replaces the user's password after checking the current one. It
+// signs out every session and returns tokens of a new session for client.
+func (s *AuthService) ChangePassword(userID uint, req *ChangePasswordRequest, client ClientInfo) (*utils.TokenPair, error) {
+	if req == nil || strings.TrimSpace(req.CurrentPassword) == "" {
+		return nil, validationError("current password is required")
+	}
+	if len(req.NewPassword) < 8 || len(req.NewPassword) > 255 {
+		return nil, validationError("password must be between 8 and 255 characters")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, err
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, user.Password) {
+		return nil, unauthorizedError("current password is incorrect")
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return nil, validationError("new password must differ from the current password")
+	}
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, errors.New("failed to hash password")
+	}
+
+	user.Password = hashedPassword
+	user.TokenVersion++
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("failed to update password")
+	}
+
+	// The version bump already rejects old tokens; this ends their sessions too
+	if _, err := s.revokeOtherSessions(user.ID, ""); err != nil {
+		return nil, err
+	}
+
+	tokens, _, err := s.startSession(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// RequestEmailChange records the new address as pending and mails a confirmation
+// link to it. The email only changes once ConfirmEmailChange is called with the token.
+func (s *AuthService) RequestEmailChange(userID uint, req *ChangeEmailRequest) error {
+	if req == nil || strings.TrimSpace(req.Email) == "" {
+		return validationError("email is required")
+	}
+	if len(req.Email) > 100 {
+		return validationError("email must be less than 100 characters")
+	}
+	if strings.TrimSpace(req.Password) == "" {
+		return validationError("password is required")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("user not found")
+		}
+		return err
+	}
+
+	if !utils.CheckPassword(req.Password, user.Password) {
+		return unauthorizedError("password is incorrect")
+	}
+	if strings.EqualFold(req.Email, user.Email) {
+		return validationError("new email must differ from the current email")
+	}
+	if err := s.checkEmailAvailable(req.Email); err != nil {
+		return err
+	}
+
+	token, err := generateEmailToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(emailChangeTTL)
+	user.PendingEmail = req.Email
+	user.EmailChangeToken = hashEmailToken(token)
+	user.EmailChangeExpiresAt = &expiresAt
+	if err := user.Validate(); err != nil {
+		var fields models.ValidationErrors
+		if errors.As(err, &fields) {
+			return fieldValidationError(fields)
+		}
+		return validationError("%s", err.Error())
+	}
+	if err := s.userRepo.Update(user); err != nil {
+		return errors.New("failed to save email change")
+	}
+
+	link := s.publicURL + "/api/auth/confirm-email?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nConfirm %s as the new email address of your account:\n%s\n\n"+
+		"The link expires in %d hours. If you did not request this change, ignore this email.",
+		user.Username, req.Email, link, int(emailChangeTTL.Hours()))
+	if err := s.mailer.Send(req.Email, "Confirm your new email address", body); err != nil {
+		return fmt.Errorf("failed to send confirmation email: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange applies the pending email change matching token and notifies
+// the previous address
+func (s *AuthService) ConfirmEmailChange(token string) (*models.User, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, validationError("token is required")
+	}
+
+	user, err := s.userRepo.GetByEmailChangeToken(hashEmailToken(token))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("email change request not found")
+		}
+		return nil, err
+	}
+
+	if user.EmailChangeExpiresAt == nil || time.Now().After(*user.EmailChangeExpiresAt) {
+		return nil, validationError("email change link has expired")
+	}
+	// The address may have been claimed since the change was requested
+	if err := s.checkEmailAvailable(user.PendingEmail); err != nil {
+		return nil, err
+	}
+
+	previousEmail := user.Email
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.EmailChangeToken = ""
+	user.EmailChangeExpiresAt = nil
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, errors.New("failed to update email")
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nThe email address of your account was changed to %s. "+
+		"If you did not make this change, reset your password immediately.", user.Username, user.Email)
+	if err := s.mailer.Send(previousEmail, "Your email address was changed", body); err != nil {
+		// The change itself succeeded; a missed notice is not worth failing it
+		slog.Error("Failed to notify of email change", "email", previousEmail, "error", err)
+	}
+
+	// Remove password from response
+	user.Password = ""
+	return user, nil
+}
+
+// issueTokens generates a token pair for the user belonging to the session family.
+// With rotation enabled the refresh token is recorded in the family.
+func (s *AuthService) issueTokens(user *models.User, family string) (*utils.TokenPair, error) {
+	tokens, err := utils.GenerateSessionTokenPair(user.ID, user.Username, user.Email, user.TokenVersion, family, s.jwtSecret)
 	if err != nil {
 		return nil, errors.New("failed to generate tokens")
 	}
 
+	if s.refreshTokenRepo == nil {
+		return tokens, nil
+	}
+	err = s.refreshTokenRepo.Create(&models.RefreshToken{
+		TokenID:   tokens.RefreshID,
+		FamilyID:  family,
+		UserID:    user.ID,
+		ExpiresAt: tokens.RefreshExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record refresh token: %w", err)
+	}
 	return tokens, nil
 }
 
+// useRefreshToken marks the refresh token with the given jti as used and returns
+// its family. A token that was used before has been copied, so its family is
+// revoked: the thief and the owner both have to sign in again.
+func (s *AuthService) useRefreshToken(tokenID string) (string, error) {
+	if tokenID == "" {
+		return "", unauthorizedError("invalid refresh token")
+	}
+
+	stored, err := s.refreshTokenRepo.GetByTokenID(tokenID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return "", unauthorizedError("invalid refresh token")
+		}
+		return "", err
+	}
+	if stored.RevokedAt != nil {
+		return "", unauthorizedError("refresh token has been revoked")
+	}
+
+	fresh, err := s.refreshTokenRepo.MarkUsed(stored.ID, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to use refresh token: %w", err)
+	}
+	if !fresh {
+		if err := s.revokeSession(stored.FamilyID); err != nil {
+			return "", err
+		}
+		slog.Warn("Refresh token reuse detected; signed out the session", "user_id", stored.UserID)
+		return "", unauthorizedError("refresh token has already been used")
+	}
+	return stored.FamilyID, nil
+}
+
+// checkEmailAvailable returns a conflict error when email belongs to another account
+func (s *AuthService) checkEmailAvailable(email string) error {
+	existingUser, err := s.userRepo.GetByEmail(email)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+	if existingUser != nil {
+		return conflictError("email is already taken")
+	}
+	return nil
+}
+
+// generateEmailToken returns a random URL-safe token for a link sent by email
+func generateEmailToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// hashEmailToken hashes an emailed token for storage, so a leaked database
+// cannot be used to confirm pending changes or sign in
+func hashEmailToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 // validateRegisterRequest validates registration request
 func (s *AuthService) validateRegisterRequest(req *RegisterRequest) error {
 	if req == nil {
-		return errors.New("registration request is required")
+		return validationError("registration request is required")
 	}
 
 	if strings.TrimSpace(req.Username) == "" {
-		return errors.New("username is required")
+		return validationError("username is required")
 	}
 
 	if len(req.Username) < 3 || len(req.Username) > 50 {
-		return errors.New("username must be between 3 and 50 characters")
+		return validationError("username must be between 3 and 50 characters")
 	}
 
 	if strings.TrimSpace(req.Email) == "" {
-		return errors.New("email is required")
+		return validationError("email is required")
 	}
 
 	if len(req.Email) > 100 {
-		return errors.New("email must be less than 100 characters")
+		return validationError("email must be less than 100 characters")
 	}
 
 	if strings.TrimSpace(req.Password) == "" {
-		return errors.New("password is required")
+		return validationError("password is required")
 	}
 
 	if len(req.Password) < 8 || len(req.Password) > 255 {
-		return errors.New("password must be between 8 and 255 characters")
+		return validationError("password must be between 8 and 255 characters")
 	}
 
 	return nil
@@ -216,15 +636,15 @@ func (s *AuthService) validateRegisterRequest(req *RegisterRequest) error {
 // validateLoginRequest validates login request
 func (s *AuthService) validateLoginRequest(req *LoginRequest) error {
 	if req == nil {
-		return errors.New("login request is required")
+		return validationError("login request is required")
 	}
 
 	if strings.TrimSpace(req.Email) == "" {
-		return errors.New("email is required")
+		return validationError("email is required")
 	}
 
 	if strings.TrimSpace(req.Password) == "" {
-		return errors.New("password is required")
+		return validationError("password is required")
 	}
 
 	return nil
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/utils"
+)
+
+// ListSessions lists the user's active sessions, most recently used first. The
+// session the request was made with, current, is flagged.
+func (s *AuthService) ListSessions(userID uint, current string) ([]models.Session, error) {
+	if s.sessionRepo == nil {
+		return nil, errors.New("session repository not available")
+	}
+
+	sessions, err := s.sessionRepo.ListActive(userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	for i := range sessions {
+		sessions[i].Current = current != "" && sessions[i].FamilyID == current
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs out one of the user's sessions. Its refresh tokens stop
+// working and so do its access tokens.
+func (s *AuthService) RevokeSession(userID, sessionID uint) error {
+	if s.sessionRepo == nil {
+		return errors.New("session repository not available")
+	}
+
+	session, err := s.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("session not found")
+		}
+		return err
+	}
+	// Other users' sessions are reported as missing rather than forbidden
+	if session.UserID != userID || session.RevokedAt != nil {
+		return notFoundError("session not found")
+	}
+
+	return s.revokeSession(session.FamilyID)
+}
+
+// RevokeOtherSessions signs out every session of the user except current, the
+// one the request was made with, and returns how many were signed out
+func (s *AuthService) RevokeOtherSessions(userID uint, current string) (int, error) {
+	if s.sessionRepo == nil {
+		return 0, errors.New("session repository not available")
+	}
+	return s.revokeOtherSessions(userID, current)
+}
+
+// startSession signs user in from client: it starts a new session family, records
+// the session when tracking is enabled and issues its first token pair
+func (s *AuthService) startSession(user *models.User, client ClientInfo) (*utils.TokenPair, *models.Session, error) {
+	family, err := generateSessionID()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.issueTokens(user, family)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.sessionRepo == nil {
+		return tokens, nil, nil
+	}
+
+	session := newSession(user.ID, family, client, tokens.RefreshExpiresAt)
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, nil, fmt.Errorf("failed to record session: %w", err)
+	}
+	return tokens, session, nil
+}
+
+// activeSession returns the tracked session of family, or nil when sessions are
+// not tracked or the family predates tracking. Signed out sessions are rejected.
+func (s *AuthService) activeSession(family string) (*models.Session, error) {
+	if s.sessionRepo == nil || family == "" {
+		return nil, nil
+	}
+
+	session, err := s.sessionRepo.GetByFamilyID(family)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, unauthorizedError("session has been signed out")
+	}
+	return session, nil
+}
+
+// touchSession records a refresh of family from client. Families that predate
+// session tracking get their session recorded now, so they can be listed and
+// signed out like any other.
+func (s *AuthService) touchSession(user *models.User, family string, session *models.Session, client ClientInfo, tokens *utils.TokenPair) error {
+	if s.sessionRepo == nil || family == "" {
+		return nil
+	}
+
+	if session == nil {
+		if err := s.sessionRepo.Create(newSession(user.ID, family, client, tokens.RefreshExpiresAt)); err != nil {
+			return fmt.Errorf("failed to record session: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.sessionRepo.Touch(family, clipRunes(client.IP, 45), time.Now(), tokens.RefreshExpiresAt); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
+// revokeSession revokes every refresh token of family and signs its session out
+func (s *AuthService) revokeSession(family string) error {
+	now := time.Now()
+	if s.refreshTokenRepo != nil {
+		if err := s.refreshTokenRepo.RevokeFamily(family, now); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+	}
+	if s.sessionRepo != nil {
+		if err := s.sessionRepo.Revoke(family, now); err != nil {
+			return fmt.Errorf("failed to revoke session: %w", err)
+		}
+	}
+	return nil
+}
+
+// revokeOtherSessions signs out the user's active sessions except keep, which
+// may be empty to sign out all of them
+func (s *AuthService) revokeOtherSessions(userID uint, keep string) (int, error) {
+	if s.sessionRepo == nil {
+		return 0, nil
+	}
+
+	sessions, err := s.sessionRepo.ListActive(userID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.FamilyID == keep {
+			continue
+		}
+		if err := s.revokeSession(session.FamilyID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+	return revoked, nil
+}
+
+// isNewDevice reports whether the user is about to sign in from a device none of
+// their sessions used before. The first tracked sign-in is not reported, as
+// there is nothing to compare it with.
+func (s *AuthService) isNewDevice(userID uint, device string) (bool, error) {
+	if s.sessionRepo == nil || !s.newDeviceAlerts {
+		return false, nil
+	}
+
+	count, err := s.sessionRepo.CountByUser(userID)
+	if err != nil {
+		return false, err
+	}
+	if count == 0 {
+		return false, nil
+	}
+
+	known, err := s.sessionRepo.HasDevice(userID, device)
+	if err != nil {
+		return false, err
+	}
+	return !known, nil
+}
+
+// sendNewDeviceAlert emails the user about a sign-in from a new device. The
+// sign-in itself succeeded, so a failed alert is only logged.
+func (s *AuthService) sendNewDeviceAlert(user *models.User, session *models.Session) {
+	if session == nil {
+		return
+	}
+
+	var details strings.Builder
+	fmt.Fprintf(&details, "Device: %s\n", session.Device)
+	if session.IP != "" {
+		fmt.Fprintf(&details, "IP address: %s\n", session.IP)
+	}
+	if session.Location != "" {
+		fmt.Fprintf(&details, "Location: %s\n", session.Location)
+	}
+	fmt.Fprintf(&details, "Time: %s\n", session.CreatedAt.UTC().Format("2006-01-02 15:04 MST"))
+
+	body := fmt.Sprintf("Hi %s,\n\nYour account was signed in to from a new device:\n\n%s\n"+
+		"If this was you, there is nothing to do. Otherwise change your password, which "+
+		"signs out every session, or sign the session out from your account settings.",
+		user.Username, details.String())
+	if err := s.mailer.Send(user.Email, "New sign-in to your account", body); err != nil {
+		slog.Error("Failed to send new device alert", "user_id", user.ID, "error", err)
+	}
+}
+
+// newSession builds the session record of a sign-in from client
+func newSession(userID uint, family string, client ClientInfo, expiresAt time.Time) *models.Session {
+	now := time.Now()
+	return &models.Session{
+		UserID:     userID,
+		FamilyID:   family,
+		IP:         clipRunes(client.IP, 45),
+		UserAgent:  clipRunes(client.UserAgent, 255),
+		Device:     clipRunes(utils.DescribeUserAgent(client.UserAgent), 100),
+		Location:   clipRunes(strings.TrimSpace(client.Location), 100),
+		LastUsedAt: now,
+		ExpiresAt:  expiresAt,
+		CreatedAt:  now,
+	}
+}
+
+// generateSessionID returns a random session family ID
+func generateSessionID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate session ID: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// clipRunes shortens value to at most n runes so it fits its column
+func clipRunes(value string, n int) string {
+	runes := []rune(value)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return value
+}
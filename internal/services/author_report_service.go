@@ -0,0 +1,186 @@
+package services
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// reportTopArticles is how many of an author's most viewed articles a report lists
+const reportTopArticles = 5
+
+// AuthorReportService compiles the monthly statistics of each author's articles
+// and emails them to the authors who did not opt out
+type AuthorReportService struct {
+	reportRepo      repositories.AuthorReportRepository
+	userRepo        repositories.UserRepository
+	settingsService *UserSettingsService
+	mailer          Mailer
+	articleURL      string
+}
+
+// AuthorReport is the statistics of an author's articles over one month
+type AuthorReport struct {
+	AuthorID    uint                     `json:"author_id"`
+	Month       string                   `json:"month"` // YYYY-MM
+	Activity    models.AuthorActivity    `json:"activity"`
+	Previous    models.AuthorActivity    `json:"previous"` // the month before, for comparison
+	TopArticles []models.ArticleActivity `json:"top_articles"`
+}
+
+// MonthlyReportRun is the outcome of sending one month's reports
+type MonthlyReportRun struct {
+	Month   string `json:"month"`
+	Sent    int    `json:"sent"`
+	Skipped int    `json:"skipped"` // opted out, nothing happened or already sent
+	Failed  int    `json:"failed"`
+}
+
+// NewAuthorReportService creates a new author report service
+func NewAuthorReportService(
+	reportRepo repositories.AuthorReportRepository,
+	userRepo repositories.UserRepository,
+	settingsService *UserSettingsService,
+	mailer Mailer,
+) *AuthorReportService {
+	return &AuthorReportService{
+		reportRepo:      reportRepo,
+		userRepo:        userRepo,
+		settingsService: settingsService,
+		mailer:          mailer,
+		articleURL:      "/articles/{slug}",
+	}
+}
+
+// SetArticleURL sets the public article URL linked from reports; {slug} is
+// replaced with the article's slug
+func (s *AuthorReportService) SetArticleURL(articleURL string) {
+	s.articleURL = articleURL
+}
+
+// Build compiles the report of the author for the month containing month
+func (s *AuthorReportService) Build(authorID uint, month time.Time) (*AuthorReport, error) {
+	from := monthStart(month)
+	to := from.AddDate(0, 1, 0)
+
+	activity, err := s.reportRepo.Activity(authorID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count author activity: %w", err)
+	}
+	previous, err := s.reportRepo.Activity(authorID, from.AddDate(0, -1, 0), from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count author activity: %w", err)
+	}
+	top, err := s.reportRepo.TopArticles(authorID, from, to, reportTopArticles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top articles: %w", err)
+	}
+
+	return &AuthorReport{
+		AuthorID:    authorID,
+		Month:       from.Format("2006-01"),
+		Activity:    *activity,
+		Previous:    *previous,
+		TopArticles: top,
+	}, nil
+}
+
+// SendMonthlyReports emails every author the report of the month before now.
+// Each author is sent a month's report at most once, so the job can be rerun;
+// a failing author is counted and skipped.
+func (s *AuthorReportService) SendMonthlyReports(now time.Time) (*MonthlyReportRun, error) {
+	month := monthStart(now).AddDate(0, -1, 0)
+	authors, err := s.reportRepo.AuthorIDs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get authors: %w", err)
+	}
+
+	run := &MonthlyReportRun{Month: month.Format("2006-01")}
+	for _, authorID := range authors {
+		sent, err := s.sendReport(authorID, month)
+		switch {
+		case err != nil:
+			slog.Error("Failed to send monthly report", "month", run.Month, "author_id", authorID, "error", err)
+			run.Failed++
+		case sent:
+			run.Sent++
+		default:
+			run.Skipped++
+		}
+	}
+	return run, nil
+}
+
+// sendReport emails the author their report of month unless they opted out,
+// nothing happened on their articles or it was sent before
+func (s *AuthorReportService) sendReport(authorID uint, month time.Time) (bool, error) {
+	settings, err := s.settingsService.Get(authorID)
+	if err != nil {
+		return false, err
+	}
+	if !settings.WantsMonthlyReport() {
+		return false, nil
+	}
+
+	report, err := s.Build(authorID, month)
+	if err != nil {
+		return false, err
+	}
+	if report.Activity.Empty() {
+		return false, nil
+	}
+
+	author, err := s.userRepo.GetByID(authorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get author: %w", err)
+	}
+
+	// Recorded first: a report that failed to send is lost rather than sent twice
+	first, err := s.reportRepo.MarkSent(authorID, report.Month)
+	if err != nil {
+		return false, fmt.Errorf("failed to record report delivery: %w", err)
+	}
+	if !first {
+		return false, nil
+	}
+
+	monthName := month.Format("January 2006")
+	if err := s.mailer.Send(author.Email, "Your articles in "+monthName, s.reportBody(author, report, month)); err != nil {
+		return false, fmt.Errorf("failed to send report: %w", err)
+	}
+	return true, nil
+}
+
+// reportBody renders report as the plain-text email body
+func (s *AuthorReportService) reportBody(author *models.User, report *AuthorReport, month time.Time) string {
+	previousMonth := month.AddDate(0, -1, 0).Format("January")
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\nHere is how your articles did in %s.\n\n", author.Username, month.Format("January 2006"))
+	fmt.Fprintf(&body, "Views: %d (%d in %s)\n", report.Activity.Views, report.Previous.Views, previousMonth)
+	fmt.Fprintf(&body, "Likes: %d (%d in %s)\n", report.Activity.Likes, report.Previous.Likes, previousMonth)
+	fmt.Fprintf(&body, "Comments: %d (%d in %s)\n", report.Activity.Comments, report.Previous.Comments, previousMonth)
+	fmt.Fprintf(&body, "New followers: %d (%d in %s)\n", report.Activity.NewFollowers, report.Previous.NewFollowers, previousMonth)
+
+	if len(report.TopArticles) > 0 {
+		body.WriteString("\nMost read:\n")
+		for i, article := range report.TopArticles {
+			link := strings.ReplaceAll(s.articleURL, "{slug}", url.PathEscape(article.Slug))
+			fmt.Fprintf(&body, "%d. %s (%d views) %s\n", i+1, article.Title, article.Views, link)
+		}
+	}
+
+	body.WriteString("\nYou can turn these reports off in your email settings.")
+	return body.String()
+}
+
+// monthStart returns the first instant of the month containing t, in UTC
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
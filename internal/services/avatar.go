@@ -0,0 +1,184 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // register decoders accepted for avatar uploads
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"log/slog"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	// MaxAvatarBytes is the largest avatar upload accepted
+	MaxAvatarBytes = 5 << 20
+	// maxAvatarPixels bounds decoded dimensions so small files cannot expand into huge images
+	maxAvatarPixels = 5000
+)
+
+// AvatarSizes are the square sizes, in pixels, every uploaded avatar is stored at.
+// The largest is used as the user's avatar URL.
+var AvatarSizes = []int{64, 128, models.AvatarSize}
+
+// AvatarResponse lists the URLs of an uploaded avatar by size
+type AvatarResponse struct {
+	AvatarURL string         `json:"avatar_url"`
+	Sizes     map[int]string `json:"sizes,omitempty"`
+}
+
+// SetAvatar decodes an uploaded JPEG, PNG, GIF or WebP image, stores it center-cropped
+// at each of AvatarSizes and makes it the user's avatar, replacing any previous upload
+func (s *UserService) SetAvatar(userID uint, r io.Reader) (*AvatarResponse, error) {
+	if s.storage == nil {
+		return nil, errors.New("avatar storage not available")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, err
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, MaxAvatarBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read avatar: %w", err)
+	}
+	if len(data) > MaxAvatarBytes {
+		return nil, validationError("avatar must be at most %d MB", MaxAvatarBytes>>20)
+	}
+
+	img, err := decodeAvatar(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys follow the content, so stored files never change and can be cached for good
+	sum := sha256.Sum256(data)
+	key := fmt.Sprintf("avatars/%d/%s", userID, hex.EncodeToString(sum[:8]))
+	if key == user.AvatarKey {
+		return s.avatarResponse(user), nil
+	}
+
+	for _, size := range AvatarSizes {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resizeSquare(img, size), &jpeg.Options{Quality: 85}); err != nil {
+			return nil, fmt.Errorf("failed to encode avatar: %w", err)
+		}
+		if err := s.storage.Put(avatarKey(key, size), &buf, "image/jpeg"); err != nil {
+			s.deleteAvatarFiles(key)
+			return nil, fmt.Errorf("failed to store avatar: %w", err)
+		}
+	}
+
+	previousKey := user.AvatarKey
+	user.AvatarKey = key
+	user.AvatarURL = s.storage.URL(avatarKey(key, models.AvatarSize))
+	if err := s.userRepo.Update(user); err != nil {
+		s.deleteAvatarFiles(key)
+		return nil, fmt.Errorf("failed to update avatar: %w", err)
+	}
+
+	if previousKey != "" {
+		s.deleteAvatarFiles(previousKey)
+	}
+
+	return s.avatarResponse(user), nil
+}
+
+// RemoveAvatar deletes the user's avatar so their profile falls back to Gravatar
+func (s *UserService) RemoveAvatar(userID uint) (*AvatarResponse, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, err
+	}
+
+	previousKey := user.AvatarKey
+	user.AvatarKey = ""
+	user.AvatarURL = ""
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update avatar: %w", err)
+	}
+
+	if previousKey != "" && s.storage != nil {
+		s.deleteAvatarFiles(previousKey)
+	}
+
+	return s.avatarResponse(user), nil
+}
+
+// avatarResponse lists the stored sizes of an uploaded avatar, or just the
+// fallback URL when the user has none
+func (s *UserService) avatarResponse(user *models.User) *AvatarResponse {
+	response := &AvatarResponse{AvatarURL: user.Avatar()}
+	if user.AvatarKey != "" && s.storage != nil {
+		response.Sizes = make(map[int]string, len(AvatarSizes))
+		for _, size := range AvatarSizes {
+			response.Sizes[size] = s.storage.URL(avatarKey(user.AvatarKey, size))
+		}
+	}
+	return response
+}
+
+// deleteAvatarFiles removes every stored size of an avatar. Failures only leave
+// orphaned files behind, so they are logged rather than returned.
+func (s *UserService) deleteAvatarFiles(key string) {
+	for _, size := range AvatarSizes {
+		if err := s.storage.Delete(avatarKey(key, size)); err != nil {
+			slog.Error("Failed to delete avatar", "key", avatarKey(key, size), "error", err)
+		}
+	}
+}
+
+func avatarKey(key string, size int) string {
+	return fmt.Sprintf("%s-%d.jpg", key, size)
+}
+
+// decodeAvatar decodes an uploaded image after checking its dimensions
+func decodeAvatar(data []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, validationError("avatar must be a JPEG, PNG, GIF or WebP image")
+	}
+	if cfg.Width > maxAvatarPixels || cfg.Height > maxAvatarPixels {
+		return nil, validationError("avatar must be at most %dx%d pixels", maxAvatarPixels, maxAvatarPixels)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, validationError("avatar image is corrupt")
+	}
+	return img, nil
+}
+
+// resizeSquare center-crops img to a square and scales it to size pixels,
+// flattening transparency onto white since avatars are stored as JPEG
+func resizeSquare(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-side)/2,
+		bounds.Min.Y+(bounds.Dy()-side)/2,
+	))
+
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Over, nil)
+	return dst
+}
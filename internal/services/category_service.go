@@ -7,8 +7,6 @@ import (
 	"go-blog/internal/repositories"
 	"go-blog/internal/utils"
 	"strings"
-
-	"gorm.io/gorm"
 )
 
 type CategoryService struct {
@@ -26,14 +24,20 @@ func NewCategoryService(categoryRepo repositories.CategoryRepository, articleRep
 
 // CreateCategoryRequest represents the request to create a category
 type CreateCategoryRequest struct {
-	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Name        string `json:"name" validate:"required,min=1,max=100,excludes=:"`
 	Description string `json:"description" validate:"omitempty,max=1000"`
+	CoverURL    string `json:"cover_url" validate:"omitempty,url,max=255"`
+	IconURL     string `json:"icon_url" validate:"omitempty,url,max=255"`
+	AccentColor string `json:"accent_color" validate:"omitempty,hex_color"` // #rrggbb
 }
 
 // UpdateCategoryRequest represents the request to update a category
 type UpdateCategoryRequest struct {
-	Name        string `json:"name" validate:"required,min=1,max=100"`
+	Name        string `json:"name" validate:"required,min=1,max=100,excludes=:"`
 	Description string `json:"description" validate:"omitempty,max=1000"`
+	CoverURL    string `json:"cover_url" validate:"omitempty,url,max=255"`
+	IconURL     string `json:"icon_url" validate:"omitempty,url,max=255"`
+	AccentColor string `json:"accent_color" validate:"omitempty,hex_color"` // #rrggbb
 }
 
 // CategoryWithStats represents a category with article statistics
@@ -45,7 +49,7 @@ type CategoryWithStats struct {
 // Create creates a new category
 func (s *CategoryService) Create(req *CreateCategoryRequest) (*models.Category, error) {
 	if req == nil {
-		return nil, errors.New("create request cannot be nil")
+		return nil, validationError("create request cannot be nil")
 	}
 
 	// Generate slug from name
@@ -56,17 +60,20 @@ func (s *CategoryService) Create(req *CreateCategoryRequest) (*models.Category,
 
 	// Check if category with same slug already exists
 	existing, err := s.categoryRepo.GetBySlug(slug)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
 		return nil, fmt.Errorf("failed to check existing category: %w", err)
 	}
 	if existing != nil {
-		return nil, errors.New("category with this name already exists")
+		return nil, conflictError("category with this name already exists")
 	}
 
 	category := &models.Category{
 		Name:        strings.TrimSpace(req.Name),
 		Description: strings.TrimSpace(req.Description),
 		Slug:        slug,
+		CoverURL:    strings.TrimSpace(req.CoverURL),
+		IconURL:     strings.TrimSpace(req.IconURL),
+		AccentColor: strings.ToLower(req.AccentColor),
 	}
 
 	if err := s.categoryRepo.Create(category); err != nil {
@@ -79,13 +86,13 @@ func (s *CategoryService) Create(req *CreateCategoryRequest) (*models.Category,
 // GetByID retrieves a category by ID
 func (s *CategoryService) GetByID(id uint) (*models.Category, error) {
 	if id == 0 {
-		return nil, errors.New("category ID cannot be zero")
+		return nil, validationError("category ID cannot be zero")
 	}
 
 	category, err := s.categoryRepo.GetByID(id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("category not found")
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -96,13 +103,13 @@ func (s *CategoryService) GetByID(id uint) (*models.Category, error) {
 // GetBySlug retrieves a category by slug
 func (s *CategoryService) GetBySlug(slug string) (*models.Category, error) {
 	if slug == "" {
-		return nil, errors.New("category slug cannot be empty")
+		return nil, validationError("category slug cannot be empty")
 	}
 
 	category, err := s.categoryRepo.GetBySlug(slug)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("category not found")
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -147,17 +154,17 @@ func (s *CategoryService) ListWithStats() ([]CategoryWithStats, error) {
 // Update updates a category
 func (s *CategoryService) Update(id uint, req *UpdateCategoryRequest) (*models.Category, error) {
 	if id == 0 {
-		return nil, errors.New("category ID cannot be zero")
+		return nil, validationError("category ID cannot be zero")
 	}
 	if req == nil {
-		return nil, errors.New("update request cannot be nil")
+		return nil, validationError("update request cannot be nil")
 	}
 
 	// Get existing category
 	category, err := s.categoryRepo.GetByID(id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("category not found")
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -171,11 +178,11 @@ func (s *CategoryService) Update(id uint, req *UpdateCategoryRequest) (*models.C
 	// Check if another category with same slug exists (excluding current category)
 	if newSlug != category.Slug {
 		existing, err := s.categoryRepo.GetBySlug(newSlug)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
 			return nil, fmt.Errorf("failed to check existing category: %w", err)
 		}
 		if existing != nil && existing.ID != id {
-			return nil, errors.New("category with this name already exists")
+			return nil, conflictError("category with this name already exists")
 		}
 	}
 
@@ -183,6 +190,9 @@ func (s *CategoryService) Update(id uint, req *UpdateCategoryRequest) (*models.C
 	category.Name = strings.TrimSpace(req.Name)
 	category.Description = strings.TrimSpace(req.Description)
 	category.Slug = newSlug
+	category.CoverURL = strings.TrimSpace(req.CoverURL)
+	category.IconURL = strings.TrimSpace(req.IconURL)
+	category.AccentColor = strings.ToLower(req.AccentColor)
 
 	if err := s.categoryRepo.Update(category); err != nil {
 		return nil, fmt.Errorf("failed to update category: %w", err)
@@ -194,14 +204,14 @@ func (s *CategoryService) Update(id uint, req *UpdateCategoryRequest) (*models.C
 // Delete deletes a category
 func (s *CategoryService) Delete(id uint) error {
 	if id == 0 {
-		return errors.New("category ID cannot be zero")
+		return validationError("category ID cannot be zero")
 	}
 
 	// Check if category exists
 	category, err := s.categoryRepo.GetByID(id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("category not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("category not found")
 		}
 		return fmt.Errorf("failed to get category: %w", err)
 	}
@@ -213,7 +223,7 @@ func (s *CategoryService) Delete(id uint) error {
 	}
 
 	if count > 0 {
-		return fmt.Errorf("cannot delete category '%s' because it has %d articles", category.Name, count)
+		return conflictError("cannot delete category '%s' because it has %d articles", category.Name, count)
 	}
 
 	if err := s.categoryRepo.Delete(id); err != nil {
@@ -226,7 +236,7 @@ func (s *CategoryService) Delete(id uint) error {
 // GetCategoryArticles retrieves articles for a specific category with pagination
 func (s *CategoryService) GetCategoryArticles(categoryID uint, page, limit int) ([]models.Article, int64, error) {
 	if categoryID == 0 {
-		return nil, 0, errors.New("category ID cannot be zero")
+		return nil, 0, validationError("category ID cannot be zero")
 	}
 
 	// Validate pagination parameters
@@ -240,8 +250,8 @@ func (s *CategoryService) GetCategoryArticles(categoryID uint, page, limit int)
 	// Check if category exists
 	_, err := s.categoryRepo.GetByID(categoryID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, 0, errors.New("category not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, 0, notFoundError("category not found")
 		}
 		return nil, 0, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -260,7 +270,7 @@ func (s *CategoryService) GetCategoryArticles(categoryID uint, page, limit int)
 // GetCategoryArticlesBySlug retrieves articles for a specific category by slug with pagination
 func (s *CategoryService) GetCategoryArticlesBySlug(slug string, page, limit int) ([]models.Article, int64, error) {
 	if slug == "" {
-		return nil, 0, errors.New("category slug cannot be empty")
+		return nil, 0, validationError("category slug cannot be empty")
 	}
 
 	// Get category by slug
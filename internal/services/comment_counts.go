@@ -0,0 +1,47 @@
+package services
+
+import (
+	"fmt"
+
+	"go-blog/internal/repositories"
+)
+
+// CommentCountReport compares the stored comment counters of articles to the
+// comments counted for them
+type CommentCountReport struct {
+	Consistent bool                             `json:"consistent"`
+	Drifted    int64                            `json:"drifted"`  // articles whose counter is off
+	Articles   []repositories.CommentCountDrift `json:"articles"` // the first drifted articles by ID
+}
+
+// ReconcileCommentCounts corrects the comment counters that drifted, such as
+// after comments were inserted or removed outside the comment service, and
+// returns how many articles were corrected
+func (s *StatisticsService) ReconcileCommentCounts() (int64, error) {
+	corrected, err := s.articleRepo.ReconcileCommentCounts()
+	if err != nil {
+		return 0, fmt.Errorf("failed to reconcile comment counts: %w", err)
+	}
+	return corrected, nil
+}
+
+// GetCommentCountReport lists up to limit articles whose comment counter
+// drifted from their comments
+func (s *StatisticsService) GetCommentCountReport(limit int) (*CommentCountReport, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	drift, total, err := s.articleRepo.CommentCountDrift(limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check comment counts: %w", err)
+	}
+	if drift == nil {
+		drift = []repositories.CommentCountDrift{}
+	}
+	return &CommentCountReport{
+		Consistent: total == 0,
+		Drifted:    total,
+		Articles:   drift,
+	}, nil
+}
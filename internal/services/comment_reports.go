@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// ReportCommentRequest represents a user's report of a comment
+type ReportCommentRequest struct {
+	Reason  models.ReportReason `json:"reason" validate:"required,oneof=spam abuse harassment off_topic other"`
+	Details string              `json:"details,omitempty" validate:"omitempty,max=500"`
+}
+
+// ReviewAction is an administrator's decision on a reported comment
+type ReviewAction string
+
+const (
+	ReviewDismiss ReviewAction = "dismiss" // the comment is fine; show it again if hidden
+	ReviewHide    ReviewAction = "hide"    // keep the comment out of listings
+	ReviewDelete  ReviewAction = "delete"  // delete the comment
+)
+
+// ReviewReportsRequest resolves the pending reports of a comment
+type ReviewReportsRequest struct {
+	Action ReviewAction `json:"action" validate:"required,oneof=dismiss hide delete"`
+}
+
+// ReportQueueItem is a reported comment awaiting review with its pending reports
+type ReportQueueItem struct {
+	Comment     models.Comment         `json:"comment"`
+	ReportCount int64                  `json:"report_count"`
+	Reports     []models.CommentReport `json:"reports"`
+}
+
+// ReportComment records a report against a comment and hides the comment once it
+// has as many pending reports as the report threshold
+func (s *CommentService) ReportComment(commentID, reporterID uint, req *ReportCommentRequest) (*models.CommentReport, error) {
+	if s.reportRepo == nil {
+		return nil, errors.New("comment reports not available")
+	}
+
+	comment, err := s.GetByID(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID == reporterID {
+		return nil, validationError("you cannot report your own comment")
+	}
+
+	exists, err := s.reportRepo.Exists(commentID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, conflictError("you have already reported this comment")
+	}
+
+	report := &models.CommentReport{
+		CommentID:  commentID,
+		ReporterID: reporterID,
+		Reason:     req.Reason,
+		Details:    req.Details,
+	}
+	if err := s.reportRepo.Create(report); err != nil {
+		return nil, err
+	}
+
+	if s.reportThreshold > 0 && !comment.Hidden {
+		pending, err := s.reportRepo.CountPending(commentID)
+		if err != nil {
+			return nil, err
+		}
+		if pending >= int64(s.reportThreshold) {
+			if err := s.commentRepo.SetHidden(commentID, true); err != nil {
+				return nil, fmt.Errorf("failed to hide comment: %w", err)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// GetReportQueue returns reported comments awaiting review, most reported first
+func (s *CommentService) GetReportQueue(page, limit int) ([]ReportQueueItem, int64, error) {
+	if s.reportRepo == nil {
+		return nil, 0, errors.New("comment reports not available")
+	}
+
+	queue, total, err := s.reportRepo.ListQueue((page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uint, 0, len(queue))
+	for _, entry := range queue {
+		ids = append(ids, entry.CommentID)
+	}
+	reports, err := s.reportRepo.ListPending(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	byComment := make(map[uint][]models.CommentReport, len(queue))
+	for _, report := range reports {
+		byComment[report.CommentID] = append(byComment[report.CommentID], report)
+	}
+
+	items := make([]ReportQueueItem, 0, len(queue))
+	for _, entry := range queue {
+		comment, err := s.commentRepo.GetByID(entry.CommentID)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue // deleted since the queue was read
+			}
+			return nil, 0, err
+		}
+		items = append(items, ReportQueueItem{
+			Comment:     *comment,
+			ReportCount: entry.ReportCount,
+			Reports:     byComment[entry.CommentID],
+		})
+	}
+
+	return items, total, nil
+}
+
+// ReviewReports applies an administrator's decision to a reported comment and
+// resolves its pending reports
+func (s *CommentService) ReviewReports(commentID uint, action ReviewAction) error {
+	if s.reportRepo == nil {
+		return errors.New("comment reports not available")
+	}
+
+	if _, err := s.GetByID(commentID); err != nil {
+		return err
+	}
+
+	var err error
+	switch action {
+	case ReviewDismiss:
+		err = s.commentRepo.SetHidden(commentID, false)
+	case ReviewHide:
+		err = s.commentRepo.SetHidden(commentID, true)
+	case ReviewDelete:
+		err = s.commentRepo.Delete(commentID)
+	default:
+		return validationError("action must be one of dismiss, hide or delete")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to apply review: %w", err)
+	}
+
+	return s.reportRepo.Resolve(commentID, time.Now())
+}
@@ -2,15 +2,33 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
-	"gorm.io/gorm"
+	"go-blog/internal/sanitize"
+	"log/slog"
+	"strings"
+)
+
+const (
+	// commentExcerptLength is how much of a comment a notification quotes
+	commentExcerptLength = 200
+	// defaultReportThreshold is how many pending reports hide a comment
+	defaultReportThreshold = 3
 )
 
 type CommentService struct {
-	commentRepo repositories.CommentRepository
-	articleRepo repositories.ArticleRepository
-	userRepo    repositories.UserRepository
+	commentRepo         repositories.CommentRepository
+	articleRepo         repositories.ArticleRepository
+	userRepo            repositories.UserRepository
+	mentionRepo         repositories.MentionRepository
+	reportRepo          repositories.CommentReportRepository
+	subscriptionRepo    repositories.CommentSubscriptionRepository
+	notificationService *NotificationService
+	quotaService        *QuotaService
+	contentFilter       *ContentFilterService
+	reportThreshold     int
+	publicURL           string
 }
 
 // NewCommentService creates a new comment service
@@ -20,49 +38,140 @@ func NewCommentService(
 	userRepo repositories.UserRepository,
 ) *CommentService {
 	return &CommentService{
-		commentRepo: commentRepo,
-		articleRepo: articleRepo,
-		userRepo:    userRepo,
+		commentRepo:     commentRepo,
+		articleRepo:     articleRepo,
+		userRepo:        userRepo,
+		reportThreshold: defaultReportThreshold,
 	}
 }
 
-// Create creates a new comment with validation
-func (s *CommentService) Create(comment *models.Comment) error {
+// SetMentionRepository enables recording @handle mentions in comments
+func (s *CommentService) SetMentionRepository(mentionRepo repositories.MentionRepository) {
+	s.mentionRepo = mentionRepo
+}
+
+// SetReportRepository enables reporting comments for review
+func (s *CommentService) SetReportRepository(reportRepo repositories.CommentReportRepository) {
+	s.reportRepo = reportRepo
+}
+
+// SetReportThreshold sets how many pending reports hide a comment; 0 disables hiding
+func (s *CommentService) SetReportThreshold(threshold int) {
+	s.reportThreshold = threshold
+}
+
+// SetSubscriptionRepository enables watching comment threads. Commenters are
+// subscribed automatically and subscribers are notified of new comments.
+func (s *CommentService) SetSubscriptionRepository(subscriptionRepo repositories.CommentSubscriptionRepository) {
+	s.subscriptionRepo = subscriptionRepo
+}
+
+// SetPublicURL sets the base URL of the unsubscribe links in notification emails
+func (s *CommentService) SetPublicURL(publicURL string) {
+	s.publicURL = strings.TrimSuffix(publicURL, "/")
+}
+
+// SetNotificationService sets the service used to notify mentioned users and subscribers
+func (s *CommentService) SetNotificationService(notificationService *NotificationService) {
+	s.notificationService = notificationService
+}
+
+// SetQuotaService enables write quotas on comments
+func (s *CommentService) SetQuotaService(quotaService *QuotaService) {
+	s.quotaService = quotaService
+}
+
+// SetContentFilter enables checking comments against the content filter rules
+func (s *CommentService) SetContentFilter(contentFilter *ContentFilterService) {
+	s.contentFilter = contentFilter
+}
+
+// Create creates a new comment with validation, written from clientIP, which
+// may be empty outside HTTP requests
+func (s *CommentService) Create(comment *models.Comment, clientIP string) error {
+	// Strip unsafe markup on write; content that was nothing but markup is rejected
+	comment.Content = sanitize.Comment(comment.Content)
+	if comment.Content == "" {
+		return validationError("content cannot be empty")
+	}
+	filtered, err := s.filter(comment.Content)
+	if err != nil {
+		return err
+	}
+	comment.Content = filtered.Text
+	// A held comment stays hidden, and uncounted, until an administrator reviews it
+	held := filtered.Action == models.FilterHold
+	comment.Hidden = held
+
 	// Verify user exists
-	_, err := s.userRepo.GetByID(comment.UserID)
+	author, err := s.userRepo.GetByID(comment.UserID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("user not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("user not found")
 		}
 		return err
 	}
 
 	// Verify article exists
-	_, err = s.articleRepo.GetByID(comment.ArticleID)
+	article, err := s.articleRepo.GetByID(comment.ArticleID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("article not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("article not found")
 		}
 		return err
 	}
+	if !article.CommentsAllowed() {
+		return forbiddenError("comments are closed on this article")
+	}
 
 	// If this is a reply, verify parent comment exists and belongs to same article
 	if comment.ParentID != nil {
 		parentComment, err := s.commentRepo.GetByID(*comment.ParentID)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errors.New("parent comment not found")
+			if errors.Is(err, repositories.ErrNotFound) {
+				return notFoundError("parent comment not found")
 			}
 			return err
 		}
 
 		// Ensure parent comment belongs to the same article
 		if parentComment.ArticleID != comment.ArticleID {
-			return errors.New("parent comment must belong to the same article")
+			return validationError("parent comment must belong to the same article")
 		}
 	}
 
-	return s.commentRepo.Create(comment)
+	// The comment counts towards the quotas once it is valid
+	release := func() {}
+	if s.quotaService != nil {
+		if release, err = s.quotaService.ReserveComment(author, clientIP); err != nil {
+			return err
+		}
+	}
+	if err := s.commentRepo.Create(comment); err != nil {
+		release()
+		return err
+	}
+	comment.User = *author
+	if held {
+		// Mentioned users and subscribers hear of the comment once it is shown
+		return s.holdForReview(comment, filtered.Rule)
+	}
+	// The comment is approved as it is posted; inserts bypassing the service
+	// are left for the reconciliation job to count
+	if err := s.articleRepo.AdjustCommentCount(article.ID, 1); err != nil {
+		return fmt.Errorf("failed to count comment: %w", err)
+	}
+
+	if err := s.recordMentions(comment, author.Username, nil); err != nil {
+		return err
+	}
+
+	if s.subscriptionRepo != nil {
+		if _, err := s.subscribe(author.ID, article.ID); err != nil {
+			return err
+		}
+	}
+	return s.notifySubscribers(comment, article)
 }
 
 // GetByArticle retrieves comments for an article with threading
@@ -70,41 +179,81 @@ func (s *CommentService) GetByArticle(articleID uint) ([]models.Comment, error)
 	// Verify article exists
 	_, err := s.articleRepo.GetByID(articleID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("article not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
 		}
 		return nil, err
 	}
 
-	return s.commentRepo.GetByArticle(articleID)
+	comments, err := s.commentRepo.GetByArticle(articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := make([]*models.Comment, 0, len(comments))
+	for i := range comments {
+		roots = append(roots, &comments[i])
+	}
+	if err := s.attachMentions(roots...); err != nil {
+		return nil, err
+	}
+	redactTombstones(roots...)
+	return comments, nil
 }
 
 // GetByID retrieves a comment by ID
 func (s *CommentService) GetByID(id uint) (*models.Comment, error) {
 	comment, err := s.commentRepo.GetByID(id)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("comment not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("comment not found")
 		}
 		return nil, err
 	}
+
+	if err := s.attachMentions(comment); err != nil {
+		return nil, err
+	}
+	redactTombstones(comment)
 	return comment, nil
 }
 
 // Update updates a comment with authorization check
 func (s *CommentService) Update(commentID uint, userID uint, content string) (*models.Comment, error) {
+	content = sanitize.Comment(content)
+	if content == "" {
+		return nil, validationError("content cannot be empty")
+	}
+	filtered, err := s.filter(content)
+	if err != nil {
+		return nil, err
+	}
+	content = filtered.Text
+
 	// Get existing comment
 	comment, err := s.commentRepo.GetByID(commentID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("comment not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("comment not found")
 		}
 		return nil, err
 	}
 
 	// Check if user is the author of the comment
 	if comment.UserID != userID {
-		return nil, errors.New("unauthorized: can only update your own comments")
+		return nil, forbiddenError("unauthorized: can only update your own comments")
+	}
+	if comment.Tombstone {
+		return nil, notFoundError("comment not found")
+	}
+
+	// Remember who was already mentioned so an edit only notifies new mentions
+	if err := s.attachMentions(comment); err != nil {
+		return nil, err
+	}
+	previous := make(map[uint]bool, len(comment.Mentions))
+	for _, mention := range comment.Mentions {
+		previous[mention.UserID] = true
 	}
 
 	// Update content
@@ -113,25 +262,181 @@ func (s *CommentService) Update(commentID uint, userID uint, content string) (*m
 	if err != nil {
 		return nil, err
 	}
+	if filtered.Action == models.FilterHold {
+		if !comment.Hidden {
+			if err := s.commentRepo.SetHidden(comment.ID, true); err != nil {
+				return nil, fmt.Errorf("failed to hide comment: %w", err)
+			}
+			comment.Hidden = true
+		}
+		if err := s.holdForReview(comment, filtered.Rule); err != nil {
+			return nil, err
+		}
+	}
 
+	if err := s.recordMentions(comment, comment.User.Username, previous); err != nil {
+		return nil, err
+	}
 	return comment, nil
 }
 
-// Delete deletes a comment with authorization check
+// filter checks content against the content filter, if enabled
+func (s *CommentService) filter(content string) (*FilterResult, error) {
+	if s.contentFilter == nil {
+		return &FilterResult{Text: content}, nil
+	}
+	return s.contentFilter.Check(models.FilterScopeComments, content)
+}
+
+// holdForReview puts a hidden comment in the report queue on behalf of the
+// administrator who wrote the rule holding it, where dismissing the report
+// shows the comment
+func (s *CommentService) holdForReview(comment *models.Comment, rule *models.ContentFilterRule) error {
+	if s.reportRepo == nil {
+		return nil
+	}
+	exists, err := s.reportRepo.Exists(comment.ID, rule.CreatedBy)
+	if err != nil || exists {
+		return err
+	}
+	report := &models.CommentReport{
+		CommentID:  comment.ID,
+		ReporterID: rule.CreatedBy,
+		Reason:     models.ReportContentFilter,
+		Details:    fmt.Sprintf("Held by content filter rule #%d", rule.ID),
+	}
+	if err := s.reportRepo.Create(report); err != nil {
+		return fmt.Errorf("failed to hold comment for review: %w", err)
+	}
+	return nil
+}
+
+// Delete deletes a comment with authorization check. A comment with replies
+// stays in the thread as a tombstone.
 func (s *CommentService) Delete(commentID uint, userID uint) error {
 	// Get existing comment
 	comment, err := s.commentRepo.GetByID(commentID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("comment not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("comment not found")
 		}
 		return err
 	}
 
 	// Check if user is the author of the comment
 	if comment.UserID != userID {
-		return errors.New("unauthorized: can only delete your own comments")
+		return forbiddenError("unauthorized: can only delete your own comments")
 	}
 
 	return s.commentRepo.Delete(commentID)
-}
\ No newline at end of file
+}
+
+// recordMentions resolves the @handles in a comment to existing users, stores them
+// and notifies users who were not in previous, the IDs mentioned before an edit.
+// Unknown handles are not mentions and are ignored.
+func (s *CommentService) recordMentions(comment *models.Comment, author string, previous map[uint]bool) error {
+	if s.mentionRepo == nil {
+		return nil
+	}
+
+	var userIDs []uint
+	for _, handle := range models.ParseMentions(comment.Content) {
+		user, err := s.userRepo.GetByHandle(handle)
+		if err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	if err := s.mentionRepo.Replace(comment.ID, userIDs); err != nil {
+		return fmt.Errorf("failed to record mentions: %w", err)
+	}
+	comment.Mentions = nil
+	if err := s.attachMentions(comment); err != nil {
+		return err
+	}
+
+	if s.notificationService == nil {
+		return nil
+	}
+	for _, userID := range userIDs {
+		if previous[userID] || userID == comment.UserID {
+			continue
+		}
+		// The comment is saved; a failed notification should not fail the request
+		if err := s.notificationService.Notify(mentionNotification(comment, author, userID), true); err != nil {
+			slog.Error("Failed to notify user of mention", "user_id", userID, "comment_id", comment.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// attachMentions loads the mention metadata of comments and their replies
+func (s *CommentService) attachMentions(comments ...*models.Comment) error {
+	if s.mentionRepo == nil {
+		return nil
+	}
+
+	byID := make(map[uint]*models.Comment)
+	var collect func(comment *models.Comment)
+	collect = func(comment *models.Comment) {
+		byID[comment.ID] = comment
+		for i := range comment.Replies {
+			collect(&comment.Replies[i])
+		}
+	}
+	for _, comment := range comments {
+		collect(comment)
+	}
+
+	ids := make([]uint, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	refs, err := s.mentionRepo.ListByComments(ids)
+	if err != nil {
+		return fmt.Errorf("failed to load mentions: %w", err)
+	}
+	for _, ref := range refs {
+		if comment, ok := byID[ref.CommentID]; ok {
+			comment.Mentions = append(comment.Mentions, ref)
+		}
+	}
+	return nil
+}
+
+// redactTombstones hides who wrote the deleted comments kept for their replies
+func redactTombstones(comments ...*models.Comment) {
+	for _, comment := range comments {
+		if comment.Tombstone {
+			comment.UserID = 0
+			comment.User = models.User{}
+		}
+		for i := range comment.Replies {
+			redactTombstones(&comment.Replies[i])
+		}
+	}
+}
+
+// mentionNotification builds the notification sent to a user mentioned in comment
+func mentionNotification(comment *models.Comment, author string, userID uint) *models.Notification {
+	return &models.Notification{
+		UserID: userID,
+		Type:   models.NotificationMention,
+		Title:  fmt.Sprintf("%s mentioned you in a comment", author),
+		Body:   commentExcerpt(comment.Content),
+		Link:   fmt.Sprintf("/api/articles/%d/comments", comment.ArticleID),
+	}
+}
+
+// commentExcerpt shortens comment content for a notification
+func commentExcerpt(content string) string {
+	excerpt := []rune(content)
+	if len(excerpt) > commentExcerptLength {
+		return string(excerpt[:commentExcerptLength]) + "..."
+	}
+	return content
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// Subscribe makes the user watch an article's comment thread. Subscribing twice
+// returns the existing subscription.
+func (s *CommentService) Subscribe(userID, articleID uint) (*models.CommentSubscription, error) {
+	if s.subscriptionRepo == nil {
+		return nil, errors.New("comment subscriptions not available")
+	}
+
+	if _, err := s.articleRepo.GetByID(articleID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
+		}
+		return nil, err
+	}
+
+	return s.subscribe(userID, articleID)
+}
+
+// Unsubscribe stops the user watching an article's comment thread
+func (s *CommentService) Unsubscribe(userID, articleID uint) error {
+	if s.subscriptionRepo == nil {
+		return errors.New("comment subscriptions not available")
+	}
+
+	if err := s.subscriptionRepo.Delete(userID, articleID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("you are not subscribed to this article's comments")
+		}
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	return nil
+}
+
+// UnsubscribeByToken removes the subscription identified by the token of an
+// unsubscribe link, so email recipients can unsubscribe without signing in
+func (s *CommentService) UnsubscribeByToken(token string) error {
+	if s.subscriptionRepo == nil {
+		return errors.New("comment subscriptions not available")
+	}
+	if token == "" {
+		return validationError("unsubscribe token is required")
+	}
+
+	subscription, err := s.subscriptionRepo.GetByToken(token)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("subscription not found or already removed")
+		}
+		return err
+	}
+
+	return s.Unsubscribe(subscription.UserID, subscription.ArticleID)
+}
+
+// IsSubscribed reports whether the user watches an article's comment thread
+func (s *CommentService) IsSubscribed(userID, articleID uint) (bool, error) {
+	if s.subscriptionRepo == nil {
+		return false, errors.New("comment subscriptions not available")
+	}
+
+	if _, err := s.subscriptionRepo.Get(userID, articleID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// subscribe returns the user's subscription to an article, creating it if needed
+func (s *CommentService) subscribe(userID, articleID uint) (*models.CommentSubscription, error) {
+	subscription, err := s.subscriptionRepo.Get(userID, articleID)
+	if err == nil {
+		return subscription, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	token, err := generateUnsubscribeToken()
+	if err != nil {
+		return nil, err
+	}
+	subscription = &models.CommentSubscription{
+		UserID:           userID,
+		ArticleID:        articleID,
+		UnsubscribeToken: token,
+	}
+	if err := s.subscriptionRepo.Create(subscription); err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+	return subscription, nil
+}
+
+// notifySubscribers tells the users watching the thread about a new comment. The
+// commenter and users already notified of a mention in it are skipped.
+func (s *CommentService) notifySubscribers(comment *models.Comment, article *models.Article) error {
+	if s.subscriptionRepo == nil || s.notificationService == nil {
+		return nil
+	}
+
+	subscriptions, err := s.subscriptionRepo.ListByArticle(comment.ArticleID)
+	if err != nil {
+		return fmt.Errorf("failed to get comment subscriptions: %w", err)
+	}
+
+	skip := map[uint]bool{comment.UserID: true}
+	for _, mention := range comment.Mentions {
+		skip[mention.UserID] = true
+	}
+	for _, subscription := range subscriptions {
+		if skip[subscription.UserID] {
+			continue
+		}
+		notification := &models.Notification{
+			UserID:         subscription.UserID,
+			Type:           models.NotificationComment,
+			Title:          fmt.Sprintf("%s commented on %q", comment.User.Username, article.Title),
+			Body:           commentExcerpt(comment.Content),
+			Link:           fmt.Sprintf("/api/articles/%d/comments", comment.ArticleID),
+			UnsubscribeURL: s.publicURL + "/api/comment-subscriptions/unsubscribe?token=" + url.QueryEscape(subscription.UnsubscribeToken),
+		}
+		// The comment is saved; a failed notification should not fail the request
+		if err := s.notificationService.Notify(notification, true); err != nil {
+			slog.Error("Failed to notify user of comment", "user_id", subscription.UserID, "comment_id", comment.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// generateUnsubscribeToken returns a random token for unsubscribe links
+func generateUnsubscribeToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to generate unsubscribe token: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// filterRulesTTL is how long the compiled rules are used before they are read
+// again, so rules changed by another instance apply within a minute
+const filterRulesTTL = time.Minute
+
+// ContentFilterRuleRequest represents content filter rule creation and update data
+type ContentFilterRuleRequest struct {
+	Pattern string              `json:"pattern" validate:"required,min=1,max=255"`
+	Regex   bool                `json:"regex"`
+	Action  models.FilterAction `json:"action" validate:"required,oneof=block hold mask"`
+	Scope   models.FilterScope  `json:"scope,omitempty" validate:"omitempty,oneof=all comments titles"`
+}
+
+// FilterResult is the outcome of checking text against the content filter
+type FilterResult struct {
+	Text   string                    // the text with masked words replaced
+	Action models.FilterAction       // the strictest action of the matching rules, empty when none matched
+	Rule   *models.ContentFilterRule // the rule that decided the action
+}
+
+// compiledRule is a rule with its pattern compiled
+type compiledRule struct {
+	rule    models.ContentFilterRule
+	pattern *regexp.Regexp
+}
+
+// ContentFilterService checks comments and article titles against the word
+// and regular expression rules administrators manage
+type ContentFilterService struct {
+	ruleRepo repositories.ContentFilterRuleRepository
+
+	mu       sync.Mutex
+	rules    []compiledRule
+	loadedAt time.Time
+}
+
+// NewContentFilterService creates a new content filter service
+func NewContentFilterService(ruleRepo repositories.ContentFilterRuleRepository) *ContentFilterService {
+	return &ContentFilterService{
+		ruleRepo: ruleRepo,
+	}
+}
+
+// Check checks text of scope against the rules. Every masking rule is applied
+// to the returned text; of the other matching rules, blocking ones win over
+// holding ones. Text matching a blocking rule is reported as a validation error.
+func (s *ContentFilterService) Check(scope models.FilterScope, text string) (*FilterResult, error) {
+	rules, err := s.compiled()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FilterResult{Text: text}
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.rule.Applies(scope) || !rule.pattern.MatchString(result.Text) {
+			continue
+		}
+		switch rule.rule.Action {
+		case models.FilterBlock:
+			return nil, validationError("%s contains language that is not allowed", scopeNoun(scope))
+		case models.FilterHold:
+			if result.Action != models.FilterHold {
+				result.Action, result.Rule = models.FilterHold, &rule.rule
+			}
+		case models.FilterMask:
+			result.Text = rule.pattern.ReplaceAllStringFunc(result.Text, func(match string) string {
+				return strings.Repeat("*", len([]rune(match)))
+			})
+			if result.Action == "" {
+				result.Action, result.Rule = models.FilterMask, &rule.rule
+			}
+		}
+	}
+	return result, nil
+}
+
+// scopeNoun names the text of scope in error messages
+func scopeNoun(scope models.FilterScope) string {
+	if scope == models.FilterScopeTitles {
+		return "title"
+	}
+	return "comment"
+}
+
+// compiled returns the compiled rules, reading them again once they are older
+// than filterRulesTTL
+func (s *ContentFilterService) compiled() ([]compiledRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rules != nil && time.Since(s.loadedAt) < filterRulesTTL {
+		return s.rules, nil
+	}
+	rules, err := s.ruleRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list content filter rules: %w", err)
+	}
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		pattern, err := compileRule(rule.Pattern, rule.Regex)
+		if err != nil {
+			// Rules are checked when saved; skip ones saved before a check existed
+			continue
+		}
+		compiled = append(compiled, compiledRule{rule: rule, pattern: pattern})
+	}
+	s.rules, s.loadedAt = compiled, time.Now()
+	return compiled, nil
+}
+
+// invalidate makes the next check read the rules again
+func (s *ContentFilterService) invalidate() {
+	s.mu.Lock()
+	s.rules = nil
+	s.mu.Unlock()
+}
+
+// wordStart and wordEnd match phrases starting and ending with a word character
+var (
+	wordStart = regexp.MustCompile(`^\w`)
+	wordEnd   = regexp.MustCompile(`\w$`)
+)
+
+// compileRule compiles a rule pattern, case-insensitively. Words and phrases
+// match whole words only, so a rule for "ass" leaves "class" alone.
+func compileRule(pattern string, regex bool) (*regexp.Regexp, error) {
+	if regex {
+		return regexp.Compile("(?i)" + pattern)
+	}
+
+	words := strings.Fields(pattern)
+	for i, word := range words {
+		words[i] = regexp.QuoteMeta(word)
+	}
+	phrase := strings.Join(words, `\s+`)
+	if wordStart.MatchString(pattern) {
+		phrase = `\b` + phrase
+	}
+	if wordEnd.MatchString(pattern) {
+		phrase += `\b`
+	}
+	return regexp.Compile("(?i)" + phrase)
+}
+
+// List returns every content filter rule, oldest first
+func (s *ContentFilterService) List() ([]models.ContentFilterRule, error) {
+	return s.ruleRepo.List()
+}
+
+// Create adds a content filter rule on behalf of adminID
+func (s *ContentFilterService) Create(adminID uint, req *ContentFilterRuleRequest) (*models.ContentFilterRule, error) {
+	rule := &models.ContentFilterRule{CreatedBy: adminID}
+	if err := applyFilterRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Create(rule); err != nil {
+		return nil, fmt.Errorf("failed to create content filter rule: %w", err)
+	}
+	s.invalidate()
+	return rule, nil
+}
+
+// Update replaces the pattern, action and scope of a content filter rule
+func (s *ContentFilterService) Update(id uint, req *ContentFilterRuleRequest) (*models.ContentFilterRule, error) {
+	rule, err := s.getRule(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyFilterRuleRequest(rule, req); err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.Update(rule); err != nil {
+		return nil, fmt.Errorf("failed to update content filter rule: %w", err)
+	}
+	s.invalidate()
+	return rule, nil
+}
+
+// Delete removes a content filter rule
+func (s *ContentFilterService) Delete(id uint) error {
+	if _, err := s.getRule(id); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete content filter rule: %w", err)
+	}
+	s.invalidate()
+	return nil
+}
+
+func (s *ContentFilterService) getRule(id uint) (*models.ContentFilterRule, error) {
+	rule, err := s.ruleRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("content filter rule not found")
+		}
+		return nil, fmt.Errorf("failed to get content filter rule: %w", err)
+	}
+	return rule, nil
+}
+
+// applyFilterRuleRequest copies a validated request onto rule
+func applyFilterRuleRequest(rule *models.ContentFilterRule, req *ContentFilterRuleRequest) error {
+	pattern := strings.TrimSpace(req.Pattern)
+	if pattern == "" {
+		return validationError("pattern is required")
+	}
+	if _, err := compileRule(pattern, req.Regex); err != nil {
+		return validationError("pattern is not a valid regular expression: %v", err)
+	}
+
+	rule.Pattern = pattern
+	rule.Regex = req.Regex
+	rule.Action = req.Action
+	rule.Scope = req.Scope
+	if rule.Scope == "" {
+		rule.Scope = models.FilterScopeAll
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-blog/internal/repositories"
+	"go-blog/internal/sanitize"
+	"go-blog/internal/utils"
+)
+
+const (
+	// maxSuggestedTags caps how many tags a suggestion proposes
+	maxSuggestedTags = 5
+	// metaDescriptionLength is the length search engines show of a meta description
+	metaDescriptionLength = 160
+	// knownTagsForSuggestions is how many of the most used tags suggesters are
+	// told about, so they prefer tags the blog already has
+	knownTagsForSuggestions = 50
+)
+
+// ContentSuggester drafts the metadata of an article from its text, e.g. with
+// a language model. Implementations must not keep the draft.
+type ContentSuggester interface {
+	Suggest(ctx context.Context, draft ContentDraft) (*ContentSuggestions, error)
+}
+
+// ContentDraft is the article text a suggester works from
+type ContentDraft struct {
+	Title     string
+	Content   string
+	Tags      []string // tags the article already has
+	KnownTags []string // most used tags of the blog
+}
+
+// ContentSuggestions are proposed for the author to review; nothing is saved
+type ContentSuggestions struct {
+	Excerpt         string   `json:"excerpt"`
+	Tags            []string `json:"tags"`
+	MetaDescription string   `json:"meta_description"`
+}
+
+// SetContentSuggester enables suggesting excerpts, tags and meta descriptions
+// for articles
+func (s *ArticleService) SetContentSuggester(suggester ContentSuggester) {
+	s.suggester = suggester
+}
+
+// SuggestMetadata asks the content suggester for an excerpt, tags and a meta
+// description of one of the user's articles. The suggestions are cleaned up
+// to fit the article's fields; tags the article already has are left out.
+func (s *ArticleService) SuggestMetadata(ctx context.Context, articleID, userID uint) (*ContentSuggestions, error) {
+	if s.suggester == nil {
+		return nil, notFoundError("content suggestions are not enabled")
+	}
+
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
+		}
+		return nil, fmt.Errorf("failed to get article: %w", err)
+	}
+	if article.AuthorID != userID {
+		return nil, forbiddenError("unauthorized: you can only get suggestions for your own articles")
+	}
+
+	draft := ContentDraft{Title: article.Title, Content: article.Content}
+	has := make(map[string]bool, len(article.Tags))
+	for _, tag := range article.Tags {
+		draft.Tags = append(draft.Tags, tag.Name)
+		has[utils.NormalizeTagName(tag.Name)] = true
+	}
+	known, err := s.tagRepo.ListWithCounts(knownTagsForSuggestions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	for _, tag := range known {
+		draft.KnownTags = append(draft.KnownTags, tag.Name)
+	}
+
+	suggestions, err := s.suggester.Suggest(ctx, draft)
+	if err != nil {
+		return nil, err
+	}
+
+	cleaned := &ContentSuggestions{
+		Excerpt:         truncate(strings.TrimSpace(sanitize.Text(suggestions.Excerpt)), 500),
+		MetaDescription: truncateWords(strings.Join(strings.Fields(sanitize.Text(suggestions.MetaDescription)), " "), metaDescriptionLength),
+		Tags:            []string{},
+	}
+	for _, tag := range suggestions.Tags {
+		name := strings.TrimSpace(tag)
+		normalized := utils.NormalizeTagName(name)
+		if normalized == "" || len([]rune(name)) > 50 || has[normalized] {
+			continue
+		}
+		has[normalized] = true
+		cleaned.Tags = append(cleaned.Tags, name)
+		if len(cleaned.Tags) == maxSuggestedTags {
+			break
+		}
+	}
+	return cleaned, nil
+}
+
+// truncateWords shortens s to at most n runes, cutting between words
+func truncateWords(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	cut := string(runes[:n+1])
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		return strings.TrimSpace(cut[:i])
+	}
+	return string(runes[:n])
+}
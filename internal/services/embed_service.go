@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// embedTimeout bounds each request to an oEmbed provider
+	embedTimeout = 5 * time.Second
+	// maxEmbedResponse caps the oEmbed responses read from providers
+	maxEmbedResponse = 1 << 20
+	// maxCachedEmbeds caps how many resolved embeds are kept in memory
+	maxCachedEmbeds = 1000
+)
+
+// Embed providers articles may embed content from
+const (
+	EmbedProviderYouTube = "youtube"
+	EmbedProviderTwitter = "twitter"
+	EmbedProviderGist    = "gist"
+)
+
+// EmbedProviders lists every supported embed provider
+var EmbedProviders = []string{EmbedProviderYouTube, EmbedProviderTwitter, EmbedProviderGist}
+
+// EmbedOptions configures which links resolve to embeds and how
+type EmbedOptions struct {
+	Providers []string      // allowed providers, out of EmbedProviders
+	CacheTTL  time.Duration // how long resolved embeds are kept, 0 disables caching
+	Privacy   bool          // strip tracking from links and prefer privacy friendly embed markup
+}
+
+// Embed is the embeddable representation of a link, following the fields of
+// an oEmbed response
+type Embed struct {
+	URL          string `json:"url"` // the link, without tracking parameters in privacy mode
+	Provider     string `json:"provider"`
+	Type         string `json:"type"` // oEmbed type: video or rich
+	Title        string `json:"title,omitempty"`
+	AuthorName   string `json:"author_name,omitempty"`
+	AuthorURL    string `json:"author_url,omitempty"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
+}
+
+// embedProvider recognizes the links of one provider and resolves them
+type embedProvider struct {
+	name     string
+	hosts    []string
+	endpoint string // oEmbed endpoint, empty when the embed is built locally
+	// canonical returns the link without parameters that do not select the
+	// content, or false when the link is not embeddable content
+	canonical func(link *url.URL) (*url.URL, bool)
+}
+
+// gistPath matches the path of a gist: the owner, then the gist ID
+var gistPath = regexp.MustCompile(`^/([A-Za-z0-9-]+)/([0-9a-f]+)/?$`)
+
+var embedProviders = map[string]embedProvider{
+	EmbedProviderYouTube: {
+		name:      EmbedProviderYouTube,
+		hosts:     []string{"youtube.com", "www.youtube.com", "m.youtube.com", "youtu.be"},
+		endpoint:  "https://www.youtube.com/oembed",
+		canonical: canonicalYouTubeURL,
+	},
+	EmbedProviderTwitter: {
+		name:      EmbedProviderTwitter,
+		hosts:     []string{"twitter.com", "www.twitter.com", "mobile.twitter.com", "x.com", "www.x.com"},
+		endpoint:  "https://publish.twitter.com/oembed",
+		canonical: canonicalTweetURL,
+	},
+	EmbedProviderGist: {
+		name:  EmbedProviderGist,
+		hosts: []string{"gist.github.com"},
+		canonical: func(link *url.URL) (*url.URL, bool) {
+			if !gistPath.MatchString(link.Path) {
+				return nil, false
+			}
+			return &url.URL{Scheme: "https", Host: "gist.github.com", Path: strings.TrimSuffix(link.Path, "/")}, true
+		},
+	},
+}
+
+// EmbedService resolves links in article content to embeddable markup through
+// the oEmbed endpoints of an allowlist of providers, so clients never contact
+// the providers before rendering and get the same result from the cache.
+type EmbedService struct {
+	providers []embedProvider
+	cacheTTL  time.Duration
+	privacy   bool
+	client    *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*cachedEmbed
+}
+
+// cachedEmbed is a resolved embed with its expiry
+type cachedEmbed struct {
+	embed   *Embed
+	expires time.Time
+}
+
+// oEmbedResponse is the part of a provider's oEmbed response kept in embeds
+type oEmbedResponse struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	AuthorName   string `json:"author_name"`
+	AuthorURL    string `json:"author_url"`
+	HTML         string `json:"html"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// NewEmbedService creates an embed service resolving the links of the allowed
+// providers; unknown provider names are ignored
+func NewEmbedService(options EmbedOptions) *EmbedService {
+	s := &EmbedService{
+		cacheTTL: options.CacheTTL,
+		privacy:  options.Privacy,
+		client:   &http.Client{Timeout: embedTimeout},
+		cache:    make(map[string]*cachedEmbed),
+	}
+	for _, name := range options.Providers {
+		if provider, ok := embedProviders[strings.ToLower(name)]; ok {
+			s.providers = append(s.providers, provider)
+		}
+	}
+	return s
+}
+
+// SetClient sets the HTTP client the oEmbed endpoints are called with
+func (s *EmbedService) SetClient(client *http.Client) {
+	s.client = client
+}
+
+// Resolve returns the embed of link, from the cache when it was resolved
+// before. Links of providers that are not allowed are rejected.
+func (s *EmbedService) Resolve(ctx context.Context, link string) (*Embed, error) {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, validationError("url must be an absolute http or https link")
+	}
+
+	provider, canonical, ok := s.match(parsed)
+	if !ok {
+		return nil, validationError("url is not embeddable; supported providers: %s", strings.Join(s.providerNames(), ", "))
+	}
+	target := parsed
+	if s.privacy || provider.endpoint == "" {
+		// Drop tracking parameters such as si and utm_source
+		target = canonical
+	}
+
+	key := target.String()
+	if embed := s.cached(key); embed != nil {
+		return embed, nil
+	}
+
+	var embed *Embed
+	if provider.endpoint == "" {
+		embed = gistEmbed(target)
+	} else if embed, err = s.fetch(ctx, provider, target); err != nil {
+		return nil, err
+	}
+	s.store(key, embed)
+	return embed, nil
+}
+
+// match returns the allowed provider serving link and the canonical link
+func (s *EmbedService) match(link *url.URL) (embedProvider, *url.URL, bool) {
+	host := strings.ToLower(link.Hostname())
+	for _, provider := range s.providers {
+		for _, providerHost := range provider.hosts {
+			if host != providerHost {
+				continue
+			}
+			canonical, ok := provider.canonical(link)
+			return provider, canonical, ok
+		}
+	}
+	return embedProvider{}, nil, false
+}
+
+// providerNames returns the names of the allowed providers
+func (s *EmbedService) providerNames() []string {
+	names := make([]string, len(s.providers))
+	for i, provider := range s.providers {
+		names[i] = provider.name
+	}
+	return names
+}
+
+// fetch asks the provider's oEmbed endpoint for the embed of link
+func (s *EmbedService) fetch(ctx context.Context, provider embedProvider, link *url.URL) (*Embed, error) {
+	query := url.Values{"url": {link.String()}, "format": {"json"}}
+	if s.privacy && provider.name == EmbedProviderTwitter {
+		// Twitter must not use the embed for personalization, and its widget
+		// script is left for the client to load once the reader agreed to it
+		query.Set("dnt", "true")
+		query.Set("omit_script", "true")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, provider.endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, upstreamError("%s could not be reached", provider.name)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		// Deleted, private or unembeddable content
+		return nil, notFoundError("%s has no embeddable content at this url", provider.name)
+	case resp.StatusCode != http.StatusOK:
+		return nil, upstreamError("%s answered with status %d", provider.name, resp.StatusCode)
+	}
+
+	var response oEmbedResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxEmbedResponse)).Decode(&response); err != nil || response.HTML == "" {
+		return nil, upstreamError("%s answered with an invalid oEmbed response", provider.name)
+	}
+
+	embed := &Embed{
+		URL:          link.String(),
+		Provider:     provider.name,
+		Type:         response.Type,
+		Title:        response.Title,
+		AuthorName:   response.AuthorName,
+		AuthorURL:    response.AuthorURL,
+		HTML:         response.HTML,
+		Width:        response.Width,
+		Height:       response.Height,
+		ThumbnailURL: response.ThumbnailURL,
+	}
+	if s.privacy && provider.name == EmbedProviderYouTube {
+		// The no-cookie player sets no cookies until the video is played
+		embed.HTML = strings.ReplaceAll(embed.HTML, "https://www.youtube.com/embed/", "https://www.youtube-nocookie.com/embed/")
+	}
+	return embed, nil
+}
+
+// gistEmbed builds the embed of a gist, which has no oEmbed endpoint
+func gistEmbed(link *url.URL) *Embed {
+	return &Embed{
+		URL:      link.String(),
+		Provider: EmbedProviderGist,
+		Type:     "rich",
+		Title:    "Gist " + link.Path[strings.LastIndex(link.Path, "/")+1:],
+		HTML:     fmt.Sprintf(`<script src="%s.js"></script>`, link.String()),
+	}
+}
+
+// cached returns the live embed stored under key, or nil
+func (s *EmbedService) cached(key string) *Embed {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached, ok := s.cache[key]
+	if !ok || time.Now().After(cached.expires) {
+		return nil
+	}
+	return cached.embed
+}
+
+// store caches embed under key. When the cache is full, expired embeds are
+// dropped first and embed is not stored if none were.
+func (s *EmbedService) store(key string, embed *Embed) {
+	if s.cacheTTL <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := s.cache[key]; !ok && len(s.cache) >= maxCachedEmbeds {
+		for stored, cached := range s.cache {
+			if now.After(cached.expires) {
+				delete(s.cache, stored)
+			}
+		}
+		if len(s.cache) >= maxCachedEmbeds {
+			return
+		}
+	}
+	s.cache[key] = &cachedEmbed{embed: embed, expires: now.Add(s.cacheTTL)}
+}
+
+// youTubeID matches a YouTube video ID
+var youTubeID = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// canonicalYouTubeURL returns the watch URL of a video link, keeping only the
+// start time of its parameters
+func canonicalYouTubeURL(link *url.URL) (*url.URL, bool) {
+	var id string
+	switch {
+	case strings.EqualFold(link.Hostname(), "youtu.be"):
+		id = strings.Trim(link.Path, "/")
+	case link.Path == "/watch":
+		id = link.Query().Get("v")
+	case strings.HasPrefix(link.Path, "/shorts/"):
+		id = strings.Trim(strings.TrimPrefix(link.Path, "/shorts/"), "/")
+	case strings.HasPrefix(link.Path, "/embed/"):
+		id = strings.Trim(strings.TrimPrefix(link.Path, "/embed/"), "/")
+	}
+	if !youTubeID.MatchString(id) {
+		return nil, false
+	}
+
+	query := url.Values{"v": {id}}
+	if start := link.Query().Get("t"); start != "" {
+		query.Set("t", start)
+	}
+	return &url.URL{Scheme: "https", Host: "www.youtube.com", Path: "/watch", RawQuery: query.Encode()}, true
+}
+
+// tweetPath matches the path of a tweet: the account, then the status ID
+var tweetPath = regexp.MustCompile(`^/([A-Za-z0-9_]{1,15})/status(?:es)?/([0-9]+)/?$`)
+
+// canonicalTweetURL returns the twitter.com URL of a tweet link
+func canonicalTweetURL(link *url.URL) (*url.URL, bool) {
+	match := tweetPath.FindStringSubmatch(link.Path)
+	if match == nil {
+		return nil, false
+	}
+	return &url.URL{Scheme: "https", Host: "twitter.com", Path: "/" + match[1] + "/status/" + match[2]}, true
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-blog/internal/models"
+)
+
+// Error kinds returned by services. Match them with errors.Is;
+// handlers map each kind to an HTTP status code.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrValidation   = errors.New("validation failed")
+	ErrQuota        = errors.New("quota exceeded")
+	ErrUpstream     = errors.New("upstream failure")
+)
+
+// Error is a service error whose message is safe to return to clients
+type Error struct {
+	Kind    error
+	Message string
+	Fields  models.ValidationErrors // per-field details for validation errors
+
+	RetryAfter time.Duration // until a quota frees up, for quota errors
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap exposes the error kind to errors.Is
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func newError(kind error, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// notFoundError reports a missing resource
+func notFoundError(format string, args ...interface{}) error {
+	return newError(ErrNotFound, format, args...)
+}
+
+// conflictError reports a clash with existing state, such as a duplicate name
+func conflictError(format string, args ...interface{}) error {
+	return newError(ErrConflict, format, args...)
+}
+
+// forbiddenError reports an authenticated user acting on something they do not own
+func forbiddenError(format string, args ...interface{}) error {
+	return newError(ErrForbidden, format, args...)
+}
+
+// unauthorizedError reports missing or invalid credentials
+func unauthorizedError(format string, args ...interface{}) error {
+	return newError(ErrUnauthorized, format, args...)
+}
+
+// validationError reports invalid input
+func validationError(format string, args ...interface{}) error {
+	return newError(ErrValidation, format, args...)
+}
+
+// quotaError reports a write quota used up until retryAfter has passed
+func quotaError(retryAfter time.Duration, format string, args ...interface{}) error {
+	return &Error{Kind: ErrQuota, Message: fmt.Sprintf(format, args...), RetryAfter: retryAfter}
+}
+
+// upstreamError reports a third-party service failing to answer properly
+func upstreamError(format string, args ...interface{}) error {
+	return newError(ErrUpstream, format, args...)
+}
+
+// fieldValidationError reports invalid input with per-field details
+func fieldValidationError(fields models.ValidationErrors) error {
+	return &Error{Kind: ErrValidation, Message: "validation failed", Fields: fields}
+}
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+const (
+	// defaultSectionArticles is how many articles a section shows unless set
+	defaultSectionArticles = 6
+	// maxManualArticles bounds the articles picked for a manual section
+	maxManualArticles = 20
+)
+
+// HomepageService manages the homepage sections curated by admins and
+// composes the homepage from them
+type HomepageService struct {
+	sectionRepo  repositories.HomepageSectionRepository
+	articleRepo  repositories.ArticleRepository
+	categoryRepo repositories.CategoryRepository
+	tagRepo      repositories.TagRepository
+}
+
+// CreateHomepageSectionRequest represents homepage section creation data.
+// Category sections need category_id, tag sections tag_id and manual sections
+// article_ids.
+type CreateHomepageSectionRequest struct {
+	Title       string `json:"title" validate:"required,min=1,max=100"`
+	Type        string `json:"type" validate:"required,oneof=latest featured category tag manual"`
+	CategoryID  *uint  `json:"category_id,omitempty"`
+	TagID       *uint  `json:"tag_id,omitempty"`
+	ArticleIDs  []uint `json:"article_ids,omitempty" validate:"max=20"`
+	MaxArticles int    `json:"max_articles,omitempty" validate:"omitempty,min=1,max=20"`
+	Position    int    `json:"position"`
+}
+
+// UpdateHomepageSectionRequest represents homepage section update data;
+// omitted fields are left unchanged
+type UpdateHomepageSectionRequest struct {
+	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=100"`
+	Type        *string `json:"type,omitempty" validate:"omitempty,oneof=latest featured category tag manual"`
+	CategoryID  *uint   `json:"category_id,omitempty"`
+	TagID       *uint   `json:"tag_id,omitempty"`
+	ArticleIDs  []uint  `json:"article_ids,omitempty" validate:"max=20"`
+	MaxArticles *int    `json:"max_articles,omitempty" validate:"omitempty,min=1,max=20"`
+	Position    *int    `json:"position,omitempty"`
+}
+
+// HomepageBlock is a homepage section with its articles
+type HomepageBlock struct {
+	*models.HomepageSection
+	Articles []models.ArticleSummary `json:"articles"`
+}
+
+// NewHomepageService creates a new homepage service
+func NewHomepageService(sectionRepo repositories.HomepageSectionRepository, articleRepo repositories.ArticleRepository,
+	categoryRepo repositories.CategoryRepository, tagRepo repositories.TagRepository) *HomepageService {
+	return &HomepageService{
+		sectionRepo:  sectionRepo,
+		articleRepo:  articleRepo,
+		categoryRepo: categoryRepo,
+		tagRepo:      tagRepo,
+	}
+}
+
+// Create creates a homepage section
+func (s *HomepageService) Create(req *CreateHomepageSectionRequest) (*models.HomepageSection, error) {
+	if req == nil {
+		return nil, validationError("create request cannot be nil")
+	}
+
+	section := &models.HomepageSection{
+		Title:       strings.TrimSpace(req.Title),
+		Type:        models.HomepageSectionType(req.Type),
+		CategoryID:  req.CategoryID,
+		TagID:       req.TagID,
+		ArticleIDs:  req.ArticleIDs,
+		MaxArticles: req.MaxArticles,
+		Position:    req.Position,
+	}
+	if section.MaxArticles == 0 {
+		section.MaxArticles = defaultSectionArticles
+	}
+	if err := s.validate(section); err != nil {
+		return nil, err
+	}
+	if err := s.sectionRepo.Create(section); err != nil {
+		return nil, fmt.Errorf("failed to create homepage section: %w", err)
+	}
+
+	return section, nil
+}
+
+// List lists every homepage section in homepage order
+func (s *HomepageService) List() ([]models.HomepageSection, error) {
+	sections, err := s.sectionRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list homepage sections: %w", err)
+	}
+	return sections, nil
+}
+
+// Update updates a homepage section
+func (s *HomepageService) Update(id uint, req *UpdateHomepageSectionRequest) (*models.HomepageSection, error) {
+	if req == nil {
+		return nil, validationError("update request cannot be nil")
+	}
+
+	section, err := s.sectionRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("homepage section not found")
+		}
+		return nil, fmt.Errorf("failed to get homepage section: %w", err)
+	}
+
+	if req.Title != nil {
+		section.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Type != nil {
+		section.Type = models.HomepageSectionType(*req.Type)
+	}
+	if req.CategoryID != nil {
+		section.CategoryID = req.CategoryID
+	}
+	if req.TagID != nil {
+		section.TagID = req.TagID
+	}
+	if req.ArticleIDs != nil {
+		section.ArticleIDs = req.ArticleIDs
+	}
+	if req.MaxArticles != nil {
+		section.MaxArticles = *req.MaxArticles
+	}
+	if req.Position != nil {
+		section.Position = *req.Position
+	}
+
+	if err := s.validate(section); err != nil {
+		return nil, err
+	}
+	if err := s.sectionRepo.Update(section); err != nil {
+		return nil, fmt.Errorf("failed to update homepage section: %w", err)
+	}
+
+	return section, nil
+}
+
+// Delete removes a homepage section
+func (s *HomepageService) Delete(id uint) error {
+	if _, err := s.sectionRepo.GetByID(id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("homepage section not found")
+		}
+		return fmt.Errorf("failed to get homepage section: %w", err)
+	}
+
+	if err := s.sectionRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete homepage section: %w", err)
+	}
+	return nil
+}
+
+// Compose returns every homepage section in order with its published
+// articles. Sections whose category, tag or articles are gone come back empty.
+func (s *HomepageService) Compose() ([]HomepageBlock, error) {
+	sections, err := s.sectionRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list homepage sections: %w", err)
+	}
+
+	blocks := make([]HomepageBlock, len(sections))
+	for i := range sections {
+		articles, err := s.sectionArticles(&sections[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve homepage section %d: %w", sections[i].ID, err)
+		}
+		blocks[i] = HomepageBlock{HomepageSection: &sections[i], Articles: models.SummarizeArticles(articles)}
+	}
+	return blocks, nil
+}
+
+// sectionArticles returns the published articles a section shows
+func (s *HomepageService) sectionArticles(section *models.HomepageSection) ([]models.Article, error) {
+	filter := &repositories.ArticleFilter{Status: string(models.StatusPublished)}
+	sortBy := repositories.ArticleSort{Field: "published_at", Desc: true}
+
+	switch section.Type {
+	case models.HomepageSectionFeatured:
+		sortBy = repositories.ArticleSort{Field: "like_count", Desc: true}
+	case models.HomepageSectionCategory:
+		if section.CategoryID == nil {
+			return nil, nil
+		}
+		filter.CategoryIDs = []uint{*section.CategoryID}
+	case models.HomepageSectionTag:
+		if section.TagID == nil {
+			return nil, nil
+		}
+		filter.TagIDs = []uint{*section.TagID}
+	case models.HomepageSectionManual:
+		return s.manualArticles(section.ArticleIDs)
+	}
+
+	articles, _, err := s.articleRepo.ListFiltered(0, section.MaxArticles, filter, sortBy)
+	return articles, err
+}
+
+// manualArticles returns the published articles among ids, in their order
+func (s *HomepageService) manualArticles(ids []uint) ([]models.Article, error) {
+	now := time.Now()
+	articles := make([]models.Article, 0, len(ids))
+	for _, id := range ids {
+		article, err := s.articleRepo.GetByID(id)
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if article.Status == models.StatusPublished && !article.Expired(now) {
+			articles = append(articles, *article)
+		}
+	}
+	return articles, nil
+}
+
+// validate checks a section and that the category, tag or articles it shows
+// exist, clearing the ones its type does not use
+func (s *HomepageService) validate(section *models.HomepageSection) error {
+	if section.Type != models.HomepageSectionCategory {
+		section.CategoryID = nil
+	}
+	if section.Type != models.HomepageSectionTag {
+		section.TagID = nil
+	}
+	if section.Type != models.HomepageSectionManual {
+		section.ArticleIDs = nil
+	}
+
+	switch section.Type {
+	case models.HomepageSectionCategory:
+		if section.CategoryID == nil {
+			return validationError("category_id is required for category sections")
+		}
+		if _, err := s.categoryRepo.GetByID(*section.CategoryID); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return validationError("category %d does not exist", *section.CategoryID)
+			}
+			return fmt.Errorf("failed to get category: %w", err)
+		}
+	case models.HomepageSectionTag:
+		if section.TagID == nil {
+			return validationError("tag_id is required for tag sections")
+		}
+		if _, err := s.tagRepo.GetByID(*section.TagID); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return validationError("tag %d does not exist", *section.TagID)
+			}
+			return fmt.Errorf("failed to get tag: %w", err)
+		}
+	case models.HomepageSectionManual:
+		if len(section.ArticleIDs) == 0 {
+			return validationError("article_ids is required for manual sections")
+		}
+		if len(section.ArticleIDs) > maxManualArticles {
+			return validationError("manual sections can have at most %d articles", maxManualArticles)
+		}
+		seen := make(map[uint]bool, len(section.ArticleIDs))
+		for _, id := range section.ArticleIDs {
+			if seen[id] {
+				return validationError("article %d is listed twice", id)
+			}
+			seen[id] = true
+			if _, err := s.articleRepo.GetByID(id); err != nil {
+				if errors.Is(err, repositories.ErrNotFound) {
+					return validationError("article %d does not exist", id)
+				}
+				return fmt.Errorf("failed to get article: %w", err)
+			}
+		}
+	}
+
+	if err := section.Validate(); err != nil {
+		var fields models.ValidationErrors
+		if errors.As(err, &fields) {
+			return fieldValidationError(fields)
+		}
+		return validationError("%s", err.Error())
+	}
+	return nil
+}
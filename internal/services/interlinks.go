@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/utils"
+)
+
+const (
+	// maxInterlinkKeywords caps how many keywords of a draft are searched for
+	maxInterlinkKeywords = 8
+	// interlinkCandidates is how many articles each of the keyword search and
+	// the tag listing contribute before ranking
+	interlinkCandidates = 30
+	// interlinkTitleWeight counts a keyword in the title as that many in the content
+	interlinkTitleWeight = 3
+)
+
+// InterlinkRequest is the draft the editor asks interlink suggestions for
+type InterlinkRequest struct {
+	Title   string   `json:"title" validate:"max=255"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags" validate:"max=20,dive,max=50"`
+	// ExcludeArticleID leaves out the article being edited
+	ExcludeArticleID uint `json:"exclude_article_id,omitempty"`
+	Limit            int  `json:"limit,omitempty" validate:"omitempty,min=1,max=20"`
+}
+
+// InterlinkSuggestion is a published article the draft could link to
+type InterlinkSuggestion struct {
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// SetTagService sets the service resolving the draft's tag names and aliases;
+// without it tags are only matched by name
+func (s *SearchService) SetTagService(tagService *TagService) {
+	s.tagService = tagService
+}
+
+// SuggestInterlinks returns published articles sharing keywords or tags with a
+// draft, best match first. The draft's most frequent keywords are run through
+// the full-text search; an article scores up to two points by its relevance
+// rank there and one point per tag it shares with the draft.
+func (s *SearchService) SuggestInterlinks(req *InterlinkRequest) ([]InterlinkSuggestion, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 5
+	}
+
+	keywords := draftKeywords(req.Title, req.Content)
+	tagIDs, err := s.resolveDraftTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(keywords) == 0 && len(tagIDs) == 0 {
+		return nil, validationError("title, content or tags are required")
+	}
+
+	scores := make(map[uint]float64)
+	candidates := make(map[uint]models.Article)
+	if len(keywords) > 0 {
+		filters := &repositories.SearchFilters{Status: string(models.StatusPublished)}
+		matches, _, err := s.articleRepo.AdvancedSearch(strings.Join(keywords, " "), 0, interlinkCandidates, filters)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		for i, article := range matches {
+			candidates[article.ID] = article
+			scores[article.ID] += 2 * float64(len(matches)-i) / float64(len(matches))
+		}
+	}
+	if len(tagIDs) > 0 {
+		tagged, _, err := s.articleRepo.ListByTaxonomies(nil, tagIDs, 0, interlinkCandidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tagged articles: %w", err)
+		}
+		for _, article := range tagged {
+			candidates[article.ID] = article
+		}
+		shared := make(map[uint]bool, len(tagIDs))
+		for _, id := range tagIDs {
+			shared[id] = true
+		}
+		for id, article := range candidates {
+			for _, tag := range article.Tags {
+				if shared[tag.ID] {
+					scores[id]++
+				}
+			}
+		}
+	}
+	delete(candidates, req.ExcludeArticleID)
+
+	ranked := make([]models.Article, 0, len(candidates))
+	for _, article := range candidates {
+		ranked = append(ranked, article)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if scores[a.ID] != scores[b.ID] {
+			return scores[a.ID] > scores[b.ID]
+		}
+		return a.ID > b.ID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+
+	suggestions := make([]InterlinkSuggestion, len(ranked))
+	for i, article := range ranked {
+		suggestions[i] = InterlinkSuggestion{Title: article.Title, Slug: article.Slug}
+	}
+	return suggestions, nil
+}
+
+// resolveDraftTags returns the IDs of the existing tags named in a draft,
+// ignoring names no tag or alias answers to
+func (s *SearchService) resolveDraftTags(names []string) ([]uint, error) {
+	seen := make(map[uint]bool)
+	var ids []uint
+	for _, name := range names {
+		normalized := utils.NormalizeTagName(name)
+		if normalized == "" {
+			continue
+		}
+		var tag *models.Tag
+		var err error
+		if s.tagService != nil {
+			tag, err = s.tagService.ResolveByName(normalized)
+		} else {
+			tag, err = s.tagRepo.GetByNormalizedName(normalized)
+		}
+		if errors.Is(err, repositories.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tag: %w", err)
+		}
+		if !seen[tag.ID] {
+			seen[tag.ID] = true
+			ids = append(ids, tag.ID)
+		}
+	}
+	return ids, nil
+}
+
+// draftMarkup matches links, HTML tags and code spans, which say little about
+// what a draft is about
+var draftMarkup = regexp.MustCompile("https?://\\S+|<[^>]*>|`[^`]*`")
+
+// draftKeywords returns the words of a draft that occur most often, leaving
+// out stop words and words under three letters. Title words weigh more.
+func draftKeywords(title, content string) []string {
+	counts := make(map[string]int)
+	var order []string
+	count := func(text string, weight int) {
+		words := strings.FieldsFunc(strings.ToLower(draftMarkup.ReplaceAllString(text, " ")), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if len([]rune(word)) < 3 || stopWords[word] || strings.IndexFunc(word, unicode.IsLetter) < 0 {
+				continue
+			}
+			if counts[word] == 0 {
+				order = append(order, word)
+			}
+			counts[word] += weight
+		}
+	}
+	count(title, interlinkTitleWeight)
+	count(content, 1)
+
+	// Most frequent first, earliest first among equals
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+	if len(order) > maxInterlinkKeywords {
+		order = order[:maxInterlinkKeywords]
+	}
+	return order
+}
+
+// stopWords are common English words that match almost any article
+var stopWords = func() map[string]bool {
+	words := strings.Fields(`about above after again against all also and any are
+		because been before being below between both but can could did does doing down
+		during each few for from further had has have having her here hers herself him
+		himself his how into its itself just let more most much must myself nor not now
+		off once only other ought our ours ourselves out over own same she should some
+		such than that the their theirs them themselves then there these they this those
+		through too under until use used using very was way were what when where which
+		while who whom why will with would you your yours yourself yourselves`)
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}()
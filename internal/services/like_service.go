@@ -2,15 +2,16 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
-	"gorm.io/gorm"
 )
 
 type LikeService struct {
 	likeRepo    repositories.LikeRepository
 	articleRepo repositories.ArticleRepository
 	userRepo    repositories.UserRepository
+	transactor  repositories.Transactor
 }
 
 // NewLikeService creates a new like service
@@ -26,58 +27,106 @@ func NewLikeService(
 	}
 }
 
-// ToggleLike toggles like status for an article by a user
+// SetTransactor sets the transactor that updates likes and article like
+// counters atomically (for dependency injection)
+func (s *LikeService) SetTransactor(transactor repositories.Transactor) {
+	s.transactor = transactor
+}
+
+// ToggleLike toggles like status for an article by a user and reports whether
+// the article is now liked. The like and the article's like counter change in
+// one transaction.
 func (s *LikeService) ToggleLike(userID, articleID uint) (bool, error) {
 	// Verify user exists
 	_, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, errors.New("user not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return false, notFoundError("user not found")
 		}
 		return false, err
 	}
 
 	// Verify article exists
-	_, err = s.articleRepo.GetByID(articleID)
+	article, err := s.articleRepo.GetByID(articleID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return false, errors.New("article not found")
+		if errors.Is(err, repositories.ErrNotFound) {
+			return false, notFoundError("article not found")
 		}
 		return false, err
 	}
 
-	// Check if like already exists
-	existingLike, err := s.likeRepo.GetByUserAndArticle(userID, articleID)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return false, err
-	}
-
-	if existingLike != nil {
-		// Unlike - remove the like
-		err = s.likeRepo.Delete(userID, articleID)
+	var liked bool
+	err = s.inTransaction(func(likes repositories.LikeRepository, articles repositories.ArticleRepository) error {
+		// Unlike - remove the like if there is one
+		deleted, err := likes.Delete(userID, articleID)
 		if err != nil {
-			return false, err
+			return fmt.Errorf("failed to remove like: %w", err)
+		}
+		if deleted {
+			liked = false
+			return articles.AdjustLikeCount(articleID, -1)
+		}
+
+		// Like - create new like; existing likes can still be taken back once
+		// likes are disabled
+		if !article.LikesAllowed() {
+			return forbiddenError("likes are disabled on this article")
 		}
-		return false, nil // false means unliked
-	} else {
-		// Like - create new like
 		like := &models.Like{
 			UserID:    userID,
 			ArticleID: articleID,
 		}
-		err = s.likeRepo.Create(like)
-		if err != nil {
-			return false, err
+		liked = true
+		if err := likes.Create(like); err != nil {
+			if errors.Is(err, repositories.ErrDuplicate) {
+				// A concurrent request liked the article first and counted it
+				return nil
+			}
+			return fmt.Errorf("failed to create like: %w", err)
 		}
-		return true, nil // true means liked
+		return articles.AdjustLikeCount(articleID, 1)
+	})
+	if err != nil {
+		return false, err
 	}
+	return liked, nil
+}
+
+// inTransaction runs fn with the like and article repositories bound to one
+// transaction, or with the plain ones when no transactor is set
+func (s *LikeService) inTransaction(fn func(likes repositories.LikeRepository, articles repositories.ArticleRepository) error) error {
+	if s.transactor == nil {
+		return fn(s.likeRepo, s.articleRepo)
+	}
+	return s.transactor.Transaction(func(tx *repositories.Tx) error {
+		return fn(tx.Likes, tx.Articles)
+	})
+}
+
+// ListLikedArticles returns a page of the published articles user liked, most
+// recently liked first, locking those user is no longer entitled to
+func (s *LikeService) ListLikedArticles(user *models.User, page, limit int) ([]models.Article, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	articles, total, err := s.likeRepo.ListByUser(user.ID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list liked articles: %w", err)
+	}
+
+	restrictArticles(articles, user)
+	return articles, total, nil
 }
 
 // IsLikedByUser checks if an article is liked by a specific user
 func (s *LikeService) IsLikedByUser(userID, articleID uint) (bool, error) {
 	like, err := s.likeRepo.GetByUserAndArticle(userID, articleID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
+		if errors.Is(err, repositories.ErrNotFound) {
 			return false, nil
 		}
 		return false, err
@@ -0,0 +1,361 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+const (
+	// linkCheckBatch is how many articles are read at a time
+	linkCheckBatch = 100
+	// maxLinksPerArticle caps how many links of one article are checked
+	maxLinksPerArticle = 100
+	// linkCheckUserAgent identifies the checker to the sites it visits
+	linkCheckUserAgent = "go-blog-link-checker/1.0"
+)
+
+// LinkCheckOptions configures how article links are checked
+type LinkCheckOptions struct {
+	RequestInterval time.Duration // pause between two requests, keeping the checker polite
+	Timeout         time.Duration // bounds each request
+	PublicURL       string        // links to the blog itself are not checked
+	ArticleURL      string        // public article URL linked from notifications; {slug} is replaced
+}
+
+// LinkCheckReport summarizes one run of the link check
+type LinkCheckReport struct {
+	Articles    int `json:"articles"`
+	Links       int `json:"links"`
+	Broken      int `json:"broken"`
+	NewlyBroken int `json:"newly_broken"`
+	Notified    int `json:"notified"` // authors told about newly broken links
+}
+
+// linkResult is the outcome of requesting one link
+type linkResult struct {
+	statusCode int
+	err        string
+	broken     bool
+}
+
+// LinkCheckService scans published articles for external links that stopped
+// working, records the outcome per article and tells authors about links that
+// broke since the previous check
+type LinkCheckService struct {
+	linkRepo            repositories.ArticleLinkRepository
+	notificationService *NotificationService
+	client              *http.Client
+	options             LinkCheckOptions
+	internalHost        string
+}
+
+// NewLinkCheckService creates a new link check service
+func NewLinkCheckService(
+	linkRepo repositories.ArticleLinkRepository,
+	notificationService *NotificationService,
+	options LinkCheckOptions,
+) *LinkCheckService {
+	if options.ArticleURL == "" {
+		options.ArticleURL = "/articles/{slug}"
+	}
+	s := &LinkCheckService{
+		linkRepo:            linkRepo,
+		notificationService: notificationService,
+		options:             options,
+		client: &http.Client{
+			Timeout:   options.Timeout,
+			Transport: externalTransport(options.Timeout),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 5 {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+	}
+	if public, err := url.Parse(options.PublicURL); err == nil {
+		s.internalHost = strings.ToLower(public.Hostname())
+	}
+	return s
+}
+
+// SetClient sets the HTTP client links are requested with
+func (s *LinkCheckService) SetClient(client *http.Client) {
+	s.client = client
+}
+
+// externalTransport returns a transport that only connects to public
+// addresses. The check runs on the address a host name resolved to, for the
+// link and for every redirect it leads to, so links cannot reach the server
+// itself or the network it runs in.
+func externalTransport(timeout time.Duration) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || isInternalIP(ip) {
+				return fmt.Errorf("address %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+	return &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: timeout,
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+	}
+}
+
+// isInternalIP reports whether ip is a loopback, private, link-local,
+// unspecified or multicast address
+func isInternalIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// Run checks the external links of every published article. Each distinct link
+// is requested once per run, waiting RequestInterval between requests; the run
+// stops early when ctx is done.
+func (s *LinkCheckService) Run(ctx context.Context) (*LinkCheckReport, error) {
+	report := &LinkCheckReport{}
+	results := make(map[string]linkResult)
+	var lastRequest time.Time
+
+	check := func(link string) (linkResult, error) {
+		if result, ok := results[link]; ok {
+			return result, nil
+		}
+		if wait := s.options.RequestInterval - time.Since(lastRequest); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return linkResult{}, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		result := s.request(ctx, link)
+		lastRequest = time.Now()
+		if ctx.Err() != nil {
+			// Requests cut short by the run's deadline say nothing about the link
+			return linkResult{}, ctx.Err()
+		}
+		results[link] = result
+		return result, nil
+	}
+
+	var afterID uint
+	for {
+		articles, err := s.linkRepo.PublishedAfter(afterID, linkCheckBatch)
+		if err != nil {
+			return report, fmt.Errorf("failed to list articles: %w", err)
+		}
+		for i := range articles {
+			if err := s.checkArticle(&articles[i], check, report); err != nil {
+				return report, err
+			}
+		}
+		if len(articles) < linkCheckBatch {
+			return report, nil
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+}
+
+// checkArticle checks the links of article and records them, notifying the
+// author of links broken since the previous check
+func (s *LinkCheckService) checkArticle(article *models.Article, check func(string) (linkResult, error), report *LinkCheckReport) error {
+	previous, err := s.linkRepo.GetByArticle(article.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get links of article %d: %w", article.ID, err)
+	}
+	brokenSince := make(map[string]*time.Time, len(previous))
+	for _, link := range previous {
+		if link.Broken {
+			brokenSince[link.URL] = link.BrokenSince
+		}
+	}
+
+	found := s.extractLinks(article.Content)
+	if len(found) == 0 && len(previous) == 0 {
+		return nil
+	}
+
+	links := make([]models.ArticleLink, 0, len(found))
+	var newlyBroken []models.ArticleLink
+	for _, link := range found {
+		result, err := check(link)
+		if err != nil {
+			return err
+		}
+		checked := models.ArticleLink{
+			URL:        link,
+			StatusCode: result.statusCode,
+			Error:      result.err,
+			Broken:     result.broken,
+			CheckedAt:  time.Now(),
+		}
+		if result.broken {
+			report.Broken++
+			if since := brokenSince[link]; since != nil {
+				checked.BrokenSince = since
+			} else {
+				checked.BrokenSince = &checked.CheckedAt
+				newlyBroken = append(newlyBroken, checked)
+			}
+		}
+		links = append(links, checked)
+	}
+
+	if err := s.linkRepo.Replace(article.ID, links); err != nil {
+		return fmt.Errorf("failed to record links of article %d: %w", article.ID, err)
+	}
+	report.Articles++
+	report.Links += len(links)
+	report.NewlyBroken += len(newlyBroken)
+
+	if len(newlyBroken) > 0 && s.notificationService != nil {
+		if err := s.notificationService.Notify(s.brokenLinksNotification(article, newlyBroken), true); err != nil {
+			slog.Error("Failed to notify author of broken links", "article_id", article.ID, "error", err)
+		} else {
+			report.Notified++
+		}
+	}
+	return nil
+}
+
+// request asks for link with HEAD, falling back to GET for servers that do not
+// answer HEAD. Links are broken when not found, gone, failing on the server or
+// unreachable; other errors such as 403 and 429 often only turn bots away.
+func (s *LinkCheckService) request(ctx context.Context, link string) linkResult {
+	resp, err := s.do(ctx, http.MethodHead, link)
+	if err == nil && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+		resp, err = s.do(ctx, http.MethodGet, link)
+	}
+	if err != nil {
+		return linkResult{err: truncate(requestError(err), 255), broken: true}
+	}
+
+	broken := resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone ||
+		resp.StatusCode >= http.StatusInternalServerError
+	return linkResult{statusCode: resp.StatusCode, broken: broken}
+}
+
+// do sends one request for link, discarding the response body
+func (s *LinkCheckService) do(ctx context.Context, method, link string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", linkCheckUserAgent)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// requestError describes a failed request without repeating its URL
+func requestError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err.Error()
+	}
+	return err.Error()
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+// articleLinkPattern matches http and https links in markdown and HTML content
+var articleLinkPattern = regexp.MustCompile(`https?://[^\s<>"'\x60()\[\]]+`)
+
+// extractLinks returns the distinct external links of content in order of
+// appearance, leaving out links to the blog itself
+func (s *LinkCheckService) extractLinks(content string) []string {
+	seen := make(map[string]bool)
+	var links []string
+	for _, match := range articleLinkPattern.FindAllString(content, -1) {
+		// Punctuation ending a sentence is not part of the link
+		link := strings.TrimRight(match, ".,;:!?*_")
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		host := strings.ToLower(parsed.Hostname())
+		if host == "localhost" || (s.internalHost != "" && host == s.internalHost) {
+			continue
+		}
+		if ip := net.ParseIP(host); ip != nil && isInternalIP(ip) {
+			continue
+		}
+		parsed.Fragment = ""
+		link = parsed.String()
+		if seen[link] {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if len(links) == maxLinksPerArticle {
+			break
+		}
+	}
+	return links
+}
+
+// brokenLinksNotification builds the notification telling the author of
+// article about its newly broken links
+func (s *LinkCheckService) brokenLinksNotification(article *models.Article, links []models.ArticleLink) *models.Notification {
+	lines := make([]string, len(links))
+	for i, link := range links {
+		reason := link.Error
+		if link.StatusCode != 0 {
+			reason = fmt.Sprintf("%d %s", link.StatusCode, http.StatusText(link.StatusCode))
+		}
+		lines[i] = fmt.Sprintf("- %s (%s)", link.URL, reason)
+	}
+	title := fmt.Sprintf("Broken links in %q", article.Title)
+	if len(links) == 1 {
+		title = fmt.Sprintf("A broken link in %q", article.Title)
+	}
+	return &models.Notification{
+		UserID: article.AuthorID,
+		Type:   models.NotificationBrokenLinks,
+		Title:  truncate(title, 255),
+		Body:   "These links in your article no longer work:\n" + strings.Join(lines, "\n"),
+		Link:   strings.ReplaceAll(s.options.ArticleURL, "{slug}", url.PathEscape(article.Slug)),
+	}
+}
+
+// ListBroken returns a page of the broken links of published articles, longest
+// broken first
+func (s *LinkCheckService) ListBroken(page, limit int) ([]repositories.BrokenLink, int64, error) {
+	links, total, err := s.linkRepo.ListBroken((page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list broken links: %w", err)
+	}
+	return links, total, nil
+}
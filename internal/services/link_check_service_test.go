@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripFunc lets a function stand in for an HTTP transport
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestLinkCheckRefusesInternalAddresses(t *testing.T) {
+	var hits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer internal.Close()
+
+	s := NewLinkCheckService(nil, nil, LinkCheckOptions{Timeout: time.Second})
+	// A public site redirecting to the internal server is answered in
+	// process; every other request goes through the guarded transport
+	guarded := s.client.Transport
+	s.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "public.example.com" {
+			return &http.Response{
+				StatusCode: http.StatusFound,
+				Header:     http.Header{"Location": []string{internal.URL + "/admin"}},
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}
+		return guarded.RoundTrip(req)
+	})
+
+	localhost := strings.Replace(internal.URL, "127.0.0.1", "localhost", 1)
+	for _, link := range []string{internal.URL + "/", localhost + "/", "https://public.example.com/moved"} {
+		result := s.request(context.Background(), link)
+		assert.True(t, result.broken, link)
+		assert.Contains(t, result.err, "not allowed", link)
+	}
+	assert.Zero(t, hits.Load(), "internal server was requested")
+}
+
+func TestExtractLinks(t *testing.T) {
+	s := NewLinkCheckService(nil, nil, LinkCheckOptions{PublicURL: "https://blog.example.com"})
+
+	content := "See [the docs](https://go.dev/doc/#intro), https://example.com/page. " +
+		"<a href=\"http://example.org/a?b=c\">this</a> and again https://go.dev/doc/ or " +
+		"[home](https://blog.example.com/about), http://localhost:8080/, http://127.0.0.1/, " +
+		"http://169.254.169.254/latest/meta-data/, http://10.0.0.1/, http://192.168.1.1/, " +
+		"http://[::1]:8080/ and http://0.0.0.0/"
+
+	assert.Equal(t, []string{"https://go.dev/doc/", "https://example.com/page", "http://example.org/a?b=c"},
+		s.extractLinks(content))
+
+	// Only the first links of an article are checked
+	var many strings.Builder
+	for i := 0; i < maxLinksPerArticle+20; i++ {
+		fmt.Fprintf(&many, "https://example.com/%d ", i)
+	}
+	links := s.extractLinks(many.String())
+	assert.Len(t, links, maxLinksPerArticle)
+	assert.Equal(t, "https://example.com/0", links[0])
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"go-blog/internal/queue"
+)
+
+// Mailer delivers email to users
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// LogMailer writes outgoing email to the application log. It is the default
+// until a real transport is configured.
+type LogMailer struct{}
+
+// Send logs the message instead of delivering it
+func (LogMailer) Send(to, subject, body string) error {
+	slog.Info("Email", "to", to, "subject", subject, "body", body)
+	return nil
+}
+
+// EmailJob is the job type of queued email
+const EmailJob = "email.send"
+
+// queuedEmail is the payload of an EmailJob
+type queuedEmail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// QueuedMailer queues email for delivery by a background worker, so requests
+// never wait on the mail server and failed deliveries are retried
+type QueuedMailer struct {
+	queue  *queue.Queue
+	mailer Mailer
+}
+
+// NewQueuedMailer creates a mailer that queues email in q and delivers it with
+// mailer from the queue's workers
+func NewQueuedMailer(q *queue.Queue, mailer Mailer) *QueuedMailer {
+	m := &QueuedMailer{queue: q, mailer: mailer}
+	q.Register(EmailJob, m.deliver)
+	return m
+}
+
+// Send queues the message
+func (m *QueuedMailer) Send(to, subject, body string) error {
+	return m.queue.Enqueue(EmailJob, queuedEmail{To: to, Subject: subject, Body: body})
+}
+
+// deliver sends a queued message
+func (m *QueuedMailer) deliver(ctx context.Context, payload []byte) error {
+	var email queuedEmail
+	if err := json.Unmarshal(payload, &email); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid email payload: %w", err))
+	}
+	return m.mailer.Send(email.To, email.Subject, email.Body)
+}
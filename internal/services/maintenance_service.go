@@ -0,0 +1,89 @@
+package services
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMaintenanceMessage is shown while in maintenance when no message is set
+const DefaultMaintenanceMessage = "The site is undergoing maintenance. Please try again later."
+
+// MaintenanceState describes maintenance mode. While enabled, write requests
+// other than admin routes are refused; BlockReads refuses reads too.
+type MaintenanceState struct {
+	Enabled    bool       `json:"enabled"`
+	BlockReads bool       `json:"block_reads"`
+	Message    string     `json:"message"`
+	RetryAfter int        `json:"retry_after,omitempty"` // seconds clients should wait before retrying, 0 omits it
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`  // last change through the admin API
+}
+
+// UpdateMaintenanceRequest changes maintenance mode; omitted fields are left unchanged
+type UpdateMaintenanceRequest struct {
+	Enabled    *bool   `json:"enabled,omitempty"`
+	BlockReads *bool   `json:"block_reads,omitempty"`
+	Message    *string `json:"message,omitempty" validate:"omitempty,max=500"`
+	RetryAfter *int    `json:"retry_after,omitempty" validate:"omitempty,min=0,max=86400"`
+}
+
+// MaintenanceService holds the maintenance mode of this instance. It starts from
+// the configuration; changes made by admins last until the next restart.
+type MaintenanceService struct {
+	mu    sync.RWMutex
+	state MaintenanceState
+}
+
+// NewMaintenanceService creates a maintenance service in the given initial state
+func NewMaintenanceService(initial MaintenanceState) *MaintenanceService {
+	if strings.TrimSpace(initial.Message) == "" {
+		initial.Message = DefaultMaintenanceMessage
+	}
+	return &MaintenanceService{state: initial}
+}
+
+// State returns the current maintenance mode
+func (s *MaintenanceService) State() MaintenanceState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Update changes maintenance mode on behalf of the admin adminID
+func (s *MaintenanceService) Update(adminID uint, req *UpdateMaintenanceRequest) (MaintenanceState, error) {
+	if req == nil {
+		return MaintenanceState{}, validationError("maintenance request is required")
+	}
+	if req.RetryAfter != nil && (*req.RetryAfter < 0 || *req.RetryAfter > 86400) {
+		return MaintenanceState{}, validationError("retry_after must be between 0 and 86400 seconds")
+	}
+	if req.Message != nil && len([]rune(*req.Message)) > 500 {
+		return MaintenanceState{}, validationError("message must be less than 500 characters")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Enabled != nil {
+		s.state.Enabled = *req.Enabled
+	}
+	if req.BlockReads != nil {
+		s.state.BlockReads = *req.BlockReads
+	}
+	if req.Message != nil {
+		s.state.Message = strings.TrimSpace(*req.Message)
+		if s.state.Message == "" {
+			s.state.Message = DefaultMaintenanceMessage
+		}
+	}
+	if req.RetryAfter != nil {
+		s.state.RetryAfter = *req.RetryAfter
+	}
+	now := time.Now()
+	s.state.UpdatedAt = &now
+
+	slog.Info("Maintenance mode updated",
+		"user_id", adminID, "enabled", s.state.Enabled, "block_reads", s.state.BlockReads)
+	return s.state, nil
+}
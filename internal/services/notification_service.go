@@ -0,0 +1,123 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// NotificationService records in-app notifications and sends their email copies
+type NotificationService struct {
+	notificationRepo repositories.NotificationRepository
+	userRepo         repositories.UserRepository
+	settingsService  *UserSettingsService
+	mailer           Mailer
+}
+
+// NotificationList is a page of notifications with the user's unread count
+type NotificationList struct {
+	Notifications []models.Notification `json:"notifications"`
+	Unread        int64                 `json:"unread"`
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(notificationRepo repositories.NotificationRepository, userRepo repositories.UserRepository) *NotificationService {
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		mailer:           LogMailer{},
+	}
+}
+
+// SetMailer sets the transport used for email notifications
+func (s *NotificationService) SetMailer(mailer Mailer) {
+	s.mailer = mailer
+}
+
+// SetSettingsService sets the service consulted for the recipient's email preferences
+func (s *NotificationService) SetSettingsService(settingsService *UserSettingsService) {
+	s.settingsService = settingsService
+}
+
+// Notify stores a notification for the user and, when email is set and the user's
+// settings allow it, mails it to them. A failed email is reported but the in-app
+// notification is kept.
+func (s *NotificationService) Notify(notification *models.Notification, email bool) error {
+	if err := s.notificationRepo.Create(notification); err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	if !email {
+		return nil
+	}
+
+	if s.settingsService != nil {
+		settings, err := s.settingsService.Get(notification.UserID)
+		if err != nil {
+			return err
+		}
+		if !settings.WantsEmail(notification.Type) {
+			return nil
+		}
+	}
+
+	user, err := s.userRepo.GetByID(notification.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get notification recipient: %w", err)
+	}
+	body := notification.Body
+	if notification.Link != "" {
+		body += "\n\n" + notification.Link
+	}
+	if notification.UnsubscribeURL != "" {
+		body += "\n\nUnsubscribe: " + notification.UnsubscribeURL
+	}
+	if err := s.mailer.Send(user.Email, notification.Title, body); err != nil {
+		return fmt.Errorf("failed to send notification email: %w", err)
+	}
+	return nil
+}
+
+// List returns a page of the user's notifications, newest first
+func (s *NotificationService) List(userID uint, unreadOnly bool, page, limit int) (*NotificationList, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	notifications, total, err := s.notificationRepo.ListByUser(userID, unreadOnly, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	unread, err := s.notificationRepo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	return &NotificationList{Notifications: notifications, Unread: unread}, total, nil
+}
+
+// MarkRead marks one of the user's notifications as read
+func (s *NotificationService) MarkRead(userID, id uint) error {
+	if err := s.notificationRepo.MarkRead(userID, id, time.Now()); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("notification not found")
+		}
+		return fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user as read
+func (s *NotificationService) MarkAllRead(userID uint) error {
+	if err := s.notificationRepo.MarkAllRead(userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import "encoding/json"
+
+// Nullable is an optional request field that can also be set to null, telling
+// apart a field left out of the JSON (Set is false), a field set to null (Set
+// is true and Value nil) and a field set to a value.
+type Nullable[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// NullableOf returns a Nullable set to value
+func NullableOf[T any](value T) Nullable[T] {
+	return Nullable[T]{Set: true, Value: &value}
+}
+
+// Null returns a Nullable set to null
+func Null[T any]() Nullable[T] {
+	return Nullable[T]{Set: true}
+}
+
+// UnmarshalJSON records that the field was present; it is only called for
+// fields found in the JSON
+func (n *Nullable[T]) UnmarshalJSON(data []byte) error {
+	n.Set = true
+	if string(data) == "null" {
+		n.Value = nil
+		return nil
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	n.Value = &value
+	return nil
+}
+
+// MarshalJSON writes the value, or null when there is none
+func (n Nullable[T]) MarshalJSON() ([]byte, error) {
+	if n.Value == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(*n.Value)
+}
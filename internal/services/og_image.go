@@ -0,0 +1,288 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg" // register decoders accepted for templates
+	"image/png"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"go-blog/internal/models"
+	"go-blog/internal/queue"
+	"go-blog/internal/repositories"
+	"go-blog/internal/storage"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// OGImageWidth and OGImageHeight are the size social networks expect of share images
+	OGImageWidth  = 1200
+	OGImageHeight = 630
+
+	// OGImageJob is the job type of queued share image renders
+	OGImageJob = "og_images.render"
+
+	// ogImageMargin is the padding around the text of share images
+	ogImageMargin = 80
+	// ogImageTitleLines caps the lines of a title; longer titles end in an ellipsis
+	ogImageTitleLines = 4
+)
+
+// OGImageOptions configures the template share images are rendered with
+type OGImageOptions struct {
+	SiteName   string
+	Template   string // optional PNG or JPEG drawn as background, scaled to the image size
+	Background string // hex colors, e.g. "#1f2937"
+	Foreground string
+	Accent     string
+}
+
+// OGImageService renders the social share image of published articles: the
+// title, the author and the site name on the configured template. Images are
+// stored under a key derived from what they show, so they are only rendered
+// again when that changes, and their URL is recorded as the article's
+// og_image_url.
+type OGImageService struct {
+	articleRepo repositories.ArticleRepository
+	storage     storage.Storage
+	queue       *queue.Queue
+	options     OGImageOptions
+
+	template    image.Image // nil without a template
+	fingerprint string      // hash of the template and colors, part of every key
+	background  color.Color
+	foreground  color.Color
+	accent      color.Color
+	titleFont   *opentype.Font
+	textFont    *opentype.Font
+}
+
+// ogImageRender is the payload of an OGImageJob
+type ogImageRender struct {
+	ArticleID uint `json:"article_id"`
+}
+
+// NewOGImageService creates a share image renderer storing images in store
+func NewOGImageService(articleRepo repositories.ArticleRepository, store storage.Storage, options OGImageOptions) (*OGImageService, error) {
+	s := &OGImageService{
+		articleRepo: articleRepo,
+		storage:     store,
+		options:     options,
+	}
+
+	var err error
+	if s.background, err = parseHexColor(options.Background); err != nil {
+		return nil, err
+	}
+	if s.foreground, err = parseHexColor(options.Foreground); err != nil {
+		return nil, err
+	}
+	if s.accent, err = parseHexColor(options.Accent); err != nil {
+		return nil, err
+	}
+	if s.titleFont, err = opentype.Parse(gobold.TTF); err != nil {
+		return nil, fmt.Errorf("failed to load title font: %w", err)
+	}
+	if s.textFont, err = opentype.Parse(goregular.TTF); err != nil {
+		return nil, fmt.Errorf("failed to load text font: %w", err)
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(options.Background + options.Foreground + options.Accent))
+	if options.Template != "" {
+		data, err := os.ReadFile(options.Template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OG image template: %w", err)
+		}
+		if s.template, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("OG image template must be a PNG or JPEG image: %w", err)
+		}
+		hash.Write(data)
+	}
+	s.fingerprint = hex.EncodeToString(hash.Sum(nil))
+	return s, nil
+}
+
+// SetQueue renders images from the job queue, retrying failed renders
+func (s *OGImageService) SetQueue(q *queue.Queue) {
+	s.queue = q
+	q.Register(OGImageJob, s.handleJob)
+}
+
+// ArticleChanged renders the share image of article again if it is published,
+// in the background when a queue is set. Failures are logged, never reported
+// to the author.
+func (s *OGImageService) ArticleChanged(article *models.Article) {
+	if article.Status != models.StatusPublished {
+		return
+	}
+	if s.queue != nil {
+		err := s.queue.Enqueue(OGImageJob, ogImageRender{ArticleID: article.ID})
+		if err == nil {
+			return
+		}
+		slog.Warn("Rendering OG image directly", "article_id", article.ID, "error", err)
+	}
+	if err := s.Render(article.ID); err != nil {
+		slog.Error("Failed to render OG image", "article_id", article.ID, "error", err)
+	}
+}
+
+// Render stores the share image of a published article unless the stored one
+// is still current, replacing the previous image. Articles that are gone or no
+// longer published are skipped.
+func (s *OGImageService) Render(articleID uint) error {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get article: %w", err)
+	}
+	if article.Status != models.StatusPublished {
+		return nil
+	}
+
+	author := article.Author.Username
+	sum := sha256.Sum256([]byte(strings.Join([]string{article.Title, author, s.options.SiteName, s.fingerprint}, "\x00")))
+	key := fmt.Sprintf("og/%d/%s.png", article.ID, hex.EncodeToString(sum[:8]))
+	if key == article.OGImageKey {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, s.draw(article.Title, author)); err != nil {
+		return fmt.Errorf("failed to encode OG image: %w", err)
+	}
+	if err := s.storage.Put(key, &buf, "image/png"); err != nil {
+		return fmt.Errorf("failed to store OG image: %w", err)
+	}
+	if err := s.articleRepo.SetOGImage(article.ID, key, s.storage.URL(key)); err != nil {
+		if err := s.storage.Delete(key); err != nil {
+			slog.Error("Failed to delete OG image", "key", key, "error", err)
+		}
+		return fmt.Errorf("failed to record OG image: %w", err)
+	}
+
+	if article.OGImageKey != "" {
+		if err := s.storage.Delete(article.OGImageKey); err != nil {
+			slog.Error("Failed to delete OG image", "key", article.OGImageKey, "error", err)
+		}
+	}
+	return nil
+}
+
+// handleJob renders a queued share image
+func (s *OGImageService) handleJob(ctx context.Context, payload []byte) error {
+	var render ogImageRender
+	if err := json.Unmarshal(payload, &render); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid OG image payload: %w", err))
+	}
+	return s.Render(render.ArticleID)
+}
+
+// draw lays out the title, the author and the site name on the template
+func (s *OGImageService) draw(title, author string) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, OGImageWidth, OGImageHeight))
+	if s.template != nil {
+		draw.CatmullRom.Scale(img, img.Bounds(), s.template, s.template.Bounds(), draw.Src, nil)
+	} else {
+		draw.Draw(img, img.Bounds(), image.NewUniform(s.background), image.Point{}, draw.Src)
+	}
+	draw.Draw(img, image.Rect(0, 0, OGImageWidth, 12), image.NewUniform(s.accent), image.Point{}, draw.Src)
+
+	titleFace := s.face(s.titleFont, 64)
+	defer titleFace.Close()
+	lineHeight := 78
+	y := ogImageMargin + 64
+	for _, line := range wrapText(titleFace, title, OGImageWidth-2*ogImageMargin, ogImageTitleLines) {
+		drawText(img, titleFace, s.foreground, ogImageMargin, y, line)
+		y += lineHeight
+	}
+
+	textFace := s.face(s.textFont, 32)
+	defer textFace.Close()
+	if author != "" {
+		drawText(img, textFace, s.foreground, ogImageMargin, OGImageHeight-ogImageMargin-48, "By "+author)
+	}
+	drawText(img, textFace, s.accent, ogImageMargin, OGImageHeight-ogImageMargin, s.options.SiteName)
+	return img
+}
+
+// face returns f at size points; the fonts are parsed at startup, so only
+// invalid sizes could fail
+func (s *OGImageService) face(f *opentype.Font, size float64) font.Face {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		panic(err)
+	}
+	return face
+}
+
+// drawText draws text with its baseline starting at x, y
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face, Dot: fixed.P(x, y)}
+	drawer.DrawString(text)
+}
+
+// wrapText breaks text into lines at most width pixels wide, ending the last of
+// maxLines in an ellipsis when the text does not fit
+func wrapText(face font.Face, text string, width, maxLines int) []string {
+	limit := fixed.I(width)
+	var lines []string
+	line := ""
+	words := strings.Fields(text)
+	for i, word := range words {
+		candidate := strings.TrimSpace(line + " " + word)
+		if line == "" || font.MeasureString(face, candidate) <= limit {
+			line = candidate
+			continue
+		}
+		if len(lines) == maxLines-1 {
+			return append(lines, ellipsize(face, line+" "+strings.Join(words[i:], " "), limit))
+		}
+		lines = append(lines, ellipsize(face, line, limit))
+		line = word
+	}
+	if line != "" {
+		lines = append(lines, ellipsize(face, line, limit))
+	}
+	return lines
+}
+
+// ellipsize cuts text to fit limit, ending it in an ellipsis when cut
+func ellipsize(face font.Face, text string, limit fixed.Int26_6) string {
+	if font.MeasureString(face, text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && font.MeasureString(face, string(runes)+"…") > limit {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
+
+// parseHexColor parses a #rrggbb color
+func parseHexColor(value string) (color.Color, error) {
+	rgb, err := strconv.ParseUint(strings.TrimPrefix(value, "#"), 16, 32)
+	if err != nil || len(value) != 7 || value[0] != '#' {
+		return nil, fmt.Errorf("invalid hex color %q", value)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxSuggesterContent caps how much of an article is sent for suggestions
+	maxSuggesterContent = 12000
+	// maxSuggesterResponse caps the responses read from the completion API
+	maxSuggesterResponse = 1 << 20
+)
+
+// suggesterPrompt tells the model what to return
+const suggesterPrompt = `You help the author of a blog article write its metadata.
+Answer with a JSON object with exactly these fields:
+"excerpt": a summary of the article in one or two sentences, at most 300 characters;
+"tags": up to 5 short topic tags, preferring the known tags of the blog when they fit;
+"meta_description": a description for search results, at most 155 characters.
+Write in the language of the article and do not invent facts it does not state.`
+
+// OpenAIOptions configures a client of an OpenAI compatible chat completion API
+type OpenAIOptions struct {
+	BaseURL string // API base, e.g. https://api.openai.com/v1; /chat/completions is appended
+	APIKey  string // sent as a bearer token when not empty
+	Model   string
+	Timeout time.Duration
+}
+
+// OpenAISuggester suggests article metadata with a chat completion API
+// compatible with OpenAI's, such as the ones of OpenAI, Azure, Ollama or vLLM
+type OpenAISuggester struct {
+	options OpenAIOptions
+	client  *http.Client
+}
+
+// chatMessage is one message of a chat completion
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// NewOpenAISuggester creates a content suggester calling the chat completion
+// API at options.BaseURL
+func NewOpenAISuggester(options OpenAIOptions) *OpenAISuggester {
+	options.BaseURL = strings.TrimSuffix(options.BaseURL, "/")
+	return &OpenAISuggester{
+		options: options,
+		client:  &http.Client{Timeout: options.Timeout},
+	}
+}
+
+// SetClient sets the HTTP client the API is called with
+func (s *OpenAISuggester) SetClient(client *http.Client) {
+	s.client = client
+}
+
+// Suggest asks the model for the metadata of draft in JSON mode
+func (s *OpenAISuggester) Suggest(ctx context.Context, draft ContentDraft) (*ContentSuggestions, error) {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Title: %s\n", draft.Title)
+	if len(draft.Tags) > 0 {
+		fmt.Fprintf(&prompt, "Current tags: %s\n", strings.Join(draft.Tags, ", "))
+	}
+	if len(draft.KnownTags) > 0 {
+		fmt.Fprintf(&prompt, "Known tags of the blog: %s\n", strings.Join(draft.KnownTags, ", "))
+	}
+	fmt.Fprintf(&prompt, "\n%s", truncate(draft.Content, maxSuggesterContent))
+
+	body, err := json.Marshal(map[string]interface{}{
+		"model": s.options.Model,
+		"messages": []chatMessage{
+			{Role: "system", Content: suggesterPrompt},
+			{Role: "user", Content: prompt.String()},
+		},
+		"response_format": map[string]string{"type": "json_object"},
+		"temperature":     0.3,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.options.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.options.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.options.APIKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, upstreamError("the suggestion service could not be reached")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, upstreamError("the suggestion service answered with status %d", resp.StatusCode)
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxSuggesterResponse)).Decode(&completion); err != nil || len(completion.Choices) == 0 {
+		return nil, upstreamError("the suggestion service answered with an invalid completion")
+	}
+
+	var suggestions ContentSuggestions
+	content := strings.TrimSpace(completion.Choices[0].Message.Content)
+	// Some models wrap JSON in a code fence despite JSON mode
+	content = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(content, "```json"), "```"), "```")
+	if err := json.Unmarshal([]byte(content), &suggestions); err != nil {
+		return nil, upstreamError("the suggestion service answered with invalid suggestions")
+	}
+	return &suggestions, nil
+}
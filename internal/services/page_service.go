@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/sanitize"
+	"go-blog/internal/utils"
+)
+
+// PageService manages static pages such as about and contact
+type PageService struct {
+	pageRepo repositories.PageRepository
+}
+
+// CreatePageRequest represents page creation data. The slug is derived from the
+// title when omitted.
+type CreatePageRequest struct {
+	Title     string `json:"title" validate:"required,min=1,max=255"`
+	Slug      string `json:"slug,omitempty" validate:"omitempty,slug,max=100"`
+	Content   string `json:"content" validate:"required"`
+	Status    string `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+	ShowInNav bool   `json:"show_in_nav"`
+	NavOrder  int    `json:"nav_order"`
+}
+
+// UpdatePageRequest represents page update data; omitted fields are left unchanged
+type UpdatePageRequest struct {
+	Title     *string `json:"title,omitempty" validate:"omitempty,min=1,max=255"`
+	Slug      *string `json:"slug,omitempty" validate:"omitempty,slug,max=100"`
+	Content   *string `json:"content,omitempty"`
+	Status    *string `json:"status,omitempty" validate:"omitempty,oneof=draft published"`
+	ShowInNav *bool   `json:"show_in_nav,omitempty"`
+	NavOrder  *int    `json:"nav_order,omitempty"`
+}
+
+// NewPageService creates a new page service
+func NewPageService(pageRepo repositories.PageRepository) *PageService {
+	return &PageService{
+		pageRepo: pageRepo,
+	}
+}
+
+// Create creates a page written by authorID; pages start as drafts unless a status is given
+func (s *PageService) Create(authorID uint, req *CreatePageRequest) (*models.Page, error) {
+	if req == nil {
+		return nil, validationError("create request cannot be nil")
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, validationError("title is required")
+	}
+	content := sanitize.Article(req.Content)
+	if strings.TrimSpace(content) == "" {
+		return nil, validationError("content is required")
+	}
+
+	slug := req.Slug
+	if slug == "" {
+		slug = utils.GenerateSlug(title)
+	}
+	if err := s.checkSlugAvailable(slug, 0); err != nil {
+		return nil, err
+	}
+
+	status := models.PageStatusDraft
+	if req.Status != "" {
+		status = models.PageStatus(req.Status)
+	}
+
+	page := &models.Page{
+		Title:     title,
+		Slug:      slug,
+		Content:   content,
+		Status:    status,
+		ShowInNav: req.ShowInNav,
+		NavOrder:  req.NavOrder,
+		AuthorID:  authorID,
+	}
+	if err := page.Validate(); err != nil {
+		return nil, pageValidationError(err)
+	}
+	if err := s.pageRepo.Create(page); err != nil {
+		return nil, fmt.Errorf("failed to create page: %w", err)
+	}
+
+	return page, nil
+}
+
+// GetBySlug retrieves a page by slug. Drafts are only returned when includeDrafts
+// is set and are reported as not found otherwise.
+func (s *PageService) GetBySlug(slug string, includeDrafts bool) (*models.Page, error) {
+	if slug == "" {
+		return nil, validationError("page slug cannot be empty")
+	}
+
+	page, err := s.pageRepo.GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("page not found")
+		}
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+	if !page.IsPublished() && !includeDrafts {
+		return nil, notFoundError("page not found")
+	}
+
+	return page, nil
+}
+
+// List lists every page, drafts included, in navigation order
+func (s *PageService) List() ([]models.Page, error) {
+	pages, err := s.pageRepo.List(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+	return pages, nil
+}
+
+// ListPublished lists the published pages as links, in navigation order. With
+// navOnly it is limited to the pages shown in navigation.
+func (s *PageService) ListPublished(navOnly bool) ([]models.PageLink, error) {
+	var pages []models.Page
+	var err error
+	if navOnly {
+		pages, err = s.pageRepo.ListNavigation()
+	} else {
+		pages, err = s.pageRepo.List(true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pages: %w", err)
+	}
+
+	links := make([]models.PageLink, 0, len(pages))
+	for i := range pages {
+		links = append(links, pages[i].Link())
+	}
+	return links, nil
+}
+
+// Update updates a page
+func (s *PageService) Update(id uint, req *UpdatePageRequest) (*models.Page, error) {
+	if req == nil {
+		return nil, validationError("update request cannot be nil")
+	}
+
+	page, err := s.pageRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("page not found")
+		}
+		return nil, fmt.Errorf("failed to get page: %w", err)
+	}
+
+	if req.Title != nil {
+		page.Title = strings.TrimSpace(*req.Title)
+		if page.Title == "" {
+			return nil, validationError("title cannot be empty")
+		}
+	}
+	if req.Slug != nil && *req.Slug != page.Slug {
+		if err := s.checkSlugAvailable(*req.Slug, page.ID); err != nil {
+			return nil, err
+		}
+		page.Slug = *req.Slug
+	}
+	if req.Content != nil {
+		page.Content = sanitize.Article(*req.Content)
+		if strings.TrimSpace(page.Content) == "" {
+			return nil, validationError("content cannot be empty")
+		}
+	}
+	if req.Status != nil {
+		page.Status = models.PageStatus(*req.Status)
+	}
+	if req.ShowInNav != nil {
+		page.ShowInNav = *req.ShowInNav
+	}
+	if req.NavOrder != nil {
+		page.NavOrder = *req.NavOrder
+	}
+
+	if err := page.Validate(); err != nil {
+		return nil, pageValidationError(err)
+	}
+	if err := s.pageRepo.Update(page); err != nil {
+		return nil, fmt.Errorf("failed to update page: %w", err)
+	}
+
+	return page, nil
+}
+
+// Delete removes a page
+func (s *PageService) Delete(id uint) error {
+	if _, err := s.pageRepo.GetByID(id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("page not found")
+		}
+		return fmt.Errorf("failed to get page: %w", err)
+	}
+
+	if err := s.pageRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete page: %w", err)
+	}
+	return nil
+}
+
+// checkSlugAvailable returns a conflict error when slug belongs to a page other than exceptID
+func (s *PageService) checkSlugAvailable(slug string, exceptID uint) error {
+	if slug == "" {
+		return validationError("failed to generate slug from page title")
+	}
+
+	existing, err := s.pageRepo.GetBySlug(slug)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return fmt.Errorf("failed to check existing page: %w", err)
+	}
+	if existing != nil && existing.ID != exceptID {
+		return conflictError("page with this slug already exists")
+	}
+	return nil
+}
+
+// pageValidationError reports model validation failures as field errors
+func pageValidationError(err error) error {
+	var fields models.ValidationErrors
+	if errors.As(err, &fields) {
+		return fieldValidationError(fields)
+	}
+	return validationError("%s", err.Error())
+}
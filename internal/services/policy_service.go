@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+	"go-blog/internal/sanitize"
+)
+
+// PolicyRequest represents the publication of a new version of a policy
+type PolicyRequest struct {
+	Kind    models.PolicyKind `json:"kind" validate:"required,oneof=terms privacy"`
+	Version string            `json:"version" validate:"required,min=1,max=50"`
+	Title   string            `json:"title" validate:"required,min=1,max=255"`
+	Content string            `json:"content" validate:"required"`
+}
+
+// AcceptPoliciesRequest names the policy versions a user accepts
+type AcceptPoliciesRequest struct {
+	PolicyIDs []uint `json:"policy_ids" validate:"required,min=1"`
+}
+
+// PolicyService publishes versioned policy documents such as the terms of
+// service and records which versions users accepted
+type PolicyService struct {
+	policyRepo repositories.PolicyRepository
+}
+
+// NewPolicyService creates a new policy service
+func NewPolicyService(policyRepo repositories.PolicyRepository) *PolicyService {
+	return &PolicyService{
+		policyRepo: policyRepo,
+	}
+}
+
+// Publish publishes a new version of a policy, which becomes the current one
+// of its kind. Every user must accept it again.
+func (s *PolicyService) Publish(adminID uint, req *PolicyRequest) (*models.Policy, error) {
+	if req == nil {
+		return nil, validationError("policy request cannot be nil")
+	}
+	content := sanitize.Article(req.Content)
+	if strings.TrimSpace(content) == "" {
+		return nil, validationError("content is required")
+	}
+
+	policy := &models.Policy{
+		Kind:      req.Kind,
+		Version:   strings.TrimSpace(req.Version),
+		Title:     strings.TrimSpace(req.Title),
+		Content:   content,
+		CreatedBy: adminID,
+	}
+	if err := policy.Validate(); err != nil {
+		var fields models.ValidationErrors
+		if errors.As(err, &fields) {
+			return nil, fieldValidationError(fields)
+		}
+		return nil, validationError("%s", err.Error())
+	}
+	if err := s.policyRepo.Create(policy); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return nil, conflictError("version %s of the %s policy already exists", policy.Version, policy.Kind)
+		}
+		return nil, fmt.Errorf("failed to publish policy: %w", err)
+	}
+	return policy, nil
+}
+
+// List returns every version of every policy, newest first
+func (s *PolicyService) List() ([]models.Policy, error) {
+	policies, err := s.policyRepo.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Current returns the current version of each kind of policy
+func (s *PolicyService) Current() ([]models.Policy, error) {
+	policies, err := s.policyRepo.Current()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policies: %w", err)
+	}
+	return policies, nil
+}
+
+// Pending returns the current policies the user has not accepted yet
+func (s *PolicyService) Pending(userID uint) ([]models.Policy, error) {
+	current, err := s.Current()
+	if err != nil {
+		return nil, err
+	}
+	accepted, err := s.policyRepo.AcceptedPolicyIDs(userID, policyIDs(current))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load policy acceptances: %w", err)
+	}
+	done := make(map[uint]bool, len(accepted))
+	for _, id := range accepted {
+		done[id] = true
+	}
+
+	pending := make([]models.Policy, 0, len(current))
+	for _, policy := range current {
+		if !done[policy.ID] {
+			pending = append(pending, policy)
+		}
+	}
+	return pending, nil
+}
+
+// Accept records the user accepting the named policy versions from ip. Only
+// current versions can be accepted; the current policies still pending are
+// returned.
+func (s *PolicyService) Accept(userID uint, req *AcceptPoliciesRequest, ip string) ([]models.Policy, error) {
+	if req == nil || len(req.PolicyIDs) == 0 {
+		return nil, validationError("policy_ids is required")
+	}
+	current, err := s.Current()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Policy, len(current))
+	for _, policy := range current {
+		byID[policy.ID] = policy
+	}
+
+	accepted := make([]models.Policy, 0, len(req.PolicyIDs))
+	for _, id := range req.PolicyIDs {
+		policy, ok := byID[id]
+		if !ok {
+			return nil, validationError("policy %d is not a current policy", id)
+		}
+		accepted = append(accepted, policy)
+	}
+	if err := s.record(userID, accepted, ip); err != nil {
+		return nil, err
+	}
+	return s.Pending(userID)
+}
+
+// PendingUsers returns a page of the users who must accept the current
+// policies again, oldest accounts first
+func (s *PolicyService) PendingUsers(page, limit int) ([]models.User, int64, error) {
+	current, err := s.Current()
+	if err != nil {
+		return nil, 0, err
+	}
+	users, total, err := s.policyRepo.UsersPendingAcceptance(policyIDs(current), (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users pending policy acceptance: %w", err)
+	}
+	return users, total, nil
+}
+
+// record records the user accepting policies from ip
+func (s *PolicyService) record(userID uint, policies []models.Policy, ip string) error {
+	if len(policies) == 0 {
+		return nil
+	}
+	now := time.Now()
+	acceptances := make([]models.PolicyAcceptance, len(policies))
+	for i, policy := range policies {
+		acceptances[i] = models.PolicyAcceptance{UserID: userID, PolicyID: policy.ID, IP: ip, AcceptedAt: now}
+	}
+	if err := s.policyRepo.Accept(acceptances); err != nil {
+		return fmt.Errorf("failed to record policy acceptance: %w", err)
+	}
+	return nil
+}
+
+// policyIDs returns the IDs of policies
+func policyIDs(policies []models.Policy) []uint {
+	ids := make([]uint, len(policies))
+	for i, policy := range policies {
+		ids[i] = policy.ID
+	}
+	return ids
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// Quota windows: articles are counted per day and comments per hour
+const (
+	articleQuotaWindow = 24 * time.Hour
+	commentQuotaWindow = time.Hour
+)
+
+// quotaWindows maps each kind of write to its quota window
+var quotaWindows = map[string]time.Duration{
+	"article": articleQuotaWindow,
+	"comment": commentQuotaWindow,
+}
+
+// maxTrackedIPs bounds the client IPs whose writes are remembered; beyond it,
+// IPs without writes in their windows are forgotten, then those that wrote
+// least recently
+const maxTrackedIPs = 10000
+
+// Quota limits the writes of a user or a client IP; 0 is unlimited
+type Quota struct {
+	ArticlesPerDay  int `json:"articles_per_day"`
+	CommentsPerHour int `json:"comments_per_hour"`
+}
+
+// QuotaService enforces write quotas protecting shared instances from abuse.
+// Users are held to the quota of their role, counted from what they wrote, and
+// client IPs to a quota across all users, counted in memory since the start of
+// this instance.
+type QuotaService struct {
+	articleRepo repositories.ArticleRepository
+	commentRepo repositories.CommentRepository
+	roles       map[models.UserRole]Quota // roles left out are unlimited
+	ip          Quota
+
+	mu       sync.Mutex
+	ipWrites map[ipWriteKey][]time.Time // oldest first
+	maxIPs   int
+	now      func() time.Time
+}
+
+// ipWriteKey identifies the writes of one kind from one client IP
+type ipWriteKey struct {
+	kind string
+	ip   string
+}
+
+// NewQuotaService creates a quota service holding users to the quota of their
+// role and client IPs to ip
+func NewQuotaService(
+	articleRepo repositories.ArticleRepository,
+	commentRepo repositories.CommentRepository,
+	roles map[models.UserRole]Quota,
+	ip Quota,
+) *QuotaService {
+	return &QuotaService{
+		articleRepo: articleRepo,
+		commentRepo: commentRepo,
+		roles:       roles,
+		ip:          ip,
+		ipWrites:    make(map[ipWriteKey][]time.Time),
+		maxIPs:      maxTrackedIPs,
+		now:         time.Now,
+	}
+}
+
+// ReserveArticle returns a quota error when user, or the client at ip, may not
+// create another article yet; ip may be empty outside HTTP requests. Otherwise
+// the article counts towards the quota of ip right away, so concurrent
+// requests cannot overrun it; call release when it is not created after all.
+func (s *QuotaService) ReserveArticle(user *models.User, ip string) (release func(), err error) {
+	now := s.now()
+	if limit := s.roles[user.Role].ArticlesPerDay; limit > 0 {
+		created, err := s.articleRepo.CreatedSince(user.ID, now.Add(-articleQuotaWindow))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count recent articles: %w", err)
+		}
+		if retryAfter, ok := quotaUsed(created, limit, articleQuotaWindow, now); ok {
+			return nil, quotaError(retryAfter, "you can create %d articles per day; try again in %s", limit, formatRetry(retryAfter))
+		}
+	}
+	return s.reserveIP("article", ip, s.ip.ArticlesPerDay, now,
+		"at most %d articles per day can be created from your network; try again in %s")
+}
+
+// ReserveComment returns a quota error when user, or the client at ip, may not
+// comment again yet; ip may be empty outside HTTP requests. Otherwise the
+// comment counts towards the quota of ip right away; call release when it is
+// not written after all.
+func (s *QuotaService) ReserveComment(user *models.User, ip string) (release func(), err error) {
+	now := s.now()
+	if limit := s.roles[user.Role].CommentsPerHour; limit > 0 {
+		written, err := s.commentRepo.CreatedSince(user.ID, now.Add(-commentQuotaWindow))
+		if err != nil {
+			return nil, fmt.Errorf("failed to count recent comments: %w", err)
+		}
+		if retryAfter, ok := quotaUsed(written, limit, commentQuotaWindow, now); ok {
+			return nil, quotaError(retryAfter, "you can write %d comments per hour; try again in %s", limit, formatRetry(retryAfter))
+		}
+	}
+	return s.reserveIP("comment", ip, s.ip.CommentsPerHour, now,
+		"at most %d comments per hour can be written from your network; try again in %s")
+}
+
+// reserveIP returns a quota error with message when ip used up limit writes of
+// kind within its window, and otherwise remembers a write at now with the
+// release removing it again. Checking and remembering under one lock keeps
+// concurrent writes from passing the check together.
+func (s *QuotaService) reserveIP(kind, ip string, limit int, now time.Time, message string) (func(), error) {
+	if ip == "" || limit <= 0 {
+		return func() {}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := ipWriteKey{kind: kind, ip: ip}
+	window := quotaWindows[kind]
+	writes := pruneWrites(s.ipWrites[key], window, now)
+	if retryAfter, ok := quotaUsed(writes, limit, window, now); ok {
+		s.ipWrites[key] = writes
+		return nil, quotaError(retryAfter, message, limit, formatRetry(retryAfter))
+	}
+	s.ipWrites[key] = append(writes, now)
+	if len(s.ipWrites) > s.maxIPs {
+		s.forgetIPs(now)
+	}
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		writes := s.ipWrites[key]
+		for i := len(writes) - 1; i >= 0; i-- {
+			if writes[i].Equal(now) {
+				s.ipWrites[key] = append(writes[:i:i], writes[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+// forgetIPs drops the IPs without writes in their windows at now and, while
+// more than maxIPs are left, those whose last write is oldest. It goes a tenth
+// below the bound so new IPs do not sort the writes on every request.
+func (s *QuotaService) forgetIPs(now time.Time) {
+	for key, writes := range s.ipWrites {
+		if len(pruneWrites(writes, quotaWindows[key.kind], now)) == 0 {
+			delete(s.ipWrites, key)
+		}
+	}
+	if len(s.ipWrites) <= s.maxIPs {
+		return
+	}
+
+	keys := make([]ipWriteKey, 0, len(s.ipWrites))
+	for key := range s.ipWrites {
+		keys = append(keys, key)
+	}
+	lastWrite := func(key ipWriteKey) time.Time {
+		writes := s.ipWrites[key]
+		return writes[len(writes)-1]
+	}
+	sort.Slice(keys, func(i, j int) bool { return lastWrite(keys[i]).Before(lastWrite(keys[j])) })
+	for _, key := range keys[:len(keys)-s.maxIPs*9/10] {
+		delete(s.ipWrites, key)
+	}
+}
+
+// quotaUsed reports whether writes, oldest first, used up limit within window
+// at now, with how long until the oldest counted write leaves the window
+func quotaUsed(writes []time.Time, limit int, window time.Duration, now time.Time) (time.Duration, bool) {
+	if len(writes) < limit {
+		return 0, false
+	}
+	retryAfter := writes[len(writes)-limit].Add(window).Sub(now)
+	if retryAfter < time.Second {
+		retryAfter = time.Second
+	}
+	return retryAfter, true
+}
+
+// pruneWrites drops writes, oldest first, older than window at now
+func pruneWrites(writes []time.Time, window time.Duration, now time.Time) []time.Time {
+	cutoff := now.Add(-window)
+	for len(writes) > 0 && !writes[0].After(cutoff) {
+		writes = writes[1:]
+	}
+	return writes
+}
+
+// formatRetry formats a wait rounded up to the minute, or to the second under
+// a minute
+func formatRetry(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return (d + time.Minute - 1).Truncate(time.Minute).String()
+}
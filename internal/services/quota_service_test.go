@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestQuotaService returns a quota service whose clock is advanced by the
+// returned function
+func newTestQuotaService(articleRepo *mocks.ArticleRepository, roles map[models.UserRole]Quota, ip Quota) (*QuotaService, func(time.Duration)) {
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+	s := NewQuotaService(articleRepo, nil, roles, ip)
+	s.now = func() time.Time { return now }
+	return s, func(d time.Duration) { now = now.Add(d) }
+}
+
+// quotaRetryAfter returns how long err asks to wait, failing unless it is a quota error
+func quotaRetryAfter(t *testing.T, err error) time.Duration {
+	t.Helper()
+	require.ErrorIs(t, err, ErrQuota)
+	var serviceErr *Error
+	require.True(t, errors.As(err, &serviceErr))
+	return serviceErr.RetryAfter
+}
+
+func TestQuotaUsed(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+	writes := []time.Time{now.Add(-50 * time.Minute), now.Add(-20 * time.Minute), now.Add(-5 * time.Minute)}
+
+	tests := []struct {
+		name       string
+		limit      int
+		used       bool
+		retryAfter time.Duration
+	}{
+		{"below the limit", 4, false, 0},
+		{"at the limit", 3, true, 10 * time.Minute}, // the oldest write leaves the window first
+		{"over the limit", 2, true, 40 * time.Minute},
+		{"one write allowed", 1, true, 55 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryAfter, used := quotaUsed(writes, tt.limit, time.Hour, now)
+			assert.Equal(t, tt.used, used)
+			assert.Equal(t, tt.retryAfter, retryAfter)
+		})
+	}
+
+	// A write about to leave the window still asks for a second
+	retryAfter, used := quotaUsed([]time.Time{now.Add(-time.Hour + time.Millisecond)}, 1, time.Hour, now)
+	assert.True(t, used)
+	assert.Equal(t, time.Second, retryAfter)
+}
+
+func TestPruneWrites(t *testing.T) {
+	now := time.Date(2024, time.March, 4, 12, 0, 0, 0, time.UTC)
+	writes := []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour), now.Add(-time.Minute)}
+
+	assert.Equal(t, writes[2:], pruneWrites(writes, time.Hour, now), "writes exactly a window old are dropped")
+	assert.Equal(t, writes, pruneWrites(writes, 3*time.Hour, now))
+	assert.Empty(t, pruneWrites(writes, time.Second, now))
+}
+
+func TestFormatRetry(t *testing.T) {
+	assert.Equal(t, "45s", formatRetry(45*time.Second+300*time.Millisecond))
+	assert.Equal(t, "1m0s", formatRetry(time.Minute))
+	assert.Equal(t, "2m0s", formatRetry(time.Minute+time.Second), "waits are rounded up to the minute")
+	assert.Equal(t, "23h0m0s", formatRetry(22*time.Hour+59*time.Minute+30*time.Second))
+}
+
+// reserve reserves a write and fails unless it is allowed
+func reserve(t *testing.T, reserveWrite func(*models.User, string) (func(), error), ip string) func() {
+	t.Helper()
+	release, err := reserveWrite(&models.User{Role: models.RoleUser}, ip)
+	require.NoError(t, err)
+	return release
+}
+
+func TestQuotaServiceIPWindow(t *testing.T) {
+	s, advance := newTestQuotaService(nil, nil, Quota{ArticlesPerDay: 1, CommentsPerHour: 2})
+	reader := &models.User{Role: models.RoleUser}
+
+	reserve(t, s.ReserveComment, "192.0.2.1")
+	advance(10 * time.Minute)
+	reserve(t, s.ReserveComment, "192.0.2.1")
+	_, err := s.ReserveComment(reader, "192.0.2.1")
+	assert.Equal(t, 50*time.Minute, quotaRetryAfter(t, err))
+
+	// Other addresses, kinds of writes and writes outside HTTP requests have their own count
+	reserve(t, s.ReserveComment, "192.0.2.2")
+	reserve(t, s.ReserveArticle, "192.0.2.1")
+	reserve(t, s.ReserveComment, "")
+
+	// The window slides: once the first comment is an hour old one more is allowed
+	advance(50 * time.Minute)
+	reserve(t, s.ReserveComment, "192.0.2.1")
+	_, err = s.ReserveComment(reader, "192.0.2.1")
+	assert.Equal(t, 10*time.Minute, quotaRetryAfter(t, err))
+
+	// Articles are counted per day
+	reserve(t, s.ReserveArticle, "192.0.2.3")
+	advance(23 * time.Hour)
+	_, err = s.ReserveArticle(reader, "192.0.2.3")
+	assert.Equal(t, time.Hour, quotaRetryAfter(t, err))
+	advance(time.Hour)
+	reserve(t, s.ReserveArticle, "192.0.2.3")
+}
+
+func TestQuotaServiceRelease(t *testing.T) {
+	s, advance := newTestQuotaService(nil, nil, Quota{CommentsPerHour: 2})
+	reader := &models.User{Role: models.RoleUser}
+
+	reserve(t, s.ReserveComment, "192.0.2.1")
+	advance(time.Minute)
+	release := reserve(t, s.ReserveComment, "192.0.2.1")
+	_, err := s.ReserveComment(reader, "192.0.2.1")
+	require.ErrorIs(t, err, ErrQuota)
+
+	// A write that failed gives its place back, and only its own
+	release()
+	release()
+	reserve(t, s.ReserveComment, "192.0.2.1")
+	_, err = s.ReserveComment(reader, "192.0.2.1")
+	require.ErrorIs(t, err, ErrQuota)
+}
+
+func TestQuotaServiceReservesConcurrently(t *testing.T) {
+	s, _ := newTestQuotaService(nil, nil, Quota{ArticlesPerDay: 5})
+	author := &models.User{Role: models.RoleUser}
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.ReserveArticle(author, "192.0.2.1"); err == nil {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(5), allowed.Load(), "concurrent writes cannot pass the check together")
+}
+
+func TestQuotaServiceForgetsIPs(t *testing.T) {
+	s, advance := newTestQuotaService(nil, nil, Quota{ArticlesPerDay: 1, CommentsPerHour: 1})
+	s.maxIPs = 10
+	reader := &models.User{Role: models.RoleUser}
+
+	// Comments leave their window after an hour while articles are kept for a day
+	reserve(t, s.ReserveArticle, "198.51.100.1")
+	for i := range 8 {
+		reserve(t, s.ReserveComment, fmt.Sprintf("192.0.2.%d", i))
+	}
+	advance(2 * time.Hour)
+	reserve(t, s.ReserveComment, "192.0.2.100")
+	reserve(t, s.ReserveComment, "192.0.2.101")
+	assert.Len(t, s.ipWrites, 3, "IPs without writes in the window of their kind are forgotten")
+	_, err := s.ReserveArticle(reader, "198.51.100.1")
+	require.ErrorIs(t, err, ErrQuota, "articles are kept for their own window")
+
+	// Beyond the bound the IPs that wrote least recently are forgotten
+	for i := range 20 {
+		advance(time.Second)
+		reserve(t, s.ReserveComment, fmt.Sprintf("203.0.113.%d", i))
+	}
+	assert.LessOrEqual(t, len(s.ipWrites), s.maxIPs)
+	_, err = s.ReserveComment(reader, "203.0.113.19")
+	require.ErrorIs(t, err, ErrQuota, "the latest writers are remembered")
+	reserve(t, s.ReserveArticle, "198.51.100.1") // the oldest writer was forgotten
+}
+
+func TestQuotaServiceRoleWindow(t *testing.T) {
+	articleRepo := new(mocks.ArticleRepository)
+	s, _ := newTestQuotaService(articleRepo, map[models.UserRole]Quota{models.RoleUser: {ArticlesPerDay: 2}}, Quota{})
+	now := s.now()
+	author := &models.User{ID: 7, Role: models.RoleUser}
+
+	// Articles of the last 24 hours are counted
+	articleRepo.On("CreatedSince", uint(7), now.Add(-24*time.Hour)).
+		Return([]time.Time{now.Add(-20 * time.Hour), now.Add(-time.Hour)}, nil).Once()
+	_, err := s.ReserveArticle(author, "192.0.2.1")
+	assert.Equal(t, 4*time.Hour, quotaRetryAfter(t, err))
+
+	articleRepo.On("CreatedSince", uint(7), now.Add(-24*time.Hour)).Return([]time.Time{now.Add(-time.Hour)}, nil).Once()
+	_, err = s.ReserveArticle(author, "192.0.2.1")
+	assert.NoError(t, err)
+
+	// Roles without a quota are not counted
+	_, err = s.ReserveArticle(&models.User{ID: 8, Role: models.RoleAdmin}, "192.0.2.1")
+	assert.NoError(t, err)
+	articleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// maxSavedSearches caps how many searches a single user can save
+const maxSavedSearches = 25
+
+// alertArticleLimit caps how many new articles are listed in one alert
+const alertArticleLimit = 10
+
+// SavedSearchService manages saved searches and evaluates their alerts
+type SavedSearchService struct {
+	savedSearchRepo     repositories.SavedSearchRepository
+	searchService       *SearchService
+	notificationService *NotificationService
+}
+
+// SavedSearchRequest represents saved search creation and update data
+type SavedSearchRequest struct {
+	Name       string `json:"name" validate:"required,min=1,max=100"`
+	Query      string `json:"query" validate:"required,min=1,max=255"`
+	CategoryID uint   `json:"category_id,omitempty"`
+	AuthorID   uint   `json:"author_id,omitempty"`
+	TagID      uint   `json:"tag_id,omitempty"`
+	SearchMode string `json:"search_mode,omitempty" validate:"omitempty,oneof=natural boolean"`
+	Alert      bool   `json:"alert"`
+	EmailAlert bool   `json:"email_alert"`
+}
+
+// AlertReport summarizes one evaluation of saved search alerts
+type AlertReport struct {
+	Checked  int `json:"checked"`
+	Notified int `json:"notified"`
+	Failed   int `json:"failed"`
+}
+
+// NewSavedSearchService creates a new saved search service
+func NewSavedSearchService(
+	savedSearchRepo repositories.SavedSearchRepository,
+	searchService *SearchService,
+	notificationService *NotificationService,
+) *SavedSearchService {
+	return &SavedSearchService{
+		savedSearchRepo:     savedSearchRepo,
+		searchService:       searchService,
+		notificationService: notificationService,
+	}
+}
+
+// Create saves a search for the user
+func (s *SavedSearchService) Create(userID uint, req *SavedSearchRequest) (*models.SavedSearch, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	count, err := s.savedSearchRepo.CountByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count saved searches: %w", err)
+	}
+	if count >= maxSavedSearches {
+		return nil, validationError("cannot save more than %d searches", maxSavedSearches)
+	}
+
+	// Alerts only report articles published after the search was saved
+	now := time.Now()
+	search := &models.SavedSearch{UserID: userID, LastCheckedAt: &now}
+	applySavedSearchRequest(search, req)
+
+	if err := s.savedSearchRepo.Create(search); err != nil {
+		return nil, fmt.Errorf("failed to save search: %w", err)
+	}
+	return search, nil
+}
+
+// List returns the user's saved searches ordered by name
+func (s *SavedSearchService) List(userID uint) ([]models.SavedSearch, error) {
+	return s.savedSearchRepo.ListByUser(userID)
+}
+
+// Get returns one of the user's saved searches
+func (s *SavedSearchService) Get(userID, id uint) (*models.SavedSearch, error) {
+	search, err := s.savedSearchRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("saved search not found")
+		}
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	if search.UserID != userID {
+		return nil, notFoundError("saved search not found")
+	}
+	return search, nil
+}
+
+// Update replaces one of the user's saved searches
+func (s *SavedSearchService) Update(userID, id uint, req *SavedSearchRequest) (*models.SavedSearch, error) {
+	if err := s.validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	search, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Turning alerts on starts from now rather than replaying older matches
+	if req.Alert && !search.Alert {
+		now := time.Now()
+		search.LastCheckedAt = &now
+	}
+	applySavedSearchRequest(search, req)
+
+	if err := s.savedSearchRepo.Update(search); err != nil {
+		return nil, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return search, nil
+}
+
+// Delete removes one of the user's saved searches
+func (s *SavedSearchService) Delete(userID, id uint) error {
+	if _, err := s.Get(userID, id); err != nil {
+		return err
+	}
+	if err := s.savedSearchRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return nil
+}
+
+// Run executes one of the user's saved searches
+func (s *SavedSearchService) Run(userID, id uint, page, limit int) (*SearchResponse, error) {
+	search, err := s.Get(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.searchService.Search(searchRequest(search), page, limit)
+}
+
+// CheckAlerts evaluates every saved search with alerts enabled and notifies its
+// owner about articles published since the previous check. A failing search is
+// counted and skipped so one bad query does not block the others.
+func (s *SavedSearchService) CheckAlerts() (*AlertReport, error) {
+	searches, err := s.savedSearchRepo.ListAlerting()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved searches: %w", err)
+	}
+
+	report := &AlertReport{}
+	for i := range searches {
+		notified, err := s.checkAlert(&searches[i])
+		report.Checked++
+		if err != nil {
+			report.Failed++
+			continue
+		}
+		if notified {
+			report.Notified++
+		}
+	}
+	return report, nil
+}
+
+// checkAlert evaluates a single saved search and advances its checkpoint
+func (s *SavedSearchService) checkAlert(search *models.SavedSearch) (bool, error) {
+	checkedAt := time.Now()
+
+	req := searchRequest(search)
+	if search.LastCheckedAt != nil {
+		req.PublishedAfter = *search.LastCheckedAt
+	} else {
+		req.PublishedAfter = search.CreatedAt
+	}
+
+	result, err := s.searchService.Search(req, 1, alertArticleLimit)
+	if err != nil {
+		return false, err
+	}
+
+	if result.Total > 0 {
+		if err := s.notificationService.Notify(alertNotification(search, result), search.EmailAlert); err != nil {
+			return false, err
+		}
+	}
+
+	if err := s.savedSearchRepo.MarkChecked(search.ID, checkedAt); err != nil {
+		return false, fmt.Errorf("failed to update saved search: %w", err)
+	}
+	return result.Total > 0, nil
+}
+
+// validateRequest validates saved search input
+func (s *SavedSearchService) validateRequest(req *SavedSearchRequest) error {
+	if req == nil {
+		return validationError("saved search is required")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return validationError("saved search name is required")
+	}
+	if req.EmailAlert && !req.Alert {
+		return validationError("email alerts require alerts to be enabled")
+	}
+	return s.searchService.validateSearchRequest(searchRequest(&models.SavedSearch{
+		Query:      req.Query,
+		SearchMode: req.SearchMode,
+	}))
+}
+
+// applySavedSearchRequest copies request fields onto search
+func applySavedSearchRequest(search *models.SavedSearch, req *SavedSearchRequest) {
+	search.Name = strings.TrimSpace(req.Name)
+	search.Query = strings.TrimSpace(req.Query)
+	search.CategoryID = req.CategoryID
+	search.AuthorID = req.AuthorID
+	search.TagID = req.TagID
+	search.SearchMode = req.SearchMode
+	if search.SearchMode == "" {
+		search.SearchMode = "natural"
+	}
+	search.Alert = req.Alert
+	search.EmailAlert = req.EmailAlert
+}
+
+// searchRequest builds the published-article search a saved search stands for
+func searchRequest(search *models.SavedSearch) *SearchRequest {
+	return &SearchRequest{
+		Query:      search.Query,
+		CategoryID: search.CategoryID,
+		AuthorID:   search.AuthorID,
+		TagID:      search.TagID,
+		Status:     string(models.StatusPublished),
+		SearchMode: search.SearchMode,
+	}
+}
+
+// alertNotification describes the new matches for a saved search
+func alertNotification(search *models.SavedSearch, result *SearchResponse) *models.Notification {
+	lines := make([]string, 0, len(result.Articles)+1)
+	for _, article := range result.Articles {
+		lines = append(lines, "- "+article.Title)
+	}
+	if more := result.Total - int64(len(result.Articles)); more > 0 {
+		lines = append(lines, fmt.Sprintf("and %d more", more))
+	}
+
+	noun := "articles match"
+	if result.Total == 1 {
+		noun = "article matches"
+	}
+
+	return &models.Notification{
+		UserID: search.UserID,
+		Type:   models.NotificationSavedSearch,
+		Title:  fmt.Sprintf("%d new %s %q", result.Total, noun, search.Name),
+		Body:   strings.Join(lines, "\n"),
+		Link:   fmt.Sprintf("/api/users/me/saved-searches/%d/results", search.ID),
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import "strings"
+
+// searchWordTrim are the characters around a word ignored when matching stop
+// words and synonyms
+const searchWordTrim = `.,!?:;()[]"'`
+
+// SearchAnalyzer rewrites article search queries before they reach the
+// full-text search. It drops stop words and adds the synonyms of every other
+// word, so searching for one word of a synonym group also finds articles using
+// the others. Both natural and boolean searches match any of their words, so
+// the added synonyms widen a search without requiring them.
+type SearchAnalyzer struct {
+	stopWords map[string]bool
+	synonyms  map[string][]string
+}
+
+// NewSearchAnalyzer creates an analyzer of stopWords and synonym groups,
+// matched case-insensitively
+func NewSearchAnalyzer(stopWords []string, synonyms [][]string) *SearchAnalyzer {
+	a := &SearchAnalyzer{
+		stopWords: make(map[string]bool, len(stopWords)),
+		synonyms:  make(map[string][]string),
+	}
+	for _, word := range stopWords {
+		a.stopWords[strings.ToLower(word)] = true
+	}
+	for _, group := range synonyms {
+		for _, word := range group {
+			word = strings.ToLower(word)
+			for _, synonym := range group {
+				if synonym = strings.ToLower(synonym); synonym != word {
+					a.synonyms[word] = append(a.synonyms[word], synonym)
+				}
+			}
+		}
+	}
+	return a
+}
+
+// Analyze returns query without its stop words and with the synonyms of its
+// words appended. A query of nothing but stop words is kept as it is, since
+// searching for nothing would find nothing.
+func (a *SearchAnalyzer) Analyze(query string) string {
+	seen := make(map[string]bool)
+	var kept, normalized []string
+	for _, word := range strings.Fields(query) {
+		key := strings.ToLower(strings.Trim(word, searchWordTrim))
+		if key == "" || a.stopWords[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		kept = append(kept, word)
+		normalized = append(normalized, key)
+	}
+	if len(kept) == 0 {
+		return query
+	}
+
+	for _, word := range normalized {
+		for _, synonym := range a.synonyms[word] {
+			if !seen[synonym] {
+				seen[synonym] = true
+				kept = append(kept, synonym)
+			}
+		}
+	}
+	return strings.Join(kept, " ")
+}
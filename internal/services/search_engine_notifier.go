@@ -0,0 +1,191 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"go-blog/internal/models"
+	"go-blog/internal/queue"
+)
+
+// searchEngineTimeout bounds each request to a search engine
+const searchEngineTimeout = 10 * time.Second
+
+// SearchEngineJob is the job type of queued search engine notifications
+const SearchEngineJob = "search_engines.notify"
+
+// SearchEngineOptions configures the search engines told about published articles
+type SearchEngineOptions struct {
+	ArticleURL       string   // public URL of an article; {slug} is replaced with its slug
+	SitemapURL       string   // sitemap sent to the ping URLs
+	PingURLs         []string // sitemap ping endpoints, called with ?sitemap=<SitemapURL>
+	IndexNowKey      string   // empty disables IndexNow
+	IndexNowEndpoint string
+}
+
+// SearchEngineNotifier tells search engines about newly published articles. It
+// pings the sitemap ping endpoints and submits the article URL to IndexNow.
+// Notifications are sent in the background, through the job queue when one is
+// set so failures are retried; they are never reported to the author.
+type SearchEngineNotifier struct {
+	options SearchEngineOptions
+	client  *http.Client
+	queue   *queue.Queue
+	site    *SiteVisibilityService // nil notifies regardless of visibility
+	pending sync.WaitGroup
+}
+
+// searchEngineNotification is the payload of a SearchEngineJob
+type searchEngineNotification struct {
+	URL string `json:"url"`
+}
+
+// indexNowRequest is the body of an IndexNow submission
+type indexNowRequest struct {
+	Host        string   `json:"host"`
+	Key         string   `json:"key"`
+	KeyLocation string   `json:"keyLocation"`
+	URLList     []string `json:"urlList"`
+}
+
+// NewSearchEngineNotifier creates a search engine notifier
+func NewSearchEngineNotifier(options SearchEngineOptions) *SearchEngineNotifier {
+	return &SearchEngineNotifier{
+		options: options,
+		client:  &http.Client{Timeout: searchEngineTimeout},
+	}
+}
+
+// IndexNowKey returns the IndexNow key, which must be served at /<key>.txt
+func (n *SearchEngineNotifier) IndexNowKey() string {
+	return n.options.IndexNowKey
+}
+
+// SetQueue sends notifications through the job queue, retrying failed ones
+func (n *SearchEngineNotifier) SetQueue(q *queue.Queue) {
+	n.queue = q
+	q.Register(SearchEngineJob, n.handleJob)
+}
+
+// SetSiteVisibility skips notifications while the blog is unlisted or private
+func (n *SearchEngineNotifier) SetSiteVisibility(site *SiteVisibilityService) {
+	n.site = site
+}
+
+// ArticlePublished notifies search engines about article in the background
+func (n *SearchEngineNotifier) ArticlePublished(article *models.Article) {
+	if n.site != nil && !n.site.Listed() {
+		return
+	}
+	articleURL := strings.ReplaceAll(n.options.ArticleURL, "{slug}", url.PathEscape(article.Slug))
+
+	if n.queue != nil {
+		err := n.queue.Enqueue(SearchEngineJob, searchEngineNotification{URL: articleURL})
+		if err == nil {
+			return
+		}
+		slog.Warn("Sending search engine notification directly", "url", articleURL, "error", err)
+	}
+
+	n.pending.Add(1)
+	go func() {
+		defer n.pending.Done()
+		if err := n.notify(articleURL); err != nil {
+			slog.Error("Search engine notification failed", "url", articleURL, "error", err)
+		}
+	}()
+}
+
+// Wait blocks until every notification started outside the queue has been sent
+func (n *SearchEngineNotifier) Wait() {
+	n.pending.Wait()
+}
+
+// handleJob sends a queued notification
+func (n *SearchEngineNotifier) handleJob(ctx context.Context, payload []byte) error {
+	var notification searchEngineNotification
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid search engine payload: %w", err))
+	}
+	return n.notify(notification.URL)
+}
+
+// notify pings the sitemap endpoints and submits articleURL to IndexNow. Every
+// endpoint is tried; the failures are returned together.
+func (n *SearchEngineNotifier) notify(articleURL string) error {
+	var errs []error
+	if n.options.SitemapURL != "" {
+		for _, pingURL := range n.options.PingURLs {
+			if err := n.pingSitemap(pingURL); err != nil {
+				errs = append(errs, fmt.Errorf("sitemap ping to %s: %w", pingURL, err))
+			}
+		}
+	}
+
+	if n.options.IndexNowKey != "" && n.options.IndexNowEndpoint != "" {
+		if err := n.submitIndexNow(articleURL); err != nil {
+			errs = append(errs, fmt.Errorf("IndexNow submission: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// pingSitemap asks a search engine to recrawl the sitemap
+func (n *SearchEngineNotifier) pingSitemap(pingURL string) error {
+	separator := "?"
+	if strings.Contains(pingURL, "?") {
+		separator = "&"
+	}
+
+	resp, err := n.client.Get(pingURL + separator + "sitemap=" + url.QueryEscape(n.options.SitemapURL))
+	if err != nil {
+		return err
+	}
+	return checkSearchEngineResponse(resp)
+}
+
+// submitIndexNow submits articleURL to the IndexNow endpoint, which shares it
+// with every participating search engine
+func (n *SearchEngineNotifier) submitIndexNow(articleURL string) error {
+	parsed, err := url.Parse(articleURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("invalid article URL %q", articleURL)
+	}
+
+	body, err := json.Marshal(indexNowRequest{
+		Host:        parsed.Host,
+		Key:         n.options.IndexNowKey,
+		KeyLocation: parsed.Scheme + "://" + parsed.Host + "/" + n.options.IndexNowKey + ".txt",
+		URLList:     []string{articleURL},
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.options.IndexNowEndpoint, "application/json; charset=utf-8", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return checkSearchEngineResponse(resp)
+}
+
+// checkSearchEngineResponse closes resp and turns error statuses into errors
+func checkSearchEngineResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxInstantQuery bounds the prefix of search-as-you-type, in characters
+	maxInstantQuery = 100
+	// defaultInstantLimit and maxInstantLimit bound each list of instant results
+	defaultInstantLimit = 5
+	maxInstantLimit     = 10
+)
+
+// InstantResults are the search-as-you-type matches of a prefix, kept small
+// for a response per keystroke
+type InstantResults struct {
+	Query    string           `json:"query"`
+	Articles []InstantArticle `json:"articles"`
+	Tags     []InstantTag     `json:"tags"`
+}
+
+// InstantArticle is a published article whose title matches the prefix
+type InstantArticle struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+}
+
+// InstantTag is a tag whose name starts with the prefix
+type InstantTag struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	ArticleCount int64  `json:"article_count"`
+}
+
+// Instant returns up to limit published articles with a title word starting
+// with query and up to limit tags whose name starts with it. Unlike Search it
+// skips the full-text search, content and filters.
+func (s *SearchService) Instant(query string, limit int) (*InstantResults, error) {
+	query = strings.Join(strings.Fields(query), " ")
+	if query == "" {
+		return nil, validationError("search query is required")
+	}
+	if len([]rune(query)) > maxInstantQuery {
+		return nil, validationError("search query must be at most %d characters", maxInstantQuery)
+	}
+	if limit < 1 {
+		limit = defaultInstantLimit
+	}
+	limit = min(limit, maxInstantLimit)
+
+	articles, err := s.articleRepo.SearchTitlePrefix(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("instant search failed: %w", err)
+	}
+	tags, err := s.tagRepo.Autocomplete(strings.ToLower(query), limit)
+	if err != nil {
+		return nil, fmt.Errorf("instant search failed: %w", err)
+	}
+
+	results := &InstantResults{
+		Query:    query,
+		Articles: make([]InstantArticle, len(articles)),
+		Tags:     make([]InstantTag, len(tags)),
+	}
+	for i, article := range articles {
+		results.Articles[i] = InstantArticle{ID: article.ID, Title: article.Title, Slug: article.Slug}
+	}
+	for i, tag := range tags {
+		results.Tags[i] = InstantTag{Name: tag.Name, Slug: tag.Slug, ArticleCount: tag.ArticleCount}
+	}
+	return results, nil
+}
@@ -16,6 +16,10 @@ type SearchService struct {
 	articleRepo  repositories.ArticleRepository
 	categoryRepo repositories.CategoryRepository
 	tagRepo      repositories.TagRepository
+	userRepo     repositories.UserRepository
+	tagService   *TagService     // optional, resolves tag aliases of interlink drafts
+	analyzer     *SearchAnalyzer // optional, applies stop words and synonyms to article searches
+	spelling     spellingVocabulary
 }
 
 // SearchRequest represents a search request
@@ -28,18 +32,27 @@ type SearchRequest struct {
 	DateFrom   time.Time `json:"date_from,omitempty"`
 	DateTo     time.Time `json:"date_to,omitempty"`
 	SearchMode string    `json:"search_mode,omitempty" validate:"omitempty,oneof=natural boolean"`
+	// Types selects the indexes to consult: articles, comments and users. Comment
+	// matches are returned as their articles. Defaults to articles only.
+	Types []string `json:"types,omitempty" validate:"omitempty,dive,oneof=articles comments users"`
+	// PublishedAfter limits results to articles published after it; used by search alerts
+	PublishedAfter time.Time `json:"-"`
 }
 
-// SearchResponse represents search results
+// SearchResponse represents search results. Articles and users are paged
+// independently; Total is the larger of the per-type totals so that paging covers both.
 type SearchResponse struct {
-	Articles    []models.Article `json:"articles"`
-	Total       int64            `json:"total"`
-	Page        int              `json:"page"`
-	Limit       int              `json:"limit"`
-	TotalPages  int              `json:"total_pages"`
-	Query       string           `json:"query"`
-	SearchTime  time.Duration    `json:"search_time_ms"`
-	Suggestions []string         `json:"suggestions,omitempty"`
+	Types       []string                `json:"types"`
+	Articles    []models.ArticleSummary `json:"articles"`
+	Users       []models.AuthorProfile  `json:"users,omitempty"`
+	Totals      map[string]int64        `json:"totals"`
+	Total       int64                   `json:"total"`
+	Page        int                     `json:"page"`
+	Limit       int                     `json:"limit"`
+	TotalPages  int                     `json:"total_pages"`
+	Query       string                  `json:"query"`
+	SearchTime  time.Duration           `json:"search_time_ms"`
+	Suggestions []string                `json:"suggestions,omitempty"`
 }
 
 // SearchSuggestion represents a search suggestion
@@ -55,14 +68,21 @@ func NewSearchService(
 	articleRepo repositories.ArticleRepository,
 	categoryRepo repositories.CategoryRepository,
 	tagRepo repositories.TagRepository,
+	userRepo repositories.UserRepository,
 ) *SearchService {
 	return &SearchService{
 		articleRepo:  articleRepo,
 		categoryRepo: categoryRepo,
 		tagRepo:      tagRepo,
+		userRepo:     userRepo,
 	}
 }
 
+// SetAnalyzer sets the stop words and synonyms applied to article searches
+func (s *SearchService) SetAnalyzer(analyzer *SearchAnalyzer) {
+	s.analyzer = analyzer
+}
+
 // Search performs a search with the given parameters
 func (s *SearchService) Search(req *SearchRequest, page, limit int) (*SearchResponse, error) {
 	startTime := time.Now()
@@ -75,7 +95,7 @@ func (s *SearchService) Search(req *SearchRequest, page, limit int) (*SearchResp
 	// Sanitize and prepare query
 	query := s.sanitizeQuery(req.Query)
 	if query == "" {
-		return nil, errors.New("search query cannot be empty after sanitization")
+		return nil, validationError("search query cannot be empty after sanitization")
 	}
 
 	// Validate pagination
@@ -96,25 +116,56 @@ func (s *SearchService) Search(req *SearchRequest, page, limit int) (*SearchResp
 		TagID:      req.TagID,
 		DateFrom:   req.DateFrom,
 		DateTo:     req.DateTo,
+
+		PublishedAfter: req.PublishedAfter,
+	}
+
+	types := searchTypes(req.Types)
+	totals := make(map[string]int64, len(types))
+	for _, t := range types {
+		if t != string(repositories.SearchUsers) {
+			filters.Targets = append(filters.Targets, repositories.SearchTarget(t))
+		}
 	}
 
 	// Perform search based on mode
 	var articles []models.Article
+	var users []models.User
 	var total int64
 	var err error
 
-	switch req.SearchMode {
-	case "boolean":
-		// Prepare query for boolean search
-		booleanQuery := s.prepareBooleanQuery(query)
-		articles, total, err = s.articleRepo.SearchWithBoolean(booleanQuery, offset, limit, filters)
-	default:
-		// Default to natural language search
-		articles, total, err = s.articleRepo.AdvancedSearch(query, offset, limit, filters)
+	if len(filters.Targets) > 0 {
+		articleQuery := query
+		if s.analyzer != nil {
+			articleQuery = s.analyzer.Analyze(query)
+		}
+
+		switch req.SearchMode {
+		case "boolean":
+			// Prepare query for boolean search
+			booleanQuery := s.prepareBooleanQuery(articleQuery)
+			articles, total, err = s.articleRepo.SearchWithBoolean(booleanQuery, offset, limit, filters)
+		default:
+			// Default to natural language search
+			articles, total, err = s.articleRepo.AdvancedSearch(articleQuery, offset, limit, filters)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		totals["articles"] = total
 	}
 
-	if err != nil {
-		return nil, fmt.Errorf("search failed: %w", err)
+	if hasSearchType(types, string(repositories.SearchUsers)) {
+		var userTotal int64
+		users, userTotal, err = s.userRepo.Search(query, offset, limit)
+		if err != nil {
+			return nil, fmt.Errorf("user search failed: %w", err)
+		}
+		totals["users"] = userTotal
+		if userTotal > total {
+			total = userTotal
+		}
 	}
 
 	// Calculate total pages
@@ -128,8 +179,16 @@ func (s *SearchService) Search(req *SearchRequest, page, limit int) (*SearchResp
 
 	searchTime := time.Since(startTime)
 
+	var profiles []models.AuthorProfile
+	if users != nil {
+		profiles = models.ProfileUsers(users)
+	}
+
 	return &SearchResponse{
-		Articles:    articles,
+		Types:       types,
+		Articles:    models.SummarizeArticles(articles),
+		Users:       profiles,
+		Totals:      totals,
 		Total:       total,
 		Page:        page,
 		Limit:       limit,
@@ -140,6 +199,48 @@ func (s *SearchService) Search(req *SearchRequest, page, limit int) (*SearchResp
 	}, nil
 }
 
+// SearchAuthorArticles searches within the articles of the author authorID,
+// overriding any author filter of req
+func (s *SearchService) SearchAuthorArticles(authorID uint, req *SearchRequest, page, limit int) (*SearchResponse, error) {
+	if _, err := s.userRepo.GetByID(authorID); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	req.AuthorID = authorID
+	return s.Search(scopedSearch(req), page, limit)
+}
+
+// SearchCategoryArticles searches within the articles of the category with the
+// given slug, overriding any category filter of req
+func (s *SearchService) SearchCategoryArticles(slug string, req *SearchRequest, page, limit int) (*SearchResponse, error) {
+	category, err := s.categoryRepo.GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	req.CategoryID = category.ID
+	return s.Search(scopedSearch(req), page, limit)
+}
+
+// scopedSearch drops the users type from req, as searches scoped to an author
+// or category only look for articles
+func scopedSearch(req *SearchRequest) *SearchRequest {
+	types := req.Types[:0:0]
+	for _, t := range req.Types {
+		if t != string(repositories.SearchUsers) {
+			types = append(types, t)
+		}
+	}
+	req.Types = types
+	return req
+}
+
 // QuickSearch performs a simple search without advanced filters
 func (s *SearchService) QuickSearch(query string, page, limit int) (*SearchResponse, error) {
 	req := &SearchRequest{
@@ -196,15 +297,15 @@ func (s *SearchService) GetSearchSuggestions(partialQuery string, limit int) ([]
 // validateSearchRequest validates the search request
 func (s *SearchService) validateSearchRequest(req *SearchRequest) error {
 	if req == nil {
-		return errors.New("search request is required")
+		return validationError("search request is required")
 	}
 
 	if strings.TrimSpace(req.Query) == "" {
-		return errors.New("search query is required")
+		return validationError("search query is required")
 	}
 
 	if len(req.Query) > 255 {
-		return errors.New("search query must be less than 255 characters")
+		return validationError("search query must be less than 255 characters")
 	}
 
 	if req.Status != "" {
@@ -217,21 +318,59 @@ func (s *SearchService) validateSearchRequest(req *SearchRequest) error {
 			}
 		}
 		if !valid {
-			return errors.New("invalid status filter")
+			return validationError("invalid status filter")
 		}
 	}
 
 	if req.SearchMode != "" && req.SearchMode != "natural" && req.SearchMode != "boolean" {
-		return errors.New("search mode must be 'natural' or 'boolean'")
+		return validationError("search mode must be 'natural' or 'boolean'")
 	}
 
 	if !req.DateFrom.IsZero() && !req.DateTo.IsZero() && req.DateFrom.After(req.DateTo) {
-		return errors.New("date_from must be before date_to")
+		return validationError("date_from must be before date_to")
+	}
+
+	for _, t := range req.Types {
+		if !hasSearchType(searchTypeNames, t) {
+			return validationError("search type must be one of %s", strings.Join(searchTypeNames, ", "))
+		}
 	}
 
 	return nil
 }
 
+// searchTypeNames lists the accepted search types in response order
+var searchTypeNames = []string{
+	string(repositories.SearchArticles),
+	string(repositories.SearchComments),
+	string(repositories.SearchUsers),
+}
+
+// searchTypes returns the requested search types de-duplicated and in a stable
+// order, defaulting to articles only
+func searchTypes(requested []string) []string {
+	if len(requested) == 0 {
+		return []string{string(repositories.SearchArticles)}
+	}
+	types := make([]string, 0, len(searchTypeNames))
+	for _, name := range searchTypeNames {
+		if hasSearchType(requested, name) {
+			types = append(types, name)
+		}
+	}
+	return types
+}
+
+// hasSearchType reports whether types contains name
+func hasSearchType(types []string, name string) bool {
+	for _, t := range types {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
 // sanitizeQuery sanitizes the search query
 func (s *SearchService) sanitizeQuery(query string) string {
 	// Trim whitespace
@@ -275,54 +414,6 @@ func (s *SearchService) prepareBooleanQuery(query string) string {
 	return strings.Join(booleanTerms, " ")
 }
 
-// generateSuggestions generates search suggestions when no results are found
-func (s *SearchService) generateSuggestions(query string) []string {
-	suggestions := make([]string, 0)
-
-	// Simple suggestions based on common search patterns
-	words := strings.Fields(strings.ToLower(query))
-	if len(words) > 0 {
-		// Suggest removing words
-		if len(words) > 1 {
-			for i := range words {
-				newWords := make([]string, 0, len(words)-1)
-				for j, word := range words {
-					if i != j {
-						newWords = append(newWords, word)
-					}
-				}
-				suggestions = append(suggestions, strings.Join(newWords, " "))
-			}
-		}
-
-		// Suggest alternative spellings (simple character removal/addition)
-		firstWord := words[0]
-		if len(firstWord) > 3 {
-			// Remove last character
-			suggestions = append(suggestions, firstWord[:len(firstWord)-1])
-			// Remove first character
-			suggestions = append(suggestions, firstWord[1:])
-		}
-	}
-
-	// Remove duplicates and limit
-	seen := make(map[string]bool)
-	uniqueSuggestions := make([]string, 0)
-	for _, suggestion := range suggestions {
-		if !seen[suggestion] && suggestion != strings.ToLower(query) {
-			seen[suggestion] = true
-			uniqueSuggestions = append(uniqueSuggestions, suggestion)
-		}
-	}
-
-	// Limit to 5 suggestions
-	if len(uniqueSuggestions) > 5 {
-		uniqueSuggestions = uniqueSuggestions[:5]
-	}
-
-	return uniqueSuggestions
-}
-
 // GetPopularSearchTerms returns popular search terms (placeholder for future implementation)
 func (s *SearchService) GetPopularSearchTerms(limit int) ([]string, error) {
 	// This would typically be implemented with search analytics
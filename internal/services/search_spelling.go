@@ -0,0 +1,190 @@
+package services
+
+import (
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+const (
+	// spellingTitles bounds how many of the latest article titles feed the vocabulary
+	spellingTitles = 5000
+	// spellingTTL is how long a loaded vocabulary is used before it is loaded again
+	spellingTTL = 10 * time.Minute
+	// spellingCandidates is how many corrections of one word are considered
+	spellingCandidates = 3
+	// maxSpellingSuggestions caps the suggestions of a search
+	maxSpellingSuggestions = 5
+)
+
+// spellingVocabulary counts the words of published titles, tags and
+// categories, which misspelled search words are corrected to
+type spellingVocabulary struct {
+	mu       sync.Mutex
+	words    map[string]int
+	loadedAt time.Time
+}
+
+// spellingCandidate is a vocabulary word close to a searched word
+type spellingCandidate struct {
+	word      string
+	distance  int
+	frequency int
+}
+
+// generateSuggestions returns "did you mean" queries for a search without
+// results: the query with its words unknown to the vocabulary replaced by the
+// closest known words, the most frequent first among equally close ones
+func (s *SearchService) generateSuggestions(query string) []string {
+	vocabulary, err := s.spellingWords()
+	if err != nil {
+		slog.Warn("Spelling suggestions unavailable", "error", err)
+		return []string{}
+	}
+
+	words := vocabularyWords(query)
+	best := make([]string, len(words))
+	alternatives := make(map[int][]string)
+	for i, word := range words {
+		best[i] = word
+		if _, known := vocabulary[word]; known {
+			continue
+		}
+		candidates := closestWords(vocabulary, word)
+		if len(candidates) == 0 {
+			continue
+		}
+		best[i] = candidates[0]
+		alternatives[i] = candidates[1:]
+	}
+
+	suggestions := make([]string, 0, maxSpellingSuggestions)
+	seen := map[string]bool{strings.Join(words, " "): true}
+	add := func(suggestion string) {
+		if !seen[suggestion] && len(suggestions) < maxSpellingSuggestions {
+			seen[suggestion] = true
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	add(strings.Join(best, " "))
+	for i := range words {
+		for _, alternative := range alternatives[i] {
+			variant := append([]string(nil), best...)
+			variant[i] = alternative
+			add(strings.Join(variant, " "))
+		}
+	}
+	return suggestions
+}
+
+// spellingWords returns the vocabulary, loading it again once it is older than
+// spellingTTL
+func (s *SearchService) spellingWords() (map[string]int, error) {
+	s.spelling.mu.Lock()
+	defer s.spelling.mu.Unlock()
+	if s.spelling.words != nil && time.Since(s.spelling.loadedAt) < spellingTTL {
+		return s.spelling.words, nil
+	}
+
+	var texts []string
+	titles, err := s.articleRepo.PublishedTitles(spellingTitles)
+	if err != nil {
+		return nil, err
+	}
+	texts = append(texts, titles...)
+	if tags, err := s.tagRepo.List(); err == nil {
+		for _, tag := range tags {
+			texts = append(texts, tag.Name)
+		}
+	}
+	if categories, err := s.categoryRepo.List(); err == nil {
+		for _, category := range categories {
+			texts = append(texts, category.Name)
+		}
+	}
+
+	words := make(map[string]int)
+	for _, text := range texts {
+		for _, word := range vocabularyWords(text) {
+			words[word]++
+		}
+	}
+	s.spelling.words = words
+	s.spelling.loadedAt = time.Now()
+	return words, nil
+}
+
+// closestWords returns up to spellingCandidates vocabulary words within the
+// edit distance allowed for word, closest and then most frequent first
+func closestWords(vocabulary map[string]int, word string) []string {
+	if len([]rune(word)) < 3 {
+		return nil
+	}
+	maxDistance := 1
+	if len([]rune(word)) > 5 {
+		maxDistance = 2
+	}
+
+	var candidates []spellingCandidate
+	for known, frequency := range vocabulary {
+		if diff := len([]rune(known)) - len([]rune(word)); diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		if distance := editDistance(word, known); distance <= maxDistance {
+			candidates = append(candidates, spellingCandidate{word: known, distance: distance, frequency: frequency})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		if candidates[i].frequency != candidates[j].frequency {
+			return candidates[i].frequency > candidates[j].frequency
+		}
+		return candidates[i].word < candidates[j].word
+	})
+
+	words := make([]string, 0, spellingCandidates)
+	for i := 0; i < len(candidates) && i < spellingCandidates; i++ {
+		words = append(words, candidates[i].word)
+	}
+	return words
+}
+
+// vocabularyWords splits text into lowercase words of letters and digits
+func vocabularyWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// editDistance is the Damerau-Levenshtein distance of a and b, counting
+// insertions, deletions, substitutions and swaps of adjacent characters
+func editDistance(a, b string) int {
+	x, y := []rune(a), []rune(b)
+	previous2 := make([]int, len(y)+1)
+	previous := make([]int, len(y)+1)
+	current := make([]int, len(y)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		current[0] = i
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				current[j] = min(current[j], previous2[j-2]+1)
+			}
+		}
+		previous2, previous, current = previous, current, previous2
+	}
+	return previous[len(y)]
+}
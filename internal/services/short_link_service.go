@@ -0,0 +1,157 @@
+package services
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+const (
+	// shortCodeAlphabet leaves out characters that are easily confused when a
+	// link is read out or typed, such as 0/O and 1/l/I
+	shortCodeAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	shortCodeLength   = 7
+	// shortCodeAttempts is how many codes are tried before giving up on collisions
+	shortCodeAttempts = 5
+)
+
+// ShortLinkService hands out short links to articles and follows them,
+// counting clicks and their referrers
+type ShortLinkService struct {
+	shortLinkRepo repositories.ShortLinkRepository
+	articleRepo   repositories.ArticleRepository
+	publicURL     string
+	articleURL    string
+}
+
+// NewShortLinkService creates a new short link service
+func NewShortLinkService(shortLinkRepo repositories.ShortLinkRepository, articleRepo repositories.ArticleRepository) *ShortLinkService {
+	return &ShortLinkService{
+		shortLinkRepo: shortLinkRepo,
+		articleRepo:   articleRepo,
+		articleURL:    "/articles/{slug}",
+	}
+}
+
+// SetPublicURL sets the base of the short URLs handed out
+func (s *ShortLinkService) SetPublicURL(publicURL string) {
+	s.publicURL = strings.TrimSuffix(publicURL, "/")
+}
+
+// SetArticleURL sets the public article URL short links redirect to; {slug}
+// is replaced with the article's slug
+func (s *ShortLinkService) SetArticleURL(articleURL string) {
+	s.articleURL = articleURL
+}
+
+// Get returns the short link of a published article, generating it on first use
+func (s *ShortLinkService) Get(articleID uint) (*models.ShortLink, error) {
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article not found")
+		}
+		return nil, err
+	}
+	if article.Status != models.StatusPublished {
+		return nil, notFoundError("article not found")
+	}
+
+	link, err := s.shortLinkRepo.GetByArticle(articleID)
+	if err == nil {
+		return s.withURL(link), nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get short link: %w", err)
+	}
+
+	for attempt := 0; attempt < shortCodeAttempts; attempt++ {
+		code, err := newShortCode()
+		if err != nil {
+			return nil, err
+		}
+		link = &models.ShortLink{ArticleID: articleID, Code: code}
+		err = s.shortLinkRepo.Create(link)
+		if err == nil {
+			return s.withURL(link), nil
+		}
+		if !errors.Is(err, repositories.ErrDuplicate) {
+			return nil, fmt.Errorf("failed to create short link: %w", err)
+		}
+		// A concurrent request may have linked the article first
+		if existing, err := s.shortLinkRepo.GetByArticle(articleID); err == nil {
+			return s.withURL(existing), nil
+		}
+	}
+	return nil, fmt.Errorf("failed to create short link: no free code after %d attempts", shortCodeAttempts)
+}
+
+// Resolve returns the URL of the published article behind code and records the
+// click with the host of referrer, the Referer header of the request
+func (s *ShortLinkService) Resolve(code, referrer string) (string, error) {
+	link, err := s.shortLinkRepo.GetByCode(code)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return "", notFoundError("short link not found")
+		}
+		return "", fmt.Errorf("failed to get short link: %w", err)
+	}
+
+	article, err := s.articleRepo.GetByID(link.ArticleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return "", notFoundError("short link not found")
+		}
+		return "", err
+	}
+	if article.Status != models.StatusPublished {
+		return "", notFoundError("short link not found")
+	}
+
+	// A lost click is not worth failing the redirect over
+	if err := s.shortLinkRepo.RecordClick(link.ID, referrerHost(referrer)); err != nil {
+		slog.Error("Failed to record short link click", "code", link.Code, "error", err)
+	}
+	return strings.ReplaceAll(s.articleURL, "{slug}", url.PathEscape(article.Slug)), nil
+}
+
+// withURL sets the short URL of link
+func (s *ShortLinkService) withURL(link *models.ShortLink) *models.ShortLink {
+	link.URL = s.publicURL + "/s/" + link.Code
+	return link
+}
+
+// newShortCode returns a random short code
+func newShortCode() (string, error) {
+	code := make([]byte, shortCodeLength)
+	max := big.NewInt(int64(len(shortCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate short code: %w", err)
+		}
+		code[i] = shortCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// referrerHost reduces a Referer header to its host, so clicks are grouped by
+// site and no paths or query strings of other sites are stored
+func referrerHost(referrer string) string {
+	parsed, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if len(host) > 255 {
+		return ""
+	}
+	return host
+}
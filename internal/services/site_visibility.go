@@ -0,0 +1,103 @@
+package services
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SiteVisibility is who can read the blog
+type SiteVisibility string
+
+const (
+	SiteVisibilityPublic SiteVisibility = "public" // anyone, and search engines are told about new articles
+	// SiteVisibilityUnlisted is readable by anyone with the address but asks
+	// search engines not to index it and does not notify them
+	SiteVisibilityUnlisted SiteVisibility = "unlisted"
+	// SiteVisibilityPrivate requires signing in for every API request other
+	// than signing in, for a soft launch or a private blog
+	SiteVisibilityPrivate SiteVisibility = "private"
+)
+
+// DefaultComingSoonMessage is shown to signed-out readers of a private blog when no message is set
+const DefaultComingSoonMessage = "This blog is coming soon. Sign in to read it."
+
+// SiteVisibilityState describes who can read the blog
+type SiteVisibilityState struct {
+	Visibility SiteVisibility `json:"visibility"`
+	Message    string         `json:"message"`              // shown to signed-out readers of a private blog
+	UpdatedAt  *time.Time     `json:"updated_at,omitempty"` // last change through the admin API
+}
+
+// UpdateSiteVisibilityRequest changes the site visibility; omitted fields are left unchanged
+type UpdateSiteVisibilityRequest struct {
+	Visibility *string `json:"visibility,omitempty" validate:"omitempty,oneof=public unlisted private"`
+	Message    *string `json:"message,omitempty" validate:"omitempty,max=500"`
+}
+
+// SiteVisibilityService holds the visibility of the blog on this instance. It
+// starts from the configuration; changes made by admins last until the next restart.
+type SiteVisibilityService struct {
+	mu    sync.RWMutex
+	state SiteVisibilityState
+}
+
+// NewSiteVisibilityService creates a site visibility service in the given initial state
+func NewSiteVisibilityService(initial SiteVisibilityState) *SiteVisibilityService {
+	if initial.Visibility == "" {
+		initial.Visibility = SiteVisibilityPublic
+	}
+	if strings.TrimSpace(initial.Message) == "" {
+		initial.Message = DefaultComingSoonMessage
+	}
+	return &SiteVisibilityService{state: initial}
+}
+
+// State returns the current site visibility
+func (s *SiteVisibilityService) State() SiteVisibilityState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state
+}
+
+// Listed reports whether search engines may index the blog and be told about
+// new articles
+func (s *SiteVisibilityService) Listed() bool {
+	return s.State().Visibility == SiteVisibilityPublic
+}
+
+// Update changes the site visibility on behalf of the admin adminID
+func (s *SiteVisibilityService) Update(adminID uint, req *UpdateSiteVisibilityRequest) (SiteVisibilityState, error) {
+	if req == nil {
+		return SiteVisibilityState{}, validationError("site visibility request is required")
+	}
+	if req.Visibility != nil {
+		switch SiteVisibility(*req.Visibility) {
+		case SiteVisibilityPublic, SiteVisibilityUnlisted, SiteVisibilityPrivate:
+		default:
+			return SiteVisibilityState{}, validationError("visibility must be public, unlisted or private")
+		}
+	}
+	if req.Message != nil && len([]rune(*req.Message)) > 500 {
+		return SiteVisibilityState{}, validationError("message must be less than 500 characters")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.Visibility != nil {
+		s.state.Visibility = SiteVisibility(*req.Visibility)
+	}
+	if req.Message != nil {
+		s.state.Message = strings.TrimSpace(*req.Message)
+		if s.state.Message == "" {
+			s.state.Message = DefaultComingSoonMessage
+		}
+	}
+	now := time.Now()
+	s.state.UpdatedAt = &now
+
+	slog.Info("Site visibility updated", "user_id", adminID, "visibility", s.state.Visibility)
+	return s.state, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSLOWindows are the windows burn rates are reported over when none are
+// configured: a fast 5 minute one and the hour and 6 hours it is confirmed over
+var DefaultSLOWindows = []time.Duration{5 * time.Minute, time.Hour, 6 * time.Hour}
+
+// DefaultSLOAlertBurnRate is the burn rate alerting when none is configured. At
+// 14.4 a 30 day error budget is used up in about 2 days.
+const DefaultSLOAlertBurnRate = 14.4
+
+// SLObjective is the objective of the API routes under a path prefix
+type SLObjective struct {
+	Name             string        `json:"name"`
+	Prefix           string        `json:"prefix"`                      // path after /api, e.g. "/articles"
+	Availability     float64       `json:"availability"`                // target share of requests not failing with a 5xx status
+	LatencyThreshold time.Duration `json:"latency_threshold,omitempty"` // requests taking longer count as slow, 0 disables the latency objective
+	LatencyTarget    float64       `json:"latency_target,omitempty"`    // target share of requests faster than the threshold
+}
+
+// SLOBurnRate is how fast a group spends its error budgets over one window. A
+// burn rate of 1 uses the budget up exactly by the end of the SLO period.
+type SLOBurnRate struct {
+	Window          string  `json:"window"`
+	Requests        int64   `json:"requests"`
+	Errors          int64   `json:"errors"`
+	Slow            int64   `json:"slow"`
+	ErrorBurnRate   float64 `json:"error_burn_rate"`
+	LatencyBurnRate float64 `json:"latency_burn_rate"`
+}
+
+// SLOStatus reports the burn rates of an objective over every window. Alerting
+// is set when a budget burns faster than the alert rate over all windows, so a
+// short spike alone does not page anyone.
+type SLOStatus struct {
+	SLObjective
+	BurnRates []SLOBurnRate `json:"burn_rates"`
+	Alerting  bool          `json:"alerting"`
+}
+
+// sloBucket counts the requests of a group during one minute
+type sloBucket struct {
+	minute   int64
+	requests int64
+	errors   int64
+	slow     int64
+}
+
+// sloGroup is an objective with the per-minute counts of its longest window
+type sloGroup struct {
+	objective SLObjective
+	buckets   []sloBucket // ring indexed by minute
+}
+
+// SLOService keeps in-memory request counts of the route groups with service
+// level objectives and reports how fast they burn their error budgets. Counts
+// are per instance and start over on restart.
+type SLOService struct {
+	mu        sync.Mutex
+	groups    []*sloGroup
+	windows   []time.Duration
+	alertRate float64
+}
+
+// NewSLOService creates an SLO service for objectives, reporting burn rates
+// over windows and alerting above alertRate; empty values use the defaults
+func NewSLOService(objectives []SLObjective, windows []time.Duration, alertRate float64) *SLOService {
+	if len(windows) == 0 {
+		windows = DefaultSLOWindows
+	}
+	windows = append([]time.Duration(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	if alertRate <= 0 {
+		alertRate = DefaultSLOAlertBurnRate
+	}
+
+	minutes := int(windows[len(windows)-1] / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	groups := make([]*sloGroup, len(objectives))
+	for i, objective := range objectives {
+		groups[i] = &sloGroup{objective: objective, buckets: make([]sloBucket, minutes)}
+	}
+	// The longest matching prefix wins
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].objective.Prefix) > len(groups[j].objective.Prefix)
+	})
+
+	return &SLOService{groups: groups, windows: windows, alertRate: alertRate}
+}
+
+// Observe counts a request to route, the path after /api, that ended with
+// status after latency
+func (s *SLOService) Observe(route string, status int, latency time.Duration, at time.Time) {
+	group := s.group(route)
+	if group == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	minute := at.Unix() / 60
+	bucket := &group.buckets[minute%int64(len(group.buckets))]
+	if bucket.minute != minute {
+		*bucket = sloBucket{minute: minute}
+	}
+	bucket.requests++
+	if status >= 500 {
+		bucket.errors++
+	}
+	if threshold := group.objective.LatencyThreshold; threshold > 0 && latency > threshold {
+		bucket.slow++
+	}
+}
+
+// Report returns the status of every objective at now
+func (s *SLOService) Report(now time.Time) []SLOStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := now.Unix() / 60
+	statuses := make([]SLOStatus, 0, len(s.groups))
+	for _, group := range s.groups {
+		status := SLOStatus{SLObjective: group.objective, Alerting: true}
+		for _, window := range s.windows {
+			rate := group.burnRate(current, window)
+			status.BurnRates = append(status.BurnRates, rate)
+			if rate.ErrorBurnRate < s.alertRate && rate.LatencyBurnRate < s.alertRate {
+				status.Alerting = false
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// group returns the group of the longest prefix matching route, or nil
+func (s *SLOService) group(route string) *sloGroup {
+	for _, group := range s.groups {
+		prefix := group.objective.Prefix
+		if route == prefix || strings.HasPrefix(route, strings.TrimSuffix(prefix, "/")+"/") {
+			return group
+		}
+	}
+	return nil
+}
+
+// burnRate sums the buckets of the minutes within window up to current
+func (g *sloGroup) burnRate(current int64, window time.Duration) SLOBurnRate {
+	rate := SLOBurnRate{Window: window.String()}
+	minutes := int64(window / time.Minute)
+	for _, bucket := range g.buckets {
+		if bucket.requests > 0 && bucket.minute > current-minutes && bucket.minute <= current {
+			rate.Requests += bucket.requests
+			rate.Errors += bucket.errors
+			rate.Slow += bucket.slow
+		}
+	}
+	if rate.Requests == 0 {
+		return rate
+	}
+
+	if budget := 1 - g.objective.Availability; budget > 0 {
+		rate.ErrorBurnRate = float64(rate.Errors) / float64(rate.Requests) / budget
+	}
+	if budget := 1 - g.objective.LatencyTarget; g.objective.LatencyThreshold > 0 && budget > 0 {
+		rate.LatencyBurnRate = float64(rate.Slow) / float64(rate.Requests) / budget
+	}
+	return rate
+}
@@ -1,17 +1,25 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
+	"sync/atomic"
 	"time"
 )
 
 // StatisticsService handles article statistics and analytics
 type StatisticsService struct {
-	articleRepo repositories.ArticleRepository
-	likeRepo    repositories.LikeRepository
-	commentRepo repositories.CommentRepository
+	articleRepo   repositories.ArticleRepository
+	likeRepo      repositories.LikeRepository
+	commentRepo   repositories.CommentRepository
+	shortLinkRepo repositories.ShortLinkRepository
+	viewRepo      repositories.ArticleViewRepository
+
+	rankingRepo    repositories.ArticleRankingRepository // nil scores articles on every request
+	trendingWindow time.Duration
+	rankingsReady  atomic.Bool
 }
 
 // NewStatisticsService creates a new statistics service
@@ -27,16 +35,41 @@ func NewStatisticsService(
 	}
 }
 
+// SetShortLinkRepository enables short link click statistics
+func (s *StatisticsService) SetShortLinkRepository(shortLinkRepo repositories.ShortLinkRepository) {
+	s.shortLinkRepo = shortLinkRepo
+}
+
+// RecountStatistics recomputes the like and comment counters of every article
+// from the stored likes and comments, correcting any drift, and returns how many
+// articles were updated
+func (s *StatisticsService) RecountStatistics() (int64, error) {
+	updated, err := s.articleRepo.RecountStatistics()
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount article statistics: %w", err)
+	}
+	return updated, nil
+}
+
 // ArticleStats represents comprehensive article statistics
 type ArticleStats struct {
-	ArticleID    uint      `json:"article_id"`
-	Title        string    `json:"title"`
-	Slug         string    `json:"slug"`
-	ViewCount    uint      `json:"view_count"`
-	LikeCount    uint      `json:"like_count"`
-	CommentCount uint      `json:"comment_count"`
-	CreatedAt    time.Time `json:"created_at"`
-	PublishedAt  *time.Time `json:"published_at"`
+	ArticleID       uint       `json:"article_id"`
+	Title           string     `json:"title"`
+	Slug            string     `json:"slug"`
+	ViewCount       uint       `json:"view_count"`
+	LikeCount       uint       `json:"like_count"`
+	CommentCount    uint       `json:"comment_count"`
+	ShortLinkClicks uint       `json:"short_link_clicks"`
+	CreatedAt       time.Time  `json:"created_at"`
+	PublishedAt     *time.Time `json:"published_at"`
+}
+
+// ShortLinkStats represents the clicks on an article's short link
+type ShortLinkStats struct {
+	ArticleID uint                   `json:"article_id"`
+	Code      string                 `json:"code"`
+	Clicks    uint                   `json:"clicks"`
+	Referrers []models.ReferrerCount `json:"referrers"` // most clicks first; "" counts clicks without a referrer
 }
 
 // PopularArticle represents popular article data
@@ -93,7 +126,7 @@ func (s *StatisticsService) GetArticleStats(articleID uint) (*ArticleStats, erro
 		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
 
-	return &ArticleStats{
+	stats := &ArticleStats{
 		ArticleID:    article.ID,
 		Title:        article.Title,
 		Slug:         article.Slug,
@@ -102,6 +135,43 @@ func (s *StatisticsService) GetArticleStats(articleID uint) (*ArticleStats, erro
 		CommentCount: article.CommentCount,
 		CreatedAt:    article.CreatedAt,
 		PublishedAt:  article.PublishedAt,
+	}
+	if s.shortLinkRepo != nil {
+		link, err := s.shortLinkRepo.GetByArticle(articleID)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+			return nil, fmt.Errorf("failed to get short link: %w", err)
+		}
+		if link != nil {
+			stats.ShortLinkClicks = link.ClickCount
+		}
+	}
+	return stats, nil
+}
+
+// GetShortLinkStats retrieves the clicks on an article's short link with its
+// top referrers
+func (s *StatisticsService) GetShortLinkStats(articleID uint, limit int) (*ShortLinkStats, error) {
+	if s.shortLinkRepo == nil {
+		return nil, notFoundError("short links are not enabled")
+	}
+
+	link, err := s.shortLinkRepo.GetByArticle(articleID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("article has no short link")
+		}
+		return nil, fmt.Errorf("failed to get short link: %w", err)
+	}
+
+	referrers, err := s.shortLinkRepo.ReferrerCounts(link.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count referrers: %w", err)
+	}
+	return &ShortLinkStats{
+		ArticleID: articleID,
+		Code:      link.Code,
+		Clicks:    link.ClickCount,
+		Referrers: referrers,
 	}, nil
 }
 
@@ -134,6 +204,9 @@ func (s *StatisticsService) GetPopularArticles(limit int) ([]*PopularArticle, er
 	if limit <= 0 {
 		limit = 10
 	}
+	if s.rankingRepo != nil {
+		return s.rankedPopularArticles(limit)
+	}
 
 	// Get published articles with statistics
 	filters := map[string]interface{}{
@@ -189,6 +262,9 @@ func (s *StatisticsService) GetTrendingArticles(limit int, days int) ([]*Trendin
 	if days <= 0 {
 		days = 7 // Default to last 7 days
 	}
+	if s.rankingRepo != nil {
+		return s.rankedTrendingArticles(limit, days)
+	}
 
 	// Get recent published articles
 	filters := map[string]interface{}{
@@ -246,23 +322,41 @@ func (s *StatisticsService) GetTrendingArticles(limit int, days int) ([]*Trendin
 
 // GetAuthorSummaryStats retrieves aggregated statistics for an author
 func (s *StatisticsService) GetAuthorSummaryStats(authorID uint) (*AuthorStats, error) {
-	articles, err := s.articleRepo.GetByAuthorID(authorID, 1000, 0) // Get all articles
+	totals, err := s.articleRepo.GetAuthorTotals(authorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get author articles: %w", err)
+		return nil, fmt.Errorf("failed to get author totals: %w", err)
 	}
 
-	stats := &AuthorStats{
-		AuthorID: authorID,
+	return &AuthorStats{
+		AuthorID:      authorID,
+		ArticleCount:  uint(totals.ArticleCount),
+		TotalViews:    uint(totals.TotalViews),
+		TotalLikes:    uint(totals.TotalLikes),
+		TotalComments: uint(totals.TotalComments),
+	}, nil
+}
+
+// GetAuthorLeaderboard ranks the top authors by one of their published article
+// totals: score (the popularity weighting of GetPopularArticles), views, likes,
+// comments or articles
+func (s *StatisticsService) GetAuthorLeaderboard(metric string, limit int) ([]repositories.AuthorRank, error) {
+	if limit <= 0 {
+		limit = 10
 	}
 
-	for _, article := range articles {
-		stats.ArticleCount++
-		stats.TotalViews += article.ViewCount
-		stats.TotalLikes += article.LikeCount
-		stats.TotalComments += article.CommentCount
+	by := repositories.AuthorMetric(metric)
+	switch by {
+	case repositories.AuthorMetricScore, repositories.AuthorMetricViews, repositories.AuthorMetricLikes,
+		repositories.AuthorMetricComments, repositories.AuthorMetricArticles:
+	default:
+		return nil, validationError("metric must be one of: score, views, likes, comments, articles")
 	}
 
-	return stats, nil
+	ranks, err := s.articleRepo.GetAuthorLeaderboard(by, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get author leaderboard: %w", err)
+	}
+	return ranks, nil
 }
 
 // GetPeriodStats retrieves statistics aggregated by time periods
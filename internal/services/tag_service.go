@@ -8,17 +8,31 @@ import (
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
 	"go-blog/internal/utils"
-
-	"gorm.io/gorm"
 )
 
 type TagService struct {
-	tagRepo repositories.TagRepository
+	tagRepo       repositories.TagRepository
+	aliasRepo     repositories.TagAliasRepository
+	protectedTags map[string]bool
 }
 
 // CreateTagRequest represents tag creation data
 type CreateTagRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=50"`
+	Name string `json:"name" validate:"required,min=1,max=50,excludes=:"`
+}
+
+// UpdateTagDetailsRequest changes the landing page text of a tag; omitted
+// fields are left unchanged
+type UpdateTagDetailsRequest struct {
+	Description     *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+	MetaTitle       *string `json:"meta_title,omitempty" validate:"omitempty,max=100"`
+	MetaDescription *string `json:"meta_description,omitempty" validate:"omitempty,max=300"`
+}
+
+// CreateTagAliasRequest represents tag alias creation data
+type CreateTagAliasRequest struct {
+	Alias string `json:"alias" validate:"required,min=1,max=50"`
+	TagID uint   `json:"tag_id" validate:"required,min=1"`
 }
 
 // TagWithStats represents a tag with usage statistics
@@ -27,6 +41,14 @@ type TagWithStats struct {
 	ArticleCount int64 `json:"article_count"`
 }
 
+// OrphanTagReport describes the result of an orphan tag cleanup run
+type OrphanTagReport struct {
+	Orphans   []models.Tag `json:"orphans"`
+	Protected []models.Tag `json:"protected"`
+	Deleted   int          `json:"deleted"`
+	DryRun    bool         `json:"dry_run"`
+}
+
 // NewTagService creates a new tag service
 func NewTagService(tagRepo repositories.TagRepository) *TagService {
 	return &TagService{
@@ -34,20 +56,46 @@ func NewTagService(tagRepo repositories.TagRepository) *TagService {
 	}
 }
 
+// SetAliasRepository sets the tag alias repository (for dependency injection)
+func (s *TagService) SetAliasRepository(aliasRepo repositories.TagAliasRepository) {
+	s.aliasRepo = aliasRepo
+}
+
+// SetProtectedTags sets tag names that orphan cleanup must never delete
+func (s *TagService) SetProtectedTags(names []string) {
+	s.protectedTags = make(map[string]bool, len(names))
+	for _, name := range names {
+		if normalized := utils.NormalizeTagName(name); normalized != "" {
+			s.protectedTags[normalized] = true
+		}
+	}
+}
+
+// withRepositories returns a copy of the service using the given repositories,
+// typically ones bound to a transaction
+func (s *TagService) withRepositories(tagRepo repositories.TagRepository, aliasRepo repositories.TagAliasRepository) *TagService {
+	bound := *s
+	bound.tagRepo = tagRepo
+	if s.aliasRepo != nil {
+		bound.aliasRepo = aliasRepo
+	}
+	return &bound
+}
+
 // Create creates a new tag
 func (s *TagService) Create(req *CreateTagRequest) (*models.Tag, error) {
 	if err := s.validateCreateRequest(req); err != nil {
 		return nil, err
 	}
 
-	tagName := strings.TrimSpace(req.Name)
-	
-	// Check if tag already exists
-	existingTag, err := s.tagRepo.GetByName(tagName)
+	tagName := strings.Join(strings.Fields(req.Name), " ")
+
+	// Check if tag already exists (case-insensitive, including aliases)
+	_, err := s.ResolveByName(tagName)
 	if err == nil {
-		return nil, fmt.Errorf("tag with name '%s' already exists", tagName)
+		return nil, conflictError("tag with name '%s' already exists", tagName)
 	}
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
+	if !errors.Is(err, repositories.ErrNotFound) {
 		return nil, fmt.Errorf("error checking existing tag: %w", err)
 	}
 
@@ -72,7 +120,7 @@ func (s *TagService) Create(req *CreateTagRequest) (*models.Tag, error) {
 // GetByID retrieves a tag by ID
 func (s *TagService) GetByID(id uint) (*models.Tag, error) {
 	if id == 0 {
-		return nil, errors.New("tag ID is required")
+		return nil, validationError("tag ID is required")
 	}
 	return s.tagRepo.GetByID(id)
 }
@@ -80,15 +128,53 @@ func (s *TagService) GetByID(id uint) (*models.Tag, error) {
 // GetBySlug retrieves a tag by slug
 func (s *TagService) GetBySlug(slug string) (*models.Tag, error) {
 	if strings.TrimSpace(slug) == "" {
-		return nil, errors.New("slug cannot be empty")
+		return nil, validationError("slug cannot be empty")
+	}
+
+	tag, err := s.tagRepo.GetBySlug(slug)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("tag not found")
+		}
+		return nil, err
 	}
-	return s.tagRepo.GetBySlug(slug)
+	return tag, nil
+}
+
+// UpdateDetails changes the description and search engine text of a tag
+func (s *TagService) UpdateDetails(id uint, req *UpdateTagDetailsRequest) (*models.Tag, error) {
+	if req == nil {
+		return nil, validationError("update request is required")
+	}
+
+	tag, err := s.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("tag not found")
+		}
+		return nil, err
+	}
+
+	if req.Description != nil {
+		tag.Description = strings.TrimSpace(*req.Description)
+	}
+	if req.MetaTitle != nil {
+		tag.MetaTitle = strings.Join(strings.Fields(*req.MetaTitle), " ")
+	}
+	if req.MetaDescription != nil {
+		tag.MetaDescription = strings.Join(strings.Fields(*req.MetaDescription), " ")
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		return nil, fmt.Errorf("failed to update tag: %w", err)
+	}
+	return tag, nil
 }
 
 // GetByName retrieves a tag by name
 func (s *TagService) GetByName(name string) (*models.Tag, error) {
 	if strings.TrimSpace(name) == "" {
-		return nil, errors.New("name cannot be empty")
+		return nil, validationError("name cannot be empty")
 	}
 	return s.tagRepo.GetByName(name)
 }
@@ -98,20 +184,50 @@ func (s *TagService) List() ([]models.Tag, error) {
 	return s.tagRepo.List()
 }
 
+// ResolveByName resolves a tag name or alias to its canonical tag.
+// Matching is case-insensitive; repositories.ErrNotFound is returned when nothing matches.
+func (s *TagService) ResolveByName(name string) (*models.Tag, error) {
+	normalized := utils.NormalizeTagName(name)
+	if normalized == "" {
+		return nil, validationError("tag name cannot be empty")
+	}
+
+	tag, err := s.tagRepo.GetByNormalizedName(normalized)
+	if err == nil {
+		return tag, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, err
+	}
+
+	if s.aliasRepo == nil {
+		return nil, repositories.ErrNotFound
+	}
+
+	alias, err := s.aliasRepo.GetByAlias(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if alias.Tag != nil {
+		return alias.Tag, nil
+	}
+	return s.tagRepo.GetByID(alias.TagID)
+}
+
 // FindOrCreateByName finds an existing tag by name or creates a new one
 func (s *TagService) FindOrCreateByName(name string) (*models.Tag, error) {
 	tagName := strings.TrimSpace(name)
 	if tagName == "" {
-		return nil, errors.New("tag name cannot be empty")
+		return nil, validationError("tag name cannot be empty")
 	}
 
-	// Try to find existing tag
-	tag, err := s.tagRepo.GetByName(tagName)
+	// Try to find existing tag by name or alias
+	tag, err := s.ResolveByName(tagName)
 	if err == nil {
 		return tag, nil
 	}
 
-	if !errors.Is(err, gorm.ErrRecordNotFound) {
+	if !errors.Is(err, repositories.ErrNotFound) {
 		return nil, fmt.Errorf("error checking existing tag: %w", err)
 	}
 
@@ -123,13 +239,13 @@ func (s *TagService) FindOrCreateByName(name string) (*models.Tag, error) {
 // GetArticles retrieves articles associated with a tag
 func (s *TagService) GetArticles(tagID uint, page, limit int) ([]models.Article, int64, error) {
 	if tagID == 0 {
-		return nil, 0, errors.New("tag ID is required")
+		return nil, 0, validationError("tag ID is required")
 	}
 
 	// Verify tag exists
 	_, err := s.tagRepo.GetByID(tagID)
 	if err != nil {
-		return nil, 0, errors.New("tag not found")
+		return nil, 0, notFoundError("tag not found")
 	}
 
 	if page < 1 {
@@ -154,10 +270,11 @@ func (s *TagService) GetArticlesBySlug(slug string, page, limit int) ([]models.A
 }
 
 // ProcessTagNames processes a list of tag names and returns tag models
-// This method finds existing tags or creates new ones as needed
+// This method finds existing tags (by name or alias) or creates new ones as needed
 func (s *TagService) ProcessTagNames(tagNames []string) ([]models.Tag, error) {
 	var tags []models.Tag
 	processedNames := make(map[string]bool) // To avoid duplicates
+	processedTags := make(map[uint]bool)    // Aliases may resolve to the same tag
 
 	for _, tagName := range tagNames {
 		tagName = strings.TrimSpace(tagName)
@@ -165,70 +282,200 @@ func (s *TagService) ProcessTagNames(tagNames []string) ([]models.Tag, error) {
 			continue
 		}
 
-		// Skip if already processed (avoid duplicates)
-		if processedNames[tagName] {
+		// Skip if already processed (avoid duplicates, case-insensitive)
+		normalized := utils.NormalizeTagName(tagName)
+		if processedNames[normalized] {
 			continue
 		}
-		processedNames[tagName] = true
+		processedNames[normalized] = true
 
 		tag, err := s.FindOrCreateByName(tagName)
 		if err != nil {
 			return nil, fmt.Errorf("failed to process tag '%s': %w", tagName, err)
 		}
 
+		if processedTags[tag.ID] {
+			continue
+		}
+		processedTags[tag.ID] = true
+
 		tags = append(tags, *tag)
 	}
 
 	return tags, nil
 }
 
-// GetPopularTags retrieves tags ordered by usage (article count)
+// GetPopularTags retrieves tags ordered by usage (article count) for tag clouds
 func (s *TagService) GetPopularTags(limit int) ([]TagWithStats, error) {
 	if limit < 1 || limit > 100 {
 		limit = 20
 	}
 
-	tags, err := s.tagRepo.List()
+	tags, err := s.tagRepo.ListWithCounts(limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags: %w", err)
 	}
 
-	// Get article counts for each tag
-	var tagsWithStats []TagWithStats
-	for _, tag := range tags {
-		_, count, err := s.tagRepo.GetArticles(tag.ID, 0, 1) // Just get count
-		if err != nil {
-			return nil, fmt.Errorf("failed to get article count for tag %d: %w", tag.ID, err)
+	return toTagsWithStats(tags), nil
+}
+
+// Autocomplete returns tags whose names start with the given prefix, most used first
+func (s *TagService) Autocomplete(prefix string, limit int) ([]TagWithStats, error) {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		return []TagWithStats{}, nil
+	}
+	if len(prefix) > 50 {
+		return nil, validationError("query must be less than 50 characters")
+	}
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	tags, err := s.tagRepo.Autocomplete(prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to autocomplete tags: %w", err)
+	}
+
+	return toTagsWithStats(tags), nil
+}
+
+// CleanupOrphanTags finds tags with no article associations and, unless dryRun
+// is set, deletes them together with their aliases. Protected tags are reported
+// separately and always kept.
+func (s *TagService) CleanupOrphanTags(dryRun bool) (*OrphanTagReport, error) {
+	orphans, err := s.tagRepo.ListOrphans()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphan tags: %w", err)
+	}
+
+	report := &OrphanTagReport{
+		Orphans:   []models.Tag{},
+		Protected: []models.Tag{},
+		DryRun:    dryRun,
+	}
+
+	for _, tag := range orphans {
+		if s.protectedTags[utils.NormalizeTagName(tag.Name)] {
+			report.Protected = append(report.Protected, tag)
+			continue
 		}
+		report.Orphans = append(report.Orphans, tag)
+	}
 
-		tagsWithStats = append(tagsWithStats, TagWithStats{
-			Tag:          tag,
-			ArticleCount: count,
-		})
+	if dryRun {
+		return report, nil
 	}
 
-	// Sort by article count (simple bubble sort for small datasets)
-	for i := 0; i < len(tagsWithStats)-1; i++ {
-		for j := 0; j < len(tagsWithStats)-i-1; j++ {
-			if tagsWithStats[j].ArticleCount < tagsWithStats[j+1].ArticleCount {
-				tagsWithStats[j], tagsWithStats[j+1] = tagsWithStats[j+1], tagsWithStats[j]
+	for _, tag := range report.Orphans {
+		if s.aliasRepo != nil {
+			if err := s.aliasRepo.DeleteByTag(tag.ID); err != nil {
+				return report, fmt.Errorf("failed to delete aliases for tag %d: %w", tag.ID, err)
 			}
 		}
+		if err := s.tagRepo.Delete(tag.ID); err != nil {
+			return report, fmt.Errorf("failed to delete tag %d: %w", tag.ID, err)
+		}
+		report.Deleted++
+	}
+
+	return report, nil
+}
+
+// toTagsWithStats converts repository tag counts into service results
+func toTagsWithStats(tags []repositories.TagCount) []TagWithStats {
+	result := make([]TagWithStats, len(tags))
+	for i, tag := range tags {
+		result[i] = TagWithStats{
+			Tag:          tag.Tag,
+			ArticleCount: tag.ArticleCount,
+		}
+	}
+	return result
+}
+
+// CreateAlias registers an alternative spelling for an existing tag
+func (s *TagService) CreateAlias(req *CreateTagAliasRequest) (*models.TagAlias, error) {
+	if s.aliasRepo == nil {
+		return nil, errors.New("tag alias repository not available")
+	}
+	if req == nil {
+		return nil, validationError("create request is required")
+	}
+
+	normalized := utils.NormalizeTagName(req.Alias)
+	if normalized == "" {
+		return nil, validationError("alias is required")
+	}
+	if len(normalized) > 50 {
+		return nil, validationError("alias must be less than 50 characters")
+	}
+
+	tag, err := s.tagRepo.GetByID(req.TagID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("tag not found")
+		}
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	// An alias must not shadow an existing tag name or another alias
+	if _, err := s.tagRepo.GetByNormalizedName(normalized); err == nil {
+		return nil, conflictError("alias '%s' conflicts with an existing tag", normalized)
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("error checking existing tag: %w", err)
 	}
+	if _, err := s.aliasRepo.GetByAlias(normalized); err == nil {
+		return nil, conflictError("alias '%s' already exists", normalized)
+	} else if !errors.Is(err, repositories.ErrNotFound) {
+		return nil, fmt.Errorf("error checking existing alias: %w", err)
+	}
+
+	alias := &models.TagAlias{
+		Alias: normalized,
+		TagID: tag.ID,
+		Tag:   tag,
+	}
+
+	if err := s.aliasRepo.Create(alias); err != nil {
+		return nil, fmt.Errorf("failed to create alias: %w", err)
+	}
+
+	return alias, nil
+}
 
-	// Limit results
-	if len(tagsWithStats) > limit {
-		tagsWithStats = tagsWithStats[:limit]
+// ListAliases retrieves tag aliases, optionally restricted to a single tag
+func (s *TagService) ListAliases(tagID uint) ([]models.TagAlias, error) {
+	if s.aliasRepo == nil {
+		return nil, errors.New("tag alias repository not available")
+	}
+	if tagID > 0 {
+		return s.aliasRepo.ListByTag(tagID)
+	}
+	return s.aliasRepo.List()
+}
+
+// DeleteAlias removes a tag alias
+func (s *TagService) DeleteAlias(id uint) error {
+	if s.aliasRepo == nil {
+		return errors.New("tag alias repository not available")
+	}
+
+	if _, err := s.aliasRepo.GetByID(id); err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return notFoundError("alias not found")
+		}
+		return fmt.Errorf("failed to get alias: %w", err)
 	}
 
-	return tagsWithStats, nil
+	return s.aliasRepo.Delete(id)
 }
 
 // generateUniqueSlug generates a unique slug for a tag
 func (s *TagService) generateUniqueSlug(name string) (string, error) {
 	baseSlug := utils.GenerateSlug(name)
 	if baseSlug == "" {
-		return "", errors.New("cannot generate slug from name")
+		return "", validationError("cannot generate slug from name")
 	}
 
 	slug := baseSlug
@@ -238,7 +485,7 @@ func (s *TagService) generateUniqueSlug(name string) (string, error) {
 	for {
 		_, err := s.tagRepo.GetBySlug(slug)
 		if err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
+			if errors.Is(err, repositories.ErrNotFound) {
 				// Slug is available
 				break
 			}
@@ -261,15 +508,15 @@ func (s *TagService) generateUniqueSlug(name string) (string, error) {
 // validateCreateRequest validates tag creation request
 func (s *TagService) validateCreateRequest(req *CreateTagRequest) error {
 	if req == nil {
-		return errors.New("create request is required")
+		return validationError("create request is required")
 	}
 
 	if strings.TrimSpace(req.Name) == "" {
-		return errors.New("tag name is required")
+		return validationError("tag name is required")
 	}
 
 	if len(req.Name) > 50 {
-		return errors.New("tag name must be less than 50 characters")
+		return validationError("tag name must be less than 50 characters")
 	}
 
 	return nil
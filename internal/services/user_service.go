@@ -2,25 +2,48 @@ package services
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
 	"go-blog/internal/models"
 	"go-blog/internal/repositories"
-
-	"gorm.io/gorm"
+	"go-blog/internal/storage"
 )
 
 type UserService struct {
-	userRepo    repositories.UserRepository
-	articleRepo repositories.ArticleRepository
+	userRepo        repositories.UserRepository
+	articleRepo     repositories.ArticleRepository
+	likeRepo        repositories.LikeRepository
+	settingsService *UserSettingsService
+	storage         storage.Storage
 }
 
-// UpdateUserRequest represents user profile update data
+// UpdateUserRequest represents user profile update data. Twitter and GitHub accept
+// a handle, "@handle" or a profile URL. Email may only repeat the current address;
+// changes go through AuthService.RequestEmailChange.
 type UpdateUserRequest struct {
 	Username  string `json:"username,omitempty" validate:"omitempty,min=3,max=50"`
+	Handle    string `json:"handle,omitempty" validate:"omitempty,handle"`
 	Email     string `json:"email,omitempty" validate:"omitempty,email,max=100"`
 	AvatarURL string `json:"avatar_url,omitempty" validate:"omitempty,url,max=255"`
 	Bio       string `json:"bio,omitempty" validate:"omitempty,max=500"`
+	Website   string `json:"website,omitempty" validate:"omitempty,url,max=255"`
+	Twitter   string `json:"twitter,omitempty" validate:"omitempty,max=255"`
+	GitHub    string `json:"github,omitempty" validate:"omitempty,max=255"`
+	Location  string `json:"location,omitempty" validate:"omitempty,max=100"`
+}
+
+// SetMembershipRequest changes the membership tier of a user
+type SetMembershipRequest struct {
+	Tier string `json:"tier" validate:"required,oneof=free member premium"`
+}
+
+// AuthorPage is an author's public profile with their published articles
+type AuthorPage struct {
+	Profile    models.AuthorProfile      `json:"profile"`
+	Stats      repositories.AuthorTotals `json:"stats"`
+	LikesGiven int64                     `json:"likes_given"`
+	Articles   []models.ArticleSummary   `json:"articles"`
 }
 
 // NewUserService creates a new user service
@@ -35,11 +58,53 @@ func (s *UserService) SetArticleRepository(articleRepo repositories.ArticleRepos
 	s.articleRepo = articleRepo
 }
 
+// SetLikeRepository sets the like repository counting the likes users gave
+func (s *UserService) SetLikeRepository(likeRepo repositories.LikeRepository) {
+	s.likeRepo = likeRepo
+}
+
+// SetSettingsService sets the settings service used to enforce profile visibility
+func (s *UserService) SetSettingsService(settingsService *UserSettingsService) {
+	s.settingsService = settingsService
+}
+
+// SetStorage sets the storage uploaded avatars are written to
+func (s *UserService) SetStorage(store storage.Storage) {
+	s.storage = store
+}
+
 // GetByID retrieves a user by ID
 func (s *UserService) GetByID(id uint) (*models.User, error) {
 	return s.userRepo.GetByID(id)
 }
 
+// GetProfile retrieves a user for viewerID (0 when anonymous), honoring the user's
+// profile visibility. Private profiles are reported as not found to other viewers.
+func (s *UserService) GetProfile(viewerID, id uint) (*models.User, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, err
+	}
+
+	if s.settingsService != nil {
+		settings, err := s.settingsService.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if !settings.CanView(viewerID) {
+			if settings.ProfileVisibility == models.ProfileVisibilityMembers {
+				return nil, unauthorizedError("sign in to view this profile")
+			}
+			return nil, notFoundError("user not found")
+		}
+	}
+
+	return user, nil
+}
+
 // Update updates a user
 func (s *UserService) Update(user *models.User) error {
 	return s.userRepo.Update(user)
@@ -55,31 +120,39 @@ func (s *UserService) UpdateProfile(userID uint, req *UpdateUserRequest) (*model
 	// Get current user
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, notFoundError("user not found")
 	}
 
 	// Check if username is being changed and if it's available
 	if req.Username != "" && req.Username != user.Username {
 		existingUser, err := s.userRepo.GetByUsername(req.Username)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
 			return nil, err
 		}
 		if existingUser != nil {
-			return nil, errors.New("username is already taken")
+			return nil, conflictError("username is already taken")
 		}
 		user.Username = req.Username
 	}
 
-	// Check if email is being changed and if it's available
-	if req.Email != "" && req.Email != user.Email {
-		existingUser, err := s.userRepo.GetByEmail(req.Email)
-		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	// Check if handle is being changed and if it's available
+	if req.Handle != "" && req.Handle != user.Handle {
+		if models.IsReservedName(req.Handle) {
+			return nil, validationError("handle is reserved and cannot be used")
+		}
+		existingUser, err := s.userRepo.GetByHandle(req.Handle)
+		if err != nil && !errors.Is(err, repositories.ErrNotFound) {
 			return nil, err
 		}
 		if existingUser != nil {
-			return nil, errors.New("email is already taken")
+			return nil, conflictError("handle is already taken")
 		}
-		user.Email = req.Email
+		user.Handle = req.Handle
+	}
+
+	// Email changes must be confirmed from the new address, see AuthService.RequestEmailChange
+	if req.Email != "" && req.Email != user.Email {
+		return nil, validationError("email changes must be confirmed; use POST /api/users/me/email")
 	}
 
 	// Update other fields
@@ -91,6 +164,31 @@ func (s *UserService) UpdateProfile(userID uint, req *UpdateUserRequest) (*model
 		user.Bio = req.Bio
 	}
 
+	if req.Website != "" {
+		user.Website = req.Website
+	}
+
+	if req.Twitter != "" {
+		user.Twitter = models.NormalizeSocialHandle(req.Twitter, "x.com", "twitter.com")
+	}
+
+	if req.GitHub != "" {
+		user.GitHub = models.NormalizeSocialHandle(req.GitHub, "github.com")
+	}
+
+	if req.Location != "" {
+		user.Location = strings.TrimSpace(req.Location)
+	}
+
+	// Validate the normalized profile so bad handles surface as field errors
+	if err := user.Validate(); err != nil {
+		var fields models.ValidationErrors
+		if errors.As(err, &fields) {
+			return nil, fieldValidationError(fields)
+		}
+		return nil, validationError("%s", err.Error())
+	}
+
 	// Save changes
 	if err := s.userRepo.Update(user); err != nil {
 		return nil, errors.New("failed to update user profile")
@@ -99,6 +197,53 @@ func (s *UserService) UpdateProfile(userID uint, req *UpdateUserRequest) (*model
 	return user, nil
 }
 
+// GetAuthorPage retrieves the public page of the author with the given handle for
+// viewerID (0 when anonymous), honoring the author's profile visibility
+func (s *UserService) GetAuthorPage(viewerID uint, handle string, page, limit int) (*AuthorPage, int64, error) {
+	if s.articleRepo == nil {
+		return nil, 0, errors.New("article repository not available")
+	}
+
+	user, err := s.userRepo.GetByHandle(strings.ToLower(handle))
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, 0, notFoundError("author not found")
+		}
+		return nil, 0, err
+	}
+
+	// Visibility is checked through GetProfile so both endpoints apply the same rules
+	if _, err := s.GetProfile(viewerID, user.ID); err != nil {
+		return nil, 0, err
+	}
+
+	totals, err := s.articleRepo.GetAuthorTotals(user.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var likesGiven int64
+	if s.likeRepo != nil {
+		if likesGiven, err = s.likeRepo.CountByUser(user.ID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	filter := &repositories.ArticleFilter{Status: string(models.StatusPublished), AuthorID: user.ID}
+	sortBy := repositories.ArticleSort{Field: "published_at", Desc: true}
+	articles, total, err := s.articleRepo.ListFiltered((page-1)*limit, limit, filter, sortBy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return &AuthorPage{
+		Profile:    user.Profile(),
+		Stats:      *totals,
+		LikesGiven: likesGiven,
+		Articles:   models.SummarizeArticles(articles),
+	}, total, nil
+}
+
 // GetUserArticles retrieves articles by user with pagination
 func (s *UserService) GetUserArticles(userID uint, page, limit int) ([]*models.Article, int64, error) {
 	if s.articleRepo == nil {
@@ -108,7 +253,7 @@ func (s *UserService) GetUserArticles(userID uint, page, limit int) ([]*models.A
 	// Verify user exists
 	_, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return nil, 0, errors.New("user not found")
+		return nil, 0, notFoundError("user not found")
 	}
 
 	// Get articles by user
@@ -127,33 +272,61 @@ func (s *UserService) GetUserArticles(userID uint, page, limit int) ([]*models.A
 	return articles, total, nil
 }
 
+// SetMembershipTier changes the membership tier deciding which members-only
+// and premium articles the user may read in full
+func (s *UserService) SetMembershipTier(userID uint, req *SetMembershipRequest) (*models.User, error) {
+	tier := models.MembershipTier(req.Tier)
+	switch tier {
+	case models.TierFree, models.TierMember, models.TierPremium:
+	default:
+		return nil, validationError("tier must be one of: free, member, premium")
+	}
+
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return nil, notFoundError("user not found")
+		}
+		return nil, err
+	}
+	if user.MembershipTier == tier {
+		return user, nil
+	}
+
+	user.MembershipTier = tier
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update membership tier: %w", err)
+	}
+	return user, nil
+}
+
 // validateUpdateRequest validates user update request
 func (s *UserService) validateUpdateRequest(req *UpdateUserRequest) error {
 	if req == nil {
-		return errors.New("update request is required")
+		return validationError("update request is required")
 	}
 
 	if req.Username != "" {
 		if len(strings.TrimSpace(req.Username)) < 3 || len(req.Username) > 50 {
-			return errors.New("username must be between 3 and 50 characters")
+			return validationError("username must be between 3 and 50 characters")
 		}
 	}
 
 	if req.Email != "" {
 		if len(strings.TrimSpace(req.Email)) == 0 {
-			return errors.New("email cannot be empty")
+			return validationError("email cannot be empty")
 		}
 		if len(req.Email) > 100 {
-			return errors.New("email must be less than 100 characters")
+			return validationError("email must be less than 100 characters")
 		}
 	}
 
 	if req.AvatarURL != "" && len(req.AvatarURL) > 255 {
-		return errors.New("avatar URL must be less than 255 characters")
+		return validationError("avatar URL must be less than 255 characters")
 	}
 
 	if req.Bio != "" && len(req.Bio) > 500 {
-		return errors.New("bio must be less than 500 characters")
+		return validationError("bio must be less than 500 characters")
 	}
 
 	return nil
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"go-blog/internal/models"
+	"go-blog/internal/repositories"
+)
+
+// UserSettingsService manages per-user notification and privacy preferences
+type UserSettingsService struct {
+	settingsRepo repositories.UserSettingsRepository
+}
+
+// UpdateSettingsRequest represents a settings update; omitted fields keep their value
+type UpdateSettingsRequest struct {
+	EmailNotifications *bool   `json:"email_notifications,omitempty"`
+	EmailSearchAlerts  *bool   `json:"email_search_alerts,omitempty"`
+	EmailMonthlyReport *bool   `json:"email_monthly_report,omitempty"`
+	ProfileVisibility  *string `json:"profile_visibility,omitempty" validate:"omitempty,oneof=public members private"`
+	EditorMode         *string `json:"editor_mode,omitempty" validate:"omitempty,oneof=markdown rich_text"`
+}
+
+// NewUserSettingsService creates a new user settings service
+func NewUserSettingsService(settingsRepo repositories.UserSettingsRepository) *UserSettingsService {
+	return &UserSettingsService{
+		settingsRepo: settingsRepo,
+	}
+}
+
+// Get returns the user's settings, falling back to the defaults when none are stored
+func (s *UserSettingsService) Get(userID uint) (*models.UserSettings, error) {
+	settings, err := s.settingsRepo.GetByUserID(userID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			return models.DefaultUserSettings(userID), nil
+		}
+		return nil, fmt.Errorf("failed to get user settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Update applies the fields present in req to the user's settings
+func (s *UserSettingsService) Update(userID uint, req *UpdateSettingsRequest) (*models.UserSettings, error) {
+	if req == nil {
+		return nil, validationError("settings update is required")
+	}
+
+	settings, err := s.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.EmailNotifications != nil {
+		settings.EmailNotifications = *req.EmailNotifications
+	}
+	if req.EmailSearchAlerts != nil {
+		settings.EmailSearchAlerts = *req.EmailSearchAlerts
+	}
+	if req.EmailMonthlyReport != nil {
+		settings.EmailMonthlyReport = *req.EmailMonthlyReport
+	}
+	if req.ProfileVisibility != nil {
+		settings.ProfileVisibility = models.ProfileVisibility(*req.ProfileVisibility)
+	}
+	if req.EditorMode != nil {
+		settings.EditorMode = models.EditorMode(*req.EditorMode)
+	}
+
+	if err := settings.Validate(); err != nil {
+		var fields models.ValidationErrors
+		if errors.As(err, &fields) {
+			return nil, fieldValidationError(fields)
+		}
+		return nil, validationError("%s", err.Error())
+	}
+
+	if err := s.settingsRepo.Save(settings); err != nil {
+		return nil, fmt.Errorf("failed to save user settings: %w", err)
+	}
+	return settings, nil
+}
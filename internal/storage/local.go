@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Local stores files on the local filesystem under Root. The application serves
+// Root at BaseURL, so URLs are relative to the API host.
+type Local struct {
+	Root    string
+	BaseURL string
+	// CDNURL, when set, is the URL of a CDN pulling from BaseURL that file
+	// URLs point at instead
+	CDNURL string
+}
+
+// NewLocal creates a filesystem storage rooted at root and served from baseURL
+func NewLocal(root, baseURL string) *Local {
+	return &Local{
+		Root:    root,
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// Put writes the file atomically so readers never see a partial upload
+func (s *Local) Put(key string, r io.Reader, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func (s *Local) Delete(key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *Local) URL(key string) string {
+	base := s.BaseURL
+	if s.CDNURL != "" {
+		base = strings.TrimSuffix(s.CDNURL, "/")
+	}
+	return base + "/" + strings.TrimPrefix(path.Clean("/"+key), "/")
+}
+
+// path maps key to a file below Root, rejecting keys that would escape it
+func (s *Local) path(key string) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalPutURLDelete(t *testing.T) {
+	s := NewLocal(t.TempDir(), "/uploads/")
+
+	if err := s.Put("avatars/1/a-64.jpg", strings.NewReader("jpeg"), "image/jpeg"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.Root, "avatars", "1", "a-64.jpg"))
+	if err != nil || string(data) != "jpeg" {
+		t.Errorf("Expected stored content, got %q (%v)", data, err)
+	}
+	if url := s.URL("avatars/1/a-64.jpg"); url != "/uploads/avatars/1/a-64.jpg" {
+		t.Errorf("Unexpected URL %q", url)
+	}
+
+	s.CDNURL = "https://cdn.example.com/"
+	if url := s.URL("avatars/1/a-64.jpg"); url != "https://cdn.example.com/avatars/1/a-64.jpg" {
+		t.Errorf("Unexpected CDN URL %q", url)
+	}
+
+	if err := s.Delete("avatars/1/a-64.jpg"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := s.Delete("avatars/1/a-64.jpg"); err != nil {
+		t.Errorf("Deleting a missing key should succeed, got %v", err)
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	s := NewLocal(t.TempDir(), "/uploads")
+
+	for _, key := range []string{"", "../outside", "avatars/../../outside", "/absolute", "avatars//double"} {
+		if err := s.Put(key, strings.NewReader("x"), "text/plain"); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q): expected ErrInvalidKey, got %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxPresignExpiry is the longest validity S3 accepts for presigned URLs
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// S3Options configures a bucket of an S3 compatible object store, such as AWS
+// S3, MinIO, Cloudflare R2 or DigitalOcean Spaces
+type S3Options struct {
+	Endpoint  string // e.g. https://s3.eu-central-1.amazonaws.com
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket as endpoint/bucket instead of as a
+	// subdomain of the endpoint, which most self-hosted stores need
+	PathStyle bool
+	// BaseURL is the public URL files are served from, such as a CDN in front
+	// of the bucket; the bucket URL when empty
+	BaseURL string
+	// CacheControl is stored with every object Put, for the bucket and CDNs to
+	// serve it with
+	CacheControl string
+}
+
+// S3 stores files as objects of a bucket, signing requests with AWS Signature
+// Version 4
+type S3 struct {
+	options  S3Options
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3 creates a storage on the bucket of options
+func NewS3(options S3Options) (*S3, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(options.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", options.Endpoint)
+	}
+	if options.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+	s := &S3{
+		options:  options,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: time.Minute},
+		now:      time.Now,
+	}
+	if options.BaseURL == "" {
+		s.options.BaseURL = s.objectURL("").String()
+	}
+	s.options.BaseURL = strings.TrimSuffix(s.options.BaseURL, "/")
+	return s, nil
+}
+
+// SetClient sets the HTTP client the bucket is called with
+func (s *S3) SetClient(client *http.Client) {
+	s.client = client
+}
+
+// Put uploads the content of r as the object key. The content is read into
+// memory first, since signed uploads need its length and hash.
+func (s *S3) Put(key string, r io.Reader, contentType string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key).String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if s.options.CacheControl != "" {
+		req.Header.Set("Cache-Control", s.options.CacheControl)
+	}
+	sum := sha256.Sum256(data)
+	s.sign(req, hex.EncodeToString(sum[:]))
+	return s.do(req, http.StatusOK)
+}
+
+// Delete removes the object key; S3 answers deletes of missing objects with 204 too
+func (s *S3) Delete(key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, emptyPayloadHash)
+	return s.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s *S3) URL(key string) string {
+	return s.options.BaseURL + "/" + strings.TrimPrefix(path.Clean("/"+key), "/")
+}
+
+// PresignPut returns a URL clients can upload the object key to with a PUT
+// request within expires, sending contentType as the Content-Type header
+func (s *S3) PresignPut(key, contentType string, expires time.Duration) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	headers := http.Header{}
+	if contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
+	return s.presign(http.MethodPut, key, headers, expires)
+}
+
+// do sends a signed request, failing unless it is answered with one of ok
+func (s *S3) do(req *http.Request, ok ...int) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	for _, status := range ok {
+		if resp.StatusCode == status {
+			io.Copy(io.Discard, resp.Body)
+			return nil
+		}
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("S3 %s %s: status %d: %s", req.Method, req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// objectURL returns the URL of the object key, or of the bucket for an empty key
+func (s *S3) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.options.PathStyle {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.options.Bucket
+	} else {
+		u.Host = s.options.Bucket + "." + u.Host
+	}
+	if key != "" {
+		u.Path += "/" + key
+	}
+	return &u
+}
+
+// validKey reports whether key is a clean relative key, the same keys the
+// local storage accepts
+func validKey(key string) bool {
+	cleaned := path.Clean("/" + key)
+	return key != "" && cleaned != "/" && cleaned == "/"+key
+}
+
+// emptyPayloadHash is the SHA-256 of an empty body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// sign adds the Signature Version 4 Authorization header to req
+func (s *S3) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(req.URL.Host, req.Header)
+	scope := s.scope(now)
+	signature := s.signature(now, canonicalRequest(req.Method, req.URL, canonicalHeaders, signedHeaders, payloadHash))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.options.AccessKey, scope, signedHeaders, signature))
+}
+
+// presign returns a URL signed in its query that allows method on the object
+// key within expires; headers must be sent as given
+func (s *S3) presign(method, key string, headers http.Header, expires time.Duration) (string, error) {
+	if expires <= 0 || expires > maxPresignExpiry {
+		return "", fmt.Errorf("presigned URLs must expire within %s", maxPresignExpiry)
+	}
+	now := s.now().UTC()
+	u := s.objectURL(key)
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(u.Host, headers)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.options.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", signedHeaders)
+	u.RawQuery = canonicalQuery(query)
+
+	signature := s.signature(now, canonicalRequest(method, u, canonicalHeaders, signedHeaders, "UNSIGNED-PAYLOAD"))
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String(), nil
+}
+
+// scope is the credential scope of requests signed at t
+func (s *S3) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + s.options.Region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request made at t
+func (s *S3) signature(t time.Time, canonical string) string {
+	hash := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + t.Format("20060102T150405Z") + "\n" + s.scope(t) + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretKey), t.Format("20060102"))
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// canonicalRequest builds the canonical form of a request that is signed
+func canonicalRequest(method string, u *url.URL, canonicalHeaders, signedHeaders, payloadHash string) string {
+	return strings.Join([]string{
+		method,
+		uriEncode(u.Path, false),
+		u.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+}
+
+// canonicalizeHeaders returns the signed header names and the canonical
+// headers of host and header, sorted by lowercase name
+func canonicalizeHeaders(host string, header http.Header) (string, string) {
+	values := map[string]string{"host": host}
+	for name, value := range header {
+		values[strings.ToLower(name)] = strings.Join(value, ",")
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonical strings.Builder
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(values[name]) + "\n")
+	}
+	return strings.Join(names, ";"), canonical.String()
+}
+
+// canonicalQuery encodes query sorted by name, as signatures require
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte but unreserved characters, and slashes
+// unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", c)
+		}
+	}
+	return encoded.String()
+}
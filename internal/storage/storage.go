@@ -0,0 +1,40 @@
+// Package storage stores uploaded files and resolves their public URLs.
+package storage
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidKey is returned for keys that are empty or escape the storage root
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage stores files under slash-separated keys such as "avatars/12/ab12-256.jpg"
+type Storage interface {
+	// Put stores the content of r under key, replacing any existing file
+	Put(key string, r io.Reader, contentType string) error
+	// Delete removes the file stored under key; deleting a missing key is not an error
+	Delete(key string) error
+	// URL returns the public URL of key
+	URL(key string) string
+}
+
+// ImmutableCacheControl is the Cache-Control value of stored files cacheable for
+// maxAge seconds; keys change with the content they store, so files never need
+// revalidating. It is empty for a maxAge of 0.
+func ImmutableCacheControl(maxAge int) string {
+	if maxAge <= 0 {
+		return ""
+	}
+	return "public, max-age=" + strconv.Itoa(maxAge) + ", immutable"
+}
+
+// Presigner is implemented by storages clients can upload files to directly,
+// without passing them through the API
+type Presigner interface {
+	// PresignPut returns a URL accepting a PUT of the file key with contentType
+	// until expires has passed
+	PresignPut(key, contentType string, expires time.Duration) (string, error)
+}
@@ -0,0 +1,109 @@
+// Package testenv runs integration tests against services started in
+// disposable Docker containers instead of a database set up beforehand.
+package testenv
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+)
+
+// DockerEnv turns the containers on when set to a non-empty value
+const DockerEnv = "TEST_DOCKER"
+
+// DatabaseURLEnv is the variable database.SetupTestDB reads the MySQL DSN from
+const DatabaseURLEnv = "TEST_DATABASE_URL"
+
+// MySQL image the database container runs and its test database
+const (
+	mysqlRepository = "mysql"
+	mysqlTag        = "8.0"
+	mysqlPassword   = "secret"
+	mysqlDatabase   = "go_blog_test"
+)
+
+// containerLifetime bounds how long a container outlives a test binary killed
+// before it could remove it
+const containerLifetime = 10 * time.Minute
+
+// Main runs the tests of a package from its TestMain. With TEST_DOCKER set and
+// TEST_DATABASE_URL unset, it first starts a MySQL container and points
+// TEST_DATABASE_URL at it, so every database the tests set up is a MySQL one,
+// and removes the container once they finish. Otherwise it only runs them.
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	if os.Getenv(DockerEnv) == "" || os.Getenv(DatabaseURLEnv) != "" {
+		return m.Run()
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		slog.Error("Failed to connect to Docker", "error", err)
+		return 1
+	}
+	pool.MaxWait = 2 * time.Minute
+
+	resource, dsn, err := startMySQL(pool)
+	if err != nil {
+		slog.Error("Failed to start MySQL", "error", err)
+		return 1
+	}
+	defer func() {
+		if err := pool.Purge(resource); err != nil {
+			slog.Error("Failed to remove MySQL container", "error", err)
+		}
+	}()
+
+	os.Setenv(DatabaseURLEnv, dsn)
+	defer os.Unsetenv(DatabaseURLEnv)
+	return m.Run()
+}
+
+// startMySQL starts a MySQL container and waits until it accepts connections,
+// returning it with the DSN of its test database
+func startMySQL(pool *dockertest.Pool) (*dockertest.Resource, string, error) {
+	resource, err := pool.RunWithOptions(&dockertest.RunOptions{
+		Repository: mysqlRepository,
+		Tag:        mysqlTag,
+		Env: []string{
+			"MYSQL_ROOT_PASSWORD=" + mysqlPassword,
+			"MYSQL_DATABASE=" + mysqlDatabase,
+		},
+	}, func(config *docker.HostConfig) {
+		config.AutoRemove = true
+		config.RestartPolicy = docker.RestartPolicy{Name: "no"}
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if err := resource.Expire(uint(containerLifetime.Seconds())); err != nil {
+		pool.Purge(resource)
+		return nil, "", err
+	}
+
+	dsn := fmt.Sprintf("root:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		mysqlPassword, resource.GetHostPort("3306/tcp"), mysqlDatabase)
+	err = pool.Retry(func() error {
+		db, err := sql.Open("mysql", dsn)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		return db.Ping()
+	})
+	if err != nil {
+		pool.Purge(resource)
+		return nil, "", fmt.Errorf("MySQL did not become ready: %w", err)
+	}
+	return resource, dsn, nil
+}
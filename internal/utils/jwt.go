@@ -1,38 +1,47 @@
 package utils
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token types carried in the typ claim
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
 // JWTClaims represents the JWT claims structure
 type JWTClaims struct {
 	UserID   uint   `json:"user_id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	Version  uint   `json:"ver,omitempty"` // user's token version when issued; stale versions are rejected
+	Type     string `json:"typ,omitempty"` // TokenTypeAccess or TokenTypeRefresh
+	Session  string `json:"sid,omitempty"` // sign-in session the token belongs to, empty for tokens without one
 	jwt.RegisteredClaims
 }
 
 // TokenPair represents access and refresh tokens
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // empty when delivered in a cookie
 	ExpiresIn    int64  `json:"expires_in"`
+
+	RefreshID        string    `json:"-"` // jti of the refresh token, used to track rotation
+	RefreshExpiresAt time.Time `json:"-"`
 }
 
-// GenerateJWT generates a JWT token for a user
+// GenerateJWT generates a JWT access token for a user
 func GenerateJWT(userID uint, username, email, secret string) (string, error) {
-	claims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 7)), // 7 days
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   string(rune(userID)),
-		},
+	claims, err := newClaims(userID, username, email, 0, TokenTypeAccess, time.Now().Add(time.Hour*24*7)) // 7 days
+	if err != nil {
+		return "", err
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -41,17 +50,24 @@ func GenerateJWT(userID uint, username, email, secret string) (string, error) {
 
 // GenerateTokenPair generates both access and refresh tokens
 func GenerateTokenPair(userID uint, username, email, secret string) (*TokenPair, error) {
+	return GenerateVersionedTokenPair(userID, username, email, 0, secret)
+}
+
+// GenerateVersionedTokenPair generates access and refresh tokens carrying the user's
+// token version, so bumping the version invalidates every pair issued before
+func GenerateVersionedTokenPair(userID uint, username, email string, version uint, secret string) (*TokenPair, error) {
+	return GenerateSessionTokenPair(userID, username, email, version, "", secret)
+}
+
+// GenerateSessionTokenPair generates a versioned token pair whose tokens carry
+// the sign-in session they belong to, so signing the session out rejects them
+func GenerateSessionTokenPair(userID uint, username, email string, version uint, session, secret string) (*TokenPair, error) {
 	// Access token (shorter expiry)
-	accessClaims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24)),     // 1 day
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   string(rune(userID)),
-		},
+	accessClaims, err := newClaims(userID, username, email, version, TokenTypeAccess, time.Now().Add(time.Hour*24)) // 1 day
+	if err != nil {
+		return nil, err
 	}
+	accessClaims.Session = session
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
 	accessTokenString, err := accessToken.SignedString([]byte(secret))
@@ -60,16 +76,12 @@ func GenerateTokenPair(userID uint, username, email, secret string) (*TokenPair,
 	}
 
 	// Refresh token (longer expiry)
-	refreshClaims := JWTClaims{
-		UserID:   userID,
-		Username: username,
-		Email:    email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 24 * 30)), // 30 days
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   string(rune(userID)),
-		},
+	refreshExpiresAt := time.Now().Add(time.Hour * 24 * 30) // 30 days
+	refreshClaims, err := newClaims(userID, username, email, version, TokenTypeRefresh, refreshExpiresAt)
+	if err != nil {
+		return nil, err
 	}
+	refreshClaims.Session = session
 
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
 	refreshTokenString, err := refreshToken.SignedString([]byte(secret))
@@ -78,12 +90,46 @@ func GenerateTokenPair(userID uint, username, email, secret string) (*TokenPair,
 	}
 
 	return &TokenPair{
-		AccessToken:  accessTokenString,
-		RefreshToken: refreshTokenString,
-		ExpiresIn:    time.Hour.Milliseconds() * 24, // 1 day in milliseconds
+		AccessToken:      accessTokenString,
+		RefreshToken:     refreshTokenString,
+		ExpiresIn:        time.Hour.Milliseconds() * 24, // 1 day in milliseconds
+		RefreshID:        refreshClaims.ID,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// newClaims builds the claims of a token of the given type. The subject is the
+// decimal user ID and every token gets a unique jti.
+func newClaims(userID uint, username, email string, version uint, tokenType string, expiresAt time.Time) (JWTClaims, error) {
+	id, err := newTokenID()
+	if err != nil {
+		return JWTClaims{}, err
+	}
+
+	return JWTClaims{
+		UserID:   userID,
+		Username: username,
+		Email:    email,
+		Version:  version,
+		Type:     tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			Subject:   strconv.FormatUint(uint64(userID), 10),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
 	}, nil
 }
 
+// newTokenID returns a random token ID for the jti claim
+func newTokenID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
 // ValidateJWT validates a JWT token and returns the claims
 func ValidateJWT(tokenString, secret string) (*JWTClaims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
@@ -104,6 +150,19 @@ func ValidateJWT(tokenString, secret string) (*JWTClaims, error) {
 	return nil, errors.New("invalid token")
 }
 
+// ValidateJWTOfType validates a JWT token like ValidateJWT and also requires its
+// type, so a refresh token cannot be used as an access token or the other way round
+func ValidateJWTOfType(tokenString, secret, tokenType string) (*JWTClaims, error) {
+	claims, err := ValidateJWT(tokenString, secret)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Type != tokenType {
+		return nil, errors.New("unexpected token type")
+	}
+	return claims, nil
+}
+
 // ExtractUserID extracts user ID from JWT token
 func ExtractUserID(tokenString, secret string) (uint, error) {
 	claims, err := ValidateJWT(tokenString, secret)
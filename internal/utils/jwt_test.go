@@ -108,12 +108,67 @@ func TestGenerateTokenPair(t *testing.T) {
 				assert.Equal(t, tt.userID, refreshClaims.UserID)
 				
 				// Verify refresh token has longer expiry than access token
-				assert.True(t, refreshClaims.ExpiresAt.After(*accessClaims.ExpiresAt))
+				assert.True(t, refreshClaims.ExpiresAt.After(accessClaims.ExpiresAt.Time))
 			}
 		})
 	}
 }
 
+func TestTokenPairClaims(t *testing.T) {
+	secret := "test-secret"
+	userID := uint(300) // above 127, where a rune-encoded subject stopped being the ID
+
+	tokenPair, err := GenerateTokenPair(userID, "testuser", "test@example.com", secret)
+	require.NoError(t, err)
+
+	accessClaims, err := ValidateJWT(tokenPair.AccessToken, secret)
+	require.NoError(t, err)
+	refreshClaims, err := ValidateJWT(tokenPair.RefreshToken, secret)
+	require.NoError(t, err)
+
+	assert.Equal(t, "300", accessClaims.Subject)
+	assert.Equal(t, "300", refreshClaims.Subject)
+	assert.Equal(t, TokenTypeAccess, accessClaims.Type)
+	assert.Equal(t, TokenTypeRefresh, refreshClaims.Type)
+	assert.NotEmpty(t, accessClaims.ID)
+	assert.NotEqual(t, accessClaims.ID, refreshClaims.ID)
+	assert.Equal(t, refreshClaims.ID, tokenPair.RefreshID)
+
+	// Tokens generated at the same instant still differ
+	again, err := GenerateTokenPair(userID, "testuser", "test@example.com", secret)
+	require.NoError(t, err)
+	assert.NotEqual(t, tokenPair.RefreshToken, again.RefreshToken)
+	assert.Empty(t, accessClaims.Session)
+
+	// Session pairs carry the session in both tokens
+	sessionPair, err := GenerateSessionTokenPair(userID, "testuser", "test@example.com", 0, "abc123", secret)
+	require.NoError(t, err)
+	accessClaims, err = ValidateJWT(sessionPair.AccessToken, secret)
+	require.NoError(t, err)
+	refreshClaims, err = ValidateJWT(sessionPair.RefreshToken, secret)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", accessClaims.Session)
+	assert.Equal(t, "abc123", refreshClaims.Session)
+}
+
+func TestValidateJWTOfType(t *testing.T) {
+	secret := "test-secret"
+	tokenPair, err := GenerateTokenPair(1, "testuser", "test@example.com", secret)
+	require.NoError(t, err)
+
+	_, err = ValidateJWTOfType(tokenPair.AccessToken, secret, TokenTypeAccess)
+	assert.NoError(t, err)
+	_, err = ValidateJWTOfType(tokenPair.RefreshToken, secret, TokenTypeRefresh)
+	assert.NoError(t, err)
+
+	claims, err := ValidateJWTOfType(tokenPair.AccessToken, secret, TokenTypeRefresh)
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+	claims, err = ValidateJWTOfType(tokenPair.RefreshToken, secret, TokenTypeAccess)
+	assert.Error(t, err)
+	assert.Nil(t, claims)
+}
+
 func TestValidateJWT(t *testing.T) {
 	secret := "test-secret"
 	userID := uint(1)
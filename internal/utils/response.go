@@ -12,12 +12,17 @@ type APIResponse struct {
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
 	Errors  []string    `json:"errors,omitempty"`
+	Meta    *Meta       `json:"meta,omitempty"`
+	// Warnings tell clients about deprecated routes and response shapes; the
+	// deprecation middleware adds them
+	Warnings []string `json:"warnings,omitempty"`
 }
 
-// PaginatedResponse represents a paginated API response
-type PaginatedResponse struct {
-	Data       interface{} `json:"data"`
-	Pagination Pagination  `json:"pagination"`
+// Meta represents list metadata returned alongside the response data
+type Meta struct {
+	Pagination *Pagination            `json:"pagination,omitempty"`
+	Filters    map[string]interface{} `json:"filters,omitempty"`
+	Sort       string                 `json:"sort,omitempty"`
 }
 
 // Pagination represents pagination metadata
@@ -72,17 +77,34 @@ func SendErrorResponse(c *gin.Context, statusCode int, message string, errors []
 	})
 }
 
-// PaginatedSuccessResponse sends a paginated successful response
-func PaginatedSuccessResponse(c *gin.Context, data interface{}, page, limit int, total int64) {
-	totalPages := int((total + int64(limit) - 1) / int64(limit))
-	
-	c.JSON(http.StatusOK, PaginatedResponse{
-		Data: data,
-		Pagination: Pagination{
-			Page:       page,
-			Limit:      limit,
-			Total:      total,
-			TotalPages: totalPages,
-		},
-	})
+// NewPagination creates pagination metadata for a page of results
+func NewPagination(page, limit int, total int64) *Pagination {
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((total + int64(limit) - 1) / int64(limit))
+	}
+
+	return &Pagination{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: totalPages,
+	}
+}
+
+// ListResponse creates a successful list response structure with metadata
+func ListResponse(message string, data interface{}, meta *Meta) APIResponse {
+	return APIResponse{
+		Success: true,
+		Data:    data,
+		Message: message,
+		Meta:    meta,
+	}
+}
+
+// PaginatedSuccessResponse sends a successful response for a page of results
+func PaginatedSuccessResponse(c *gin.Context, message string, data interface{}, page, limit int, total int64) {
+	c.JSON(http.StatusOK, ListResponse(message, data, &Meta{
+		Pagination: NewPagination(page, limit, total),
+	}))
 }
\ No newline at end of file
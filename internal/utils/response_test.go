@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		page       int
+		limit      int
+		total      int64
+		totalPages int
+	}{
+		{"empty", 1, 10, 0, 0},
+		{"partial page", 1, 10, 3, 1},
+		{"exact pages", 2, 10, 20, 2},
+		{"rounds up", 3, 10, 21, 3},
+		{"no limit", 1, 0, 5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, &Pagination{Page: tt.page, Limit: tt.limit, Total: tt.total, TotalPages: tt.totalPages},
+				NewPagination(tt.page, tt.limit, tt.total))
+		})
+	}
+}
+
+func TestListResponseEnvelope(t *testing.T) {
+	response := ListResponse("Articles retrieved successfully", []string{"a"}, &Meta{
+		Pagination: NewPagination(2, 1, 3),
+		Filters:    map[string]interface{}{"tag_id": "1,2"},
+		Sort:       "-view_count",
+	})
+	body, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"success": true,
+		"message": "Articles retrieved successfully",
+		"data": ["a"],
+		"meta": {
+			"pagination": {"page": 2, "limit": 1, "total": 3, "total_pages": 3},
+			"filters": {"tag_id": "1,2"},
+			"sort": "-view_count"
+		}
+	}`, string(body))
+
+	// Single items carry no list metadata, and errors no data
+	body, err = json.Marshal(SuccessResponse("Tag retrieved successfully", map[string]string{"name": "go"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"success": true, "message": "Tag retrieved successfully", "data": {"name": "go"}}`, string(body))
+
+	body, err = json.Marshal(ErrorResponseWithDetails("validation failed", []string{"name is required"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"success": false, "message": "validation failed", "errors": ["name is required"]}`, string(body))
+}
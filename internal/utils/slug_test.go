@@ -0,0 +1,10 @@
+package utils
+
+import "testing"
+
+func BenchmarkGenerateSlug(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		GenerateSlug("Building a Blog in Go: Routing, Middleware & Testing (Part 2)")
+	}
+}
@@ -0,0 +1,12 @@
+package utils
+
+import (
+	"strings"
+)
+
+// NormalizeTagName returns the canonical lookup form of a tag name or alias.
+// Matching is case-insensitive and ignores surrounding and repeated whitespace,
+// so "Go", " go " and "GO" all normalize to "go".
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
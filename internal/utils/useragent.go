@@ -0,0 +1,70 @@
+package utils
+
+import "strings"
+
+// userAgentBrowsers maps User-Agent product tokens to browser names. Order
+// matters: Edge and Opera also announce Chrome, and Chrome announces Safari.
+var userAgentBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"FxiOS/", "Firefox"},
+	{"Safari/", "Safari"},
+}
+
+// userAgentSystems maps User-Agent fragments to operating system names. Mobile
+// systems come first as they also mention the desktop system they derive from.
+var userAgentSystems = []struct{ token, name string }{
+	{"Android", "Android"},
+	{"iPhone", "iOS"},
+	{"iPad", "iPadOS"},
+	{"Windows", "Windows"},
+	{"Mac OS X", "macOS"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+}
+
+// DescribeUserAgent returns a short device label such as "Firefox on Linux" for
+// a User-Agent header. Clients that are not browsers are named after their first
+// product token ("curl"), and an empty header gives "Unknown device".
+func DescribeUserAgent(userAgent string) string {
+	userAgent = strings.TrimSpace(userAgent)
+	if userAgent == "" {
+		return "Unknown device"
+	}
+
+	browser := ""
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	system := ""
+	for _, s := range userAgentSystems {
+		if strings.Contains(userAgent, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	case system != "":
+		return "Unknown browser on " + system
+	}
+
+	product := strings.Fields(userAgent)[0]
+	if name, _, found := strings.Cut(product, "/"); found && name != "" {
+		product = name
+	}
+	if len(product) > 50 {
+		product = product[:50]
+	}
+	return product
+}
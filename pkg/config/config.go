@@ -2,18 +2,49 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"regexp"
 	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// indexNowKeyPattern matches the key format required by the IndexNow protocol
+var indexNowKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9-]{8,128}$`)
+
+// hexColorPattern matches #rrggbb colors
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
 // Config holds all configuration for our application
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Log      LogConfig      `mapstructure:"log"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	JWT           JWTConfig           `mapstructure:"jwt"`
+	Log           LogConfig           `mapstructure:"log"`
+	Tags          TagsConfig          `mapstructure:"tags"`
+	Search        SearchConfig        `mapstructure:"search"`
+	Stats         StatsConfig         `mapstructure:"stats"`
+	Embeds        EmbedsConfig        `mapstructure:"embeds"`
+	LinkCheck     LinkCheckConfig     `mapstructure:"link_check"`
+	Suggestions   SuggestionsConfig   `mapstructure:"suggestions"`
+	OGImages      OGImagesConfig      `mapstructure:"og_images"`
+	Storage       StorageConfig       `mapstructure:"storage"`
+	Comments      CommentsConfig      `mapstructure:"comments"`
+	Security      SecurityConfig      `mapstructure:"security"`
+	CORS          CORSConfig          `mapstructure:"cors"`
+	Limits        LimitsConfig        `mapstructure:"limits"`
+	Quotas        QuotasConfig        `mapstructure:"quotas"`
+	Sessions      SessionsConfig      `mapstructure:"sessions"`
+	MagicLinks    MagicLinksConfig    `mapstructure:"magic_links"`
+	Maintenance   MaintenanceConfig   `mapstructure:"maintenance"`
+	Site          SiteConfig          `mapstructure:"site"`
+	SearchEngines SearchEnginesConfig `mapstructure:"search_engines"`
+	Scheduler     SchedulerConfig     `mapstructure:"scheduler"`
+	Queue         QueueConfig         `mapstructure:"queue"`
+	Cache         CacheConfig         `mapstructure:"cache"`
+	SLO           SLOConfig           `mapstructure:"slo"`
+	Errors        ErrorsConfig        `mapstructure:"errors"`
 }
 
 // ServerConfig holds server configuration
@@ -23,6 +54,9 @@ type ServerConfig struct {
 	ReadTimeout  int    `mapstructure:"read_timeout"`
 	WriteTimeout int    `mapstructure:"write_timeout"`
 	IdleTimeout  int    `mapstructure:"idle_timeout"`
+	PublicURL    string `mapstructure:"public_url"` // base URL used in links sent by email
+
+	TrustedProxies []string `mapstructure:"trusted_proxies"` // IPs or CIDRs of reverse proxies whose X-Forwarded-For is believed; empty trusts none
 }
 
 // DatabaseConfig holds database configuration
@@ -35,18 +69,258 @@ type DatabaseConfig struct {
 	MaxIdleConns int    `mapstructure:"max_idle_conns"`
 	MaxOpenConns int    `mapstructure:"max_open_conns"`
 	MaxLifetime  int    `mapstructure:"max_lifetime"`
+
+	StatementTimeout int `mapstructure:"statement_timeout"` // in seconds a statement may run before it is cancelled, 0 disables
+	BreakerThreshold int `mapstructure:"breaker_threshold"` // consecutive connection failures or timeouts that stop queries, 0 disables
+	BreakerCooldown  int `mapstructure:"breaker_cooldown"`  // in seconds queries are refused with 503 before one is let through to probe
+	ExplainThreshold int `mapstructure:"explain_threshold"` // in milliseconds a SELECT may take before its query plan is logged, for development; 0 disables
 }
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	Secret     string `mapstructure:"secret"`
-	ExpireTime int    `mapstructure:"expire_time"` // in hours
+	Secret         string `mapstructure:"secret"`
+	ExpireTime     int    `mapstructure:"expire_time"`      // in hours
+	RefreshCookie  bool   `mapstructure:"refresh_cookie"`   // deliver refresh tokens in an HttpOnly cookie instead of the body
+	CookieSecure   bool   `mapstructure:"cookie_secure"`    // disable only for local development over HTTP
+	CookieSameSite string `mapstructure:"cookie_same_site"` // strict, lax or none
 }
 
 // LogConfig holds logging configuration
 type LogConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level       string            `mapstructure:"level"`        // debug, info, warn or error
+	Format      string            `mapstructure:"format"`       // json or text
+	Sampling    LogSamplingConfig `mapstructure:"sampling"`     // thins out repeated info and debug messages
+	DebugSecret string            `mapstructure:"debug_secret"` // signs the X-Debug-Log header turning on debug logs for one request, empty disables it
+}
+
+// ErrorsConfig holds error reporting; panics recovered while serving requests
+// are sent to Sentry when a DSN is set
+type ErrorsConfig struct {
+	SentryDSN   string `mapstructure:"sentry_dsn"`
+	Environment string `mapstructure:"environment"` // tags reported events, e.g. production
+}
+
+// LogSamplingConfig holds log sampling; warnings and errors are never sampled
+type LogSamplingConfig struct {
+	Enabled    bool `mapstructure:"enabled"`
+	Initial    int  `mapstructure:"initial"`    // records of the same message written each second before sampling starts
+	Thereafter int  `mapstructure:"thereafter"` // then one in this many is written
+}
+
+// TagsConfig holds tag maintenance configuration
+type TagsConfig struct {
+	Protected             []string `mapstructure:"protected"`               // tags never removed by orphan cleanup
+	OrphanCleanupInterval int      `mapstructure:"orphan_cleanup_interval"` // in hours, 0 disables
+	OrphanCleanupDelete   bool     `mapstructure:"orphan_cleanup_delete"`   // false only reports orphans
+}
+
+// SearchConfig holds search configuration
+type SearchConfig struct {
+	AlertInterval int        `mapstructure:"alert_interval"` // in minutes between saved search alert checks, 0 disables
+	StopWords     []string   `mapstructure:"stop_words"`     // words dropped from article searches
+	Synonyms      [][]string `mapstructure:"synonyms"`       // groups of words an article search for one of them also finds the others of
+}
+
+// StatsConfig holds the precomputed article rankings behind the popular and
+// trending listings
+type StatsConfig struct {
+	RankingInterval int `mapstructure:"ranking_interval"` // in minutes between ranking refreshes, 0 scores articles on every request instead
+	TrendingWindow  int `mapstructure:"trending_window"`  // in days of age that halve an article's trending score
+}
+
+// EmbedsConfig holds the oEmbed proxy resolving YouTube, Twitter/X and Gist
+// links in article content
+type EmbedsConfig struct {
+	Enabled   bool     `mapstructure:"enabled"`
+	Providers []string `mapstructure:"providers"` // allowed providers out of youtube, twitter and gist
+	CacheTTL  int      `mapstructure:"cache_ttl"` // in minutes resolved embeds are cached, 0 disables caching
+	Privacy   bool     `mapstructure:"privacy"`   // strip tracking parameters, use youtube-nocookie and ask Twitter not to track
+}
+
+// LinkCheckConfig holds the background check of external links in published
+// articles
+type LinkCheckConfig struct {
+	Interval        int `mapstructure:"interval"`         // in hours between checks, 0 disables
+	RequestInterval int `mapstructure:"request_interval"` // in milliseconds between two requests
+	Timeout         int `mapstructure:"timeout"`          // in seconds before a link counts as unreachable
+}
+
+// SuggestionsConfig holds the OpenAI compatible API suggesting excerpts, tags
+// and meta descriptions of drafts
+type SuggestionsConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	BaseURL string `mapstructure:"base_url"` // API base the chat completion path is appended to
+	APIKey  string `mapstructure:"api_key"`  // bearer token, empty for local servers without authentication
+	Model   string `mapstructure:"model"`
+	Timeout int    `mapstructure:"timeout"` // in seconds a suggestion may take
+}
+
+// OGImagesConfig holds the social share images rendered for published articles
+type OGImagesConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	SiteName   string `mapstructure:"site_name"`  // branding shown below the title
+	Template   string `mapstructure:"template"`   // optional 1200x630 PNG or JPEG drawn as background
+	Background string `mapstructure:"background"` // hex colors, e.g. "#1f2937"
+	Foreground string `mapstructure:"foreground"`
+	Accent     string `mapstructure:"accent"`
+}
+
+// CommentsConfig holds comment moderation configuration
+type CommentsConfig struct {
+	ReportThreshold int `mapstructure:"report_threshold"` // pending reports that hide a comment, 0 disables
+}
+
+// SecurityConfig holds browser security header and CSRF configuration
+type SecurityConfig struct {
+	ContentSecurityPolicy string `mapstructure:"content_security_policy"` // empty omits the header
+	FrameOptions          string `mapstructure:"frame_options"`           // X-Frame-Options: DENY or SAMEORIGIN
+	ReferrerPolicy        string `mapstructure:"referrer_policy"`
+	HSTSMaxAge            int    `mapstructure:"hsts_max_age"` // in seconds, 0 disables; sent over HTTPS only
+	CSRF                  bool   `mapstructure:"csrf"`         // require CSRF tokens on cookie-authenticated writes
+}
+
+// CORSConfig holds Cross-Origin Resource Sharing. No origin is allowed by
+// default, so only pages served from the API's own origin can call it.
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`   // exact origins such as https://blog.example.com, https://*.example.com for subdomains, or "*" for any
+	AllowedMethods   []string `mapstructure:"allowed_methods"`   // methods preflight requests may ask for
+	AllowedHeaders   []string `mapstructure:"allowed_headers"`   // request headers preflight requests may ask for
+	ExposedHeaders   []string `mapstructure:"exposed_headers"`   // response headers scripts may read
+	AllowCredentials bool     `mapstructure:"allow_credentials"` // let browsers send cookies; "*" is refused with it
+	MaxAge           int      `mapstructure:"max_age"`           // in seconds browsers may cache preflight results, 0 omits the header
+}
+
+// LimitsConfig holds request body size limits, answered with 413 when exceeded,
+// and the files upload routes accept
+type LimitsConfig struct {
+	MaxBodySize   int64            `mapstructure:"max_body_size"`   // in bytes, of requests to routes other than uploads; 0 is unlimited
+	MaxUploadSize int64            `mapstructure:"max_upload_size"` // in bytes, of upload requests including the multipart envelope; 0 is unlimited
+	UploadTypes   []string         `mapstructure:"upload_types"`    // content types of uploaded files, sniffed from their content; "image/*" matches any image
+	Routes        map[string]int64 `mapstructure:"routes"`          // per-route limits in bytes keyed by path after /api, e.g. "/articles": 2097152; 0 is unlimited
+}
+
+// QuotasConfig holds write quotas protecting shared instances from abuse.
+// Writes over a quota are refused with 429 until it frees up.
+type QuotasConfig struct {
+	Enabled bool                   `mapstructure:"enabled"`
+	Roles   map[string]QuotaConfig `mapstructure:"roles"` // keyed by user role, e.g. user; roles left out are unlimited
+	IP      QuotaConfig            `mapstructure:"ip"`    // per client IP, across all users
+}
+
+// QuotaConfig limits the writes of a user or client IP; 0 is unlimited
+type QuotaConfig struct {
+	ArticlesPerDay  int `mapstructure:"articles_per_day"`
+	CommentsPerHour int `mapstructure:"comments_per_hour"`
+}
+
+// SessionsConfig holds sign-in session tracking configuration
+type SessionsConfig struct {
+	GeoHeader       string `mapstructure:"geo_header"`        // request header with the client's coarse location, e.g. CF-IPCountry; empty disables
+	NewDeviceAlerts bool   `mapstructure:"new_device_alerts"` // email users on sign-ins from devices they have not used before
+}
+
+// MagicLinksConfig holds passwordless sign-in with emailed links
+type MagicLinksConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	TTL        int    `mapstructure:"ttl"`          // minutes a link can be used
+	MaxPerHour int    `mapstructure:"max_per_hour"` // links sent per account and per IP address in an hour
+	LinkURL    string `mapstructure:"link_url"`     // page the link opens with ?token=; defaults to <public_url>/magic-link
+}
+
+// MaintenanceConfig holds the maintenance mode the server starts in; admins can
+// change it at runtime
+type MaintenanceConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`     // refuse writes except admin routes with 503
+	BlockReads bool   `mapstructure:"block_reads"` // refuse reads too
+	Message    string `mapstructure:"message"`     // banner text, empty uses a generic one
+	RetryAfter int    `mapstructure:"retry_after"` // in seconds, sent as Retry-After; 0 omits it
+}
+
+// SiteConfig holds the visibility the blog starts in; admins can change it at runtime
+type SiteConfig struct {
+	Visibility string `mapstructure:"visibility"` // public, unlisted (not indexed) or private (sign-in required)
+	Message    string `mapstructure:"message"`    // coming soon text for signed-out readers of a private blog, empty uses a generic one
+}
+
+// SearchEnginesConfig holds the search engine notifications sent when articles are published
+type SearchEnginesConfig struct {
+	Enabled          bool     `mapstructure:"enabled"`
+	ArticleURL       string   `mapstructure:"article_url"`  // public article URL, {slug} is replaced; empty uses <public_url>/articles/{slug}
+	SitemapURL       string   `mapstructure:"sitemap_url"`  // sent to ping_urls
+	PingURLs         []string `mapstructure:"ping_urls"`    // sitemap ping endpoints, called with ?sitemap=<sitemap_url>
+	IndexNowKey      string   `mapstructure:"indexnow_key"` // empty disables IndexNow; served at /<key>.txt
+	IndexNowEndpoint string   `mapstructure:"indexnow_endpoint"`
+}
+
+// SchedulerConfig holds the recurring background jobs configuration
+type SchedulerConfig struct {
+	Enabled        bool                 `mapstructure:"enabled"`         // false runs no jobs on schedule; admins can still run them by hand
+	TrashRetention int                  `mapstructure:"trash_retention"` // in days deleted articles are kept before being purged, 0 keeps them
+	RunRetention   int                  `mapstructure:"run_retention"`   // in days job run history and finished queued jobs are kept
+	Jobs           map[string]JobConfig `mapstructure:"jobs"`            // per-job overrides keyed by job name
+}
+
+// JobConfig overrides the defaults of one scheduled job
+type JobConfig struct {
+	Enabled  *bool  `mapstructure:"enabled"`  // nil keeps the job's default
+	Schedule string `mapstructure:"schedule"` // cron expression, @daily style descriptor or "@every 1h"; empty keeps the default
+	Jitter   int    `mapstructure:"jitter"`   // in seconds, random delay added to each run; 0 keeps the default
+}
+
+// QueueConfig holds the background job queue configuration
+type QueueConfig struct {
+	Workers      int `mapstructure:"workers"`       // jobs processed concurrently
+	MaxAttempts  int `mapstructure:"max_attempts"`  // attempts before a job moves to the dead letters
+	Backoff      int `mapstructure:"backoff"`       // in seconds before the first retry, doubled for each further one
+	MaxBackoff   int `mapstructure:"max_backoff"`   // in seconds, cap of the retry delay
+	PollInterval int `mapstructure:"poll_interval"` // in seconds between checks for due jobs while idle
+	Timeout      int `mapstructure:"timeout"`       // in seconds a job may run before it is considered abandoned and run again
+}
+
+// CacheConfig holds the in-memory cache of anonymous API reads. Public routes
+// have default TTLs; any successful write empties the cache.
+type CacheConfig struct {
+	Enabled    bool           `mapstructure:"enabled"`     // cache anonymous GET responses of public routes
+	MaxEntries int            `mapstructure:"max_entries"` // responses kept at most; further ones are not cached until entries expire
+	Routes     map[string]int `mapstructure:"routes"`      // per-route TTL overrides in seconds keyed by path after /api, e.g. "/articles/:id"; 0 turns caching off
+}
+
+// SLOConfig holds the service level objectives of API route groups, whose error
+// budget burn rates admins read from /api/admin/slo
+type SLOConfig struct {
+	Windows       []int            `mapstructure:"windows"`         // in minutes burn rates are reported over; a group alerts when it burns too fast over all of them
+	AlertBurnRate float64          `mapstructure:"alert_burn_rate"` // burn rate above which a group alerts
+	Groups        []SLOGroupConfig `mapstructure:"groups"`
+}
+
+// SLOGroupConfig is the objective of the API routes under a path prefix
+type SLOGroupConfig struct {
+	Name             string  `mapstructure:"name"`
+	Prefix           string  `mapstructure:"prefix"`            // route path after /api, e.g. "/articles"; the longest matching prefix wins
+	Availability     float64 `mapstructure:"availability"`      // target share of requests not failing with a 5xx status, e.g. 0.999
+	LatencyThreshold int     `mapstructure:"latency_threshold"` // in milliseconds a request may take, 0 disables the latency objective
+	LatencyTarget    float64 `mapstructure:"latency_target"`    // target share of requests faster than the threshold, e.g. 0.99
+}
+
+// StorageConfig holds configuration for uploaded files
+type StorageConfig struct {
+	Driver      string          `mapstructure:"driver"`        // "local" or "s3"
+	LocalPath   string          `mapstructure:"local_path"`    // directory for the local driver
+	BaseURL     string          `mapstructure:"base_url"`      // absolute URL files are served from; for local, its path is mounted on the router
+	CDNURL      string          `mapstructure:"cdn_url"`       // absolute URL of a CDN pulling from base_url; replaces it in file URLs when set
+	CacheMaxAge int             `mapstructure:"cache_max_age"` // in seconds files are cached as immutable, 0 omits the header
+	S3          S3StorageConfig `mapstructure:"s3"`
+}
+
+// S3StorageConfig holds the bucket of the s3 driver, on AWS or any S3
+// compatible store
+type S3StorageConfig struct {
+	Endpoint  string `mapstructure:"endpoint"` // e.g. https://s3.eu-central-1.amazonaws.com
+	Region    string `mapstructure:"region"`
+	Bucket    string `mapstructure:"bucket"`
+	AccessKey string `mapstructure:"access_key"`
+	SecretKey string `mapstructure:"secret_key"`
+	PathStyle bool   `mapstructure:"path_style"` // address the bucket as endpoint/bucket, as MinIO needs
 }
 
 // Load loads configuration from file and environment variables
@@ -70,12 +344,12 @@ func Load() (*Config, error) {
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			log.Println("Config file not found, using defaults and environment variables")
+			slog.Info("Config file not found, using defaults and environment variables")
 		} else {
 			return nil, fmt.Errorf("error reading config file: %w", err)
 		}
 	} else {
-		log.Printf("Using config file: %s", viper.ConfigFileUsed())
+		slog.Info("Using config file", "path", viper.ConfigFileUsed())
 	}
 
 	// Unmarshal config
@@ -99,6 +373,8 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", 30)
 	viper.SetDefault("server.write_timeout", 30)
 	viper.SetDefault("server.idle_timeout", 120)
+	viper.SetDefault("server.public_url", "http://localhost:8080")
+	viper.SetDefault("server.trusted_proxies", []string{})
 
 	// Database defaults
 	viper.SetDefault("database.host", "localhost")
@@ -109,14 +385,146 @@ func setDefaults() {
 	viper.SetDefault("database.max_idle_conns", 10)
 	viper.SetDefault("database.max_open_conns", 100)
 	viper.SetDefault("database.max_lifetime", 3600) // 1 hour in seconds
+	viper.SetDefault("database.statement_timeout", 10)
+	viper.SetDefault("database.breaker_threshold", 5)
+	viper.SetDefault("database.breaker_cooldown", 30)
+	viper.SetDefault("database.explain_threshold", 0)
 
 	// JWT defaults
 	viper.SetDefault("jwt.secret", "your-secret-key-change-in-production")
 	viper.SetDefault("jwt.expire_time", 168) // 7 days in hours
+	viper.SetDefault("jwt.refresh_cookie", false)
+	viper.SetDefault("jwt.cookie_secure", true)
+	viper.SetDefault("jwt.cookie_same_site", "strict")
 
 	// Log defaults
 	viper.SetDefault("log.level", "info")
 	viper.SetDefault("log.format", "json")
+	viper.SetDefault("log.sampling.enabled", false)
+	viper.SetDefault("log.sampling.initial", 100)
+	viper.SetDefault("log.sampling.thereafter", 100)
+
+	// Tag maintenance defaults
+	viper.SetDefault("tags.protected", []string{})
+	viper.SetDefault("tags.orphan_cleanup_interval", 24)
+	viper.SetDefault("tags.orphan_cleanup_delete", false)
+
+	// Search defaults
+	viper.SetDefault("search.alert_interval", 60)
+	viper.SetDefault("search.stop_words", []string{})
+	viper.SetDefault("search.synonyms", [][]string{})
+	viper.SetDefault("stats.ranking_interval", 15)
+	viper.SetDefault("stats.trending_window", 7)
+	viper.SetDefault("embeds.enabled", true)
+	viper.SetDefault("embeds.providers", []string{"youtube", "twitter", "gist"})
+	viper.SetDefault("embeds.cache_ttl", 1440)
+	viper.SetDefault("embeds.privacy", true)
+	viper.SetDefault("link_check.interval", 24)
+	viper.SetDefault("link_check.request_interval", 500)
+	viper.SetDefault("link_check.timeout", 10)
+	viper.SetDefault("suggestions.enabled", false)
+	viper.SetDefault("suggestions.base_url", "https://api.openai.com/v1")
+	viper.SetDefault("suggestions.model", "gpt-4o-mini")
+	viper.SetDefault("suggestions.timeout", 30)
+	viper.SetDefault("og_images.enabled", false)
+	viper.SetDefault("og_images.site_name", "Go Blog")
+	viper.SetDefault("og_images.template", "")
+	viper.SetDefault("og_images.background", "#1f2937")
+	viper.SetDefault("og_images.foreground", "#f9fafb")
+	viper.SetDefault("og_images.accent", "#38bdf8")
+
+	// Comment moderation defaults
+	viper.SetDefault("comments.report_threshold", 3)
+
+	// Security defaults
+	viper.SetDefault("security.content_security_policy", "default-src 'none'; frame-ancestors 'none'")
+	viper.SetDefault("security.frame_options", "DENY")
+	viper.SetDefault("security.referrer_policy", "strict-origin-when-cross-origin")
+	viper.SetDefault("security.hsts_max_age", 31536000) // 1 year in seconds
+	viper.SetDefault("security.csrf", false)
+
+	// Request limit defaults
+	viper.SetDefault("limits.max_body_size", 1<<20)    // 1 MB
+	viper.SetDefault("limits.max_upload_size", 10<<20) // 10 MB
+	viper.SetDefault("limits.upload_types", []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+
+	// Quota defaults
+	viper.SetDefault("quotas.enabled", false)
+	viper.SetDefault("quotas.roles", map[string]interface{}{
+		"user": map[string]interface{}{"articles_per_day": 10, "comments_per_hour": 30},
+	})
+	viper.SetDefault("quotas.ip.articles_per_day", 20)
+	viper.SetDefault("quotas.ip.comments_per_hour", 60)
+
+	// CORS defaults
+	viper.SetDefault("cors.allowed_origins", []string{})
+	viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	viper.SetDefault("cors.allowed_headers", []string{"Content-Type", "Authorization", "X-CSRF-Token", "X-Requested-With", "Cache-Control", "X-Response-Shape"})
+	viper.SetDefault("cors.exposed_headers", []string{"X-Request-ID", "Deprecation", "Sunset", "Link"})
+	viper.SetDefault("cors.allow_credentials", false)
+	viper.SetDefault("cors.max_age", 600) // 10 minutes in seconds
+
+	// Session defaults
+	viper.SetDefault("sessions.geo_header", "")
+	viper.SetDefault("sessions.new_device_alerts", true)
+
+	// Magic link defaults
+	viper.SetDefault("magic_links.enabled", false)
+	viper.SetDefault("magic_links.ttl", 15)
+	viper.SetDefault("magic_links.max_per_hour", 5)
+	viper.SetDefault("magic_links.link_url", "")
+
+	// Maintenance defaults
+	viper.SetDefault("maintenance.enabled", false)
+	viper.SetDefault("maintenance.block_reads", false)
+	viper.SetDefault("maintenance.message", "")
+	viper.SetDefault("maintenance.retry_after", 0)
+
+	// Site visibility defaults
+	viper.SetDefault("site.visibility", "public")
+	viper.SetDefault("site.message", "")
+
+	// Search engine notification defaults
+	viper.SetDefault("search_engines.enabled", false)
+	viper.SetDefault("search_engines.article_url", "")
+	viper.SetDefault("search_engines.sitemap_url", "")
+	viper.SetDefault("search_engines.ping_urls", []string{})
+	viper.SetDefault("search_engines.indexnow_key", "")
+	viper.SetDefault("search_engines.indexnow_endpoint", "https://api.indexnow.org/indexnow")
+
+	// Scheduler defaults
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.trash_retention", 30)
+	viper.SetDefault("scheduler.run_retention", 30)
+
+	// Queue defaults
+	viper.SetDefault("queue.workers", 4)
+	viper.SetDefault("queue.max_attempts", 8)
+	viper.SetDefault("queue.backoff", 30)
+	viper.SetDefault("queue.max_backoff", 3600) // 1 hour in seconds
+	viper.SetDefault("queue.poll_interval", 5)
+	viper.SetDefault("queue.timeout", 600) // 10 minutes in seconds
+
+	// Response cache defaults
+	viper.SetDefault("cache.enabled", false)
+	viper.SetDefault("cache.max_entries", 10000)
+
+	// SLO defaults
+	viper.SetDefault("slo.windows", []int{5, 60, 360})
+	viper.SetDefault("slo.alert_burn_rate", 14.4)
+
+	// Storage defaults
+	viper.SetDefault("storage.driver", "local")
+	viper.SetDefault("storage.local_path", "./uploads")
+	viper.SetDefault("storage.base_url", "http://localhost:8080/uploads")
+	viper.SetDefault("storage.cdn_url", "")
+	viper.SetDefault("storage.cache_max_age", 31536000) // 1 year in seconds; stored file names change with their content
+	viper.SetDefault("storage.s3.endpoint", "https://s3.amazonaws.com")
+	viper.SetDefault("storage.s3.region", "us-east-1")
+	viper.SetDefault("storage.s3.bucket", "")
+	viper.SetDefault("storage.s3.access_key", "")
+	viper.SetDefault("storage.s3.secret_key", "")
+	viper.SetDefault("storage.s3.path_style", false)
 }
 
 // GetDatabaseURL returns the database connection URL
@@ -133,13 +541,21 @@ func (c *Config) GetDatabaseURL() string {
 // GetServerAddress returns the server address
 func (c *Config) GetServerAddress() string {
 	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.Port)
-}//
- Validate validates the configuration
+}
+
+// Validate validates the configuration
 func (c *Config) Validate() error {
 	// Validate server config
 	if c.Server.Port == "" {
 		return fmt.Errorf("server port is required")
 	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				return fmt.Errorf("server trusted_proxies must be IPs or CIDRs, got %q", proxy)
+			}
+		}
+	}
 
 	// Validate database config
 	if c.Database.Host == "" {
@@ -151,10 +567,250 @@ func (c *Config) Validate() error {
 	if c.Database.Database == "" {
 		return fmt.Errorf("database name is required")
 	}
+	if c.Database.StatementTimeout < 0 {
+		return fmt.Errorf("database statement_timeout must not be negative, got %d", c.Database.StatementTimeout)
+	}
+	if c.Database.ExplainThreshold < 0 {
+		return fmt.Errorf("database explain_threshold must not be negative, got %d", c.Database.ExplainThreshold)
+	}
+	if c.Database.BreakerThreshold > 0 && c.Database.BreakerCooldown < 1 {
+		return fmt.Errorf("database breaker_cooldown must be at least 1 when the breaker is enabled, got %d", c.Database.BreakerCooldown)
+	}
+
+	// Validate storage config
+	switch c.Storage.Driver {
+	case "", "local":
+	case "s3":
+		if c.Storage.S3.Bucket == "" || c.Storage.S3.AccessKey == "" || c.Storage.S3.SecretKey == "" {
+			return fmt.Errorf("storage s3 bucket, access_key and secret_key are required for the s3 driver")
+		}
+		if !strings.HasPrefix(c.Storage.S3.Endpoint, "https://") && !strings.HasPrefix(c.Storage.S3.Endpoint, "http://") {
+			return fmt.Errorf("storage s3 endpoint must be an http or https URL, got %q", c.Storage.S3.Endpoint)
+		}
+	default:
+		return fmt.Errorf("unsupported storage driver %q", c.Storage.Driver)
+	}
+	if c.Storage.CDNURL != "" && !strings.HasPrefix(c.Storage.CDNURL, "https://") && !strings.HasPrefix(c.Storage.CDNURL, "http://") {
+		return fmt.Errorf("storage cdn_url must be an http or https URL, got %q", c.Storage.CDNURL)
+	}
+	if c.Storage.CacheMaxAge < 0 {
+		return fmt.Errorf("storage cache_max_age must not be negative, got %d", c.Storage.CacheMaxAge)
+	}
+
+	// Validate security config
+	if c.Security.FrameOptions != "" && c.Security.FrameOptions != "DENY" && c.Security.FrameOptions != "SAMEORIGIN" {
+		return fmt.Errorf("security frame_options must be DENY or SAMEORIGIN, got %q", c.Security.FrameOptions)
+	}
+
+	// Validate maintenance config
+	if c.Maintenance.RetryAfter < 0 {
+		return fmt.Errorf("maintenance retry_after must not be negative, got %d", c.Maintenance.RetryAfter)
+	}
+
+	// Validate site config
+	switch c.Site.Visibility {
+	case "public", "unlisted", "private":
+	default:
+		return fmt.Errorf("site visibility must be public, unlisted or private, got %q", c.Site.Visibility)
+	}
+
+	// Validate search engine config
+	if key := c.SearchEngines.IndexNowKey; key != "" && !indexNowKeyPattern.MatchString(key) {
+		return fmt.Errorf("search_engines indexnow_key must be 8 to 128 letters, digits or dashes")
+	}
+
+	// Validate scheduler config
+	if c.Scheduler.TrashRetention < 0 || c.Scheduler.RunRetention < 0 {
+		return fmt.Errorf("scheduler retention must not be negative")
+	}
+	for name, job := range c.Scheduler.Jobs {
+		if job.Jitter < 0 {
+			return fmt.Errorf("scheduler job %s jitter must not be negative, got %d", name, job.Jitter)
+		}
+	}
+
+	// Validate queue config
+	if c.Queue.Workers < 1 {
+		return fmt.Errorf("queue workers must be at least 1, got %d", c.Queue.Workers)
+	}
+	if c.Queue.MaxAttempts < 1 {
+		return fmt.Errorf("queue max_attempts must be at least 1, got %d", c.Queue.MaxAttempts)
+	}
+
+	// Validate CORS config
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" && c.CORS.AllowCredentials {
+			return fmt.Errorf("cors allowed_origins must list origins rather than \"*\" when allow_credentials is on")
+		}
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			return fmt.Errorf("cors allowed origin %q must start with http:// or https://", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		return fmt.Errorf("cors max_age must not be negative, got %d", c.CORS.MaxAge)
+	}
+
+	// Validate limits config
+	if c.Limits.MaxBodySize < 0 || c.Limits.MaxUploadSize < 0 {
+		return fmt.Errorf("limits max_body_size and max_upload_size must not be negative")
+	}
+	for route, limit := range c.Limits.Routes {
+		if limit < 0 {
+			return fmt.Errorf("limits size of route %s must not be negative, got %d", route, limit)
+		}
+	}
+
+	// Validate stats config
+	if c.Stats.RankingInterval < 0 {
+		return fmt.Errorf("stats ranking_interval must not be negative, got %d", c.Stats.RankingInterval)
+	}
+	if c.Stats.RankingInterval > 0 && c.Stats.TrendingWindow < 1 {
+		return fmt.Errorf("stats trending_window must be at least 1 when rankings are enabled, got %d", c.Stats.TrendingWindow)
+	}
+
+	// Validate embeds config
+	for _, provider := range c.Embeds.Providers {
+		if provider != "youtube" && provider != "twitter" && provider != "gist" {
+			return fmt.Errorf("embeds provider must be youtube, twitter or gist, got %q", provider)
+		}
+	}
+	if c.Embeds.CacheTTL < 0 {
+		return fmt.Errorf("embeds cache_ttl must not be negative, got %d", c.Embeds.CacheTTL)
+	}
+
+	// Validate link check config
+	if c.LinkCheck.Interval < 0 {
+		return fmt.Errorf("link_check interval must not be negative, got %d", c.LinkCheck.Interval)
+	}
+	if c.LinkCheck.RequestInterval < 0 {
+		return fmt.Errorf("link_check request_interval must not be negative, got %d", c.LinkCheck.RequestInterval)
+	}
+	if c.LinkCheck.Interval > 0 && c.LinkCheck.Timeout < 1 {
+		return fmt.Errorf("link_check timeout must be at least 1 when link checks are enabled, got %d", c.LinkCheck.Timeout)
+	}
+
+	// Validate suggestions config
+	if c.Suggestions.Enabled {
+		if !strings.HasPrefix(c.Suggestions.BaseURL, "http://") && !strings.HasPrefix(c.Suggestions.BaseURL, "https://") {
+			return fmt.Errorf("suggestions base_url must be an absolute http or https URL, got %q", c.Suggestions.BaseURL)
+		}
+		if c.Suggestions.Model == "" {
+			return fmt.Errorf("suggestions model is required when suggestions are enabled")
+		}
+		if c.Suggestions.Timeout < 1 {
+			return fmt.Errorf("suggestions timeout must be at least 1 when suggestions are enabled, got %d", c.Suggestions.Timeout)
+		}
+	}
+
+	// Validate search config
+	for _, word := range c.Search.StopWords {
+		if word == "" || strings.ContainsAny(word, " \t") {
+			return fmt.Errorf("search stop_words must be single words, got %q", word)
+		}
+	}
+	for _, group := range c.Search.Synonyms {
+		if len(group) < 2 {
+			return fmt.Errorf("search synonyms groups need at least two words, got %v", group)
+		}
+		for _, word := range group {
+			if word == "" || strings.ContainsAny(word, " \t") {
+				return fmt.Errorf("search synonyms must be single words, got %q", word)
+			}
+		}
+	}
+
+	// Validate OG images config
+	if c.OGImages.Enabled {
+		colors := [][2]string{{"background", c.OGImages.Background}, {"foreground", c.OGImages.Foreground}, {"accent", c.OGImages.Accent}}
+		for _, color := range colors {
+			if !hexColorPattern.MatchString(color[1]) {
+				return fmt.Errorf("og_images %s must be a hex color like #1f2937, got %q", color[0], color[1])
+			}
+		}
+	}
+
+	// Validate quotas config
+	for role, quota := range c.Quotas.Roles {
+		switch role {
+		case "user", "admin", "system":
+		default:
+			return fmt.Errorf("quotas role must be user, admin or system, got %q", role)
+		}
+		if quota.ArticlesPerDay < 0 || quota.CommentsPerHour < 0 {
+			return fmt.Errorf("quotas of role %s must not be negative", role)
+		}
+	}
+	if c.Quotas.IP.ArticlesPerDay < 0 || c.Quotas.IP.CommentsPerHour < 0 {
+		return fmt.Errorf("quotas of client IPs must not be negative")
+	}
+
+	// Validate log config
+	switch strings.ToLower(c.Log.Level) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		return fmt.Errorf("log level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "", "json", "text":
+	default:
+		return fmt.Errorf("log format must be json or text, got %q", c.Log.Format)
+	}
+	if c.Log.Sampling.Enabled && (c.Log.Sampling.Initial < 0 || c.Log.Sampling.Thereafter < 1) {
+		return fmt.Errorf("log sampling needs initial of at least 0 and thereafter of at least 1")
+	}
+
+	// Validate cache config
+	if c.Cache.Enabled && c.Cache.MaxEntries < 1 {
+		return fmt.Errorf("cache max_entries must be at least 1 when the cache is enabled, got %d", c.Cache.MaxEntries)
+	}
+	for route, ttl := range c.Cache.Routes {
+		if ttl < 0 {
+			return fmt.Errorf("cache ttl of route %s must not be negative, got %d", route, ttl)
+		}
+	}
+
+	// Validate SLO config
+	for _, window := range c.SLO.Windows {
+		if window < 1 {
+			return fmt.Errorf("slo windows must be at least 1 minute, got %d", window)
+		}
+	}
+	if c.SLO.AlertBurnRate < 0 {
+		return fmt.Errorf("slo alert_burn_rate must not be negative, got %g", c.SLO.AlertBurnRate)
+	}
+	for _, group := range c.SLO.Groups {
+		if group.Name == "" {
+			return fmt.Errorf("slo groups must have a name")
+		}
+		if group.Availability <= 0 || group.Availability >= 1 {
+			return fmt.Errorf("slo group %s availability must be between 0 and 1, got %g", group.Name, group.Availability)
+		}
+		if group.LatencyThreshold < 0 {
+			return fmt.Errorf("slo group %s latency_threshold must not be negative, got %d", group.Name, group.LatencyThreshold)
+		}
+		if group.LatencyThreshold > 0 && (group.LatencyTarget <= 0 || group.LatencyTarget >= 1) {
+			return fmt.Errorf("slo group %s latency_target must be between 0 and 1, got %g", group.Name, group.LatencyTarget)
+		}
+	}
 
 	// Validate JWT config
+	switch c.JWT.CookieSameSite {
+	case "", "strict", "lax", "none":
+	default:
+		return fmt.Errorf("jwt cookie_same_site must be strict, lax or none, got %q", c.JWT.CookieSameSite)
+	}
 	if c.JWT.Secret == "" || c.JWT.Secret == "your-secret-key-change-in-production" {
-		log.Println("WARNING: Using default JWT secret. Please change it in production!")
+		slog.Warn("Using default JWT secret. Please change it in production!")
+	}
+
+	// Validate magic links config
+	if c.MagicLinks.Enabled {
+		if c.MagicLinks.TTL < 1 || c.MagicLinks.MaxPerHour < 1 {
+			return fmt.Errorf("magic_links ttl and max_per_hour must be at least 1")
+		}
+		if link := c.MagicLinks.LinkURL; link != "" && !strings.HasPrefix(link, "https://") && !strings.HasPrefix(link, "http://") {
+			return fmt.Errorf("magic_links link_url must be an http or https URL, got %q", link)
+		}
 	}
 
 	return nil
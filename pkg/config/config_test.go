@@ -29,6 +29,78 @@ func TestLoad(t *testing.T) {
 	if config.JWT.ExpireTime != 168 {
 		t.Errorf("Expected default JWT expire time 168, got %d", config.JWT.ExpireTime)
 	}
+
+	if config.Security.FrameOptions != "DENY" || config.Security.CSRF {
+		t.Errorf("Expected default frame options DENY without CSRF, got %+v", config.Security)
+	}
+
+	if !config.Sessions.NewDeviceAlerts || config.Sessions.GeoHeader != "" {
+		t.Errorf("Expected new device alerts without a geo header by default, got %+v", config.Sessions)
+	}
+
+	if !config.Scheduler.Enabled || config.Scheduler.TrashRetention != 30 || config.Scheduler.RunRetention != 30 {
+		t.Errorf("Expected the scheduler enabled with 30 day retention by default, got %+v", config.Scheduler)
+	}
+
+	if config.Queue.Workers != 4 || config.Queue.MaxAttempts != 8 {
+		t.Errorf("Expected 4 queue workers with 8 attempts by default, got %+v", config.Queue)
+	}
+
+	if config.Cache.Enabled || config.Cache.MaxEntries != 10000 {
+		t.Errorf("Expected the response cache disabled with 10000 entries by default, got %+v", config.Cache)
+	}
+
+	if len(config.CORS.AllowedOrigins) != 0 || config.CORS.AllowCredentials || len(config.CORS.AllowedMethods) == 0 {
+		t.Errorf("Expected no cross-origin access by default, got %+v", config.CORS)
+	}
+
+	if config.Limits.MaxBodySize != 1<<20 || config.Limits.MaxUploadSize != 10<<20 || len(config.Limits.UploadTypes) == 0 {
+		t.Errorf("Expected 1 MB bodies and 10 MB image uploads by default, got %+v", config.Limits)
+	}
+}
+
+func TestValidateCORS(t *testing.T) {
+	viper.Reset()
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	config.CORS.AllowedOrigins = []string{"*"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected any origin to be allowed without credentials, got %v", err)
+	}
+
+	config.CORS.AllowCredentials = true
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a wildcard origin to be refused with credentials")
+	}
+
+	config.CORS.AllowedOrigins = []string{"blog.example.com"}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected an origin without scheme to be refused")
+	}
+}
+
+func TestValidateTrustedProxies(t *testing.T) {
+	viper.Reset()
+	config, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	if len(config.Server.TrustedProxies) != 0 {
+		t.Errorf("Expected no trusted proxies by default, got %v", config.Server.TrustedProxies)
+	}
+
+	config.Server.TrustedProxies = []string{"10.0.0.1", "172.16.0.0/12", "::1"}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Expected IPs and CIDRs to be accepted, got %v", err)
+	}
+
+	config.Server.TrustedProxies = []string{"proxy.internal"}
+	if err := config.Validate(); err == nil {
+		t.Error("Expected a host name to be refused")
+	}
 }
 
 func TestLoadWithEnvVars(t *testing.T) {